@@ -12,14 +12,16 @@ import (
 	"strings"
 	"time"
 
-	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	ginadapter "github.com/awslabs/aws-lambda-go-api-proxy/gin"
 	gin "github.com/gin-gonic/gin"
 	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
 
+	"versionary-api/pkg/apikey"
 	"versionary-api/pkg/app"
 	"versionary-api/pkg/event"
+	"versionary-api/pkg/role"
 	"versionary-api/pkg/token"
 	"versionary-api/pkg/user"
 )
@@ -58,12 +60,59 @@ func main() {
 	}
 	flag.StringVar(&env, "env", env, "Operating Environment <dev | qa | staging | prod>")
 
+	// Flag: JWT access token signing algorithm (default is the JWT_ALGORITHM environment variable; empty for opaque tokens)
+	flag.StringVar(&api.JWTAlgorithm, "jwt", os.Getenv("JWT_ALGORITHM"), "JWT access token signing algorithm <EdDSA | HS256>, or empty for opaque tokens; a JWT remains valid until it expires, even if revoked or its user is disabled")
+
+	// Flags: email domains allowed or blocked for self-service registration (comma-delimited; default is the
+	// ALLOWED_EMAIL_DOMAINS or BLOCKED_EMAIL_DOMAINS environment variable; any domain is allowed if empty)
+	var allowedDomains, blockedDomains string
+	flag.StringVar(&allowedDomains, "allow-domains", os.Getenv("ALLOWED_EMAIL_DOMAINS"), "Email domains allowed for self-service registration (comma-delimited)")
+	flag.StringVar(&blockedDomains, "block-domains", os.Getenv("BLOCKED_EMAIL_DOMAINS"), "Email domains blocked for self-service registration (comma-delimited)")
+
+	// Flag: refuse tokens to PENDING users, who have not verified their email address (default is the REQUIRE_VERIFIED environment variable)
+	requireVerified, _ := strconv.ParseBool(os.Getenv("REQUIRE_VERIFIED"))
+	flag.BoolVar(&api.RequireVerified, "require-verified", requireVerified, "Refuse tokens to users who have not verified their email address")
+
+	// Flags: brute-force lockout policies for failed logins per user and per client IP address, and for registrations
+	// per client IP address, in the form threshold/baseDelay/maxDelay/resetAfter (e.g. "5/1m/1h/24h"; default is the
+	// USER_LOCKOUT_POLICY, IP_LOCKOUT_POLICY, or REGISTRATION_LOCKOUT_POLICY environment variable; built-in if empty)
+	var userLockout, ipLockout, registrationLockout string
+	flag.StringVar(&userLockout, "user-lockout", os.Getenv("USER_LOCKOUT_POLICY"), "Failed login lockout policy per user (default "+user.DefaultUserLockoutPolicy.String()+")")
+	flag.StringVar(&ipLockout, "ip-lockout", os.Getenv("IP_LOCKOUT_POLICY"), "Failed login lockout policy per client IP address (default "+user.DefaultIPLockoutPolicy.String()+")")
+	flag.StringVar(&registrationLockout, "registration-lockout", os.Getenv("REGISTRATION_LOCKOUT_POLICY"), "Registration lockout policy per client IP address (default "+user.DefaultRegistrationLockoutPolicy.String()+")")
+
+	// Flag: extend org-scoped permissions to descendant organizations (default is the ORG_SCOPE_DESCENDANTS environment variable)
+	orgDescendants, _ := strconv.ParseBool(os.Getenv("ORG_SCOPE_DESCENDANTS"))
+	flag.BoolVar(&api.OrgScopeDescendants, "org-descendants", orgDescendants, "Extend org-scoped permissions to descendant organizations")
+
 	// Initialize the application, including required services:
 	flag.Parse()
+	api.SignupDomains = user.DomainPolicy{
+		Allowed: user.ParseDomains(allowedDomains),
+		Blocked: user.ParseDomains(blockedDomains),
+	}
+	if api.JWTAlgorithm != "" && !token.ValidAlgorithm(api.JWTAlgorithm) {
+		log.Fatalf("invalid JWT signing algorithm %q", api.JWTAlgorithm)
+	}
 	err := api.Init(env)
 	if err != nil {
 		log.Fatal(err)
 	}
+	policies := []struct {
+		spec   string
+		policy *user.LockoutPolicy
+	}{
+		{userLockout, &api.LockoutService.UserPolicy},
+		{ipLockout, &api.LockoutService.IPPolicy},
+		{registrationLockout, &api.LockoutService.RegistrationPolicy},
+	}
+	for _, p := range policies {
+		if p.spec != "" {
+			if *p.policy, err = user.ParseLockoutPolicy(p.spec); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
 
 	// Show application version
 	if version {
@@ -88,12 +137,10 @@ func main() {
 	// Identify operating environment (AWS or on localhost)
 	_, ok := os.LookupEnv("LAMBDA_TASK_ROOT")
 	if ok {
-		// Run API as an AWS Lambda function with an API Gateway proxy
+		// Run API as an AWS Lambda function with an API Gateway proxy (and scheduled tasks)
 		router.TrustedPlatform = "X-Forwarded-For"
-		ginLambda := ginadapter.NewV2(router)
-		lambda.Start(func(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
-			return ginLambda.ProxyWithContext(ctx, req)
-		})
+		api.SyncJobs = true // background work is frozen between requests
+		lambda.Start(lambdaHandler(ginadapter.NewV2(router)))
 	} else {
 		// Run API on localhost for local development, debugging, etc.
 		_ = router.SetTrustedProxies(nil) // disable IP allow list
@@ -111,13 +158,22 @@ func main() {
 func registerRoutes(r *gin.Engine) {
 	r.Use(bearerTokenHandler())
 	r.NoRoute(notFound)
+	registerAPIKeyRoutes(r)
 	registerContentRoutes(r)
 	registerDeviceRoutes(r)
+	registerDomainRoutes(r)
+	registerHierarchyRoutes(r)
 	registerEmailRoutes(r)
 	registerEventRoutes(r)
 	registerImageRoutes(r)
+	registerInvitationRoutes(r)
+	registerJobRoutes(r)
+	registerMembershipRoutes(r)
 	registerMetricRoutes(r)
+	registerOAuthRoutes(r)
 	registerOrganizationRoutes(r)
+	registerRestoreRoutes(r)
+	registerRoleRoutes(r)
 	registerTokenRoutes(r)
 	registerTuidRoutes(r)
 	registerUserRoutes(r)
@@ -160,19 +216,55 @@ func abortWithError(c *gin.Context, code int, err error) {
 }
 
 // bearerTokenHandler is a middleware function that reads a Bearer token, adding both the Token
-// and the associated User to the request. If an error occurs, nothing is added to the request
-// and processing continues. Authorization, if required, should be handled by a subsequent handler.
+// and the associated User to the request. If the bearer credential is an APIKey secret, then the
+// APIKey is added instead of a Token, and its scopes are enforced by the authorization middleware.
+// If an invalid credential is supplied, the request is aborted with a 401 Unauthorized status.
+// Authorization, if required, should be handled by a subsequent handler.
 func bearerTokenHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		h := c.GetHeader("Authorization")
 		if h != "" {
 			b, a, f := strings.Cut(strings.TrimSpace(h), " ")
 			if f && strings.ToLower(b) == "bearer" && len(a) > 0 {
-				t, u, err := tokenUser(c, a)
-				if err != nil {
-					abortWithError(c, http.StatusUnauthorized, err)
-					return
+				if apikey.IsSecret(a) {
+					k, u, err := apiKeyUser(c, a)
+					if err != nil {
+						abortWithError(c, http.StatusUnauthorized, err)
+						return
+					}
+					c.Set("apikey", k.Scrub())
+					c.Set("user", u.Scrub())
+				} else if api.JWTAlgorithm != "" && token.IsJWT(a) {
+					t, u, m, k, err := jwtUser(c, a)
+					if err != nil {
+						abortWithError(c, http.StatusUnauthorized, err)
+						return
+					}
+					if k.ID != "" {
+						c.Set("apikey", k)
+					} else if !selectJWTOrganization(c, t, u, m) {
+						return
+					}
+					c.Set("token", t)
+					c.Set("user", u)
 				} else {
+					t, u, err := tokenUser(c, a)
+					if err != nil {
+						abortWithError(c, http.StatusUnauthorized, err)
+						return
+					}
+					t, _ = api.TokenService.Touch(c, t, time.Now()) // best effort: record session activity
+					if t.APIKeyID != "" {
+						// Tokens issued with the client_credentials grant carry the scopes of their APIKey
+						k, err := tokenAPIKey(c, t)
+						if err != nil {
+							abortWithError(c, http.StatusUnauthorized, err)
+							return
+						}
+						c.Set("apikey", k.Scrub())
+					} else if !selectOrganization(c, t, u) {
+						return
+					}
 					c.Set("token", t)
 					c.Set("user", u.Scrub())
 				}
@@ -182,6 +274,129 @@ func bearerTokenHandler() gin.HandlerFunc {
 	}
 }
 
+// OrganizationHeader is the request header used to select the active Organization for a single request,
+// overriding the Organization selected when the Token was issued.
+const OrganizationHeader = "X-Organization-ID"
+
+// selectOrganization selects the active Organization for the request: the one named in the OrganizationHeader,
+// or else the one selected when the Token was issued, or else the User's primary Organization. Outside their
+// primary Organization, the User must hold a Membership, which is added to the request. If they do not, the
+// request is aborted with a 403 Forbidden status, and false is returned.
+func selectOrganization(c *gin.Context, t token.Token, u user.User) bool {
+	orgID := strings.TrimSpace(c.GetHeader(OrganizationHeader))
+	if orgID == "" {
+		orgID = t.OrgID
+	}
+	if orgID == "" || orgID == u.OrgID {
+		return true
+	}
+	if !tuid.IsValid(tuid.TUID(orgID)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid %s: %s", OrganizationHeader, orgID))
+		return false
+	}
+	m, err := api.UserService.Memberships.ReadMembership(c, u.ID, orgID)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: user %s is not a member of organization %s", u.ID, orgID))
+		return false
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     u.ID,
+			EntityType: api.UserService.Memberships.EntityType,
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read membership in organization %s: %w", orgID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return false
+	}
+	c.Set("membership", m)
+	return true
+}
+
+// selectJWTOrganization selects the active Organization for a request authenticated with a JWT. The User's
+// Membership in the Organization selected when the token was issued is embedded in its claims, so it is added
+// to the request without a database read. Selecting a different Organization with the OrganizationHeader
+// reads the Membership, as for an opaque Token.
+func selectJWTOrganization(c *gin.Context, t token.Token, u user.User, m user.Membership) bool {
+	if orgID := strings.TrimSpace(c.GetHeader(OrganizationHeader)); orgID != "" && orgID != t.OrgID {
+		return selectOrganization(c, t, u)
+	}
+	if m.OrgID != "" {
+		c.Set("membership", m)
+	}
+	return true
+}
+
+// apiKeyUser authenticates a specified APIKey secret and reads its associated User.
+func apiKeyUser(ctx context.Context, secret string) (apikey.APIKey, user.User, error) {
+	// Validate the Application
+	if api.APIKeyService.Table == nil || api.UserService.Table == nil {
+		return apikey.APIKey{}, user.User{}, fmt.Errorf("application not initialized")
+	}
+	// Authenticate the API key
+	k, err := api.APIKeyService.Authenticate(ctx, secret)
+	if err != nil {
+		return apikey.APIKey{}, user.User{}, fmt.Errorf("error reading api key: %w", err)
+	}
+	// Read the associated user
+	u, err := api.UserService.Read(ctx, k.UserID)
+	if err != nil {
+		return k, user.User{}, fmt.Errorf("error reading user %s from api key: %w", k.UserID, err)
+	}
+	// Check that the user is an active service account
+	if u.Status == user.DISABLED {
+		return k, u, fmt.Errorf("user %s status is %s", u.ID, u.Status)
+	}
+	if !u.IsServiceAccount() {
+		return k, u, fmt.Errorf("user %s is not a service account", u.ID)
+	}
+	return k, u, nil
+}
+
+// jwtUser verifies a signed JWT access token, and reconstructs its Token and User from its claims, without reading
+// them from the database. It also reconstructs the User's Membership in the active Organization, if it is not their
+// primary Organization, and the APIKey, for tokens issued with the client_credentials grant. Note that a JWT
+// remains valid until it expires, even if its Token is deleted, its User is disabled, or their sessions are revoked.
+func jwtUser(ctx context.Context, jwt string) (token.Token, user.User, user.Membership, apikey.APIKey, error) {
+	claims, err := api.VerifyJWT(ctx, jwt)
+	if err != nil {
+		return token.Token{}, user.User{}, user.Membership{}, apikey.APIKey{}, fmt.Errorf("invalid bearer token: %w", err)
+	}
+	t := claims.Token()
+	u := user.User{
+		ID:         claims.Subject,
+		GivenName:  claims.GivenName,
+		FamilyName: claims.FamilyName,
+		Email:      claims.Email,
+		Roles:      claims.Roles,
+		OrgID:      claims.OrgID,
+		Status:     user.ENABLED,
+	}
+	var m user.Membership
+	if claims.ActiveOrg != "" && claims.ActiveOrg != claims.OrgID {
+		m = user.Membership{
+			UserID:  claims.Subject,
+			Email:   claims.Email,
+			OrgID:   claims.ActiveOrg,
+			OrgName: claims.OrgName,
+			Roles:   claims.OrgRoles,
+		}
+	}
+	var k apikey.APIKey
+	if claims.ClientID != "" {
+		k = apikey.APIKey{
+			ID:     claims.ClientID,
+			UserID: claims.Subject,
+			Email:  claims.Email,
+			Scopes: claims.Scopes(),
+			Status: apikey.ENABLED,
+		}
+	}
+	return t, u, m, k, nil
+}
+
 // tokenUser reads a specified Token and its associated User.
 func tokenUser(ctx context.Context, tokenID string) (token.Token, user.User, error) {
 	// Validate the Application
@@ -210,35 +425,66 @@ func tokenUser(ctx context.Context, tokenID string) (token.Token, user.User, err
 	return t, u, nil
 }
 
-// userAuthenticator is a middleware function that ensures that the request is authenticated.
-// If the user is not present (no valid bearer token), the request is aborted with a 401 Unauthorized status.
-// If an authenticated user is present, the processing continues. Anonymous requests are rejected.
-// In many cases, the user will be authorized for their content in a subsequent handler function.
-func userAuthenticator() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		_, ok := c.Get("user")
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, APIEvent{
-				CreatedAt: time.Now(),
-				LogLevel:  "ERROR",
-				Code:      http.StatusUnauthorized,
-				Message:   "unauthenticated",
-				URI:       c.Request.URL.String(),
-			})
-			return
+// tokenAPIKey reads the APIKey used to issue a Token with the client_credentials grant,
+// verifying that the APIKey is still usable (neither revoked nor expired).
+func tokenAPIKey(ctx context.Context, t token.Token) (apikey.APIKey, error) {
+	k, err := api.APIKeyService.Read(ctx, t.APIKeyID)
+	if err != nil {
+		return k, fmt.Errorf("error reading api key %s from token: %w", t.APIKeyID, err)
+	}
+	if k.UserID != t.UserID || k.Status != apikey.ENABLED || k.IsExpired(time.Now()) {
+		return k, fmt.Errorf("api key %s is not usable: %w", k.ID, apikey.ErrInvalidAPIKey)
+	}
+	return k, nil
+}
+
+// Pseudo-permissions for routes that do not require a permission granted by a Role.
+const (
+	public        = ""              // anonymous requests are allowed; the handler may authorize the requester
+	authenticated = "authenticated" // any authenticated user; the handler authorizes access to their own resources
+)
+
+// Permission scopes for routes.
+const (
+	global    = false // the permission must be granted globally
+	orgScoped = true  // the permission may be granted within the user's Organization; the handler limits access
+)
+
+// route declares an API endpoint: the HTTP method ("ANY" for all methods), the path, the permission
+// required by the endpoint, whether an organization-scoped grant of the permission suffices, and the handler.
+type route struct {
+	Method     string
+	Path       string
+	Permission string
+	OrgScoped  bool
+	Handler    gin.HandlerFunc
+}
+
+// handleRoutes registers a table of routes with the Gin router, checking the permission required by each route.
+func handleRoutes(r *gin.Engine, routes []route) {
+	for _, rt := range routes {
+		handlers := []gin.HandlerFunc{rt.Handler}
+		if rt.Permission != public {
+			handlers = []gin.HandlerFunc{permissionAuthorizer(rt.Permission, rt.OrgScoped), rt.Handler}
+		}
+		if rt.Method == "ANY" {
+			r.Any(rt.Path, handlers...)
+		} else {
+			r.Handle(rt.Method, rt.Path, handlers...)
 		}
-		c.Next()
 	}
 }
 
-// roleAuthorizer is a middleware function that checks the request for a user with the specified role.
+// permissionAuthorizer is a middleware function that checks the request for a user with the specified permission.
 // If the user is not present (no valid bearer token), the request is aborted with a 401 Unauthorized status.
-// If the user does not have the specified role, the request is aborted with a 403 Forbidden status.
-// If the user is an administrator or has the specified role, then processing continues.
-func roleAuthorizer(r string) gin.HandlerFunc {
+// If the user's Roles do not grant the permission, the request is aborted with a 403 Forbidden status.
+// If orgScoped is true, a permission granted only within the user's Organization suffices, and the handler
+// must limit access to that Organization. Requests authenticated with an APIKey must also be authorized by
+// scope: the APIKey must grant the scope required by the request (e.g. "images:write" for POST /v1/images),
+// and the permissions of its service account are limited to those granted by its scopes.
+func permissionAuthorizer(perm string, orgScoped bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		u, ok := c.Get("user")
-		if !ok {
+		if _, ok := c.Get("user"); !ok {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, APIEvent{
 				CreatedAt: time.Now(),
 				LogLevel:  "ERROR",
@@ -248,20 +494,74 @@ func roleAuthorizer(r string) gin.HandlerFunc {
 			})
 			return
 		}
-		if u.(user.User).HasRole(r) {
+		if k, isKey := contextAPIKey(c); isKey && !scopeAuthorized(c, k) {
+			abortWithScopeError(c)
+			return
+		}
+		if perm == authenticated {
+			c.Next()
+			return
+		}
+		p := contextPermissions(c)
+		if p.Has(perm) || (orgScoped && p.HasAnywhere(perm)) {
 			c.Next()
 		} else {
 			c.AbortWithStatusJSON(http.StatusForbidden, APIEvent{
 				CreatedAt: time.Now(),
 				LogLevel:  "ERROR",
 				Code:      http.StatusForbidden,
-				Message:   "unauthorized",
+				Message:   "unauthorized: permission required: " + perm,
 				URI:       c.Request.URL.String(),
 			})
 		}
 	}
 }
 
+// requestScope returns the APIKey scope required by the request (e.g. "images:write"),
+// derived from the matched route and the HTTP method. An empty string is returned if the
+// route does not correspond to a resource that may be granted to an APIKey.
+func requestScope(c *gin.Context) string {
+	path := c.FullPath()
+	if path == "" {
+		path = c.Request.URL.Path
+	}
+	resource := apikey.PathResource(path)
+	if resource == "" {
+		return ""
+	}
+	return apikey.Scope(resource, apikey.ScopeAction(c.Request.Method))
+}
+
+// scopeAuthorized returns true if the APIKey grants the scope required by the request.
+func scopeAuthorized(c *gin.Context, k apikey.APIKey) bool {
+	scope := requestScope(c)
+	return scope != "" && k.HasScope(scope)
+}
+
+// abortWithScopeError aborts the request with a 403 Forbidden status, naming the missing APIKey scope.
+func abortWithScopeError(c *gin.Context) {
+	scope := requestScope(c)
+	if scope == "" {
+		scope = "(none available)"
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, APIEvent{
+		CreatedAt: time.Now(),
+		LogLevel:  "ERROR",
+		Code:      http.StatusForbidden,
+		Message:   "unauthorized: api key scope required: " + scope,
+		URI:       c.Request.URL.String(),
+	})
+}
+
+// contextAPIKey returns the typed APIKey associated with the request, if it was authenticated with an APIKey.
+func contextAPIKey(c *gin.Context) (apikey.APIKey, bool) {
+	k, ok := c.Get("apikey")
+	if !ok {
+		return apikey.APIKey{}, false
+	}
+	return k.(apikey.APIKey), true
+}
+
 // contextToken returns the typed Token associated with the request.
 func contextToken(c *gin.Context) (token.Token, bool) {
 	t, ok := c.Get("token")
@@ -290,6 +590,71 @@ func contextUserID(c *gin.Context) string {
 	return u.(user.User).ID
 }
 
+// contextPermissions returns the effective Permissions of the user associated with the request, granted by
+// their Roles, and limited by the scopes of the APIKey, if any. Anonymous requests have no permissions.
+// Permissions are computed once per request.
+func contextPermissions(c *gin.Context) role.Permissions {
+	if p, ok := c.Get("permissions"); ok {
+		return p.(role.Permissions)
+	}
+	u, ok := contextUser(c)
+	if !ok {
+		return role.NewPermissions(user.User{}, nil)
+	}
+	var p role.Permissions
+	var err error
+	if k, ok := contextAPIKey(c); ok {
+		p, err = api.APIKeyPermissions(c, u, k)
+	} else if m, ok := contextMembership(c); ok {
+		p, err = api.MembershipPermissions(c, u, m)
+	} else {
+		p, err = api.Permissions(c, u)
+	}
+	if err != nil {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     u.ID,
+			EntityType: "Role",
+			LogLevel:   event.WARN,
+			Message:    fmt.Errorf("read roles: using default roles: %w", err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+	}
+	c.Set("permissions", p)
+	return p
+}
+
+// contextMembership returns the requester's Membership in the active Organization, if the request
+// selected an Organization other than the User's primary Organization.
+func contextMembership(c *gin.Context) (user.Membership, bool) {
+	m, ok := c.Get("membership")
+	if !ok {
+		return user.Membership{}, false
+	}
+	return m.(user.Membership), true
+}
+
+// contextOrg returns the ID and name of the requester's active Organization, if any.
+func contextOrg(c *gin.Context) (string, string) {
+	if m, ok := contextMembership(c); ok {
+		return m.OrgID, m.OrgName
+	}
+	u, _ := contextUser(c)
+	return u.OrgID, u.OrgName
+}
+
+// contextOrgScope returns the Organization ID to which the requester's authority is limited for the specified
+// permission. Users granted the permission globally are not limited to an Organization, so false is returned
+// for them. Otherwise, the user is limited to their own Organization. Requests authenticated with an APIKey
+// are always limited to the Organization of its service account.
+func contextOrgScope(c *gin.Context, perm string) (string, bool) {
+	p := contextPermissions(c)
+	if _, isKey := contextAPIKey(c); !isKey && p.Has(perm) {
+		return "", false
+	}
+	return p.OrgID, true
+}
+
 // paginationParams parses pagination query parameters (reverse, limit, offset), with supplied defaults.
 func paginationParams(c *gin.Context, reverse bool, limit int) (bool, int, string, error) {
 	var err error
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -107,6 +108,48 @@ func generateUsers() {
 	adminToken = aToken.ID
 }
 
+// generateOrgAdmin creates a new Organization with an organization administrator, returning the Organization,
+// the administrator, and their bearer token.
+func generateOrgAdmin(name string) (org.Organization, user.User, string) {
+	ctx := context.Background()
+	o, problems, err := api.OrgService.Create(ctx, org.Organization{
+		Name:   name,
+		Status: org.ENABLED,
+	})
+	if err != nil || len(problems) > 0 {
+		log.Fatal(err)
+	}
+	u, problems, err := api.UserService.Create(ctx, user.User{
+		GivenName:  name,
+		FamilyName: "Manager",
+		Email:      strings.ToLower(strings.ReplaceAll(name, " ", "_")) + "_manager@test.com",
+		Roles:      []string{user.OrgAdminRole},
+		OrgID:      o.ID,
+		OrgName:    o.Name,
+		Status:     user.ENABLED,
+	})
+	if err != nil || len(problems) > 0 {
+		log.Fatal(err)
+	}
+	t, err := api.TokenService.Create(ctx, token.Token{
+		UserID: u.ID,
+		Email:  u.Email,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return o, u, t.ID
+}
+
+// deleteOrgAdmin deletes an Organization and organization administrator created by generateOrgAdmin,
+// along with the administrator's tokens.
+func deleteOrgAdmin(o org.Organization, u user.User) {
+	ctx := context.Background()
+	_ = api.TokenService.DeleteAllTokensByUserID(ctx, u.ID)
+	_, _ = api.UserService.Delete(ctx, u.ID)
+	_, _ = api.OrgService.Delete(ctx, o.ID)
+}
+
 func generateEmails() {
 	ctx := context.Background()
 	// Test Email: admin to regular user
@@ -180,6 +223,7 @@ func generateContent() {
 		Type:       content.BOOK,
 		Comment:    "This is a great book to learn Golang programming language.",
 		Tags:       []string{"v1", "v2", "v3", "edited", "published"},
+		Status:     content.PUBLISHED,
 		EditorID:   adminUser.ID,
 		EditorName: adminUser.FullName(),
 		Authors: []content.Author{
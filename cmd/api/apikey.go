@@ -0,0 +1,292 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+
+	"versionary-api/pkg/apikey"
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/role"
+	"versionary-api/pkg/user"
+)
+
+// registerAPIKeyRoutes initializes the APIKey routes.
+func registerAPIKeyRoutes(r *gin.Engine) {
+	handleRoutes(r, []route{
+		{"POST", "/v1/api_keys", role.APIKeyWrite, global, createAPIKey},
+		{"GET", "/v1/api_keys", role.APIKeyRead, global, readAPIKeys},
+		{"GET", "/v1/api_keys/:id", role.APIKeyRead, global, readAPIKey},
+		{"DELETE", "/v1/api_keys/:id", role.APIKeyWrite, global, revokeAPIKey},
+	})
+}
+
+// APIKeyResponse provides a newly-created APIKey, along with its secret.
+// The secret is displayed only once; it is not stored, and it cannot be recovered.
+type APIKeyResponse struct {
+	APIKey apikey.APIKey `json:"apiKey"`
+	Secret string        `json:"secret"`
+}
+
+// createAPIKey creates a new APIKey for a service account User.
+//
+// @Summary Create APIKey
+// @Description Create a new APIKey
+// @Description Create a new scoped APIKey for a service account User (role "service").
+// @Description Scopes have the form <resource>:read or <resource>:write (e.g. images:write).
+// @Description The secret is returned only once. Use it as a Bearer token in the Authorization header.
+// @Tags APIKey
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param apikey body apikey.APIKey true "APIKey (userId, name, scopes, and optional expiresAt)"
+// @Success 201 {object} APIKeyResponse "Newly-created APIKey and its secret"
+// @Failure 400 {object} APIEvent "Bad Request (invalid JSON body, or user is not a service account)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 422 {object} APIEvent "APIKey validation errors"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Header 201 {string} Location "URL of the newly created APIKey"
+// @Router /v1/api_keys [post]
+func createAPIKey(c *gin.Context) {
+	// Parse the request body as an APIKey
+	var body apikey.APIKey
+	if err := c.ShouldBindJSON(&body); err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
+		return
+	}
+	// Validate the associated service account User
+	u, err := api.UserService.Read(c, body.UserID)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: user %s not found", body.UserID))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   body.UserID,
+			EntityType: "User",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("create api key: read user %s: %w", body.UserID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	if !u.IsServiceAccount() {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: user %s is not a service account (role %s)", u.ID, user.ServiceRole))
+		return
+	}
+	if u.Status == user.DISABLED {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: user %s is disabled", u.ID))
+		return
+	}
+	// Create a new APIKey
+	body.UserID = u.ID
+	body.Email = u.Email
+	k, secret, problems, err := api.APIKeyService.Create(c, body)
+	if len(problems) > 0 && err != nil {
+		abortWithError(c, http.StatusUnprocessableEntity, fmt.Errorf("unprocessable entity: %w", err))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   k.ID,
+			EntityType: k.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("create api key %s for user %s: %w", k.ID, u.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Log the creation
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   k.ID,
+		EntityType: k.Type(),
+		OtherIDs:   []string{u.ID},
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("created APIKey %s %s for User %s with scopes %v", k.ID, k.Name, u.ID, k.Scopes),
+		URI:        c.Request.URL.String(),
+	})
+	// Return the new APIKey and its secret
+	c.Header("Location", c.Request.URL.String()+"/"+k.ID)
+	c.JSON(http.StatusCreated, APIKeyResponse{
+		APIKey: k.Scrub(),
+		Secret: secret,
+	})
+}
+
+// readAPIKeys returns a paginated list of APIKeys, or all APIKeys for a specified User.
+//
+// @Summary List APIKeys
+// @Description List APIKeys
+// @Description List APIKeys, paging with reverse, limit, and offset, or list all APIKeys for a specified User.
+// @Tags APIKey
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param user query string false "User ID or Email"
+// @Param reverse query bool false "Reverse Order (default: false)"
+// @Param limit query int false "Limit (default: 100)"
+// @Param offset query string false "Offset (default: forward/reverse alphanumeric)"
+// @Success 200 {array} apikey.APIKey "APIKeys"
+// @Failure 400 {object} APIEvent "Bad Request (invalid parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/api_keys [get]
+func readAPIKeys(c *gin.Context) {
+	// Parse query parameters, with defaults
+	reverse, limit, offset, err := paginationParams(c, false, 100)
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, err)
+		return
+	}
+	// Read all APIKeys for the specified User
+	if idOrEmail := c.Query("user"); idOrEmail != "" {
+		u, err := api.UserService.Read(c, idOrEmail)
+		if err != nil && errors.Is(err, v.ErrNotFound) {
+			abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: user %s not found", idOrEmail))
+			return
+		}
+		if err != nil {
+			e, _, _ := api.EventService.Create(c, event.Event{
+				UserID:     contextUserID(c),
+				EntityType: "APIKey",
+				LogLevel:   event.ERROR,
+				Message:    fmt.Errorf("read api keys: read user %s: %w", idOrEmail, err).Error(),
+				URI:        c.Request.URL.String(),
+				Err:        err,
+			})
+			abortWithError(c, http.StatusInternalServerError, e)
+			return
+		}
+		keys, err := api.APIKeyService.ReadAllAPIKeysByUserID(c, u.ID)
+		if err != nil {
+			e, _, _ := api.EventService.Create(c, event.Event{
+				UserID:     contextUserID(c),
+				EntityType: "APIKey",
+				LogLevel:   event.ERROR,
+				Message:    fmt.Errorf("read api keys for user %s: %w", u.ID, err).Error(),
+				URI:        c.Request.URL.String(),
+				Err:        err,
+			})
+			abortWithError(c, http.StatusInternalServerError, e)
+			return
+		}
+		c.JSON(http.StatusOK, v.Map(keys, func(k apikey.APIKey) apikey.APIKey { return k.Scrub() }))
+		return
+	}
+	// Read a page of APIKeys
+	keys := api.APIKeyService.ReadAPIKeys(c, reverse, limit, offset)
+	c.JSON(http.StatusOK, v.Map(keys, func(k apikey.APIKey) apikey.APIKey { return k.Scrub() }))
+}
+
+// readAPIKey returns the specified APIKey. The secret is never returned.
+//
+// @Summary Read APIKey
+// @Description Get APIKey
+// @Description Get the specified APIKey. The secret is never returned.
+// @Tags APIKey
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param id path string true "APIKey ID"
+// @Success 200 {object} apikey.APIKey "APIKey"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/api_keys/{id} [get]
+func readAPIKey(c *gin.Context) {
+	// Validate the path parameter ID
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	// Read and return the specified APIKey
+	k, err := api.APIKeyService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: api key %s", id))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "APIKey",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read api key %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	c.JSON(http.StatusOK, k.Scrub())
+}
+
+// revokeAPIKey revokes the specified APIKey. Revoked APIKeys are retained for auditing purposes.
+//
+// @Summary Revoke APIKey
+// @Description Revoke APIKey
+// @Description Revoke and return the specified APIKey. It may no longer be used for authentication.
+// @Tags APIKey
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param id path string true "APIKey ID"
+// @Success 200 {object} apikey.APIKey "APIKey that was revoked"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/api_keys/{id} [delete]
+func revokeAPIKey(c *gin.Context) {
+	// Validate the path parameter ID
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	// Revoke the specified APIKey
+	k, err := api.APIKeyService.Revoke(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: api key %s", id))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "APIKey",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("revoke api key %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Log the revocation
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   k.ID,
+		EntityType: k.Type(),
+		OtherIDs:   []string{k.UserID},
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("revoked APIKey %s %s for User %s", k.ID, k.Name, k.UserID),
+		URI:        c.Request.URL.String(),
+	})
+	// Return the revoked APIKey
+	c.JSON(http.StatusOK, k.Scrub())
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/voxtechnica/tuid-go"
+
+	"versionary-api/pkg/apikey"
+	"versionary-api/pkg/user"
+)
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	// Service account user, with the permissions of an administrator (limited by the scopes of its API keys)
+	svc, _, err := api.UserService.Create(ctx, user.User{
+		GivenName:  "Service",
+		FamilyName: "Account",
+		Email:      "service_account@versionary.net",
+		Roles:      []string{user.ServiceRole, "admin"},
+		OrgID:      userOrg.ID,
+		OrgName:    userOrg.Name,
+		Status:     user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	serve := func(method, path, bearer, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		req.Header.Set("Content-Type", "application/json;charset=UTF-8")
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Regular users may not create API keys
+	w := serve("POST", "/v1/api_keys", regularToken, `{"userId":"`+svc.ID+`","name":"test","scopes":["organizations:read"]}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// API keys are only for service accounts
+	w = serve("POST", "/v1/api_keys", adminToken, `{"userId":"`+regularUser.ID+`","name":"test","scopes":["organizations:read"]}`)
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	// Scopes must be valid
+	w = serve("POST", "/v1/api_keys", adminToken, `{"userId":"`+svc.ID+`","name":"test","scopes":["api_keys:write"]}`)
+	expect.Equal(http.StatusUnprocessableEntity, w.Code, "HTTP Status Code")
+	// Create an API key
+	w = serve("POST", "/v1/api_keys", adminToken, `{"userId":"`+svc.ID+`","name":"Org Reader","scopes":["organizations:read","images:write"]}`)
+	var res APIKeyResponse
+	if !expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") ||
+		!expect.NoError(json.NewDecoder(w.Body).Decode(&res), "Decode JSON APIKeyResponse") {
+		return
+	}
+	expect.True(tuid.IsValid(tuid.TUID(res.APIKey.ID)), "Valid APIKey ID")
+	expect.Empty(res.APIKey.SecretHash, "Secret Hash is not returned")
+	expect.True(apikey.IsSecret(res.Secret), "Secret is returned once")
+	// The secret is not returned again
+	w = serve("GET", "/v1/api_keys/"+res.APIKey.ID, adminToken, "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.NotContains(w.Body.String(), res.Secret)
+		expect.NotContains(w.Body.String(), "secretHash")
+	}
+	w = serve("GET", "/v1/api_keys?user="+svc.Email, adminToken, "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.Contains(w.Body.String(), res.APIKey.ID)
+	}
+
+	// The API key is authorized by scope, within the permissions of its service account
+	w = serve("GET", "/v1/organizations", res.Secret, "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	w = serve("GET", "/v1/organization_names", res.Secret, "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	// Missing scopes are rejected
+	w = serve("POST", "/v1/organizations", res.Secret, `{"name":"Scoped Org","status":"ENABLED"}`)
+	if expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code") {
+		expect.Contains(w.Body.String(), "organizations:write")
+	}
+	w = serve("GET", "/v1/users", res.Secret, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = serve("GET", "/v1/user_names", res.Secret, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// API keys may not manage API keys
+	w = serve("GET", "/v1/api_keys", res.Secret, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Invalid secrets are rejected
+	w = serve("GET", "/v1/organizations", res.Secret+"0", "")
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+
+	// API keys may not grant roles, even with the users:write scope
+	w = serve("POST", "/v1/api_keys", adminToken, `{"userId":"`+svc.ID+`","name":"User Writer","scopes":["users:write"]}`)
+	var userWriter APIKeyResponse
+	if !expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") ||
+		!expect.NoError(json.NewDecoder(w.Body).Decode(&userWriter), "Decode JSON APIKeyResponse") {
+		return
+	}
+	w = serve("POST", "/v1/users", userWriter.Secret, `{"email":"key_admin@versionary.net","password":"Password123!","roles":["admin"],"status":"ENABLED"}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+
+	// API keys do not receive credentials, even with the users:write scope
+	for _, path := range []string{"/v1/users", "/v1/users?status=ENABLED", "/v1/users?role=admin", "/v1/users/" + adminUser.ID + "/versions"} {
+		w = serve("GET", path, userWriter.Secret, "")
+		if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+			expect.Contains(w.Body.String(), adminUser.ID, path)
+			expect.NotContains(w.Body.String(), "passwordHash", path)
+		}
+	}
+
+	// API keys are limited by the permissions of their service account
+	svcReader, _, err := api.UserService.Create(ctx, user.User{
+		GivenName:  "Service",
+		FamilyName: "Reader",
+		Email:      "service_reader@versionary.net",
+		Roles:      []string{user.ServiceRole},
+		Status:     user.ENABLED,
+	})
+	if expect.NoError(err) {
+		w = serve("POST", "/v1/api_keys", adminToken, `{"userId":"`+svcReader.ID+`","name":"Unprivileged","scopes":["organizations:read"]}`)
+		var unprivileged APIKeyResponse
+		if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+			expect.NoError(json.NewDecoder(w.Body).Decode(&unprivileged), "Decode JSON APIKeyResponse") {
+			w = serve("GET", "/v1/organizations", unprivileged.Secret, "")
+			expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+		}
+		_, _ = api.UserService.Delete(ctx, svcReader.ID)
+	}
+
+	// Revoke the API key
+	w = serve("DELETE", "/v1/api_keys/"+res.APIKey.ID, adminToken, "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		var k apikey.APIKey
+		if expect.NoError(json.NewDecoder(w.Body).Decode(&k), "Decode JSON APIKey") {
+			expect.Equal(apikey.REVOKED, k.Status)
+		}
+	}
+	w = serve("GET", "/v1/organizations", res.Secret, "")
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	w = serve("DELETE", "/v1/api_keys/"+tuid.NewID().String(), adminToken, "")
+	expect.Equal(http.StatusNotFound, w.Code, "HTTP Status Code")
+
+	// Clean up
+	_, _ = api.UserService.Delete(ctx, svc.ID)
+}
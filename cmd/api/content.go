@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/voxtechnica/tuid-go"
@@ -13,25 +15,33 @@ import (
 	"versionary-api/pkg/content"
 	"versionary-api/pkg/event"
 	"versionary-api/pkg/ref"
+	"versionary-api/pkg/role"
 )
 
 // registerContentRoutes initializes the Content routes.
 func registerContentRoutes(r *gin.Engine) {
-	r.POST("/v1/contents", roleAuthorizer("admin"), createContent)
-	r.GET("/v1/contents", roleAuthorizer("admin"), readContents)
-	r.GET("/v1/contents/:id", readContent)
-	r.HEAD("/v1/contents/:id", existsContent)
-	r.GET("/v1/contents/:id/versions", roleAuthorizer("admin"), readContentVersions)
-	r.GET("/v1/contents/:id/versions/:versionid", readContentVersion)
-	r.HEAD("/v1/contents/:id/versions/:versionid", existsContentVersion)
-	r.PUT("/v1/contents/:id", roleAuthorizer("admin"), updateContent)
-	r.DELETE("/v1/contents/:id", roleAuthorizer("admin"), deleteContent)
-	r.DELETE("/v1/contents/:id/versions/:versionid", roleAuthorizer("admin"), deleteContentVersion)
-	r.GET("/v1/content_types", roleAuthorizer("admin"), readContentTypes)
-	r.GET("/v1/content_authors", roleAuthorizer("admin"), readContentAuthors)
-	r.GET("/v1/content_editors", roleAuthorizer("admin"), readContentEditors)
-	r.GET("/v1/content_tags", roleAuthorizer("admin"), readContentTags)
-	r.GET("/v1/content_titles", roleAuthorizer("admin"), readContentTitles)
+	handleRoutes(r, []route{
+		{"POST", "/v1/contents", role.ContentWrite, global, createContent},
+		{"GET", "/v1/contents", role.ContentRead, global, readContents},
+		{"GET", "/v1/contents/search", public, global, searchContents},
+		{"GET", "/v1/contents/:id", public, global, readContent},
+		{"HEAD", "/v1/contents/:id", public, global, existsContent},
+		{"GET", "/v1/contents/by-slug/:type/:slug", public, global, readContentBySlug},
+		{"GET", "/v1/contents/:id/versions", role.ContentRead, global, readContentVersions},
+		{"GET", "/v1/contents/:id/versions/:versionid", public, global, readContentVersion},
+		{"HEAD", "/v1/contents/:id/versions/:versionid", public, global, existsContentVersion},
+		{"GET", "/v1/contents/:id/diff", role.ContentRead, global, readContentDiff},
+		{"PUT", "/v1/contents/:id", role.ContentWrite, global, updateContent},
+		{"PUT", "/v1/contents/:id/status", role.ContentWrite, global, updateContentStatus},
+		{"DELETE", "/v1/contents/:id", role.ContentWrite, global, deleteContent},
+		{"DELETE", "/v1/contents/:id/versions/:versionid", role.ContentWrite, global, deleteContentVersion},
+		{"GET", "/v1/content_types", role.ContentRead, global, readContentTypes},
+		{"GET", "/v1/content_authors", role.ContentRead, global, readContentAuthors},
+		{"GET", "/v1/content_editors", role.ContentRead, global, readContentEditors},
+		{"GET", "/v1/content_tags", role.ContentRead, global, readContentTags},
+		{"GET", "/v1/content_statuses", role.ContentRead, global, readContentStatuses},
+		{"GET", "/v1/content_titles", role.ContentRead, global, readContentTitles},
+	})
 }
 
 // createContent creates a new unit of Content.
@@ -39,6 +49,9 @@ func registerContentRoutes(r *gin.Engine) {
 // @Summary Create Content
 // @Description Create a new Content
 // @Description Create a new unit of Content (Book, Chapter, Article, Category, etc.)
+// @Description New Content is a DRAFT, which is not publicly visible until it has been reviewed and published.
+// @Description Optionally, schedule publication (publishAt, an embargo) and removal (unpublishAt, an expiry).
+// @Description The slug, used in permalinks, is generated from the title unless one is provided.
 // @Tags Content
 // @Accept json
 // @Produce json
@@ -48,6 +61,7 @@ func registerContentRoutes(r *gin.Engine) {
 // @Failure 400 {object} APIEvent "Bad Request (invalid JSON body)"
 // @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
 // @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 409 {object} APIEvent "Conflict (the slug is in use by other Content of the same type)"
 // @Failure 422 {object} APIEvent "Content validation errors"
 // @Failure 500 {object} APIEvent "Internal Server Error"
 // @Header 201 {string} Location "URL of the newly created Content"
@@ -59,16 +73,22 @@ func createContent(c *gin.Context) {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
 		return
 	}
-	// Identify the Editor
+	// Identify the Editor, and start a new draft
 	editor, _ := contextUser(c)
 	body.EditorID = editor.ID
 	body.EditorName = editor.FullName()
+	body.Status = content.DRAFT
+	body.PublishedAt = time.Time{}
 	// Create a new Content
 	created, problems, err := api.ContentService.Create(c, body)
 	if len(problems) > 0 && err != nil {
 		abortWithError(c, http.StatusUnprocessableEntity, fmt.Errorf("unprocessable entity: %w", err))
 		return
 	}
+	if err != nil && errors.Is(err, content.ErrDuplicateSlug) {
+		abortWithError(c, http.StatusConflict, fmt.Errorf("conflict: %w", err))
+		return
+	}
 	if err != nil {
 		e, _, _ := api.EventService.Create(c, event.Event{
 			UserID:     contextUserID(c),
@@ -125,17 +145,99 @@ func readContents(c *gin.Context) {
 	c.JSON(http.StatusOK, contents)
 }
 
+// searchContents returns a page of Content matching a full-text search, ranked by relevance.
+//
+// @Summary Search Content
+// @Description Search Content
+// @Description Search the titles, text, link titles, and image alt text of Content for all the words and
+// @Description "quoted phrases" in the query, optionally filtered by type, tag, and author. Hits are ranked by
+// @Description relevance (matches in titles, and of rare words, count for more), with snippets of the matching
+// @Description text, highlighted with <mark> tags. Anonymous readers search the latest PUBLISHED versions that
+// @Description are visible now, while editors (with permission to read Content) search the latest versions,
+// @Description unless they ask for the published versions. At most 100 matching Content (the most recently created)
+// @Description are ranked, so add words or filters to narrow a broad search.
+// @Tags Content
+// @Produce json
+// @Param authorization header string false "OAuth Bearer Token (optional; Editor)"
+// @Param q query string true "Search Query (words and quoted phrases)"
+// @Param type query string false "Type" Enums(BOOK, CHAPTER, ARTICLE, CATEGORY)
+// @Param tag query string false "Tag"
+// @Param author query string false "Author Name"
+// @Param published query bool false "Published Versions? (default: true for anonymous readers, false for editors)"
+// @Param limit query int false "Limit (default: 10)"
+// @Param offset query int false "Offset (number of hits to skip; default: 0)"
+// @Success 200 {object} content.SearchResults "Ranked Search Hits"
+// @Failure 400 {object} APIEvent "Bad Request (invalid parameter, or no searchable words)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/contents/search [get]
+func searchContents(c *gin.Context) {
+	// Parse query parameters, with defaults
+	q := content.SearchQuery{
+		Text:   c.Query("q"),
+		Type:   content.Type(strings.ToUpper(c.Query("type"))),
+		Tag:    c.Query("tag"),
+		Author: c.Query("author"),
+	}
+	if q.Type != "" && !q.Type.IsValid() {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid parameter, type: %s", c.Query("type")))
+		return
+	}
+	editor := contextPermissions(c).Has(role.ContentRead)
+	published, err := strconv.ParseBool(c.DefaultQuery("published", strconv.FormatBool(!editor)))
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid parameter, published: %w", err))
+		return
+	}
+	q.Published = published || !editor
+	_, q.Limit, _, err = paginationParams(c, false, 10)
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, err)
+		return
+	}
+	if o := c.Query("offset"); o != "" {
+		q.Offset, err = strconv.Atoi(o)
+		if err != nil || q.Offset < 0 {
+			abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid parameter, offset: %s", o))
+			return
+		}
+	}
+	// Search the Content
+	results, err := api.ContentService.Search(c, q)
+	if err != nil && errors.Is(err, content.ErrEmptySearch) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid parameter, q: %w", err))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityType: api.ContentService.EntityType,
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("search %s: %w", api.ContentService.EntityType, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
 // readContent returns the current version of the specified Content.
 //
 // @Summary Read Content
 // @Description Get Content
-// @Description Get Content by ID.
+// @Description Get Content by ID. Anonymous readers get the latest PUBLISHED version that is visible now
+// @Description (after its publishAt embargo, if any, and before its unpublishAt expiry), while editors (with
+// @Description permission to read Content) get the latest version, whatever its status, unless they ask
+// @Description for the published version.
 // @Tags Content
 // @Produce json
+// @Param authorization header string false "OAuth Bearer Token (optional; Editor)"
 // @Param id path string true "Content ID"
+// @Param published query bool false "Published Version? (default: true for anonymous readers, false for editors)"
 // @Success 200 {object} content.Content "Content"
 // @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
-// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 404 {object} APIEvent "Not Found (or not published)"
 // @Failure 500 {object} APIEvent "Internal Server Error"
 // @Router /v1/contents/{id} [get]
 func readContent(c *gin.Context) {
@@ -146,8 +248,93 @@ func readContent(c *gin.Context) {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %w", err))
 		return
 	}
+	respondWithContent(c, refID)
+}
+
+// readContentBySlug returns the Content with the specified permalink: its type and slug.
+//
+// @Summary Read Content by Slug
+// @Description Get Content by Slug
+// @Description Get Content by its type (e.g. article) and slug, a human-readable permalink that is unique among
+// @Description the Content of its type. A prior slug is an alias: it's redirected (301 Moved Permanently) to
+// @Description the current slug, so that existing links keep working. Anonymous readers get the latest
+// @Description PUBLISHED version that is visible now, while editors (with permission to read Content) get the
+// @Description latest version, whatever its status, unless they ask for the published version.
+// @Tags Content
+// @Produce json
+// @Param authorization header string false "OAuth Bearer Token (optional; Editor)"
+// @Param type path string true "Content Type (e.g. article)"
+// @Param slug path string true "Content Slug (e.g. my-title)"
+// @Param published query bool false "Published Version? (default: true for anonymous readers, false for editors)"
+// @Success 200 {object} content.Content "Content"
+// @Success 301 "Moved Permanently (the slug is an alias; see the Location header)"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter type or slug)"
+// @Failure 404 {object} APIEvent "Not Found (or not published)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Header 301 {string} Location "URL of the Content with its current slug"
+// @Router /v1/contents/by-slug/{type}/{slug} [get]
+func readContentBySlug(c *gin.Context) {
+	// Validate the path parameters
+	t := content.Type(strings.ToUpper(c.Param("type")))
+	if !t.IsValid() {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter type: %s", c.Param("type")))
+		return
+	}
+	slug := c.Param("slug")
+	if !content.IsValidSlug(slug) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter slug: %s", slug))
+		return
+	}
+	// Find the Content with the slug
+	tv, err := api.ContentService.ReadSlug(c, t, slug)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: %s", content.SlugKey(t, slug)))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityType: api.ContentService.EntityType,
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read slug %s: %w", content.SlugKey(t, slug), err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Redirect an alias to the current slug
+	if tv.Value != slug {
+		location := "/v1/contents/by-slug/" + c.Param("type") + "/" + tv.Value
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+	refID, _ := ref.NewRefID(api.ContentService.EntityType, tv.Key, "")
+	respondWithContent(c, refID)
+}
+
+// respondWithContent responds with the specified Content: the latest published version, unless an editor
+// asks for the latest version.
+func respondWithContent(c *gin.Context, refID ref.RefID) {
+	id := refID.EntityID
+	editor := contextPermissions(c).Has(role.ContentRead)
+	published, err := strconv.ParseBool(c.DefaultQuery("published", strconv.FormatBool(!editor)))
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid parameter, published: %w", err))
+		return
+	}
+	published = published || !editor
 	// Read and return the specified Content
-	j, err := api.ContentService.ReadAsJSON(c, id)
+	var pub content.Content
+	var j []byte
+	if published {
+		pub, err = api.ContentService.ReadPublished(c, id)
+	} else {
+		j, err = api.ContentService.ReadAsJSON(c, id)
+	}
 	if err != nil && errors.Is(err, v.ErrNotFound) {
 		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: %s", refID))
 		return
@@ -165,6 +352,10 @@ func readContent(c *gin.Context) {
 		abortWithError(c, http.StatusInternalServerError, e)
 		return
 	}
+	if published {
+		c.JSON(http.StatusOK, pub)
+		return
+	}
 	c.Data(http.StatusOK, "application/json;charset=UTF-8", j)
 }
 
@@ -249,14 +440,15 @@ func readContentVersions(c *gin.Context) {
 //
 // @Summary Read Content Version
 // @Description Get Content Version
-// @Description Get Content Version by ID and VersionID.
+// @Description Get Content Version by ID and VersionID. Anonymous readers may only read PUBLISHED versions.
 // @Tags Content
 // @Produce json
+// @Param authorization header string false "OAuth Bearer Token (optional; Editor)"
 // @Param id path string true "Content ID"
 // @Param versionid path string true "Content VersionID"
 // @Success 200 {object} content.Content "Content Version"
 // @Failure 400 {object} APIEvent "Bad Request (invalid path parameter)"
-// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 404 {object} APIEvent "Not Found (or not published)"
 // @Failure 500 {object} APIEvent "Internal Server Error"
 // @Router /v1/contents/{id}/versions/{versionid} [get]
 func readContentVersion(c *gin.Context) {
@@ -268,8 +460,11 @@ func readContentVersion(c *gin.Context) {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %w", err))
 		return
 	}
-	// Read and return the Content Version
-	j, err := api.ContentService.ReadVersionAsJSON(c, id, versionid)
+	// Read and return the Content Version (if it's published and visible now, for anonymous readers)
+	version, err := api.ContentService.ReadVersion(c, id, versionid)
+	if err == nil && !version.IsVisibleAt(time.Now()) && !contextPermissions(c).Has(role.ContentRead) {
+		err = v.ErrNotFound
+	}
 	if err != nil && errors.Is(err, v.ErrNotFound) {
 		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: %s", refID))
 		return
@@ -287,7 +482,7 @@ func readContentVersion(c *gin.Context) {
 		abortWithError(c, http.StatusInternalServerError, e)
 		return
 	}
-	c.Data(http.StatusOK, "application/json;charset=UTF-8", j)
+	c.JSON(http.StatusOK, version)
 }
 
 // existsContentVersion checks if the specified Content version exists.
@@ -314,12 +509,88 @@ func existsContentVersion(c *gin.Context) {
 	}
 }
 
+// readContentDiff compares two versions of the specified Content.
+//
+// @Summary Compare Content Versions
+// @Description Compare Content Versions
+// @Description Compare two versions of the specified Content. Sections are matched by ID, and reported as added,
+// @Description removed, moved (to a new parent, or a new order among their siblings), or modified. Changed titles
+// @Description and text have word-level (HTML-aware) edits, and changes to links, images, tags, and authors are
+// @Description listed. Add format=html for a rendering suitable for reviewers, with <ins> and <del> markup.
+// @Tags Content
+// @Produce json,html
+// @Param authorization header string true "OAuth Bearer Token (Editor)"
+// @Param id path string true "Content ID"
+// @Param from query string true "Older Content VersionID"
+// @Param to query string true "Newer Content VersionID"
+// @Param format query string false "Response Format (default: json)" Enums(json, html)
+// @Success 200 {object} content.Diff "Content Diff"
+// @Failure 400 {object} APIEvent "Bad Request (invalid parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Editor)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/contents/{id}/diff [get]
+func readContentDiff(c *gin.Context) {
+	// Validate the parameters
+	id := c.Param("id")
+	fromID, toID := c.Query("from"), c.Query("to")
+	fromRef, err := ref.NewRefID(api.ContentService.EntityType, id, fromID)
+	if err != nil || fromID == "" {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid ID or from VersionID: %s %s", id, fromID))
+		return
+	}
+	toRef, err := ref.NewRefID(api.ContentService.EntityType, id, toID)
+	if err != nil || toID == "" {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid ID or to VersionID: %s %s", id, toID))
+		return
+	}
+	format := strings.ToLower(c.DefaultQuery("format", "json"))
+	if format != "json" && format != "html" {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid parameter, format: %s", format))
+		return
+	}
+	// Read and compare the Content versions
+	var versions []content.Content
+	for _, r := range []ref.RefID{fromRef, toRef} {
+		version, err := api.ContentService.ReadVersion(c, id, r.VersionID)
+		if err != nil && errors.Is(err, v.ErrNotFound) {
+			abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: %s", r))
+			return
+		}
+		if err != nil {
+			e, _, _ := api.EventService.Create(c, event.Event{
+				UserID:     contextUserID(c),
+				EntityID:   id,
+				EntityType: api.ContentService.EntityType,
+				LogLevel:   event.ERROR,
+				Message:    fmt.Errorf("read %s: %w", r, err).Error(),
+				URI:        c.Request.URL.String(),
+				Err:        err,
+			})
+			abortWithError(c, http.StatusInternalServerError, e)
+			return
+		}
+		versions = append(versions, version)
+	}
+	diff := content.NewDiff(versions[0], versions[1])
+	if format == "html" {
+		c.Data(http.StatusOK, "text/html;charset=UTF-8", []byte(diff.HTML()))
+		return
+	}
+	c.JSON(http.StatusOK, diff)
+}
+
 // updateContent updates and returns the specified Content.
 // Note that the updated version needs to be complete; this is not a partial update (e.g. PATCH).
 //
 // @Summary Update Content
 // @Description Update Content
-// @Description Update the provided, complete unit of Content.
+// @Description Update the provided, complete unit of Content. The Status is not changed by an update, except
+// @Description that editing PUBLISHED (or ARCHIVED) Content starts a new DRAFT; the published version remains
+// @Description publicly visible until the draft is published. Use the status endpoint to change the Status.
+// @Description An omitted slug is left unchanged. A changed slug keeps the prior slug as an alias, which is
+// @Description redirected to the new slug, so that existing permalinks still work.
 // @Tags Content
 // @Accept json
 // @Produce json
@@ -330,6 +601,7 @@ func existsContentVersion(c *gin.Context) {
 // @Failure 400 {object} APIEvent "Bad Request (invalid JSON or parameter)"
 // @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
 // @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 409 {object} APIEvent "Conflict (the slug is in use by other Content of the same type)"
 // @Failure 422 {object} APIEvent "Content validation errors"
 // @Failure 500 {object} APIEvent "Internal Server Error"
 // @Router /v1/contents/{id} [put]
@@ -356,12 +628,33 @@ func updateContent(c *gin.Context) {
 	editor, _ := contextUser(c)
 	body.EditorID = editor.ID
 	body.EditorName = editor.FullName()
+	// The Status is changed only by the publishing workflow
+	prior, err := api.ContentService.Read(c, id)
+	if err != nil && !errors.Is(err, v.ErrNotFound) {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: api.ContentService.EntityType,
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read %s: %w", refID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	body.Status = prior.Status.Edited()
+	body.PublishedAt = time.Time{}
 	// Update the specified Content
 	updated, problems, err := api.ContentService.Update(c, body)
 	if len(problems) > 0 && err != nil {
 		abortWithError(c, http.StatusUnprocessableEntity, fmt.Errorf("unprocessable entity %s: %w", refID, err))
 		return
 	}
+	if err != nil && errors.Is(err, content.ErrDuplicateSlug) {
+		abortWithError(c, http.StatusConflict, fmt.Errorf("conflict %s: %w", refID, err))
+		return
+	}
 	if err != nil {
 		e, _, _ := api.EventService.Create(c, event.Event{
 			UserID:     contextUserID(c),
@@ -388,6 +681,111 @@ func updateContent(c *gin.Context) {
 	c.JSON(http.StatusOK, updated)
 }
 
+// ContentStatusRequest is the request body for changing the Status of Content.
+type ContentStatusRequest struct {
+	Status  content.Status `json:"status"`
+	Comment string         `json:"comment,omitempty"`
+}
+
+// updateContentStatus moves the specified Content through the publishing workflow.
+//
+// @Summary Update Content Status
+// @Description Update Content Status
+// @Description Change the Status of the specified Content, saving a new version. The workflow is:
+// @Description DRAFT to IN_REVIEW (submit), IN_REVIEW to DRAFT (return), IN_REVIEW to PUBLISHED (publish),
+// @Description PUBLISHED to ARCHIVED (archive), and ARCHIVED to DRAFT (reopen). Publishing, archiving, and
+// @Description reopening require the content:publish permission. Each change is recorded in the event log.
+// @Tags Content
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Editor)"
+// @Param id path string true "Content ID"
+// @Param status body ContentStatusRequest true "New Status, and an optional comment"
+// @Success 200 {object} content.Content "Content"
+// @Failure 400 {object} APIEvent "Bad Request (invalid JSON, parameter, or status)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Editor, or not permitted to publish)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 422 {object} APIEvent "Unprocessable Entity (the workflow does not permit the change)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/contents/{id}/status [put]
+func updateContentStatus(c *gin.Context) {
+	// Parse the request body
+	var body ContentStatusRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
+		return
+	}
+	status, err := content.ParseStatus(body.Status.String())
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: %w", err))
+		return
+	}
+	// Validate the path parameter ID
+	id := c.Param("id")
+	refID, err := ref.NewRefID(api.ContentService.EntityType, id, "")
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %w", err))
+		return
+	}
+	// Read the current version
+	current, err := api.ContentService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: %s", refID))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: api.ContentService.EntityType,
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read %s: %w", refID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// The workflow must permit the change, and the editor must be permitted to make it
+	from := current.CurrentStatus()
+	t, err := content.FindTransition(from, status)
+	if err != nil {
+		abortWithError(c, http.StatusUnprocessableEntity, fmt.Errorf("unprocessable entity %s: %w", refID, err))
+		return
+	}
+	if t.Publish && !contextPermissions(c).Has(role.ContentPublish) {
+		abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: permission required: %s", role.ContentPublish))
+		return
+	}
+	// Save a new version with the new Status
+	editor, _ := contextUser(c)
+	updated, err := api.ContentService.Transition(c, current, status, editor.ID, editor.FullName(), body.Comment)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: api.ContentService.EntityType,
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("update %s status to %s: %w", refID, status, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Log the change, naming the editor
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     editor.ID,
+		EntityID:   updated.ID,
+		EntityType: api.ContentService.EntityType,
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("changed %s %s status from %s to %s (editor: %s)", updated.RefID(), updated.Title(), from, status, editor.FullName()),
+		URI:        c.Request.URL.String(),
+	})
+	c.JSON(http.StatusOK, updated)
+}
+
 // deleteContent deletes the specified Content.
 //
 // @Summary Delete Content
@@ -632,11 +1030,42 @@ func readContentTags(c *gin.Context) {
 	c.JSON(http.StatusOK, tags)
 }
 
+// readContentStatuses returns a list of Content statuses for which contents exist.
+// It's useful for paging through contents by status (e.g. those awaiting review).
+//
+// @Summary List Content Statuses
+// @Description List Content Statuses
+// @Description List content statuses, for which contents exist.
+// @Tags Content
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Success 200 {array} string "Content Statuses"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/content_statuses [get]
+func readContentStatuses(c *gin.Context) {
+	statuses, err := api.ContentService.ReadAllStatuses(c)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityType: api.ContentService.EntityType,
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read content statuses: %w", err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	c.JSON(http.StatusOK, statuses)
+}
+
 // readContentTitles returns a paginated list of Content titles.
 //
 // @Summary List Content Titles
 // @Description List Content Titles
-// @Description List Content Titles by type, author, editor, or tag, paging with reverse, limit, and offset.
+// @Description List Content Titles by type, author, editor, tag, or status, paging with reverse, limit, and offset.
 // @Description Optionally, filter results with search terms.
 // @Tags Content
 // @Produce json
@@ -645,6 +1074,7 @@ func readContentTags(c *gin.Context) {
 // @Param author query string false "Author Name"
 // @Param editor query string false "Editor ID"
 // @Param tag query string false "Tag"
+// @Param status query string false "Status" Enums(DRAFT, IN_REVIEW, PUBLISHED, ARCHIVED)
 // @Param search query string false "Search Terms, separated by spaces"
 // @Param any query bool false "Any Match? (default: false; all search terms must match)"
 // @Param sorted query bool false "Sort by Title? (not paginated; default: false)"
@@ -673,6 +1103,11 @@ func readContentTitles(c *gin.Context) {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("invalid editor ID: %s", editorID))
 		return
 	}
+	status := strings.ToUpper(c.Query("status"))
+	if status != "" && !content.Status(status).IsValid() {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid status: %s", status))
+		return
+	}
 	// Search query parameters
 	search := c.Query("search")
 	anyMatch, err := strconv.ParseBool(c.DefaultQuery("any", "false"))
@@ -734,6 +1169,17 @@ func readContentTitles(c *gin.Context) {
 			errMessage = fmt.Sprintf("read content titles by tag (%s)", tag)
 			titles, err = api.ContentService.ReadTitlesByTag(c, tag, reverse, limit, offset)
 		}
+	} else if status != "" {
+		if search != "" {
+			errMessage = fmt.Sprintf("search (%s) content titles by status (%s)", search, status)
+			titles, err = api.ContentService.FilterTitlesByStatus(c, status, search, anyMatch)
+		} else if all {
+			errMessage = fmt.Sprintf("read all content titles by status (%s)", status)
+			titles, err = api.ContentService.ReadAllTitlesByStatus(c, status, sortByValue)
+		} else {
+			errMessage = fmt.Sprintf("read content titles by status (%s)", status)
+			titles, err = api.ContentService.ReadTitlesByStatus(c, status, reverse, limit, offset)
+		}
 	} else {
 		if search != "" {
 			errMessage = fmt.Sprintf("search (%s) content titles", search)
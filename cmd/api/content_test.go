@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -13,6 +14,10 @@ import (
 	"github.com/voxtechnica/versionary"
 
 	"versionary-api/pkg/content"
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/role"
+	"versionary-api/pkg/token"
+	"versionary-api/pkg/user"
 	"versionary-api/pkg/util"
 )
 
@@ -965,3 +970,347 @@ func TestReadContentTitles(t *testing.T) {
 		}
 	}
 }
+
+func TestContentWorkflow(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	// A writer may edit Content, but not publish it
+	writerRole, _, err := api.RoleService.Create(ctx, role.Role{Name: "workflow_writer", Permissions: []string{role.ContentWrite}})
+	if !expect.NoError(err) {
+		return
+	}
+	api.InvalidateRoles()
+	defer func() {
+		_, _ = api.RoleService.Delete(ctx, writerRole.ID)
+		api.InvalidateRoles()
+	}()
+	writer, _, err := api.UserService.Create(ctx, user.User{
+		GivenName:  "Workflow",
+		FamilyName: "Writer",
+		Email:      "workflow_writer@test.com",
+		Roles:      []string{writerRole.Name},
+		Status:     user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.UserService.Delete(ctx, writer.ID) }()
+	writerToken, err := api.TokenService.Create(ctx, token.Token{UserID: writer.ID, Email: writer.Email})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _ = api.TokenService.DeleteAllTokensByUserID(ctx, writer.ID) }()
+	call := func(bearer, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "10.0.20.1:1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) content.Content {
+		var con content.Content
+		expect.NoError(json.NewDecoder(w.Body).Decode(&con), "Decode JSON Content")
+		return con
+	}
+
+	// New Content is a draft, visible only to editors
+	w := call(writerToken.ID, "POST", "/v1/contents", `{"type": "ARTICLE", "status": "PUBLISHED", "body": {"title": "Workflow", "text": "First draft."}}`)
+	if !expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") {
+		return
+	}
+	draft := decode(w)
+	defer func() { _, _ = api.ContentService.Delete(ctx, draft.ID) }()
+	expect.Equal(content.DRAFT, draft.Status)
+	path := "/v1/contents/" + draft.ID
+	w = call("", "GET", path, "")
+	expect.Equal(http.StatusNotFound, w.Code, "HTTP Status Code")
+	w = call("", "GET", path+"/versions/"+draft.VersionID, "")
+	expect.Equal(http.StatusNotFound, w.Code, "HTTP Status Code")
+	w = call(writerToken.ID, "GET", path, "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.Equal(draft.VersionID, decode(w).VersionID)
+	}
+
+	// Submit for review, and publish
+	w = call(writerToken.ID, "PUT", path+"/status", `{"status": "pending"}`)
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	w = call(writerToken.ID, "PUT", path+"/status", `{"status": "PUBLISHED"}`)
+	expect.Equal(http.StatusUnprocessableEntity, w.Code, "HTTP Status Code")
+	w = call(writerToken.ID, "PUT", path+"/status", `{"status": "IN_REVIEW", "comment": "Ready for review"}`)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		reviewed := decode(w)
+		expect.Equal(content.IN_REVIEW, reviewed.Status)
+		expect.Equal(writer.ID, reviewed.EditorID)
+		expect.Equal("Ready for review", reviewed.Comment)
+	}
+	w = call(writerToken.ID, "PUT", path+"/status", `{"status": "PUBLISHED"}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call(adminToken, "PUT", path+"/status", `{"status": "PUBLISHED"}`)
+	if !expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		return
+	}
+	published := decode(w)
+	expect.Equal(content.PUBLISHED, published.Status)
+	expect.Equal(adminUser.ID, published.EditorID)
+	w = call("", "GET", path, "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.Equal(published.VersionID, decode(w).VersionID)
+	}
+
+	// Editing published Content starts a new draft; the public still sees the published version
+	published.Body.Text = "Second draft."
+	body, _ := json.Marshal(published)
+	w = call(writerToken.ID, "PUT", path, string(body))
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.Equal(content.DRAFT, decode(w).Status)
+	}
+	w = call("", "GET", path, "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.Equal("First draft.", decode(w).Body.Text)
+	}
+	w = call(writerToken.ID, "GET", path, "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.Equal("Second draft.", decode(w).Body.Text)
+	}
+	w = call(writerToken.ID, "GET", path+"?published=true", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.Equal(published.VersionID, decode(w).VersionID)
+	}
+
+	// List Content by status
+	var titles []versionary.TextValue
+	w = call(writerToken.ID, "GET", "/v1/content_titles?status=draft", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&titles), "Decode JSON Titles") {
+		expect.Contains(versionary.Map(titles, func(tv versionary.TextValue) string { return tv.Key }), draft.ID)
+	}
+	var statuses []string
+	w = call(writerToken.ID, "GET", "/v1/content_statuses", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&statuses), "Decode JSON Statuses") {
+		expect.Contains(statuses, "DRAFT")
+	}
+
+	// Each transition is recorded, naming the editor
+	events, err := api.EventService.ReadEventsByEntityID(ctx, draft.ID, false, 100, "")
+	if expect.NoError(err) {
+		messages := versionary.Map(events, func(e event.Event) string { return e.Message })
+		expect.Contains(strings.Join(messages, "\n"), "from DRAFT to IN_REVIEW (editor: Workflow Writer)")
+		expect.Contains(strings.Join(messages, "\n"), "from IN_REVIEW to PUBLISHED (editor: "+adminUser.FullName()+")")
+	}
+}
+
+func TestContentDiff(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	older, _, err := api.ContentService.Create(ctx, content.Content{
+		Type: content.ARTICLE,
+		Body: content.Section{Title: "Diff", Text: "<p>First draft.</p>"},
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.ContentService.Delete(ctx, older.ID) }()
+	newer := older
+	newer.Body.Text = "<p>Second draft.</p>"
+	newer.Tags = []string{"diff"}
+	newer, _, err = api.ContentService.Update(ctx, newer)
+	if !expect.NoError(err) {
+		return
+	}
+	call := func(bearer, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/v1/contents/"+older.ID+"/diff?"+query, nil)
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		req.RemoteAddr = "10.0.22.1:1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+	query := "from=" + older.VersionID + "&to=" + newer.VersionID
+
+	// JSON
+	w := call(adminToken, query)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		var d content.Diff
+		if expect.NoError(json.NewDecoder(w.Body).Decode(&d), "Decode JSON Diff") {
+			expect.Equal(older.VersionID, d.From.VersionID)
+			expect.Equal(newer.VersionID, d.To.VersionID)
+			expect.Equal(1, d.Summary.Modified)
+			if expect.Len(d.Sections, 1) {
+				expect.Equal("<p><del>First</del><ins>Second</ins> draft.</p>", content.EditsHTML(d.Sections[0].Text))
+			}
+			expect.Len(d.Tags, 1)
+		}
+	}
+
+	// HTML
+	w = call(adminToken, query+"&format=html")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.Contains(w.Header().Get("Content-Type"), "text/html")
+		expect.Contains(w.Body.String(), "<del>First</del><ins>Second</ins>")
+	}
+
+	// Errors
+	expect.Equal(http.StatusUnauthorized, call("", query).Code)
+	expect.Equal(http.StatusForbidden, call(regularToken, query).Code)
+	expect.Equal(http.StatusBadRequest, call(adminToken, "from="+older.VersionID).Code)
+	expect.Equal(http.StatusBadRequest, call(adminToken, query+"&format=pdf").Code)
+	expect.Equal(http.StatusNotFound, call(adminToken, "from="+older.VersionID+"&to="+tuid.NewID().String()).Code)
+}
+
+func TestContentBySlug(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	call := func(method, bearer, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "10.0.24.1:1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+	c, _, err := api.ContentService.Create(ctx, content.Content{
+		Type: content.ARTICLE,
+		Body: content.Section{Title: "Permalink Test", Text: "Find me."},
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.ContentService.Delete(ctx, c.ID) }()
+	expect.Equal("permalink-test", c.Slug)
+	c, err = api.ContentService.Transition(ctx, c, content.IN_REVIEW, adminUser.ID, adminUser.FullName(), "")
+	if err == nil {
+		c, err = api.ContentService.Transition(ctx, c, content.PUBLISHED, adminUser.ID, adminUser.FullName(), "")
+	}
+	if !expect.NoError(err) {
+		return
+	}
+
+	// Published Content is found by its slug
+	w := call("GET", "", "/v1/contents/by-slug/article/permalink-test", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		var found content.Content
+		if expect.NoError(json.NewDecoder(w.Body).Decode(&found), "Decode JSON Content") {
+			expect.Equal(c.ID, found.ID)
+		}
+	}
+	expect.Equal(http.StatusNotFound, call("GET", "", "/v1/contents/by-slug/article/no-such-slug", "").Code)
+	expect.Equal(http.StatusBadRequest, call("GET", "", "/v1/contents/by-slug/widget/permalink-test", "").Code)
+	expect.Equal(http.StatusBadRequest, call("GET", "", "/v1/contents/by-slug/article/Bad_Slug", "").Code)
+
+	// A changed slug redirects the prior slug
+	c.Slug = "permalink-moved"
+	body, _ := json.Marshal(c)
+	w = call("PUT", adminToken, "/v1/contents/"+c.ID, string(body))
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		var updated content.Content
+		if expect.NoError(json.NewDecoder(w.Body).Decode(&updated), "Decode JSON Content") {
+			expect.Equal([]string{"permalink-test"}, updated.SlugAliases)
+		}
+	}
+	w = call("GET", "", "/v1/contents/by-slug/article/permalink-test?published=true", "")
+	expect.Equal(http.StatusMovedPermanently, w.Code, "HTTP Status Code")
+	expect.Equal("/v1/contents/by-slug/article/permalink-moved?published=true", w.Header().Get("Location"))
+	expect.Equal(http.StatusOK, call("GET", "", "/v1/contents/by-slug/article/permalink-moved", "").Code)
+
+	// Slugs and aliases are reserved
+	for _, slug := range []string{"permalink-moved", "permalink-test"} {
+		w = call("POST", adminToken, "/v1/contents", `{"type": "ARTICLE", "slug": "`+slug+`", "body": {"title": "Taken"}}`)
+		expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+	}
+}
+
+func TestContentSearch(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	call := func(bearer, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/v1/contents/search?"+query, nil)
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		req.RemoteAddr = "10.0.25.1:1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+	search := func(bearer, query string) content.SearchResults {
+		var results content.SearchResults
+		w := call(bearer, query)
+		if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+			expect.NoError(json.NewDecoder(w.Body).Decode(&results), "Decode JSON SearchResults")
+		}
+		return results
+	}
+	published, _, err := api.ContentService.Create(ctx, content.Content{
+		Type: content.ARTICLE,
+		Tags: []string{"search"},
+		Body: content.Section{Title: "Searchable", Text: "<p>A paragraph about the elusive numbat.</p>"},
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.ContentService.Delete(ctx, published.ID) }()
+	published, err = api.ContentService.Transition(ctx, published, content.IN_REVIEW, adminUser.ID, adminUser.FullName(), "")
+	if err == nil {
+		_, err = api.ContentService.Transition(ctx, published, content.PUBLISHED, adminUser.ID, adminUser.FullName(), "")
+	}
+	if !expect.NoError(err) {
+		return
+	}
+	draft, _, err := api.ContentService.Create(ctx, content.Content{
+		Type: content.ARTICLE,
+		Body: content.Section{Title: "Unpublished", Text: "<p>Another numbat sighting.</p>"},
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.ContentService.Delete(ctx, draft.ID) }()
+
+	// Anonymous readers find published Content, with highlighted snippets
+	results := search("", `q="elusive+numbat"`)
+	if expect.Equal(1, results.Total) {
+		hit := results.Hits[0]
+		expect.Equal(published.ID, hit.ID)
+		if expect.Len(hit.Snippets, 1) {
+			expect.Equal("A paragraph about the <mark>elusive</mark> <mark>numbat</mark>.", hit.Snippets[0].HTML)
+		}
+	}
+	expect.Equal(1, search("", "q=numbat").Total)
+	expect.Equal(1, search(regularToken, "q=numbat").Total)
+
+	// Editors find drafts, too
+	expect.Equal(2, search(adminToken, "q=numbat").Total)
+	expect.Equal(1, search(adminToken, "q=numbat&published=true").Total)
+	expect.Equal(1, search(adminToken, "q=numbat&tag=search").Total)
+	expect.Equal(0, search(adminToken, "q=numbat&type=chapter").Total)
+	results = search(adminToken, "q=numbat&limit=1&offset=1")
+	expect.Equal(2, results.Total)
+	expect.Len(results.Hits, 1)
+
+	// Anonymous readers don't find the words of an unpublished draft of published Content
+	edited, err := api.ContentService.Read(ctx, published.ID)
+	if expect.NoError(err) {
+		edited.Status = edited.Status.Edited()
+		edited.Body.Text = "<p>A paragraph about the elusive quoll.</p>"
+		_, _, err = api.ContentService.Update(ctx, edited)
+		expect.NoError(err)
+	}
+	expect.Equal(0, search("", "q=quoll").Total)
+	expect.Equal(1, search("", "q=numbat").Total)
+	expect.Equal(1, search(adminToken, "q=quoll").Total)
+
+	// Invalid queries
+	expect.Equal(http.StatusBadRequest, call("", "q=").Code)
+	expect.Equal(http.StatusBadRequest, call("", "q=the").Code)
+	expect.Equal(http.StatusBadRequest, call("", "q=numbat&type=widget").Code)
+	expect.Equal(http.StatusBadRequest, call("", "q=numbat&offset=-1").Code)
+}
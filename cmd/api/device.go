@@ -14,27 +14,30 @@ import (
 	"versionary-api/pkg/device"
 	"versionary-api/pkg/event"
 	"versionary-api/pkg/ref"
+	"versionary-api/pkg/role"
 )
 
 // registerDeviceRoutes initializes the Device routes.
 func registerDeviceRoutes(r *gin.Engine) {
-	r.POST("/v1/devices", createDevice)
-	r.PUT("/v1/devices/:id", updateDevice)
-	r.DELETE("/v1/devices/:id", roleAuthorizer("admin"), deleteDevice)
-	r.DELETE("/v1/devices/:id/versions/:versionid", roleAuthorizer("admin"), deleteDeviceVersion)
-	r.GET("/v1/devices", roleAuthorizer("admin"), readDevices)
-	r.GET("/v1/devices/:id", readDevice)
-	r.HEAD("/v1/devices/:id", existsDevice)
-	r.GET("/v1/devices/:id/versions", roleAuthorizer("admin"), readDeviceVersions)
-	r.GET("/v1/devices/:id/versions/:versionid", readDeviceVersion)
-	r.HEAD("/v1/devices/:id/versions/:versionid", existsDeviceVersion)
-	r.GET("/v1/device_agents", roleAuthorizer("admin"), readDeviceAgents)
-	r.GET("/v1/device_dates", roleAuthorizer("admin"), readDeviceDates)
-	r.GET("/v1/device_user_ids", roleAuthorizer("admin"), readDeviceUserIDs)
-	r.GET("/v1/device_counts", roleAuthorizer("admin"), readDeviceCounts)
-	r.GET("/v1/device_counts/:date", roleAuthorizer("admin"), readDeviceCount)
-	r.HEAD("/v1/device_counts/:date", roleAuthorizer("admin"), existsDeviceCount)
-	r.PUT("/v1/device_counts/:date", roleAuthorizer("admin"), updateDeviceCount)
+	handleRoutes(r, []route{
+		{"POST", "/v1/devices", public, global, createDevice},
+		{"PUT", "/v1/devices/:id", public, global, updateDevice},
+		{"DELETE", "/v1/devices/:id", role.DeviceWrite, global, deleteDevice},
+		{"DELETE", "/v1/devices/:id/versions/:versionid", role.DeviceWrite, global, deleteDeviceVersion},
+		{"GET", "/v1/devices", role.DeviceRead, global, readDevices},
+		{"GET", "/v1/devices/:id", public, global, readDevice},
+		{"HEAD", "/v1/devices/:id", public, global, existsDevice},
+		{"GET", "/v1/devices/:id/versions", role.DeviceRead, global, readDeviceVersions},
+		{"GET", "/v1/devices/:id/versions/:versionid", public, global, readDeviceVersion},
+		{"HEAD", "/v1/devices/:id/versions/:versionid", public, global, existsDeviceVersion},
+		{"GET", "/v1/device_agents", role.DeviceRead, global, readDeviceAgents},
+		{"GET", "/v1/device_dates", role.DeviceRead, global, readDeviceDates},
+		{"GET", "/v1/device_user_ids", role.DeviceRead, global, readDeviceUserIDs},
+		{"GET", "/v1/device_counts", role.DeviceRead, global, readDeviceCounts},
+		{"GET", "/v1/device_counts/:date", role.DeviceRead, global, readDeviceCount},
+		{"HEAD", "/v1/device_counts/:date", role.DeviceRead, global, existsDeviceCount},
+		{"PUT", "/v1/device_counts/:date", role.DeviceWrite, global, updateDeviceCount},
+	})
 }
 
 // createDevice creates a new Device.
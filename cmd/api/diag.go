@@ -13,6 +13,7 @@ import (
 	user_agent "github.com/voxtechnica/user-agent"
 
 	"versionary-api/cmd/api/docs"
+	"versionary-api/pkg/role"
 	"versionary-api/pkg/token"
 	"versionary-api/pkg/user"
 )
@@ -24,15 +25,17 @@ func registerDiagRoutes(r *gin.Engine) {
 	docs.SwaggerInfo.Description = api.Description
 	docs.SwaggerInfo.Version = api.GitHash
 	docs.SwaggerInfo.BasePath = "/"
-	r.GET("/docs", swaggerDocs)
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	handleRoutes(r, []route{
+		{"GET", "/docs", public, global, swaggerDocs},
+		{"GET", "/swagger/*any", public, global, ginSwagger.WrapHandler(swaggerFiles.Handler)},
 
-	// Diagnostic routes
-	r.Any("/echo", roleAuthorizer("admin"), echoRequest)
-	r.GET("/user_agent", userAgent)
-	r.GET("/commit", commit)
-	r.GET("/about", about)
-	r.GET("/", about)
+		// Diagnostic routes
+		{"ANY", "/echo", role.DiagRead, global, echoRequest},
+		{"GET", "/user_agent", public, global, userAgent},
+		{"GET", "/commit", public, global, commit},
+		{"GET", "/about", public, global, about},
+		{"GET", "/", public, global, about},
+	})
 }
 
 // swaggerDocs initializes Swagger and redirects to the Swagger API documentation.
@@ -88,7 +88,7 @@ func TestEcho(t *testing.T) {
 		var e APIEvent
 		if expect.NoError(json.NewDecoder(w.Body).Decode(&e), "Decode JSON Event") {
 			expect.Equal("ERROR", e.LogLevel, "Event Log Level")
-			expect.Equal("unauthorized", e.Message, "Event Message")
+			expect.Equal("unauthorized: permission required: diag:read", e.Message, "Event Message")
 		}
 	}
 	// Admin user (bearer token with admin role)
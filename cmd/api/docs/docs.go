@@ -15,6 +15,32 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "JSON Web Key Set (RFC 7517)\nPublic keys for verifying signed JWT access tokens, identified by key ID (kid).\nThe key set is empty if JWT access tokens are not enabled, or if they are signed with HS256.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth"
+                ],
+                "summary": "JSON Web Key Set",
+                "responses": {
+                    "200": {
+                        "description": "JSON Web Key Set",
+                        "schema": {
+                            "$ref": "#/definitions/token.JWKS"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
         "/about": {
             "get": {
                 "description": "Basic information about the API\nBasic information about the API, including the operating environment and the current git commit.",
@@ -155,7 +181,7 @@ const docTemplate = `{
         },
         "/login": {
             "post": {
-                "description": "Login\nCreate a Token for the specified User, returning both.",
+                "description": "Login\nCreate a Token for the specified User, returning both.\nUsers enrolled in TOTP must also supply a code (TOTP or recovery code).",
                 "consumes": [
                     "application/json"
                 ],
@@ -191,13 +217,19 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthenticated (invalid username or password)",
+                        "description": "Unauthenticated (invalid username, password, or second factor code)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (user is disabled)",
+                        "description": "Unauthorized (user is disabled, email address is not verified, or not a member of the organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests (too many failed login attempts; see Retry-After)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -252,177 +284,283 @@ const docTemplate = `{
                 }
             }
         },
-        "/user_agent": {
-            "get": {
-                "description": "Echo a parsed User-Agent header\nEcho a parsed User-Agent header.",
+        "/oauth/introspect": {
+            "post": {
+                "description": "OAuth 2.0 Token Introspection (RFC 7662)\nDescribe an access Token or a RefreshToken, including whether it is currently active.\nThe caller must have the token:read permission (e.g. an administrator), or be an OAuth client (service account APIKey) with the tokens:read scope.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Diagnostic"
+                    "OAuth"
                 ],
-                "summary": "Parse User-Agent Header",
+                "summary": "OAuth 2.0 Introspect",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User-Agent header",
-                        "name": "user-agent",
+                        "description": "OAuth Bearer Token (Administrator or Service Account) or HTTP Basic client credentials",
+                        "name": "authorization",
                         "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Access Token or Refresh Token",
+                        "name": "token",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "access_token",
+                            "refresh_token"
+                        ],
+                        "type": "string",
+                        "description": "Token Type Hint",
+                        "name": "token_type_hint",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Parsed User-Agent header",
+                        "description": "Token Introspection",
                         "schema": {
-                            "$ref": "#/definitions/user_agent.UserAgent"
+                            "$ref": "#/definitions/token.Introspection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid_request, or invalid_scope without the tokens:read scope)",
+                        "schema": {
+                            "$ref": "#/definitions/token.OAuthError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (invalid_client)",
+                        "schema": {
+                            "$ref": "#/definitions/token.OAuthError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error (server_error)",
+                        "schema": {
+                            "$ref": "#/definitions/token.OAuthError"
                         }
                     }
                 }
             }
         },
-        "/v1/content_authors": {
-            "get": {
-                "description": "List Content Authors\nList content authors, for which contents exist.",
+        "/oauth/revoke": {
+            "post": {
+                "description": "OAuth 2.0 Token Revocation (RFC 7009)\nRevoke an access Token or a RefreshToken. Revoking a RefreshToken also revokes the access\nTokens issued with the same password grant. Invalid or unknown tokens are ignored.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Content"
+                    "OAuth"
                 ],
-                "summary": "List Content Authors",
+                "summary": "OAuth 2.0 Revoke",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
+                        "description": "Access Token or Refresh Token",
+                        "name": "token",
+                        "in": "formData",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "access_token",
+                            "refresh_token"
+                        ],
+                        "type": "string",
+                        "description": "Token Type Hint",
+                        "name": "token_type_hint",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Content Authors",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
+                        "description": "OK"
                     },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                    "400": {
+                        "description": "Bad Request (invalid_request)",
                         "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
+                            "$ref": "#/definitions/token.OAuthError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal Server Error (server_error)",
                         "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
+                            "$ref": "#/definitions/token.OAuthError"
                         }
                     }
                 }
             }
         },
-        "/v1/content_editors": {
-            "get": {
-                "description": "List Content Editors\nList content editors (IDs and names), for which contents exist.",
+        "/oauth/token": {
+            "post": {
+                "description": "OAuth 2.0 Token Endpoint (RFC 6749)\nCreate a new OAuth Bearer Token, using the \"password\", \"client_credentials\", or \"refresh_token\" grant.\nThe password grant accepts an extension parameter \"code\" for Users enrolled in TOTP.\nThe client_credentials grant authenticates a service account with an APIKey ID (client_id)\nand secret (client_secret); the access Token carries the scopes of the APIKey.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Content"
+                    "OAuth"
                 ],
-                "summary": "List Content Editors",
+                "summary": "OAuth 2.0 Token",
                 "parameters": [
                     {
+                        "enum": [
+                            "password",
+                            "client_credentials",
+                            "refresh_token"
+                        ],
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
+                        "description": "Grant Type",
+                        "name": "grant_type",
+                        "in": "formData",
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "Sort by Name? (not paginated; default: false)",
-                        "name": "sorted",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Email or User ID (password grant)",
+                        "name": "username",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password (password grant)",
+                        "name": "password",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "TOTP or recovery code (password grant, if enrolled)",
+                        "name": "code",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Refresh Token (refresh_token grant)",
+                        "name": "refresh_token",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Space-delimited scopes (client_credentials grant)",
+                        "name": "scope",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "APIKey ID (client_credentials grant)",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "APIKey secret (client_credentials grant)",
+                        "name": "client_secret",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Content Editor IDs and Names",
+                        "description": "Token Response",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/versionary.TextValue"
-                            }
+                            "$ref": "#/definitions/token.OAuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid_request, invalid_grant, invalid_scope, unsupported_grant_type)",
+                        "schema": {
+                            "$ref": "#/definitions/token.OAuthError"
                         }
                     },
                     "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "description": "Unauthenticated (invalid_client)",
                         "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
+                            "$ref": "#/definitions/token.OAuthError"
                         }
                     },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                    "429": {
+                        "description": "Too Many Requests (too many failed login attempts; see Retry-After)",
                         "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
+                            "$ref": "#/definitions/token.OAuthError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal Server Error (server_error)",
                         "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
+                            "$ref": "#/definitions/token.OAuthError"
                         }
                     }
                 }
             }
         },
-        "/v1/content_tags": {
-            "get": {
-                "description": "List Content Tags\nList content tags, for which contents exist.",
+        "/register": {
+            "post": {
+                "description": "Register User\nCreate a new User account (self-service), in PENDING status with no roles, and send an email\nverification link. The email domain must be permitted by the configured allow/block lists.\nRegistrations are rate-limited per client IP address. Once their email address is verified,\nthe User joins the organization that verified their email domain, if any.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Content"
+                    "User"
                 ],
-                "summary": "List Content Tags",
+                "summary": "Register User",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
-                        "required": true
+                        "description": "Registration",
+                        "name": "registration",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.Registration"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Content Tags",
+                    "201": {
+                        "description": "Newly-registered User",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/user.User"
                         }
                     },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                    "400": {
+                        "description": "Bad Request (invalid JSON body)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (email domain is not allowed)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict (email address is already in use)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity (invalid registration)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests (too many registrations; see Retry-After)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -436,16 +574,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/content_titles": {
+        "/user_agent": {
             "get": {
-                "description": "List Content Titles\nList Content Titles by type, author, editor, or tag, paging with reverse, limit, and offset.\nOptionally, filter results with search terms.",
+                "description": "Echo a parsed User-Agent header\nEcho a parsed User-Agent header.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Content"
+                    "Diagnostic"
                 ],
-                "summary": "List Content Titles",
+                "summary": "Parse User-Agent Header",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User-Agent header",
+                        "name": "user-agent",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Parsed User-Agent header",
+                        "schema": {
+                            "$ref": "#/definitions/user_agent.UserAgent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/api_keys": {
+            "get": {
+                "description": "List APIKeys\nList APIKeys, paging with reverse, limit, and offset, or list all APIKeys for a specified User.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "APIKey"
+                ],
+                "summary": "List APIKeys",
                 "parameters": [
                     {
                         "type": "string",
@@ -454,80 +620,38 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "enum": [
-                            "BOOK",
-                            "CHAPTER",
-                            "ARTICLE",
-                            "CATEGORY"
-                        ],
-                        "type": "string",
-                        "description": "Type",
-                        "name": "type",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Author Name",
-                        "name": "author",
+                        "description": "User ID or Email",
+                        "name": "user",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Editor ID",
-                        "name": "editor",
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Tag",
-                        "name": "tag",
+                        "type": "integer",
+                        "description": "Limit (default: 100)",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Search Terms, separated by spaces",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Any Match? (default: false; all search terms must match)",
-                        "name": "any",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Sort by Title? (not paginated; default: false)",
-                        "name": "sorted",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (omit for all)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Content IDs and Titles",
+                        "description": "APIKeys",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/versionary.TextValue"
+                                "$ref": "#/definitions/apikey.APIKey"
                             }
                         }
                     },
@@ -556,18 +680,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/content_types": {
-            "get": {
-                "description": "List Content Types\nList content types, for which contents exist.",
+            },
+            "post": {
+                "description": "Create a new APIKey\nCreate a new scoped APIKey for a service account User (role \"service\").\nScopes have the form \u003cresource\u003e:read or \u003cresource\u003e:write (e.g. images:write).\nThe secret is returned only once. Use it as a Bearer token in the Authorization header.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Content"
+                    "APIKey"
                 ],
-                "summary": "List Content Types",
+                "summary": "Create APIKey",
                 "parameters": [
                     {
                         "type": "string",
@@ -575,18 +700,36 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "APIKey (userId, name, scopes, and optional expiresAt)",
+                        "name": "apikey",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apikey.APIKey"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Content Types",
+                    "201": {
+                        "description": "Newly-created APIKey and its secret",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
+                            "$ref": "#/definitions/main.APIKeyResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the newly created APIKey"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request (invalid JSON body, or user is not a service account)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "401": {
                         "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
@@ -599,6 +742,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "422": {
+                        "description": "APIKey validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -608,16 +757,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/contents": {
+        "/v1/api_keys/{id}": {
             "get": {
-                "description": "List Contents\nList Contents, paging with reverse, limit, and offset.",
+                "description": "Get APIKey\nGet the specified APIKey. The secret is never returned.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Content"
+                    "APIKey"
                 ],
-                "summary": "List Contents",
+                "summary": "Read APIKey",
                 "parameters": [
                     {
                         "type": "string",
@@ -626,37 +775,23 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (default: 10)",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "APIKey ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Contents",
+                        "description": "APIKey",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/content.Content"
-                            }
+                            "$ref": "#/definitions/apikey.APIKey"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid parameter)",
+                        "description": "Bad Request (invalid path parameter ID)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -673,6 +808,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -681,18 +822,15 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create a new Content\nCreate a new unit of Content (Book, Chapter, Article, Category, etc.)",
-                "consumes": [
-                    "application/json"
-                ],
+            "delete": {
+                "description": "Revoke APIKey\nRevoke and return the specified APIKey. It may no longer be used for authentication.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Content"
+                    "APIKey"
                 ],
-                "summary": "Create Content",
+                "summary": "Revoke APIKey",
                 "parameters": [
                     {
                         "type": "string",
@@ -702,30 +840,22 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Content",
-                        "name": "content",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/content.Content"
-                        }
+                        "type": "string",
+                        "description": "APIKey ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Newly-created Content",
+                    "200": {
+                        "description": "APIKey that was revoked",
                         "schema": {
-                            "$ref": "#/definitions/content.Content"
-                        },
-                        "headers": {
-                            "Location": {
-                                "type": "string",
-                                "description": "URL of the newly created Content"
-                            }
+                            "$ref": "#/definitions/apikey.APIKey"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid JSON body)",
+                        "description": "Bad Request (invalid path parameter ID)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -742,8 +872,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "422": {
-                        "description": "Content validation errors",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -757,40 +887,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/contents/{id}": {
+        "/v1/content_authors": {
             "get": {
-                "description": "Get Content\nGet Content by ID.",
+                "description": "List Content Authors\nList content authors, for which contents exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Content"
                 ],
-                "summary": "Read Content",
+                "summary": "List Content Authors",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Content ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Content",
+                        "description": "Content Authors",
                         "schema": {
-                            "$ref": "#/definitions/content.Content"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -802,19 +935,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update Content\nUpdate the provided, complete unit of Content.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/v1/content_editors": {
+            "get": {
+                "description": "List Content Editors\nList content editors (IDs and names), for which contents exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Content"
                 ],
-                "summary": "Update Content",
+                "summary": "List Content Editors",
                 "parameters": [
                     {
                         "type": "string",
@@ -824,33 +956,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Content",
-                        "name": "content",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/content.Content"
-                        }
-                    },
-                    {
-                        "type": "string",
-                        "description": "Content ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Sort by Name? (not paginated; default: false)",
+                        "name": "sorted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Content",
-                        "schema": {
-                            "$ref": "#/definitions/content.Content"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request (invalid JSON or parameter)",
+                        "description": "Content Editor IDs and Names",
                         "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/versionary.TextValue"
+                            }
                         }
                     },
                     "401": {
@@ -865,12 +984,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "422": {
-                        "description": "Content validation errors",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -878,16 +991,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Content\nDelete and return the specified Content.",
+            }
+        },
+        "/v1/content_statuses": {
+            "get": {
+                "description": "List Content Statuses\nList content statuses, for which contents exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Content"
                 ],
-                "summary": "Delete Content",
+                "summary": "List Content Statuses",
                 "parameters": [
                     {
                         "type": "string",
@@ -895,26 +1010,16 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Content ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Content that was deleted",
-                        "schema": {
-                            "$ref": "#/definitions/content.Content"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Content Statuses",
                         "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
@@ -929,12 +1034,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -942,45 +1041,68 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "head": {
-                "description": "Content Exists\nCheck if the specified Content exists.",
-                "tags": [
-                    "Content"
-                ],
-                "summary": "Content Exists",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Content ID",
-                        "name": "id",
-                        "in": "path",
+            }
+        },
+        "/v1/content_tags": {
+            "get": {
+                "description": "List Content Tags\nList content tags, for which contents exist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Content"
+                ],
+                "summary": "List Content Tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Content Exists"
+                    "200": {
+                        "description": "Content Tags",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
                     },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter ID)"
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
                     }
                 }
             }
         },
-        "/v1/contents/{id}/versions": {
+        "/v1/content_titles": {
             "get": {
-                "description": "Get Content Versions\nGet Content Versions by ID, paging with reverse, limit, and offset.",
+                "description": "List Content Titles\nList Content Titles by type, author, editor, tag, or status, paging with reverse, limit, and offset.\nOptionally, filter results with search terms.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Content"
                 ],
-                "summary": "List Content Versions",
+                "summary": "List Content Titles",
                 "parameters": [
                     {
                         "type": "string",
@@ -990,11 +1112,64 @@ const docTemplate = `{
                         "required": true
                     },
                     {
+                        "enum": [
+                            "BOOK",
+                            "CHAPTER",
+                            "ARTICLE",
+                            "CATEGORY"
+                        ],
                         "type": "string",
-                        "description": "Content ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author Name",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Editor ID",
+                        "name": "editor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "DRAFT",
+                            "IN_REVIEW",
+                            "PUBLISHED",
+                            "ARCHIVED"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search Terms, separated by spaces",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Any Match? (default: false; all search terms must match)",
+                        "name": "any",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort by Title? (not paginated; default: false)",
+                        "name": "sorted",
+                        "in": "query"
                     },
                     {
                         "type": "boolean",
@@ -1004,7 +1179,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Limit (default: 10)",
+                        "description": "Limit (omit for all)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -1017,11 +1192,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Content Versions",
+                        "description": "Content IDs and Titles",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/content.Content"
+                                "$ref": "#/definitions/versionary.TextValue"
                             }
                         }
                     },
@@ -1043,12 +1218,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1058,47 +1227,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/contents/{id}/versions/{versionid}": {
+        "/v1/content_types": {
             "get": {
-                "description": "Get Content Version\nGet Content Version by ID and VersionID.",
+                "description": "List Content Types\nList content types, for which contents exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Content"
                 ],
-                "summary": "Read Content Version",
+                "summary": "List Content Types",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Content ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Content VersionID",
-                        "name": "versionid",
-                        "in": "path",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Content Version",
+                        "description": "Content Types",
                         "schema": {
-                            "$ref": "#/definitions/content.Content"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter)",
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -1110,16 +1275,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Content Version\nDelete and return the specified Content version.",
+            }
+        },
+        "/v1/contents": {
+            "get": {
+                "description": "List Contents\nList Contents, paging with reverse, limit, and offset.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Content"
                 ],
-                "summary": "Delete Content Version",
+                "summary": "List Contents",
                 "parameters": [
                     {
                         "type": "string",
@@ -1129,29 +1296,36 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Content ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Content VersionID",
-                        "name": "versionid",
-                        "in": "path",
-                        "required": true
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Content version that was deleted",
+                        "description": "Contents",
                         "schema": {
-                            "$ref": "#/definitions/content.Content"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/content.Content"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (invalid parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -1168,12 +1342,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1182,51 +1350,18 @@ const docTemplate = `{
                     }
                 }
             },
-            "head": {
-                "description": "Content Version Exists\nCheck if the specified Content version exists.",
-                "tags": [
-                    "Content"
-                ],
-                "summary": "Content Version Exists",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Content ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Content VersionID",
-                        "name": "versionid",
-                        "in": "path",
-                        "required": true
-                    }
+            "post": {
+                "description": "Create a new Content\nCreate a new unit of Content (Book, Chapter, Article, Category, etc.)\nNew Content is a DRAFT, which is not publicly visible until it has been reviewed and published.\nOptionally, schedule publication (publishAt, an embargo) and removal (unpublishAt, an expiry).\nThe slug, used in permalinks, is generated from the title unless one is provided.",
+                "consumes": [
+                    "application/json"
                 ],
-                "responses": {
-                    "204": {
-                        "description": "Content Version Exists"
-                    },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter)"
-                    },
-                    "404": {
-                        "description": "Not Found"
-                    }
-                }
-            }
-        },
-        "/v1/device_agents": {
-            "get": {
-                "description": "List Device IDs and UserAgents\nList Device IDs and UserAgents, paging with reverse, limit, and offset.\nOptionally, filter results with search terms.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Device"
+                    "Content"
                 ],
-                "summary": "List Device UserAgents",
+                "summary": "Create Content",
                 "parameters": [
                     {
                         "type": "string",
@@ -1236,54 +1371,30 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Search Terms, separated by spaces",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Any Match? (default: false; all search terms must match)",
-                        "name": "any",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Sort by UserAgent? (not paginated; default: false)",
-                        "name": "sorted",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (omit for all)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Content",
+                        "name": "content",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/content.Content"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Device IDs and UserAgents",
+                    "201": {
+                        "description": "Newly-created Content",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/versionary.TextValue"
+                            "$ref": "#/definitions/content.Content"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the newly created Content"
                             }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid parameter)",
+                        "description": "Bad Request (invalid JSON body)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -1300,6 +1411,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "409": {
+                        "description": "Conflict (the slug is in use by other Content of the same type)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Content validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1309,67 +1432,68 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/device_counts": {
+        "/v1/contents/by-slug/{type}/{slug}": {
             "get": {
-                "description": "Get Device Counts\nGet a paginated list of device counts by date.",
+                "description": "Get Content by Slug\nGet Content by its type (e.g. article) and slug, a human-readable permalink that is unique among\nthe Content of its type. A prior slug is an alias: it's redirected (301 Moved Permanently) to\nthe current slug, so that existing links keep working. Anonymous readers get the latest\nPUBLISHED version that is visible now, while editors (with permission to read Content) get the\nlatest version, whatever its status, unless they ask for the published version.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Device"
+                    "Content"
                 ],
-                "summary": "List Device Counts",
+                "summary": "Read Content by Slug",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (optional; Editor)",
                         "name": "authorization",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Content Type (e.g. article)",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Limit (default: 100)",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Content Slug (e.g. my-title)",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
+                        "type": "boolean",
+                        "description": "Published Version? (default: true for anonymous readers, false for editors)",
+                        "name": "published",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Device Counts",
+                        "description": "Content",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/device.Count"
-                            }
+                            "$ref": "#/definitions/content.Content"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request (invalid parameter)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
+                    "301": {
+                        "description": "Moved Permanently (the slug is an alias; see the Location header)",
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the Content with its current slug"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                    "400": {
+                        "description": "Bad Request (invalid path parameter type or slug)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                    "404": {
+                        "description": "Not Found (or not published)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -1383,59 +1507,141 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/device_counts/{date}": {
+        "/v1/contents/search": {
             "get": {
-                "description": "Get Device Count\nGet the number of devices encountered on the specified date.",
+                "description": "Search Content\nSearch the titles, text, link titles, and image alt text of Content for all the words and\n\"quoted phrases\" in the query, optionally filtered by type, tag, and author. Hits are ranked by\nrelevance (matches in titles, and of rare words, count for more), with snippets of the matching\ntext, highlighted with \u003cmark\u003e tags. Anonymous readers search the latest PUBLISHED versions that\nare visible now, while editors (with permission to read Content) search the latest versions,\nunless they ask for the published versions. At most 100 matching Content (the most recently created)\nare ranked, so add words or filters to narrow a broad search.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Device"
+                    "Content"
                 ],
-                "summary": "Read Device Count",
+                "summary": "Search Content",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (optional; Editor)",
                         "name": "authorization",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
                         "type": "string",
-                        "description": "Date (YYYY-MM-DD)",
-                        "name": "date",
-                        "in": "path",
+                        "description": "Search Query (words and quoted phrases)",
+                        "name": "q",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "BOOK",
+                            "CHAPTER",
+                            "ARTICLE",
+                            "CATEGORY"
+                        ],
+                        "type": "string",
+                        "description": "Type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author Name",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Published Versions? (default: true for anonymous readers, false for editors)",
+                        "name": "published",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset (number of hits to skip; default: 0)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Device Count",
+                        "description": "Ranked Search Hits",
                         "schema": {
-                            "$ref": "#/definitions/device.Count"
+                            "$ref": "#/definitions/content.SearchResults"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter date)",
+                        "description": "Bad Request (invalid parameter, or no searchable words)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
+                    }
+                }
+            }
+        },
+        "/v1/contents/{id}": {
+            "get": {
+                "description": "Get Content\nGet Content by ID. Anonymous readers get the latest PUBLISHED version that is visible now\n(after its publishAt embargo, if any, and before its unpublishAt expiry), while editors (with\npermission to read Content) get the latest version, whatever its status, unless they ask\nfor the published version.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Content"
+                ],
+                "summary": "Read Content",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (optional; Editor)",
+                        "name": "authorization",
+                        "in": "header"
                     },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                    {
+                        "type": "string",
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Published Version? (default: true for anonymous readers, false for editors)",
+                        "name": "published",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Content",
+                        "schema": {
+                            "$ref": "#/definitions/content.Content"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Not Found (or not published)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -1449,14 +1655,17 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update Device Count\nUpdate the number of devices encountered on the specified date.",
+                "description": "Update Content\nUpdate the provided, complete unit of Content. The Status is not changed by an update, except\nthat editing PUBLISHED (or ARCHIVED) Content starts a new DRAFT; the published version remains\npublicly visible until the draft is published. Use the status endpoint to change the Status.\nAn omitted slug is left unchanged. A changed slug keeps the prior slug as an alias, which is\nredirected to the new slug, so that existing permalinks still work.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Device"
+                    "Content"
                 ],
-                "summary": "Update Device Count",
+                "summary": "Update Content",
                 "parameters": [
                     {
                         "type": "string",
@@ -1465,23 +1674,32 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "description": "Content",
+                        "name": "content",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/content.Content"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "Date (YYYY-MM-DD)",
-                        "name": "date",
+                        "description": "Content ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Device Count",
+                        "description": "Content",
                         "schema": {
-                            "$ref": "#/definitions/device.Count"
+                            "$ref": "#/definitions/content.Content"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter date)",
+                        "description": "Bad Request (invalid JSON or parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -1498,6 +1716,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "409": {
+                        "description": "Conflict (the slug is in use by other Content of the same type)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Content validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1506,16 +1736,80 @@ const docTemplate = `{
                     }
                 }
             },
-            "head": {
-                "description": "Device Count Exists\nCheck if the specified Device Count exists.",
+            "delete": {
+                "description": "Delete Content\nDelete and return the specified Content.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Device"
+                    "Content"
                 ],
-                "summary": "Device Count Exists",
+                "summary": "Delete Content",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Date (YYYY-MM-DD)",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Content that was deleted",
+                        "schema": {
+                            "$ref": "#/definitions/content.Content"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Content Exists\nCheck if the specified Content exists.",
+                "tags": [
+                    "Content"
+                ],
+                "summary": "Content Exists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Content ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1523,10 +1817,10 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "Device Exists"
+                        "description": "Content Exists"
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter date)"
+                        "description": "Bad Request (invalid path parameter ID)"
                     },
                     "404": {
                         "description": "Not Found"
@@ -1534,33 +1828,68 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/device_dates": {
+        "/v1/contents/{id}/diff": {
             "get": {
-                "description": "Get Device Dates\nGet a list of dates for which devices exist.",
+                "description": "Compare Content Versions\nCompare two versions of the specified Content. Sections are matched by ID, and reported as added,\nremoved, moved (to a new parent, or a new order among their siblings), or modified. Changed titles\nand text have word-level (HTML-aware) edits, and changes to links, images, tags, and authors are\nlisted. Add format=html for a rendering suitable for reviewers, with \u003cins\u003e and \u003cdel\u003e markup.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/html"
                 ],
                 "tags": [
-                    "Device"
+                    "Content"
                 ],
-                "summary": "List Device Dates",
+                "summary": "Compare Content Versions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (Editor)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Older Content VersionID",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Newer Content VersionID",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Response Format (default: json)",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Device Dates",
+                        "description": "Content Diff",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/content.Diff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "401": {
@@ -1570,7 +1899,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an Editor)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -1584,55 +1919,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/device_user_ids": {
-            "get": {
-                "description": "Get Device User IDs\nGet a list of User IDs for which devices exist.",
+        "/v1/contents/{id}/status": {
+            "put": {
+                "description": "Update Content Status\nChange the Status of the specified Content, saving a new version. The workflow is:\nDRAFT to IN_REVIEW (submit), IN_REVIEW to DRAFT (return), IN_REVIEW to PUBLISHED (publish),\nPUBLISHED to ARCHIVED (archive), and ARCHIVED to DRAFT (reopen). Publishing, archiving, and\nreopening require the content:publish permission. Each change is recorded in the event log.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Device"
+                    "Content"
                 ],
-                "summary": "List Device User IDs",
+                "summary": "Update Content Status",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (Editor)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (default: 100)",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "New Status, and an optional comment",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.ContentStatusRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User IDs",
+                        "description": "Content",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/content.Content"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid parameter)",
+                        "description": "Bad Request (invalid JSON, parameter, or status)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -1644,7 +1977,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an Editor, or not permitted to publish)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity (the workflow does not permit the change)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -1658,16 +2003,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/devices": {
+        "/v1/contents/{id}/versions": {
             "get": {
-                "description": "List Devices\nList Devices, paging with reverse, limit, and offset. Optionally, filter by UserID or Date.",
+                "description": "Get Content Versions\nGet Content Versions by ID, paging with reverse, limit, and offset.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Device"
+                    "Content"
                 ],
-                "summary": "List Devices",
+                "summary": "List Content Versions",
                 "parameters": [
                     {
                         "type": "string",
@@ -1678,15 +2023,10 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "User ID (optional, TUID)",
-                        "name": "user",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Date (optional, YYYY-MM-DD)",
-                        "name": "date",
-                        "in": "query"
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "boolean",
@@ -1696,7 +2036,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Limit (default: 100)",
+                        "description": "Limit (default: 10)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -1709,11 +2049,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Devices",
+                        "description": "Content Versions",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/device.Device"
+                                "$ref": "#/definitions/content.Content"
                             }
                         }
                     },
@@ -1735,6 +2075,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1742,80 +2088,104 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new Device\nCreate a new Device from a User-Agent header.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/v1/contents/{id}/versions/{versionid}": {
+            "get": {
+                "description": "Get Content Version\nGet Content Version by ID and VersionID. Anonymous readers may only read PUBLISHED versions.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Device"
+                    "Content"
                 ],
-                "summary": "Create Device",
+                "summary": "Read Content Version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User-Agent Header",
-                        "name": "user-agent",
-                        "in": "header",
+                        "description": "OAuth Bearer Token (optional; Editor)",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Content VersionID",
+                        "name": "versionid",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Newly-created Device",
+                    "200": {
+                        "description": "Content Version",
                         "schema": {
-                            "$ref": "#/definitions/device.Device"
-                        },
-                        "headers": {
-                            "Location": {
-                                "type": "string",
-                                "description": "/v1/devices/{id}"
-                            }
+                            "$ref": "#/definitions/content.Content"
                         }
                     },
-                    "422": {
-                        "description": "Device validation errors",
+                    "400": {
+                        "description": "Bad Request (invalid path parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "500": {
+                    "404": {
+                        "description": "Not Found (or not published)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     }
                 }
-            }
-        },
-        "/v1/devices/{id}": {
-            "get": {
-                "description": "Get Device\nGet Device by ID.",
+            },
+            "delete": {
+                "description": "Delete Content Version\nDelete and return the specified Content version.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Device"
+                    "Content"
                 ],
-                "summary": "Read Device",
+                "summary": "Delete Content Version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Device ID",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Content ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Content VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Device",
+                        "description": "Content version that was deleted",
                         "schema": {
-                            "$ref": "#/definitions/device.Device"
+                            "$ref": "#/definitions/content.Content"
                         }
                     },
                     "400": {
@@ -1824,6 +2194,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1838,107 +2220,94 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update Device\nUpdate the specified Device from a User-Agent header.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            "head": {
+                "description": "Content Version Exists\nCheck if the specified Content version exists.",
                 "tags": [
-                    "Device"
+                    "Content"
                 ],
-                "summary": "Update Device",
+                "summary": "Content Version Exists",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User-Agent Header",
-                        "name": "user-agent",
-                        "in": "header",
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Device ID",
-                        "name": "id",
+                        "description": "Content VersionID",
+                        "name": "versionid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Updated Device",
-                        "schema": {
-                            "$ref": "#/definitions/device.Device"
-                        }
-                    },
-                    "201": {
-                        "description": "Newly-created Device (old Device TTL expired)",
-                        "schema": {
-                            "$ref": "#/definitions/device.Device"
-                        },
-                        "headers": {
-                            "Location": {
-                                "type": "string",
-                                "description": "/v1/devices/{id}"
-                            }
-                        }
+                    "204": {
+                        "description": "Content Version Exists"
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "422": {
-                        "description": "Device validation errors",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
+                        "description": "Bad Request (invalid path parameter)"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
+                    "404": {
+                        "description": "Not Found"
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Device\nDelete and return the specified Device.",
+            }
+        },
+        "/v1/contents/{id}/versions/{versionid}/restore": {
+            "post": {
+                "description": "Restore Content Version\nRestore a prior version of the specified Content, saving it as a new version with a comment\nrecording where it came from. The Status is not restored: the new version is a DRAFT if the\nContent is currently PUBLISHED or ARCHIVED, so that it's reviewed before it's published again.\nA restored slug keeps the current slug as an alias, so that its permalinks still work.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Device"
+                    "Content"
                 ],
-                "summary": "Delete Device",
+                "summary": "Restore Content Version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (Editor)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Device ID",
+                        "description": "Content ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Content VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for restoring the version (optional)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/main.RestoreVersionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Device that was deleted",
+                        "description": "Restored Content",
                         "schema": {
-                            "$ref": "#/definitions/device.Device"
+                            "$ref": "#/definitions/app.Restoration"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (invalid parameter or JSON body)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -1950,7 +2319,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an Editor)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -1961,52 +2330,37 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict (the version is already current, or its slug is in use)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
-                    }
-                }
-            },
-            "head": {
-                "description": "Device Exists\nCheck if the specified Device exists.",
-                "tags": [
-                    "Device"
-                ],
-                "summary": "Device Exists",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Device ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "Device Exists"
                     },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter ID)"
+                    "422": {
+                        "description": "Content validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
                     }
                 }
             }
         },
-        "/v1/devices/{id}/versions": {
+        "/v1/device_agents": {
             "get": {
-                "description": "Get Device Versions\nGet Device Versions by ID, paging with reverse, limit, and offset.",
+                "description": "List Device IDs and UserAgents\nList Device IDs and UserAgents, paging with reverse, limit, and offset.\nOptionally, filter results with search terms.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Device"
                 ],
-                "summary": "List Device Versions",
+                "summary": "List Device UserAgents",
                 "parameters": [
                     {
                         "type": "string",
@@ -2017,10 +2371,21 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Device ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Search Terms, separated by spaces",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Any Match? (default: false; all search terms must match)",
+                        "name": "any",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort by UserAgent? (not paginated; default: false)",
+                        "name": "sorted",
+                        "in": "query"
                     },
                     {
                         "type": "boolean",
@@ -2030,7 +2395,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Limit (default: 100)",
+                        "description": "Limit (omit for all)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -2043,11 +2408,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Device Versions",
+                        "description": "Device IDs and UserAgents",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/device.Device"
+                                "$ref": "#/definitions/versionary.TextValue"
                             }
                         }
                     },
@@ -2069,12 +2434,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2084,47 +2443,67 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/devices/{id}/versions/{versionid}": {
+        "/v1/device_counts": {
             "get": {
-                "description": "Get Device Version\nGet Device Version by ID and VersionID.",
+                "description": "Get Device Counts\nGet a paginated list of device counts by date.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Device"
                 ],
-                "summary": "Read Device Version",
+                "summary": "List Device Counts",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Device ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Device VersionID",
-                        "name": "versionid",
-                        "in": "path",
-                        "required": true
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Device Version",
+                        "description": "Device Counts",
                         "schema": {
-                            "$ref": "#/definitions/device.Device"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/device.Count"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter)",
+                        "description": "Bad Request (invalid parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -2136,16 +2515,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Device Version\nDelete and return the specified Device Version.",
+            }
+        },
+        "/v1/device_counts/{date}": {
+            "get": {
+                "description": "Get Device Count\nGet the number of devices encountered on the specified date.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Device"
                 ],
-                "summary": "Delete Device Version",
+                "summary": "Read Device Count",
                 "parameters": [
                     {
                         "type": "string",
@@ -2156,28 +2537,21 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Device ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Device Version ID",
-                        "name": "versionid",
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "date",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Device version that was deleted",
+                        "description": "Device Count",
                         "schema": {
-                            "$ref": "#/definitions/device.Device"
+                            "$ref": "#/definitions/device.Count"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (invalid path parameter date)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -2208,34 +2582,85 @@ const docTemplate = `{
                     }
                 }
             },
-            "head": {
-                "description": "Device Version Exists\nCheck if the specified Device version exists.",
+            "put": {
+                "description": "Update Device Count\nUpdate the number of devices encountered on the specified date.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Device"
                 ],
-                "summary": "Device Version Exists",
+                "summary": "Update Device Count",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Device ID",
-                        "name": "id",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "date",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Device Count",
+                        "schema": {
+                            "$ref": "#/definitions/device.Count"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter date)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Device Count Exists\nCheck if the specified Device Count exists.",
+                "tags": [
+                    "Device"
+                ],
+                "summary": "Device Count Exists",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Device VersionID",
-                        "name": "versionid",
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "Device Version Exists"
+                        "description": "Device Exists"
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter)"
+                        "description": "Bad Request (invalid path parameter date)"
                     },
                     "404": {
                         "description": "Not Found"
@@ -2243,16 +2668,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/email_addresses": {
+        "/v1/device_dates": {
             "get": {
-                "description": "Get Email Addresses\nGet a list of email addresses for which emails exist.",
+                "description": "Get Device Dates\nGet a list of dates for which devices exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Email"
+                    "Device"
                 ],
-                "summary": "List Email Addresses",
+                "summary": "List Device Dates",
                 "parameters": [
                     {
                         "type": "string",
@@ -2260,29 +2685,11 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (default: 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Email Addresses",
+                        "description": "Device Dates",
                         "schema": {
                             "type": "array",
                             "items": {
@@ -2311,16 +2718,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/email_statuses": {
+        "/v1/device_user_ids": {
             "get": {
-                "description": "Get Email Statuses\nGet a list of status codes for which emails exist.",
+                "description": "Get Device User IDs\nGet a list of User IDs for which devices exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Email"
+                    "Device"
                 ],
-                "summary": "List Email Statuses",
+                "summary": "List Device User IDs",
                 "parameters": [
                     {
                         "type": "string",
@@ -2328,11 +2735,29 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Email Statuses",
+                        "description": "User IDs",
                         "schema": {
                             "type": "array",
                             "items": {
@@ -2340,6 +2765,12 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request (invalid parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "401": {
                         "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
@@ -2361,40 +2792,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/emails": {
+        "/v1/devices": {
             "get": {
-                "description": "List Email Messages\nList Emails, paging with reverse, limit, and offset. Optionally, filter by email address or status.\nRegular users can only list their own Emails. Administrators can list all Emails.",
+                "description": "List Devices\nList Devices, paging with reverse, limit, and offset. Optionally, filter by UserID or Date.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Email"
+                    "Device"
                 ],
-                "summary": "List Email Messages",
+                "summary": "List Devices",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (User or Administrator)",
+                        "description": "OAuth Bearer Token (Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Email Address",
-                        "name": "address",
+                        "description": "User ID (optional, TUID)",
+                        "name": "user",
                         "in": "query"
                     },
                     {
-                        "enum": [
-                            "PENDING",
-                            "SENT",
-                            "UNSENT",
-                            "ERROR"
-                        ],
                         "type": "string",
-                        "description": "Status",
-                        "name": "status",
+                        "description": "Date (optional, YYYY-MM-DD)",
+                        "name": "date",
                         "in": "query"
                     },
                     {
@@ -2418,11 +2843,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Emails",
+                        "description": "Devices",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/email.Email"
+                                "$ref": "#/definitions/device.Device"
                             }
                         }
                     },
@@ -2439,7 +2864,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not Owner or Administrator)",
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -2453,7 +2878,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create/Send a new Email\nCreate and send a new Email message.",
+                "description": "Create a new Device\nCreate a new Device from a User-Agent header.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2461,60 +2886,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Email"
+                    "Device"
                 ],
-                "summary": "Create Email Message",
+                "summary": "Create Device",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
+                        "description": "User-Agent Header",
+                        "name": "user-agent",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "Email",
-                        "name": "email",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/email.Email"
-                        }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Newly-created Email",
+                        "description": "Newly-created Device",
                         "schema": {
-                            "$ref": "#/definitions/email.Email"
+                            "$ref": "#/definitions/device.Device"
                         },
                         "headers": {
                             "Location": {
                                 "type": "string",
-                                "description": "URL of the newly created Email"
+                                "description": "/v1/devices/{id}"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request (invalid JSON body)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "422": {
-                        "description": "Email validation errors",
+                        "description": "Device validation errors",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -2528,27 +2926,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/emails/{id}": {
+        "/v1/devices/{id}": {
             "get": {
-                "description": "Get Email Message\nGet Email Message by ID.",
+                "description": "Get Device\nGet Device by ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Email"
+                    "Device"
                 ],
-                "summary": "Read Email Message",
+                "summary": "Read Device",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (User or Administrator)",
-                        "name": "authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Email ID",
+                        "description": "Device ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2556,9 +2947,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Email",
+                        "description": "Device",
                         "schema": {
-                            "$ref": "#/definitions/email.Email"
+                            "$ref": "#/definitions/device.Device"
                         }
                     },
                     "400": {
@@ -2567,18 +2958,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "403": {
-                        "description": "Unauthorized (not an Owner or Administrator)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -2594,7 +2973,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update/Send Email Message\nUpdate the provided complete Email. If the status is PENDING, the Email is sent.",
+                "description": "Update Device\nUpdate the specified Device from a User-Agent header.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2602,29 +2981,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Email"
+                    "Device"
                 ],
-                "summary": "Update Email Message",
+                "summary": "Update Device",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
+                        "description": "User-Agent Header",
+                        "name": "user-agent",
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "description": "Email",
-                        "name": "email",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/email.Email"
-                        }
-                    },
                     {
                         "type": "string",
-                        "description": "Email ID",
+                        "description": "Device ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2632,31 +3002,31 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Email",
-                        "schema": {
-                            "$ref": "#/definitions/email.Email"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request (invalid JSON or parameter)",
+                        "description": "Updated Device",
                         "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
+                            "$ref": "#/definitions/device.Device"
                         }
                     },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                    "201": {
+                        "description": "Newly-created Device (old Device TTL expired)",
                         "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
+                            "$ref": "#/definitions/device.Device"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "/v1/devices/{id}"
+                            }
                         }
                     },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "422": {
-                        "description": "Email validation errors",
+                        "description": "Device validation errors",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -2670,14 +3040,14 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete Email Message\nDelete and return the specified Email.",
+                "description": "Delete Device\nDelete and return the specified Device.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Email"
+                    "Device"
                 ],
-                "summary": "Delete Email Message",
+                "summary": "Delete Device",
                 "parameters": [
                     {
                         "type": "string",
@@ -2688,7 +3058,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Email ID",
+                        "description": "Device ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2696,9 +3066,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Email that was deleted",
+                        "description": "Device that was deleted",
                         "schema": {
-                            "$ref": "#/definitions/email.Email"
+                            "$ref": "#/definitions/device.Device"
                         }
                     },
                     "400": {
@@ -2734,15 +3104,15 @@ const docTemplate = `{
                 }
             },
             "head": {
-                "description": "Email Message Exists\nCheck if the specified Email exists.",
+                "description": "Device Exists\nCheck if the specified Device exists.",
                 "tags": [
-                    "Email"
+                    "Device"
                 ],
-                "summary": "Email Message Exists",
+                "summary": "Device Exists",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Email ID",
+                        "description": "Device ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2750,7 +3120,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "Email Exists"
+                        "description": "Device Exists"
                     },
                     "400": {
                         "description": "Bad Request (invalid path parameter ID)"
@@ -2761,16 +3131,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/emails/{id}/versions": {
+        "/v1/devices/{id}/versions": {
             "get": {
-                "description": "Get Email Versions\nGet Email Versions by ID, paging with reverse, limit, and offset.",
+                "description": "Get Device Versions\nGet Device Versions by ID, paging with reverse, limit, and offset.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Email"
+                    "Device"
                 ],
-                "summary": "List Email Versions",
+                "summary": "List Device Versions",
                 "parameters": [
                     {
                         "type": "string",
@@ -2781,7 +3151,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Email ID",
+                        "description": "Device ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2807,11 +3177,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Email Versions",
+                        "description": "Device Versions",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/email.Email"
+                                "$ref": "#/definitions/device.Device"
                             }
                         }
                     },
@@ -2828,7 +3198,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Owner or Administrator)",
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -2848,34 +3218,86 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/emails/{id}/versions/{versionid}": {
+        "/v1/devices/{id}/versions/{versionid}": {
             "get": {
-                "description": "Get Email Version\nGet Email Version by ID and VersionID.",
+                "description": "Get Device Version\nGet Device Version by ID and VersionID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Email"
+                    "Device"
                 ],
-                "summary": "Read Email Version",
+                "summary": "Read Device Version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (User or Administrator)",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Device Version",
+                        "schema": {
+                            "$ref": "#/definitions/device.Device"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete Device Version\nDelete and return the specified Device Version.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Device"
+                ],
+                "summary": "Delete Device Version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Email ID",
+                        "description": "Device ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Email VersionID",
+                        "description": "Device Version ID",
                         "name": "versionid",
                         "in": "path",
                         "required": true
@@ -2883,13 +3305,13 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Email Version",
+                        "description": "Device version that was deleted",
                         "schema": {
-                            "$ref": "#/definitions/email.Email"
+                            "$ref": "#/definitions/device.Device"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter)",
+                        "description": "Bad Request (invalid path parameter ID)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -2901,7 +3323,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Owner or Administrator)",
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -2921,22 +3343,22 @@ const docTemplate = `{
                 }
             },
             "head": {
-                "description": "Email Version Exists\nCheck if the specified Email version exists.",
+                "description": "Device Version Exists\nCheck if the specified Device version exists.",
                 "tags": [
-                    "Email"
+                    "Device"
                 ],
-                "summary": "Email Version Exists",
+                "summary": "Device Version Exists",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Email ID",
+                        "description": "Device ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Email VersionID",
+                        "description": "Device VersionID",
                         "name": "versionid",
                         "in": "path",
                         "required": true
@@ -2944,7 +3366,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "Email Version Exists"
+                        "description": "Device Version Exists"
                     },
                     "400": {
                         "description": "Bad Request (invalid path parameter)"
@@ -2955,16 +3377,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/event_dates": {
-            "get": {
-                "description": "List Event Dates\nGet a paginated list of ISO dates (e.g. yyyy-mm-dd) for which events exist.",
+        "/v1/devices/{id}/versions/{versionid}/restore": {
+            "post": {
+                "description": "Restore Device Version\nRestore a prior version of the specified Device (e.g. its User and User-Agent), saving it as a new\nversion. The times it was last seen and expires are not restored.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Event"
+                    "Device"
                 ],
-                "summary": "List Event Dates",
+                "summary": "Restore Device Version",
                 "parameters": [
                     {
                         "type": "string",
@@ -2974,36 +3399,37 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Limit (default: 100)",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Device VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Reason for restoring the version (optional)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/main.RestoreVersionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Dates",
+                        "description": "Restored Device",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/app.Restoration"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid pagination parameter)",
+                        "description": "Bad Request (invalid parameter or JSON body)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -3020,6 +3446,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict (the version is already current)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Device validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3029,16 +3473,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/event_entity_ids": {
+        "/v1/email_addresses": {
             "get": {
-                "description": "List Event Entity IDs\nGet a paginated list of entity IDs for which events exist.",
+                "description": "Get Email Addresses\nGet a list of email addresses for which emails exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Event"
+                    "Email"
                 ],
-                "summary": "List Event Entity IDs",
+                "summary": "List Email Addresses",
                 "parameters": [
                     {
                         "type": "string",
@@ -3068,63 +3512,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Entity IDs",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request (invalid pagination parameter)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/event_entity_types": {
-            "get": {
-                "description": "List Event Entity Types\nGet a complete, sorted list of entity types for which events exist.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Event"
-                ],
-                "summary": "List Event Entity Types",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Entity Types",
+                        "description": "Email Addresses",
                         "schema": {
                             "type": "array",
                             "items": {
@@ -3153,16 +3541,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/event_log_levels": {
+        "/v1/email_statuses": {
             "get": {
-                "description": "List Event Log Levels\nGet a complete, sorted list of log levels for which events exist.",
+                "description": "Get Email Statuses\nGet a list of status codes for which emails exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Event"
+                    "Email"
                 ],
-                "summary": "List Event Log Levels",
+                "summary": "List Email Statuses",
                 "parameters": [
                     {
                         "type": "string",
@@ -3174,7 +3562,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Log Levels",
+                        "description": "Email Statuses",
                         "schema": {
                             "type": "array",
                             "items": {
@@ -3203,140 +3591,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/event_messages": {
-            "get": {
-                "description": "List Event IDs and Log Messages\nList Event IDs and Log Messages, paging with reverse, limit, and offset.\nOptionally, filter results with search terms.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Event"
-                ],
-                "summary": "List Event Log Messages",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search Terms, separated by spaces",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Any Match? (default: false; all search terms must match)",
-                        "name": "any",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (default: 1000)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Event IDs and Log Messages",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/versionary.TextValue"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request (invalid parameter)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/events": {
+        "/v1/emails": {
             "get": {
-                "description": "List Events\nList Events, paging with reverse, limit, and offset.\nOptionally, filter by date, entity ID, entity type, or log level.\nIf no filter is specified, the default is to return up to limit recent Events in reverse order.",
+                "description": "List Email Messages\nList Emails, paging with reverse, limit, and offset. Optionally, filter by email address or status.\nRegular users can only list their own Emails. Administrators can list all Emails.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Event"
+                    "Email"
                 ],
-                "summary": "List Events",
+                "summary": "List Email Messages",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (User or Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Entity ID (a TUID)",
-                        "name": "entity",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Entity Type (e.g. User, Organization, etc.)",
-                        "name": "type",
+                        "description": "Email Address",
+                        "name": "address",
                         "in": "query"
                     },
                     {
                         "enum": [
-                            "TRACE",
-                            "DEBUG",
-                            "INFO",
-                            "WARN",
-                            "ERROR",
-                            "FATAL"
+                            "PENDING",
+                            "SENT",
+                            "UNSENT",
+                            "ERROR"
                         ],
                         "type": "string",
-                        "description": "Log Level",
-                        "name": "log_level",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Date (YYYY-MM-DD)",
-                        "name": "date",
+                        "description": "Status",
+                        "name": "status",
                         "in": "query"
                     },
                     {
@@ -3360,11 +3648,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Events",
+                        "description": "Emails",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/event.Event"
+                                "$ref": "#/definitions/email.Email"
                             }
                         }
                     },
@@ -3381,7 +3669,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not Owner or Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -3395,7 +3683,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new Event\nCreate a new Event.",
+                "description": "Create/Send a new Email\nCreate and send a new Email message.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3403,9 +3691,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Event"
+                    "Email"
                 ],
-                "summary": "Create Event",
+                "summary": "Create Email Message",
                 "parameters": [
                     {
                         "type": "string",
@@ -3413,18 +3701,27 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Email",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/email.Email"
+                        }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Newly-created Event",
+                        "description": "Newly-created Email",
                         "schema": {
-                            "$ref": "#/definitions/event.Event"
+                            "$ref": "#/definitions/email.Email"
                         },
                         "headers": {
                             "Location": {
                                 "type": "string",
-                                "description": "URL of the newly created Event"
+                                "description": "URL of the newly created Email"
                             }
                         }
                     },
@@ -3447,7 +3744,7 @@ const docTemplate = `{
                         }
                     },
                     "422": {
-                        "description": "Event validation errors",
+                        "description": "Email validation errors",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -3461,20 +3758,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/events/{id}": {
+        "/v1/emails/{id}": {
             "get": {
-                "description": "Get Event\nGet Event by ID.",
+                "description": "Get Email Message\nGet Email Message by ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Event"
+                    "Email"
                 ],
-                "summary": "Read Event",
+                "summary": "Read Email Message",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event ID",
+                        "description": "OAuth Bearer Token (User or Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3482,9 +3786,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Event",
+                        "description": "Email",
                         "schema": {
-                            "$ref": "#/definitions/event.Event"
+                            "$ref": "#/definitions/email.Email"
                         }
                     },
                     "400": {
@@ -3493,29 +3797,117 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Unauthorized (not an Owner or Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
-                    }
-                }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update/Send Email Message\nUpdate the provided complete Email. If the status is PENDING, the Email is sent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Email"
+                ],
+                "summary": "Update Email Message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Email",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/email.Email"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email",
+                        "schema": {
+                            "$ref": "#/definitions/email.Email"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid JSON or parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Email validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
             },
             "delete": {
-                "description": "Delete Event\nDelete and return the specified Event.",
+                "description": "Delete Email Message\nDelete and return the specified Email.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Event"
+                    "Email"
                 ],
-                "summary": "Delete Event",
+                "summary": "Delete Email Message",
                 "parameters": [
                     {
                         "type": "string",
@@ -3526,7 +3918,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Event ID",
+                        "description": "Email ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3534,9 +3926,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Deleted Event",
+                        "description": "Email that was deleted",
                         "schema": {
-                            "$ref": "#/definitions/event.Event"
+                            "$ref": "#/definitions/email.Email"
                         }
                     },
                     "400": {
@@ -3572,15 +3964,15 @@ const docTemplate = `{
                 }
             },
             "head": {
-                "description": "Event Exists\nCheck if the specified Event exists.",
+                "description": "Email Message Exists\nCheck if the specified Email exists.",
                 "tags": [
-                    "Event"
+                    "Email"
                 ],
-                "summary": "Event Exists",
+                "summary": "Email Message Exists",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event ID",
+                        "description": "Email ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3588,7 +3980,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "Event Exists"
+                        "description": "Email Exists"
                     },
                     "400": {
                         "description": "Bad Request (invalid path parameter ID)"
@@ -3599,16 +3991,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/image_labels": {
+        "/v1/emails/{id}/versions": {
             "get": {
-                "description": "Get Image Labels\nGet a list of Image Labels, optionally filtered with search terms.",
+                "description": "Get Email Versions\nGet Email Versions by ID, paging with reverse, limit, and offset.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Image"
+                    "Email"
                 ],
-                "summary": "List Image Labels",
+                "summary": "List Email Versions",
                 "parameters": [
                     {
                         "type": "string",
@@ -3619,21 +4011,10 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Search Terms, separated by spaces",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Any Match? (default: false; all search terms must match)",
-                        "name": "any",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Sort by Value? (not paginated; default: false)",
-                        "name": "sorted",
-                        "in": "query"
+                        "description": "Email ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "boolean",
@@ -3643,7 +4024,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Limit (default: 1000)",
+                        "description": "Limit (default: 100)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -3656,11 +4037,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Image Labels",
+                        "description": "Email Versions",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/versionary.TextValue"
+                                "$ref": "#/definitions/email.Email"
                             }
                         }
                     },
@@ -3677,7 +4058,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an Owner or Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -3691,33 +4078,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/image_statuses": {
+        "/v1/emails/{id}/versions/{versionid}": {
             "get": {
-                "description": "Get Image Statuses\nGet a complete list of status codes for which images exist.",
+                "description": "Get Email Version\nGet Email Version by ID and VersionID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Image"
+                    "Email"
                 ],
-                "summary": "List Image Statuses",
+                "summary": "Read Email Version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (User or Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Image Statuses",
+                        "description": "Email Version",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/email.Email"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "401": {
@@ -3727,7 +4131,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an Owner or Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -3739,68 +4149,55 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/image_tags": {
-            "get": {
-                "description": "Get Image Tags\nGet a complete list of tags for which images exist.",
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "head": {
+                "description": "Email Version Exists\nCheck if the specified Email version exists.",
                 "tags": [
-                    "Image"
+                    "Email"
                 ],
-                "summary": "List Image Tags",
+                "summary": "Email Version Exists",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
+                        "description": "Email ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email VersionID",
+                        "name": "versionid",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Image Tags",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
+                    "204": {
+                        "description": "Email Version Exists"
                     },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
+                    "400": {
+                        "description": "Bad Request (invalid path parameter)"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
+                    "404": {
+                        "description": "Not Found"
                     }
                 }
             }
         },
-        "/v1/images": {
-            "get": {
-                "description": "List Images\nList Images, paging with reverse, limit, and offset. Optionally, filter by status.",
+        "/v1/emails/{id}/versions/{versionid}/restore": {
+            "post": {
+                "description": "Restore Email Version\nRestore a prior version of the specified Email, saving it as a new version. The Status is not\nrestored, so restoring a version never sends (or re-sends) the message.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Image"
+                    "Email"
                 ],
-                "summary": "List Images",
+                "summary": "Restore Email Version",
                 "parameters": [
                     {
                         "type": "string",
@@ -3810,22 +4207,97 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "enum": [
-                            "PENDING",
-                            "UPLOADED",
-                            "COMPLETE",
-                            "ERROR"
-                        ],
                         "type": "string",
-                        "description": "Status",
-                        "name": "status",
-                        "in": "query"
+                        "description": "Email ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Tag",
-                        "name": "tag",
-                        "in": "query"
+                        "description": "Email VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for restoring the version (optional)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/main.RestoreVersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored Email",
+                        "schema": {
+                            "$ref": "#/definitions/app.Restoration"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid parameter or JSON body)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict (the version is already current)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Email validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/event_dates": {
+            "get": {
+                "description": "List Event Dates\nGet a paginated list of ISO dates (e.g. yyyy-mm-dd) for which events exist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "List Event Dates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
                     },
                     {
                         "type": "boolean",
@@ -3848,16 +4320,16 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Images",
+                        "description": "Dates",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/image.Image"
+                                "type": "string"
                             }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid parameter)",
+                        "description": "Bad Request (invalid pagination parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -3881,19 +4353,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new Image\nCreate a new Image.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/v1/event_entity_ids": {
+            "get": {
+                "description": "List Event Entity IDs\nGet a paginated list of entity IDs for which events exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Image"
+                    "Event"
                 ],
-                "summary": "Create Image",
+                "summary": "List Event Entity IDs",
                 "parameters": [
                     {
                         "type": "string",
@@ -3903,30 +4374,36 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Image",
-                        "name": "image",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/image.Image"
-                        }
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Newly-created Image",
+                    "200": {
+                        "description": "Entity IDs",
                         "schema": {
-                            "$ref": "#/definitions/image.Image"
-                        },
-                        "headers": {
-                            "Location": {
-                                "type": "string",
-                                "description": "URL of the newly created Image"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
                             }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid JSON body)",
+                        "description": "Bad Request (invalid pagination parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -3943,12 +4420,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "422": {
-                        "description": "Image validation errors",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3958,40 +4429,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/images/{id}": {
+        "/v1/event_entity_types": {
             "get": {
-                "description": "Get Image Metadata.\nGet Image Metadata by ID.",
+                "description": "List Event Entity Types\nGet a complete, sorted list of entity types for which events exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Image"
+                    "Event"
                 ],
-                "summary": "Read Image Metadata",
+                "summary": "List Event Entity Types",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Image ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Image",
+                        "description": "Entity Types",
                         "schema": {
-                            "$ref": "#/definitions/image.Image"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -4003,19 +4477,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update Image\nUpdate the provided, complete Image.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/v1/event_log_levels": {
+            "get": {
+                "description": "List Event Log Levels\nGet a complete, sorted list of log levels for which events exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Image"
+                    "Event"
                 ],
-                "summary": "Update Image",
+                "summary": "List Event Log Levels",
                 "parameters": [
                     {
                         "type": "string",
@@ -4023,35 +4496,16 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "Image",
-                        "name": "image",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/image.Image"
-                        }
-                    },
-                    {
-                        "type": "string",
-                        "description": "Image ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Image",
-                        "schema": {
-                            "$ref": "#/definitions/image.Image"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request (invalid JSON or parameter)",
+                        "description": "Log Levels",
                         "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
@@ -4066,12 +4520,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "422": {
-                        "description": "Image validation errors",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4079,16 +4527,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Image\nDelete and return the specified Image.\nThe associated Image file is also deleted.",
+            }
+        },
+        "/v1/event_messages": {
+            "get": {
+                "description": "List Event IDs and Log Messages\nList Event IDs and Log Messages, paging with reverse, limit, and offset.\nOptionally, filter results with search terms.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Image"
+                    "Event"
                 ],
-                "summary": "Delete Image",
+                "summary": "List Event Log Messages",
                 "parameters": [
                     {
                         "type": "string",
@@ -4099,21 +4549,47 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Image ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Search Terms, separated by spaces",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Any Match? (default: false; all search terms must match)",
+                        "name": "any",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Image that was deleted",
+                        "description": "Event IDs and Log Messages",
                         "schema": {
-                            "$ref": "#/definitions/image.Image"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/versionary.TextValue"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (invalid parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -4130,12 +4606,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4143,69 +4613,101 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "head": {
-                "description": "Image Exists\nCheck if the specified Image exists.",
-                "tags": [
-                    "Image"
-                ],
-                "summary": "Image Exists",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Image ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "Image Exists"
-                    },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter ID)"
-                    },
-                    "404": {
-                        "description": "Not Found"
-                    }
-                }
             }
         },
-        "/v1/images/{id}/download_url": {
+        "/v1/events": {
             "get": {
-                "description": "Get Image Download URL\nGet a pre-signed file download URL for the specified Image.",
+                "description": "List Events\nList Events, paging with reverse, limit, and offset.\nOptionally, filter by date, entity ID, entity type, or log level.\nIf no filter is specified, the default is to return up to limit recent Events in reverse order.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Image"
+                    "Event"
                 ],
-                "summary": "Get Image Download URL",
+                "summary": "List Events",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Image ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entity ID (a TUID)",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entity Type (e.g. User, Organization, etc.)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "TRACE",
+                            "DEBUG",
+                            "INFO",
+                            "WARN",
+                            "ERROR",
+                            "FATAL"
+                        ],
+                        "type": "string",
+                        "description": "Log Level",
+                        "name": "log_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Image Download URL",
+                        "description": "Events",
                         "schema": {
-                            "$ref": "#/definitions/bucket.PreSignedURL"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/event.Event"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (invalid parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -4217,18 +4719,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/images/{id}/similar": {
-            "get": {
-                "description": "Find Similar Images\nFind similar Images, within the specified perceptual hash distance.",
+            },
+            "post": {
+                "description": "Create a new Event\nCreate a new Event.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Image"
+                    "Event"
                 ],
-                "summary": "Find Similar Images",
+                "summary": "Create Event",
                 "parameters": [
                     {
                         "type": "string",
@@ -4236,37 +4739,23 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "maximum": 64,
-                        "minimum": 0,
-                        "type": "integer",
-                        "default": 16,
-                        "description": "Maximum Distance (range: 0-64, default: 16)",
-                        "name": "maxdist",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Limit (default: 20, max: 100)",
-                        "name": "limit",
-                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Image Distances",
+                    "201": {
+                        "description": "Newly-created Event",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/image.Distance"
+                            "$ref": "#/definitions/event.Event"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the newly created Event"
                             }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (invalid JSON body)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -4283,8 +4772,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "422": {
+                        "description": "Event validation errors",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -4298,27 +4787,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/images/{id}/upload_url": {
+        "/v1/events/{id}": {
             "get": {
-                "description": "Get Image Upload URL\nGet a pre-signed file upload URL for the specified Image.",
+                "description": "Get Event\nGet Event by ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Image"
+                    "Event"
                 ],
-                "summary": "Get Image Upload URL",
+                "summary": "Read Event",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Image ID",
+                        "description": "Event ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4326,9 +4808,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Image Download URL",
+                        "description": "Event",
                         "schema": {
-                            "$ref": "#/definitions/bucket.PreSignedURL"
+                            "$ref": "#/definitions/event.Event"
                         }
                     },
                     "400": {
@@ -4337,18 +4819,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -4362,18 +4832,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/images/{id}/versions": {
-            "get": {
-                "description": "Get Image Versions\nGet Image Versions by ID, paging with reverse, limit, and offset.",
+            },
+            "delete": {
+                "description": "Delete Event\nDelete and return the specified Event.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Image"
+                    "Event"
                 ],
-                "summary": "List Image Versions",
+                "summary": "Delete Event",
                 "parameters": [
                     {
                         "type": "string",
@@ -4384,42 +4852,21 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Image ID",
+                        "description": "Event ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (default: 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Image Versions",
+                        "description": "Deleted Event",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/image.Image"
-                            }
+                            "$ref": "#/definitions/event.Event"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid parameter)",
+                        "description": "Bad Request (invalid path parameter ID)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -4449,141 +4896,28 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/images/{id}/versions/{versionid}": {
-            "get": {
-                "description": "Get Image Version\nGet Image Version by ID and VersionID.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Image"
-                ],
-                "summary": "Read Image Version",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Image ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Image VersionID",
-                        "name": "versionid",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Image Version",
-                        "schema": {
-                            "$ref": "#/definitions/image.Image"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "Delete Image Version\nDelete Image Version by ID and VersionID.\nNote that the associated Image file is not deleted.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Image"
-                ],
-                "summary": "Delete Image Version",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Image ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Image VersionID",
-                        "name": "versionid",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Image Version",
-                        "schema": {
-                            "$ref": "#/definitions/image.Image"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    }
-                }
             },
             "head": {
-                "description": "Image Version Exists\nCheck if the specified Image version exists.",
+                "description": "Event Exists\nCheck if the specified Event exists.",
                 "tags": [
-                    "Image"
+                    "Event"
                 ],
-                "summary": "Image Version Exists",
+                "summary": "Event Exists",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Image ID",
+                        "description": "Event ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Image VersionID",
-                        "name": "versionid",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "Image Version Exists"
+                        "description": "Event Exists"
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter)"
+                        "description": "Bad Request (invalid path parameter ID)"
                     },
                     "404": {
                         "description": "Not Found"
@@ -4591,16 +4925,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/metric": {
+        "/v1/image_labels": {
             "get": {
-                "description": "Get Metrics\nGet Metrics, paging with reverse, limit and offset or date range.\nOptionally, filter by entity ID, entity type, or tag.",
+                "description": "Get Image Labels\nGet a list of Image Labels, optionally filtered with search terms.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Metric"
+                    "Image"
                 ],
-                "summary": "Read Metrics",
+                "summary": "List Image Labels",
                 "parameters": [
                     {
                         "type": "string",
@@ -4611,32 +4945,20 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Entity ID (a TUID)",
-                        "name": "entity",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Entity Type (e.g. User, Organization, etc.)",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Tag",
-                        "name": "tag",
+                        "description": "Search Terms, separated by spaces",
+                        "name": "search",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Inclusive Begining of Date Range (YYYY-MM-DD)",
-                        "name": "from",
+                        "type": "boolean",
+                        "description": "Any Match? (default: false; all search terms must match)",
+                        "name": "any",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Exclusive End of Date Range (YYYY-MM-DD)",
-                        "name": "to",
+                        "type": "boolean",
+                        "description": "Sort by Value? (not paginated; default: false)",
+                        "name": "sorted",
                         "in": "query"
                     },
                     {
@@ -4647,7 +4969,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Limit (default: 100)",
+                        "description": "Limit (default: 1000)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -4660,11 +4982,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Metrics",
+                        "description": "Image Labels",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/metric.Metric"
+                                "$ref": "#/definitions/versionary.TextValue"
                             }
                         }
                     },
@@ -4695,40 +5017,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/metric/{id}": {
+        "/v1/image_statuses": {
             "get": {
-                "description": "Get Metric\nGet Metric by ID.",
+                "description": "Get Image Statuses\nGet a complete list of status codes for which images exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Metric"
+                    "Image"
                 ],
-                "summary": "Read Metric",
+                "summary": "List Image Statuses",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Metric ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Metric",
+                        "description": "Image Statuses",
                         "schema": {
-                            "$ref": "#/definitions/metric.Metric"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -4742,16 +5067,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/metric_entity_ids": {
+        "/v1/image_tags": {
             "get": {
-                "description": "List Metric Entity IDs\nList all entity IDs for which metrics exist.",
+                "description": "Get Image Tags\nGet a complete list of tags for which images exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Metric"
+                    "Image"
                 ],
-                "summary": "List Metric Entity IDs",
+                "summary": "List Image Tags",
                 "parameters": [
                     {
                         "type": "string",
@@ -4763,7 +5088,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Entity IDs",
+                        "description": "Image Tags",
                         "schema": {
                             "type": "array",
                             "items": {
@@ -4792,16 +5117,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/metric_entity_types": {
+        "/v1/images": {
             "get": {
-                "description": "List Metric Entity Types\nList all entity types for which metrics exist.",
+                "description": "List Images\nList Images, paging with reverse, limit, and offset. Optionally, filter by status.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Metric"
+                    "Image"
                 ],
-                "summary": "List Metric Entity Types",
+                "summary": "List Images",
                 "parameters": [
                     {
                         "type": "string",
@@ -4809,56 +5134,24 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Entity Types",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
-                        }
                     },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
+                    {
+                        "enum": [
+                            "PENDING",
+                            "UPLOADED",
+                            "COMPLETE",
+                            "ERROR"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/metric_labels": {
-            "get": {
-                "description": "Read Metric Labels\nRead a paginated list of Metric IDs and labels.\nThis is the preferred, more performant method for 'browsing' metrics.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Metric"
-                ],
-                "summary": "Read Metric Labels",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
-                        "required": true
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "query"
                     },
                     {
                         "type": "boolean",
@@ -4881,11 +5174,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Metric ID/Label pairs",
+                        "description": "Images",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/versionary.TextValue"
+                                "$ref": "#/definitions/image.Image"
                             }
                         }
                     },
@@ -4914,59 +5207,52 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/metric_stats": {
-            "get": {
-                "description": "Read Metric Stats\nRead Metric Stats by entity ID, entity type, or tag.\nOptionally, filter by date range.",
+            },
+            "post": {
+                "description": "Create a new Image\nCreate a new Image.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Metric"
+                    "Image"
                 ],
-                "summary": "Read Metric Stats",
+                "summary": "Create Image",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Entity ID",
-                        "name": "entity",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Entity Type",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Tag",
-                        "name": "tag",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Inclusive Begining of Date Range (YYYY-MM-DD)",
-                        "name": "from",
-                        "in": "query"
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Exclusive End of Date Range (YYYY-MM-DD)",
-                        "name": "to",
-                        "in": "query"
+                        "description": "Image",
+                        "name": "image",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/image.Image"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Metric Stats",
+                    "201": {
+                        "description": "Newly-created Image",
                         "schema": {
-                            "$ref": "#/definitions/metric.MetricStat"
+                            "$ref": "#/definitions/image.Image"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the newly created Image"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (query parameter entity or type or tag must be provided)",
+                        "description": "Bad Request (invalid JSON body)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -4983,6 +5269,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "422": {
+                        "description": "Image validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4992,43 +5284,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/metric_tags": {
+        "/v1/images/{id}": {
             "get": {
-                "description": "List Metric Tags\nList all metric tags, for which metrics exist.",
+                "description": "Get Image Metadata.\nGet Image Metadata by ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Metric"
+                    "Image"
                 ],
-                "summary": "List Metric Tags",
+                "summary": "Read Image Metadata",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
+                        "description": "Image ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Metric Tags",
+                        "description": "Image",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/image.Image"
                         }
                     },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -5040,11 +5329,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/metrics": {
-            "post": {
-                "description": "Create a new Metric\nCreate a new Metric.",
+            },
+            "put": {
+                "description": "Update Image\nUpdate the provided, complete Image.",
                 "consumes": [
                     "application/json"
                 ],
@@ -5052,9 +5339,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Metric"
+                    "Image"
                 ],
-                "summary": "Create Metric",
+                "summary": "Update Image",
                 "parameters": [
                     {
                         "type": "string",
@@ -5062,23 +5349,33 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Image",
+                        "name": "image",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/image.Image"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Image ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Newly-created Metric",
+                    "200": {
+                        "description": "Image",
                         "schema": {
-                            "$ref": "#/definitions/metric.Metric"
-                        },
-                        "headers": {
-                            "Location": {
-                                "type": "string",
-                                "description": "URL of the newly created Metric"
-                            }
+                            "$ref": "#/definitions/image.Image"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid JSON body)",
+                        "description": "Bad Request (invalid JSON or parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -5096,7 +5393,7 @@ const docTemplate = `{
                         }
                     },
                     "422": {
-                        "description": "Metric validation errors",
+                        "description": "Image validation errors",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -5108,18 +5405,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/metrics/{id}": {
+            },
             "delete": {
-                "description": "Delete Metric\nDelete and return the specified Metric.",
+                "description": "Delete Image\nDelete and return the specified Image.\nThe associated Image file is also deleted.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Metric"
+                    "Image"
                 ],
-                "summary": "Delete Metric",
+                "summary": "Delete Image",
                 "parameters": [
                     {
                         "type": "string",
@@ -5130,7 +5425,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Metric ID",
+                        "description": "Image ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -5138,9 +5433,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Deleted Metric",
+                        "description": "Image that was deleted",
                         "schema": {
-                            "$ref": "#/definitions/metric.Metric"
+                            "$ref": "#/definitions/image.Image"
                         }
                     },
                     "400": {
@@ -5176,15 +5471,15 @@ const docTemplate = `{
                 }
             },
             "head": {
-                "description": "Metric Exists\nCheck if the specified Metric exists.",
+                "description": "Image Exists\nCheck if the specified Image exists.",
                 "tags": [
-                    "Metric"
+                    "Image"
                 ],
-                "summary": "Metric Exists",
+                "summary": "Image Exists",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Metric ID",
+                        "description": "Image ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -5192,7 +5487,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "Metric Exists"
+                        "description": "Image Exists"
                     },
                     "400": {
                         "description": "Bad Request (invalid path parameter ID)"
@@ -5203,73 +5498,101 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/organization_names": {
+        "/v1/images/{id}/download_url": {
             "get": {
-                "description": "List Organization IDs and Names\nList Organization IDs and Names, paging with reverse, limit, and offset.\nOptionally, filter results with search terms.",
+                "description": "Get Image Download URL\nGet a pre-signed file download URL for the specified Image.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Image"
                 ],
-                "summary": "List Organization Names",
+                "summary": "Get Image Download URL",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
+                        "description": "Image ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image Download URL",
+                        "schema": {
+                            "$ref": "#/definitions/bucket.PreSignedURL"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Search Terms, separated by spaces",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Any Match? (default: false; all search terms must match)",
-                        "name": "any",
-                        "in": "query"
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
                     },
-                    {
-                        "type": "boolean",
-                        "description": "Sort by Name? (not paginated; default: false)",
-                        "name": "sorted",
-                        "in": "query"
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/images/{id}/similar": {
+            "get": {
+                "description": "Find Similar Images\nFind similar Images, within the specified perceptual hash distance.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Image"
+                ],
+                "summary": "Find Similar Images",
+                "parameters": [
                     {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
                     },
                     {
+                        "maximum": 64,
+                        "minimum": 0,
                         "type": "integer",
-                        "description": "Limit (omit for all)",
-                        "name": "limit",
+                        "default": 16,
+                        "description": "Maximum Distance (range: 0-64, default: 16)",
+                        "name": "maxdist",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limit (default: 20, max: 100)",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Organization IDs and Names",
+                        "description": "Image Distances",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/versionary.TextValue"
+                                "$ref": "#/definitions/image.Distance"
                             }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid parameter)",
+                        "description": "Bad Request (invalid path parameter ID)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -5286,6 +5609,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5295,16 +5624,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/organization_statuses": {
+        "/v1/images/{id}/upload_url": {
             "get": {
-                "description": "Get Organization Statuses\nGet a list of status codes for which organizations exist.",
+                "description": "Get Image Upload URL\nGet a pre-signed file upload URL for the specified Image.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Image"
                 ],
-                "summary": "List Organization Statuses",
+                "summary": "Get Image Upload URL",
                 "parameters": [
                     {
                         "type": "string",
@@ -5312,16 +5641,26 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Image ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Organization Statuses",
+                        "description": "Image Download URL",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/bucket.PreSignedURL"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "401": {
@@ -5336,6 +5675,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5345,16 +5690,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/organizations": {
+        "/v1/images/{id}/versions": {
             "get": {
-                "description": "List Organizations\nList Organizations, paging with reverse, limit, and offset. Optionally, filter by status.",
+                "description": "Get Image Versions\nGet Image Versions by ID, paging with reverse, limit, and offset.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Image"
                 ],
-                "summary": "List Organizations",
+                "summary": "List Image Versions",
                 "parameters": [
                     {
                         "type": "string",
@@ -5364,15 +5709,11 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "enum": [
-                            "PENDING",
-                            "ENABLED",
-                            "DISABLED"
-                        ],
                         "type": "string",
-                        "description": "Status",
-                        "name": "status",
-                        "in": "query"
+                        "description": "Image ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "boolean",
@@ -5395,11 +5736,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Organizations",
+                        "description": "Image Versions",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/org.Organization"
+                                "$ref": "#/definitions/image.Image"
                             }
                         }
                     },
@@ -5421,6 +5762,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5428,70 +5775,49 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new Organization\nCreate a new Organization.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/v1/images/{id}/versions/{versionid}": {
+            "get": {
+                "description": "Get Image Version\nGet Image Version by ID and VersionID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Image"
                 ],
-                "summary": "Create Organization",
+                "summary": "Read Image Version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
+                        "description": "Image ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Organization",
-                        "name": "organization",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/org.Organization"
-                        }
+                        "type": "string",
+                        "description": "Image VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Newly-created Organization",
+                    "200": {
+                        "description": "Image Version",
                         "schema": {
-                            "$ref": "#/definitions/org.Organization"
-                        },
-                        "headers": {
-                            "Location": {
-                                "type": "string",
-                                "description": "URL of the newly created Organization"
-                            }
+                            "$ref": "#/definitions/image.Image"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid JSON body)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Bad Request (invalid path parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "422": {
-                        "description": "Organization validation errors",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -5503,36 +5829,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/organizations/{id}": {
-            "get": {
-                "description": "Get Organization\nGet Organization by ID.",
+            },
+            "delete": {
+                "description": "Delete Image Version\nDelete Image Version by ID and VersionID.\nNote that the associated Image file is not deleted.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Image"
                 ],
-                "summary": "Read Organization",
+                "summary": "Delete Image Version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Organization ID",
+                        "description": "Image ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Image VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Organization",
+                        "description": "Image Version",
                         "schema": {
-                            "$ref": "#/definitions/org.Organization"
+                            "$ref": "#/definitions/image.Image"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (invalid path parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -5551,91 +5882,54 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update Organization\nUpdate the provided, complete Organization.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            "head": {
+                "description": "Image Version Exists\nCheck if the specified Image version exists.",
                 "tags": [
-                    "Organization"
+                    "Image"
                 ],
-                "summary": "Update Organization",
+                "summary": "Image Version Exists",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
+                        "description": "Image ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
-                    {
-                        "description": "Organization",
-                        "name": "organization",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/org.Organization"
-                        }
-                    },
                     {
                         "type": "string",
-                        "description": "Organization ID",
-                        "name": "id",
+                        "description": "Image VersionID",
+                        "name": "versionid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Organization",
-                        "schema": {
-                            "$ref": "#/definitions/org.Organization"
-                        }
+                    "204": {
+                        "description": "Image Version Exists"
                     },
                     "400": {
-                        "description": "Bad Request (invalid JSON or parameter)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "422": {
-                        "description": "Organization validation errors",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
+                        "description": "Bad Request (invalid path parameter)"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
+                    "404": {
+                        "description": "Not Found"
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Organization\nDelete and return the specified Organization.",
+            }
+        },
+        "/v1/images/{id}/versions/{versionid}/restore": {
+            "post": {
+                "description": "Restore Image Version\nRestore a prior version of the specified Image's metadata (e.g. its title, alt text, and tags),\nsaving it as a new version. Only the current image file is stored, so the file and its analysis\n(size, hash, dimensions, etc.) are not restored.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Image"
                 ],
-                "summary": "Delete Organization",
+                "summary": "Restore Image Version",
                 "parameters": [
                     {
                         "type": "string",
@@ -5646,21 +5940,36 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Organization ID",
+                        "description": "Image ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Image VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for restoring the version (optional)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/main.RestoreVersionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Organization that was deleted",
+                        "description": "Restored Image",
                         "schema": {
-                            "$ref": "#/definitions/org.Organization"
+                            "$ref": "#/definitions/app.Restoration"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (invalid parameter or JSON body)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -5683,110 +5992,91 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict (the version is already current)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
-                    }
-                }
-            },
-            "head": {
-                "description": "Organization Exists\nCheck if the specified Organization exists.",
-                "tags": [
-                    "Organization"
-                ],
-                "summary": "Organization Exists",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Organization ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "Organization Exists"
                     },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter ID)"
+                    "422": {
+                        "description": "Image validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
                     }
                 }
             }
         },
-        "/v1/organizations/{id}/versions": {
-            "get": {
-                "description": "Get Organization Versions\nGet Organization Versions by ID, paging with reverse, limit, and offset.",
+        "/v1/invitations/{id}/accept": {
+            "post": {
+                "description": "Accept an Organization Invitation\nAccept an Invitation with the token from the invitation link. If a User with the invited email\naddress exists, they join the Organization with the granted roles (as a member, if they belong\nto another Organization). Otherwise, a new User is created with the supplied name and password.\nEither way, the email address is verified.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Invitation"
                 ],
-                "summary": "Read Organization Versions",
+                "summary": "Accept Invitation",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization ID",
+                        "description": "Invitation ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (default: 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Invitation Token, and new User name and password",
+                        "name": "acceptance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.InvitationAcceptance"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Organization Versions",
+                        "description": "Existing User, now a member of the Organization",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/org.Organization"
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "201": {
+                        "description": "Newly-created User",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the newly created User"
                             }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid parameter)",
+                        "description": "Bad Request (invalid JSON body or path parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "description": "Unauthenticated (invalid or expired invitation token)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (authenticated as a different User)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -5797,6 +6087,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "409": {
+                        "description": "Conflict (invitation is closed)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity (invalid name or password)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5806,96 +6108,105 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/organizations/{id}/versions/{versionid}": {
+        "/v1/jobs": {
             "get": {
-                "description": "Get Organization Version\nGet Organization Version by ID and VersionID.",
+                "description": "List Jobs\nList recent background Jobs (retained for 30 days), paging with reverse, limit, and offset.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Job"
                 ],
-                "summary": "Read Organization Version",
+                "summary": "List Jobs",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Organization ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Organization VersionID",
-                        "name": "versionid",
-                        "in": "path",
-                        "required": true
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Organization Version",
+                        "description": "Jobs",
                         "schema": {
-                            "$ref": "#/definitions/org.Organization"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/job.Job"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter)",
+                        "description": "Bad Request (invalid parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Organization Version\nDelete and return the specified Organization version.",
+            }
+        },
+        "/v1/jobs/{id}": {
+            "get": {
+                "description": "Get Job\nGet a background Job by ID, to follow its progress. Users may read the Jobs they started,\nand organization administrators may read the Jobs about their organization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Job"
                 ],
-                "summary": "Delete Organization Version",
+                "summary": "Read Job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Organization ID",
+                        "description": "Job ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization Version ID",
-                        "name": "versionid",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Organization version that was deleted",
+                        "description": "Job",
                         "schema": {
-                            "$ref": "#/definitions/org.Organization"
+                            "$ref": "#/definitions/job.Job"
                         }
                     },
                     "400": {
@@ -5911,7 +6222,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not the User that started the Job, or an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -5929,64 +6240,54 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "head": {
-                "description": "Organization Version Exists\nCheck if the specified Organization version exists.",
+            }
+        },
+        "/v1/metric": {
+            "get": {
+                "description": "Get Metrics\nGet Metrics, paging with reverse, limit and offset or date range.\nOptionally, filter by entity ID, entity type, or tag.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Organization"
+                    "Metric"
                 ],
-                "summary": "Organization Version Exists",
+                "summary": "Read Metrics",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Organization ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Organization VersionID",
-                        "name": "versionid",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "Organization Version Exists"
+                        "description": "Entity ID (a TUID)",
+                        "name": "entity",
+                        "in": "query"
                     },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter)"
+                    {
+                        "type": "string",
+                        "description": "Entity Type (e.g. User, Organization, etc.)",
+                        "name": "type",
+                        "in": "query"
                     },
-                    "404": {
-                        "description": "Not Found"
-                    }
-                }
-            }
-        },
-        "/v1/token_ids": {
-            "get": {
-                "description": "List Token/User ID pairs\nList Token/User ID pairs. This is useful for paging through tokens.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Token"
-                ],
-                "summary": "List Token/User ID Pairs",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
-                        "required": true
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Sort by User ID? (not paginated; default: false)",
-                        "name": "sorted",
+                        "type": "string",
+                        "description": "Inclusive Begining of Date Range (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exclusive End of Date Range (YYYY-MM-DD)",
+                        "name": "to",
                         "in": "query"
                     },
                     {
@@ -5997,7 +6298,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Limit (default: all)",
+                        "description": "Limit (default: 100)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -6010,16 +6311,16 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Token/User ID pairs",
+                        "description": "Metrics",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/versionary.TextValue"
+                                "$ref": "#/definitions/metric.Metric"
                             }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid query parameter)",
+                        "description": "Bad Request (invalid parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -6045,85 +6346,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/token_users": {
+        "/v1/metric/{id}": {
             "get": {
-                "description": "List Users for which tokens exist\nList User IDs and email addresses for which tokens exist.\nThis is useful for paging through tokens by user.",
+                "description": "Get Metric\nGet Metric by ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Token"
+                    "Metric"
                 ],
-                "summary": "List Token Users",
+                "summary": "Read Metric",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
-                        "name": "authorization",
-                        "in": "header",
+                        "description": "Metric ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search Terms, separated by spaces",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Any Match? (default: false; all search terms must match)",
-                        "name": "any",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Sort by Email? (not paginated; default: false)",
-                        "name": "sorted",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (default: all)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User IDs and Email Addresses",
+                        "description": "Metric",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/versionary.TextValue"
-                            }
+                            "$ref": "#/definitions/metric.Metric"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid query parameter)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "description": "Bad Request (invalid path parameter ID)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -6137,47 +6393,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/tokens": {
+        "/v1/metric_entity_ids": {
             "get": {
-                "description": "List Tokens\nList OAuth Bearer Tokens by User ID (defaults to the Context User).",
+                "description": "List Metric Entity IDs\nList all entity IDs for which metrics exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Token"
+                    "Metric"
                 ],
-                "summary": "List Tokens",
+                "summary": "List Metric Entity IDs",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (any role)",
+                        "description": "OAuth Bearer Token (Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID or Email (defaults to the Context User)",
-                        "name": "user",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Tokens",
+                        "description": "Entity IDs",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/token.Token"
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request (invalid User ID or Email)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "401": {
                         "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
@@ -6185,7 +6429,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden (only administrators may read any user's tokens)",
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -6197,57 +6441,45 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new Token\nCreate a new OAuth Bearer Token.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/v1/metric_entity_types": {
+            "get": {
+                "description": "List Metric Entity Types\nList all entity types for which metrics exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Token"
+                    "Metric"
                 ],
-                "summary": "Create Token",
+                "summary": "List Metric Entity Types",
                 "parameters": [
                     {
-                        "description": "Token Request",
-                        "name": "TokenRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/token.Request"
-                        }
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Token Response",
+                    "200": {
+                        "description": "Entity Types",
                         "schema": {
-                            "$ref": "#/definitions/token.Response"
-                        },
-                        "headers": {
-                            "Location": {
-                                "type": "string",
-                                "description": "URL of the newly created Token"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request (invalid JSON body)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "401": {
-                        "description": "Unauthenticated (invalid username or password)",
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (user is disabled)",
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -6261,41 +6493,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/tokens/{id}": {
+        "/v1/metric_labels": {
             "get": {
-                "description": "Read Token\nRead OAuth Bearer Token by ID (e.g. to verify a token has not expired).",
+                "description": "Read Metric Labels\nRead a paginated list of Metric IDs and labels.\nThis is the preferred, more performant method for 'browsing' metrics.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Token"
+                    "Metric"
                 ],
-                "summary": "Read Token",
+                "summary": "Read Metric Labels",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (any role)",
+                        "description": "OAuth Bearer Token (Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Token ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Token",
+                        "description": "Metric ID/Label pairs",
                         "schema": {
-                            "$ref": "#/definitions/token.Token"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/versionary.TextValue"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (invalid parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -6307,13 +6553,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden (only administrators may read any user's tokens)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found (Token not found)",
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -6325,41 +6565,59 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Token\nDelete OAuth Bearer Token by ID (e.g. to log out).",
+            }
+        },
+        "/v1/metric_stats": {
+            "get": {
+                "description": "Read Metric Stats\nRead Metric Stats by entity ID, entity type, or tag.\nOptionally, filter by date range.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Token"
+                    "Metric"
                 ],
-                "summary": "Delete Token",
+                "summary": "Read Metric Stats",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (any role)",
-                        "name": "authorization",
-                        "in": "header",
-                        "required": true
+                        "description": "Entity ID",
+                        "name": "entity",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Token ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Entity Type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Inclusive Begining of Date Range (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exclusive End of Date Range (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Token that was deleted",
+                        "description": "Metric Stats",
                         "schema": {
-                            "$ref": "#/definitions/token.Token"
+                            "$ref": "#/definitions/metric.MetricStat"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (query parameter entity or type or tag must be provided)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -6371,13 +6629,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden (only administrators may delete any user's tokens)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found (Token not found)",
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -6389,124 +6641,119 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "head": {
-                "description": "Token Exists\nCheck if the specified OAuth Bearer Token exists.",
-                "tags": [
-                    "Token"
-                ],
-                "summary": "Token Exists",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Token ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "Token Exists"
-                    },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter ID)"
-                    },
-                    "404": {
-                        "description": "Not Found"
-                    }
-                }
             }
         },
-        "/v1/tuids": {
+        "/v1/metric_tags": {
             "get": {
-                "description": "Generate the specified number of TUIDs\nGenerate the specified number of TUIDs, based on the current system time.",
+                "description": "List Metric Tags\nList all metric tags, for which metrics exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "TUID"
+                    "Metric"
                 ],
-                "summary": "Generate TUIDs",
+                "summary": "List Metric Tags",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Number of TUIDs (default: 5)",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "TUIDInfo for the generated TUIDs",
+                        "description": "Metric Tags",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tuid.TUIDInfo"
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid limit",
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
-                    }
-                }
-            },
-            "post": {
-                "description": "Generate a new TUID\nGenerate a new TUID based on current system time and return the TUIDInfo.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "TUID"
-                ],
-                "summary": "Generate TUID",
-                "responses": {
-                    "201": {
-                        "description": "TUIDInfo for the generated TUID",
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
                         "schema": {
-                            "$ref": "#/definitions/tuid.TUIDInfo"
-                        },
-                        "headers": {
-                            "Location": {
-                                "type": "string",
-                                "description": "URL of the newly created TUID"
-                            }
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
                         }
                     }
                 }
             }
         },
-        "/v1/tuids/{id}": {
-            "get": {
-                "description": "Read TUIDInfo for the provided TUID\nParse the provided TUID, returning the TUIDInfo. This can be useful for extracting the timestamp from an ID.",
+        "/v1/metrics": {
+            "post": {
+                "description": "Create a new Metric\nCreate a new Metric.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "TUID"
+                    "Metric"
                 ],
-                "summary": "Read TUID Info",
+                "summary": "Create Metric",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "TUID to parse (e.g. 9GEG9f25zjGI3ath)",
-                        "name": "id",
-                        "in": "path",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "TUIDInfo for the provided TUID",
+                    "201": {
+                        "description": "Newly-created Metric",
                         "schema": {
-                            "$ref": "#/definitions/tuid.TUIDInfo"
+                            "$ref": "#/definitions/metric.Metric"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the newly created Metric"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid TUID",
+                        "description": "Bad Request (invalid JSON body)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Metric validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -6514,16 +6761,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user_emails": {
-            "get": {
-                "description": "List User Email Addresses\nGet a paginated list of email addresses for which users exist.",
+        "/v1/metrics/{id}": {
+            "delete": {
+                "description": "Delete Metric\nDelete and return the specified Metric.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Metric"
                 ],
-                "summary": "List User Email Addresses",
+                "summary": "Delete Metric",
                 "parameters": [
                     {
                         "type": "string",
@@ -6532,33 +6779,25 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (default: 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Metric ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Email Addresses",
+                        "description": "Deleted Metric",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/metric.Metric"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "401": {
@@ -6573,6 +6812,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -6580,62 +6825,45 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/user_ids": {
-            "get": {
-                "description": "List User IDs for Email Address\nList User IDs for a given email address.",
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "head": {
+                "description": "Metric Exists\nCheck if the specified Metric exists.",
                 "tags": [
-                    "User"
+                    "Metric"
                 ],
-                "summary": "List User IDs for Email Address",
+                "summary": "Metric Exists",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Email Address",
-                        "name": "email",
-                        "in": "query",
-                        "required": true
-                    }
+                        "description": "Metric ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "List of User IDs",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
-                        }
+                    "204": {
+                        "description": "Metric Exists"
                     },
                     "400": {
-                        "description": "Bad Request (invalid query parameter email)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
+                        "description": "Bad Request (invalid path parameter ID)"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
+                    "404": {
+                        "description": "Not Found"
                     }
                 }
             }
         },
-        "/v1/user_names": {
+        "/v1/organization_domains": {
             "get": {
-                "description": "List User IDs and Names\nList User IDs and Names, paging with reverse, limit, and offset.\nOptionally, filter results with search terms.",
+                "description": "Get Organization Domains\nGet an alphabetical list of the email domains verified by organizations.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "List User Names",
+                "summary": "List Organization Domains",
                 "parameters": [
                     {
                         "type": "string",
@@ -6643,60 +6871,18 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search Terms, separated by spaces",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Any Match? (default: false; all search terms must match)",
-                        "name": "any",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Sort by Name/Email? (not paginated; default: false)",
-                        "name": "sorted",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (omit for all)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User IDs and Names/Emails",
+                        "description": "Organization Domains",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/versionary.TextValue"
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request (invalid parameter)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
                     "401": {
                         "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
@@ -6718,16 +6904,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user_orgs": {
+        "/v1/organization_domains/{domain}": {
             "get": {
-                "description": "List User Organization ID/Name pairs\nGet a list of Organization ID/Name pairs for which users exist.",
+                "description": "Get Organization by Domain\nGet the Organization that verified the specified email domain.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "List User Organizations",
+                "summary": "Read Organization by Domain",
                 "parameters": [
                     {
                         "type": "string",
@@ -6736,39 +6922,19 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "boolean",
-                        "description": "Sort by Organization Name? (not paginated; default: false)",
-                        "name": "sorted",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (omit for all)",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Email Domain",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Organization ID/Name pairs",
+                        "description": "Organization",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/versionary.TextValue"
-                            }
+                            "$ref": "#/definitions/org.Organization"
                         }
                     },
                     "401": {
@@ -6783,6 +6949,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -6792,16 +6964,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user_roles": {
+        "/v1/organization_names": {
             "get": {
-                "description": "List User Roles\nGet a list of roles for which users exist.",
+                "description": "List Organization IDs and Names\nList Organization IDs and Names, paging with reverse, limit, and offset.\nOptionally, filter results with search terms.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "List User Roles",
+                "summary": "List Organization Names",
                 "parameters": [
                     {
                         "type": "string",
@@ -6809,18 +6981,60 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search Terms, separated by spaces",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Any Match? (default: false; all search terms must match)",
+                        "name": "any",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort by Name? (not paginated; default: false)",
+                        "name": "sorted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (omit for all)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User Roles",
+                        "description": "Organization IDs and Names",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "type": "string"
+                                "$ref": "#/definitions/versionary.TextValue"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request (invalid parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "401": {
                         "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
@@ -6842,16 +7056,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user_statuses": {
+        "/v1/organization_statuses": {
             "get": {
-                "description": "List User Statuses\nGet a list of status codes for which users exist.",
+                "description": "Get Organization Statuses\nGet a list of status codes for which organizations exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "List User Statuses",
+                "summary": "List Organization Statuses",
                 "parameters": [
                     {
                         "type": "string",
@@ -6863,7 +7077,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "User Statuses",
+                        "description": "Organization Statuses",
                         "schema": {
                             "type": "array",
                             "items": {
@@ -6892,16 +7106,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/users": {
+        "/v1/organizations": {
             "get": {
-                "description": "List Users\nList Users, paging with reverse, limit, and offset.\nOptionally, filter by email, organization, role, or status.",
+                "description": "List Organizations\nList Organizations, paging with reverse, limit, and offset. Optionally, filter by status.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "List Users",
+                "summary": "List Organizations",
                 "parameters": [
                     {
                         "type": "string",
@@ -6910,24 +7124,6 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "string",
-                        "description": "Email Address",
-                        "name": "email",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization ID",
-                        "name": "org",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Role (e.g. admin)",
-                        "name": "role",
-                        "in": "query"
-                    },
                     {
                         "enum": [
                             "PENDING",
@@ -6960,11 +7156,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Users",
+                        "description": "Organizations",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/user.User"
+                                "$ref": "#/definitions/org.Organization"
                             }
                         }
                     },
@@ -6995,7 +7191,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new User\nCreate a new User.",
+                "description": "Create a new Organization\nCreate a new Organization.",
                 "consumes": [
                     "application/json"
                 ],
@@ -7003,9 +7199,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "Create User",
+                "summary": "Create Organization",
                 "parameters": [
                     {
                         "type": "string",
@@ -7015,25 +7211,25 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "User",
-                        "name": "user",
+                        "description": "Organization",
+                        "name": "organization",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user.User"
+                            "$ref": "#/definitions/org.Organization"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Newly-created User",
+                        "description": "Newly-created Organization",
                         "schema": {
-                            "$ref": "#/definitions/user.User"
+                            "$ref": "#/definitions/org.Organization"
                         },
                         "headers": {
                             "Location": {
                                 "type": "string",
-                                "description": "URL of the newly created User"
+                                "description": "URL of the newly created Organization"
                             }
                         }
                     },
@@ -7056,7 +7252,7 @@ const docTemplate = `{
                         }
                     },
                     "422": {
-                        "description": "User validation errors",
+                        "description": "Organization validation errors",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7070,27 +7266,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/users/{id}": {
+        "/v1/organizations/{id}": {
             "get": {
-                "description": "Get User\nGet User by ID or email, scrubbing sensitive information if the requester is not an administrator.",
+                "description": "Get Organization\nGet Organization by ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "Read User",
+                "summary": "Read Organization",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token",
-                        "name": "authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID or Email Address",
+                        "description": "Organization ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -7098,9 +7287,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "User",
+                        "description": "Organization",
                         "schema": {
-                            "$ref": "#/definitions/user.User"
+                            "$ref": "#/definitions/org.Organization"
                         }
                     },
                     "400": {
@@ -7109,20 +7298,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "401": {
-                        "description": "Unauthenticated (missing or invalid Authorization header)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden (only administrators may read any user)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7136,7 +7313,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update User\nUpdate the provided complete User, ensuring that sensitive information is retained.",
+                "description": "Update Organization\nUpdate the provided, complete Organization.\nOrganization administrators may only rename their own organization; other changes are ignored.\nA rename is propagated to the organization's users, memberships, and invitations by a background\nJob, identified by the X-Job-ID response header.",
                 "consumes": [
                     "application/json"
                 ],
@@ -7144,29 +7321,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "Update User",
+                "summary": "Update Organization",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "description": "User",
-                        "name": "user",
+                        "description": "Organization",
+                        "name": "organization",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user.User"
+                            "$ref": "#/definitions/org.Organization"
                         }
                     },
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Organization ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -7174,9 +7351,15 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "User",
+                        "description": "Organization",
                         "schema": {
-                            "$ref": "#/definitions/user.User"
+                            "$ref": "#/definitions/org.Organization"
+                        },
+                        "headers": {
+                            "X-Job-ID": {
+                                "type": "string",
+                                "description": "ID of the Job propagating a rename, if any"
+                            }
                         }
                     },
                     "400": {
@@ -7192,13 +7375,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an Administrator, or another organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "422": {
-                        "description": "User validation errors",
+                        "description": "Organization validation errors",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7212,39 +7401,51 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete User\nDelete and return the specified User. Attempt to delete their associated Tokens as well, logging errors.",
+                "description": "Delete Organization\nDelete the specified Organization, and report what happened to each of its members.\nThe member policy is one of: refuse (the default) to refuse while members remain, reassign\nto move the members to the target Organization (dropping organization-scoped roles), or\ndisable to disable the members and revoke their tokens. Members whose primary organization\nis elsewhere just lose their membership. Open invitations to the Organization are revoked.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "Delete User",
+                "summary": "Delete Organization",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token",
+                        "description": "OAuth Bearer Token (Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Organization ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Member Policy: refuse | reassign | disable (default: refuse)",
+                        "name": "policy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target Organization ID (reassign policy only)",
+                        "name": "target",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User that was deleted",
+                        "description": "Report of the deleted Organization and its members",
                         "schema": {
-                            "$ref": "#/definitions/user.User"
+                            "$ref": "#/definitions/app.OrgDeletion"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (invalid path parameter ID, policy, or target)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7267,6 +7468,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "409": {
+                        "description": "Conflict (the Organization has child organizations, or members and the policy is refuse)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -7276,15 +7483,15 @@ const docTemplate = `{
                 }
             },
             "head": {
-                "description": "User Exists\nCheck if the specified User exists.",
+                "description": "Organization Exists\nCheck if the specified Organization exists.",
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "User Exists",
+                "summary": "Organization Exists",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Organization ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -7292,60 +7499,73 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "User Exists"
+                        "description": "Organization Exists"
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
+                        "description": "Bad Request (invalid path parameter ID)"
                     },
                     "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/main.APIEvent"
-                        }
+                        "description": "Not Found"
                     }
                 }
             }
         },
-        "/v1/users/{id}/resets": {
-            "post": {
-                "description": "Get User by ID or email\nUpdate the provided User, including a new password reset token.\nSend the password reset token to the user's email address.",
+        "/v1/organizations/{id}/ancestors": {
+            "get": {
+                "description": "List Organization Ancestors\nList the ancestors of the specified Organization, starting with its parent and ending with\nthe root of the hierarchy (e.g. for breadcrumbs). The list is empty for a root Organization.\nOrganization administrators may only list ancestors of their own organization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "Create a Password Reset Token",
+                "summary": "List Organization Ancestors",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID or Email Address",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "Ancestor Organizations",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/org.Organization"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Bad Request (invalid JSON or parameter)",
+                        "description": "Bad Request (invalid path parameter ID)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found (no user with the specified ID or email address)",
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity (invalid password reset email message)",
+                    "403": {
+                        "description": "Unauthorized (not an Administrator, or another organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7359,71 +7579,62 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/users/{id}/resets/:token_id": {
-            "put": {
-                "description": "Update the provided User with a new password hash and delete the password reset token.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/v1/organizations/{id}/children": {
+            "get": {
+                "description": "List Child Organizations\nList the Organizations whose parent is the specified Organization, sorted by ID.\nOrganization administrators may only list children of their own organization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "Reset Password",
+                "summary": "List Child Organizations",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID or Email Address",
-                        "name": "id",
-                        "in": "path",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Password Reset Token",
-                        "name": "token",
+                        "description": "Organization ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "New Password",
-                        "name": "password",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "Child Organizations",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/org.Organization"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Bad Request (invalid JSON or parameter)",
+                        "description": "Bad Request (invalid path parameter ID)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "401": {
-                        "description": "Unauthenticated (incorrect password reset token)",
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found (no user with the specified ID or email address)",
+                    "403": {
+                        "description": "Unauthorized (not an Administrator, or another organization)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "422": {
-                        "description": "Invalid password (must be at least 12 characters)",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7437,62 +7648,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/users/{id}/versions": {
-            "get": {
-                "description": "List User Versions\nGet User Versions by User ID, paging with reverse, limit, and offset.",
+        "/v1/organizations/{id}/domains": {
+            "post": {
+                "description": "Start verifying an Organization email domain\nStart verifying an email domain owned by the Organization. The response describes a DNS TXT\nrecord to publish; then verify the domain. Once verified, new Users with an email address in\nthe domain (or its subdomains) join the Organization automatically. Organization administrators\nmay only verify domains for their own organization.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "List User Versions",
+                "summary": "Create Domain Challenge",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Organization ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (default: 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Email Domain",
+                        "name": "domain",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.DomainRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "User Versions",
+                    "201": {
+                        "description": "DNS TXT record to publish",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/user.User"
-                            }
+                            "$ref": "#/definitions/org.DomainChallenge"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (invalid JSON body, path parameter, or domain)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7504,13 +7706,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an administrator of the organization)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Not Found (organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict (domain is already verified)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7524,44 +7732,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/users/{id}/versions/{versionid}": {
-            "get": {
-                "description": "Get User Version\nGet User Version by ID and VersionID, scrubbing sensitive information if the requester is not an administrator.",
+        "/v1/organizations/{id}/domains/{domain}": {
+            "delete": {
+                "description": "Remove an Organization email domain\nRemove a verified email domain (or an open domain challenge) from the Organization.\nExisting members are not affected.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "Read User Version",
+                "summary": "Remove Domain",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Organization ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "User VersionID",
-                        "name": "versionid",
+                        "description": "Email Domain",
+                        "name": "domain",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User Version",
+                        "description": "Organization, without the domain",
                         "schema": {
-                            "$ref": "#/definitions/user.User"
+                            "$ref": "#/definitions/org.Organization"
                         }
                     },
                     "400": {
@@ -7577,13 +7785,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an administrator of the organization)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Not Found (organization or domain)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7595,48 +7803,50 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete User Version\nDelete and return the specified User Version.",
+            }
+        },
+        "/v1/organizations/{id}/domains/{domain}/verify": {
+            "post": {
+                "description": "Verify an Organization email domain\nCheck the DNS TXT record of the domain challenge. If it holds the challenge value, then the\ndomain is verified, and the updated Organization is returned. A domain may be verified by only\none Organization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Organization"
                 ],
-                "summary": "Delete User Version",
+                "summary": "Verify Domain",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Organization ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "User Version ID",
-                        "name": "versionid",
+                        "description": "Email Domain",
+                        "name": "domain",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User version that was deleted",
+                        "description": "Organization, with the verified domain",
                         "schema": {
-                            "$ref": "#/definitions/user.User"
+                            "$ref": "#/definitions/org.Organization"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (invalid path parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7648,59 +7858,31 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an administrator of the organization)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Not Found (organization or domain challenge)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict (domain is verified by another organization)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
-                    }
-                }
-            },
-            "head": {
-                "description": "User Version Exists\nCheck if the specified User version exists.",
-                "tags": [
-                    "User"
-                ],
-                "summary": "User Version Exists",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User VersionID",
-                        "name": "versionid",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "User Version Exists"
                     },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter)",
+                    "422": {
+                        "description": "Unprocessable Entity (DNS TXT record not found)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7708,50 +7890,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/view_counts": {
+        "/v1/organizations/{id}/invitations": {
             "get": {
-                "description": "Get View Counts\nGet a paginated list of view counts by date.",
+                "description": "List Organization Invitations\nList the Invitations to an Organization, in chronological order, optionally filtered by status.\nOrganization administrators may only list the Invitations to their own organization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "View"
+                    "Invitation"
                 ],
-                "summary": "List View Counts",
+                "summary": "List Invitations",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (default: 100)",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
+                        "enum": [
+                            "INVITED",
+                            "ACCEPTED",
+                            "REVOKED"
+                        ],
                         "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
+                        "description": "Status",
+                        "name": "status",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "View Counts",
+                        "description": "Invitations",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/view.Count"
+                                "$ref": "#/definitions/org.Invitation"
                             }
                         }
                     },
@@ -7768,7 +7950,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an administrator of the organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found (organization)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7780,43 +7968,59 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/view_counts/{date}": {
-            "get": {
-                "description": "Get View Count\nGet the number of views encountered on the specified date.",
+            },
+            "post": {
+                "description": "Invite a person to join an Organization\nInvite a person, by email address, to join the Organization with the specified roles.\nAn email message is sent with a link for accepting the invitation, which expires in 7 days\nby default. Organization administrators may only invite people to their own organization.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "View"
+                    "Invitation"
                 ],
-                "summary": "Read View Count",
+                "summary": "Create Invitation",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Date (YYYY-MM-DD)",
-                        "name": "date",
+                        "description": "Organization ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Invitation",
+                        "name": "invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.InvitationRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "View Count",
+                    "201": {
+                        "description": "Newly-created Invitation",
                         "schema": {
-                            "$ref": "#/definitions/view.Count"
+                            "$ref": "#/definitions/org.Invitation"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the organization's Invitations"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter date)",
+                        "description": "Bad Request (invalid JSON body or path parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7828,13 +8032,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an administrator of the organization, or role may not be granted)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Not Found (organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Invitation validation errors",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7846,41 +8056,50 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update View Count\nUpdate the number of views encountered on the specified date.",
+            }
+        },
+        "/v1/organizations/{id}/invitations/{invitation_id}": {
+            "delete": {
+                "description": "Revoke an Organization Invitation\nRevoke an open Invitation, so that it may no longer be accepted.\nOrganization administrators may only revoke the Invitations to their own organization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "View"
+                    "Invitation"
                 ],
-                "summary": "Update View Count",
+                "summary": "Revoke Invitation",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Date (YYYY-MM-DD)",
-                        "name": "date",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invitation ID",
+                        "name": "invitation_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "View Count",
+                        "description": "Revoked Invitation",
                         "schema": {
-                            "$ref": "#/definitions/view.Count"
+                            "$ref": "#/definitions/org.Invitation"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter date)",
+                        "description": "Bad Request (invalid path parameter)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7892,76 +8111,74 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an administrator of the organization)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
-                    }
-                }
-            },
-            "head": {
-                "description": "View Count Exists\nCheck if the specified View Count exists.",
-                "tags": [
-                    "View"
-                ],
-                "summary": "View Count Exists",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Date (YYYY-MM-DD)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "View Exists"
                     },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter date)"
+                    "409": {
+                        "description": "Conflict (invitation was already accepted or revoked)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
                     }
                 }
             }
         },
-        "/v1/view_dates": {
+        "/v1/organizations/{id}/jobs": {
             "get": {
-                "description": "Get View Dates\nGet a list of dates for which views exist.",
+                "description": "List Organization Jobs\nList the recent background Jobs about an Organization, in chronological order.\nOrganization administrators may only list the Jobs about their own organization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "View"
+                    "Job"
                 ],
-                "summary": "List View Dates",
+                "summary": "List Organization Jobs",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "View Dates",
+                        "description": "Jobs",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "type": "string"
+                                "$ref": "#/definitions/job.Job"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "401": {
                         "description": "Unauthenticated (missing or invalid Authorization header)",
                         "schema": {
@@ -7969,7 +8186,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an administrator of the organization)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -7983,55 +8200,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/view_device_ids": {
+        "/v1/organizations/{id}/subtree": {
             "get": {
-                "description": "Get View Device IDs\nGet a list of Device IDs for which views exist.",
+                "description": "Read Organization Subtree\nRead the specified Organization and all of its descendants, as a tree of nested children.\nOrganization administrators may only read the subtree of their own organization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "View"
+                    "Organization"
                 ],
-                "summary": "List View Devices",
+                "summary": "Read Organization Subtree",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth Bearer Token (Administrator)",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
                         "name": "authorization",
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "boolean",
-                        "description": "Reverse Order (default: false)",
-                        "name": "reverse",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (default: 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Offset (default: forward/reverse alphanumeric)",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Device IDs",
+                        "description": "Organization Subtree",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/org.Tree"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid parameter)",
+                        "description": "Bad Request (invalid path parameter ID)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -8043,7 +8246,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Unauthorized (not an Administrator)",
+                        "description": "Unauthorized (not an Administrator, or another organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -8057,16 +8266,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/views": {
+        "/v1/organizations/{id}/versions": {
             "get": {
-                "description": "List Views\nList Views, paging with reverse, limit, and offset. Optionally, filter by DeviceID or Date.",
+                "description": "Get Organization Versions\nGet Organization Versions by ID, paging with reverse, limit, and offset.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "View"
+                    "Organization"
                 ],
-                "summary": "List Views",
+                "summary": "Read Organization Versions",
                 "parameters": [
                     {
                         "type": "string",
@@ -8077,15 +8286,10 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Device ID (optional, TUID)",
-                        "name": "device",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Date (optional, YYYY-MM-DD)",
-                        "name": "date",
-                        "in": "query"
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "boolean",
@@ -8108,11 +8312,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Views",
+                        "description": "Organization Versions",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/view.View"
+                                "$ref": "#/definitions/org.Organization"
                             }
                         }
                     },
@@ -8134,50 +8338,62 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new View\nCreate a new View with an associated new/updated Device.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/v1/organizations/{id}/versions/{versionid}": {
+            "get": {
+                "description": "Get Organization Version\nGet Organization Version by ID and VersionID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "View"
+                    "Organization"
                 ],
-                "summary": "Create View",
+                "summary": "Read Organization Version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User-Agent Header",
-                        "name": "user-agent",
-                        "in": "header",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization VersionID",
+                        "name": "versionid",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Newly-created View",
+                    "200": {
+                        "description": "Organization Version",
                         "schema": {
-                            "$ref": "#/definitions/view.View"
-                        },
-                        "headers": {
-                            "Location": {
-                                "type": "string",
-                                "description": "/v1/views/{id}"
-                            }
+                            "$ref": "#/definitions/org.Organization"
                         }
                     },
-                    "422": {
-                        "description": "View validation errors",
+                    "400": {
+                        "description": "Bad Request (invalid path parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -8189,32 +8405,44 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/views/{id}": {
-            "get": {
-                "description": "Get View\nGet View by ID.",
+            },
+            "delete": {
+                "description": "Delete Organization Version\nDelete and return the specified Organization version.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "View"
+                    "Organization"
                 ],
-                "summary": "Read View",
+                "summary": "Delete Organization Version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "View ID",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization Version ID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "View",
+                        "description": "Organization version that was deleted",
                         "schema": {
-                            "$ref": "#/definitions/view.View"
+                            "$ref": "#/definitions/org.Organization"
                         }
                     },
                     "400": {
@@ -8223,6 +8451,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -8237,15 +8477,54 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete View\nDelete and return the specified View.",
+            "head": {
+                "description": "Organization Version Exists\nCheck if the specified Organization version exists.",
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Organization Version Exists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Organization Version Exists"
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter)"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    }
+                }
+            }
+        },
+        "/v1/organizations/{id}/versions/{versionid}/restore": {
+            "post": {
+                "description": "Restore Organization Version\nRestore a prior version of the specified Organization, saving it as a new version. Email domains\nchange only by verification, so they're not restored. A restored name is propagated to the\nmembers, Memberships, and Invitations in a background Job, identified by the X-Job-ID header.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "View"
+                    "Organization"
                 ],
-                "summary": "Delete View",
+                "summary": "Restore Organization Version",
                 "parameters": [
                     {
                         "type": "string",
@@ -8256,21 +8535,42 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "View ID",
+                        "description": "Organization ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for restoring the version (optional)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/main.RestoreVersionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "View that was deleted",
+                        "description": "Restored Organization",
                         "schema": {
-                            "$ref": "#/definitions/view.View"
+                            "$ref": "#/definitions/app.Restoration"
+                        },
+                        "headers": {
+                            "X-Job-ID": {
+                                "type": "string",
+                                "description": "ID of the Job propagating a restored name"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Bad Request (invalid path parameter ID)",
+                        "description": "Bad Request (invalid parameter or JSON body)",
                         "schema": {
                             "$ref": "#/definitions/main.APIEvent"
                         }
@@ -8293,6 +8593,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.APIEvent"
                         }
                     },
+                    "409": {
+                        "description": "Conflict (the version is already current)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Organization validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -8300,418 +8612,5289 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "head": {
-                "description": "View Exists\nCheck if the specified View exists.",
+            }
+        },
+        "/v1/roles": {
+            "get": {
+                "description": "List Roles\nList the stored Roles, followed by any built-in Roles (without an ID) that have not been redefined.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "View"
+                    "Role"
                 ],
-                "summary": "View Exists",
+                "summary": "List Roles",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "View ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "View Exists"
+                    "200": {
+                        "description": "Roles",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/role.Role"
+                            }
+                        }
                     },
-                    "400": {
-                        "description": "Bad Request (invalid path parameter ID)"
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "403": {
+                        "description": "Unauthorized (missing role:read permission)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "app.About": {
-            "type": "object",
-            "properties": {
-                "baseDomain": {
-                    "type": "string"
-                },
-                "buildTime": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "environment": {
-                    "type": "string"
-                },
-                "gitHash": {
-                    "type": "string"
-                },
-                "language": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "bucket.PreSignedURL": {
-            "type": "object",
-            "properties": {
-                "bucketName": {
+            },
+            "post": {
+                "description": "Create a new Role\nCreate a new Role, granting permissions (e.g. \"content:write\") to each User with the role name.\nA Role with the same name as a built-in role (e.g. \"org_admin\") redefines it. The admin role, and its \"*\" permission, are reserved.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Create Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/role.Role"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Newly-created Role",
+                        "schema": {
+                            "$ref": "#/definitions/role.Role"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the newly created Role"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid JSON body)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (missing role:write permission)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict (role name is already in use)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Role validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/roles/{id}": {
+            "get": {
+                "description": "Get Role\nGet Role by ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Read Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role",
+                        "schema": {
+                            "$ref": "#/definitions/role.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (missing role:read permission)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update Role\nUpdate the provided, complete Role. Changes take effect for all Users with the role name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Update Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/role.Role"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role",
+                        "schema": {
+                            "$ref": "#/definitions/role.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid JSON or parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (missing role:write permission)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict (role name is already in use)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Role validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete Role\nDelete and return the specified Role. A deleted built-in role reverts to its default permissions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Delete Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role that was deleted",
+                        "schema": {
+                            "$ref": "#/definitions/role.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (missing role:write permission)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/token_ids": {
+            "get": {
+                "description": "List Token/User ID pairs\nList Token/User ID pairs. This is useful for paging through tokens.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token"
+                ],
+                "summary": "List Token/User ID Pairs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort by User ID? (not paginated; default: false)",
+                        "name": "sorted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: all)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token/User ID pairs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/versionary.TextValue"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid query parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/token_users": {
+            "get": {
+                "description": "List Users for which tokens exist\nList User IDs and email addresses for which tokens exist.\nThis is useful for paging through tokens by user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token"
+                ],
+                "summary": "List Token Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search Terms, separated by spaces",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Any Match? (default: false; all search terms must match)",
+                        "name": "any",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort by Email? (not paginated; default: false)",
+                        "name": "sorted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: all)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User IDs and Email Addresses",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/versionary.TextValue"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid query parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/tokens": {
+            "get": {
+                "description": "List Tokens\nList OAuth Bearer Tokens by User ID (defaults to the Context User).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token"
+                ],
+                "summary": "List Tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (any role)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID or Email (defaults to the Context User)",
+                        "name": "user",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tokens",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/token.Token"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid User ID or Email)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (only administrators may read any user's tokens)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new Token\nCreate a new OAuth Bearer Token, using either the \"password\" grant (default)\nor the \"refresh_token\" grant. The response includes a replacement RefreshToken.\nReusing a RefreshToken revokes all Tokens descended from the same password grant.\nUsers enrolled in TOTP must also supply a code (TOTP or recovery code) with the password grant.\nMembers of several organizations may select the active organization with the orgId field.\nFor a standards-compliant OAuth 2.0 token endpoint, see /oauth/token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token"
+                ],
+                "summary": "Create Token",
+                "parameters": [
+                    {
+                        "description": "Token Request",
+                        "name": "TokenRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/token.Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Token Response",
+                        "schema": {
+                            "$ref": "#/definitions/token.Response"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the newly created Token"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid JSON body or unsupported grant type)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (invalid username, password, second factor code, or refresh token)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (user is disabled, email address is not verified, or not a member of the organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests (too many failed login attempts; see Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/tokens/{id}": {
+            "get": {
+                "description": "Read Token\nRead OAuth Bearer Token by ID (e.g. to verify a token has not expired).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token"
+                ],
+                "summary": "Read Token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (any role)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token",
+                        "schema": {
+                            "$ref": "#/definitions/token.Token"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (only administrators may read any user's tokens)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found (Token not found)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete Token\nDelete OAuth Bearer Token by ID (e.g. to log out).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token"
+                ],
+                "summary": "Delete Token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (any role)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token that was deleted",
+                        "schema": {
+                            "$ref": "#/definitions/token.Token"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (only administrators may delete any user's tokens)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found (Token not found)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Token Exists\nCheck if the specified OAuth Bearer Token exists.",
+                "tags": [
+                    "Token"
+                ],
+                "summary": "Token Exists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Token Exists"
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    }
+                }
+            }
+        },
+        "/v1/tuids": {
+            "get": {
+                "description": "Generate the specified number of TUIDs\nGenerate the specified number of TUIDs, based on the current system time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TUID"
+                ],
+                "summary": "Generate TUIDs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of TUIDs (default: 5)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "TUIDInfo for the generated TUIDs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tuid.TUIDInfo"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Generate a new TUID\nGenerate a new TUID based on current system time and return the TUIDInfo.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TUID"
+                ],
+                "summary": "Generate TUID",
+                "responses": {
+                    "201": {
+                        "description": "TUIDInfo for the generated TUID",
+                        "schema": {
+                            "$ref": "#/definitions/tuid.TUIDInfo"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the newly created TUID"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/tuids/{id}": {
+            "get": {
+                "description": "Read TUIDInfo for the provided TUID\nParse the provided TUID, returning the TUIDInfo. This can be useful for extracting the timestamp from an ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TUID"
+                ],
+                "summary": "Read TUID Info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "TUID to parse (e.g. 9GEG9f25zjGI3ath)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "TUIDInfo for the provided TUID",
+                        "schema": {
+                            "$ref": "#/definitions/tuid.TUIDInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid TUID",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user_emails": {
+            "get": {
+                "description": "List User Email Addresses\nGet a paginated list of email addresses for which users exist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List User Email Addresses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email Addresses",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user_ids": {
+            "get": {
+                "description": "List User IDs for Email Address\nList User IDs for a given email address.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List User IDs for Email Address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email Address",
+                        "name": "email",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of User IDs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid query parameter email)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user_names": {
+            "get": {
+                "description": "List User IDs and Names\nList User IDs and Names, paging with reverse, limit, and offset.\nOptionally, filter results with search terms.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List User Names",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search Terms, separated by spaces",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Any Match? (default: false; all search terms must match)",
+                        "name": "any",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort by Name/Email? (not paginated; default: false)",
+                        "name": "sorted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (omit for all)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User IDs and Names/Emails",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/versionary.TextValue"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user_orgs": {
+            "get": {
+                "description": "List User Organization ID/Name pairs\nGet a list of Organization ID/Name pairs for which users exist, including members\nwhose primary organization is elsewhere.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List User Organizations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort by Organization Name? (not paginated; default: false)",
+                        "name": "sorted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (omit for all)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Organization ID/Name pairs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/versionary.TextValue"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user_roles": {
+            "get": {
+                "description": "List User Roles\nGet a list of roles for which users exist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List User Roles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Roles",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user_statuses": {
+            "get": {
+                "description": "List User Statuses\nGet a list of status codes for which users exist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List User Statuses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Statuses",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users": {
+            "get": {
+                "description": "List Users\nList Users, paging with reverse, limit, and offset.\nOptionally, filter by email, organization, role, or status. With descendants, the Users\nof the organization's descendants (e.g. divisions) are included.\nOrganization administrators may only list the Users in their own organization.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email Address",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "org",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include descendant Organizations (default: false)",
+                        "name": "descendants",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role (e.g. admin)",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "PENDING",
+                            "ENABLED",
+                            "DISABLED"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/user.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator, or another organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new User\nCreate a new User. Organization administrators may only create Users in their own organization,\nand may only grant organization-scoped roles. A User without an organization joins the organization that\nverified their email domain, if any, once their email address is verified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Create User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Newly-created User",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the newly created User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid JSON body)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator, or not an administrator of the organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "User validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}": {
+            "get": {
+                "description": "Get User\nGet User by ID or email, scrubbing sensitive information if the requester is not an administrator.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Read User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID or Email Address",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (only administrators may read any user, or organization administrators their organization's users)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update User\nUpdate the provided complete User, ensuring that sensitive information is retained.\nOrganization administrators may update the Users in their own organization, including their\nroles and status, but may not move them to another organization or grant roles that are not organization-scoped.\nDisabling a User or changing their password revokes all of their Tokens.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid JSON or parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator, or not in the organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "User validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete User\nDelete and return the specified User. Attempt to delete their associated Tokens as well, logging errors.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User that was deleted",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "User Exists\nCheck if the specified User exists.",
+                "tags": [
+                    "User"
+                ],
+                "summary": "User Exists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User Exists"
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/lockout": {
+            "get": {
+                "description": "Read User Lockout\nRead the failed login attempts recorded for the specified User, and any temporary lockout.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Read User Lockout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lockout",
+                        "schema": {
+                            "$ref": "#/definitions/user.Lockout"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found (no failed login attempts)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Unlock User\nClear the failed login attempts recorded for the specified User, ending any temporary lockout.\nOptionally, clear the failed login attempts recorded for a client IP address as well.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Unlock User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client IP Address",
+                        "name": "ip",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lockouts that were cleared",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/user.Lockout"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found (no failed login attempts)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/memberships": {
+            "get": {
+                "description": "List User Organization Memberships\nList the Organizations to which the specified User belongs, with their roles in each, sorted by\nOrganization ID. The primary Membership reflects the User's own Organization and roles.\nUsers may list their own Memberships; administrators may list any User's Memberships.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List User Memberships",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Memberships",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/user.Membership"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not the specified User or an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/memberships/{org_id}": {
+            "put": {
+                "description": "Add a User to an Organization\nAdd the specified User to an Organization, in addition to their primary Organization, or replace\ntheir roles within it. The roles apply only within the Organization. Organization administrators\nmay only manage Memberships in their own organization, and may only add Users who already belong\nto it (or its descendants); other Users must accept an Invitation. The User's primary Membership\nis changed by updating the User.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update User Membership",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator or Organization Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "org_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Roles within the Organization",
+                        "name": "membership",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.MembershipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated Membership",
+                        "schema": {
+                            "$ref": "#/definitions/user.Membership"
+                        }
+                    },
+                    "201": {
+                        "description": "Newly-created Membership",
+                        "schema": {
+                            "$ref": "#/definitions/user.Membership"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the User's Memberships"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid JSON body or path parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an administrator of the organization, role may not be granted, or user must be invited)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found (user or organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict (primary organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Membership validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a User from an Organization\nRemove the specified User from an Organization other than their primary Organization.\nUsers may leave an Organization; administrators may remove a User from their own organization.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete User Membership",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "org_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deleted Membership",
+                        "schema": {
+                            "$ref": "#/definitions/user.Membership"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not the specified User or an administrator of the organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict (primary organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/permissions": {
+            "get": {
+                "description": "Get User Permissions\nGet the effective permissions of the specified User, granted by their Roles.\nGlobal permissions apply everywhere; org permissions apply only within the User's Organization.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Read User Permissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Permissions",
+                        "schema": {
+                            "$ref": "#/definitions/role.Permissions"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (missing role:read permission)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/resets": {
+            "post": {
+                "description": "Get User by ID or email\nUpdate the provided User with the hash of a new password reset token, which expires in an hour.\nSend a password reset link, containing the token, to the user's email address.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Create a Password Reset Token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID or Email Address",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid JSON or parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found (no user with the specified ID or email address)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity (invalid password reset email message)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/resets/{token_id}": {
+            "put": {
+                "description": "Update the provided User with a new password hash and delete the password reset token.\nReset tokens expire, and may be used only once. All of the User's bearer tokens are revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Reset Password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID or Email Address",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password Reset Token",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New Password",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid JSON or parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (incorrect or expired password reset token)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found (no user with the specified ID or email address)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid password (must be at least 12 characters)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/sessions": {
+            "get": {
+                "description": "List User Sessions\nList the active sessions (unexpired access Tokens) of the specified User, including the Device\n(parsed User-Agent) and client IP address that requested each Token, and when it was last used.\nUsers may list their own sessions; administrators may list any User's sessions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List User Sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sessions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/token.Session"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not the specified User or an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Revoke Other User Sessions\nSign the specified User out everywhere else, revoking all of their access Tokens and RefreshTokens,\nexcept for the session making the request (if it belongs to the User).\nUsers may revoke their own sessions; administrators may revoke any User's sessions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Revoke Other User Sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "IDs of revoked Tokens",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not the specified User or an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/totp": {
+            "put": {
+                "description": "Confirm TOTP\nVerify a TOTP code for the specified User's pending enrollment (self only), enabling the second factor.\nReturns single-use recovery codes, which are displayed only once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Confirm TOTP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "TOTP Code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.TOTPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recovery Codes",
+                        "schema": {
+                            "$ref": "#/definitions/main.TOTPRecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid JSON or parameter, or not enrolled)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (users may only confirm their own enrollment)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity (invalid TOTP code)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Enroll TOTP\nGenerate a new TOTP secret for the specified User (self only), returning the secret\nand an otpauth:// key URI. The enrollment must be confirmed with a valid TOTP code.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Enroll TOTP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "TOTP Enrollment",
+                        "schema": {
+                            "$ref": "#/definitions/main.TOTPEnrollment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (users may only enroll themselves)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict (TOTP is already enabled)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Disable TOTP\nRemove the specified User's second factor (self only). If TOTP is enabled,\na valid TOTP code or recovery code is required.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Disable TOTP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "TOTP or Recovery Code",
+                        "name": "code",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/main.TOTPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid JSON or parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (users may only disable their own second factor)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity (missing or invalid code)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/totp/resets": {
+            "post": {
+                "description": "Reset TOTP\nRemove the specified User's second factor without a code (e.g. for a locked-out User).",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Reset TOTP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/verification": {
+            "get": {
+                "description": "Verify Email Address\nVerify the User's email address with the signed token from an email verification link.\nPENDING Users are ENABLED. The token expires, and is invalidated if the email address changes.\nA User without an organization joins the organization that verified their email domain, if any.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Verify Email Address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email Verification Token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Verified User",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (invalid or expired token)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Resend Email Verification\nSend a new email verification link to the User's email address. Messages are throttled:\nat most one per minute, and five per day.",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Resend Email Verification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID or Email Address",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found (no user with the specified ID or email address)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict (email address is already verified)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests (see Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/versions": {
+            "get": {
+                "description": "List User Versions\nGet User Versions by User ID, paging with reverse, limit, and offset, scrubbing sensitive information.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List User Versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Versions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/user.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/versions/{versionid}": {
+            "get": {
+                "description": "Get User Version\nGet User Version by ID and VersionID, scrubbing sensitive information if the requester is not an administrator.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Read User Version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Version",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator, or not in the organization)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete User Version\nDelete and return the specified User Version.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete User Version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User Version ID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User version that was deleted",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "User Version Exists\nCheck if the specified User version exists.",
+                "tags": [
+                    "User"
+                ],
+                "summary": "User Version Exists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User Version Exists"
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/versions/{versionid}/restore": {
+            "post": {
+                "description": "Restore User Version\nRestore a prior version of the specified User, saving it as a new version. Credentials are never\nrolled back: the current password hash, password reset, TOTP secret, and recovery codes are kept,\nalong with the verified email address. The Organization name is refreshed, and a User who is\ndisabled by the restored version is signed out. Restoring roles that the requester may not grant\n(or an Organization in which they may not manage Users) is refused, as it is for an update.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Restore User Version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User VersionID",
+                        "name": "versionid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for restoring the version (optional)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/main.RestoreVersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored User",
+                        "schema": {
+                            "$ref": "#/definitions/app.Restoration"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid parameter or JSON body)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator, or may not grant the restored roles)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict (the version is already current, or its email address is in use)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "422": {
+                        "description": "User validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/view_counts": {
+            "get": {
+                "description": "Get View Counts\nGet a paginated list of view counts by date.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "View"
+                ],
+                "summary": "List View Counts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "View Counts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/view.Count"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/view_counts/{date}": {
+            "get": {
+                "description": "Get View Count\nGet the number of views encountered on the specified date.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "View"
+                ],
+                "summary": "Read View Count",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "View Count",
+                        "schema": {
+                            "$ref": "#/definitions/view.Count"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter date)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update View Count\nUpdate the number of views encountered on the specified date.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "View"
+                ],
+                "summary": "Update View Count",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "View Count",
+                        "schema": {
+                            "$ref": "#/definitions/view.Count"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter date)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "View Count Exists\nCheck if the specified View Count exists.",
+                "tags": [
+                    "View"
+                ],
+                "summary": "View Count Exists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "View Exists"
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter date)"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    }
+                }
+            }
+        },
+        "/v1/view_dates": {
+            "get": {
+                "description": "Get View Dates\nGet a list of dates for which views exist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "View"
+                ],
+                "summary": "List View Dates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "View Dates",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/view_device_ids": {
+            "get": {
+                "description": "Get View Device IDs\nGet a list of Device IDs for which views exist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "View"
+                ],
+                "summary": "List View Devices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Device IDs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/views": {
+            "get": {
+                "description": "List Views\nList Views, paging with reverse, limit, and offset. Optionally, filter by DeviceID or Date.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "View"
+                ],
+                "summary": "List Views",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device ID (optional, TUID)",
+                        "name": "device",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (optional, YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse Order (default: false)",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset (default: forward/reverse alphanumeric)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Views",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/view.View"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid parameter)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new View\nCreate a new View with an associated new/updated Device.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "View"
+                ],
+                "summary": "Create View",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User-Agent Header",
+                        "name": "user-agent",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Newly-created View",
+                        "schema": {
+                            "$ref": "#/definitions/view.View"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "/v1/views/{id}"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "View validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/views/{id}": {
+            "get": {
+                "description": "Get View\nGet View by ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "View"
+                ],
+                "summary": "Read View",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "View ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "View",
+                        "schema": {
+                            "$ref": "#/definitions/view.View"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete View\nDelete and return the specified View.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "View"
+                ],
+                "summary": "Delete View",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth Bearer Token (Administrator)",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "View ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "View that was deleted",
+                        "schema": {
+                            "$ref": "#/definitions/view.View"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated (missing or invalid Authorization header)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized (not an Administrator)",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.APIEvent"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "View Exists\nCheck if the specified View exists.",
+                "tags": [
+                    "View"
+                ],
+                "summary": "View Exists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "View ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "View Exists"
+                    },
+                    "400": {
+                        "description": "Bad Request (invalid path parameter ID)"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apikey.APIKey": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secretHash": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/apikey.Status"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "apikey.Status": {
+            "type": "string",
+            "enum": [
+                "ENABLED",
+                "REVOKED"
+            ],
+            "x-enum-varnames": [
+                "ENABLED",
+                "REVOKED"
+            ]
+        },
+        "app.About": {
+            "type": "object",
+            "properties": {
+                "baseDomain": {
+                    "type": "string"
+                },
+                "buildTime": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "environment": {
+                    "type": "string"
+                },
+                "gitHash": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.MemberPolicy": {
+            "type": "string",
+            "enum": [
+                "refuse",
+                "reassign",
+                "disable"
+            ],
+            "x-enum-varnames": [
+                "RefuseMembers",
+                "ReassignMembers",
+                "DisableMembers"
+            ]
+        },
+        "app.MemberResult": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "orgId": {
+                    "description": "the member's new primary Organization, if reassigned",
+                    "type": "string"
+                },
+                "primary": {
+                    "type": "boolean"
+                },
+                "removedRoles": {
+                    "description": "organization-scoped roles dropped when reassigned",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.OrgDeletion": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "boolean"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.MemberResult"
+                    }
+                },
+                "organization": {
+                    "$ref": "#/definitions/org.Organization"
+                },
+                "policy": {
+                    "$ref": "#/definitions/app.MemberPolicy"
+                },
+                "revokedInvitations": {
+                    "type": "integer"
+                },
+                "targetOrgId": {
+                    "type": "string"
+                },
+                "targetOrgName": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.Restoration": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "entity": {},
+                "entityType": {
+                    "type": "string"
+                },
+                "fromVersionId": {
+                    "description": "the prior version that was restored",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "jobId": {
+                    "description": "a Job started to finish the restoration, if any",
+                    "type": "string"
+                },
+                "priorVersionId": {
+                    "description": "the version that was current before restoring",
+                    "type": "string"
+                },
+                "versionId": {
+                    "description": "the new version",
+                    "type": "string"
+                }
+            }
+        },
+        "bucket.PreSignedURL": {
+            "type": "object",
+            "properties": {
+                "bucketName": {
+                    "type": "string"
+                },
+                "contentLength": {
+                    "type": "integer"
+                },
+                "contentType": {
+                    "type": "string"
+                },
+                "etag": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "fileName": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "content.Author": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "content.Change": {
+            "type": "string",
+            "enum": [
+                "ADDED",
+                "REMOVED",
+                "MOVED",
+                "MODIFIED"
+            ],
+            "x-enum-varnames": [
+                "ADDED",
+                "REMOVED",
+                "MOVED",
+                "MODIFIED"
+            ]
+        },
+        "content.Content": {
+            "type": "object",
+            "properties": {
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.Author"
+                    }
+                },
+                "body": {
+                    "$ref": "#/definitions/content.Section"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "editorId": {
+                    "type": "string"
+                },
+                "editorName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imageCount": {
+                    "type": "integer"
+                },
+                "linkCount": {
+                    "type": "integer"
+                },
+                "publishAt": {
+                    "description": "embargo: not publicly visible before this time",
+                    "type": "string"
+                },
+                "publishedAt": {
+                    "description": "when the version went live, if it has",
+                    "type": "string"
+                },
+                "sectionCount": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "description": "unique per Type; generated from the title if empty",
+                    "type": "string"
+                },
+                "slugAliases": {
+                    "description": "prior slugs, redirected to the current slug",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/content.Status"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "$ref": "#/definitions/content.Type"
+                },
+                "unpublishAt": {
+                    "description": "expiry: not publicly visible after this time",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "versionId": {
+                    "type": "string"
+                },
+                "wordCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "content.Diff": {
+            "type": "object",
+            "properties": {
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.ItemChange"
+                    }
+                },
+                "contentId": {
+                    "type": "string"
+                },
+                "from": {
+                    "$ref": "#/definitions/content.VersionInfo"
+                },
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.SectionDiff"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/content.DiffSummary"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.ItemChange"
+                    }
+                },
+                "to": {
+                    "$ref": "#/definitions/content.VersionInfo"
+                }
+            }
+        },
+        "content.DiffSummary": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "integer"
+                },
+                "modified": {
+                    "type": "integer"
+                },
+                "moved": {
+                    "type": "integer"
+                },
+                "removed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "content.EditOp": {
+            "type": "string",
+            "enum": [
+                "EQUAL",
+                "INSERT",
+                "DELETE"
+            ],
+            "x-enum-varnames": [
+                "EQUAL",
+                "INSERT",
+                "DELETE"
+            ]
+        },
+        "content.ItemChange": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "$ref": "#/definitions/content.Change"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "content.Link": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description is a brief rich text (HTML) description of the destination (optional)",
+                    "type": "string"
+                },
+                "entityId": {
+                    "description": "EntityID (a TUID) of the destination resource (optional)",
+                    "type": "string"
+                },
+                "entityType": {
+                    "description": "EntityType of the destination resource (optional)",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is used for client application state management",
+                    "type": "string"
+                },
+                "images": {
+                    "description": "Image(s) for the link destination (optional). Thumbnail images of different sizes may be provided.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/image.Image"
+                    }
+                },
+                "shortTitle": {
+                    "description": "ShortTitle (anchor tag body), useful for portrait view (optional)",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "Title (anchor tag body), useful for landscape view (required)",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL (anchor tag href), may be relative or absolute (required)",
+                    "type": "string"
+                }
+            }
+        },
+        "content.SearchHit": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "snippets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.Snippet"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/content.Status"
+                },
+                "title": {
+                    "description": "plain text",
+                    "type": "string"
+                },
+                "titleHtml": {
+                    "description": "HTML, with matching words highlighted",
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/content.Type"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "versionId": {
+                    "type": "string"
+                }
+            }
+        },
+        "content.SearchResults": {
+            "type": "object",
+            "properties": {
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.SearchHit"
+                    }
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "the number of hits on all pages",
+                    "type": "integer"
+                }
+            }
+        },
+        "content.Section": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Section ID used for client application state management",
+                    "type": "string"
+                },
+                "images": {
+                    "description": "Section images (optional)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/image.Image"
+                    }
+                },
+                "links": {
+                    "description": "Section links (optional)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.Link"
+                    }
+                },
+                "sections": {
+                    "description": "Nested subsections (optional)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.Section"
+                    }
+                },
+                "subtitle": {
+                    "description": "Section subtitle (subheading; optional)",
+                    "type": "string"
+                },
+                "text": {
+                    "description": "Section text (HTML content; optional)",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "Section title (heading; optional)",
+                    "type": "string"
+                }
+            }
+        },
+        "content.SectionDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.Change"
+                    }
+                },
+                "fromParentId": {
+                    "type": "string"
+                },
+                "fromPath": {
+                    "type": "string"
+                },
+                "heading": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.ItemChange"
+                    }
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.ItemChange"
+                    }
+                },
+                "sectionId": {
+                    "type": "string"
+                },
+                "subtitle": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.TextEdit"
+                    }
+                },
+                "text": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.TextEdit"
+                    }
+                },
+                "title": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/content.TextEdit"
+                    }
+                },
+                "toParentId": {
+                    "type": "string"
+                },
+                "toPath": {
+                    "type": "string"
+                }
+            }
+        },
+        "content.Snippet": {
+            "type": "object",
+            "properties": {
+                "heading": {
+                    "description": "the title of the section containing the text",
+                    "type": "string"
+                },
+                "html": {
+                    "type": "string"
+                },
+                "sectionId": {
+                    "type": "string"
+                }
+            }
+        },
+        "content.Status": {
+            "type": "string",
+            "enum": [
+                "DRAFT",
+                "IN_REVIEW",
+                "PUBLISHED",
+                "ARCHIVED"
+            ],
+            "x-enum-varnames": [
+                "DRAFT",
+                "IN_REVIEW",
+                "PUBLISHED",
+                "ARCHIVED"
+            ]
+        },
+        "content.TextEdit": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "$ref": "#/definitions/content.EditOp"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "content.Type": {
+            "type": "string",
+            "enum": [
+                "BOOK",
+                "CHAPTER",
+                "ARTICLE",
+                "CATEGORY"
+            ],
+            "x-enum-varnames": [
+                "BOOK",
+                "CHAPTER",
+                "ARTICLE",
+                "CATEGORY"
+            ]
+        },
+        "content.VersionInfo": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "editorName": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/content.Status"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "versionId": {
+                    "type": "string"
+                },
+                "wordCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "device.Count": {
+            "type": "object",
+            "properties": {
+                "clientNames": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "clientTypes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "deviceTypes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "osNames": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "device.Device": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastSeen": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "$ref": "#/definitions/user_agent.UserAgent"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "versionID": {
+                    "type": "string"
+                }
+            }
+        },
+        "email.Email": {
+            "type": "object",
+            "properties": {
+                "bcc": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/email.Identity"
+                    }
+                },
+                "bodyHTML": {
+                    "type": "string"
+                },
+                "bodyText": {
+                    "type": "string"
+                },
+                "cc": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/email.Identity"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "eventMessage": {
+                    "type": "string"
+                },
+                "from": {
+                    "$ref": "#/definitions/email.Identity"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/email.Status"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/email.Identity"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "versionID": {
+                    "type": "string"
+                }
+            }
+        },
+        "email.Identity": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Email address of the person/identity",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Full name of the person/identity",
+                    "type": "string"
+                }
+            }
+        },
+        "email.Status": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "SENT",
+                "UNSENT",
+                "ERROR"
+            ],
+            "x-enum-varnames": [
+                "PENDING",
+                "SENT",
+                "UNSENT",
+                "ERROR"
+            ]
+        },
+        "event.Event": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "entityId": {
+                    "type": "string"
+                },
+                "entityType": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "logLevel": {
+                    "$ref": "#/definitions/event.LogLevel"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "otherIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "uri": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "event.LogLevel": {
+            "type": "string",
+            "enum": [
+                "TRACE",
+                "DEBUG",
+                "INFO",
+                "WARN",
+                "ERROR",
+                "FATAL"
+            ],
+            "x-enum-varnames": [
+                "TRACE",
+                "DEBUG",
+                "INFO",
+                "WARN",
+                "ERROR",
+                "FATAL"
+            ]
+        },
+        "image.Distance": {
+            "type": "object",
+            "properties": {
+                "distance": {
+                    "description": "The number of differing perceptual hash bits",
+                    "type": "integer"
+                },
+                "fileName": {
+                    "description": "S3 File name (ID + file extension)",
                     "type": "string"
                 },
-                "contentLength": {
+                "fileSize": {
+                    "description": "File size in bytes",
                     "type": "integer"
                 },
-                "contentType": {
-                    "type": "string"
+                "height": {
+                    "description": "Height of the image in pixels",
+                    "type": "integer"
                 },
-                "etag": {
+                "id": {
+                    "description": "Image ID of the similar Image",
                     "type": "string"
                 },
-                "expiresAt": {
+                "label": {
+                    "description": "Image title or alt text, if available",
                     "type": "string"
                 },
-                "fileName": {
+                "md5Hash": {
+                    "description": "MD5 hash of the image",
                     "type": "string"
                 },
-                "host": {
+                "pHash": {
+                    "description": "PHash is the DTC perceptual hash of the image",
                     "type": "string"
                 },
-                "method": {
+                "source": {
+                    "description": "Source is the URI or file name of the original image",
                     "type": "string"
                 },
-                "url": {
-                    "type": "string"
+                "width": {
+                    "description": "Width of the image in pixels",
+                    "type": "integer"
                 }
             }
         },
-        "content.Author": {
+        "image.Image": {
             "type": "object",
             "properties": {
-                "email": {
+                "altText": {
+                    "description": "Concise Image description for accessibility",
                     "type": "string"
                 },
-                "name": {
+                "aspectRatio": {
+                    "description": "AspectRatio is width / height",
+                    "type": "number"
+                },
+                "createdAt": {
                     "type": "string"
                 },
-                "url": {
+                "description": {
+                    "description": "Full Image description",
+                    "type": "string"
+                },
+                "fileName": {
+                    "description": "S3 File name (ID + file extension)",
+                    "type": "string"
+                },
+                "fileSize": {
+                    "description": "File size in bytes",
+                    "type": "integer"
+                },
+                "height": {
+                    "description": "Height of the image in pixels",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "md5Hash": {
+                    "description": "MD5 hash of the image",
+                    "type": "string"
+                },
+                "mediaType": {
+                    "description": "Media Type (image/jpeg, image/webp, image/png, image/gif)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/image.MediaType"
+                        }
+                    ]
+                },
+                "pHash": {
+                    "description": "PHash is the DTC perceptual hash of the image",
+                    "type": "string"
+                },
+                "sourceFileName": {
+                    "description": "SourceFileName is the name of the original image",
+                    "type": "string"
+                },
+                "sourceURI": {
+                    "description": "SourceURI is the URI of the original image",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is the current status of the image",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/image.Status"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "Tags are used to group images by topic or category",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "Image title, suitable for display as a caption",
                     "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "versionID": {
+                    "type": "string"
+                },
+                "width": {
+                    "description": "Width of the image in pixels",
+                    "type": "integer"
                 }
             }
         },
-        "content.Content": {
+        "image.MediaType": {
+            "type": "string",
+            "enum": [
+                "image/gif",
+                "image/jpeg",
+                "image/png",
+                "image/webp"
+            ],
+            "x-enum-varnames": [
+                "GIF",
+                "JPEG",
+                "PNG",
+                "WebP"
+            ]
+        },
+        "image.Status": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "UPLOADED",
+                "COMPLETE",
+                "ERROR"
+            ],
+            "x-enum-varnames": [
+                "PENDING",
+                "UPLOADED",
+                "COMPLETE",
+                "ERROR"
+            ]
+        },
+        "job.Job": {
             "type": "object",
             "properties": {
-                "authors": {
+                "changed": {
+                    "description": "items updated",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "entityId": {
+                    "type": "string"
+                },
+                "entityType": {
+                    "description": "subject of the Job, if any (e.g. Organization)",
+                    "type": "string"
+                },
+                "errors": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/content.Author"
+                        "type": "string"
                     }
                 },
-                "body": {
-                    "$ref": "#/definitions/content.Section"
-                },
-                "comment": {
+                "expiresAt": {
                     "type": "string"
                 },
-                "createdAt": {
+                "failed": {
+                    "description": "items that could not be updated",
+                    "type": "integer"
+                },
+                "finishedAt": {
                     "type": "string"
                 },
-                "editorId": {
+                "id": {
                     "type": "string"
                 },
-                "editorName": {
+                "kind": {
                     "type": "string"
                 },
-                "id": {
+                "message": {
                     "type": "string"
                 },
-                "imageCount": {
+                "processed": {
+                    "description": "items checked",
                     "type": "integer"
                 },
-                "linkCount": {
+                "stale": {
+                    "description": "items found to need an update",
                     "type": "integer"
                 },
-                "sectionCount": {
-                    "type": "integer"
+                "startedAt": {
+                    "type": "string"
                 },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "status": {
+                    "$ref": "#/definitions/job.Status"
                 },
-                "type": {
-                    "$ref": "#/definitions/content.Type"
+                "total": {
+                    "description": "items found so far",
+                    "type": "integer"
                 },
                 "updatedAt": {
                     "type": "string"
                 },
-                "versionId": {
+                "userId": {
+                    "description": "who started the Job, if anyone",
                     "type": "string"
-                },
-                "wordCount": {
-                    "type": "integer"
                 }
             }
         },
-        "content.Link": {
+        "job.Status": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "RUNNING",
+                "SUCCEEDED",
+                "FAILED"
+            ],
+            "x-enum-varnames": [
+                "PENDING",
+                "RUNNING",
+                "SUCCEEDED",
+                "FAILED"
+            ]
+        },
+        "main.APIEvent": {
             "type": "object",
             "properties": {
-                "description": {
-                    "description": "Description is a brief rich text (HTML) description of the destination (optional)",
+                "code": {
+                    "type": "integer"
+                },
+                "createdAt": {
                     "type": "string"
                 },
-                "entityId": {
-                    "description": "EntityID (a TUID) of the destination resource (optional)",
+                "eventID": {
                     "type": "string"
                 },
-                "entityType": {
-                    "description": "EntityType of the destination resource (optional)",
+                "logLevel": {
                     "type": "string"
                 },
-                "id": {
-                    "description": "ID is used for client application state management",
+                "message": {
                     "type": "string"
                 },
-                "images": {
-                    "description": "Image(s) for the link destination (optional). Thumbnail images of different sizes may be provided.",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/image.Image"
-                    }
+                "uri": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "apiKey": {
+                    "$ref": "#/definitions/apikey.APIKey"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.ContentStatusRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/content.Status"
+                }
+            }
+        },
+        "main.DomainRequest": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.InvitationAcceptance": {
+            "type": "object",
+            "properties": {
+                "familyName": {
+                    "type": "string"
                 },
-                "shortTitle": {
-                    "description": "ShortTitle (anchor tag body), useful for portrait view (optional)",
+                "givenName": {
                     "type": "string"
                 },
-                "title": {
-                    "description": "Title (anchor tag body), useful for landscape view (required)",
+                "password": {
                     "type": "string"
                 },
-                "url": {
-                    "description": "URL (anchor tag href), may be relative or absolute (required)",
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "content.Section": {
+        "main.InvitationRequest": {
             "type": "object",
             "properties": {
-                "id": {
-                    "description": "Section ID used for client application state management",
+                "email": {
                     "type": "string"
                 },
-                "images": {
-                    "description": "Section images (optional)",
+                "expiresAt": {
+                    "type": "string"
+                },
+                "roles": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/image.Image"
+                        "type": "string"
                     }
+                }
+            }
+        },
+        "main.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "$ref": "#/definitions/token.Token"
                 },
-                "links": {
-                    "description": "Section links (optional)",
+                "user": {
+                    "$ref": "#/definitions/user.User"
+                }
+            }
+        },
+        "main.MembershipRequest": {
+            "type": "object",
+            "properties": {
+                "roles": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/content.Link"
+                        "type": "string"
                     }
+                }
+            }
+        },
+        "main.RestoreVersionRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.TOTPEnrollment": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
                 },
-                "sections": {
-                    "description": "Nested subsections (optional)",
+                "uri": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.TOTPRecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "recoveryCodes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/content.Section"
+                        "type": "string"
                     }
-                },
-                "subtitle": {
-                    "description": "Section subtitle (subheading; optional)",
+                }
+            }
+        },
+        "main.TOTPRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string"
+                }
+            }
+        },
+        "main.params": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
                 },
-                "text": {
-                    "description": "Section text (HTML content; optional)",
+                "offset": {
                     "type": "string"
                 },
-                "title": {
-                    "description": "Section title (heading; optional)",
-                    "type": "string"
+                "reverse": {
+                    "type": "boolean"
                 }
             }
         },
-        "content.Type": {
-            "type": "string",
-            "enum": [
-                "BOOK",
-                "CHAPTER",
-                "ARTICLE",
-                "CATEGORY"
-            ],
-            "x-enum-varnames": [
-                "BOOK",
-                "CHAPTER",
-                "ARTICLE",
-                "CATEGORY"
-            ]
-        },
-        "device.Count": {
+        "main.request": {
             "type": "object",
             "properties": {
-                "clientNames": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
+                "body": {
+                    "type": "string"
                 },
-                "clientTypes": {
+                "contentLength": {
+                    "type": "integer"
+                },
+                "header": {
                     "type": "object",
                     "additionalProperties": {
-                        "type": "integer"
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
                     }
                 },
-                "date": {
-                    "description": "YYYY-MM-DD",
+                "host": {
                     "type": "string"
                 },
-                "deviceTypes": {
+                "method": {
+                    "type": "string"
+                },
+                "params": {
+                    "$ref": "#/definitions/main.params"
+                },
+                "proto": {
+                    "type": "string"
+                },
+                "remoteAddr": {
+                    "type": "string"
+                },
+                "requestURI": {
+                    "type": "string"
+                },
+                "token": {
+                    "$ref": "#/definitions/token.Token"
+                },
+                "trailer": {
                     "type": "object",
                     "additionalProperties": {
-                        "type": "integer"
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
                     }
                 },
-                "osNames": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
+                "transferEncoding": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 },
-                "total": {
-                    "type": "integer"
+                "url": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/user.User"
                 }
             }
         },
-        "device.Device": {
+        "metric.Metric": {
             "type": "object",
             "properties": {
                 "createdAt": {
                     "type": "string"
                 },
-                "expiresAt": {
+                "entityId": {
                     "type": "string"
                 },
-                "id": {
+                "entityType": {
                     "type": "string"
                 },
-                "lastSeen": {
+                "expiresAt": {
                     "type": "string"
                 },
-                "updatedAt": {
+                "id": {
                     "type": "string"
                 },
-                "userAgent": {
-                    "$ref": "#/definitions/user_agent.UserAgent"
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "userId": {
+                "title": {
                     "type": "string"
                 },
-                "versionID": {
+                "units": {
                     "type": "string"
+                },
+                "value": {
+                    "type": "number"
                 }
             }
         },
-        "email.Email": {
+        "metric.MetricStat": {
             "type": "object",
             "properties": {
-                "bcc": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/email.Identity"
-                    }
+                "count": {
+                    "type": "integer"
                 },
-                "bodyHTML": {
+                "entityId": {
                     "type": "string"
                 },
-                "bodyText": {
+                "entityType": {
                     "type": "string"
                 },
-                "cc": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/email.Identity"
-                    }
-                },
-                "createdAt": {
+                "fromTime": {
                     "type": "string"
                 },
-                "eventMessage": {
-                    "type": "string"
+                "max": {
+                    "type": "number"
                 },
-                "from": {
-                    "$ref": "#/definitions/email.Identity"
+                "mean": {
+                    "type": "number"
                 },
-                "id": {
-                    "type": "string"
+                "median": {
+                    "type": "number"
                 },
-                "status": {
-                    "$ref": "#/definitions/email.Status"
+                "min": {
+                    "type": "number"
                 },
-                "subject": {
-                    "type": "string"
+                "stdDev": {
+                    "type": "number"
                 },
-                "to": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/email.Identity"
-                    }
+                "sum": {
+                    "type": "number"
                 },
-                "updatedAt": {
+                "tag": {
                     "type": "string"
                 },
-                "versionID": {
+                "toTime": {
                     "type": "string"
                 }
             }
         },
-        "email.Identity": {
+        "org.DomainChallenge": {
             "type": "object",
             "properties": {
-                "address": {
-                    "description": "Email address of the person/identity",
+                "createdAt": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "expiresAt": {
                     "type": "string"
                 },
-                "name": {
-                    "description": "Full name of the person/identity",
+                "recordName": {
+                    "type": "string"
+                },
+                "value": {
                     "type": "string"
                 }
             }
         },
-        "email.Status": {
-            "type": "string",
-            "enum": [
-                "PENDING",
-                "SENT",
-                "UNSENT",
-                "ERROR"
-            ],
-            "x-enum-varnames": [
-                "PENDING",
-                "SENT",
-                "UNSENT",
-                "ERROR"
-            ]
-        },
-        "event.Event": {
+        "org.Invitation": {
             "type": "object",
             "properties": {
-                "createdAt": {
+                "acceptedAt": {
                     "type": "string"
                 },
-                "entityId": {
+                "acceptedBy": {
+                    "description": "ID of the User who accepted the Invitation",
                     "type": "string"
                 },
-                "entityType": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
                     "type": "string"
                 },
                 "expiresAt": {
@@ -8720,465 +13903,476 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
-                "logLevel": {
-                    "$ref": "#/definitions/event.LogLevel"
+                "inviterId": {
+                    "type": "string"
                 },
-                "message": {
+                "inviterName": {
                     "type": "string"
                 },
-                "otherIds": {
+                "orgId": {
+                    "type": "string"
+                },
+                "orgName": {
+                    "type": "string"
+                },
+                "roles": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "uri": {
+                "status": {
+                    "$ref": "#/definitions/org.InvitationStatus"
+                },
+                "tokenHash": {
                     "type": "string"
                 },
-                "userId": {
+                "updatedAt": {
+                    "type": "string"
+                },
+                "versionID": {
                     "type": "string"
                 }
             }
         },
-        "event.LogLevel": {
+        "org.InvitationStatus": {
             "type": "string",
             "enum": [
-                "TRACE",
-                "DEBUG",
-                "INFO",
-                "WARN",
-                "ERROR",
-                "FATAL"
+                "INVITED",
+                "ACCEPTED",
+                "REVOKED"
             ],
             "x-enum-varnames": [
-                "TRACE",
-                "DEBUG",
-                "INFO",
-                "WARN",
-                "ERROR",
-                "FATAL"
+                "INVITED",
+                "ACCEPTED",
+                "REVOKED"
             ]
         },
-        "image.Distance": {
+        "org.Organization": {
             "type": "object",
             "properties": {
-                "distance": {
-                    "description": "The number of differing perceptual hash bits",
-                    "type": "integer"
-                },
-                "fileName": {
-                    "description": "S3 File name (ID + file extension)",
+                "createdAt": {
                     "type": "string"
                 },
-                "fileSize": {
-                    "description": "File size in bytes",
-                    "type": "integer"
+                "domainChallenges": {
+                    "description": "DomainChallenges are the open requests to verify email domains.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/org.DomainChallenge"
+                    }
                 },
-                "height": {
-                    "description": "Height of the image in pixels",
-                    "type": "integer"
+                "domains": {
+                    "description": "Domains are the verified email domains owned by the Organization. New Users with a matching email\naddress join the Organization automatically.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "id": {
-                    "description": "Image ID of the similar Image",
                     "type": "string"
                 },
-                "label": {
-                    "description": "Image title or alt text, if available",
+                "name": {
                     "type": "string"
                 },
-                "md5Hash": {
-                    "description": "MD5 hash of the image",
+                "parentId": {
+                    "description": "parent Organization (e.g. of a division), if any",
                     "type": "string"
                 },
-                "pHash": {
-                    "description": "PHash is the DTC perceptual hash of the image",
-                    "type": "string"
+                "status": {
+                    "$ref": "#/definitions/org.Status"
                 },
-                "source": {
-                    "description": "Source is the URI or file name of the original image",
+                "updatedAt": {
                     "type": "string"
                 },
-                "width": {
-                    "description": "Width of the image in pixels",
-                    "type": "integer"
+                "versionID": {
+                    "type": "string"
                 }
             }
         },
-        "image.Image": {
+        "org.Status": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "ENABLED",
+                "DISABLED"
+            ],
+            "x-enum-varnames": [
+                "PENDING",
+                "ENABLED",
+                "DISABLED"
+            ]
+        },
+        "org.Tree": {
             "type": "object",
             "properties": {
-                "altText": {
-                    "description": "Concise Image description for accessibility",
-                    "type": "string"
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/org.Tree"
+                    }
                 },
-                "aspectRatio": {
-                    "description": "AspectRatio is width / height",
-                    "type": "number"
+                "organization": {
+                    "$ref": "#/definitions/org.Organization"
+                }
+            }
+        },
+        "role.Permissions": {
+            "type": "object",
+            "properties": {
+                "global": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "createdAt": {
-                    "type": "string"
+                "org": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "description": {
-                    "description": "Full Image description",
+                "orgId": {
                     "type": "string"
                 },
-                "fileName": {
-                    "description": "S3 File name (ID + file extension)",
-                    "type": "string"
+                "orgRoles": {
+                    "description": "Membership roles, if OrgID is not the primary Organization",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "fileSize": {
-                    "description": "File size in bytes",
-                    "type": "integer"
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "height": {
-                    "description": "Height of the image in pixels",
-                    "type": "integer"
+                "subOrgIds": {
+                    "description": "SubOrgIDs are descendants of the Organization, where the Org permissions also apply, if enabled.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "id": {
+                "userId": {
                     "type": "string"
-                },
-                "md5Hash": {
-                    "description": "MD5 hash of the image",
+                }
+            }
+        },
+        "role.Role": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
                     "type": "string"
                 },
-                "mediaType": {
-                    "description": "Media Type (image/jpeg, image/webp, image/png, image/gif)",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/image.MediaType"
-                        }
-                    ]
-                },
-                "pHash": {
-                    "description": "PHash is the DTC perceptual hash of the image",
+                "description": {
                     "type": "string"
                 },
-                "sourceFileName": {
-                    "description": "SourceFileName is the name of the original image",
+                "id": {
                     "type": "string"
                 },
-                "sourceURI": {
-                    "description": "SourceURI is the URI of the original image",
+                "name": {
                     "type": "string"
                 },
-                "status": {
-                    "description": "Status is the current status of the image",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/image.Status"
-                        }
-                    ]
+                "orgScoped": {
+                    "type": "boolean"
                 },
-                "tags": {
-                    "description": "Tags are used to group images by topic or category",
+                "permissions": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "title": {
-                    "description": "Image title, suitable for display as a caption",
-                    "type": "string"
-                },
                 "updatedAt": {
                     "type": "string"
                 },
                 "versionID": {
                     "type": "string"
-                },
-                "width": {
-                    "description": "Width of the image in pixels",
-                    "type": "integer"
                 }
             }
         },
-        "image.MediaType": {
-            "type": "string",
-            "enum": [
-                "image/gif",
-                "image/jpeg",
-                "image/png",
-                "image/webp"
-            ],
-            "x-enum-varnames": [
-                "GIF",
-                "JPEG",
-                "PNG",
-                "WebP"
-            ]
-        },
-        "image.Status": {
-            "type": "string",
-            "enum": [
-                "PENDING",
-                "UPLOADED",
-                "COMPLETE",
-                "ERROR"
-            ],
-            "x-enum-varnames": [
-                "PENDING",
-                "UPLOADED",
-                "COMPLETE",
-                "ERROR"
-            ]
+        "token.Introspection": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "client_id": {
+                    "description": "APIKey ID (client_credentials grant)",
+                    "type": "string"
+                },
+                "exp": {
+                    "description": "expiration time (Unix seconds)",
+                    "type": "integer"
+                },
+                "iat": {
+                    "description": "issued-at time (Unix seconds)",
+                    "type": "integer"
+                },
+                "scope": {
+                    "description": "space-delimited scopes (client_credentials grant)",
+                    "type": "string"
+                },
+                "sub": {
+                    "description": "User ID",
+                    "type": "string"
+                },
+                "token_type": {
+                    "description": "\"Bearer\" or \"refresh_token\"",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "User email address",
+                    "type": "string"
+                }
+            }
         },
-        "main.APIEvent": {
+        "token.JWK": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer"
+                "alg": {
+                    "type": "string"
                 },
-                "createdAt": {
+                "crv": {
                     "type": "string"
                 },
-                "eventID": {
+                "kid": {
                     "type": "string"
                 },
-                "logLevel": {
+                "kty": {
                     "type": "string"
                 },
-                "message": {
+                "use": {
                     "type": "string"
                 },
-                "uri": {
+                "x": {
                     "type": "string"
                 }
             }
         },
-        "main.LoginResponse": {
+        "token.JWKS": {
             "type": "object",
             "properties": {
-                "token": {
-                    "$ref": "#/definitions/token.Token"
-                },
-                "user": {
-                    "$ref": "#/definitions/user.User"
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/token.JWK"
+                    }
                 }
             }
         },
-        "main.params": {
+        "token.OAuthError": {
             "type": "object",
             "properties": {
-                "limit": {
-                    "type": "integer"
-                },
-                "offset": {
+                "error": {
                     "type": "string"
                 },
-                "reverse": {
-                    "type": "boolean"
+                "error_description": {
+                    "type": "string"
                 }
             }
         },
-        "main.request": {
+        "token.OAuthResponse": {
             "type": "object",
             "properties": {
-                "body": {
+                "access_token": {
+                    "description": "Token ID (a tuid.TUID)",
                     "type": "string"
                 },
-                "contentLength": {
+                "expires_in": {
+                    "description": "lifetime of the access token, in seconds",
                     "type": "integer"
                 },
-                "header": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
-                    }
-                },
-                "host": {
+                "refresh_token": {
+                    "description": "RefreshToken ID, for use with the refresh_token grant",
                     "type": "string"
                 },
-                "method": {
+                "scope": {
+                    "description": "space-delimited scopes (client_credentials grant)",
                     "type": "string"
                 },
-                "params": {
-                    "$ref": "#/definitions/main.params"
-                },
-                "proto": {
+                "token_type": {
+                    "description": "always \"Bearer\"",
                     "type": "string"
-                },
-                "remoteAddr": {
+                }
+            }
+        },
+        "token.Request": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "TOTP or recovery code (password grant, if enrolled)",
                     "type": "string"
                 },
-                "requestURI": {
+                "deviceId": {
+                    "description": "Device ID, if known (password grant)",
                     "type": "string"
                 },
-                "token": {
-                    "$ref": "#/definitions/token.Token"
+                "grantType": {
+                    "description": "\"password\" (default) or \"refresh_token\"",
+                    "type": "string"
                 },
-                "trailer": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
-                    }
+                "orgId": {
+                    "description": "active Organization ID, if not the primary (password grant)",
+                    "type": "string"
                 },
-                "transferEncoding": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "password": {
+                    "description": "plaintext password (password grant)",
+                    "type": "string"
                 },
-                "url": {
+                "refreshToken": {
+                    "description": "RefreshToken ID (refresh_token grant)",
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/user.User"
+                "username": {
+                    "description": "email or User ID (password grant)",
+                    "type": "string"
                 }
             }
         },
-        "metric.Metric": {
+        "token.Response": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "type": "string"
-                },
-                "entityId": {
-                    "type": "string"
-                },
-                "entityType": {
+                "accessToken": {
+                    "description": "Token ID (a tuid.TUID)",
                     "type": "string"
                 },
                 "expiresAt": {
+                    "description": "when the token expires (DynamoDB TTL)",
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
-                },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "title": {
+                "refreshToken": {
+                    "description": "RefreshToken ID, for use with the refresh_token grant",
                     "type": "string"
                 },
-                "units": {
+                "tokenType": {
+                    "description": "usually \"Bearer\"",
                     "type": "string"
-                },
-                "value": {
-                    "type": "number"
                 }
             }
         },
-        "metric.MetricStat": {
+        "token.Session": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "entityId": {
+                "apiKeyId": {
                     "type": "string"
                 },
-                "entityType": {
+                "clientIp": {
                     "type": "string"
                 },
-                "fromTime": {
+                "createdAt": {
                     "type": "string"
                 },
-                "max": {
-                    "type": "number"
-                },
-                "mean": {
-                    "type": "number"
+                "current": {
+                    "description": "the Token used to make the request",
+                    "type": "boolean"
                 },
-                "median": {
-                    "type": "number"
+                "deviceId": {
+                    "type": "string"
                 },
-                "min": {
-                    "type": "number"
+                "expiresAt": {
+                    "type": "string"
                 },
-                "stdDev": {
-                    "type": "number"
+                "lastUsedAt": {
+                    "type": "string"
                 },
-                "sum": {
-                    "type": "number"
+                "orgId": {
+                    "type": "string"
                 },
-                "tag": {
+                "tokenId": {
                     "type": "string"
                 },
-                "toTime": {
+                "userAgent": {
+                    "$ref": "#/definitions/user_agent.UserAgent"
+                },
+                "userId": {
                     "type": "string"
                 }
             }
         },
-        "org.Organization": {
+        "token.Token": {
             "type": "object",
             "properties": {
+                "apiKeyId": {
+                    "description": "APIKey used for a client_credentials grant",
+                    "type": "string"
+                },
+                "clientIp": {
+                    "description": "client IP address that requested the Token",
+                    "type": "string"
+                },
                 "createdAt": {
                     "type": "string"
                 },
-                "id": {
+                "deviceId": {
+                    "description": "Device that requested the Token",
                     "type": "string"
                 },
-                "name": {
+                "email": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/org.Status"
+                "expiresAt": {
+                    "type": "string"
                 },
-                "updatedAt": {
+                "id": {
                     "type": "string"
                 },
-                "versionID": {
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "orgId": {
+                    "description": "active Organization, if not the User's primary Organization",
+                    "type": "string"
+                },
+                "userId": {
                     "type": "string"
                 }
             }
         },
-        "org.Status": {
-            "type": "string",
-            "enum": [
-                "PENDING",
-                "ENABLED",
-                "DISABLED"
-            ],
-            "x-enum-varnames": [
-                "PENDING",
-                "ENABLED",
-                "DISABLED"
-            ]
-        },
-        "token.Request": {
+        "tuid.TUIDInfo": {
             "type": "object",
             "properties": {
-                "grantType": {
-                    "description": "usually \"password\"",
-                    "type": "string"
+                "entropy": {
+                    "type": "integer"
                 },
-                "password": {
-                    "description": "plaintext password",
+                "id": {
                     "type": "string"
                 },
-                "username": {
-                    "description": "email or User ID",
+                "timestamp": {
                     "type": "string"
                 }
             }
         },
-        "token.Response": {
+        "user.Lockout": {
             "type": "object",
             "properties": {
-                "accessToken": {
-                    "description": "Token ID (a tuid.TUID)",
+                "expiresAt": {
                     "type": "string"
                 },
-                "expiresAt": {
-                    "description": "when the token expires (DynamoDB TTL)",
+                "failures": {
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "kind and subject, e.g. \"user:\u003cUser ID\u003e\" or \"ip:\u003cIP address\u003e\"",
                     "type": "string"
                 },
-                "tokenType": {
-                    "description": "usually \"Bearer\"",
+                "kind": {
+                    "description": "\"user\", \"ip\", or \"registration\"",
+                    "type": "string"
+                },
+                "lastFailure": {
+                    "type": "string"
+                },
+                "lockedUntil": {
+                    "type": "string"
+                },
+                "subject": {
+                    "description": "User ID or IP address",
                     "type": "string"
                 }
             }
         },
-        "token.Token": {
+        "user.Membership": {
             "type": "object",
             "properties": {
                 "createdAt": {
@@ -9187,27 +14381,49 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
-                "expiresAt": {
+                "id": {
                     "type": "string"
                 },
-                "id": {
+                "orgId": {
+                    "type": "string"
+                },
+                "orgName": {
+                    "type": "string"
+                },
+                "primary": {
+                    "description": "true for the User's primary Organization (User.OrgID)",
+                    "type": "boolean"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updatedAt": {
                     "type": "string"
                 },
                 "userId": {
                     "type": "string"
+                },
+                "versionID": {
+                    "type": "string"
                 }
             }
         },
-        "tuid.TUIDInfo": {
+        "user.Registration": {
             "type": "object",
             "properties": {
-                "entropy": {
-                    "type": "integer"
+                "email": {
+                    "type": "string"
                 },
-                "id": {
+                "familyName": {
                     "type": "string"
                 },
-                "timestamp": {
+                "givenName": {
+                    "type": "string"
+                },
+                "password": {
                     "type": "string"
                 }
             }
@@ -9261,6 +14477,15 @@ const docTemplate = `{
                 "passwordReset": {
                     "type": "string"
                 },
+                "passwordResetExpires": {
+                    "type": "string"
+                },
+                "recoveryCodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "roles": {
                     "type": "array",
                     "items": {
@@ -9270,9 +14495,18 @@ const docTemplate = `{
                 "status": {
                     "$ref": "#/definitions/user.Status"
                 },
+                "totpEnabled": {
+                    "type": "boolean"
+                },
+                "totpSecret": {
+                    "type": "string"
+                },
                 "updatedAt": {
                     "type": "string"
                 },
+                "verifiedEmail": {
+                    "type": "string"
+                },
                 "versionID": {
                     "type": "string"
                 },
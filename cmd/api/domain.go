@@ -0,0 +1,330 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/org"
+	"versionary-api/pkg/role"
+)
+
+// registerDomainRoutes initializes the Organization email domain routes.
+func registerDomainRoutes(r *gin.Engine) {
+	handleRoutes(r, []route{
+		{"POST", "/v1/organizations/:id/domains", role.OrganizationWrite, orgScoped, createDomainChallenge},
+		{"POST", "/v1/organizations/:id/domains/:domain/verify", role.OrganizationWrite, orgScoped, verifyDomain},
+		{"DELETE", "/v1/organizations/:id/domains/:domain", role.OrganizationWrite, orgScoped, removeDomain},
+		{"GET", "/v1/organization_domains", role.OrganizationRead, global, readOrganizationDomains},
+		{"GET", "/v1/organization_domains/:domain", role.OrganizationRead, global, readOrganizationByDomain},
+	})
+}
+
+// DomainRequest is the request body for starting verification of an Organization's email domain.
+type DomainRequest struct {
+	Domain string `json:"domain"`
+}
+
+// createDomainChallenge starts verification of an email domain for an Organization.
+//
+// @Summary Create Domain Challenge
+// @Description Start verifying an Organization email domain
+// @Description Start verifying an email domain owned by the Organization. The response describes a DNS TXT
+// @Description record to publish; then verify the domain. Once verified, new Users with an email address in
+// @Description the domain (or its subdomains) join the Organization automatically. Organization administrators
+// @Description may only verify domains for their own organization.
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
+// @Param id path string true "Organization ID"
+// @Param domain body DomainRequest true "Email Domain"
+// @Success 201 {object} org.DomainChallenge "DNS TXT record to publish"
+// @Failure 400 {object} APIEvent "Bad Request (invalid JSON body, path parameter, or domain)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an administrator of the organization)"
+// @Failure 404 {object} APIEvent "Not Found (organization)"
+// @Failure 409 {object} APIEvent "Conflict (domain is already verified)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/organizations/{id}/domains [post]
+func createDomainChallenge(c *gin.Context) {
+	o, ok := readDomainOrg(c)
+	if !ok {
+		return
+	}
+	var body DomainRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
+		return
+	}
+	o, dc, err := api.OrgService.CreateDomainChallenge(c, o, body.Domain)
+	if err != nil && errors.Is(err, org.ErrInvalidDomain) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: %w", err))
+		return
+	}
+	if err != nil && (errors.Is(err, org.ErrDomainVerified) || errors.Is(err, org.ErrDomainTaken)) {
+		abortWithError(c, http.StatusConflict, fmt.Errorf("conflict: %w", err))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   o.ID,
+			EntityType: o.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("create domain challenge for organization %s: %w", o.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   o.ID,
+		EntityType: o.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("created domain challenge for Organization %s %s: %s", o.ID, o.Name, dc.Domain),
+		URI:        c.Request.URL.String(),
+	})
+	c.JSON(http.StatusCreated, dc)
+}
+
+// verifyDomain checks the DNS TXT record for an Organization's email domain challenge.
+//
+// @Summary Verify Domain
+// @Description Verify an Organization email domain
+// @Description Check the DNS TXT record of the domain challenge. If it holds the challenge value, then the
+// @Description domain is verified, and the updated Organization is returned. A domain may be verified by only
+// @Description one Organization.
+// @Tags Organization
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
+// @Param id path string true "Organization ID"
+// @Param domain path string true "Email Domain"
+// @Success 200 {object} org.Organization "Organization, with the verified domain"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an administrator of the organization)"
+// @Failure 404 {object} APIEvent "Not Found (organization or domain challenge)"
+// @Failure 409 {object} APIEvent "Conflict (domain is verified by another organization)"
+// @Failure 422 {object} APIEvent "Unprocessable Entity (DNS TXT record not found)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/organizations/{id}/domains/{domain}/verify [post]
+func verifyDomain(c *gin.Context) {
+	o, ok := readDomainOrg(c)
+	if !ok {
+		return
+	}
+	domain := org.StandardizeDomain(c.Param("domain"))
+	o, err := api.OrgService.VerifyDomain(c, api.DNSResolver, o, domain)
+	if err != nil && errors.Is(err, org.ErrDomainChallengeNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: %w", err))
+		return
+	}
+	if err != nil && errors.Is(err, org.ErrDomainNotVerified) {
+		abortWithError(c, http.StatusUnprocessableEntity, fmt.Errorf("unprocessable entity: %w", err))
+		return
+	}
+	if err != nil && errors.Is(err, org.ErrDomainTaken) {
+		abortWithError(c, http.StatusConflict, fmt.Errorf("conflict: %w", err))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   o.ID,
+			EntityType: o.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("verify domain %s for organization %s: %w", domain, o.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   o.ID,
+		EntityType: o.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("verified domain %s for Organization %s %s", domain, o.ID, o.Name),
+		URI:        c.Request.URL.String(),
+	})
+	c.JSON(http.StatusOK, o)
+}
+
+// removeDomain removes a verified email domain, or an open domain challenge, from an Organization.
+//
+// @Summary Remove Domain
+// @Description Remove an Organization email domain
+// @Description Remove a verified email domain (or an open domain challenge) from the Organization.
+// @Description Existing members are not affected.
+// @Tags Organization
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
+// @Param id path string true "Organization ID"
+// @Param domain path string true "Email Domain"
+// @Success 200 {object} org.Organization "Organization, without the domain"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an administrator of the organization)"
+// @Failure 404 {object} APIEvent "Not Found (organization or domain)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/organizations/{id}/domains/{domain} [delete]
+func removeDomain(c *gin.Context) {
+	o, ok := readDomainOrg(c)
+	if !ok {
+		return
+	}
+	domain := org.StandardizeDomain(c.Param("domain"))
+	o, err := api.OrgService.RemoveDomain(c, o, domain)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: organization %s domain %s", o.ID, domain))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   o.ID,
+			EntityType: o.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("remove domain %s from organization %s: %w", domain, o.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   o.ID,
+		EntityType: o.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("removed domain %s from Organization %s %s", domain, o.ID, o.Name),
+		URI:        c.Request.URL.String(),
+	})
+	c.JSON(http.StatusOK, o)
+}
+
+// readOrganizationDomains returns a list of the verified email domains.
+//
+// @Summary List Organization Domains
+// @Description Get Organization Domains
+// @Description Get an alphabetical list of the email domains verified by organizations.
+// @Tags Organization
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Success 200 {array} string "Organization Domains"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/organization_domains [get]
+func readOrganizationDomains(c *gin.Context) {
+	domains, err := api.OrgService.ReadAllDomains(c)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityType: "Organization",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read organization domains: %w", err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	c.JSON(http.StatusOK, domains)
+}
+
+// readOrganizationByDomain returns the Organization that verified the specified email domain.
+//
+// @Summary Read Organization by Domain
+// @Description Get Organization by Domain
+// @Description Get the Organization that verified the specified email domain.
+// @Tags Organization
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param domain path string true "Email Domain"
+// @Success 200 {object} org.Organization "Organization"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/organization_domains/{domain} [get]
+func readOrganizationByDomain(c *gin.Context) {
+	domain := org.StandardizeDomain(c.Param("domain"))
+	o, err := api.OrgService.ReadOrganizationByDomain(c, domain)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: organization with domain %s", domain))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityType: "Organization",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read organization with domain %s: %w", domain, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	c.JSON(http.StatusOK, o)
+}
+
+// domainOrg returns the ENABLED Organization that verified the domain of the email address, if any, so that
+// a new User may join it automatically. Lookup errors are logged, and the User joins no Organization.
+func domainOrg(c *gin.Context, address string) (org.Organization, bool) {
+	o, err := api.OrgService.ReadOrganizationByEmail(c, address)
+	if err != nil && !errors.Is(err, v.ErrNotFound) {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityType: "Organization",
+			LogLevel:   event.WARN,
+			Message:    fmt.Errorf("read organization for email %s: %w", address, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+	}
+	return o, err == nil
+}
+
+// readDomainOrg validates the path parameter ID and the requester's authority to manage the Organization's
+// email domains, and reads the Organization. The request is aborted, and false returned, if it fails.
+func readDomainOrg(c *gin.Context) (org.Organization, bool) {
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return org.Organization{}, false
+	}
+	if _, scoped := contextOrgScope(c, role.OrganizationWrite); scoped && !contextPermissions(c).HasInOrg(role.OrganizationWrite, id) {
+		abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: manage domains of organization %s", id))
+		return org.Organization{}, false
+	}
+	o, err := api.OrgService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: organization %s", id))
+		return o, false
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "Organization",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read organization %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return o, false
+	}
+	return o, true
+}
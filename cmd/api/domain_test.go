@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"versionary-api/pkg/app"
+	"versionary-api/pkg/org"
+	"versionary-api/pkg/user"
+)
+
+func TestOrganizationDomains(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	o, orgAdmin, orgAdminToken := generateOrgAdmin("Domain Org")
+	defer deleteOrgAdmin(o, orgAdmin)
+	other, otherAdmin, otherAdminToken := generateOrgAdmin("Other Domain Org")
+	defer deleteOrgAdmin(other, otherAdmin)
+	resolver := org.StaticResolver{}
+	prior := api.DNSResolver
+	api.DNSResolver = resolver
+	defer func() { api.DNSResolver = prior }()
+	call := func(bearer, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "10.0.18.1:1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+	path := "/v1/organizations/" + o.ID + "/domains"
+	// Discard the verification emails sent to new Users
+	defer func() {
+		for _, address := range []string{"domain.user@eng.domainorg.com", "domain.staff@domainorg.com", "domain.contractor@domainorg.com"} {
+			emails, _ := api.EmailService.ReadAllEmailsByAddress(ctx, address)
+			for _, e := range emails {
+				_, _ = api.EmailService.Delete(ctx, e.ID)
+			}
+		}
+	}()
+
+	// Start verifying a domain
+	w := call(otherAdminToken, "POST", path, `{"domain": "domainorg.com"}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call(orgAdminToken, "POST", path, `{"domain": "not a domain"}`)
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	var dc org.DomainChallenge
+	w = call(orgAdminToken, "POST", path, `{"domain": "DomainOrg.com"}`)
+	if !expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") ||
+		!expect.NoError(json.NewDecoder(w.Body).Decode(&dc), "Decode JSON Domain Challenge") {
+		return
+	}
+	expect.Equal("domainorg.com", dc.Domain)
+	expect.Equal("_versionary-challenge.domainorg.com", dc.RecordName)
+
+	// Verify the domain, once the DNS TXT record is published
+	w = call(orgAdminToken, "POST", path+"/domainorg.com/verify", "")
+	expect.Equal(http.StatusUnprocessableEntity, w.Code, "HTTP Status Code")
+	w = call(orgAdminToken, "POST", path+"/unknown.com/verify", "")
+	expect.Equal(http.StatusNotFound, w.Code, "HTTP Status Code")
+	resolver[dc.RecordName] = []string{dc.Value}
+	var verified org.Organization
+	w = call(orgAdminToken, "POST", path+"/domainorg.com/verify", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&verified), "Decode JSON Organization") {
+		expect.Equal([]string{"domainorg.com"}, verified.Domains)
+		expect.Empty(verified.DomainChallenges)
+	}
+	w = call(otherAdminToken, "POST", "/v1/organizations/"+other.ID+"/domains", `{"domain": "domainorg.com"}`)
+	expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+
+	// Domains may not be changed by updating the Organization
+	hijack := other
+	hijack.Domains = []string{"domainorg.com"}
+	body, _ := json.Marshal(hijack)
+	w = call(adminToken, "PUT", "/v1/organizations/"+other.ID, string(body))
+	var updated org.Organization
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&updated), "Decode JSON Organization") {
+		expect.Empty(updated.Domains)
+	}
+
+	// Look up the Organization by domain
+	var found org.Organization
+	w = call(adminToken, "GET", "/v1/organization_domains/domainorg.com", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&found), "Decode JSON Organization") {
+		expect.Equal(o.ID, found.ID)
+	}
+	w = call(adminToken, "GET", "/v1/organization_domains/unknown.com", "")
+	expect.Equal(http.StatusNotFound, w.Code, "HTTP Status Code")
+	var domains []string
+	w = call(adminToken, "GET", "/v1/organization_domains", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&domains), "Decode JSON Domains") {
+		expect.Contains(domains, "domainorg.com")
+	}
+
+	// A registering User joins the Organization automatically, once their email address is verified
+	var registered user.User
+	w = call("", "POST", "/register", `{"givenName": "Domain", "email": "domain.user@eng.domainorg.com", "password": "domain1234"}`)
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&registered), "Decode JSON User") {
+		defer func() { _, _ = api.UserService.Delete(ctx, registered.ID) }()
+		expect.Empty(registered.OrgID)
+		expect.Empty(registered.Roles)
+		key, err := api.SecretKey(ctx, app.EmailVerificationKey)
+		if expect.NoError(err) {
+			token := registered.EmailVerificationToken(key, time.Now().Add(time.Hour))
+			w = call("", "GET", "/v1/users/"+registered.ID+"/verification?token="+token, "")
+			if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+				expect.NoError(json.NewDecoder(w.Body).Decode(&registered), "Decode JSON User") {
+				expect.Equal(o.ID, registered.OrgID)
+				expect.Equal(o.Name, registered.OrgName)
+				expect.Empty(registered.Roles)
+			}
+		}
+	}
+	// A new User created by an administrator joins once verified, too, unless an Organization is specified
+	var staff, contractor user.User
+	w = call(adminToken, "POST", "/v1/users", `{"givenName": "Domain", "familyName": "Staff", "email": "domain.staff@domainorg.com", "status": "ENABLED"}`)
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&staff), "Decode JSON User") {
+		defer func() { _, _ = api.UserService.Delete(ctx, staff.ID) }()
+		expect.Empty(staff.OrgID)
+	}
+	w = call(adminToken, "POST", "/v1/users", `{"givenName": "Domain", "familyName": "Contractor", "email": "domain.contractor@domainorg.com", "orgId": "`+other.ID+`", "status": "ENABLED"}`)
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&contractor), "Decode JSON User") {
+		defer func() { _, _ = api.UserService.Delete(ctx, contractor.ID) }()
+		expect.Equal(other.ID, contractor.OrgID)
+	}
+
+	// Remove the domain
+	w = call(orgAdminToken, "DELETE", path+"/domainorg.com", "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	w = call(orgAdminToken, "DELETE", path+"/domainorg.com", "")
+	expect.Equal(http.StatusNotFound, w.Code, "HTTP Status Code")
+	w = call(adminToken, "GET", "/v1/organization_domains/domainorg.com", "")
+	expect.Equal(http.StatusNotFound, w.Code, "HTTP Status Code")
+}
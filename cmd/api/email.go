@@ -12,21 +12,24 @@ import (
 
 	"versionary-api/pkg/email"
 	"versionary-api/pkg/event"
+	"versionary-api/pkg/role"
 )
 
 // registerEmailRoutes initializes the Email routes.
 func registerEmailRoutes(r *gin.Engine) {
-	r.POST("/v1/emails", roleAuthorizer("admin"), createEmail)
-	r.GET("/v1/emails", userAuthenticator(), readEmails)
-	r.GET("/v1/emails/:id", userAuthenticator(), readEmail)
-	r.HEAD("/v1/emails/:id", existsEmail)
-	r.GET("/v1/emails/:id/versions", roleAuthorizer("admin"), readEmailVersions)
-	r.GET("/v1/emails/:id/versions/:versionid", userAuthenticator(), readEmailVersion)
-	r.HEAD("/v1/emails/:id/versions/:versionid", existsEmailVersion)
-	r.PUT("/v1/emails/:id", roleAuthorizer("admin"), updateEmail)
-	r.DELETE("/v1/emails/:id", roleAuthorizer("admin"), deleteEmail)
-	r.GET("/v1/email_addresses", roleAuthorizer("admin"), readEmailAddresses)
-	r.GET("/v1/email_statuses", roleAuthorizer("admin"), readEmailStatuses)
+	handleRoutes(r, []route{
+		{"POST", "/v1/emails", role.EmailWrite, global, createEmail},
+		{"GET", "/v1/emails", authenticated, global, readEmails},
+		{"GET", "/v1/emails/:id", authenticated, global, readEmail},
+		{"HEAD", "/v1/emails/:id", public, global, existsEmail},
+		{"GET", "/v1/emails/:id/versions", role.EmailRead, global, readEmailVersions},
+		{"GET", "/v1/emails/:id/versions/:versionid", authenticated, global, readEmailVersion},
+		{"HEAD", "/v1/emails/:id/versions/:versionid", public, global, existsEmailVersion},
+		{"PUT", "/v1/emails/:id", role.EmailWrite, global, updateEmail},
+		{"DELETE", "/v1/emails/:id", role.EmailWrite, global, deleteEmail},
+		{"GET", "/v1/email_addresses", role.EmailRead, global, readEmailAddresses},
+		{"GET", "/v1/email_statuses", role.EmailRead, global, readEmailStatuses},
+	})
 }
 
 // createEmail creates and sends a new Email message.
@@ -126,7 +129,7 @@ func readEmails(c *gin.Context) {
 		address = i.Address
 	}
 	u, _ := contextUser(c) // the user has already been authenticated
-	if !u.HasRole("admin") {
+	if !contextPermissions(c).Has(role.EmailRead) {
 		if address != "" && address != u.Email {
 			abortWithError(c, http.StatusForbidden, fmt.Errorf("forbidden: admin credentials required to read another user's emails"))
 			return
@@ -227,7 +230,7 @@ func readEmail(c *gin.Context) {
 	}
 	// Verify that the user is authorized to read the Email
 	u, _ := contextUser(c) // the user has already been authenticated
-	if !u.HasRole("admin") && !e.IsParticipant(u.Email) {
+	if !contextPermissions(c).Has(role.EmailRead) && !e.IsParticipant(u.Email) {
 		abortWithError(c, http.StatusForbidden, fmt.Errorf("forbidden: email %s", id))
 		return
 	}
@@ -360,7 +363,7 @@ func readEmailVersion(c *gin.Context) {
 	}
 	// Verify that the user is authorized to read the Email
 	u, _ := contextUser(c) // the user has already been authenticated
-	if !u.HasRole("admin") && !version.IsParticipant(u.Email) {
+	if !contextPermissions(c).Has(role.EmailRead) && !version.IsParticipant(u.Email) {
 		abortWithError(c, http.StatusForbidden, fmt.Errorf("forbidden: email %s", id))
 		return
 	}
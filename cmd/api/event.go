@@ -13,20 +13,23 @@ import (
 	v "github.com/voxtechnica/versionary"
 
 	"versionary-api/pkg/event"
+	"versionary-api/pkg/role"
 )
 
 // registerEventRoutes initializes the Event routes with the Gin router.
 func registerEventRoutes(r *gin.Engine) {
-	r.POST("/v1/events", roleAuthorizer("admin"), createEvent)
-	r.GET("/v1/events", roleAuthorizer("admin"), readEvents)
-	r.GET("/v1/events/:id", readEvent)
-	r.HEAD("/v1/events/:id", existsEvent)
-	r.DELETE("/v1/events/:id", roleAuthorizer("admin"), deleteEvent)
-	r.GET("/v1/event_entity_ids", roleAuthorizer("admin"), readEventEntityIDs)
-	r.GET("/v1/event_entity_types", roleAuthorizer("admin"), readEventEntityTypes)
-	r.GET("/v1/event_log_levels", roleAuthorizer("admin"), readEventLogLevels)
-	r.GET("/v1/event_dates", roleAuthorizer("admin"), readEventDates)
-	r.GET("/v1/event_messages", roleAuthorizer("admin"), readEventMessages)
+	handleRoutes(r, []route{
+		{"POST", "/v1/events", role.EventWrite, global, createEvent},
+		{"GET", "/v1/events", role.EventRead, global, readEvents},
+		{"GET", "/v1/events/:id", public, global, readEvent},
+		{"HEAD", "/v1/events/:id", public, global, existsEvent},
+		{"DELETE", "/v1/events/:id", role.EventWrite, global, deleteEvent},
+		{"GET", "/v1/event_entity_ids", role.EventRead, global, readEventEntityIDs},
+		{"GET", "/v1/event_entity_types", role.EventRead, global, readEventEntityTypes},
+		{"GET", "/v1/event_log_levels", role.EventRead, global, readEventLogLevels},
+		{"GET", "/v1/event_dates", role.EventRead, global, readEventDates},
+		{"GET", "/v1/event_messages", role.EventRead, global, readEventMessages},
+	})
 }
 
 // createEvent creates a new Event.
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/org"
+	"versionary-api/pkg/role"
+)
+
+// registerHierarchyRoutes initializes the Organization hierarchy routes.
+func registerHierarchyRoutes(r *gin.Engine) {
+	handleRoutes(r, []route{
+		{"GET", "/v1/organizations/:id/children", role.OrganizationRead, orgScoped, readOrganizationChildren},
+		{"GET", "/v1/organizations/:id/ancestors", role.OrganizationRead, orgScoped, readOrganizationAncestors},
+		{"GET", "/v1/organizations/:id/subtree", role.OrganizationRead, orgScoped, readOrganizationSubtree},
+	})
+}
+
+// readOrganizationChildren returns the child Organizations of the specified Organization.
+//
+// @Summary List Child Organizations
+// @Description List Child Organizations
+// @Description List the Organizations whose parent is the specified Organization, sorted by ID.
+// @Description Organization administrators may only list children of their own organization.
+// @Tags Organization
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
+// @Param id path string true "Organization ID"
+// @Success 200 {array} org.Organization "Child Organizations"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator, or another organization)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/organizations/{id}/children [get]
+func readOrganizationChildren(c *gin.Context) {
+	o, ok := readHierarchyOrg(c)
+	if !ok {
+		return
+	}
+	children, err := api.OrgService.ReadChildren(c, o.ID)
+	if err != nil {
+		abortWithHierarchyError(c, o, "read children of", err)
+		return
+	}
+	c.JSON(http.StatusOK, children)
+}
+
+// readOrganizationAncestors returns the ancestors of the specified Organization.
+//
+// @Summary List Organization Ancestors
+// @Description List Organization Ancestors
+// @Description List the ancestors of the specified Organization, starting with its parent and ending with
+// @Description the root of the hierarchy (e.g. for breadcrumbs). The list is empty for a root Organization.
+// @Description Organization administrators may only list ancestors of their own organization.
+// @Tags Organization
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
+// @Param id path string true "Organization ID"
+// @Success 200 {array} org.Organization "Ancestor Organizations"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator, or another organization)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/organizations/{id}/ancestors [get]
+func readOrganizationAncestors(c *gin.Context) {
+	o, ok := readHierarchyOrg(c)
+	if !ok {
+		return
+	}
+	ancestors, err := api.OrgService.ReadAncestors(c, o)
+	if err != nil {
+		abortWithHierarchyError(c, o, "read ancestors of", err)
+		return
+	}
+	if ancestors == nil {
+		ancestors = []org.Organization{}
+	}
+	c.JSON(http.StatusOK, ancestors)
+}
+
+// readOrganizationSubtree returns the specified Organization and all of its descendants.
+//
+// @Summary Read Organization Subtree
+// @Description Read Organization Subtree
+// @Description Read the specified Organization and all of its descendants, as a tree of nested children.
+// @Description Organization administrators may only read the subtree of their own organization.
+// @Tags Organization
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
+// @Param id path string true "Organization ID"
+// @Success 200 {object} org.Tree "Organization Subtree"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator, or another organization)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/organizations/{id}/subtree [get]
+func readOrganizationSubtree(c *gin.Context) {
+	o, ok := readHierarchyOrg(c)
+	if !ok {
+		return
+	}
+	tree, err := api.OrgService.ReadSubtree(c, o)
+	if err != nil {
+		abortWithHierarchyError(c, o, "read subtree of", err)
+		return
+	}
+	c.JSON(http.StatusOK, tree)
+}
+
+// readHierarchyOrg validates the path parameter ID and the requester's authority to read the Organization,
+// and reads the Organization. The request is aborted, and false returned, if it fails.
+func readHierarchyOrg(c *gin.Context) (org.Organization, bool) {
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return org.Organization{}, false
+	}
+	if _, scoped := contextOrgScope(c, role.OrganizationRead); scoped && !contextPermissions(c).HasInOrg(role.OrganizationRead, id) {
+		abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: read hierarchy of organization %s", id))
+		return org.Organization{}, false
+	}
+	o, err := api.OrgService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: organization %s", id))
+		return o, false
+	}
+	if err != nil {
+		abortWithHierarchyError(c, org.Organization{ID: id}, "read", err)
+		return o, false
+	}
+	return o, true
+}
+
+// abortWithHierarchyError logs an error reading the Organization hierarchy, and aborts the request.
+func abortWithHierarchyError(c *gin.Context, o org.Organization, action string, err error) {
+	e, _, _ := api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   o.ID,
+		EntityType: "Organization",
+		LogLevel:   event.ERROR,
+		Message:    fmt.Errorf("%s organization %s: %w", action, o.ID, err).Error(),
+		URI:        c.Request.URL.String(),
+		Err:        err,
+	})
+	abortWithError(c, http.StatusInternalServerError, e)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"versionary-api/pkg/org"
+	"versionary-api/pkg/user"
+)
+
+func TestOrganizationHierarchy(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	root, rootAdmin, rootAdminToken := generateOrgAdmin("Hierarchy Root")
+	defer deleteOrgAdmin(root, rootAdmin)
+	call := func(bearer, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+bearer)
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "10.0.19.1:1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Create a division of the root Organization
+	var division org.Organization
+	w := call(adminToken, "POST", "/v1/organizations", `{"name": "Hierarchy Division", "status": "ENABLED", "parentId": "`+root.ID+`"}`)
+	if !expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") ||
+		!expect.NoError(json.NewDecoder(w.Body).Decode(&division), "Decode JSON Organization") {
+		return
+	}
+	defer func() { _, _ = api.OrgService.Delete(ctx, division.ID) }()
+	expect.Equal(root.ID, division.ParentID)
+	w = call(adminToken, "POST", "/v1/organizations", `{"name": "Hierarchy Orphan", "status": "ENABLED", "parentId": "9G5rgTk3bxHxM8Ak"}`)
+	expect.Equal(http.StatusUnprocessableEntity, w.Code, "HTTP Status Code")
+	member, _, err := api.UserService.Create(ctx, user.User{
+		GivenName:  "Division",
+		FamilyName: "Member",
+		Email:      "hierarchy_division_member@test.com",
+		OrgID:      division.ID,
+		OrgName:    division.Name,
+		Status:     user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.UserService.Delete(ctx, member.ID) }()
+
+	// Read the hierarchy
+	var orgs []org.Organization
+	w = call(rootAdminToken, "GET", "/v1/organizations/"+root.ID+"/children", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&orgs), "Decode JSON Organizations") &&
+		expect.Len(orgs, 1) {
+		expect.Equal(division.ID, orgs[0].ID)
+	}
+	w = call(adminToken, "GET", "/v1/organizations/"+division.ID+"/ancestors", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&orgs), "Decode JSON Organizations") &&
+		expect.Len(orgs, 1) {
+		expect.Equal(root.ID, orgs[0].ID)
+	}
+	var tree org.Tree
+	w = call(rootAdminToken, "GET", "/v1/organizations/"+root.ID+"/subtree", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&tree), "Decode JSON Organization Tree") {
+		expect.Equal([]string{root.ID, division.ID}, tree.IDs())
+	}
+	w = call(regularToken, "GET", "/v1/organizations/"+root.ID+"/subtree", "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+
+	// An Organization may not become its own ancestor
+	cycle := root
+	cycle.ParentID = division.ID
+	body, _ := json.Marshal(cycle)
+	w = call(adminToken, "PUT", "/v1/organizations/"+root.ID, string(body))
+	expect.Equal(http.StatusUnprocessableEntity, w.Code, "HTTP Status Code")
+
+	// By default, organization administrators may not manage descendant organizations
+	var users []user.User
+	w = call(rootAdminToken, "GET", "/v1/organizations/"+division.ID+"/children", "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call(rootAdminToken, "GET", "/v1/users?descendants=true", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&users), "Decode JSON Users") {
+		expect.Equal([]string{rootAdmin.ID}, userIDs(users))
+	}
+	w = call(adminToken, "GET", "/v1/users?org="+root.ID+"&descendants=true", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&users), "Decode JSON Users") {
+		expect.ElementsMatch([]string{rootAdmin.ID, member.ID}, userIDs(users))
+	}
+
+	// Optionally, org-scoped permissions extend to descendant organizations
+	api.OrgScopeDescendants = true
+	defer func() { api.OrgScopeDescendants = false }()
+	w = call(rootAdminToken, "GET", "/v1/organizations/"+division.ID+"/children", "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	w = call(rootAdminToken, "GET", "/v1/users?descendants=true", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&users), "Decode JSON Users") {
+		expect.ElementsMatch([]string{rootAdmin.ID, member.ID}, userIDs(users))
+	}
+	w = call(rootAdminToken, "GET", "/v1/users?org="+division.ID, "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&users), "Decode JSON Users") {
+		expect.Equal([]string{member.ID}, userIDs(users))
+	}
+	w = call(rootAdminToken, "GET", "/v1/users/"+member.ID, "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+
+	// An Organization with children may not be deleted
+	w = call(adminToken, "DELETE", "/v1/organizations/"+root.ID+"?policy=disable", "")
+	expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+}
+
+// userIDs returns the IDs of the Users.
+func userIDs(users []user.User) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return ids
+}
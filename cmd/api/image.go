@@ -16,27 +16,30 @@ import (
 	"versionary-api/pkg/event"
 	"versionary-api/pkg/image"
 	"versionary-api/pkg/ref"
+	"versionary-api/pkg/role"
 	"versionary-api/pkg/user"
 )
 
 // registerImageRoutes initializes the Image routes.
 func registerImageRoutes(r *gin.Engine) {
-	r.POST("/v1/images", roleAuthorizer("admin"), createImage)
-	r.GET("/v1/images", roleAuthorizer("admin"), readImages)
-	r.GET("/v1/images/:id", readImage)
-	r.HEAD("/v1/images/:id", existsImage)
-	r.GET("/v1/images/:id/versions", roleAuthorizer("admin"), readImageVersions)
-	r.GET("/v1/images/:id/versions/:versionid", readImageVersion)
-	r.HEAD("/v1/images/:id/versions/:versionid", existsImageVersion)
-	r.GET("/v1/images/:id/similar", roleAuthorizer("admin"), readSimilarImages)
-	r.GET("/v1/images/:id/download_url", getImageDownloadURL)
-	r.GET("/v1/images/:id/upload_url", roleAuthorizer("admin"), getImageUploadURL)
-	r.PUT("/v1/images/:id", roleAuthorizer("admin"), updateImage)
-	r.DELETE("/v1/images/:id", roleAuthorizer("admin"), deleteImage)
-	r.DELETE("/v1/images/:id/versions/:versionid", roleAuthorizer("admin"), deleteImageVersion)
-	r.GET("/v1/image_statuses", roleAuthorizer("admin"), readImageStatuses)
-	r.GET("/v1/image_tags", roleAuthorizer("admin"), readImageTags)
-	r.GET("/v1/image_labels", roleAuthorizer("admin"), readImageLabels)
+	handleRoutes(r, []route{
+		{"POST", "/v1/images", role.ImageWrite, global, createImage},
+		{"GET", "/v1/images", role.ImageRead, global, readImages},
+		{"GET", "/v1/images/:id", public, global, readImage},
+		{"HEAD", "/v1/images/:id", public, global, existsImage},
+		{"GET", "/v1/images/:id/versions", role.ImageRead, global, readImageVersions},
+		{"GET", "/v1/images/:id/versions/:versionid", public, global, readImageVersion},
+		{"HEAD", "/v1/images/:id/versions/:versionid", public, global, existsImageVersion},
+		{"GET", "/v1/images/:id/similar", role.ImageRead, global, readSimilarImages},
+		{"GET", "/v1/images/:id/download_url", public, global, getImageDownloadURL},
+		{"GET", "/v1/images/:id/upload_url", role.ImageRead, global, getImageUploadURL},
+		{"PUT", "/v1/images/:id", role.ImageWrite, global, updateImage},
+		{"DELETE", "/v1/images/:id", role.ImageWrite, global, deleteImage},
+		{"DELETE", "/v1/images/:id/versions/:versionid", role.ImageWrite, global, deleteImageVersion},
+		{"GET", "/v1/image_statuses", role.ImageRead, global, readImageStatuses},
+		{"GET", "/v1/image_tags", role.ImageRead, global, readImageTags},
+		{"GET", "/v1/image_labels", role.ImageRead, global, readImageLabels},
+	})
 }
 
 // createImage creates a new Image.
@@ -0,0 +1,565 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+
+	"versionary-api/pkg/email"
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/org"
+	"versionary-api/pkg/role"
+	"versionary-api/pkg/user"
+)
+
+// registerInvitationRoutes initializes the Invitation routes.
+func registerInvitationRoutes(r *gin.Engine) {
+	handleRoutes(r, []route{
+		{"POST", "/v1/organizations/:id/invitations", role.UserWrite, orgScoped, createInvitation},
+		{"GET", "/v1/organizations/:id/invitations", role.UserRead, orgScoped, readInvitations},
+		{"DELETE", "/v1/organizations/:id/invitations/:invitation_id", role.UserWrite, orgScoped, revokeInvitation},
+		{"POST", "/v1/invitations/:id/accept", public, global, acceptInvitation},
+	})
+}
+
+// InvitationRequest is the request body for inviting a person to join an Organization.
+type InvitationRequest struct {
+	Email     string    `json:"email"`
+	Roles     []string  `json:"roles,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+// InvitationAcceptance is the request body for accepting an Invitation. The name and password are
+// required only if a new User account is created for the invited email address.
+type InvitationAcceptance struct {
+	Token      string `json:"token"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+	Password   string `json:"password,omitempty"`
+}
+
+// createInvitation invites a person, by email address, to join an Organization.
+//
+// @Summary Create Invitation
+// @Description Invite a person to join an Organization
+// @Description Invite a person, by email address, to join the Organization with the specified roles.
+// @Description An email message is sent with a link for accepting the invitation, which expires in 7 days
+// @Description by default. Organization administrators may only invite people to their own organization.
+// @Tags Invitation
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
+// @Param id path string true "Organization ID"
+// @Param invitation body InvitationRequest true "Invitation"
+// @Success 201 {object} org.Invitation "Newly-created Invitation"
+// @Failure 400 {object} APIEvent "Bad Request (invalid JSON body or path parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an administrator of the organization, or role may not be granted)"
+// @Failure 404 {object} APIEvent "Not Found (organization)"
+// @Failure 422 {object} APIEvent "Invitation validation errors"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Header 201 {string} Location "URL of the organization's Invitations"
+// @Router /v1/organizations/{id}/invitations [post]
+func createInvitation(c *gin.Context) {
+	// Validate the path parameter ID and the requester's authority within the Organization
+	o, ok := readInvitationOrg(c, role.UserWrite)
+	if !ok {
+		return
+	}
+	// Parse the request body
+	var body InvitationRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
+		return
+	}
+	for _, r := range body.Roles {
+		if !canGrantRole(c, r) {
+			abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: grant role %s", r))
+			return
+		}
+	}
+	// Create the Invitation
+	cUser, _ := contextUser(c)
+	i, token, problems, err := api.InvitationService.Create(c, org.Invitation{
+		ExpiresAt:   body.ExpiresAt,
+		OrgID:       o.ID,
+		OrgName:     o.Name,
+		Email:       body.Email,
+		Roles:       body.Roles,
+		InviterID:   cUser.ID,
+		InviterName: cUser.FullName(),
+	})
+	if len(problems) > 0 && err != nil {
+		abortWithError(c, http.StatusUnprocessableEntity, fmt.Errorf("unprocessable entity: %w", err))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   i.ID,
+			EntityType: i.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("create invitation %s %s: %w", i.ID, i.Email, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Log the creation
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   i.ID,
+		EntityType: i.Type(),
+		OtherIDs:   []string{o.ID},
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("created Invitation %s for %s to Organization %s", i.ID, i.Email, o.ID),
+		URI:        c.Request.URL.String(),
+	})
+	// Send the invitation to the invited email address
+	link := fmt.Sprintf("%s/invitations/%s/%s", api.WebURL, i.ID, token)
+	inviter := i.InviterName
+	if inviter == "" {
+		inviter = "An administrator"
+	}
+	message := email.Email{
+		To:      []email.Identity{{Address: i.Email}},
+		Subject: "Invitation to join " + o.Name,
+		BodyText: fmt.Sprintf("Hello,\n\n%s has invited you to join %s. Follow the link below to accept the invitation. The link expires on %s.\n\n%s\n\nIf you were not expecting this invitation, you may ignore this message.\n",
+			inviter, o.Name, i.ExpiresAt.Format("January 2, 2006"), link),
+	}
+	e, problems, err := api.EmailService.Create(c, message)
+	if len(problems) > 0 && err != nil {
+		abortWithError(c, http.StatusUnprocessableEntity, fmt.Errorf("unprocessable entity: %w", err))
+		return
+	}
+	if err != nil {
+		evt, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   e.ID,
+			EntityType: e.Type(),
+			OtherIDs:   []string{i.ID},
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("invitation: create email %s for Invitation %s: %w", e.ID, i.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, evt)
+		return
+	}
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   e.ID,
+		EntityType: e.Type(),
+		OtherIDs:   []string{i.ID},
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("invitation: created email %s for Invitation %s", e.ID, i.ID),
+		URI:        c.Request.URL.String(),
+	})
+	// Return the new Invitation
+	c.Header("Location", c.Request.URL.String())
+	c.JSON(http.StatusCreated, i.Scrub())
+}
+
+// readInvitations returns the Invitations to an Organization.
+//
+// @Summary List Invitations
+// @Description List Organization Invitations
+// @Description List the Invitations to an Organization, in chronological order, optionally filtered by status.
+// @Description Organization administrators may only list the Invitations to their own organization.
+// @Tags Invitation
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
+// @Param id path string true "Organization ID"
+// @Param status query string false "Status" Enums(INVITED, ACCEPTED, REVOKED)
+// @Success 200 {array} org.Invitation "Invitations"
+// @Failure 400 {object} APIEvent "Bad Request (invalid parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an administrator of the organization)"
+// @Failure 404 {object} APIEvent "Not Found (organization)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/organizations/{id}/invitations [get]
+func readInvitations(c *gin.Context) {
+	// Validate the parameters
+	o, ok := readInvitationOrg(c, role.UserRead)
+	if !ok {
+		return
+	}
+	status := org.InvitationStatus(strings.ToUpper(c.Query("status")))
+	if status != "" && !status.IsValid() {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid status: %s", status))
+		return
+	}
+	// Read and return the Invitations
+	invitations, err := api.InvitationService.ReadAllInvitationsByOrgID(c, o.ID)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   o.ID,
+			EntityType: "Invitation",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read invitations to organization %s: %w", o.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	if status != "" {
+		invitations = v.Filter(invitations, func(i org.Invitation) bool { return i.Status == status })
+	}
+	c.JSON(http.StatusOK, v.Map(invitations, func(i org.Invitation) org.Invitation { return i.Scrub() }))
+}
+
+// revokeInvitation revokes an open Invitation to an Organization.
+//
+// @Summary Revoke Invitation
+// @Description Revoke an Organization Invitation
+// @Description Revoke an open Invitation, so that it may no longer be accepted.
+// @Description Organization administrators may only revoke the Invitations to their own organization.
+// @Tags Invitation
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
+// @Param id path string true "Organization ID"
+// @Param invitation_id path string true "Invitation ID"
+// @Success 200 {object} org.Invitation "Revoked Invitation"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an administrator of the organization)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 409 {object} APIEvent "Conflict (invitation was already accepted or revoked)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/organizations/{id}/invitations/{invitation_id} [delete]
+func revokeInvitation(c *gin.Context) {
+	// Validate the parameters
+	o, ok := readInvitationOrg(c, role.UserWrite)
+	if !ok {
+		return
+	}
+	id := c.Param("invitation_id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	// Read the specified Invitation
+	i, ok := readInvitation(c, id, "revoke")
+	if !ok {
+		return
+	}
+	if i.OrgID != o.ID {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: invitation %s", id))
+		return
+	}
+	// Revoke the Invitation
+	i, err := api.InvitationService.Revoke(c, i)
+	if err != nil && errors.Is(err, org.ErrClosedInvitation) {
+		abortWithError(c, http.StatusConflict, fmt.Errorf("conflict: invitation %s is %s", id, i.Status))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   i.ID,
+			EntityType: i.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("revoke invitation %s: %w", i.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Log the revocation
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   i.ID,
+		EntityType: i.Type(),
+		OtherIDs:   []string{o.ID},
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("revoked Invitation %s for %s to Organization %s", i.ID, i.Email, o.ID),
+		URI:        c.Request.URL.String(),
+	})
+	c.JSON(http.StatusOK, i.Scrub())
+}
+
+// acceptInvitation accepts an Invitation, creating a new User or attaching an existing User to the Organization.
+//
+// @Summary Accept Invitation
+// @Description Accept an Organization Invitation
+// @Description Accept an Invitation with the token from the invitation link. If a User with the invited email
+// @Description address exists, they join the Organization with the granted roles (as a member, if they belong
+// @Description to another Organization). Otherwise, a new User is created with the supplied name and password.
+// @Description Either way, the email address is verified.
+// @Tags Invitation
+// @Accept json
+// @Produce json
+// @Param id path string true "Invitation ID"
+// @Param acceptance body InvitationAcceptance true "Invitation Token, and new User name and password"
+// @Success 200 {object} user.User "Existing User, now a member of the Organization"
+// @Success 201 {object} user.User "Newly-created User"
+// @Failure 400 {object} APIEvent "Bad Request (invalid JSON body or path parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (invalid or expired invitation token)"
+// @Failure 403 {object} APIEvent "Unauthorized (authenticated as a different User)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 409 {object} APIEvent "Conflict (invitation is closed)"
+// @Failure 422 {object} APIEvent "Unprocessable Entity (invalid name or password)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Header 201 {string} Location "URL of the newly created User"
+// @Router /v1/invitations/{id}/accept [post]
+func acceptInvitation(c *gin.Context) {
+	// Validate the parameters
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	var body InvitationAcceptance
+	if err := c.ShouldBindJSON(&body); err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
+		return
+	}
+	// Read the specified Invitation, and check the token
+	i, ok := readInvitation(c, id, "accept")
+	if !ok {
+		return
+	}
+	if !checkInvitationToken(c, i, body.Token) {
+		return
+	}
+	// Read the invited User, if they exist, and check the request
+	status := http.StatusOK
+	u, err := api.UserService.ReadUserByEmail(c, i.Email)
+	if err != nil && !errors.Is(err, v.ErrNotFound) {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			EntityID:   i.ID,
+			EntityType: i.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("accept invitation %s: read user %s: %w", i.ID, i.Email, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	exists := err == nil
+	reg := user.Registration{
+		GivenName:  body.GivenName,
+		FamilyName: body.FamilyName,
+		Email:      i.Email,
+		Password:   body.Password,
+	}
+	if exists {
+		// The invited User must not be anyone else
+		if cUser, ok := contextUser(c); ok && cUser.ID != u.ID {
+			abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: invitation %s is for another user", i.ID))
+			return
+		}
+	} else if problems := reg.Validate(); len(problems) > 0 {
+		abortWithError(c, http.StatusUnprocessableEntity, fmt.Errorf("unprocessable entity: invalid field(s): %s", strings.Join(problems, ", ")))
+		return
+	}
+	// Claim the Invitation before making any changes, so that only one of several concurrent requests
+	// accepts it. If the acceptance fails, the claim is released, and the Invitation remains open.
+	claim, err := api.InvitationService.Claim(c, i, body.Token)
+	if err != nil && errors.Is(err, org.ErrClosedInvitation) {
+		abortWithError(c, http.StatusConflict, fmt.Errorf("conflict: invitation %s is closed", i.ID))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			EntityID:   i.ID,
+			EntityType: i.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("accept invitation %s: %w", i.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	accepted := false
+	defer func() {
+		if !accepted {
+			_ = api.InvitationService.Release(c, claim)
+		}
+	}()
+	// Attach the existing User, or create a new one. A User that belongs to another Organization
+	// keeps it as their primary Organization, and joins this one as a member.
+	var problems []string
+	if exists {
+		if u.OrgID == "" || u.OrgID == i.OrgID {
+			u.OrgID = i.OrgID
+			u.OrgName = i.OrgName
+			u.Roles = mergeRoles(u.Roles, i.Roles)
+		}
+		u.VerifiedEmail = u.Email
+		if u.Status == user.PENDING {
+			u.Status = user.ENABLED
+		}
+		u, problems, err = api.UserService.Update(c, u)
+	} else {
+		u = reg.User()
+		u.OrgID = i.OrgID
+		u.OrgName = i.OrgName
+		u.Roles = i.Roles
+		u.VerifiedEmail = i.Email
+		u.Status = user.ENABLED
+		u, problems, err = api.UserService.Create(c, u)
+		status = http.StatusCreated
+	}
+	if len(problems) > 0 && err != nil {
+		abortWithError(c, http.StatusUnprocessableEntity, fmt.Errorf("unprocessable entity: %w", err))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			OtherIDs:   []string{i.ID},
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("accept invitation %s: save user %s %s: %w", i.ID, u.ID, u.Email, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Join the Organization as a member, if it's not the User's primary Organization
+	if u.OrgID != i.OrgID {
+		m, err := api.UserService.Memberships.ReadMembership(c, u.ID, i.OrgID)
+		if err == nil {
+			m.Roles = mergeRoles(m.Roles, i.Roles)
+			m, _, err = api.UserService.Memberships.Update(c, m)
+		} else if errors.Is(err, v.ErrNotFound) {
+			m, _, err = api.UserService.Memberships.Create(c, user.Membership{
+				UserID:  u.ID,
+				Email:   u.Email,
+				OrgID:   i.OrgID,
+				OrgName: i.OrgName,
+				Roles:   i.Roles,
+			})
+		}
+		if err != nil {
+			e, _, _ := api.EventService.Create(c, event.Event{
+				UserID:     u.ID,
+				EntityID:   i.ID,
+				EntityType: i.Type(),
+				LogLevel:   event.ERROR,
+				Message:    fmt.Errorf("accept invitation %s: save membership in organization %s: %w", i.ID, i.OrgID, err).Error(),
+				URI:        c.Request.URL.String(),
+				Err:        err,
+			})
+			abortWithError(c, http.StatusInternalServerError, e)
+			return
+		}
+	}
+	// Close the Invitation
+	i, err = api.InvitationService.Accept(c, i, body.Token, u.ID)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     u.ID,
+			EntityID:   i.ID,
+			EntityType: i.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("accept invitation %s: %w", i.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	accepted = true
+	// Log the acceptance
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     u.ID,
+		EntityID:   i.ID,
+		EntityType: i.Type(),
+		OtherIDs:   []string{u.ID, i.OrgID},
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("accepted Invitation %s: User %s %s joined Organization %s", i.ID, u.ID, u.Email, i.OrgID),
+		URI:        c.Request.URL.String(),
+	})
+	if status == http.StatusCreated {
+		c.Header("Location", "/v1/users/"+u.ID)
+	}
+	c.JSON(status, u.Scrub())
+}
+
+// readInvitationOrg validates the Organization ID path parameter, checks that the requester has the permission
+// within the Organization, and reads it. The request is aborted, and false returned, if any check fails.
+func readInvitationOrg(c *gin.Context, perm string) (org.Organization, bool) {
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return org.Organization{}, false
+	}
+	if _, scoped := contextOrgScope(c, perm); scoped && !contextPermissions(c).HasInOrg(perm, id) {
+		abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: manage invitations to organization %s", id))
+		return org.Organization{}, false
+	}
+	o, err := api.OrgService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: organization %s", id))
+		return o, false
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "Organization",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read organization %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return o, false
+	}
+	return o, true
+}
+
+// readInvitation reads the specified Invitation. The request is aborted, and false returned, if it fails.
+func readInvitation(c *gin.Context, id string, action string) (org.Invitation, bool) {
+	i, err := api.InvitationService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: invitation %s", id))
+		return i, false
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "Invitation",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("%s invitation: read invitation %s: %w", action, id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return i, false
+	}
+	return i, true
+}
+
+// checkInvitationToken checks that the Invitation may be accepted with the supplied token.
+// The request is aborted, and false returned, if it may not.
+func checkInvitationToken(c *gin.Context, i org.Invitation, token string) bool {
+	err := i.ValidateToken(token, time.Now())
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, org.ErrClosedInvitation):
+		abortWithError(c, http.StatusConflict, fmt.Errorf("conflict: invitation %s is %s", i.ID, i.Status))
+	case errors.Is(err, org.ErrExpiredInvitation):
+		abortWithError(c, http.StatusUnauthorized, errors.New("unauthenticated: expired invitation token"))
+	default:
+		abortWithError(c, http.StatusUnauthorized, errors.New("unauthenticated: invalid invitation token"))
+	}
+	return false
+}
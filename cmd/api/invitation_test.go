@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/voxtechnica/tuid-go"
+
+	"versionary-api/pkg/org"
+	"versionary-api/pkg/user"
+)
+
+// invitationLink matches the invitation ID and token in an invitation email message.
+var invitationLink = regexp.MustCompile(`/invitations/([0-9A-Za-z]+)/([0-9A-Za-z_-]+)`)
+
+// readInvitationToken returns the token from the most recent invitation email sent to the address.
+func readInvitationToken(address, invitationID string) string {
+	emails, err := api.EmailService.ReadEmailsByAddress(context.Background(), address, true, 10, tuid.MaxID)
+	if err != nil {
+		return ""
+	}
+	for _, e := range emails {
+		if m := invitationLink.FindStringSubmatch(e.BodyText); m != nil && m[1] == invitationID {
+			return m[2]
+		}
+	}
+	return ""
+}
+
+func TestInvitations(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	o, orgAdmin, orgAdminToken := generateOrgAdmin("Invitation Org")
+	defer deleteOrgAdmin(o, orgAdmin)
+	call := func(bearer, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "10.0.14.1:1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+	path := "/v1/organizations/" + o.ID + "/invitations"
+	// Regular users may not invite people, and organization administrators may only grant org-scoped roles
+	w := call(regularToken, "POST", path, `{"email": "invited.person@test.com"}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	for _, r := range []string{"admin", "service", "creator"} {
+		w = call(orgAdminToken, "POST", path, `{"email": "invited.person@test.com", "roles": ["`+r+`"]}`)
+		expect.Equal(http.StatusForbidden, w.Code, r)
+	}
+	w = call(orgAdminToken, "POST", "/v1/organizations/"+userOrg.ID+"/invitations", `{"email": "invited.person@test.com"}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call(orgAdminToken, "POST", path, `{"email": "not an email address"}`)
+	expect.Equal(http.StatusUnprocessableEntity, w.Code, "HTTP Status Code")
+	// Invite a new person
+	var invited org.Invitation
+	w = call(orgAdminToken, "POST", path, `{"email": "Invited.Person@test.com", "roles": ["org_admin"]}`)
+	if !expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") ||
+		!expect.NoError(json.NewDecoder(w.Body).Decode(&invited), "Decode JSON Invitation") {
+		return
+	}
+	defer func() { _, _ = api.InvitationService.Delete(ctx, invited.ID) }()
+	expect.Equal("invited.person@test.com", invited.Email)
+	expect.Equal(o.ID, invited.OrgID)
+	expect.Equal(orgAdmin.ID, invited.InviterID)
+	expect.Equal(org.INVITED, invited.Status)
+	expect.Empty(invited.TokenHash)
+	token := readInvitationToken(invited.Email, invited.ID)
+	expect.NotEmpty(token, "Invitation Token")
+	// Invite an existing person, and revoke a third invitation
+	existing, _, err := api.UserService.Create(ctx, user.User{
+		GivenName:  "Existing",
+		FamilyName: "Invitee",
+		Email:      "existing.invitee@test.com",
+		Password:   "existing invitee password",
+		Status:     user.PENDING,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.UserService.Delete(ctx, existing.ID) }()
+	var existingInvitation, revoked org.Invitation
+	w = call(orgAdminToken, "POST", path, `{"email": "existing.invitee@test.com"}`)
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&existingInvitation), "Decode JSON Invitation") {
+		defer func() { _, _ = api.InvitationService.Delete(ctx, existingInvitation.ID) }()
+	}
+	w = call(adminToken, "POST", path, `{"email": "revoked.invitee@test.com"}`)
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&revoked), "Decode JSON Invitation") {
+		defer func() { _, _ = api.InvitationService.Delete(ctx, revoked.ID) }()
+	}
+	w = call(regularToken, "DELETE", path+"/"+revoked.ID, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call(orgAdminToken, "DELETE", path+"/"+revoked.ID, "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	w = call(orgAdminToken, "DELETE", path+"/"+revoked.ID, "")
+	expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+	w = call(orgAdminToken, "DELETE", path+"/"+tuid.NewID().String(), "")
+	expect.Equal(http.StatusNotFound, w.Code, "HTTP Status Code")
+	revokedToken := readInvitationToken(revoked.Email, revoked.ID)
+	w = call("", "POST", "/v1/invitations/"+revoked.ID+"/accept", `{"token": "`+revokedToken+`"}`)
+	expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+	// List the organization's invitations
+	var invitations []org.Invitation
+	w = call(orgAdminToken, "GET", path+"?status=INVITED", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&invitations), "Decode JSON Invitations") {
+		ids := make([]string, 0, len(invitations))
+		for _, i := range invitations {
+			ids = append(ids, i.ID)
+			expect.Empty(i.TokenHash)
+		}
+		expect.ElementsMatch([]string{invited.ID, existingInvitation.ID}, ids)
+	}
+	w = call(regularToken, "GET", path, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Accept the invitation as a new User
+	w = call("", "POST", "/v1/invitations/"+invited.ID+"/accept", `{"token": "wrong"}`)
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	w = call("", "POST", "/v1/invitations/"+invited.ID+"/accept", `{"token": "`+token+`", "password": "short"}`)
+	expect.Equal(http.StatusUnprocessableEntity, w.Code, "HTTP Status Code")
+	var u user.User
+	w = call("", "POST", "/v1/invitations/"+invited.ID+"/accept",
+		`{"token": "`+token+`", "givenName": "Invited", "familyName": "Person", "password": "invited person password"}`)
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&u), "Decode JSON User") {
+		defer func() { _, _ = api.UserService.Delete(ctx, u.ID) }()
+		expect.Equal(invited.Email, u.Email)
+		expect.Equal(o.ID, u.OrgID)
+		expect.Equal(o.Name, u.OrgName)
+		expect.Equal([]string{user.OrgAdminRole}, u.Roles)
+		expect.Equal(user.ENABLED, u.Status)
+		expect.True(u.EmailVerified())
+	}
+	// Invitations may only be accepted once
+	w = call("", "POST", "/v1/invitations/"+invited.ID+"/accept", `{"token": "`+token+`"}`)
+	expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+	if i, err := api.InvitationService.Read(ctx, invited.ID); expect.NoError(err) {
+		expect.Equal(org.ACCEPTED, i.Status)
+		expect.Equal(u.ID, i.AcceptedBy)
+	}
+	// An invitation being accepted by a concurrent request may not be accepted again, and is not applied
+	existingToken := readInvitationToken(existingInvitation.Email, existingInvitation.ID)
+	stored, _ := api.InvitationService.Read(ctx, existingInvitation.ID)
+	if claim, err := api.InvitationService.Claim(ctx, stored, existingToken); expect.NoError(err) {
+		w = call("", "POST", "/v1/invitations/"+existingInvitation.ID+"/accept", `{"token": "`+existingToken+`"}`)
+		expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+		if u, err := api.UserService.Read(ctx, existing.ID); expect.NoError(err) {
+			expect.Empty(u.OrgID)
+			expect.Equal(user.PENDING, u.Status)
+		}
+		expect.NoError(api.InvitationService.Release(ctx, claim))
+	}
+	// Accept the invitation as an existing User, who may not be impersonated
+	w = call(regularToken, "POST", "/v1/invitations/"+existingInvitation.ID+"/accept", `{"token": "`+existingToken+`"}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call("", "POST", "/v1/invitations/"+existingInvitation.ID+"/accept", `{"token": "`+existingToken+`"}`)
+	var joined user.User
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&joined), "Decode JSON User") {
+		expect.Equal(existing.ID, joined.ID)
+		expect.Equal(o.ID, joined.OrgID)
+		expect.Equal(user.ENABLED, joined.Status)
+		expect.True(joined.EmailVerified())
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/job"
+	"versionary-api/pkg/role"
+)
+
+// registerJobRoutes initializes the Job routes.
+func registerJobRoutes(r *gin.Engine) {
+	handleRoutes(r, []route{
+		{"GET", "/v1/jobs", role.JobRead, global, readJobs},
+		{"GET", "/v1/jobs/:id", authenticated, global, readJob},
+		{"GET", "/v1/organizations/:id/jobs", role.OrganizationRead, orgScoped, readOrganizationJobs},
+	})
+}
+
+// canReadJob returns true if the requester may follow the progress of the Job: either they started it,
+// or they may read Jobs, or it's about an Organization they manage.
+func canReadJob(c *gin.Context, j job.Job) bool {
+	p := contextPermissions(c)
+	return j.UserID == contextUserID(c) || p.Has(role.JobRead) ||
+		(j.EntityType == "Organization" && p.HasInOrg(role.OrganizationRead, j.EntityID))
+}
+
+// readJobs returns a paginated list of Jobs.
+//
+// @Summary List Jobs
+// @Description List Jobs
+// @Description List recent background Jobs (retained for 30 days), paging with reverse, limit, and offset.
+// @Tags Job
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param reverse query bool false "Reverse Order (default: false)"
+// @Param limit query int false "Limit (default: 100)"
+// @Param offset query string false "Offset (default: forward/reverse alphanumeric)"
+// @Success 200 {array} job.Job "Jobs"
+// @Failure 400 {object} APIEvent "Bad Request (invalid parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Router /v1/jobs [get]
+func readJobs(c *gin.Context) {
+	reverse, limit, offset, err := paginationParams(c, false, 100)
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, api.JobService.ReadJobs(c, reverse, limit, offset))
+}
+
+// readJob returns the specified Job, including its progress.
+//
+// @Summary Read Job
+// @Description Get Job
+// @Description Get a background Job by ID, to follow its progress. Users may read the Jobs they started,
+// @Description and organization administrators may read the Jobs about their organization.
+// @Tags Job
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token"
+// @Param id path string true "Job ID"
+// @Success 200 {object} job.Job "Job"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not the User that started the Job, or an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/jobs/{id} [get]
+func readJob(c *gin.Context) {
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	j, err := api.JobService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: job %s", id))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "Job",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read job %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	if !canReadJob(c, j) {
+		abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: read job %s", id))
+		return
+	}
+	c.JSON(http.StatusOK, j)
+}
+
+// readOrganizationJobs returns the Jobs about the specified Organization (e.g. propagating a rename).
+//
+// @Summary List Organization Jobs
+// @Description List Organization Jobs
+// @Description List the recent background Jobs about an Organization, in chronological order.
+// @Description Organization administrators may only list the Jobs about their own organization.
+// @Tags Job
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
+// @Param id path string true "Organization ID"
+// @Success 200 {array} job.Job "Jobs"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an administrator of the organization)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/organizations/{id}/jobs [get]
+func readOrganizationJobs(c *gin.Context) {
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	if _, scoped := contextOrgScope(c, role.OrganizationRead); scoped && !contextPermissions(c).HasInOrg(role.OrganizationRead, id) {
+		abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: read jobs about organization %s", id))
+		return
+	}
+	jobs, err := api.JobService.ReadAllJobsByEntityID(c, id)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "Job",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read jobs about organization %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
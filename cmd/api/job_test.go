@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"versionary-api/pkg/job"
+	"versionary-api/pkg/org"
+	"versionary-api/pkg/token"
+	"versionary-api/pkg/user"
+)
+
+func TestOrgRenameJob(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	o, orgAdmin, orgAdminToken := generateOrgAdmin("Rename Org")
+	defer deleteOrgAdmin(o, orgAdmin)
+	call := func(bearer, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+bearer)
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "10.0.16.1:1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+	// A member from another organization, and a pending invitation
+	m, _, err := api.UserService.Memberships.Create(ctx, user.Membership{
+		UserID:  regularUser.ID,
+		Email:   regularUser.Email,
+		OrgID:   o.ID,
+		OrgName: o.Name,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.UserService.Memberships.Delete(ctx, m.ID) }()
+	i, _, _, err := api.InvitationService.Create(ctx, org.Invitation{
+		OrgID:     o.ID,
+		OrgName:   o.Name,
+		Email:     "rename.invitee@test.com",
+		InviterID: orgAdmin.ID,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.InvitationService.Delete(ctx, i.ID) }()
+
+	// The organization administrator renames the organization
+	w := call(orgAdminToken, "PUT", "/v1/organizations/"+o.ID, `{"id": "`+o.ID+`", "name": "Renamed Org"}`)
+	if !expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		return
+	}
+	jobID := w.Header().Get("X-Job-ID")
+	if !expect.NotEmpty(jobID, "X-Job-ID header") {
+		return
+	}
+	defer func() { _, _ = api.JobService.Delete(ctx, jobID) }()
+	api.WaitForJobs()
+
+	// The organization administrator follows the progress of the Job
+	var j job.Job
+	w = call(orgAdminToken, "GET", "/v1/jobs/"+jobID, "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&j), "Decode JSON Job") {
+		expect.Equal(job.OrgRename, j.Kind)
+		expect.Equal(job.SUCCEEDED, j.Status)
+		expect.Equal(o.ID, j.EntityID)
+		expect.Equal(3, j.Processed)
+		expect.Equal(3, j.Changed)
+	}
+	w = call(regularToken, "GET", "/v1/jobs/"+jobID, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	var jobs []job.Job
+	w = call(orgAdminToken, "GET", "/v1/organizations/"+o.ID+"/jobs", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&jobs), "Decode JSON Jobs") &&
+		expect.Len(jobs, 1) {
+		expect.Equal(jobID, jobs[0].ID)
+	}
+	w = call(regularToken, "GET", "/v1/organizations/"+o.ID+"/jobs", "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call(orgAdminToken, "GET", "/v1/jobs", "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call(adminToken, "GET", "/v1/jobs", "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+
+	// The denormalized names have been updated
+	if u, err := api.UserService.Read(ctx, orgAdmin.ID); expect.NoError(err) {
+		expect.Equal("Renamed Org", u.OrgName)
+	}
+	if check, err := api.UserService.Memberships.Read(ctx, m.ID); expect.NoError(err) {
+		expect.Equal("Renamed Org", check.OrgName)
+	}
+	if check, err := api.InvitationService.Read(ctx, i.ID); expect.NoError(err) {
+		expect.Equal("Renamed Org", check.OrgName)
+	}
+	if orgs, err := api.UserService.ReadAllOrgs(ctx, false); expect.NoError(err) {
+		for _, tv := range orgs {
+			if tv.Key == o.ID {
+				expect.Equal("Renamed Org", tv.Value)
+			}
+		}
+	}
+
+	// Renaming without a change of name doesn't start a Job
+	w = call(orgAdminToken, "PUT", "/v1/organizations/"+o.ID, `{"id": "`+o.ID+`", "name": "Renamed Org"}`)
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	expect.Empty(w.Header().Get("X-Job-ID"), "X-Job-ID header")
+}
+
+func TestSyncNames(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	// A token with a stale email address
+	tk, err := api.TokenService.Create(ctx, token.Token{UserID: regularUser.ID, Email: "stale.regular@test.com"})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.TokenService.Delete(ctx, tk.ID) }()
+
+	// Find, but don't fix, the stale names
+	j, _, err := api.JobService.Create(ctx, job.Job{Kind: job.Consistency})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.JobService.Delete(ctx, j.ID) }()
+	j = api.SyncNames(ctx, j, false)
+	expect.Equal(job.SUCCEEDED, j.Status)
+	expect.Greater(j.Processed, 0)
+	expect.Equal(1, j.Stale)
+	expect.Equal(0, j.Changed)
+	if check, err := api.TokenService.Read(ctx, tk.ID); expect.NoError(err) {
+		expect.Equal("stale.regular@test.com", check.Email)
+	}
+
+	// Find and fix the stale names
+	j, _, err = api.JobService.Create(ctx, job.Job{Kind: job.Consistency})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.JobService.Delete(ctx, j.ID) }()
+	j = api.SyncNames(ctx, j, true)
+	expect.Equal(job.SUCCEEDED, j.Status)
+	expect.Equal(1, j.Stale)
+	expect.Equal(1, j.Changed)
+	if check, err := api.TokenService.Read(ctx, tk.ID); expect.NoError(err) {
+		expect.Equal(regularUser.Email, check.Email)
+	}
+}
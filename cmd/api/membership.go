@@ -0,0 +1,350 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/org"
+	"versionary-api/pkg/role"
+	"versionary-api/pkg/user"
+)
+
+// registerMembershipRoutes initializes the Membership routes.
+func registerMembershipRoutes(r *gin.Engine) {
+	handleRoutes(r, []route{
+		{"GET", "/v1/users/:id/memberships", authenticated, global, readMemberships},
+		{"PUT", "/v1/users/:id/memberships/:org_id", role.UserWrite, orgScoped, updateMembership},
+		{"DELETE", "/v1/users/:id/memberships/:org_id", authenticated, global, deleteMembership},
+	})
+}
+
+// MembershipRequest is the request body for adding a User to an Organization, or changing their roles within it.
+type MembershipRequest struct {
+	Roles []string `json:"roles,omitempty"`
+}
+
+// canManageMember returns true if the requester has the permission for the specified User's Membership in an
+// Organization: either the permission is granted globally, or it's granted within that Organization and the
+// User is not an administrator.
+func canManageMember(c *gin.Context, perm string, u user.User, orgID string) bool {
+	p := contextPermissions(c)
+	return p.Has(perm) || (p.HasInOrg(perm, orgID) && !u.HasRole(role.Admin))
+}
+
+// canAddMember returns true if the requester may add the specified User to an Organization directly, without
+// their consent: either the user:write permission is granted globally, or the User's primary Organization is
+// within the requester's scope (their Organization, or one of its descendants). Other Users must be invited,
+// and join by accepting the Invitation.
+func canAddMember(c *gin.Context, u user.User) bool {
+	p := contextPermissions(c)
+	return p.Has(role.UserWrite) || p.InScope(u.OrgID)
+}
+
+// mergeRoles returns the roles, with any additional roles appended.
+func mergeRoles(roles, additional []string) []string {
+	merged := append([]string{}, roles...)
+	for _, r := range additional {
+		if !v.Contains(merged, r) {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
+
+// readMemberships returns the specified User's Organization Memberships.
+//
+// @Summary List User Memberships
+// @Description List User Organization Memberships
+// @Description List the Organizations to which the specified User belongs, with their roles in each, sorted by
+// @Description Organization ID. The primary Membership reflects the User's own Organization and roles.
+// @Description Users may list their own Memberships; administrators may list any User's Memberships.
+// @Tags User
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token"
+// @Param id path string true "User ID"
+// @Success 200 {array} user.Membership "Memberships"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not the specified User or an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/memberships [get]
+func readMemberships(c *gin.Context) {
+	u, ok := readMemberUser(c)
+	if !ok {
+		return
+	}
+	if u.ID != contextUserID(c) && !canManageUser(c, role.UserRead, u) {
+		abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: read memberships of user %s", u.ID))
+		return
+	}
+	memberships, err := api.UserService.Memberships.ReadAllMembershipsByUserID(c, u.ID)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   u.ID,
+			EntityType: api.UserService.Memberships.EntityType,
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read memberships of user %s: %w", u.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	c.JSON(http.StatusOK, memberships)
+}
+
+// updateMembership adds a User to an Organization, or changes their roles within it.
+//
+// @Summary Update User Membership
+// @Description Add a User to an Organization
+// @Description Add the specified User to an Organization, in addition to their primary Organization, or replace
+// @Description their roles within it. The roles apply only within the Organization. Organization administrators
+// @Description may only manage Memberships in their own organization, and may only add Users who already belong
+// @Description to it (or its descendants); other Users must accept an Invitation. The User's primary Membership
+// @Description is changed by updating the User.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
+// @Param id path string true "User ID"
+// @Param org_id path string true "Organization ID"
+// @Param membership body MembershipRequest true "Roles within the Organization"
+// @Success 200 {object} user.Membership "Updated Membership"
+// @Success 201 {object} user.Membership "Newly-created Membership"
+// @Failure 400 {object} APIEvent "Bad Request (invalid JSON body or path parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an administrator of the organization, role may not be granted, or user must be invited)"
+// @Failure 404 {object} APIEvent "Not Found (user or organization)"
+// @Failure 409 {object} APIEvent "Conflict (primary organization)"
+// @Failure 422 {object} APIEvent "Membership validation errors"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Header 201 {string} Location "URL of the User's Memberships"
+// @Router /v1/users/{id}/memberships/{org_id} [put]
+func updateMembership(c *gin.Context) {
+	// Validate the parameters and the requester's authority
+	u, ok := readMemberUser(c)
+	if !ok {
+		return
+	}
+	o, ok := readMemberOrg(c)
+	if !ok {
+		return
+	}
+	if !canManageMember(c, role.UserWrite, u, o.ID) {
+		abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: manage membership of user %s in organization %s", u.ID, o.ID))
+		return
+	}
+	var body MembershipRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
+		return
+	}
+	for _, r := range body.Roles {
+		if !canGrantRole(c, r) {
+			abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: grant role %s", r))
+			return
+		}
+	}
+	if u.OrgID == o.ID {
+		abortWithError(c, http.StatusConflict, fmt.Errorf("conflict: %w: %s", user.ErrPrimaryMembership, o.ID))
+		return
+	}
+	// Create or update the Membership
+	status := http.StatusOK
+	m, err := api.UserService.Memberships.ReadMembership(c, u.ID, o.ID)
+	var problems []string
+	if err == nil {
+		m.Email, m.OrgName, m.Roles = u.Email, o.Name, body.Roles
+		m, problems, err = api.UserService.Memberships.Update(c, m)
+	} else if errors.Is(err, v.ErrNotFound) {
+		// Users outside the requester's scope join only with their consent, by accepting an Invitation
+		if !canAddMember(c, u) {
+			abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: add user %s to organization %s: invite them instead", u.ID, o.ID))
+			return
+		}
+		status = http.StatusCreated
+		m, problems, err = api.UserService.Memberships.Create(c, user.Membership{
+			UserID:  u.ID,
+			Email:   u.Email,
+			OrgID:   o.ID,
+			OrgName: o.Name,
+			Roles:   body.Roles,
+		})
+	}
+	if len(problems) > 0 && err != nil {
+		abortWithError(c, http.StatusUnprocessableEntity, fmt.Errorf("unprocessable entity: %w", err))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   m.ID,
+			EntityType: m.Type(),
+			OtherIDs:   []string{u.ID, o.ID},
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("save membership of user %s in organization %s: %w", u.ID, o.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Log the change
+	action := "updated"
+	if status == http.StatusCreated {
+		action = "created"
+	}
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   m.ID,
+		EntityType: m.Type(),
+		OtherIDs:   []string{u.ID, o.ID},
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("%s Membership %s of User %s in Organization %s", action, m.ID, u.ID, o.ID),
+		URI:        c.Request.URL.String(),
+	})
+	if status == http.StatusCreated {
+		c.Header("Location", "/v1/users/"+u.ID+"/memberships")
+	}
+	c.JSON(status, m)
+}
+
+// deleteMembership removes a User from an Organization other than their primary Organization.
+//
+// @Summary Delete User Membership
+// @Description Remove a User from an Organization
+// @Description Remove the specified User from an Organization other than their primary Organization.
+// @Description Users may leave an Organization; administrators may remove a User from their own organization.
+// @Tags User
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token"
+// @Param id path string true "User ID"
+// @Param org_id path string true "Organization ID"
+// @Success 200 {object} user.Membership "Deleted Membership"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not the specified User or an administrator of the organization)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 409 {object} APIEvent "Conflict (primary organization)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/memberships/{org_id} [delete]
+func deleteMembership(c *gin.Context) {
+	// Validate the parameters and the requester's authority
+	u, ok := readMemberUser(c)
+	if !ok {
+		return
+	}
+	orgID := c.Param("org_id")
+	if !tuid.IsValid(tuid.TUID(orgID)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter org_id: %s", orgID))
+		return
+	}
+	if u.ID != contextUserID(c) && !canManageMember(c, role.UserWrite, u, orgID) {
+		abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: remove user %s from organization %s", u.ID, orgID))
+		return
+	}
+	if u.OrgID == orgID {
+		abortWithError(c, http.StatusConflict, fmt.Errorf("conflict: %w: %s", user.ErrPrimaryMembership, orgID))
+		return
+	}
+	// Delete the Membership
+	m, err := api.UserService.Memberships.ReadMembership(c, u.ID, orgID)
+	if err == nil {
+		m, err = api.UserService.Memberships.Delete(c, m.ID)
+	}
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: membership of user %s in organization %s", u.ID, orgID))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   m.ID,
+			EntityType: api.UserService.Memberships.EntityType,
+			OtherIDs:   []string{u.ID, orgID},
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("delete membership of user %s in organization %s: %w", u.ID, orgID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Log the deletion
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   m.ID,
+		EntityType: m.Type(),
+		OtherIDs:   []string{u.ID, orgID},
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("deleted Membership %s of User %s in Organization %s", m.ID, u.ID, orgID),
+		URI:        c.Request.URL.String(),
+	})
+	c.JSON(http.StatusOK, m)
+}
+
+// readMemberUser reads the User specified by the path parameter ID. The request is aborted, and false returned,
+// if it fails.
+func readMemberUser(c *gin.Context) (user.User, bool) {
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return user.User{}, false
+	}
+	u, err := api.UserService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: user %s", id))
+		return u, false
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "User",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read user %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return u, false
+	}
+	return u, true
+}
+
+// readMemberOrg reads the Organization specified by the path parameter org_id. The request is aborted,
+// and false returned, if it fails.
+func readMemberOrg(c *gin.Context) (org.Organization, bool) {
+	id := c.Param("org_id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter org_id: %s", id))
+		return org.Organization{}, false
+	}
+	o, err := api.OrgService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: organization %s", id))
+		return o, false
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "Organization",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read organization %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return o, false
+	}
+	return o, true
+}
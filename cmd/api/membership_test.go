@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"versionary-api/pkg/org"
+	"versionary-api/pkg/token"
+	"versionary-api/pkg/user"
+)
+
+func TestMemberships(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	home, homeAdmin, homeAdminToken := generateOrgAdmin("Membership Home Org")
+	defer deleteOrgAdmin(home, homeAdmin)
+	client, clientAdmin, clientAdminToken := generateOrgAdmin("Membership Client Org")
+	defer deleteOrgAdmin(client, clientAdmin)
+	call := func(bearer, orgID, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		if orgID != "" {
+			req.Header.Set(OrganizationHeader, orgID)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "10.0.15.1:1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+	// A consultant belongs to their home organization, without any roles
+	consultant, _, err := api.UserService.Create(ctx, user.User{
+		GivenName:  "Membership",
+		FamilyName: "Consultant",
+		Email:      "membership.consultant@test.com",
+		Password:   "consultant password",
+		OrgID:      home.ID,
+		OrgName:    home.Name,
+		Status:     user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() {
+		_ = api.TokenService.DeleteAllTokensByUserID(ctx, consultant.ID)
+		_, _ = api.UserService.Delete(ctx, consultant.ID)
+	}()
+	consultantToken, err := api.TokenService.Create(ctx, token.Token{UserID: consultant.ID, Email: consultant.Email})
+	if !expect.NoError(err) {
+		return
+	}
+	path := "/v1/users/" + consultant.ID + "/memberships/" + client.ID
+	// Only administrators of the client organization may add the consultant to it
+	w := call(homeAdminToken, "", "PUT", path, `{"roles": ["org_admin"]}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	for _, r := range []string{"admin", "service", "creator"} {
+		w = call(clientAdminToken, "", "PUT", path, `{"roles": ["`+r+`"]}`)
+		expect.Equal(http.StatusForbidden, w.Code, r)
+	}
+	w = call(homeAdminToken, "", "PUT", "/v1/users/"+consultant.ID+"/memberships/"+home.ID, `{"roles": ["org_admin"]}`)
+	expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+	// Users outside the organization must consent, by accepting an invitation; administrators may add them
+	w = call(clientAdminToken, "", "PUT", path, `{"roles": ["org_admin"]}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	expect.Contains(w.Body.String(), "invite them instead")
+	var m user.Membership
+	w = call(adminToken, "", "PUT", path, `{"roles": ["org_admin"]}`)
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&m), "Decode JSON Membership") {
+		expect.Equal(consultant.ID, m.UserID)
+		expect.Equal(client.ID, m.OrgID)
+		expect.Equal(client.Name, m.OrgName)
+		expect.Equal([]string{user.OrgAdminRole}, m.Roles)
+		expect.False(m.Primary)
+	}
+	w = call(clientAdminToken, "", "PUT", path, `{"roles": ["org_admin"]}`)
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	// The consultant belongs to both organizations
+	var memberships []user.Membership
+	w = call(consultantToken.ID, "", "GET", "/v1/users/"+consultant.ID+"/memberships", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&memberships), "Decode JSON Memberships") {
+		orgIDs := make([]string, 0, len(memberships))
+		for _, e := range memberships {
+			orgIDs = append(orgIDs, e.OrgID)
+		}
+		expect.ElementsMatch([]string{home.ID, client.ID}, orgIDs)
+	}
+	w = call(regularToken, "", "GET", "/v1/users/"+consultant.ID+"/memberships", "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	var users []user.User
+	w = call(clientAdminToken, "", "GET", "/v1/users", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&users), "Decode JSON Users") {
+		ids := make([]string, 0, len(users))
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		expect.ElementsMatch([]string{clientAdmin.ID, consultant.ID}, ids)
+	}
+	// The consultant's roles apply only in the selected organization
+	w = call(consultantToken.ID, "", "GET", "/v1/users", "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call(consultantToken.ID, client.ID, "GET", "/v1/users", "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	w = call(consultantToken.ID, userOrg.ID, "GET", "/v1/users", "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call(consultantToken.ID, "not-an-org", "GET", "/v1/users", "")
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	// Tokens may select the active organization
+	w = call("", "", "POST", "/v1/tokens", `{"username": "membership.consultant@test.com", "password": "consultant password", "orgId": "`+userOrg.ID+`"}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	var res token.Response
+	w = call("", "", "POST", "/v1/tokens", `{"username": "membership.consultant@test.com", "password": "consultant password", "orgId": "`+client.ID+`"}`)
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&res), "Decode JSON Token Response") {
+		if t, err := api.TokenService.Read(ctx, res.AccessToken); expect.NoError(err) {
+			expect.Equal(client.ID, t.OrgID)
+		}
+		w = call(res.AccessToken, "", "GET", "/v1/users", "")
+		expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+		w = call(res.AccessToken, home.ID, "GET", "/v1/users", "")
+		expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	}
+	// JWTs embed the membership in the selected organization
+	api.JWTAlgorithm = token.AlgEdDSA
+	w = call("", "", "POST", "/v1/tokens", `{"username": "membership.consultant@test.com", "password": "consultant password", "orgId": "`+client.ID+`"}`)
+	api.JWTAlgorithm = ""
+	var jwt token.Response
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&jwt), "Decode JSON Token Response") {
+		if claims, err := api.VerifyJWT(ctx, jwt.AccessToken); expect.NoError(err) {
+			expect.Equal(client.ID, claims.ActiveOrg)
+			expect.Equal(client.Name, claims.OrgName)
+			expect.Equal([]string{user.OrgAdminRole}, claims.OrgRoles)
+		}
+	}
+	// Consultants may leave an organization, but not their primary organization
+	w = call(consultantToken.ID, "", "DELETE", "/v1/users/"+consultant.ID+"/memberships/"+home.ID, "")
+	expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+	w = call(regularToken, "", "DELETE", path, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call(consultantToken.ID, "", "DELETE", path, "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	w = call(consultantToken.ID, "", "DELETE", path, "")
+	expect.Equal(http.StatusNotFound, w.Code, "HTTP Status Code")
+	w = call(consultantToken.ID, client.ID, "GET", "/v1/users", "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// The membership embedded in a JWT is not read from the database, so it applies until the JWT expires
+	api.JWTAlgorithm = token.AlgEdDSA
+	w = call(jwt.AccessToken, "", "GET", "/v1/users", "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	w = call(jwt.AccessToken, home.ID, "GET", "/v1/users", "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	api.JWTAlgorithm = ""
+	// Accepting an invitation to another organization adds a membership
+	var i org.Invitation
+	w = call(clientAdminToken, "", "POST", "/v1/organizations/"+client.ID+"/invitations", `{"email": "membership.consultant@test.com", "roles": ["org_admin"]}`)
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&i), "Decode JSON Invitation") {
+		defer func() { _, _ = api.InvitationService.Delete(ctx, i.ID) }()
+		w = call("", "", "POST", "/v1/invitations/"+i.ID+"/accept", `{"token": "`+readInvitationToken(i.Email, i.ID)+`"}`)
+		var joined user.User
+		if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+			expect.NoError(json.NewDecoder(w.Body).Decode(&joined), "Decode JSON User") {
+			expect.Equal(home.ID, joined.OrgID)
+		}
+		if m, err := api.UserService.Memberships.ReadMembership(ctx, consultant.ID, client.ID); expect.NoError(err) {
+			expect.Equal([]string{user.OrgAdminRole}, m.Roles)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 	"versionary-api/pkg/event"
 	"versionary-api/pkg/metric"
+	"versionary-api/pkg/role"
 
 	"github.com/gin-gonic/gin"
 	"github.com/voxtechnica/tuid-go"
@@ -16,16 +17,18 @@ import (
 
 // registerMetricRoutes initializes the Metric routes with the Gin router.
 func registerMetricRoutes(r *gin.Engine) {
-	r.POST("/v1/metrics", roleAuthorizer("admin"), createMetric)
-	r.GET("/v1/metrics", roleAuthorizer("admin"), readMetrics)
-	r.GET("/v1/metrics/:id", readMetric)
-	r.HEAD("/v1/metrics/:id", existsMetric)
-	r.DELETE("/v1/metrics/:id", roleAuthorizer("admin"), deleteMetric)
-	r.GET("/v1/metric_labels", roleAuthorizer("admin"), readMetricLabels)
-	r.GET("/v1/metric_entity_ids", roleAuthorizer("admin"), readMetricEntityIDs)
-	r.GET("/v1/metric_entity_types", roleAuthorizer("admin"), readMetricEntityTypes)
-	r.GET("/v1/metric_tags", roleAuthorizer("admin"), readMetricTags)
-	r.GET("/v1/metric_stats", roleAuthorizer("admin"), readMetricStats)
+	handleRoutes(r, []route{
+		{"POST", "/v1/metrics", role.MetricWrite, global, createMetric},
+		{"GET", "/v1/metrics", role.MetricRead, global, readMetrics},
+		{"GET", "/v1/metrics/:id", public, global, readMetric},
+		{"HEAD", "/v1/metrics/:id", public, global, existsMetric},
+		{"DELETE", "/v1/metrics/:id", role.MetricWrite, global, deleteMetric},
+		{"GET", "/v1/metric_labels", role.MetricRead, global, readMetricLabels},
+		{"GET", "/v1/metric_entity_ids", role.MetricRead, global, readMetricEntityIDs},
+		{"GET", "/v1/metric_entity_types", role.MetricRead, global, readMetricEntityTypes},
+		{"GET", "/v1/metric_tags", role.MetricRead, global, readMetricTags},
+		{"GET", "/v1/metric_stats", role.MetricRead, global, readMetricStats},
+	})
 }
 
 // createMetric handles the HTTP request to create a new Metric.
@@ -0,0 +1,468 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+
+	"versionary-api/pkg/apikey"
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/role"
+	"versionary-api/pkg/token"
+	"versionary-api/pkg/user"
+)
+
+// registerOAuthRoutes initializes the standards-compliant OAuth 2.0 routes.
+func registerOAuthRoutes(r *gin.Engine) {
+	handleRoutes(r, []route{
+		{"POST", "/oauth/token", public, global, oauthToken},
+		{"POST", "/oauth/revoke", public, global, oauthRevoke},
+		{"POST", "/oauth/introspect", public, global, oauthIntrospect},
+		{"GET", "/.well-known/jwks.json", public, global, readJWKS},
+	})
+}
+
+// oauthToken implements a standards-compliant OAuth 2.0 token endpoint (RFC 6749), supporting the
+// "password", "client_credentials", and "refresh_token" grants. Client credentials are an APIKey ID
+// and secret, supplied with HTTP Basic authentication or as form parameters.
+//
+// @Summary OAuth 2.0 Token
+// @Description OAuth 2.0 Token Endpoint (RFC 6749)
+// @Description Create a new OAuth Bearer Token, using the "password", "client_credentials", or "refresh_token" grant.
+// @Description The password grant accepts an extension parameter "code" for Users enrolled in TOTP.
+// @Description The client_credentials grant authenticates a service account with an APIKey ID (client_id)
+// @Description and secret (client_secret); the access Token carries the scopes of the APIKey.
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "Grant Type" Enums(password, client_credentials, refresh_token)
+// @Param username formData string false "Email or User ID (password grant)"
+// @Param password formData string false "Password (password grant)"
+// @Param code formData string false "TOTP or recovery code (password grant, if enrolled)"
+// @Param refresh_token formData string false "Refresh Token (refresh_token grant)"
+// @Param scope formData string false "Space-delimited scopes (client_credentials grant)"
+// @Param client_id formData string false "APIKey ID (client_credentials grant)"
+// @Param client_secret formData string false "APIKey secret (client_credentials grant)"
+// @Success 200 {object} token.OAuthResponse "Token Response"
+// @Failure 400 {object} token.OAuthError "Bad Request (invalid_request, invalid_grant, invalid_scope, unsupported_grant_type)"
+// @Failure 401 {object} token.OAuthError "Unauthenticated (invalid_client)"
+// @Failure 429 {object} token.OAuthError "Too Many Requests (too many failed login attempts; see Retry-After)"
+// @Failure 500 {object} token.OAuthError "Internal Server Error (server_error)"
+// @Router /oauth/token [post]
+func oauthToken(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	// Parse the request body as an OAuth 2.0 Access Token Request
+	var req token.OAuthRequest
+	if !strings.HasPrefix(c.ContentType(), binding.MIMEPOSTForm) {
+		abortWithOAuthError(c, newGrantError(http.StatusBadRequest, token.ErrCodeInvalidRequest,
+			errors.New("bad request: expecting Content-Type "+binding.MIMEPOSTForm)))
+		return
+	}
+	if err := c.ShouldBindWith(&req, binding.FormPost); err != nil {
+		abortWithOAuthError(c, newGrantError(http.StatusBadRequest, token.ErrCodeInvalidRequest,
+			fmt.Errorf("bad request: invalid form body: %w", err)))
+		return
+	}
+	var t token.Token
+	var rt token.RefreshToken
+	var scopes []string
+	var ge *grantError
+	switch req.GrantType {
+	case token.GrantPassword:
+		t, rt, ge = passwordGrant(c, req.Username, req.Password, req.Code, req.DeviceID, req.OrgID)
+	case token.GrantRefreshToken:
+		t, rt, ge = refreshTokenGrant(c, req.RefreshToken)
+	case token.GrantClientCredentials:
+		var k apikey.APIKey
+		t, k, ge = clientCredentialsGrant(c, req)
+		scopes = k.Scopes
+	case "":
+		ge = newGrantError(http.StatusBadRequest, token.ErrCodeInvalidRequest, errors.New("bad request: missing grant_type"))
+	default:
+		ge = newGrantError(http.StatusBadRequest, token.ErrCodeUnsupportedGrantType,
+			fmt.Errorf("bad request: unsupported grant type %s", req.GrantType))
+	}
+	if ge != nil {
+		abortWithOAuthError(c, ge)
+		return
+	}
+	accessToken, ge := signAccessToken(c, t, scopes)
+	if ge != nil {
+		abortWithOAuthError(c, ge)
+		return
+	}
+	res := token.NewOAuthResponse(t, rt, scopes, time.Now())
+	res.AccessToken = accessToken
+	c.JSON(http.StatusOK, res)
+}
+
+// clientCredentialsGrant authenticates an OAuth client (a service account APIKey), and creates a new
+// short-lived access Token carrying the scopes of the APIKey. No RefreshToken is issued (RFC 6749 4.4.3).
+func clientCredentialsGrant(c *gin.Context, req token.OAuthRequest) (token.Token, apikey.APIKey, *grantError) {
+	k, u, ge := oauthClient(c, req.ClientID, req.ClientSecret)
+	if ge != nil {
+		return token.Token{}, k, ge
+	}
+	// The requested scopes, if any, must be granted by the APIKey
+	for _, scope := range strings.Fields(req.Scope) {
+		if !k.HasScope(scope) {
+			return token.Token{}, k, newGrantError(http.StatusBadRequest, token.ErrCodeInvalidScope,
+				fmt.Errorf("bad request: scope %s is not granted to client %s", scope, k.ID))
+		}
+	}
+	// Create a new token for the service account
+	t, err := api.TokenService.CreateWithLifetime(c, token.Token{
+		UserID:   u.ID,
+		Email:    u.Email,
+		APIKeyID: k.ID,
+		ClientIP: c.ClientIP(),
+	}, token.AccessTokenLifetime)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     u.ID,
+			EntityID:   t.ID,
+			EntityType: "Token",
+			OtherIDs:   []string{k.ID},
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("create token for %s with APIKey %s: %w", u.ID, k.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		return t, k, newGrantError(http.StatusInternalServerError, token.ErrCodeServerError, e)
+	}
+	// Log the token creation
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     t.UserID,
+		EntityID:   t.ID,
+		EntityType: t.Type(),
+		OtherIDs:   []string{k.ID},
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("created Token %s for User %s with APIKey %s", t.ID, u.ID, k.ID),
+		URI:        c.Request.URL.String(),
+	})
+	return t, k, nil
+}
+
+// oauthClient authenticates an OAuth client: a service account APIKey. The client credentials are read
+// from the HTTP Basic Authorization header (RFC 6749 2.3.1), or else from the supplied form parameters.
+func oauthClient(c *gin.Context, clientID, clientSecret string) (apikey.APIKey, user.User, *grantError) {
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		// Client credentials are form-urlencoded before Basic encoding
+		clientID, _ = url.QueryUnescape(id)
+		clientSecret, _ = url.QueryUnescape(secret)
+		c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+	if clientSecret == "" {
+		return apikey.APIKey{}, user.User{}, newGrantError(http.StatusUnauthorized, token.ErrCodeInvalidClient,
+			errors.New("unauthenticated: missing client credentials"))
+	}
+	k, u, err := apiKeyUser(c, clientSecret)
+	if err == nil && clientID != "" && clientID != k.ID {
+		err = errors.New("client_id does not match client_secret")
+	}
+	if err != nil {
+		return k, u, newGrantError(http.StatusUnauthorized, token.ErrCodeInvalidClient,
+			fmt.Errorf("unauthenticated: invalid client credentials: %w", err))
+	}
+	return k, u, nil
+}
+
+// abortWithOAuthError aborts the request with an OAuth 2.0 Error Response describing the failed token grant.
+// Errors are reported with a 400 Bad Request status, except for client authentication failures (401),
+// temporary lockouts (429), and server errors (500).
+func abortWithOAuthError(c *gin.Context, ge *grantError) {
+	status := http.StatusBadRequest
+	switch {
+	case ge.Code == token.ErrCodeInvalidClient:
+		status = http.StatusUnauthorized
+	case ge.Code == token.ErrCodeServerError:
+		status = http.StatusInternalServerError
+	case ge.Status == http.StatusTooManyRequests:
+		status = http.StatusTooManyRequests
+		c.Header("Retry-After", strconv.Itoa(int(ge.RetryAfter.Seconds())))
+	}
+	description := ge.Err.Error()
+	var e event.Event
+	if errors.As(ge.Err, &e) {
+		description = e.Message
+	}
+	c.AbortWithStatusJSON(status, token.OAuthError{
+		Code:        ge.Code,
+		Description: description,
+	})
+}
+
+// oauthRevoke implements OAuth 2.0 Token Revocation (RFC 7009). Either an access Token or a RefreshToken may be
+// revoked; revoking a RefreshToken also revokes the access Tokens issued with its token family. Possession of
+// the token is sufficient to revoke it. Unknown tokens are ignored, so the response is always 200 OK on success.
+//
+// @Summary OAuth 2.0 Revoke
+// @Description OAuth 2.0 Token Revocation (RFC 7009)
+// @Description Revoke an access Token or a RefreshToken. Revoking a RefreshToken also revokes the access
+// @Description Tokens issued with the same password grant. Invalid or unknown tokens are ignored.
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Access Token or Refresh Token"
+// @Param token_type_hint formData string false "Token Type Hint" Enums(access_token, refresh_token)
+// @Success 200
+// @Failure 400 {object} token.OAuthError "Bad Request (invalid_request)"
+// @Failure 500 {object} token.OAuthError "Internal Server Error (server_error)"
+// @Router /oauth/revoke [post]
+func oauthRevoke(c *gin.Context) {
+	var req token.RevocationRequest
+	if err := c.ShouldBindWith(&req, binding.FormPost); err != nil || req.Token == "" {
+		abortWithOAuthError(c, newGrantError(http.StatusBadRequest, token.ErrCodeInvalidRequest,
+			errors.New("bad request: missing token")))
+		return
+	}
+	req.Token = accessTokenID(c, req.Token)
+	if !tuid.IsValid(tuid.TUID(req.Token)) {
+		c.Status(http.StatusOK)
+		return
+	}
+	// Try the hinted token type first
+	revokers := []func(*gin.Context, string) (bool, error){revokeAccessToken, revokeRefreshToken}
+	if req.TokenTypeHint == token.HintRefreshToken {
+		revokers = []func(*gin.Context, string) (bool, error){revokeRefreshToken, revokeAccessToken}
+	}
+	for _, revoke := range revokers {
+		revoked, err := revoke(c, req.Token)
+		if err != nil {
+			e, _, _ := api.EventService.Create(c, event.Event{
+				UserID:     contextUserID(c),
+				EntityID:   req.Token,
+				EntityType: "Token",
+				LogLevel:   event.ERROR,
+				Message:    fmt.Errorf("revoke token %s: %w", req.Token, err).Error(),
+				URI:        c.Request.URL.String(),
+				Err:        err,
+			})
+			abortWithOAuthError(c, newGrantError(http.StatusInternalServerError, token.ErrCodeServerError, e))
+			return
+		}
+		if revoked {
+			break
+		}
+	}
+	c.Status(http.StatusOK)
+}
+
+// accessTokenID returns the Token ID (jti) of a signed JWT access token, so that its Token may be revoked or
+// introspected. Expired JWTs are accepted, but JWTs with an invalid signature are not. Other bearer credentials,
+// or any credential if the application does not issue JWTs, are returned unchanged.
+func accessTokenID(c *gin.Context, bearer string) string {
+	if api.JWTAlgorithm == "" || !token.IsJWT(bearer) {
+		return bearer
+	}
+	claims, err := api.VerifyJWT(c, bearer)
+	if err != nil && !errors.Is(err, token.ErrExpiredJWT) {
+		return ""
+	}
+	return claims.TokenID
+}
+
+// revokeAccessToken deletes the specified access Token, returning true if it existed.
+func revokeAccessToken(c *gin.Context, id string) (bool, error) {
+	t, err := api.TokenService.Delete(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     t.UserID,
+		EntityID:   t.ID,
+		EntityType: t.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("revoked Token %s for User %s", t.ID, t.UserID),
+		URI:        c.Request.URL.String(),
+	})
+	return true, nil
+}
+
+// revokeRefreshToken revokes the token family of the specified RefreshToken, returning true if it existed.
+func revokeRefreshToken(c *gin.Context, id string) (bool, error) {
+	rt, err := api.TokenService.ReadRefreshToken(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err = api.TokenService.RevokeFamily(c, rt.FamilyID); err != nil {
+		return false, err
+	}
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     rt.UserID,
+		EntityID:   rt.ID,
+		EntityType: rt.Type(),
+		OtherIDs:   []string{rt.FamilyID},
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("revoked RefreshToken %s for User %s: revoked token family %s", rt.ID, rt.UserID, rt.FamilyID),
+		URI:        c.Request.URL.String(),
+	})
+	return true, nil
+}
+
+// oauthIntrospect implements OAuth 2.0 Token Introspection (RFC 7662). The caller must have the token:read
+// permission (e.g. an administrator), or be an OAuth client (a service account APIKey) with the tokens:read scope,
+// authenticated with a bearer credential or client credentials.
+//
+// @Summary OAuth 2.0 Introspect
+// @Description OAuth 2.0 Token Introspection (RFC 7662)
+// @Description Describe an access Token or a RefreshToken, including whether it is currently active.
+// @Description The caller must have the token:read permission (e.g. an administrator), or be an OAuth client (service account APIKey) with the tokens:read scope.
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param authorization header string false "OAuth Bearer Token (Administrator or Service Account) or HTTP Basic client credentials"
+// @Param token formData string true "Access Token or Refresh Token"
+// @Param token_type_hint formData string false "Token Type Hint" Enums(access_token, refresh_token)
+// @Success 200 {object} token.Introspection "Token Introspection"
+// @Failure 400 {object} token.OAuthError "Bad Request (invalid_request, or invalid_scope without the tokens:read scope)"
+// @Failure 401 {object} token.OAuthError "Unauthenticated (invalid_client)"
+// @Failure 500 {object} token.OAuthError "Internal Server Error (server_error)"
+// @Router /oauth/introspect [post]
+func oauthIntrospect(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	// Authorize the caller
+	_, ok := contextUser(c)
+	k, isKey := contextAPIKey(c)
+	if !ok || (!isKey && !contextPermissions(c).Has(role.TokenRead)) {
+		var ge *grantError
+		if k, _, ge = oauthClient(c, c.PostForm("client_id"), c.PostForm("client_secret")); ge != nil {
+			abortWithOAuthError(c, ge)
+			return
+		}
+		isKey = true
+	}
+	if scope := apikey.PermissionScope(role.TokenRead); isKey && !k.HasScope(scope) {
+		abortWithOAuthError(c, newGrantError(http.StatusForbidden, token.ErrCodeInvalidScope,
+			errors.New("unauthorized: api key scope required: "+scope)))
+		return
+	}
+	var req token.RevocationRequest
+	if err := c.ShouldBindWith(&req, binding.FormPost); err != nil || req.Token == "" {
+		abortWithOAuthError(c, newGrantError(http.StatusBadRequest, token.ErrCodeInvalidRequest,
+			errors.New("bad request: missing token")))
+		return
+	}
+	req.Token = accessTokenID(c, req.Token)
+	if !tuid.IsValid(tuid.TUID(req.Token)) {
+		c.JSON(http.StatusOK, token.Introspection{})
+		return
+	}
+	// Try the hinted token type first
+	introspectors := []func(*gin.Context, string) (token.Introspection, bool, error){introspectAccessToken, introspectRefreshToken}
+	if req.TokenTypeHint == token.HintRefreshToken {
+		introspectors = []func(*gin.Context, string) (token.Introspection, bool, error){introspectRefreshToken, introspectAccessToken}
+	}
+	for _, introspect := range introspectors {
+		i, found, err := introspect(c, req.Token)
+		if err != nil {
+			e, _, _ := api.EventService.Create(c, event.Event{
+				UserID:     contextUserID(c),
+				EntityID:   req.Token,
+				EntityType: "Token",
+				LogLevel:   event.ERROR,
+				Message:    fmt.Errorf("introspect token %s: %w", req.Token, err).Error(),
+				URI:        c.Request.URL.String(),
+				Err:        err,
+			})
+			abortWithOAuthError(c, newGrantError(http.StatusInternalServerError, token.ErrCodeServerError, e))
+			return
+		}
+		if found {
+			c.JSON(http.StatusOK, i)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, token.Introspection{})
+}
+
+// introspectAccessToken describes the specified access Token, returning true if it exists. A Token is active
+// only if its User is not disabled, and the APIKey it was issued with (if any) is still usable.
+func introspectAccessToken(c *gin.Context, id string) (token.Introspection, bool, error) {
+	t, err := api.TokenService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		return token.Introspection{}, false, nil
+	}
+	if err != nil {
+		return token.Introspection{}, false, err
+	}
+	if _, _, err = tokenUser(c, id); err != nil {
+		return token.Introspection{}, true, nil
+	}
+	var scopes []string
+	if t.APIKeyID != "" {
+		k, err := tokenAPIKey(c, t)
+		if err != nil {
+			return token.Introspection{}, true, nil
+		}
+		scopes = k.Scopes
+	}
+	return t.Introspect(scopes, time.Now()), true, nil
+}
+
+// introspectRefreshToken describes the specified RefreshToken, returning true if it exists.
+// A RefreshToken is active only if it has not been rotated, and its User is not disabled.
+func introspectRefreshToken(c *gin.Context, id string) (token.Introspection, bool, error) {
+	rt, err := api.TokenService.ReadRefreshToken(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		return token.Introspection{}, false, nil
+	}
+	if err != nil {
+		return token.Introspection{}, false, err
+	}
+	u, err := api.UserService.Read(c, rt.UserID)
+	if err != nil || u.Status == user.DISABLED {
+		return token.Introspection{}, true, nil
+	}
+	return rt.Introspect(time.Now()), true, nil
+}
+
+// readJWKS publishes the public keys used to sign JWT access tokens, so that clients and resource servers may
+// verify them. Only EdDSA keys are published; HMAC keys are secret, so the key set is empty when using HS256,
+// or when JWT access tokens are not enabled.
+//
+// @Summary JSON Web Key Set
+// @Description JSON Web Key Set (RFC 7517)
+// @Description Public keys for verifying signed JWT access tokens, identified by key ID (kid).
+// @Description The key set is empty if JWT access tokens are not enabled, or if they are signed with HS256.
+// @Tags OAuth
+// @Produce json
+// @Success 200 {object} token.JWKS "JSON Web Key Set"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /.well-known/jwks.json [get]
+func readJWKS(c *gin.Context) {
+	if api.JWTAlgorithm == "" {
+		c.JSON(http.StatusOK, token.JWKS{Keys: []token.JWK{}})
+		return
+	}
+	ks, err := api.SigningKeys(c)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:   contextUserID(c),
+			LogLevel: event.ERROR,
+			Message:  fmt.Errorf("read signing keys: %w", err).Error(),
+			URI:      c.Request.URL.String(),
+			Err:      err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, ks.JWKS())
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"versionary-api/pkg/apikey"
+	"versionary-api/pkg/token"
+	"versionary-api/pkg/user"
+)
+
+// serveForm submits an application/x-www-form-urlencoded POST request, with optional request headers.
+func serveForm(path string, form url.Values, headers map[string]string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.RemoteAddr = "203.0.113.8:1234"
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestOAuthPasswordGrant(t *testing.T) {
+	expect := assert.New(t)
+	u, _, err := api.UserService.Create(context.Background(), user.User{
+		GivenName: "oauth_user",
+		Email:     "oauth_user@test.com",
+		Password:  "oauthabcd1234",
+		Status:    user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	// Requests must be form-urlencoded
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader(`{"grant_type":"password"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	var oe token.OAuthError
+	if expect.NoError(json.NewDecoder(w.Body).Decode(&oe), "Decode JSON OAuthError") {
+		expect.Equal(token.ErrCodeInvalidRequest, oe.Code)
+	}
+	// Unsupported grant type
+	w = serveForm("/oauth/token", url.Values{"grant_type": {"authorization_code"}}, nil)
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	if expect.NoError(json.NewDecoder(w.Body).Decode(&oe), "Decode JSON OAuthError") {
+		expect.Equal(token.ErrCodeUnsupportedGrantType, oe.Code)
+	}
+	// Invalid password
+	w = serveForm("/oauth/token", url.Values{"grant_type": {"password"}, "username": {u.Email}, "password": {"wrong"}}, nil)
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	if expect.NoError(json.NewDecoder(w.Body).Decode(&oe), "Decode JSON OAuthError") {
+		expect.Equal(token.ErrCodeInvalidGrant, oe.Code)
+		expect.Contains(oe.Description, "invalid username or password")
+	}
+	// Password grant
+	var res token.OAuthResponse
+	w = serveForm("/oauth/token", url.Values{"grant_type": {"password"}, "username": {u.Email}, "password": {"oauthabcd1234"}}, nil)
+	if !expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		return
+	}
+	expect.Equal("no-store", w.Header().Get("Cache-Control"))
+	expect.Contains(w.Body.String(), `"access_token"`)
+	expect.NoError(json.NewDecoder(w.Body).Decode(&res), "Decode JSON OAuthResponse")
+	expect.Equal("Bearer", res.TokenType)
+	expect.InDelta(token.AccessTokenLifetime.Seconds(), float64(res.ExpiresIn), 2)
+	expect.NotEmpty(res.RefreshToken)
+	// The access token works with the rest of the API
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/v1/users/"+u.ID, nil)
+	req.Header.Set("Authorization", "Bearer "+res.AccessToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	// Refresh token grant
+	var refreshed token.OAuthResponse
+	w = serveForm("/oauth/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {res.RefreshToken}}, nil)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.NoError(json.NewDecoder(w.Body).Decode(&refreshed), "Decode JSON OAuthResponse")
+		expect.NotEqual(res.RefreshToken, refreshed.RefreshToken)
+	}
+	w = serveForm("/oauth/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"bogus"}}, nil)
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	// Introspection requires an authorized caller
+	var i token.Introspection
+	w = serveForm("/oauth/introspect", url.Values{"token": {refreshed.AccessToken}}, nil)
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	w = serveForm("/oauth/introspect", url.Values{"token": {refreshed.AccessToken}}, map[string]string{"Authorization": "Bearer " + regularToken})
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	w = serveForm("/oauth/introspect", url.Values{"token": {refreshed.AccessToken}}, map[string]string{"Authorization": "Bearer " + adminToken})
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.NoError(json.NewDecoder(w.Body).Decode(&i), "Decode JSON Introspection")
+		expect.True(i.Active)
+		expect.Equal(u.ID, i.Sub)
+		expect.Equal(u.Email, i.Username)
+		expect.Equal("Bearer", i.TokenType)
+	}
+	// Revoke the refresh token, which revokes its token family
+	w = serveForm("/oauth/revoke", url.Values{"token": {refreshed.RefreshToken}, "token_type_hint": {"refresh_token"}}, nil)
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	expect.False(api.TokenService.Exists(context.Background(), refreshed.AccessToken), "Access token revoked")
+	w = serveForm("/oauth/introspect", url.Values{"token": {refreshed.RefreshToken}}, map[string]string{"Authorization": "Bearer " + adminToken})
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.JSONEq(`{"active":false}`, w.Body.String())
+	}
+	// Unknown tokens are ignored
+	w = serveForm("/oauth/revoke", url.Values{"token": {"bogus"}}, nil)
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	w = serveForm("/oauth/revoke", url.Values{}, nil)
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	// Clean up
+	_ = api.TokenService.DeleteAllTokensByUserID(context.Background(), u.ID)
+	_, err = api.UserService.Delete(context.Background(), u.ID)
+	expect.NoError(err)
+}
+
+func TestOAuthClientCredentialsGrant(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	svc, _, err := api.UserService.Create(ctx, user.User{
+		GivenName: "OAuth",
+		Email:     "oauth_client@versionary.net",
+		Roles:     []string{user.ServiceRole, "admin"},
+		Status:    user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	k, secret, _, err := api.APIKeyService.Create(ctx, apikey.APIKey{
+		UserID: svc.ID,
+		Name:   "OAuth Client",
+		Scopes: []string{"organizations:read", "tokens:read"},
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	// Client credentials are required
+	var oe token.OAuthError
+	w := serveForm("/oauth/token", url.Values{"grant_type": {"client_credentials"}}, nil)
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	if expect.NoError(json.NewDecoder(w.Body).Decode(&oe), "Decode JSON OAuthError") {
+		expect.Equal(token.ErrCodeInvalidClient, oe.Code)
+	}
+	w = serveForm("/oauth/token", url.Values{"grant_type": {"client_credentials"}, "client_id": {regularUser.ID}, "client_secret": {secret}}, nil)
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	// Requested scopes must be granted to the client
+	w = serveForm("/oauth/token", url.Values{"grant_type": {"client_credentials"}, "scope": {"images:write"}, "client_id": {k.ID}, "client_secret": {secret}}, nil)
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	if expect.NoError(json.NewDecoder(w.Body).Decode(&oe), "Decode JSON OAuthError") {
+		expect.Equal(token.ErrCodeInvalidScope, oe.Code)
+	}
+	// Client credentials grant, with HTTP Basic authentication
+	var res token.OAuthResponse
+	req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader("grant_type=client_credentials&scope=organizations:read"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(k.ID, secret)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if !expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		return
+	}
+	expect.NoError(json.NewDecoder(w.Body).Decode(&res), "Decode JSON OAuthResponse")
+	expect.Empty(res.RefreshToken, "No refresh token for client credentials")
+	expect.Equal("organizations:read tokens:read", res.Scope)
+	// The access token carries the scopes of the API key
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/v1/organizations", nil)
+	req.Header.Set("Authorization", "Bearer "+res.AccessToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/v1/users", nil)
+	req.Header.Set("Authorization", "Bearer "+res.AccessToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Clients with the tokens:read scope may introspect tokens
+	var i token.Introspection
+	w = serveForm("/oauth/introspect", url.Values{"token": {res.AccessToken}, "client_id": {k.ID}, "client_secret": {secret}}, nil)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.NoError(json.NewDecoder(w.Body).Decode(&i), "Decode JSON Introspection")
+		expect.True(i.Active)
+		expect.Equal(k.ID, i.ClientID)
+		expect.Equal("organizations:read tokens:read", i.Scope)
+	}
+	w = serveForm("/oauth/introspect", url.Values{"token": {res.AccessToken}}, map[string]string{"Authorization": "Bearer " + secret})
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	w = serveForm("/oauth/introspect", url.Values{"token": {res.AccessToken}}, map[string]string{"Authorization": "Bearer " + res.AccessToken})
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	// Other clients may not, including their access tokens
+	other, otherSecret, _, err := api.APIKeyService.Create(ctx, apikey.APIKey{
+		UserID: svc.ID,
+		Name:   "Other Client",
+		Scopes: []string{"organizations:read"},
+	})
+	if expect.NoError(err) {
+		w = serveForm("/oauth/introspect", url.Values{"token": {res.AccessToken}, "client_id": {other.ID}, "client_secret": {otherSecret}}, nil)
+		expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+		if expect.NoError(json.NewDecoder(w.Body).Decode(&oe), "Decode JSON OAuthError") {
+			expect.Equal(token.ErrCodeInvalidScope, oe.Code)
+		}
+		w = serveForm("/oauth/introspect", url.Values{"token": {res.AccessToken}}, map[string]string{"Authorization": "Bearer " + otherSecret})
+		expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+		var otherRes token.OAuthResponse
+		w = serveForm("/oauth/token", url.Values{"grant_type": {"client_credentials"}, "client_id": {other.ID}, "client_secret": {otherSecret}}, nil)
+		if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+			expect.NoError(json.NewDecoder(w.Body).Decode(&otherRes), "Decode JSON OAuthResponse") {
+			w = serveForm("/oauth/introspect", url.Values{"token": {res.AccessToken}}, map[string]string{"Authorization": "Bearer " + otherRes.AccessToken})
+			expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+		}
+		_, _ = api.APIKeyService.Delete(ctx, other.ID)
+	}
+	// Revoking the API key deactivates its access tokens
+	_, err = api.APIKeyService.Revoke(ctx, k.ID)
+	expect.NoError(err)
+	w = serveForm("/oauth/introspect", url.Values{"token": {res.AccessToken}}, map[string]string{"Authorization": "Bearer " + adminToken})
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.JSONEq(`{"active":false}`, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/v1/organizations", nil)
+	req.Header.Set("Authorization", "Bearer "+res.AccessToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	// Clean up
+	_ = api.TokenService.DeleteAllTokensByUserID(ctx, svc.ID)
+	_, _ = api.APIKeyService.Delete(ctx, k.ID)
+	_, err = api.UserService.Delete(ctx, svc.ID)
+	expect.NoError(err)
+}
+
+func TestJWTAccessTokens(t *testing.T) {
+	expect := assert.New(t)
+	api.JWTAlgorithm = token.AlgEdDSA
+	defer func() { api.JWTAlgorithm = "" }()
+	u, _, err := api.UserService.Create(context.Background(), user.User{
+		GivenName: "jwt_user",
+		Email:     "jwt_user@test.com",
+		Password:  "jwtabcd1234",
+		Status:    user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	// The access token is a signed JWT
+	w := serveForm("/oauth/token", url.Values{"grant_type": {"password"}, "username": {u.Email}, "password": {"jwtabcd1234"}}, nil)
+	if !expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		return
+	}
+	var res token.OAuthResponse
+	if !expect.NoError(json.NewDecoder(w.Body).Decode(&res), "Decode JSON OAuthResponse") {
+		return
+	}
+	expect.True(token.IsJWT(res.AccessToken), "JWT access token")
+	expect.NotEmpty(res.RefreshToken)
+	claims, err := api.VerifyJWT(context.Background(), res.AccessToken)
+	if expect.NoError(err) {
+		expect.Equal(u.ID, claims.Subject)
+		expect.Equal(u.Email, claims.Email)
+		expect.True(api.TokenService.Exists(context.Background(), claims.TokenID), "Token recorded")
+	}
+	// The JWT authenticates the User
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/v1/users/"+u.ID, nil)
+	req.Header.Set("Authorization", "Bearer "+res.AccessToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	// A tampered JWT is rejected
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/v1/users/"+u.ID, nil)
+	req.Header.Set("Authorization", "Bearer "+res.AccessToken+"x")
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	// JWTs are not accepted unless the application is configured to issue them
+	api.JWTAlgorithm = ""
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/v1/users/"+u.ID, nil)
+	req.Header.Set("Authorization", "Bearer "+res.AccessToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	api.JWTAlgorithm = token.AlgEdDSA
+	// The public signing key is published
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+	r.ServeHTTP(w, req)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		var jwks token.JWKS
+		if expect.NoError(json.NewDecoder(w.Body).Decode(&jwks), "Decode JSON JWKS") && expect.NotEmpty(jwks.Keys) {
+			expect.Equal("Ed25519", jwks.Keys[0].Curve)
+		}
+	}
+	// JWTs may be introspected and revoked by their Token ID
+	w = serveForm("/oauth/introspect", url.Values{"token": {res.AccessToken}}, map[string]string{"Authorization": "Bearer " + adminToken})
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		var i token.Introspection
+		expect.NoError(json.NewDecoder(w.Body).Decode(&i), "Decode JSON Introspection")
+		expect.True(i.Active)
+		expect.Equal(u.ID, i.Sub)
+	}
+	w = serveForm("/oauth/revoke", url.Values{"token": {res.AccessToken}}, nil)
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	expect.False(api.TokenService.Exists(context.Background(), claims.TokenID), "Token revoked")
+	w = serveForm("/oauth/introspect", url.Values{"token": {res.AccessToken}}, map[string]string{"Authorization": "Bearer " + adminToken})
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.JSONEq(`{"active":false}`, w.Body.String())
+	}
+	// After key rotation, outstanding JWTs remain valid
+	_, err = api.RotateSigningKeys(context.Background(), token.AlgEdDSA)
+	if expect.NoError(err) {
+		_, err = api.VerifyJWT(context.Background(), res.AccessToken)
+		expect.NoError(err)
+	}
+}
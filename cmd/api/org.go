@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -11,25 +12,30 @@ import (
 	"github.com/voxtechnica/tuid-go"
 	v "github.com/voxtechnica/versionary"
 
+	"versionary-api/pkg/app"
 	"versionary-api/pkg/event"
+	"versionary-api/pkg/job"
 	"versionary-api/pkg/org"
 	"versionary-api/pkg/ref"
+	"versionary-api/pkg/role"
 )
 
 // registerOrganizationRoutes initializes the Organization routes.
 func registerOrganizationRoutes(r *gin.Engine) {
-	r.POST("/v1/organizations", roleAuthorizer("admin"), createOrganization)
-	r.GET("/v1/organizations", roleAuthorizer("admin"), readOrganizations)
-	r.GET("/v1/organizations/:id", readOrganization)
-	r.HEAD("/v1/organizations/:id", existsOrganization)
-	r.GET("/v1/organizations/:id/versions", roleAuthorizer("admin"), readOrganizationVersions)
-	r.GET("/v1/organizations/:id/versions/:versionid", readOrganizationVersion)
-	r.HEAD("/v1/organizations/:id/versions/:versionid", existsOrganizationVersion)
-	r.PUT("/v1/organizations/:id", roleAuthorizer("admin"), updateOrganization)
-	r.DELETE("/v1/organizations/:id", roleAuthorizer("admin"), deleteOrganization)
-	r.DELETE("/v1/organizations/:id/versions/:versionid", roleAuthorizer("admin"), deleteOrganizationVersion)
-	r.GET("/v1/organization_names", roleAuthorizer("admin"), readOrganizationNames)
-	r.GET("/v1/organization_statuses", roleAuthorizer("admin"), readOrganizationStatuses)
+	handleRoutes(r, []route{
+		{"POST", "/v1/organizations", role.OrganizationWrite, global, createOrganization},
+		{"GET", "/v1/organizations", role.OrganizationRead, global, readOrganizations},
+		{"GET", "/v1/organizations/:id", public, global, readOrganization},
+		{"HEAD", "/v1/organizations/:id", public, global, existsOrganization},
+		{"GET", "/v1/organizations/:id/versions", role.OrganizationRead, global, readOrganizationVersions},
+		{"GET", "/v1/organizations/:id/versions/:versionid", public, global, readOrganizationVersion},
+		{"HEAD", "/v1/organizations/:id/versions/:versionid", public, global, existsOrganizationVersion},
+		{"PUT", "/v1/organizations/:id", role.OrganizationWrite, orgScoped, updateOrganization},
+		{"DELETE", "/v1/organizations/:id", role.OrganizationWrite, global, deleteOrganization},
+		{"DELETE", "/v1/organizations/:id/versions/:versionid", role.OrganizationWrite, global, deleteOrganizationVersion},
+		{"GET", "/v1/organization_names", role.OrganizationRead, global, readOrganizationNames},
+		{"GET", "/v1/organization_statuses", role.OrganizationRead, global, readOrganizationStatuses},
+	})
 }
 
 // createOrganization creates a new Organization.
@@ -57,6 +63,8 @@ func createOrganization(c *gin.Context) {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
 		return
 	}
+	// Email domains are added only by verification
+	body.Domains, body.DomainChallenges = nil, nil
 	// Create a new Organization
 	o, problems, err := api.OrgService.Create(c, body)
 	if len(problems) > 0 && err != nil {
@@ -338,18 +346,23 @@ func existsOrganizationVersion(c *gin.Context) {
 // @Summary Update Organization
 // @Description Update Organization
 // @Description Update the provided, complete Organization.
+// @Description Organization administrators may only rename their own organization; other changes are ignored.
+// @Description A rename is propagated to the organization's users, memberships, and invitations by a background
+// @Description Job, identified by the X-Job-ID response header.
 // @Tags Organization
 // @Accept json
 // @Produce json
-// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
 // @Param organization body org.Organization true "Organization"
 // @Param id path string true "Organization ID"
 // @Success 200 {object} org.Organization "Organization"
 // @Failure 400 {object} APIEvent "Bad Request (invalid JSON or parameter)"
 // @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
-// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator, or another organization)"
+// @Failure 404 {object} APIEvent "Not Found"
 // @Failure 422 {object} APIEvent "Organization validation errors"
 // @Failure 500 {object} APIEvent "Internal Server Error"
+// @Header 200 {string} X-Job-ID "ID of the Job propagating a rename, if any"
 // @Router /v1/organizations/{id} [put]
 func updateOrganization(c *gin.Context) {
 	// Parse the request body as an Organization
@@ -369,6 +382,38 @@ func updateOrganization(c *gin.Context) {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: path parameter ID %s does not match Organization ID %s", id, body.ID))
 		return
 	}
+	// Organization administrators may only rename their own Organization
+	_, scoped := contextOrgScope(c, role.OrganizationWrite)
+	if scoped && !contextPermissions(c).HasInOrg(role.OrganizationWrite, id) {
+		abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: update organization %s", id))
+		return
+	}
+	// Read the prior version, to detect a rename
+	prior, err := api.OrgService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) && scoped {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: organization %s", id))
+		return
+	}
+	if err != nil && !errors.Is(err, v.ErrNotFound) {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "Organization",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read organization %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	if scoped {
+		name := body.Name
+		body = prior
+		body.Name = name
+	}
+	// Email domains are changed only by verification (or removal)
+	body.Domains, body.DomainChallenges = prior.Domains, prior.DomainChallenges
 	// Update the specified Organization
 	o, problems, err := api.OrgService.Update(c, body)
 	if len(problems) > 0 && err != nil {
@@ -397,24 +442,79 @@ func updateOrganization(c *gin.Context) {
 		Message:    fmt.Sprintf("updated Organization %s %s", o.ID, o.Name),
 		URI:        c.Request.URL.String(),
 	})
+	// Propagate a rename to the Users, Memberships, and Invitations, in the background
+	if prior.ID != "" && prior.Name != o.Name {
+		if j, ok := startOrgRename(c, o, prior.Name); ok {
+			c.Header("X-Job-ID", j.ID)
+		}
+	}
 	// Return the updated Organization
 	c.JSON(http.StatusOK, o)
 }
 
-// deleteOrganization deletes the specified Organization.
+// startOrgRename starts a Job that propagates the Organization's new name to the copies kept by its members,
+// Memberships, and Invitations. The Job is returned, or false if it could not be created (which is logged).
+func startOrgRename(c *gin.Context, o org.Organization, priorName string) (job.Job, bool) {
+	j, _, err := api.JobService.Create(c, job.Job{
+		Kind:       job.OrgRename,
+		UserID:     contextUserID(c),
+		EntityType: o.Type(),
+		EntityID:   o.ID,
+		Message:    fmt.Sprintf("rename Organization %s from %q to %q", o.ID, priorName, o.Name),
+	})
+	if err != nil {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   o.ID,
+			EntityType: o.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("create rename job for organization %s: %w", o.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		return j, false
+	}
+	userID, uri := contextUserID(c), c.Request.URL.String()
+	api.RunJob(func(ctx context.Context) {
+		j := api.SyncOrgName(ctx, j, o)
+		logLevel := event.INFO
+		if j.Status == job.FAILED {
+			logLevel = event.ERROR
+		}
+		_, _, _ = api.EventService.Create(ctx, event.Event{
+			UserID:     userID,
+			EntityID:   j.ID,
+			EntityType: j.Type(),
+			OtherIDs:   []string{o.ID},
+			LogLevel:   logLevel,
+			Message:    j.String(),
+			URI:        uri,
+		})
+	})
+	return j, true
+}
+
+// deleteOrganization deletes the specified Organization, handling its members according to the member policy.
 //
 // @Summary Delete Organization
 // @Description Delete Organization
-// @Description Delete and return the specified Organization.
+// @Description Delete the specified Organization, and report what happened to each of its members.
+// @Description The member policy is one of: refuse (the default) to refuse while members remain, reassign
+// @Description to move the members to the target Organization (dropping organization-scoped roles), or
+// @Description disable to disable the members and revoke their tokens. Members whose primary organization
+// @Description is elsewhere just lose their membership. Open invitations to the Organization are revoked.
 // @Tags Organization
 // @Produce json
 // @Param authorization header string true "OAuth Bearer Token (Administrator)"
 // @Param id path string true "Organization ID"
-// @Success 200 {object} org.Organization "Organization that was deleted"
-// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Param policy query string false "Member Policy: refuse | reassign | disable (default: refuse)"
+// @Param target query string false "Target Organization ID (reassign policy only)"
+// @Success 200 {object} app.OrgDeletion "Report of the deleted Organization and its members"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID, policy, or target)"
 // @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
 // @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
 // @Failure 404 {object} APIEvent "Not Found"
+// @Failure 409 {object} APIEvent "Conflict (the Organization has child organizations, or members and the policy is refuse)"
 // @Failure 500 {object} APIEvent "Internal Server Error"
 // @Router /v1/organizations/{id} [delete]
 func deleteOrganization(c *gin.Context) {
@@ -424,12 +524,37 @@ func deleteOrganization(c *gin.Context) {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
 		return
 	}
-	// Delete the specified Organization
-	o, err := api.OrgService.Delete(c, id)
+	// Validate the member policy
+	policy, err := app.ParseMemberPolicy(c.Query("policy"))
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: %w", err))
+		return
+	}
+	target := c.Query("target")
+	if policy == app.ReassignMembers && !tuid.IsValid(tuid.TUID(target)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid target organization ID: %q", target))
+		return
+	}
+	// Delete the specified Organization, handling its members
+	d, err := api.DeleteOrganization(c, app.OrgDeletionRequest{
+		OrgID:       id,
+		Policy:      policy,
+		TargetOrgID: target,
+		UserID:      contextUserID(c),
+		URI:         c.Request.URL.String(),
+	})
+	if err != nil && errors.Is(err, app.ErrInvalidTargetOrg) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: %w", err))
+		return
+	}
 	if err != nil && errors.Is(err, v.ErrNotFound) {
 		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: organization %s", id))
 		return
 	}
+	if err != nil && (errors.Is(err, app.ErrOrgHasMembers) || errors.Is(err, app.ErrOrgHasChildren)) {
+		abortWithError(c, http.StatusConflict, fmt.Errorf("conflict: %w", err))
+		return
+	}
 	if err != nil {
 		e, _, _ := api.EventService.Create(c, event.Event{
 			UserID:     contextUserID(c),
@@ -443,17 +568,8 @@ func deleteOrganization(c *gin.Context) {
 		abortWithError(c, http.StatusInternalServerError, e)
 		return
 	}
-	// Log the deletion
-	_, _, _ = api.EventService.Create(c, event.Event{
-		UserID:     contextUserID(c),
-		EntityID:   o.ID,
-		EntityType: o.Type(),
-		LogLevel:   event.INFO,
-		Message:    fmt.Sprintf("deleted Organization %s %s", o.ID, o.Name),
-		URI:        c.Request.URL.String(),
-	})
-	// Return the deleted organization
-	c.JSON(http.StatusOK, o)
+	// Return the report of the deleted Organization and its members
+	c.JSON(http.StatusOK, d)
 }
 
 // deleteOrganizationVersion deletes the specified Organization version.
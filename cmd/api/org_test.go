@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -12,6 +13,7 @@ import (
 	"github.com/voxtechnica/tuid-go"
 	"github.com/voxtechnica/versionary"
 
+	"versionary-api/pkg/app"
 	"versionary-api/pkg/org"
 	"versionary-api/pkg/user"
 )
@@ -89,11 +91,14 @@ func TestOrganizationCRUD(t *testing.T) {
 	if expect.NoError(err) {
 		r.ServeHTTP(w, req)
 		expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
-		var o2 org.Organization
-		if expect.NoError(json.NewDecoder(w.Body).Decode(&o2), "Decode JSON Organization") {
-			expect.Equal(o.ID, o2.ID, "Organization ID")
-			expect.Equal(o.Name, o2.Name, "Organization Name")
-			expect.Equal(o.Status, o2.Status, "Organization Status")
+		var d app.OrgDeletion
+		if expect.NoError(json.NewDecoder(w.Body).Decode(&d), "Decode JSON Organization Deletion") {
+			expect.True(d.Deleted, "Organization Deleted")
+			expect.Equal(app.RefuseMembers, d.Policy, "Member Policy")
+			expect.Empty(d.Members, "Organization Members")
+			expect.Equal(o.ID, d.Organization.ID, "Organization ID")
+			expect.Equal(o.Name, d.Organization.Name, "Organization Name")
+			expect.Equal(o.Status, d.Organization.Status, "Organization Status")
 		}
 	}
 }
@@ -522,6 +527,151 @@ func TestDeleteOrganization(t *testing.T) {
 	// Delete a known organization: covered in the CRUD test above
 }
 
+func TestDeleteOrganizationMembers(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	o, orgAdmin, orgAdminToken := generateOrgAdmin("Doomed Org")
+	defer deleteOrgAdmin(o, orgAdmin)
+	target, targetAdmin, _ := generateOrgAdmin("Receiving Org")
+	defer deleteOrgAdmin(target, targetAdmin)
+	call := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("DELETE", "/v1/organizations/"+o.ID+query, nil)
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		r.ServeHTTP(w, req)
+		return w
+	}
+	// A member whose primary organization is elsewhere, and an open invitation
+	m, _, err := api.UserService.Memberships.Create(ctx, user.Membership{
+		UserID:  regularUser.ID,
+		Email:   regularUser.Email,
+		OrgID:   o.ID,
+		OrgName: o.Name,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.UserService.Memberships.Delete(ctx, m.ID) }()
+	i, _, _, err := api.InvitationService.Create(ctx, org.Invitation{
+		OrgID:     o.ID,
+		OrgName:   o.Name,
+		Email:     "doomed.invitee@test.com",
+		InviterID: orgAdmin.ID,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.InvitationService.Delete(ctx, i.ID) }()
+
+	// Invalid policies and targets
+	expect.Equal(http.StatusBadRequest, call("?policy=bogus").Code, "HTTP Status Code")
+	expect.Equal(http.StatusBadRequest, call("?policy=reassign").Code, "HTTP Status Code")
+	expect.Equal(http.StatusBadRequest, call("?policy=reassign&target="+o.ID).Code, "HTTP Status Code")
+	expect.Equal(http.StatusBadRequest, call("?policy=reassign&target="+tuid.NewID().String()).Code, "HTTP Status Code")
+
+	// By default, an organization with members may not be deleted
+	w := call("")
+	expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+	expect.True(api.OrgService.Exists(ctx, o.ID), "Organization Exists")
+
+	// Reassign the members to another organization
+	var d app.OrgDeletion
+	w = call("?policy=reassign&target=" + target.ID)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&d), "Decode JSON Organization Deletion") {
+		expect.True(d.Deleted, "Organization Deleted")
+		expect.Equal(target.ID, d.TargetOrgID, "Target Organization ID")
+		expect.Equal(1, d.RevokedInvitations, "Revoked Invitations")
+		if expect.Len(d.Members, 2, "Organization Members") {
+			for _, result := range d.Members {
+				if result.UserID == orgAdmin.ID {
+					expect.Equal(app.MemberReassigned, result.Action)
+					expect.Equal(target.ID, result.OrgID)
+					expect.Equal([]string{user.OrgAdminRole}, result.RemovedRoles)
+				} else {
+					expect.Equal(regularUser.ID, result.UserID)
+					expect.Equal(app.MemberRemoved, result.Action)
+				}
+			}
+		}
+	}
+	expect.False(api.OrgService.Exists(ctx, o.ID), "Organization Exists")
+	if u, err := api.UserService.Read(ctx, orgAdmin.ID); expect.NoError(err) {
+		expect.Equal(target.ID, u.OrgID)
+		expect.Equal(target.Name, u.OrgName)
+		expect.Empty(u.Roles)
+	}
+	expect.False(api.UserService.Memberships.Exists(ctx, regularUser.ID, o.ID), "Membership Exists")
+	if check, err := api.InvitationService.Read(ctx, i.ID); expect.NoError(err) {
+		expect.Equal(org.REVOKED, check.Status)
+	}
+	// The reassigned administrator may no longer manage users in either organization
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/v1/users?org="+target.ID, nil)
+	req.Header.Set("Authorization", "Bearer "+orgAdminToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+
+	// Disable the members of another organization
+	o2, orgAdmin2, orgAdminToken2 := generateOrgAdmin("Disabled Org")
+	defer deleteOrgAdmin(o2, orgAdmin2)
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("DELETE", "/v1/organizations/"+o2.ID+"?policy=disable", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	r.ServeHTTP(w, req)
+	d = app.OrgDeletion{}
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&d), "Decode JSON Organization Deletion") &&
+		expect.Len(d.Members, 1, "Organization Members") {
+		expect.Equal(orgAdmin2.ID, d.Members[0].UserID)
+		expect.Equal(app.MemberDisabled, d.Members[0].Action)
+	}
+	if u, err := api.UserService.Read(ctx, orgAdmin2.ID); expect.NoError(err) {
+		expect.Equal(user.DISABLED, u.Status)
+		expect.Empty(u.OrgID)
+	}
+	expect.False(api.TokenService.Exists(ctx, orgAdminToken2), "Token Exists")
+
+	// Legacy members, without a Membership row, are found too
+	o3, _, err := api.OrgService.Create(ctx, org.Organization{Name: "Legacy Org", Status: org.ENABLED})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.OrgService.Delete(ctx, o3.ID) }()
+	legacy, _, err := api.UserService.Create(ctx, user.User{
+		Email:   "legacy.member@test.com",
+		OrgID:   o3.ID,
+		OrgName: o3.Name,
+		Status:  user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.UserService.Delete(ctx, legacy.ID) }()
+	expect.NoError(api.UserService.Memberships.DeleteAllMembershipsByUserID(ctx, legacy.ID))
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("DELETE", "/v1/organizations/"+o3.ID, nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+	expect.Contains(w.Body.String(), "1 member(s)")
+	expect.True(api.OrgService.Exists(ctx, o3.ID), "Organization Exists")
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("DELETE", "/v1/organizations/"+o3.ID+"?policy=disable", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	r.ServeHTTP(w, req)
+	d = app.OrgDeletion{}
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&d), "Decode JSON Organization Deletion") &&
+		expect.Len(d.Members, 1, "Organization Members") {
+		expect.Equal(legacy.ID, d.Members[0].UserID)
+		expect.Equal(app.MemberDisabled, d.Members[0].Action)
+	}
+	if u, err := api.UserService.Read(ctx, legacy.ID); expect.NoError(err) {
+		expect.Empty(u.OrgID)
+	}
+}
+
 func TestReadOrganizationStatuses(t *testing.T) {
 	expect := assert.New(t)
 	// Read organization statuses in use
@@ -539,3 +689,39 @@ func TestReadOrganizationStatuses(t *testing.T) {
 		}
 	}
 }
+
+func TestOrgAdminOrganization(t *testing.T) {
+	expect := assert.New(t)
+	o, orgAdmin, orgAdminToken := generateOrgAdmin("Scoped Org")
+	defer deleteOrgAdmin(o, orgAdmin)
+	other, otherAdmin, _ := generateOrgAdmin("Other Scoped Org")
+	defer deleteOrgAdmin(other, otherAdmin)
+	call := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+orgAdminToken)
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+	// View the organization
+	w := call("GET", "/v1/organizations/"+o.ID, "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	// Rename the organization; other changes are ignored
+	var renamed org.Organization
+	w = call("PUT", "/v1/organizations/"+o.ID, `{"id": "`+o.ID+`", "name": "Renamed Scoped Org", "status": "DISABLED"}`)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&renamed), "Decode JSON Organization") {
+		expect.Equal("Renamed Scoped Org", renamed.Name)
+		expect.Equal(org.ENABLED, renamed.Status)
+	}
+	// Other organizations are forbidden
+	w = call("PUT", "/v1/organizations/"+other.ID, `{"id": "`+other.ID+`", "name": "Hijacked"}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call("GET", "/v1/organizations", "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call("DELETE", "/v1/organizations/"+o.ID, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call("POST", "/v1/organizations", `{"name": "Another Org"}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+}
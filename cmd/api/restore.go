@@ -0,0 +1,277 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v "github.com/voxtechnica/versionary"
+
+	"versionary-api/pkg/app"
+	"versionary-api/pkg/content"
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/ref"
+	"versionary-api/pkg/role"
+	"versionary-api/pkg/user"
+)
+
+// registerRestoreRoutes initializes the routes that restore prior entity versions.
+func registerRestoreRoutes(r *gin.Engine) {
+	handleRoutes(r, []route{
+		{"POST", "/v1/contents/:id/versions/:versionid/restore", role.ContentWrite, global, restoreContentVersion},
+		{"POST", "/v1/devices/:id/versions/:versionid/restore", role.DeviceWrite, global, restoreDeviceVersion},
+		{"POST", "/v1/emails/:id/versions/:versionid/restore", role.EmailWrite, global, restoreEmailVersion},
+		{"POST", "/v1/images/:id/versions/:versionid/restore", role.ImageWrite, global, restoreImageVersion},
+		{"POST", "/v1/organizations/:id/versions/:versionid/restore", role.OrganizationWrite, global, restoreOrganizationVersion},
+		{"POST", "/v1/users/:id/versions/:versionid/restore", role.UserWrite, global, restoreUserVersion},
+	})
+}
+
+// RestoreVersionRequest explains why a prior version is being restored.
+type RestoreVersionRequest struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+// restoreContentVersion restores a prior version of the specified Content.
+//
+// @Summary Restore Content Version
+// @Description Restore Content Version
+// @Description Restore a prior version of the specified Content, saving it as a new version with a comment
+// @Description recording where it came from. The Status is not restored: the new version is a DRAFT if the
+// @Description Content is currently PUBLISHED or ARCHIVED, so that it's reviewed before it's published again.
+// @Description A restored slug keeps the current slug as an alias, so that its permalinks still work.
+// @Tags Content
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Editor)"
+// @Param id path string true "Content ID"
+// @Param versionid path string true "Content VersionID"
+// @Param request body RestoreVersionRequest false "Reason for restoring the version (optional)"
+// @Success 200 {object} app.Restoration "Restored Content"
+// @Failure 400 {object} APIEvent "Bad Request (invalid parameter or JSON body)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Editor)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 409 {object} APIEvent "Conflict (the version is already current, or its slug is in use)"
+// @Failure 422 {object} APIEvent "Content validation errors"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/contents/{id}/versions/{versionid}/restore [post]
+func restoreContentVersion(c *gin.Context) {
+	restoreEntityVersion(c, "Content")
+}
+
+// restoreDeviceVersion restores a prior version of the specified Device.
+//
+// @Summary Restore Device Version
+// @Description Restore Device Version
+// @Description Restore a prior version of the specified Device (e.g. its User and User-Agent), saving it as a new
+// @Description version. The times it was last seen and expires are not restored.
+// @Tags Device
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param id path string true "Device ID"
+// @Param versionid path string true "Device VersionID"
+// @Param request body RestoreVersionRequest false "Reason for restoring the version (optional)"
+// @Success 200 {object} app.Restoration "Restored Device"
+// @Failure 400 {object} APIEvent "Bad Request (invalid parameter or JSON body)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 409 {object} APIEvent "Conflict (the version is already current)"
+// @Failure 422 {object} APIEvent "Device validation errors"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/devices/{id}/versions/{versionid}/restore [post]
+func restoreDeviceVersion(c *gin.Context) {
+	restoreEntityVersion(c, "Device")
+}
+
+// restoreEmailVersion restores a prior version of the specified Email.
+//
+// @Summary Restore Email Version
+// @Description Restore Email Version
+// @Description Restore a prior version of the specified Email, saving it as a new version. The Status is not
+// @Description restored, so restoring a version never sends (or re-sends) the message.
+// @Tags Email
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param id path string true "Email ID"
+// @Param versionid path string true "Email VersionID"
+// @Param request body RestoreVersionRequest false "Reason for restoring the version (optional)"
+// @Success 200 {object} app.Restoration "Restored Email"
+// @Failure 400 {object} APIEvent "Bad Request (invalid parameter or JSON body)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 409 {object} APIEvent "Conflict (the version is already current)"
+// @Failure 422 {object} APIEvent "Email validation errors"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/emails/{id}/versions/{versionid}/restore [post]
+func restoreEmailVersion(c *gin.Context) {
+	restoreEntityVersion(c, "Email")
+}
+
+// restoreImageVersion restores a prior version of the specified Image.
+//
+// @Summary Restore Image Version
+// @Description Restore Image Version
+// @Description Restore a prior version of the specified Image's metadata (e.g. its title, alt text, and tags),
+// @Description saving it as a new version. Only the current image file is stored, so the file and its analysis
+// @Description (size, hash, dimensions, etc.) are not restored.
+// @Tags Image
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param id path string true "Image ID"
+// @Param versionid path string true "Image VersionID"
+// @Param request body RestoreVersionRequest false "Reason for restoring the version (optional)"
+// @Success 200 {object} app.Restoration "Restored Image"
+// @Failure 400 {object} APIEvent "Bad Request (invalid parameter or JSON body)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 409 {object} APIEvent "Conflict (the version is already current)"
+// @Failure 422 {object} APIEvent "Image validation errors"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/images/{id}/versions/{versionid}/restore [post]
+func restoreImageVersion(c *gin.Context) {
+	restoreEntityVersion(c, "Image")
+}
+
+// restoreOrganizationVersion restores a prior version of the specified Organization.
+//
+// @Summary Restore Organization Version
+// @Description Restore Organization Version
+// @Description Restore a prior version of the specified Organization, saving it as a new version. Email domains
+// @Description change only by verification, so they're not restored. A restored name is propagated to the
+// @Description members, Memberships, and Invitations in a background Job, identified by the X-Job-ID header.
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param id path string true "Organization ID"
+// @Param versionid path string true "Organization VersionID"
+// @Param request body RestoreVersionRequest false "Reason for restoring the version (optional)"
+// @Success 200 {object} app.Restoration "Restored Organization"
+// @Failure 400 {object} APIEvent "Bad Request (invalid parameter or JSON body)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 409 {object} APIEvent "Conflict (the version is already current)"
+// @Failure 422 {object} APIEvent "Organization validation errors"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Header 200 {string} X-Job-ID "ID of the Job propagating a restored name"
+// @Router /v1/organizations/{id}/versions/{versionid}/restore [post]
+func restoreOrganizationVersion(c *gin.Context) {
+	restoreEntityVersion(c, "Organization")
+}
+
+// restoreUserVersion restores a prior version of the specified User.
+//
+// @Summary Restore User Version
+// @Description Restore User Version
+// @Description Restore a prior version of the specified User, saving it as a new version. Credentials are never
+// @Description rolled back: the current password hash, password reset, TOTP secret, and recovery codes are kept,
+// @Description along with the verified email address. The Organization name is refreshed, and a User who is
+// @Description disabled by the restored version is signed out. Restoring roles that the requester may not grant
+// @Description (or an Organization in which they may not manage Users) is refused, as it is for an update.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param id path string true "User ID"
+// @Param versionid path string true "User VersionID"
+// @Param request body RestoreVersionRequest false "Reason for restoring the version (optional)"
+// @Success 200 {object} app.Restoration "Restored User"
+// @Failure 400 {object} APIEvent "Bad Request (invalid parameter or JSON body)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator, or may not grant the restored roles)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 409 {object} APIEvent "Conflict (the version is already current, or its email address is in use)"
+// @Failure 422 {object} APIEvent "User validation errors"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/versions/{versionid}/restore [post]
+func restoreUserVersion(c *gin.Context) {
+	restoreEntityVersion(c, "User")
+}
+
+// restoreEntityVersion restores the prior version of an entity specified by the path parameters.
+func restoreEntityVersion(c *gin.Context, entityType string) {
+	// Validate the path parameters
+	id := c.Param("id")
+	versionID := c.Param("versionid")
+	refID, err := ref.NewRefID(entityType, id, versionID)
+	if err != nil || versionID == "" {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID or VersionID: %s %s", id, versionID))
+		return
+	}
+	// Parse the optional request body
+	var body RestoreVersionRequest
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
+		return
+	}
+	// Restore the version
+	editor, _ := contextUser(c)
+	rs, problems, err := api.Restore(c, app.RestoreRequest{
+		EntityType: entityType,
+		ID:         id,
+		VersionID:  versionID,
+		Comment:    body.Comment,
+		UserID:     editor.ID,
+		UserName:   editor.FullName(),
+		URI:        c.Request.URL.String(),
+		AuthorizeUser: func(version, current user.User) error {
+			return authorizeRestoredUser(c, version, current)
+		},
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrRestoreUnauthorized):
+			abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: %w", err))
+		case errors.Is(err, v.ErrNotFound):
+			abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: %s", refID))
+		case errors.Is(err, app.ErrCurrentVersion), errors.Is(err, content.ErrDuplicateSlug), errors.Is(err, user.ErrDuplicateEmail):
+			abortWithError(c, http.StatusConflict, fmt.Errorf("conflict: %w", err))
+		case len(problems) > 0:
+			abortWithError(c, http.StatusUnprocessableEntity, fmt.Errorf("unprocessable entity: %w", err))
+		default:
+			e, _, _ := api.EventService.Create(c, event.Event{
+				UserID:     contextUserID(c),
+				EntityID:   id,
+				EntityType: entityType,
+				LogLevel:   event.ERROR,
+				Message:    fmt.Errorf("restore %s: %w", refID, err).Error(),
+				URI:        c.Request.URL.String(),
+				Err:        err,
+			})
+			abortWithError(c, http.StatusInternalServerError, e)
+		}
+		return
+	}
+	if rs.JobID != "" {
+		c.Header("X-Job-ID", rs.JobID)
+	}
+	if u, ok := rs.Entity.(user.User); ok {
+		rs.Entity = scrubUser(c, u)
+	}
+	c.JSON(http.StatusOK, rs)
+}
+
+// authorizeRestoredUser checks a User version before it's restored, as updateUser checks an update: the
+// requester must be able to grant every role that the version would add, and to manage Users in the
+// Organization that it would move the User to.
+func authorizeRestoredUser(c *gin.Context, version, current user.User) error {
+	if version.OrgID != current.OrgID && !contextPermissions(c).HasInOrg(role.UserWrite, version.OrgID) {
+		return fmt.Errorf("%w: manage users in organization %s", app.ErrRestoreUnauthorized, version.OrgID)
+	}
+	for _, r := range version.Roles {
+		if !v.Contains(current.Roles, r) && !canGrantRole(c, r) {
+			return fmt.Errorf("%w: grant role %s", app.ErrRestoreUnauthorized, r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/voxtechnica/tuid-go"
+
+	"versionary-api/pkg/app"
+	"versionary-api/pkg/content"
+	"versionary-api/pkg/org"
+	"versionary-api/pkg/role"
+	"versionary-api/pkg/token"
+	"versionary-api/pkg/user"
+)
+
+func TestRestoreVersion(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	call := func(bearer, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "10.0.23.1:1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder, entity any) app.Restoration {
+		rs := app.Restoration{Entity: entity}
+		expect.NoError(json.NewDecoder(w.Body).Decode(&rs), "Decode JSON Restoration")
+		return rs
+	}
+
+	// Restored published Content is a new draft
+	c, _, err := api.ContentService.Create(ctx, content.Content{
+		Type: content.ARTICLE,
+		Body: content.Section{Title: "Restore", Text: "Original text."},
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.ContentService.Delete(ctx, c.ID) }()
+	original := c.VersionID
+	c.Body.Text = "Vandalized text."
+	c.Status = content.PUBLISHED
+	c, _, err = api.ContentService.Update(ctx, c)
+	if !expect.NoError(err) {
+		return
+	}
+	path := "/v1/contents/" + c.ID + "/versions/" + original + "/restore"
+	expect.Equal(http.StatusUnauthorized, call("", path, "").Code)
+	expect.Equal(http.StatusForbidden, call(regularToken, path, "").Code)
+	w := call(adminToken, path, `{"comment": "revert vandalism"}`)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		var restored content.Content
+		rs := decode(w, &restored)
+		expect.Equal(original, rs.FromVersionID)
+		expect.Equal(c.VersionID, rs.PriorVersionID)
+		expect.Equal(restored.VersionID, rs.VersionID)
+		expect.Equal("Original text.", restored.Body.Text)
+		expect.Equal(content.DRAFT, restored.Status)
+		expect.Equal(adminUser.ID, restored.EditorID)
+		expect.Equal("restored from version "+original+": revert vandalism", restored.Comment)
+		// The restored version is current, so it can't be restored again
+		w = call(adminToken, "/v1/contents/"+c.ID+"/versions/"+rs.VersionID+"/restore", "")
+		expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+	}
+	expect.Equal(http.StatusNotFound, call(adminToken, "/v1/contents/"+c.ID+"/versions/"+tuid.NewID().String()+"/restore", "").Code)
+	expect.Equal(http.StatusBadRequest, call(adminToken, "/v1/contents/"+c.ID+"/versions/bad/restore", "").Code)
+
+	// A restored User keeps their current credentials
+	u, _, err := api.UserService.Create(ctx, user.User{
+		GivenName:  "Restore",
+		FamilyName: "Original",
+		Email:      "restore@test.com",
+		Password:   "original password",
+		Status:     user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.UserService.Delete(ctx, u.ID) }()
+	original = u.VersionID
+	u.FamilyName = "Changed"
+	u.Password = "changed password"
+	u, _, err = api.UserService.Update(ctx, u)
+	if !expect.NoError(err) {
+		return
+	}
+	w = call(adminToken, "/v1/users/"+u.ID+"/versions/"+original+"/restore", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		var restored user.User
+		rs := decode(w, &restored)
+		expect.Equal(original, rs.FromVersionID)
+		expect.Equal("Original", restored.FamilyName)
+		expect.Equal(u.PasswordHash, restored.PasswordHash)
+		if current, err := api.UserService.Read(ctx, u.ID); expect.NoError(err) {
+			expect.True(current.ValidPassword("changed password"))
+			expect.False(current.ValidPassword("original password"))
+		}
+	}
+
+	// Restoring roles requires permission to grant them
+	editorRole, _, err := api.RoleService.Create(ctx, role.Role{Name: "user_editor", Permissions: []string{role.UserWrite}})
+	if !expect.NoError(err) {
+		return
+	}
+	api.InvalidateRoles()
+	defer func() {
+		_, _ = api.RoleService.Delete(ctx, editorRole.ID)
+		api.InvalidateRoles()
+	}()
+	editor, _, err := api.UserService.Create(ctx, user.User{
+		GivenName: "User",
+		Email:     "restore_editor@test.com",
+		Roles:     []string{"user_editor"},
+		Status:    user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.UserService.Delete(ctx, editor.ID) }()
+	editorToken, err := api.TokenService.Create(ctx, token.Token{UserID: editor.ID, Email: editor.Email})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _ = api.TokenService.DeleteAllTokensByUserID(ctx, editor.ID) }()
+	u.Roles = []string{"admin"}
+	u, _, err = api.UserService.Update(ctx, u)
+	if !expect.NoError(err) {
+		return
+	}
+	adminVersion := u.VersionID
+	u.Roles = nil
+	u, _, err = api.UserService.Update(ctx, u)
+	if !expect.NoError(err) {
+		return
+	}
+	w = call(editorToken.ID, "/v1/users/"+u.ID+"/versions/"+original+"/restore", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		u, _ = api.UserService.Read(ctx, u.ID)
+	}
+	w = call(editorToken.ID, "/v1/users/"+u.ID+"/versions/"+adminVersion+"/restore", "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	expect.Contains(w.Body.String(), "grant role admin")
+	if current, err := api.UserService.Read(ctx, u.ID); expect.NoError(err) {
+		expect.Empty(current.Roles)
+		expect.Equal(u.VersionID, current.VersionID)
+	}
+	w = call(adminToken, "/v1/users/"+u.ID+"/versions/"+adminVersion+"/restore", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		var restored user.User
+		decode(w, &restored)
+		expect.Equal([]string{"admin"}, restored.Roles)
+	}
+
+	// A restored Organization name is propagated to its members
+	o, _, err := api.OrgService.Create(ctx, org.Organization{Name: "Restore Original", Status: org.ENABLED})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.OrgService.Delete(ctx, o.ID) }()
+	original = o.VersionID
+	o.Name = "Restore Renamed"
+	o, _, err = api.OrgService.Update(ctx, o)
+	if !expect.NoError(err) {
+		return
+	}
+	w = call(adminToken, "/v1/organizations/"+o.ID+"/versions/"+original+"/restore", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		var restored org.Organization
+		rs := decode(w, &restored)
+		expect.Equal("Restore Original", restored.Name)
+		expect.NotEmpty(rs.JobID)
+		expect.Equal(rs.JobID, w.Header().Get("X-Job-ID"))
+		api.WaitForJobs()
+		_, _ = api.JobService.Delete(ctx, rs.JobID)
+	}
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/role"
+)
+
+// registerRoleRoutes initializes the Role routes.
+func registerRoleRoutes(r *gin.Engine) {
+	handleRoutes(r, []route{
+		{"POST", "/v1/roles", role.RoleWrite, global, createRole},
+		{"GET", "/v1/roles", role.RoleRead, global, readRoles},
+		{"GET", "/v1/roles/:id", role.RoleRead, global, readRole},
+		{"PUT", "/v1/roles/:id", role.RoleWrite, global, updateRole},
+		{"DELETE", "/v1/roles/:id", role.RoleWrite, global, deleteRole},
+		{"GET", "/v1/users/:id/permissions", role.RoleRead, global, readUserPermissions},
+	})
+}
+
+// createRole creates a new Role.
+//
+// @Summary Create Role
+// @Description Create a new Role
+// @Description Create a new Role, granting permissions (e.g. "content:write") to each User with the role name.
+// @Description A Role with the same name as a built-in role (e.g. "org_admin") redefines it. The admin role, and its "*" permission, are reserved.
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param role body role.Role true "Role"
+// @Success 201 {object} role.Role "Newly-created Role"
+// @Failure 400 {object} APIEvent "Bad Request (invalid JSON body)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (missing role:write permission)"
+// @Failure 409 {object} APIEvent "Conflict (role name is already in use)"
+// @Failure 422 {object} APIEvent "Role validation errors"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Header 201 {string} Location "URL of the newly created Role"
+// @Router /v1/roles [post]
+func createRole(c *gin.Context) {
+	// Parse the request body as a Role
+	var body role.Role
+	if err := c.ShouldBindJSON(&body); err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
+		return
+	}
+	// Create a new Role
+	rl, problems, err := api.RoleService.Create(c, body)
+	if len(problems) > 0 && err != nil {
+		abortWithError(c, http.StatusUnprocessableEntity, fmt.Errorf("unprocessable entity: %w", err))
+		return
+	}
+	if errors.Is(err, role.ErrDuplicateName) {
+		abortWithError(c, http.StatusConflict, fmt.Errorf("conflict: %w", err))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   rl.ID,
+			EntityType: rl.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("create role %s %s: %w", rl.ID, rl.Name, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	api.InvalidateRoles()
+	// Log the creation
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   rl.ID,
+		EntityType: rl.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("created Role %s %s", rl.ID, rl.Name),
+		URI:        c.Request.URL.String(),
+	})
+	// Return the new Role
+	c.Header("Location", c.Request.URL.String()+"/"+rl.ID)
+	c.JSON(http.StatusCreated, rl)
+}
+
+// readRoles returns the stored Roles, followed by any built-in Roles that have not been redefined.
+//
+// @Summary List Roles
+// @Description List Roles
+// @Description List the stored Roles, followed by any built-in Roles (without an ID) that have not been redefined.
+// @Tags Role
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Success 200 {array} role.Role "Roles"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (missing role:read permission)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/roles [get]
+func readRoles(c *gin.Context) {
+	roles, err := api.RoleService.ReadAllRoles(c)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityType: "Role",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read roles: %w", err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	names := v.Map(roles, func(r role.Role) string { return r.Name })
+	for _, r := range role.DefaultRoles {
+		if r.Name == role.Admin || !v.Contains(names, r.Name) {
+			roles = append(roles, r)
+		}
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// readRole returns the current version of the specified Role.
+//
+// @Summary Read Role
+// @Description Get Role
+// @Description Get Role by ID.
+// @Tags Role
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param id path string true "Role ID"
+// @Success 200 {object} role.Role "Role"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (missing role:read permission)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/roles/{id} [get]
+func readRole(c *gin.Context) {
+	// Validate the path parameter ID
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	// Read and return the specified Role
+	rl, err := api.RoleService.ReadAsJSON(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: role %s", id))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "Role",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read role %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	c.Data(http.StatusOK, "application/json;charset=UTF-8", rl)
+}
+
+// updateRole updates and returns the specified Role.
+// Note that the updated version needs to be complete; this is not a partial update (e.g. PATCH).
+//
+// @Summary Update Role
+// @Description Update Role
+// @Description Update the provided, complete Role. Changes take effect for all Users with the role name.
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param role body role.Role true "Role"
+// @Param id path string true "Role ID"
+// @Success 200 {object} role.Role "Role"
+// @Failure 400 {object} APIEvent "Bad Request (invalid JSON or parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (missing role:write permission)"
+// @Failure 409 {object} APIEvent "Conflict (role name is already in use)"
+// @Failure 422 {object} APIEvent "Role validation errors"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/roles/{id} [put]
+func updateRole(c *gin.Context) {
+	// Parse the request body as a Role
+	var body role.Role
+	if err := c.ShouldBindJSON(&body); err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
+		return
+	}
+	// Validate the path parameter ID
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	// The path parameter ID must match the Role ID
+	if body.ID != id {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: path parameter ID %s does not match Role ID %s", id, body.ID))
+		return
+	}
+	// Update the specified Role
+	rl, problems, err := api.RoleService.Update(c, body)
+	if len(problems) > 0 && err != nil {
+		abortWithError(c, http.StatusUnprocessableEntity, fmt.Errorf("unprocessable entity: %w", err))
+		return
+	}
+	if errors.Is(err, role.ErrDuplicateName) {
+		abortWithError(c, http.StatusConflict, fmt.Errorf("conflict: %w", err))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: rl.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("update role %s %s: %w", id, rl.Name, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	api.InvalidateRoles()
+	// Log the update
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   rl.ID,
+		EntityType: rl.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("updated Role %s %s", rl.ID, rl.Name),
+		URI:        c.Request.URL.String(),
+	})
+	c.JSON(http.StatusOK, rl)
+}
+
+// deleteRole deletes the specified Role. Users with the role name lose its permissions, unless it's a built-in role,
+// which reverts to its default permissions.
+//
+// @Summary Delete Role
+// @Description Delete Role
+// @Description Delete and return the specified Role. A deleted built-in role reverts to its default permissions.
+// @Tags Role
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param id path string true "Role ID"
+// @Success 200 {object} role.Role "Role that was deleted"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (missing role:write permission)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/roles/{id} [delete]
+func deleteRole(c *gin.Context) {
+	// Validate the path parameter ID
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	// Delete the specified Role
+	rl, err := api.RoleService.Delete(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: role %s", id))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "Role",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("delete role %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	api.InvalidateRoles()
+	// Log the deletion
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   rl.ID,
+		EntityType: rl.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("deleted Role %s %s", rl.ID, rl.Name),
+		URI:        c.Request.URL.String(),
+	})
+	c.JSON(http.StatusOK, rl)
+}
+
+// readUserPermissions returns the effective permissions of the specified User, granted by their Roles.
+//
+// @Summary Read User Permissions
+// @Description Get User Permissions
+// @Description Get the effective permissions of the specified User, granted by their Roles.
+// @Description Global permissions apply everywhere; org permissions apply only within the User's Organization.
+// @Tags User
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param id path string true "User ID"
+// @Success 200 {object} role.Permissions "Permissions"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (missing role:read permission)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/permissions [get]
+func readUserPermissions(c *gin.Context) {
+	// Validate the path parameter ID
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	// Read the specified User
+	u, err := api.UserService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: user %s", id))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "User",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read user %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Return the User's effective permissions
+	p, err := api.Permissions(c, u)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "User",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read user %s permissions: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
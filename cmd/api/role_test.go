@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"versionary-api/pkg/role"
+	"versionary-api/pkg/token"
+	"versionary-api/pkg/user"
+)
+
+func TestRoleCRUD(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	// An auditor, with a role that is not yet defined
+	auditor, _, err := api.UserService.Create(ctx, user.User{
+		GivenName:  "Event",
+		FamilyName: "Auditor",
+		Email:      "event_auditor@test.com",
+		Roles:      []string{"auditor"},
+		Status:     user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.UserService.Delete(ctx, auditor.ID) }()
+	auditorToken, err := api.TokenService.Create(ctx, token.Token{UserID: auditor.ID, Email: auditor.Email})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _ = api.TokenService.DeleteAllTokensByUserID(ctx, auditor.ID) }()
+	call := func(bearer, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+bearer)
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+	// Undefined roles grant nothing
+	w := call(auditorToken.ID, "GET", "/v1/events", "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	var e APIEvent
+	if expect.NoError(json.NewDecoder(w.Body).Decode(&e), "Decode JSON Event") {
+		expect.Equal("unauthorized: permission required: event:read", e.Message, "Event Message")
+	}
+	// Only administrators may manage roles
+	w = call(regularToken, "POST", "/v1/roles", `{"name": "auditor", "permissions": ["event:read"]}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Invalid permissions and reserved names are rejected
+	w = call(adminToken, "POST", "/v1/roles", `{"name": "auditor", "permissions": ["event:audit"]}`)
+	expect.Equal(http.StatusUnprocessableEntity, w.Code, "HTTP Status Code")
+	w = call(adminToken, "POST", "/v1/roles", `{"name": "admin", "permissions": ["event:read"]}`)
+	expect.Equal(http.StatusUnprocessableEntity, w.Code, "HTTP Status Code")
+	// Create the role
+	var rl role.Role
+	w = call(adminToken, "POST", "/v1/roles", `{"name": "auditor", "permissions": ["event:read"]}`)
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&rl), "Decode JSON Role") {
+		expect.Equal("auditor", rl.Name)
+		expect.Equal([]string{role.EventRead}, rl.Permissions)
+	}
+	defer func() { _, _ = api.RoleService.Delete(ctx, rl.ID) }()
+	// Role names are unique
+	w = call(adminToken, "POST", "/v1/roles", `{"name": "auditor"}`)
+	expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+	// List the stored and built-in roles
+	var roles []role.Role
+	w = call(adminToken, "GET", "/v1/roles", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&roles), "Decode JSON Roles") {
+		names := make([]string, 0, len(roles))
+		for _, r := range roles {
+			names = append(names, r.Name)
+		}
+		expect.Subset(names, []string{"auditor", role.Admin, role.OrgAdmin})
+	}
+	// The role takes effect immediately
+	w = call(auditorToken.ID, "GET", "/v1/events", "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	w = call(auditorToken.ID, "DELETE", "/v1/events/"+auditor.ID, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Read the auditor's effective permissions
+	var p role.Permissions
+	w = call(adminToken, "GET", "/v1/users/"+auditor.ID+"/permissions", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&p), "Decode JSON Permissions") {
+		expect.Equal(auditor.ID, p.UserID)
+		expect.Equal([]string{role.EventRead}, p.Global)
+		expect.Empty(p.Org)
+	}
+	w = call(auditorToken.ID, "GET", "/v1/users/"+auditor.ID+"/permissions", "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Update the role, limiting it to email
+	rl.Permissions = []string{role.EmailRead}
+	body, _ := json.Marshal(rl)
+	w = call(adminToken, "PUT", "/v1/roles/"+rl.ID, string(body))
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		w = call(auditorToken.ID, "GET", "/v1/events", "")
+		expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+		w = call(auditorToken.ID, "GET", "/v1/email_statuses", "")
+		expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	}
+	// Read the role
+	w = call(adminToken, "GET", "/v1/roles/"+rl.ID, "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	// Delete the role
+	w = call(adminToken, "DELETE", "/v1/roles/"+rl.ID, "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		w = call(auditorToken.ID, "GET", "/v1/email_statuses", "")
+		expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+		w = call(adminToken, "GET", "/v1/roles/"+rl.ID, "")
+		expect.Equal(http.StatusNotFound, w.Code, "HTTP Status Code")
+	}
+}
+
+func TestOrgAdminPermissions(t *testing.T) {
+	expect := assert.New(t)
+	o, orgAdmin, _ := generateOrgAdmin("Permission Org")
+	defer deleteOrgAdmin(o, orgAdmin)
+	// Organization administrators manage users and their organization, within their organization only
+	var p role.Permissions
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/v1/users/"+orgAdmin.ID+"/permissions", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	r.ServeHTTP(w, req)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&p), "Decode JSON Permissions") {
+		expect.Equal(o.ID, p.OrgID)
+		expect.Empty(p.Global)
+		expect.Equal([]string{role.OrganizationWrite, role.UserWrite}, p.Org)
+	}
+}
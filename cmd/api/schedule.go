@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	ginadapter "github.com/awslabs/aws-lambda-go-api-proxy/gin"
+
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/job"
+)
+
+// lambdaHandler returns an AWS Lambda handler that proxies API Gateway requests to the router, and runs the
+// scheduled tasks (e.g. publishing and unpublishing Content) in response to scheduled (EventBridge) events.
+func lambdaHandler(ginLambda *ginadapter.GinLambdaV2) func(context.Context, json.RawMessage) (any, error) {
+	return func(ctx context.Context, payload json.RawMessage) (any, error) {
+		var scheduled events.CloudWatchEvent
+		if err := json.Unmarshal(payload, &scheduled); err == nil && isScheduledEvent(scheduled) {
+			return publishDueContent(ctx)
+		}
+		var req events.APIGatewayV2HTTPRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, fmt.Errorf("error parsing API Gateway request: %w", err)
+		}
+		return ginLambda.ProxyWithContext(ctx, req)
+	}
+}
+
+// isScheduledEvent returns true if the event was sent by an EventBridge schedule.
+func isScheduledEvent(e events.CloudWatchEvent) bool {
+	return e.Source == "aws.events" && e.DetailType == "Scheduled Event"
+}
+
+// publishDueContent publishes and unpublishes the Content scheduled to change by now, recording it in a Job.
+// A failed Job returns an error, so that the (idempotent) task is retried.
+func publishDueContent(ctx context.Context) (job.Job, error) {
+	jb, _, err := api.JobService.Create(ctx, job.Job{
+		Kind:    job.ContentSchedule,
+		Message: "publish and unpublish scheduled content",
+	})
+	if err != nil {
+		return jb, fmt.Errorf("error creating content schedule job: %w", err)
+	}
+	jb = api.PublishDue(ctx, jb, time.Now())
+	_, _, _ = api.EventService.Create(ctx, event.Event{
+		EntityID:   jb.ID,
+		EntityType: jb.Type(),
+		LogLevel:   event.INFO,
+		Message:    jb.String(),
+	})
+	if jb.Status == job.FAILED {
+		return jb, fmt.Errorf("content schedule job %s failed", jb.ID)
+	}
+	return jb, nil
+}
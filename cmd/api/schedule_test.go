@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	ginadapter "github.com/awslabs/aws-lambda-go-api-proxy/gin"
+	"github.com/stretchr/testify/assert"
+
+	"versionary-api/pkg/content"
+	"versionary-api/pkg/job"
+)
+
+func TestContentSchedule(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	now := time.Now()
+	publish := func(c content.Content) content.Content {
+		c.Type = content.ARTICLE
+		c, _, err := api.ContentService.Create(ctx, c)
+		expect.NoError(err)
+		c, err = api.ContentService.Transition(ctx, c, content.IN_REVIEW, "", "Editor", "")
+		expect.NoError(err)
+		c, err = api.ContentService.Transition(ctx, c, content.PUBLISHED, "", "Publisher", "")
+		expect.NoError(err)
+		return c
+	}
+	get := func(bearer, path string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", path, nil)
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		req.RemoteAddr = "10.0.21.1:1234"
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Embargoed Content is visible only to editors
+	embargoed := publish(content.Content{
+		Body:      content.Section{Title: "Embargoed", Text: "Coming soon."},
+		PublishAt: now.Add(time.Hour),
+	})
+	defer func() { _, _ = api.ContentService.Delete(ctx, embargoed.ID) }()
+	expect.True(embargoed.PublishedAt.IsZero())
+	expect.Equal(http.StatusNotFound, get("", "/v1/contents/"+embargoed.ID))
+	expect.Equal(http.StatusNotFound, get("", "/v1/contents/"+embargoed.ID+"/versions/"+embargoed.VersionID))
+	expect.Equal(http.StatusOK, get(adminToken, "/v1/contents/"+embargoed.ID))
+
+	// Content whose embargo has lifted is visible, and due to be marked as live
+	lifted := publish(content.Content{
+		Body:      content.Section{Title: "Lifted", Text: "Now showing."},
+		PublishAt: now.Add(time.Minute),
+	})
+	defer func() { _, _ = api.ContentService.Delete(ctx, lifted.ID) }()
+	lifted.PublishAt = now.Add(-time.Minute)
+	lifted, _, err := api.ContentService.Update(ctx, lifted)
+	expect.NoError(err)
+	expect.Equal(http.StatusOK, get("", "/v1/contents/"+lifted.ID))
+
+	// Expired Content is no longer visible, and due to be archived
+	expired := publish(content.Content{
+		Body:        content.Section{Title: "Expired", Text: "Gone."},
+		UnpublishAt: now.Add(time.Minute),
+	})
+	defer func() { _, _ = api.ContentService.Delete(ctx, expired.ID) }()
+	expired.UnpublishAt = now.Add(-time.Minute)
+	expired, _, err = api.ContentService.Update(ctx, expired)
+	expect.NoError(err)
+	expect.Equal(http.StatusNotFound, get("", "/v1/contents/"+expired.ID))
+
+	// A scheduled event runs the publication schedule
+	handler := lambdaHandler(ginadapter.NewV2(r))
+	payload, _ := json.Marshal(events.CloudWatchEvent{
+		Source:     "aws.events",
+		DetailType: "Scheduled Event",
+		Time:       now,
+	})
+	result, err := handler(ctx, payload)
+	if expect.NoError(err) && expect.IsType(job.Job{}, result) {
+		jb := result.(job.Job)
+		defer func() { _, _ = api.JobService.Delete(ctx, jb.ID) }()
+		expect.Equal(job.ContentSchedule, jb.Kind)
+		expect.Equal(job.SUCCEEDED, jb.Status)
+		expect.GreaterOrEqual(jb.Changed, 2)
+	}
+	if c, err := api.ContentService.Read(ctx, lifted.ID); expect.NoError(err) {
+		expect.Equal(content.PUBLISHED, c.Status)
+		expect.False(c.PublishedAt.IsZero())
+	}
+	if c, err := api.ContentService.Read(ctx, expired.ID); expect.NoError(err) {
+		expect.Equal(content.ARCHIVED, c.Status)
+	}
+	if due, err := api.ContentService.ReadDue(ctx, now); expect.NoError(err) {
+		expect.Empty(due)
+	}
+
+	// Other events are API Gateway requests
+	payload, _ = json.Marshal(events.APIGatewayV2HTTPRequest{
+		Version:  "2.0",
+		RawPath:  "/v1/contents/" + lifted.ID,
+		RouteKey: "$default",
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{Method: "GET", Path: "/v1/contents/" + lifted.ID, SourceIP: "10.0.21.1"},
+		},
+	})
+	result, err = handler(ctx, payload)
+	if expect.NoError(err) && expect.IsType(events.APIGatewayV2HTTPResponse{}, result) {
+		expect.Equal(http.StatusOK, result.(events.APIGatewayV2HTTPResponse).StatusCode)
+	}
+}
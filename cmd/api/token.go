@@ -5,43 +5,56 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/voxtechnica/tuid-go"
 	v "github.com/voxtechnica/versionary"
 
+	"versionary-api/pkg/device"
 	"versionary-api/pkg/event"
+	"versionary-api/pkg/role"
 	"versionary-api/pkg/token"
 	"versionary-api/pkg/user"
 )
 
 // registerTokenRoutes initializes the Token routes.
 func registerTokenRoutes(r *gin.Engine) {
-	r.POST("/v1/tokens", createToken)
-	r.GET("/v1/tokens", userAuthenticator(), readTokens)
-	r.GET("/v1/tokens/:id", userAuthenticator(), readToken)
-	r.HEAD("/v1/tokens/:id", userAuthenticator(), existsToken)
-	r.DELETE("/v1/tokens/:id", userAuthenticator(), deleteToken)
-	r.GET("/logout", userAuthenticator(), logout)
-	r.POST("/login", login)
-	r.GET("/v1/token_ids", roleAuthorizer("admin"), readTokenIDs)
-	r.GET("/v1/token_users", roleAuthorizer("admin"), readTokenUsers)
+	handleRoutes(r, []route{
+		{"POST", "/v1/tokens", public, global, createToken},
+		{"GET", "/v1/tokens", authenticated, global, readTokens},
+		{"GET", "/v1/tokens/:id", authenticated, global, readToken},
+		{"HEAD", "/v1/tokens/:id", authenticated, global, existsToken},
+		{"DELETE", "/v1/tokens/:id", authenticated, global, deleteToken},
+		{"GET", "/logout", authenticated, global, logout},
+		{"POST", "/login", public, global, login},
+		{"GET", "/v1/token_ids", role.TokenRead, global, readTokenIDs},
+		{"GET", "/v1/token_users", role.TokenRead, global, readTokenUsers},
+	})
 }
 
-// createToken receives an OAuth TokenRequest, validates the User password,
-// creates a new Token, and returns an OAuth Response.
+// createToken receives an OAuth TokenRequest and, depending on the grant type, either validates the User
+// password or exchanges a RefreshToken. It creates a new short-lived access Token and a RefreshToken, and
+// returns an OAuth Response. Each RefreshToken may be used only once; reusing one revokes its token family.
 //
 // @Summary Create Token
 // @Description Create a new Token
-// @Description Create a new OAuth Bearer Token.
+// @Description Create a new OAuth Bearer Token, using either the "password" grant (default)
+// @Description or the "refresh_token" grant. The response includes a replacement RefreshToken.
+// @Description Reusing a RefreshToken revokes all Tokens descended from the same password grant.
+// @Description Users enrolled in TOTP must also supply a code (TOTP or recovery code) with the password grant.
+// @Description Members of several organizations may select the active organization with the orgId field.
+// @Description For a standards-compliant OAuth 2.0 token endpoint, see /oauth/token.
 // @Tags Token
 // @Accept json
 // @Produce json
 // @Param TokenRequest body token.Request true "Token Request"
 // @Success 201 {object} token.Response "Token Response"
-// @Failure 400 {object} APIEvent "Bad Request (invalid JSON body)"
-// @Failure 401 {object} APIEvent "Unauthenticated (invalid username or password)"
-// @Failure 403 {object} APIEvent "Unauthorized (user is disabled)"
+// @Failure 400 {object} APIEvent "Bad Request (invalid JSON body or unsupported grant type)"
+// @Failure 401 {object} APIEvent "Unauthenticated (invalid username, password, second factor code, or refresh token)"
+// @Failure 403 {object} APIEvent "Unauthorized (user is disabled, email address is not verified, or not a member of the organization)"
+// @Failure 429 {object} APIEvent "Too Many Requests (too many failed login attempts; see Retry-After)"
 // @Failure 500 {object} APIEvent "Internal Server Error"
 // @Header 201 {string} Location "URL of the newly created Token"
 // @Router /v1/tokens [post]
@@ -52,68 +65,438 @@ func createToken(c *gin.Context) {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
 		return
 	}
+	var t token.Token
+	var rt token.RefreshToken
+	var ge *grantError
+	switch req.GrantType {
+	case "", token.GrantPassword:
+		t, rt, ge = passwordGrant(c, req.Username, req.Password, req.Code, req.DeviceID, req.OrgID)
+	case token.GrantRefreshToken:
+		t, rt, ge = refreshTokenGrant(c, req.RefreshToken)
+	default:
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: unsupported grant type %s", req.GrantType))
+		return
+	}
+	if ge != nil {
+		abortWithGrantError(c, ge)
+		return
+	}
+	accessToken, ge := signAccessToken(c, t, nil)
+	if ge != nil {
+		abortWithGrantError(c, ge)
+		return
+	}
+	// Return an OAuth Response
+	c.Header("Location", c.Request.URL.String()+"/"+t.ID)
+	c.JSON(http.StatusCreated, token.Response{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresAt:    t.ExpiresAt,
+		RefreshToken: rt.ID,
+	})
+}
+
+// grantError describes a failed token grant, so that it may be reported either as an APIEvent
+// (by /v1/tokens and /login) or as an OAuth 2.0 Error Response (by /oauth/token).
+type grantError struct {
+	Status     int           // HTTP status code for an APIEvent response
+	Code       string        // OAuth 2.0 error code
+	Err        error         // the error, possibly a logged event.Event
+	RetryAfter time.Duration // time remaining in a temporary lockout, if any
+}
+
+// newGrantError creates a grantError with the specified HTTP status code, OAuth 2.0 error code, and error.
+func newGrantError(status int, code string, err error) *grantError {
+	return &grantError{Status: status, Code: code, Err: err}
+}
+
+// abortWithGrantError aborts the request with an APIEvent describing the failed token grant.
+func abortWithGrantError(c *gin.Context, ge *grantError) {
+	if ge.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(ge.RetryAfter.Seconds())))
+	}
+	abortWithError(c, ge.Status, ge.Err)
+}
+
+// signAccessToken returns the bearer credential for an access Token. By default, this is the opaque Token ID.
+// If the application is configured to issue JWTs, it is a signed JWT embedding the User's identity and roles,
+// and their Membership roles in the active Organization, which may be verified without reading the Token,
+// User, or Membership from the database.
+func signAccessToken(c *gin.Context, t token.Token, scopes []string) (string, *grantError) {
+	if api.JWTAlgorithm == "" {
+		return t.ID, nil
+	}
+	u, err := api.UserService.Read(c, t.UserID)
+	var m user.Membership
+	if err == nil && t.OrgID != "" && t.OrgID != u.OrgID {
+		m, err = api.UserService.Memberships.ReadMembership(c, u.ID, t.OrgID)
+	}
+	if err == nil {
+		claims := token.NewClaims(t, scopes, api.APIURL)
+		claims.GivenName = u.GivenName
+		claims.FamilyName = u.FamilyName
+		claims.Roles = u.Roles
+		claims.OrgID = u.OrgID
+		claims.OrgName = m.OrgName
+		claims.OrgRoles = m.Roles
+		var jwt string
+		if jwt, err = api.SignJWT(c, claims); err == nil {
+			return jwt, nil
+		}
+	}
+	e, _, _ := api.EventService.Create(c, event.Event{
+		UserID:     t.UserID,
+		EntityID:   t.ID,
+		EntityType: t.Type(),
+		LogLevel:   event.ERROR,
+		Message:    fmt.Errorf("sign token %s for %s: %w", t.ID, t.UserID, err).Error(),
+		URI:        c.Request.URL.String(),
+		Err:        err,
+	})
+	return "", newGrantError(http.StatusInternalServerError, token.ErrCodeServerError, e)
+}
+
+// passwordGrant validates the User password, and creates a new access Token and RefreshToken,
+// linked to the requesting Device. If an Organization is specified, the User must be a member,
+// and it becomes the active Organization for the Token.
+func passwordGrant(c *gin.Context, username, password, code, deviceID, orgID string) (token.Token, token.RefreshToken, *grantError) {
+	u, ge := authenticateUser(c, username, password, code)
+	if ge != nil {
+		return token.Token{}, token.RefreshToken{}, ge
+	}
+	orgID, ge = selectTokenOrg(c, u, orgID)
+	if ge != nil {
+		return token.Token{}, token.RefreshToken{}, ge
+	}
+	// Create a new token for the User
+	t, rt, err := api.TokenService.CreateWithRefresh(c, token.Token{
+		UserID:   u.ID,
+		Email:    u.Email,
+		OrgID:    orgID,
+		DeviceID: sessionDevice(c, deviceID, u.ID),
+		ClientIP: c.ClientIP(),
+	})
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     u.ID,
+			EntityID:   t.ID,
+			EntityType: "Token",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("create token for %s: %w", u.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		return t, rt, newGrantError(http.StatusInternalServerError, token.ErrCodeServerError, e)
+	}
+	// Log the token creation
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     t.UserID,
+		EntityID:   t.ID,
+		EntityType: t.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("created Token %s for User %s", t.ID, u.ID),
+		URI:        c.Request.URL.String(),
+	})
+	return t, rt, nil
+}
+
+// selectTokenOrg validates the Organization selected for a new Token, returning its ID, or an empty string
+// for the User's primary Organization. The User must be a member of any other Organization.
+func selectTokenOrg(c *gin.Context, u user.User, orgID string) (string, *grantError) {
+	if orgID == "" || orgID == u.OrgID {
+		return "", nil
+	}
+	if !api.UserService.Memberships.Exists(c, u.ID, orgID) {
+		return "", newGrantError(http.StatusForbidden, token.ErrCodeInvalidScope,
+			fmt.Errorf("unauthorized: user is not a member of organization %s", orgID))
+	}
+	return orgID, nil
+}
+
+// sessionDevice identifies the Device requesting a new session: the supplied Device, if any (recording that it
+// was seen), or else a new Device, created from the User-Agent header. Device tracking is best-effort, so errors
+// are logged, and an empty Device ID is returned.
+func sessionDevice(c *gin.Context, deviceID, userID string) string {
+	header := strings.Join(c.Request.Header.Values("User-Agent"), " ")
+	var d device.Device
+	var err error
+	if tuid.IsValid(tuid.TUID(deviceID)) {
+		d, _, err = api.DeviceService.Update(c, deviceID, header, userID)
+	} else {
+		d, _, err = api.DeviceService.Create(c, header, userID)
+	}
+	if err != nil {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     userID,
+			EntityID:   d.ID,
+			EntityType: "Device",
+			LogLevel:   event.WARN,
+			Message:    fmt.Errorf("session device for user %s: %w", userID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		return ""
+	}
+	return d.ID
+}
+
+// authenticateUser validates the User password and second factor, enforcing temporary lockouts.
+// A legacy password hash is upgraded after a successful login.
+func authenticateUser(c *gin.Context, username, password, code string) (user.User, *grantError) {
 	// Read the associated User
-	u, err := api.UserService.Read(c, req.Username)
+	u, err := api.UserService.Read(c, username)
 	if err != nil && errors.Is(err, v.ErrNotFound) {
-		abortWithError(c, http.StatusUnauthorized, errors.New("unauthenticated: invalid username or password"))
-		return
+		if ge := loginLocked(c, ""); ge != nil {
+			return u, ge
+		}
+		recordLoginFailure(c, "")
+		return u, newGrantError(http.StatusUnauthorized, token.ErrCodeInvalidGrant, errors.New("unauthenticated: invalid username or password"))
 	}
 	if err != nil {
 		e, _, _ := api.EventService.Create(c, event.Event{
 			UserID:     contextUserID(c),
 			EntityType: "Token",
 			LogLevel:   event.ERROR,
-			Message:    fmt.Errorf("create token for %s: %w", req.Username, err).Error(),
+			Message:    fmt.Errorf("create token for %s: %w", username, err).Error(),
 			URI:        c.Request.URL.String(),
 			Err:        err,
 		})
-		abortWithError(c, http.StatusInternalServerError, e)
-		return
+		return u, newGrantError(http.StatusInternalServerError, token.ErrCodeServerError, e)
 	}
 	// Check if the user is disabled
 	if u.Status == user.DISABLED {
-		abortWithError(c, http.StatusForbidden, errors.New("unauthorized: user is disabled"))
-		return
+		return u, newGrantError(http.StatusForbidden, token.ErrCodeInvalidGrant, errors.New("unauthorized: user is disabled"))
+	}
+	// Refuse login attempts during a temporary lockout
+	if ge := loginLocked(c, u.ID); ge != nil {
+		return u, ge
 	}
 	// Validate the password
-	if !u.ValidPassword(req.Password) {
-		abortWithError(c, http.StatusUnauthorized, errors.New("unauthenticated: invalid username or password"))
-		return
+	if !u.ValidPassword(password) {
+		recordLoginFailure(c, u.ID)
+		return u, newGrantError(http.StatusUnauthorized, token.ErrCodeInvalidGrant, errors.New("unauthenticated: invalid username or password"))
+	}
+	// Verify the second factor, if the User has enrolled
+	u, ge := verifySecondFactor(c, u, code)
+	if ge != nil {
+		return u, ge
+	}
+	recordLoginSuccess(c, u.ID)
+	// Optionally, refuse tokens to Users that have not verified their email address
+	if api.RequireVerified && u.Status == user.PENDING {
+		return u, newGrantError(http.StatusForbidden, token.ErrCodeInvalidGrant, errors.New("unauthorized: email address is not verified"))
+	}
+	// Upgrade a legacy password hash
+	return rehashPassword(c, u, password), nil
+}
+
+// refreshTokenGrant exchanges a RefreshToken for a new access Token and a replacement RefreshToken.
+// If the RefreshToken has already been used, the whole token family is revoked.
+func refreshTokenGrant(c *gin.Context, refreshID string) (token.Token, token.RefreshToken, *grantError) {
+	if refreshID == "" {
+		return token.Token{}, token.RefreshToken{}, newGrantError(http.StatusBadRequest, token.ErrCodeInvalidRequest, errors.New("bad request: missing refresh token"))
+	}
+	t, rt, err := api.TokenService.Refresh(c, refreshID, c.ClientIP())
+	if err != nil && errors.Is(err, token.ErrRefreshTokenReused) {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     rt.UserID,
+			EntityID:   rt.ID,
+			EntityType: rt.Type(),
+			OtherIDs:   []string{rt.FamilyID},
+			LogLevel:   event.WARN,
+			Message:    fmt.Sprintf("reused RefreshToken %s for User %s: revoked token family %s", rt.ID, rt.UserID, rt.FamilyID),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		return t, rt, newGrantError(http.StatusUnauthorized, token.ErrCodeInvalidGrant, errors.New("unauthenticated: invalid refresh token"))
+	}
+	if err != nil && errors.Is(err, token.ErrInvalidRefreshToken) {
+		return t, rt, newGrantError(http.StatusUnauthorized, token.ErrCodeInvalidGrant, errors.New("unauthenticated: invalid refresh token"))
 	}
-	// Create a new token for the User
-	t, err := api.TokenService.Create(c, token.Token{
-		UserID: u.ID,
-		Email:  u.Email,
-	})
 	if err != nil {
 		e, _, _ := api.EventService.Create(c, event.Event{
-			UserID:     u.ID,
-			EntityID:   t.ID,
-			EntityType: "Token",
+			UserID:     rt.UserID,
+			EntityID:   refreshID,
+			EntityType: "RefreshToken",
 			LogLevel:   event.ERROR,
-			Message:    fmt.Errorf("create token for %s: %w", u.ID, err).Error(),
+			Message:    fmt.Errorf("refresh token %s: %w", refreshID, err).Error(),
 			URI:        c.Request.URL.String(),
 			Err:        err,
 		})
-		abortWithError(c, http.StatusInternalServerError, e)
-		return
-	}
-	// Log the token creation
+		return t, rt, newGrantError(http.StatusInternalServerError, token.ErrCodeServerError, e)
+	}
+	// Verify that the User still exists and is not disabled
+	u, err := api.UserService.Read(c, t.UserID)
+	if err != nil || u.Status == user.DISABLED {
+		_ = api.TokenService.RevokeFamily(c, rt.FamilyID)
+		if err != nil && !errors.Is(err, v.ErrNotFound) {
+			e, _, _ := api.EventService.Create(c, event.Event{
+				UserID:     t.UserID,
+				EntityID:   rt.ID,
+				EntityType: rt.Type(),
+				LogLevel:   event.ERROR,
+				Message:    fmt.Errorf("refresh token %s: read user %s: %w", refreshID, t.UserID, err).Error(),
+				URI:        c.Request.URL.String(),
+				Err:        err,
+			})
+			return t, rt, newGrantError(http.StatusInternalServerError, token.ErrCodeServerError, e)
+		}
+		if err != nil {
+			return t, rt, newGrantError(http.StatusUnauthorized, token.ErrCodeInvalidGrant, errors.New("unauthenticated: invalid refresh token"))
+		}
+		return t, rt, newGrantError(http.StatusForbidden, token.ErrCodeInvalidGrant, errors.New("unauthorized: user is disabled"))
+	}
+	// Log the token refresh
 	_, _, _ = api.EventService.Create(c, event.Event{
 		UserID:     t.UserID,
 		EntityID:   t.ID,
 		EntityType: t.Type(),
+		OtherIDs:   []string{refreshID, rt.ID},
 		LogLevel:   event.INFO,
-		Message:    fmt.Sprintf("created Token %s for User %s", t.ID, u.ID),
+		Message:    fmt.Sprintf("refreshed Token %s for User %s with RefreshToken %s", t.ID, t.UserID, refreshID),
 		URI:        c.Request.URL.String(),
 	})
-	// Return an OAuth Response
-	c.Header("Location", c.Request.URL.String()+"/"+t.ID)
-	c.JSON(http.StatusCreated, token.Response{
-		AccessToken: t.ID,
-		TokenType:   "Bearer",
-		ExpiresAt:   t.ExpiresAt,
+	return t, rt, nil
+}
+
+// verifySecondFactor checks the supplied TOTP or recovery code for a User that has enrolled in TOTP.
+// If verification fails, a grantError is returned.
+func verifySecondFactor(c *gin.Context, u user.User, code string) (user.User, *grantError) {
+	verified, err := api.UserService.VerifySecondFactor(c, u, code)
+	if err != nil && errors.Is(err, user.ErrSecondFactorRequired) {
+		return u, newGrantError(http.StatusUnauthorized, token.ErrCodeInvalidGrant, errors.New("unauthenticated: second factor code required"))
+	}
+	if err != nil && errors.Is(err, user.ErrInvalidSecondFactor) {
+		recordLoginFailure(c, u.ID)
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     u.ID,
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.WARN,
+			Message:    fmt.Sprintf("invalid second factor code for User %s", u.ID),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		return u, newGrantError(http.StatusUnauthorized, token.ErrCodeInvalidGrant, errors.New("unauthenticated: invalid second factor code"))
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     u.ID,
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("verify second factor for %s: %w", u.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		return u, newGrantError(http.StatusInternalServerError, token.ErrCodeServerError, e)
+	}
+	if len(verified.RecoveryCodes) < len(u.RecoveryCodes) {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     u.ID,
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.INFO,
+			Message:    fmt.Sprintf("used recovery code for User %s: %d remaining", u.ID, len(verified.RecoveryCodes)),
+			URI:        c.Request.URL.String(),
+		})
+	}
+	return verified, nil
+}
+
+// loginLocked checks whether login attempts are temporarily refused for the User or the client IP address.
+// If so, a grantError with a RetryAfter duration is returned.
+func loginLocked(c *gin.Context, userID string) *grantError {
+	l, locked, err := api.LockoutService.Check(c, userID, c.ClientIP())
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     userID,
+			EntityType: "Lockout",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("check lockout for user %s, ip %s: %w", userID, c.ClientIP(), err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		return newGrantError(http.StatusInternalServerError, token.ErrCodeServerError, e)
+	}
+	if locked {
+		retryAfter := l.RetryAfter(time.Now())
+		ge := newGrantError(http.StatusTooManyRequests, token.ErrCodeInvalidGrant, fmt.Errorf("too many requests: too many failed login attempts, retry after %s", retryAfter))
+		ge.RetryAfter = retryAfter
+		return ge
+	}
+	return nil
+}
+
+// recordLoginFailure records a failed login attempt for the User (if known) and the client IP address,
+// logging a WARN event for each lockout imposed as a result. Errors are logged, but do not affect the response.
+func recordLoginFailure(c *gin.Context, userID string) {
+	locked, err := api.LockoutService.RecordFailure(c, userID, c.ClientIP())
+	if err != nil {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     userID,
+			EntityType: "Lockout",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("record login failure for user %s, ip %s: %w", userID, c.ClientIP(), err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+	}
+	for _, l := range locked {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     userID,
+			EntityID:   userID,
+			EntityType: l.Type(),
+			LogLevel:   event.WARN,
+			Message:    fmt.Sprintf("locked %s %s until %s after %d failed login attempts", l.Kind, l.Subject, l.LockedUntil.Format(time.RFC3339), l.Failures),
+			URI:        c.Request.URL.String(),
+		})
+	}
+}
+
+// recordLoginSuccess clears the failed login attempts for a User. Errors are logged, but do not affect the response.
+func recordLoginSuccess(c *gin.Context, userID string) {
+	if err := api.LockoutService.RecordSuccess(c, userID); err != nil {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     userID,
+			EntityType: "Lockout",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("clear login failures for user %s: %w", userID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+	}
+}
+
+// rehashPassword upgrades a legacy password hash after a successful login. Failure to upgrade
+// the hash is logged, but it does not prevent the login; the upgrade is retried on the next login.
+func rehashPassword(c *gin.Context, u user.User, password string) user.User {
+	if !u.PasswordNeedsRehash() {
+		return u
+	}
+	scheme := user.PasswordHashScheme(u.PasswordHash)
+	updated, err := api.UserService.RehashPassword(c, u, password)
+	if err != nil {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     u.ID,
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.WARN,
+			Message:    fmt.Errorf("rehash %s password for %s: %w", scheme, u.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		return u
+	}
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     u.ID,
+		EntityID:   u.ID,
+		EntityType: u.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("upgraded %s password hash for User %s", scheme, u.ID),
+		URI:        c.Request.URL.String(),
 	})
+	return updated
 }
 
 // readTokens returns a paginated list of Tokens for the specified User.
@@ -140,9 +523,9 @@ func readTokens(c *gin.Context) {
 		abortWithError(c, http.StatusUnauthorized, errors.New("unauthenticated: read tokens"))
 		return
 	}
-	// Only administrators can read tokens for other users
+	// Only users with the token:read permission can read tokens for other users
 	idOrEmail := c.DefaultQuery("user", cUser.ID)
-	if !(idOrEmail == cUser.ID || idOrEmail == cUser.Email) && !cUser.HasRole("admin") {
+	if !(idOrEmail == cUser.ID || idOrEmail == cUser.Email) && !contextPermissions(c).Has(role.TokenRead) {
 		abortWithError(c, http.StatusForbidden, errors.New("unauthorized: read tokens"))
 		return
 	}
@@ -232,8 +615,8 @@ func readToken(c *gin.Context) {
 		abortWithError(c, http.StatusInternalServerError, e)
 		return
 	}
-	// Only administrators can read tokens for other users
-	if t.UserID != cUser.ID && !cUser.HasRole("admin") {
+	// Only users with the token:read permission can read tokens for other users
+	if t.UserID != cUser.ID && !contextPermissions(c).Has(role.TokenRead) {
 		abortWithError(c, http.StatusForbidden, errors.New("unauthorized: read token"))
 		return
 	}
@@ -313,8 +696,8 @@ func deleteToken(c *gin.Context) {
 		abortWithError(c, http.StatusInternalServerError, e)
 		return
 	}
-	// Only administrators can delete tokens for other users
-	if t.UserID != cUser.ID && !cUser.HasRole("admin") {
+	// Only users with the token:write permission can delete tokens for other users
+	if t.UserID != cUser.ID && !contextPermissions(c).Has(role.TokenWrite) {
 		abortWithError(c, http.StatusForbidden, errors.New("unauthorized: delete token"))
 		return
 	}
@@ -532,14 +915,16 @@ type LoginResponse struct {
 // @Summary Login
 // @Description Login
 // @Description Create a Token for the specified User, returning both.
+// @Description Users enrolled in TOTP must also supply a code (TOTP or recovery code).
 // @Tags Token
 // @Accept json
 // @Produce json
 // @Param TokenRequest body token.Request true "Token Request"
 // @Success 201 {object} LoginResponse "Login Response"
 // @Failure 400 {object} APIEvent "Bad Request (invalid JSON body)"
-// @Failure 401 {object} APIEvent "Unauthenticated (invalid username or password)"
-// @Failure 403 {object} APIEvent "Unauthorized (user is disabled)"
+// @Failure 401 {object} APIEvent "Unauthenticated (invalid username, password, or second factor code)"
+// @Failure 403 {object} APIEvent "Unauthorized (user is disabled, email address is not verified, or not a member of the organization)"
+// @Failure 429 {object} APIEvent "Too Many Requests (too many failed login attempts; see Retry-After)"
 // @Failure 500 {object} APIEvent "Internal Server Error"
 // @Router /login [post]
 func login(c *gin.Context) {
@@ -549,39 +934,24 @@ func login(c *gin.Context) {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
 		return
 	}
-	// Read the associated User
-	u, err := api.UserService.Read(c, req.Username)
-	if err != nil && errors.Is(err, v.ErrNotFound) {
-		abortWithError(c, http.StatusUnauthorized, errors.New("unauthenticated: invalid username or password"))
+	u, ge := authenticateUser(c, req.Username, req.Password, req.Code)
+	if ge != nil {
+		abortWithGrantError(c, ge)
 		return
 	}
-	if err != nil {
-		e, _, _ := api.EventService.Create(c, event.Event{
-			UserID:     contextUserID(c),
-			EntityType: "Token",
-			LogLevel:   event.ERROR,
-			Message:    fmt.Errorf("create token for %s: %w", u.Email, err).Error(),
-			URI:        c.Request.URL.String(),
-			Err:        err,
-		})
-		abortWithError(c, http.StatusInternalServerError, e)
-		return
-	}
-	// Check if the user is disabled
-	if u.Status == user.DISABLED {
-		abortWithError(c, http.StatusForbidden, errors.New("unauthorized: user is disabled"))
-		return
-	}
-	// Validate the password
-	if !u.ValidPassword(req.Password) {
-		abortWithError(c, http.StatusUnauthorized, errors.New("unauthenticated: invalid username or password"))
+	orgID, ge := selectTokenOrg(c, u, req.OrgID)
+	if ge != nil {
+		abortWithGrantError(c, ge)
 		return
 	}
 
 	// Create a new token for the User
 	t, err := api.TokenService.Create(c, token.Token{
-		UserID: u.ID,
-		Email:  u.Email,
+		UserID:   u.ID,
+		Email:    u.Email,
+		OrgID:    orgID,
+		DeviceID: sessionDevice(c, req.DeviceID, u.ID),
+		ClientIP: c.ClientIP(),
 	})
 	if err != nil {
 		e, _, _ := api.EventService.Create(c, event.Event{
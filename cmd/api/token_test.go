@@ -2,11 +2,16 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+	"versionary-api/pkg/event"
 	"versionary-api/pkg/token"
 	"versionary-api/pkg/user"
 
@@ -471,3 +476,198 @@ func TestTokenUsers(t *testing.T) {
 		}
 	}
 }
+
+func TestLegacyPasswordRehash(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	// Create a user with a legacy (salted SHA256) password hash
+	u, _, err := api.UserService.Create(ctx, user.User{
+		GivenName: "legacy_user",
+		Email:     "legacy_user@test.com",
+		Status:    user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	hash := sha256.Sum256([]byte(u.ID + "legacyabcd1234"))
+	u.PasswordHash = hex.EncodeToString(hash[:])
+	u, err = api.UserService.Write(ctx, u)
+	if !expect.NoError(err) || !expect.True(u.PasswordNeedsRehash(), "Legacy Password Hash") {
+		return
+	}
+	// Create a token with the legacy password
+	j, err := json.Marshal(token.Request{
+		GrantType: "password",
+		Username:  "legacy_user@test.com",
+		Password:  "legacyabcd1234",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/v1/tokens", bytes.NewBuffer(j))
+	req.Header.Set("Content-Type", "application/json;charset=UTF-8")
+	req.Header.Set("Accept", "application/json;charset=UTF-8")
+	if expect.NoError(err) {
+		r.ServeHTTP(w, req)
+		expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code")
+	}
+	// The password hash was upgraded
+	u2, err := api.UserService.Read(ctx, u.ID)
+	if expect.NoError(err) {
+		expect.NotEqual(u.VersionID, u2.VersionID, "New Version")
+		expect.Equal(user.HashSchemeArgon2id, user.PasswordHashScheme(u2.PasswordHash), "Password Hash Scheme")
+		expect.True(u2.ValidPassword("legacyabcd1234"), "Valid Password")
+	}
+	// Login still works with the upgraded hash
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/login", bytes.NewBuffer(j))
+	req.Header.Set("Content-Type", "application/json;charset=UTF-8")
+	req.Header.Set("Accept", "application/json;charset=UTF-8")
+	if expect.NoError(err) {
+		r.ServeHTTP(w, req)
+		expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code")
+	}
+	// Clean up
+	_ = api.TokenService.DeleteAllTokensByUserID(ctx, u.ID)
+	_, _ = api.UserService.Delete(ctx, u.ID)
+}
+
+func TestRefreshTokenGrant(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	u, _, err := api.UserService.Create(ctx, user.User{
+		GivenName: "refresh_user",
+		Email:     "refresh_user@test.com",
+		Password:  "refreshabcd1234",
+		Status:    user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	postToken := func(req token.Request) (*httptest.ResponseRecorder, token.Response) {
+		var res token.Response
+		j, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/v1/tokens", bytes.NewBuffer(j)))
+		if w.Code == http.StatusCreated {
+			expect.NoError(json.NewDecoder(w.Body).Decode(&res), "Decode JSON Token Response")
+		}
+		return w, res
+	}
+	// Password grant: short-lived access token plus refresh token
+	w, res1 := postToken(token.Request{GrantType: "password", Username: u.Email, Password: "refreshabcd1234"})
+	if !expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") {
+		return
+	}
+	expect.True(tuid.IsValid(tuid.TUID(res1.RefreshToken)), "Valid Refresh Token")
+	expect.True(res1.ExpiresAt.Before(time.Now().Add(token.AccessTokenLifetime+time.Minute)), "Short-lived Access Token")
+	// Refresh grant: missing refresh token
+	w, _ = postToken(token.Request{GrantType: "refresh_token"})
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	// Unsupported grant type
+	w, _ = postToken(token.Request{GrantType: "implicit"})
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	// Refresh grant: rotate the refresh token
+	w, res2 := postToken(token.Request{GrantType: "refresh_token", RefreshToken: res1.RefreshToken})
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") {
+		expect.NotEqual(res1.AccessToken, res2.AccessToken, "New Access Token")
+		expect.NotEqual(res1.RefreshToken, res2.RefreshToken, "New Refresh Token")
+		expect.True(api.TokenService.Exists(ctx, res2.AccessToken), "Access Token Exists")
+	}
+	// Reuse of the rotated refresh token revokes the token family
+	w, _ = postToken(token.Request{GrantType: "refresh_token", RefreshToken: res1.RefreshToken})
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	expect.False(api.TokenService.Exists(ctx, res1.AccessToken), "Original Access Token Revoked")
+	expect.False(api.TokenService.Exists(ctx, res2.AccessToken), "Rotated Access Token Revoked")
+	w, _ = postToken(token.Request{GrantType: "refresh_token", RefreshToken: res2.RefreshToken})
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	// Disabled users may not refresh tokens
+	_, res3 := postToken(token.Request{Username: u.Email, Password: "refreshabcd1234"})
+	u.Status = user.DISABLED
+	_, err = api.UserService.Write(ctx, u)
+	expect.NoError(err)
+	w, _ = postToken(token.Request{GrantType: "refresh_token", RefreshToken: res3.RefreshToken})
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	expect.False(api.TokenService.Exists(ctx, res3.AccessToken), "Access Token Revoked")
+	// Clean up
+	_ = api.TokenService.DeleteAllTokensByUserID(ctx, u.ID)
+	_, _ = api.UserService.Delete(ctx, u.ID)
+}
+
+func TestLoginLockout(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	u, _, err := api.UserService.Create(ctx, user.User{
+		GivenName: "lockout_user",
+		Email:     "lockout_user@test.com",
+		Password:  "lockoutabcd1234",
+		Status:    user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	ip := "203.0.113.7"
+	login := func(password string) *httptest.ResponseRecorder {
+		j, err := json.Marshal(token.Request{Username: u.Email, Password: password})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(j))
+		req.RemoteAddr = ip + ":1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+	// Failed attempts below the threshold are rejected as usual
+	threshold := api.LockoutService.UserPolicy.Threshold
+	for i := 1; i < threshold; i++ {
+		w := login("wrong_password")
+		expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	}
+	// Reaching the threshold locks the account, even with the correct password
+	w := login("wrong_password")
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	w = login("lockoutabcd1234")
+	expect.Equal(http.StatusTooManyRequests, w.Code, "HTTP Status Code")
+	expect.NotEmpty(w.Header().Get("Retry-After"), "Retry-After Header")
+	// The lockout is visible to administrators
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/v1/users/"+u.ID+"/lockout", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	r.ServeHTTP(w, req)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		var l user.Lockout
+		expect.NoError(json.NewDecoder(w.Body).Decode(&l), "Decode JSON Lockout")
+		expect.Equal(threshold, l.Failures)
+		expect.True(l.IsLocked(time.Now()))
+	}
+	// A WARN event was recorded
+	events, err := api.EventService.ReadEventsByEntityID(ctx, u.ID, false, 100, tuid.MinID)
+	if expect.NoError(err) {
+		warnings := versionary.Filter(events, func(e event.Event) bool {
+			return e.EntityType == "Lockout" && e.LogLevel == event.WARN
+		})
+		expect.Len(warnings, 1, "Lockout WARN Event")
+	}
+	// Regular users may not unlock accounts
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("DELETE", "/v1/users/"+u.ID+"/lockout", nil)
+	req.Header.Set("Authorization", "Bearer "+regularToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Administrators may unlock accounts and client IP addresses
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("DELETE", "/v1/users/"+u.ID+"/lockout?ip="+ip, nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	w = login("lockoutabcd1234")
+	expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code")
+	// Clean up
+	_ = api.TokenService.DeleteAllTokensByUserID(ctx, u.ID)
+	_, _ = api.UserService.Delete(ctx, u.ID)
+}
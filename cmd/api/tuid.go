@@ -12,12 +12,11 @@ import (
 
 // registerTuidRoutes initializes the TUID routes.
 func registerTuidRoutes(r *gin.Engine) {
-	tuids := r.Group("/v1/tuids")
-	{
-		tuids.POST("", createTUID)
-		tuids.GET("", readTUIDs)
-		tuids.GET("/:id", readTUID)
-	}
+	handleRoutes(r, []route{
+		{"POST", "/v1/tuids", public, global, createTUID},
+		{"GET", "/v1/tuids", public, global, readTUIDs},
+		{"GET", "/v1/tuids/:id", public, global, readTUID},
+	})
 }
 
 // createTUID generates a new TUID based on the current system time.
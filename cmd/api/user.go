@@ -3,57 +3,120 @@ package main
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/mail"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/voxtechnica/tuid-go"
+	ua "github.com/voxtechnica/user-agent"
 	v "github.com/voxtechnica/versionary"
 
+	"versionary-api/pkg/app"
 	"versionary-api/pkg/email"
 	"versionary-api/pkg/event"
 	"versionary-api/pkg/ref"
+	"versionary-api/pkg/role"
+	"versionary-api/pkg/token"
 	"versionary-api/pkg/user"
 )
 
 // registerUserRoutes registers the User routes on the gin router.
 func registerUserRoutes(r *gin.Engine) {
-	r.POST("/v1/users", roleAuthorizer("admin"), createUser)
-	r.GET("/v1/users", roleAuthorizer("admin"), readUsers)
-	r.GET("/v1/users/:id", readUser)
-	r.HEAD("/v1/users/:id", existsUser)
-	r.GET("/v1/users/:id/versions", roleAuthorizer("admin"), readUserVersions)
-	r.GET("/v1/users/:id/versions/:versionid", readUserVersion)
-	r.HEAD("/v1/users/:id/versions/:versionid", existsUserVersion)
-	r.PUT("/v1/users/:id", updateUser)
-	r.DELETE("/v1/users/:id", deleteUser)
-	r.DELETE("/v1/users/:id/versions/:versionid", roleAuthorizer("admin"), deleteUserVersion)
-	r.GET("/v1/user_ids", readUserIDs)
-	r.GET("/v1/user_names", roleAuthorizer("admin"), readUserNames)
-	r.GET("/v1/user_emails", roleAuthorizer("admin"), readUserEmails)
-	r.GET("/v1/user_orgs", roleAuthorizer("admin"), readUserOrgs)
-	r.GET("/v1/user_roles", roleAuthorizer("admin"), readUserRoles)
-	r.GET("/v1/user_statuses", roleAuthorizer("admin"), readUserStatuses)
-	r.POST("/v1/users/:id/resets", sendResetToken)
-	r.PUT("/v1/users/:id/resets/:token_id", resetUserPassword)
+	handleRoutes(r, []route{
+		{"POST", "/v1/users", role.UserWrite, orgScoped, createUser},
+		{"POST", "/register", public, global, registerUser},
+		{"GET", "/v1/users", role.UserRead, orgScoped, readUsers},
+		{"GET", "/v1/users/:id", public, global, readUser},
+		{"HEAD", "/v1/users/:id", public, global, existsUser},
+		{"GET", "/v1/users/:id/versions", role.UserRead, global, readUserVersions},
+		{"GET", "/v1/users/:id/versions/:versionid", public, global, readUserVersion},
+		{"HEAD", "/v1/users/:id/versions/:versionid", public, global, existsUserVersion},
+		{"PUT", "/v1/users/:id", public, global, updateUser},
+		{"DELETE", "/v1/users/:id", public, global, deleteUser},
+		{"DELETE", "/v1/users/:id/versions/:versionid", role.UserWrite, global, deleteUserVersion},
+		{"GET", "/v1/user_ids", public, global, readUserIDs},
+		{"GET", "/v1/user_names", role.UserRead, global, readUserNames},
+		{"GET", "/v1/user_emails", role.UserRead, global, readUserEmails},
+		{"GET", "/v1/user_orgs", role.UserRead, global, readUserOrgs},
+		{"GET", "/v1/user_roles", role.UserRead, global, readUserRoles},
+		{"GET", "/v1/user_statuses", role.UserRead, global, readUserStatuses},
+		{"POST", "/v1/users/:id/resets", public, global, sendResetToken},
+		{"PUT", "/v1/users/:id/resets/:token_id", public, global, resetUserPassword},
+		{"POST", "/v1/users/:id/totp", authenticated, global, enrollTOTP},
+		{"PUT", "/v1/users/:id/totp", authenticated, global, confirmTOTP},
+		{"DELETE", "/v1/users/:id/totp", authenticated, global, disableTOTP},
+		{"POST", "/v1/users/:id/totp/resets", role.UserWrite, global, resetUserTOTP},
+		{"GET", "/v1/users/:id/verification", public, global, verifyUserEmail},
+		{"POST", "/v1/users/:id/verification", public, global, sendUserVerification},
+		{"GET", "/v1/users/:id/lockout", role.UserRead, global, readUserLockout},
+		{"DELETE", "/v1/users/:id/lockout", role.UserWrite, global, unlockUser},
+		{"GET", "/v1/users/:id/sessions", authenticated, global, readUserSessions},
+		{"DELETE", "/v1/users/:id/sessions", authenticated, global, deleteUserSessions},
+	})
+}
+
+// canManageUser returns true if the requester has the permission for the specified User: either the permission
+// is granted globally, or it's granted within the User's Organization and the User holds only
+// organization-scoped roles. Users with any global role (e.g. admin or service) are managed globally.
+func canManageUser(c *gin.Context, perm string, u user.User) bool {
+	p := contextPermissions(c)
+	return p.Has(perm) || (p.HasInOrg(perm, u.OrgID) && orgScopedRoles(c, u.Roles))
+}
+
+// canGrantRole returns true if the requester may grant the named role to a User. Granting any role requires
+// the role:write permission. Those who manage Users in their Organization may grant organization-scoped roles,
+// which cannot confer any authority outside the Organization.
+func canGrantRole(c *gin.Context, r string) bool {
+	p := contextPermissions(c)
+	return p.Has(role.RoleWrite) || (p.HasAnywhere(role.UserWrite) && orgScopedRoles(c, []string{r}))
+}
+
+// scrubUser removes sensitive information from a User for an API response. Password hashes are returned only
+// to those granted the user:write permission globally with a user Token (not an APIKey). The TOTP secret and
+// recovery codes are never returned.
+func scrubUser(c *gin.Context, u user.User) user.User {
+	if _, isKey := contextAPIKey(c); !isKey && contextPermissions(c).Has(role.UserWrite) {
+		return u.ScrubTOTP()
+	}
+	return u.Scrub()
+}
+
+// orgScopedRoles returns true if every named role (stored or default) is organization-scoped.
+// If the stored roles cannot be read, it returns false.
+func orgScopedRoles(c *gin.Context, names []string) bool {
+	roles, err := api.Roles(c)
+	if err != nil {
+		return false
+	}
+	for _, name := range names {
+		if !role.IsOrgScoped(name, roles) {
+			return false
+		}
+	}
+	return true
 }
 
 // createUser creates a new User.
 //
 // @Summary Create User
 // @Description Create a new User
-// @Description Create a new User.
+// @Description Create a new User. Organization administrators may only create Users in their own organization,
+// @Description and may only grant organization-scoped roles. A User without an organization joins the organization that
+// @Description verified their email domain, if any, once their email address is verified.
 // @Tags User
 // @Accept json
 // @Produce json
-// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
 // @Param user body user.User true "User"
 // @Success 201 {object} user.User "Newly-created User"
 // @Failure 400 {object} APIEvent "Bad Request (invalid JSON body)"
 // @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
-// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator, or not an administrator of the organization)"
 // @Failure 422 {object} APIEvent "User validation errors"
 // @Failure 500 {object} APIEvent "Internal Server Error"
 // @Header 201 {string} Location "URL of the newly created User"
@@ -65,6 +128,24 @@ func createUser(c *gin.Context) {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
 		return
 	}
+	// Organization administrators may only create Users in their active Organization
+	if _, scoped := contextOrgScope(c, role.UserWrite); scoped {
+		activeID, activeName := contextOrg(c)
+		if u.OrgID == "" {
+			u.OrgID = activeID
+		}
+		if !contextPermissions(c).HasInOrg(role.UserWrite, u.OrgID) {
+			abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: create user in organization %s", u.OrgID))
+			return
+		}
+		u.OrgName = activeName
+		for _, r := range u.Roles {
+			if !canGrantRole(c, r) {
+				abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: grant role %s", r))
+				return
+			}
+		}
+	}
 	// Create a new User
 	u, problems, err := api.UserService.Create(c, u)
 	if len(problems) > 0 && err != nil {
@@ -93,9 +174,117 @@ func createUser(c *gin.Context) {
 		Message:    fmt.Sprintf("created User %s %s", u.ID, u.Email),
 		URI:        c.Request.URL.String(),
 	})
+	// Verify the email address
+	if !u.EmailVerified() {
+		sendVerificationEmailOrLog(c, u)
+	}
 	// Return the new User
 	c.Header("Location", c.Request.URL.String()+"/"+u.ID)
-	c.JSON(http.StatusCreated, u)
+	c.JSON(http.StatusCreated, scrubUser(c, u))
+}
+
+// registerUser creates a new PENDING User from a self-service Registration. Registrations are rate-limited
+// per client IP address, and restricted to the configured email domains.
+//
+// @Summary Register User
+// @Description Register User
+// @Description Create a new User account (self-service), in PENDING status with no roles, and send an email
+// @Description verification link. The email domain must be permitted by the configured allow/block lists.
+// @Description Registrations are rate-limited per client IP address. Once their email address is verified,
+// @Description the User joins the organization that verified their email domain, if any.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param registration body user.Registration true "Registration"
+// @Success 201 {object} user.User "Newly-registered User"
+// @Failure 400 {object} APIEvent "Bad Request (invalid JSON body)"
+// @Failure 403 {object} APIEvent "Unauthorized (email domain is not allowed)"
+// @Failure 409 {object} APIEvent "Conflict (email address is already in use)"
+// @Failure 422 {object} APIEvent "Unprocessable Entity (invalid registration)"
+// @Failure 429 {object} APIEvent "Too Many Requests (too many registrations; see Retry-After)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /register [post]
+func registerUser(c *gin.Context) {
+	// Refuse registrations from a client IP address that has registered too often
+	ip := c.ClientIP()
+	l, locked, err := api.LockoutService.CheckRegistration(c, ip)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			EntityType: l.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("registration: check lockout for %s: %w", ip, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	if locked {
+		wait := l.RetryAfter(time.Now())
+		c.Header("Retry-After", strconv.Itoa(int(wait.Seconds())))
+		abortWithError(c, http.StatusTooManyRequests, fmt.Errorf("too many requests: too many registrations from %s, retry after %s", ip, wait))
+		return
+	}
+	// Parse the request body as a Registration
+	var reg user.Registration
+	if err := c.ShouldBindJSON(&reg); err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
+		return
+	}
+	// Count the attempt, whether or not it succeeds
+	if l, err = api.LockoutService.RecordRegistration(c, ip); err != nil {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			EntityType: l.Type(),
+			LogLevel:   event.WARN,
+			Message:    fmt.Errorf("registration: record attempt from %s: %w", ip, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+	}
+	// Create the new User
+	u, problems, err := api.UserService.Register(c, reg, api.SignupDomains)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, user.ErrDomainBlocked), errors.Is(err, user.ErrDomainNotAllowed):
+			status = http.StatusForbidden
+		case errors.Is(err, user.ErrDuplicateEmail):
+			status = http.StatusConflict
+		case len(problems) > 0:
+			status = http.StatusUnprocessableEntity
+		}
+		level := event.WARN
+		if status == http.StatusInternalServerError {
+			level = event.ERROR
+		}
+		e, _, _ := api.EventService.Create(c, event.Event{
+			EntityType: u.Type(),
+			LogLevel:   level,
+			Message:    fmt.Errorf("registration: refused %s from %s: %w", user.StandardizeEmail(reg.Email), ip, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		if status == http.StatusInternalServerError {
+			abortWithError(c, status, e)
+			return
+		}
+		abortWithError(c, status, fmt.Errorf("%s: %w", strings.ToLower(http.StatusText(status)), err))
+		return
+	}
+	// Log the registration
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     u.ID,
+		EntityID:   u.ID,
+		EntityType: u.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("registered User %s %s from %s", u.ID, u.Email, ip),
+		URI:        c.Request.URL.String(),
+	})
+	// Verify the email address
+	sendVerificationEmailOrLog(c, u)
+	// Return the new User
+	c.Header("Location", "/v1/users/"+u.ID)
+	c.JSON(http.StatusCreated, scrubUser(c, u))
 }
 
 // readUsers returns a paginated list of Users.
@@ -103,12 +292,15 @@ func createUser(c *gin.Context) {
 // @Summary List Users
 // @Description List Users
 // @Description List Users, paging with reverse, limit, and offset.
-// @Description Optionally, filter by email, organization, role, or status.
+// @Description Optionally, filter by email, organization, role, or status. With descendants, the Users
+// @Description of the organization's descendants (e.g. divisions) are included.
+// @Description Organization administrators may only list the Users in their own organization.
 // @Tags User
 // @Produce json
-// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param authorization header string true "OAuth Bearer Token (Administrator or Organization Administrator)"
 // @Param email query string false "Email Address"
 // @Param org query string false "Organization ID"
+// @Param descendants query bool false "Include descendant Organizations (default: false)"
 // @Param role query string false "Role (e.g. admin)"
 // @Param status query string false "Status" Enums(PENDING, ENABLED, DISABLED)
 // @Param reverse query bool false "Reverse Order (default: false)"
@@ -117,7 +309,7 @@ func createUser(c *gin.Context) {
 // @Success 200 {array} user.User "Users"
 // @Failure 400 {object} APIEvent "Bad Request (invalid parameter)"
 // @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
-// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator, or another organization)"
 // @Failure 500 {object} APIEvent "Internal Server Error"
 // @Router /v1/users [get]
 func readUsers(c *gin.Context) {
@@ -133,12 +325,67 @@ func readUsers(c *gin.Context) {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid parameter, org : %s", orgID))
 		return
 	}
-	role := c.Query("role")
+	roleName := c.Query("role")
 	status := strings.ToUpper(c.Query("status"))
 	if status != "" && !user.Status(status).IsValid() {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid status: %s", status))
 		return
 	}
+	descendants, err := strconv.ParseBool(c.DefaultQuery("descendants", "false"))
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid parameter, descendants: %w", err))
+		return
+	}
+	// Organization administrators may only list the Users in their own Organization (or its descendants,
+	// if org-scoped permissions extend to them), filtered in memory
+	scopeID, scoped := contextOrgScope(c, role.UserRead)
+	if scoped && orgID == "" {
+		orgID = scopeID
+	}
+	if scoped && !contextPermissions(c).HasInOrg(role.UserRead, orgID) {
+		abortWithError(c, http.StatusForbidden, errors.New("unauthorized: read users in another organization"))
+		return
+	}
+	if scoped || (descendants && orgID != "") {
+		orgIDs := []string{orgID}
+		if descendants {
+			subOrgIDs, err := api.OrgService.ReadDescendantIDs(c, orgID)
+			if err != nil {
+				e, _, _ := api.EventService.Create(c, event.Event{
+					UserID:     contextUserID(c),
+					EntityType: "User",
+					LogLevel:   event.ERROR,
+					Message:    fmt.Errorf("read descendants of organization %s: %w", orgID, err).Error(),
+					URI:        c.Request.URL.String(),
+					Err:        err,
+				})
+				abortWithError(c, http.StatusInternalServerError, e)
+				return
+			}
+			p := contextPermissions(c)
+			orgIDs = append(orgIDs, v.Filter(subOrgIDs, func(id string) bool { return p.HasInOrg(role.UserRead, id) })...)
+		}
+		users, err := api.UserService.ReadUsersByOrgIDs(c, orgIDs, reverse, limit, offset)
+		if err != nil {
+			e, _, _ := api.EventService.Create(c, event.Event{
+				UserID:     contextUserID(c),
+				EntityType: "User",
+				LogLevel:   event.ERROR,
+				Message:    fmt.Errorf("read users by organization %s: %w", orgID, err).Error(),
+				URI:        c.Request.URL.String(),
+				Err:        err,
+			})
+			abortWithError(c, http.StatusInternalServerError, e)
+			return
+		}
+		users = v.Filter(users, func(u user.User) bool {
+			return (email == "" || u.Email == user.StandardizeEmail(email)) &&
+				(roleName == "" || v.Contains(u.Roles, roleName)) &&
+				(status == "" || string(u.Status) == status)
+		})
+		c.JSON(http.StatusOK, v.Map(users, func(u user.User) user.User { return scrubUser(c, u) }))
+		return
+	}
 	// Read and return paginated Users
 	if email != "" {
 		// Filter by email address (there should be only one user with this email address)
@@ -155,10 +402,10 @@ func readUsers(c *gin.Context) {
 			abortWithError(c, http.StatusInternalServerError, e)
 			return
 		}
-		c.JSON(http.StatusOK, u)
+		c.JSON(http.StatusOK, v.Map(u, func(u user.User) user.User { return scrubUser(c, u) }))
 	} else if orgID != "" {
 		// Filter by Organization ID
-		u, err := api.UserService.ReadUsersByOrgIDAsJSON(c, orgID, reverse, limit, offset)
+		u, err := api.UserService.ReadUsersByOrgID(c, orgID, reverse, limit, offset)
 		if err != nil {
 			e, _, _ := api.EventService.Create(c, event.Event{
 				UserID:     contextUserID(c),
@@ -171,26 +418,26 @@ func readUsers(c *gin.Context) {
 			abortWithError(c, http.StatusInternalServerError, e)
 			return
 		}
-		c.Data(http.StatusOK, "application/json;charset=UTF-8", u)
-	} else if role != "" {
+		c.JSON(http.StatusOK, v.Map(u, func(u user.User) user.User { return scrubUser(c, u) }))
+	} else if roleName != "" {
 		// Filter by role (e.g. "admin")
-		u, err := api.UserService.ReadUsersByRoleAsJSON(c, role, reverse, limit, offset)
+		u, err := api.UserService.ReadUsersByRole(c, roleName, reverse, limit, offset)
 		if err != nil {
 			e, _, _ := api.EventService.Create(c, event.Event{
 				UserID:     contextUserID(c),
 				EntityType: "User",
 				LogLevel:   event.ERROR,
-				Message:    fmt.Errorf("read users by role %s: %w", role, err).Error(),
+				Message:    fmt.Errorf("read users by role %s: %w", roleName, err).Error(),
 				URI:        c.Request.URL.String(),
 				Err:        err,
 			})
 			abortWithError(c, http.StatusInternalServerError, e)
 			return
 		}
-		c.Data(http.StatusOK, "application/json;charset=UTF-8", u)
+		c.JSON(http.StatusOK, v.Map(u, func(u user.User) user.User { return scrubUser(c, u) }))
 	} else if status != "" {
 		// Filter by status (e.g. "ENABLED")
-		u, err := api.UserService.ReadUsersByStatusAsJSON(c, status, reverse, limit, offset)
+		u, err := api.UserService.ReadUsersByStatus(c, status, reverse, limit, offset)
 		if err != nil {
 			e, _, _ := api.EventService.Create(c, event.Event{
 				UserID:     contextUserID(c),
@@ -203,11 +450,11 @@ func readUsers(c *gin.Context) {
 			abortWithError(c, http.StatusInternalServerError, e)
 			return
 		}
-		c.Data(http.StatusOK, "application/json;charset=UTF-8", u)
+		c.JSON(http.StatusOK, v.Map(u, func(u user.User) user.User { return scrubUser(c, u) }))
 	} else {
 		// Unfiltered, fetched in parallel by ID
 		u := api.UserService.ReadUsers(c, reverse, limit, offset)
-		c.JSON(http.StatusOK, u)
+		c.JSON(http.StatusOK, v.Map(u, func(u user.User) user.User { return scrubUser(c, u) }))
 	}
 }
 
@@ -223,7 +470,7 @@ func readUsers(c *gin.Context) {
 // @Success 200 {object} user.User "User"
 // @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
 // @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
-// @Failure 403 {object} APIEvent "Forbidden (only administrators may read any user)"
+// @Failure 403 {object} APIEvent "Forbidden (only administrators may read any user, or organization administrators their organization's users)"
 // @Failure 404 {object} APIEvent "Not Found"
 // @Failure 500 {object} APIEvent "Internal Server Error"
 // @Router /v1/users/{id} [get]
@@ -271,17 +518,13 @@ func readUser(c *gin.Context) {
 		abortWithError(c, http.StatusInternalServerError, e)
 		return
 	}
-	// Only administrators can read any User
-	if u.ID != cUser.ID && !cUser.HasRole("admin") {
+	// Only administrators can read any User; organization administrators can read Users in their Organization
+	if u.ID != cUser.ID && !canManageUser(c, role.UserRead, u) {
 		abortWithError(c, http.StatusForbidden, errors.New("unauthorized: read user"))
 		return
 	}
 	// Scrub sensitive information from the User
-	if cUser.HasRole("admin") {
-		c.JSON(http.StatusOK, u)
-	} else {
-		c.JSON(http.StatusOK, u.Scrub())
-	}
+	c.JSON(http.StatusOK, scrubUser(c, u))
 }
 
 // existsUser checks if the specified User exists.
@@ -310,7 +553,7 @@ func existsUser(c *gin.Context) {
 //
 // @Summary List User Versions
 // @Description List User Versions
-// @Description Get User Versions by User ID, paging with reverse, limit, and offset.
+// @Description Get User Versions by User ID, paging with reverse, limit, and offset, scrubbing sensitive information.
 // @Tags User
 // @Produce json
 // @Param authorization header string true "OAuth Bearer Token (Administrator)"
@@ -343,7 +586,7 @@ func readUserVersions(c *gin.Context) {
 		return
 	}
 	// Read and return the specified User Versions
-	versions, err := api.UserService.ReadVersionsAsJSON(c, id, reverse, limit, offset)
+	versions, err := api.UserService.ReadVersions(c, id, reverse, limit, offset)
 	if err != nil {
 		e, _, _ := api.EventService.Create(c, event.Event{
 			UserID:     contextUserID(c),
@@ -357,7 +600,7 @@ func readUserVersions(c *gin.Context) {
 		abortWithError(c, http.StatusInternalServerError, e)
 		return
 	}
-	c.Data(http.StatusOK, "application/json;charset=UTF-8", versions)
+	c.JSON(http.StatusOK, v.Map(versions, func(u user.User) user.User { return scrubUser(c, u) }))
 }
 
 // readUserVersion returns the specified version of the specified User.
@@ -373,7 +616,7 @@ func readUserVersions(c *gin.Context) {
 // @Success 200 {object} user.User "User Version"
 // @Failure 400 {object} APIEvent "Bad Request (invalid path parameter)"
 // @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
-// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator, or not in the organization)"
 // @Failure 404 {object} APIEvent "Not Found"
 // @Failure 500 {object} APIEvent "Internal Server Error"
 // @Router /v1/users/{id}/versions/{versionid} [get]
@@ -414,17 +657,13 @@ func readUserVersion(c *gin.Context) {
 		abortWithError(c, http.StatusInternalServerError, e)
 		return
 	}
-	// Only administrators can read any User
-	if u.ID != cUser.ID && !cUser.HasRole("admin") {
+	// Only administrators can read any User; organization administrators can read Users in their Organization
+	if u.ID != cUser.ID && !canManageUser(c, role.UserRead, u) {
 		abortWithError(c, http.StatusForbidden, errors.New("unauthorized: read user"))
 		return
 	}
 	// Scrub sensitive information from the User version
-	if cUser.HasRole("admin") {
-		c.JSON(http.StatusOK, u)
-	} else {
-		c.JSON(http.StatusOK, u.Scrub())
-	}
+	c.JSON(http.StatusOK, scrubUser(c, u))
 }
 
 // existsUserVersion checks if the specified User version exists.
@@ -457,6 +696,9 @@ func existsUserVersion(c *gin.Context) {
 // @Summary Update User
 // @Description Update User
 // @Description Update the provided complete User, ensuring that sensitive information is retained.
+// @Description Organization administrators may update the Users in their own organization, including their
+// @Description roles and status, but may not move them to another organization or grant roles that are not organization-scoped.
+// @Description Disabling a User or changing their password revokes all of their Tokens.
 // @Tags User
 // @Accept json
 // @Produce json
@@ -466,7 +708,7 @@ func existsUserVersion(c *gin.Context) {
 // @Success 200 {object} user.User "User"
 // @Failure 400 {object} APIEvent "Bad Request (invalid JSON or parameter)"
 // @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
-// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator, or not in the organization)"
 // @Failure 422 {object} APIEvent "User validation errors"
 // @Failure 500 {object} APIEvent "Internal Server Error"
 // @Router /v1/users/{id} [put]
@@ -494,36 +736,57 @@ func updateUser(c *gin.Context) {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: path parameter ID %s does not match User ID %s", id, u.ID))
 		return
 	}
-	// Only administrators can update any User
-	if u.ID != cUser.ID && !cUser.HasRole("admin") {
+	// Only administrators can update any User; organization administrators can update Users in their Organization
+	if u.ID != cUser.ID && !contextPermissions(c).HasAnywhere(role.UserWrite) {
 		abortWithError(c, http.StatusForbidden, errors.New("unauthorized: update user"))
 		return
 	}
+	// Read the prior version of the User (Administrators may create a User with a specified ID)
+	prior, err := api.UserService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) && !contextPermissions(c).Has(role.UserWrite) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: user %s", id))
+		return
+	}
+	if err != nil && !errors.Is(err, v.ErrNotFound) {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     cUser.ID,
+			EntityID:   id,
+			EntityType: "User",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read user %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// The TOTP secret and recovery codes are never returned, so they're managed only with the TOTP endpoints
+	u.TOTPSecret, u.TOTPEnabled, u.RecoveryCodes = prior.TOTPSecret, prior.TOTPEnabled, prior.RecoveryCodes
 	// If the User is not an Administrator, restore sensitive information
-	if !cUser.HasRole("admin") {
-		// Read the prior version of the User
-		prior, err := api.UserService.Read(c, id)
-		if err != nil && errors.Is(err, v.ErrNotFound) {
-			abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: user %s", id))
-			return
-		}
-		if err != nil {
-			e, _, _ := api.EventService.Create(c, event.Event{
-				UserID:     cUser.ID,
-				EntityID:   id,
-				EntityType: "User",
-				LogLevel:   event.ERROR,
-				Message:    fmt.Errorf("read user %s: %w", id, err).Error(),
-				URI:        c.Request.URL.String(),
-				Err:        err,
-			})
-			abortWithError(c, http.StatusInternalServerError, e)
+	if !contextPermissions(c).Has(role.UserWrite) {
+		if u.ID != cUser.ID && !canManageUser(c, role.UserWrite, prior) {
+			abortWithError(c, http.StatusForbidden, errors.New("unauthorized: update user"))
 			return
 		}
 		// Restore sensitive information from the prior version
 		u = u.RestoreScrubbed(prior)
-		// Avoid escalating privileges
-		u.Roles = prior.Roles
+		// Avoid bypassing email verification
+		u.VerifiedEmail = prior.VerifiedEmail
+		if canManageUser(c, role.UserWrite, prior) {
+			// Organization administrators manage roles and status, but only within their Organization
+			u.OrgID = prior.OrgID
+			u.OrgName = prior.OrgName
+			for _, r := range u.Roles {
+				if !v.Contains(prior.Roles, r) && !canGrantRole(c, r) {
+					abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: grant role %s", r))
+					return
+				}
+			}
+		} else {
+			// Avoid escalating privileges
+			u.Roles = prior.Roles
+			u.Status = prior.Status
+		}
 	}
 	// Update the provided User
 	u, problems, err := api.UserService.Update(c, u)
@@ -553,12 +816,18 @@ func updateUser(c *gin.Context) {
 		Message:    fmt.Sprintf("updated User %s %s", u.ID, u.Email),
 		URI:        c.Request.URL.String(),
 	})
-	// Scrub sensitive information from the User version
-	if cUser.HasRole("admin") {
-		c.JSON(http.StatusOK, u)
-	} else {
-		c.JSON(http.StatusOK, u.Scrub())
+	// Verify a changed email address
+	if user.StandardizeEmail(u.Email) != user.StandardizeEmail(prior.Email) && !u.EmailVerified() {
+		sendVerificationEmailOrLog(c, u)
+	}
+	// Sign out a disabled User, or a User whose password was changed
+	if u.Status == user.DISABLED && prior.ID != "" && prior.Status != user.DISABLED {
+		revokeUserTokens(c, u, "user disabled")
+	} else if prior.PasswordHash != "" && u.PasswordHash != prior.PasswordHash {
+		revokeUserTokens(c, u, "password changed")
 	}
+	// Scrub sensitive information from the User version
+	c.JSON(http.StatusOK, scrubUser(c, u))
 }
 
 // deleteUser deletes the specified User.
@@ -591,7 +860,7 @@ func deleteUser(c *gin.Context) {
 		return
 	}
 	// Only administrators can delete any User
-	if id != cUser.ID && !cUser.HasRole("admin") {
+	if id != cUser.ID && !contextPermissions(c).Has(role.UserWrite) {
 		abortWithError(c, http.StatusForbidden, errors.New("unauthorized: delete user"))
 		return
 	}
@@ -636,8 +905,21 @@ func deleteUser(c *gin.Context) {
 			Err:        err,
 		})
 	}
+	// Revoke the user's API keys
+	err = api.APIKeyService.RevokeAllAPIKeysByUserID(c, id)
+	if err != nil {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     cUser.ID,
+			EntityID:   id,
+			EntityType: "User",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("revoke api keys for user %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+	}
 	// Return the deleted user
-	c.JSON(http.StatusOK, u)
+	c.JSON(http.StatusOK, scrubUser(c, u))
 }
 
 // deleteUserVersion deletes the specified User version.
@@ -695,7 +977,7 @@ func deleteUserVersion(c *gin.Context) {
 		URI:        c.Request.URL.String(),
 	})
 	// Return the deleted User
-	c.JSON(http.StatusOK, d)
+	c.JSON(http.StatusOK, scrubUser(c, d))
 }
 
 // readUserIDs returns a list of User IDs for a given email address.
@@ -852,12 +1134,13 @@ func readUserEmails(c *gin.Context) {
 	c.JSON(http.StatusOK, emails)
 }
 
-// readUserOrgs returns a list of Organization ID/Name pairs for which users exist.
+// readUserOrgs returns a list of Organization ID/Name pairs for which users (members) exist.
 // It's useful for paging through users by organization.
 //
 // @Summary List User Organizations
 // @Description List User Organization ID/Name pairs
-// @Description Get a list of Organization ID/Name pairs for which users exist.
+// @Description Get a list of Organization ID/Name pairs for which users exist, including members
+// @Description whose primary organization is elsewhere.
 // @Tags User
 // @Produce json
 // @Param authorization header string true "OAuth Bearer Token (Administrator)"
@@ -971,12 +1254,12 @@ func readUserStatuses(c *gin.Context) {
 	c.JSON(http.StatusOK, statuses)
 }
 
-// sendResetToken sends a password reset token to the user's email address.
+// sendResetToken sends a password reset link to the user's email address.
 //
 // @Summary Create a Password Reset Token
 // @Description Get User by ID or email
-// @Description Update the provided User, including a new password reset token.
-// @Description Send the password reset token to the user's email address.
+// @Description Update the provided User with the hash of a new password reset token, which expires in an hour.
+// @Description Send a password reset link, containing the token, to the user's email address.
 // @Tags User
 // @Produce json
 // @Param id path string true "User ID or Email Address"
@@ -1023,12 +1306,8 @@ func sendResetToken(c *gin.Context) {
 		return
 	}
 
-	// Create token
-	token := tuid.NewID().String()
-
-	// Update user with token
-	u.PasswordReset = token
-	u, _, err = api.UserService.Update(c, u)
+	// Update user with a new password reset token (only its hash is stored)
+	u, token, err := api.UserService.CreatePasswordReset(c, u)
 	if err != nil {
 		e, _, _ := api.EventService.Create(c, event.Event{
 			EntityID:   u.ID,
@@ -1042,15 +1321,17 @@ func sendResetToken(c *gin.Context) {
 		return
 	}
 
-	// Create and send an email to the user's email address containing the password reset token
+	// Create and send an email to the user's email address containing the password reset link
 	to := email.Identity{
 		Name:    u.FullName(),
 		Address: u.Email,
 	}
-	body := fmt.Sprintf("Hi %s,\n\nYou recently requested to reset your password for your account. Your password reset token is provided below. Copy and paste it on the website where you sent the reset message.\n\nPassword reset token: %s\n", u.FullName(), token)
+	link := fmt.Sprintf("%s/users/%s/resets/%s", api.WebURL, u.ID, token)
+	body := fmt.Sprintf("Hi %s,\n\nYou recently requested to reset your password for your account. Follow the link below to choose a new password. The link expires in %d minutes, and may be used only once.\n\n%s\n\nIf you did not request a password reset, you may ignore this message.\n",
+		u.FullName(), int(user.PasswordResetLifetime.Minutes()), link)
 	message := email.Email{
 		To:       []email.Identity{to},
-		Subject:  "Password Reset Token",
+		Subject:  "Password Reset",
 		BodyText: body,
 	}
 
@@ -1086,22 +1367,24 @@ func sendResetToken(c *gin.Context) {
 }
 
 // resetUserPassword updates the provided User with a new password hash and deletes the password reset token.
+// All of the User's bearer tokens are revoked.
 //
 // @Summary Reset Password
 // @Description Update the provided User with a new password hash and delete the password reset token.
+// @Description Reset tokens expire, and may be used only once. All of the User's bearer tokens are revoked.
 // @Tags User
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID or Email Address"
-// @Param token path string true "Password Reset Token"
+// @Param token_id path string true "Password Reset Token"
 // @Param password body map[string]string true "New Password"
 // @Success 204
 // @Failure 400 {object} APIEvent "Bad Request (invalid JSON or parameter)"
-// @Failure 401 {object} APIEvent "Unauthenticated (incorrect password reset token)"
+// @Failure 401 {object} APIEvent "Unauthenticated (incorrect or expired password reset token)"
 // @Failure 404 {object} APIEvent "Not Found (no user with the specified ID or email address)"
 // @Failure 422 {object} APIEvent "Invalid password (must be at least 12 characters)"
 // @Failure 500 {object} APIEvent "Internal Server Error"
-// @Router /v1/users/{id}/resets/:token_id [put]
+// @Router /v1/users/{id}/resets/{token_id} [put]
 func resetUserPassword(c *gin.Context) {
 	// Extract the password from the request body
 	body := make(map[string]string)
@@ -1143,7 +1426,7 @@ func resetUserPassword(c *gin.Context) {
 
 	// Validate the path parameter token
 	token := c.Param("token_id")
-	if !tuid.IsValid(tuid.TUID(token)) {
+	if !user.IsPasswordResetToken(token) {
 		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter token: %s", token))
 		return
 	}
@@ -1166,19 +1449,16 @@ func resetUserPassword(c *gin.Context) {
 		return
 	}
 
-	// Verify reset token
-	if u.PasswordReset != token {
-		abortWithError(c, http.StatusUnauthorized, fmt.Errorf("unauthenticated: invalid token"))
+	// Verify the reset token and update user with new password (hashed by the UserService)
+	u, err = api.UserService.ResetPassword(c, u, token, password)
+	if err != nil && errors.Is(err, user.ErrExpiredPasswordReset) {
+		abortWithError(c, http.StatusUnauthorized, errors.New("unauthenticated: expired token"))
+		return
+	}
+	if err != nil && errors.Is(err, user.ErrInvalidPasswordReset) {
+		abortWithError(c, http.StatusUnauthorized, errors.New("unauthenticated: invalid token"))
 		return
 	}
-
-	// Hash the new password using a suitable hashing algorithm
-	newHashedPassword := user.HashPassword(u.ID, password)
-
-	// Update user with new hashed password and remove the password reset token
-	u.PasswordHash = newHashedPassword
-	u.PasswordReset = ""
-	u, _, err = api.UserService.Update(c, u)
 	if err != nil {
 		e, _, _ := api.EventService.Create(c, event.Event{
 			EntityID:   u.ID,
@@ -1192,15 +1472,885 @@ func resetUserPassword(c *gin.Context) {
 		return
 	}
 
+	// Revoke the user's bearer tokens
+	if err = api.TokenService.DeleteAllTokensByUserID(c, u.ID); err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("password update: delete tokens for user %s %s: %w", u.ID, u.Email, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+
 	// Log the update
 	_, _, _ = api.EventService.Create(c, event.Event{
 		EntityID:   u.ID,
 		EntityType: u.Type(),
 		LogLevel:   event.INFO,
-		Message:    fmt.Sprintf("updated user %s %s: updated password hash", u.ID, u.Email),
+		Message:    fmt.Sprintf("updated user %s %s: reset password and revoked tokens", u.ID, u.Email),
 		URI:        c.Request.URL.String(),
 	})
 
 	// Return an ok with no content
 	c.Status(http.StatusNoContent)
 }
+
+// TOTPRequest provides a second-factor code: either a TOTP code or a recovery code.
+type TOTPRequest struct {
+	Code string `json:"code"`
+}
+
+// TOTPEnrollment provides a new TOTP secret, along with an otpauth:// key URI for rendering as a QR code.
+// The enrollment must be confirmed with a valid TOTP code before it is enforced.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// TOTPRecoveryCodes provides single-use recovery codes, displayed only once. Only their hashes are stored.
+type TOTPRecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// readTOTPUser reads the User identified by the path parameter ID for a TOTP operation.
+// Users may manage only their own second factor. If the request fails, it is aborted, and false is returned.
+func readTOTPUser(c *gin.Context, action string) (user.User, bool) {
+	// Only authenticated users can manage their second factor
+	cUser, ok := contextUser(c)
+	if !ok {
+		abortWithError(c, http.StatusUnauthorized, fmt.Errorf("unauthenticated: %s", action))
+		return user.User{}, false
+	}
+	// Validate the path parameter ID
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return user.User{}, false
+	}
+	// Users may only manage their own second factor
+	if id != cUser.ID {
+		abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: %s", action))
+		return user.User{}, false
+	}
+	// Read the specified User
+	u, err := api.UserService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: user %s", id))
+		return u, false
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     cUser.ID,
+			EntityID:   id,
+			EntityType: "User",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("%s: read user %s: %w", action, id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return u, false
+	}
+	return u, true
+}
+
+// enrollTOTP generates a new TOTP secret for the specified User. The enrollment is not enforced
+// until it has been confirmed with a valid TOTP code (see confirmTOTP).
+//
+// @Summary Enroll TOTP
+// @Description Enroll TOTP
+// @Description Generate a new TOTP secret for the specified User (self only), returning the secret
+// @Description and an otpauth:// key URI. The enrollment must be confirmed with a valid TOTP code.
+// @Tags User
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token"
+// @Param id path string true "User ID"
+// @Success 201 {object} TOTPEnrollment "TOTP Enrollment"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (users may only enroll themselves)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 409 {object} APIEvent "Conflict (TOTP is already enabled)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/totp [post]
+func enrollTOTP(c *gin.Context) {
+	u, ok := readTOTPUser(c, "enroll totp")
+	if !ok {
+		return
+	}
+	// A confirmed second factor must be disabled before enrolling again
+	if u.HasTOTP() {
+		abortWithError(c, http.StatusConflict, errors.New("conflict: totp is already enabled"))
+		return
+	}
+	// Generate a new TOTP secret
+	u, err := api.UserService.EnrollTOTP(c, u)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     u.ID,
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("enroll totp for user %s: %w", u.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Log the enrollment
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     u.ID,
+		EntityID:   u.ID,
+		EntityType: u.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("enrolled User %s in TOTP (pending confirmation)", u.ID),
+		URI:        c.Request.URL.String(),
+	})
+	// Return the secret and key URI
+	c.JSON(http.StatusCreated, TOTPEnrollment{
+		Secret: u.TOTPSecret,
+		URI:    user.TOTPURI(api.Name, u.Email, u.TOTPSecret),
+	})
+}
+
+// confirmTOTP verifies a TOTP code against a pending enrollment, enabling the second factor,
+// and returns a new set of single-use recovery codes.
+//
+// @Summary Confirm TOTP
+// @Description Confirm TOTP
+// @Description Verify a TOTP code for the specified User's pending enrollment (self only), enabling the second factor.
+// @Description Returns single-use recovery codes, which are displayed only once.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token"
+// @Param id path string true "User ID"
+// @Param code body TOTPRequest true "TOTP Code"
+// @Success 200 {object} TOTPRecoveryCodes "Recovery Codes"
+// @Failure 400 {object} APIEvent "Bad Request (invalid JSON or parameter, or not enrolled)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (users may only confirm their own enrollment)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 422 {object} APIEvent "Unprocessable Entity (invalid TOTP code)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/totp [put]
+func confirmTOTP(c *gin.Context) {
+	var req TOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
+		return
+	}
+	u, ok := readTOTPUser(c, "confirm totp")
+	if !ok {
+		return
+	}
+	if u.TOTPSecret == "" || u.HasTOTP() {
+		abortWithError(c, http.StatusBadRequest, errors.New("bad request: no pending totp enrollment"))
+		return
+	}
+	// Verify the code and enable the second factor
+	u, codes, err := api.UserService.ConfirmTOTP(c, u, req.Code)
+	if err != nil && errors.Is(err, user.ErrInvalidSecondFactor) {
+		abortWithError(c, http.StatusUnprocessableEntity, errors.New("unprocessable entity: invalid totp code"))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     u.ID,
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("confirm totp for user %s: %w", u.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Log the confirmation
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     u.ID,
+		EntityID:   u.ID,
+		EntityType: u.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("enabled TOTP for User %s", u.ID),
+		URI:        c.Request.URL.String(),
+	})
+	// Return the recovery codes
+	c.JSON(http.StatusOK, TOTPRecoveryCodes{RecoveryCodes: codes})
+}
+
+// disableTOTP removes the specified User's second factor. If TOTP is enabled, a valid
+// TOTP code or recovery code is required. A pending enrollment may be cancelled without a code.
+//
+// @Summary Disable TOTP
+// @Description Disable TOTP
+// @Description Remove the specified User's second factor (self only). If TOTP is enabled,
+// @Description a valid TOTP code or recovery code is required.
+// @Tags User
+// @Accept json
+// @Param authorization header string true "OAuth Bearer Token"
+// @Param id path string true "User ID"
+// @Param code body TOTPRequest false "TOTP or Recovery Code"
+// @Success 204
+// @Failure 400 {object} APIEvent "Bad Request (invalid JSON or parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (users may only disable their own second factor)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 422 {object} APIEvent "Unprocessable Entity (missing or invalid code)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/totp [delete]
+func disableTOTP(c *gin.Context) {
+	var req TOTPRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid JSON body: %w", err))
+			return
+		}
+	}
+	u, ok := readTOTPUser(c, "disable totp")
+	if !ok {
+		return
+	}
+	// Verify the second factor, if enabled. Like a login, this uses up the code.
+	u, err := api.UserService.VerifySecondFactor(c, u, req.Code)
+	if err != nil && (errors.Is(err, user.ErrSecondFactorRequired) || errors.Is(err, user.ErrInvalidSecondFactor)) {
+		abortWithError(c, http.StatusUnprocessableEntity, errors.New("unprocessable entity: missing or invalid second factor code"))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("disable totp: verify second factor for user %s: %w", u.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Remove the second factor
+	disableUserTOTP(c, u, "disabled")
+}
+
+// resetUserTOTP removes the specified User's second factor without a code, for Users that have
+// lost both their authenticator and their recovery codes.
+//
+// @Summary Reset TOTP
+// @Description Reset TOTP
+// @Description Remove the specified User's second factor without a code (e.g. for a locked-out User).
+// @Tags User
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param id path string true "User ID"
+// @Success 204
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/totp/resets [post]
+func resetUserTOTP(c *gin.Context) {
+	// Validate the path parameter ID
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	// Read the specified User
+	u, err := api.UserService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: user %s", id))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "User",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("reset totp: read user %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Remove the second factor
+	disableUserTOTP(c, u, "reset")
+}
+
+// disableUserTOTP removes the User's second factor, logs the change, and responds with no content.
+func disableUserTOTP(c *gin.Context, u user.User, action string) {
+	u, err := api.UserService.DisableTOTP(c, u)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("%s totp for user %s: %w", action, u.ID, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   u.ID,
+		EntityType: u.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("%s TOTP for User %s", action, u.ID),
+		URI:        c.Request.URL.String(),
+	})
+	c.Status(http.StatusNoContent)
+}
+
+// readUserLockout returns the failed login attempts recorded for the specified User.
+//
+// @Summary Read User Lockout
+// @Description Read User Lockout
+// @Description Read the failed login attempts recorded for the specified User, and any temporary lockout.
+// @Tags User
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param id path string true "User ID"
+// @Success 200 {object} user.Lockout "Lockout"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found (no failed login attempts)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/lockout [get]
+func readUserLockout(c *gin.Context) {
+	// Validate the path parameter ID
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	// Read the Lockout
+	l, err := api.LockoutService.Read(c, user.LockoutUser, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: lockout for user %s", id))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: l.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read lockout for user %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	c.JSON(http.StatusOK, l)
+}
+
+// unlockUser clears the failed login attempts for the specified User, and optionally for a client IP address,
+// ending any temporary lockout.
+//
+// @Summary Unlock User
+// @Description Unlock User
+// @Description Clear the failed login attempts recorded for the specified User, ending any temporary lockout.
+// @Description Optionally, clear the failed login attempts recorded for a client IP address as well.
+// @Tags User
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token (Administrator)"
+// @Param id path string true "User ID"
+// @Param ip query string false "Client IP Address"
+// @Success 200 {array} user.Lockout "Lockouts that were cleared"
+// @Failure 400 {object} APIEvent "Bad Request (invalid parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found (no failed login attempts)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/lockout [delete]
+func unlockUser(c *gin.Context) {
+	// Validate the parameters
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	ip := c.Query("ip")
+	if ip != "" && net.ParseIP(ip) == nil {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid parameter, ip: %s", ip))
+		return
+	}
+	// Clear the Lockout(s)
+	cleared := []user.Lockout{}
+	for _, kind := range []string{user.LockoutUser, user.LockoutIP} {
+		subject := id
+		if kind == user.LockoutIP {
+			subject = ip
+		}
+		if subject == "" {
+			continue
+		}
+		l, err := api.LockoutService.Unlock(c, kind, subject)
+		if err != nil && errors.Is(err, v.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			e, _, _ := api.EventService.Create(c, event.Event{
+				UserID:     contextUserID(c),
+				EntityID:   id,
+				EntityType: l.Type(),
+				LogLevel:   event.ERROR,
+				Message:    fmt.Errorf("unlock %s %s: %w", kind, subject, err).Error(),
+				URI:        c.Request.URL.String(),
+				Err:        err,
+			})
+			abortWithError(c, http.StatusInternalServerError, e)
+			return
+		}
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: l.Type(),
+			LogLevel:   event.INFO,
+			Message:    fmt.Sprintf("unlocked %s %s after %d failed login attempts", kind, subject, l.Failures),
+			URI:        c.Request.URL.String(),
+		})
+		cleared = append(cleared, l)
+	}
+	if len(cleared) == 0 {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: lockout for user %s", id))
+		return
+	}
+	c.JSON(http.StatusOK, cleared)
+}
+
+// Email verification messages are throttled: at most one per verificationInterval,
+// and at most verificationDailyLimit in any 24-hour period.
+const (
+	verificationSubject    = "Verify Your Email Address"
+	verificationInterval   = time.Minute
+	verificationDailyLimit = 5
+)
+
+// sendVerificationEmail sends the User a signed, expiring link for verifying their email address.
+func sendVerificationEmail(c *gin.Context, u user.User) (email.Email, error) {
+	key, err := api.SecretKey(c, app.EmailVerificationKey)
+	if err != nil {
+		return email.Email{}, err
+	}
+	token := u.EmailVerificationToken(key, time.Now().Add(user.EmailVerificationLifetime))
+	link := fmt.Sprintf("%s/v1/users/%s/verification?token=%s", api.APIURL, u.ID, url.QueryEscape(token))
+	body := fmt.Sprintf("Hi %s,\n\nPlease verify your email address by following the link below. The link expires in %d hours.\n\n%s\n\nIf you did not create an account, you may ignore this message.\n",
+		u.FullName(), int(user.EmailVerificationLifetime.Hours()), link)
+	e, _, err := api.EmailService.Create(c, email.Email{
+		To:       []email.Identity{{Name: u.FullName(), Address: u.Email}},
+		Subject:  verificationSubject,
+		BodyText: body,
+	})
+	return e, err
+}
+
+// sendVerificationEmailOrLog sends an email verification link, logging the outcome.
+// Failure to send the message is logged, but does not affect the response.
+func sendVerificationEmailOrLog(c *gin.Context, u user.User) {
+	e, err := sendVerificationEmail(c, u)
+	if err != nil {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("send email verification for user %s %s: %w", u.ID, u.Email, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		return
+	}
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   e.ID,
+		EntityType: e.Type(),
+		OtherIDs:   []string{u.ID},
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("email verification: created email %s for User %s", e.ID, u.ID),
+		URI:        c.Request.URL.String(),
+	})
+}
+
+// verificationRetryAfter returns the time remaining before another verification message may be sent
+// to the User's email address, based on the verification messages recently sent to that address.
+func verificationRetryAfter(c *gin.Context, u user.User) (time.Duration, error) {
+	emails, err := api.EmailService.ReadEmailsByAddress(c, u.Email, true, 100, tuid.MaxID)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	var sent []time.Time // most recent first
+	for _, e := range emails {
+		if e.Subject == verificationSubject && now.Sub(e.CreatedAt) < 24*time.Hour {
+			sent = append(sent, e.CreatedAt)
+		}
+	}
+	var wait time.Duration
+	if len(sent) > 0 {
+		wait = max(wait, sent[0].Add(verificationInterval).Sub(now))
+	}
+	if len(sent) >= verificationDailyLimit {
+		wait = max(wait, sent[verificationDailyLimit-1].Add(24*time.Hour).Sub(now))
+	}
+	return wait, nil
+}
+
+// verifyUserEmail verifies the User's email address with a signed token from a verification link.
+//
+// @Summary Verify Email Address
+// @Description Verify Email Address
+// @Description Verify the User's email address with the signed token from an email verification link.
+// @Description PENDING Users are ENABLED. The token expires, and is invalidated if the email address changes.
+// @Description A User without an organization joins the organization that verified their email domain, if any.
+// @Tags User
+// @Produce json
+// @Param id path string true "User ID"
+// @Param token query string true "Email Verification Token"
+// @Success 200 {object} user.User "Verified User"
+// @Failure 400 {object} APIEvent "Bad Request (invalid parameter)"
+// @Failure 401 {object} APIEvent "Unauthenticated (invalid or expired token)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/verification [get]
+func verifyUserEmail(c *gin.Context) {
+	// Validate the parameters
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return
+	}
+	token := c.Query("token")
+	if token == "" {
+		abortWithError(c, http.StatusBadRequest, errors.New("bad request: missing required query parameter: token"))
+		return
+	}
+	// Read the specified User
+	u, err := api.UserService.Read(c, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: user %s", id))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			EntityID:   id,
+			EntityType: "User",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("email verification: read user %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Verify the email address. Users without an Organization join the Organization that verified their
+	// email domain, if any, but only once they've proven that they own the address.
+	key, err := api.SecretKey(c, app.EmailVerificationKey)
+	if err == nil {
+		err = u.ValidateEmailVerificationToken(key, token, time.Now())
+	}
+	if err == nil && u.OrgID == "" && !u.EmailVerified() {
+		if o, ok := domainOrg(c, u.Email); ok {
+			u.OrgID, u.OrgName = o.ID, o.Name
+		}
+	}
+	if err == nil {
+		u, err = api.UserService.VerifyEmail(c, u, key, token)
+	}
+	if err != nil && errors.Is(err, user.ErrExpiredVerification) {
+		abortWithError(c, http.StatusUnauthorized, errors.New("unauthenticated: expired verification token"))
+		return
+	}
+	if err != nil && errors.Is(err, user.ErrInvalidVerification) {
+		abortWithError(c, http.StatusUnauthorized, errors.New("unauthenticated: invalid verification token"))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("email verification: verify user %s %s: %w", u.ID, u.Email, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Log the verification
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     u.ID,
+		EntityID:   u.ID,
+		EntityType: u.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("verified email address %s for User %s", u.Email, u.ID),
+		URI:        c.Request.URL.String(),
+	})
+	c.JSON(http.StatusOK, scrubUser(c, u))
+}
+
+// sendUserVerification resends an email verification link to the User's email address.
+//
+// @Summary Resend Email Verification
+// @Description Resend Email Verification
+// @Description Send a new email verification link to the User's email address. Messages are throttled:
+// @Description at most one per minute, and five per day.
+// @Tags User
+// @Param id path string true "User ID or Email Address"
+// @Success 204
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter)"
+// @Failure 404 {object} APIEvent "Not Found (no user with the specified ID or email address)"
+// @Failure 409 {object} APIEvent "Conflict (email address is already verified)"
+// @Failure 429 {object} APIEvent "Too Many Requests (see Retry-After)"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/verification [post]
+func sendUserVerification(c *gin.Context) {
+	// Validate the path parameter ID (as either an email address or a TUID)
+	idOrEmail := c.Param("id")
+	if strings.Contains(idOrEmail, "@") {
+		i, err := email.NewIdentity("", idOrEmail)
+		if err != nil {
+			abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter %s: %w", idOrEmail, err))
+			return
+		}
+		idOrEmail = i.Address
+	} else if !tuid.IsValid(tuid.TUID(idOrEmail)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", idOrEmail))
+		return
+	}
+	// Read the specified User
+	u, err := api.UserService.Read(c, idOrEmail)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: user %s", idOrEmail))
+		return
+	}
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			EntityType: "User",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("email verification: read user %s: %w", idOrEmail, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	if u.EmailVerified() {
+		abortWithError(c, http.StatusConflict, errors.New("conflict: email address is already verified"))
+		return
+	}
+	// Throttle verification messages
+	wait, err := verificationRetryAfter(c, u)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("email verification: read emails for %s: %w", u.Email, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	if wait > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(wait.Round(time.Second).Seconds())))
+		abortWithError(c, http.StatusTooManyRequests, fmt.Errorf("too many requests: verification email recently sent, retry after %s", wait.Round(time.Second)))
+		return
+	}
+	// Send the verification message
+	e, err := sendVerificationEmail(c, u)
+	if err != nil {
+		evt, _, _ := api.EventService.Create(c, event.Event{
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("email verification: send email for user %s %s: %w", u.ID, u.Email, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, evt)
+		return
+	}
+	// Log the creation
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     u.ID,
+		EntityID:   e.ID,
+		EntityType: e.Type(),
+		LogLevel:   event.INFO,
+		Message:    "email verification: created email " + e.ID,
+		URI:        c.Request.URL.String(),
+	})
+	c.Status(http.StatusNoContent)
+}
+
+// readSessionUserID validates the path parameter ID for a session operation. Users may manage their own sessions,
+// and users with the specified permission may manage any User's sessions. If the request fails, it is aborted,
+// and false is returned.
+func readSessionUserID(c *gin.Context, perm, action string) (string, bool) {
+	cUser, ok := contextUser(c)
+	if !ok {
+		abortWithError(c, http.StatusUnauthorized, fmt.Errorf("unauthenticated: %s", action))
+		return "", false
+	}
+	id := c.Param("id")
+	if !tuid.IsValid(tuid.TUID(id)) {
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("bad request: invalid path parameter ID: %s", id))
+		return id, false
+	}
+	if id != cUser.ID && !contextPermissions(c).Has(perm) {
+		abortWithError(c, http.StatusForbidden, fmt.Errorf("unauthorized: %s", action))
+		return id, false
+	}
+	if !api.UserService.Exists(c, id) {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("not found: user %s", id))
+		return id, false
+	}
+	return id, true
+}
+
+// readUserSessions returns the active sessions (unexpired access Tokens) of the specified User.
+//
+// @Summary List User Sessions
+// @Description List User Sessions
+// @Description List the active sessions (unexpired access Tokens) of the specified User, including the Device
+// @Description (parsed User-Agent) and client IP address that requested each Token, and when it was last used.
+// @Description Users may list their own sessions; administrators may list any User's sessions.
+// @Tags User
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token"
+// @Param id path string true "User ID"
+// @Success 200 {array} token.Session "Sessions"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not the specified User or an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/sessions [get]
+func readUserSessions(c *gin.Context) {
+	id, ok := readSessionUserID(c, role.UserRead, "read sessions")
+	if !ok {
+		return
+	}
+	// Read the active Tokens
+	tokens, err := api.TokenService.ReadActiveTokensByUserID(c, id, time.Now())
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "User",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("read sessions for user %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	// Describe each Token with its Device (best effort; Devices may have expired)
+	cToken, _ := contextToken(c)
+	agents := map[string]*ua.UserAgent{}
+	sessions := make([]token.Session, 0, len(tokens))
+	for _, t := range tokens {
+		if _, ok := agents[t.DeviceID]; !ok && t.DeviceID != "" {
+			agents[t.DeviceID] = nil
+			if d, err := api.DeviceService.Read(c, t.DeviceID); err == nil {
+				agents[t.DeviceID] = &d.UserAgent
+			}
+		}
+		sessions = append(sessions, token.NewSession(t, agents[t.DeviceID], t.ID == cToken.ID))
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// deleteUserSessions signs the specified User out everywhere else, revoking all of their Tokens and RefreshTokens
+// except for the session making the request.
+//
+// @Summary Revoke Other User Sessions
+// @Description Revoke Other User Sessions
+// @Description Sign the specified User out everywhere else, revoking all of their access Tokens and RefreshTokens,
+// @Description except for the session making the request (if it belongs to the User).
+// @Description Users may revoke their own sessions; administrators may revoke any User's sessions.
+// @Tags User
+// @Produce json
+// @Param authorization header string true "OAuth Bearer Token"
+// @Param id path string true "User ID"
+// @Success 200 {array} string "IDs of revoked Tokens"
+// @Failure 400 {object} APIEvent "Bad Request (invalid path parameter ID)"
+// @Failure 401 {object} APIEvent "Unauthenticated (missing or invalid Authorization header)"
+// @Failure 403 {object} APIEvent "Unauthorized (not the specified User or an Administrator)"
+// @Failure 404 {object} APIEvent "Not Found"
+// @Failure 500 {object} APIEvent "Internal Server Error"
+// @Router /v1/users/{id}/sessions [delete]
+func deleteUserSessions(c *gin.Context) {
+	id, ok := readSessionUserID(c, role.UserWrite, "revoke sessions")
+	if !ok {
+		return
+	}
+	var keepID string
+	if cToken, ok := contextToken(c); ok && cToken.UserID == id {
+		keepID = cToken.ID
+	}
+	revoked, err := api.TokenService.DeleteOtherTokensByUserID(c, id, keepID)
+	if err != nil {
+		e, _, _ := api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   id,
+			EntityType: "User",
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("revoke sessions for user %s: %w", id, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		abortWithError(c, http.StatusInternalServerError, e)
+		return
+	}
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   id,
+		EntityType: "User",
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("revoked %d other sessions for User %s", len(revoked), id),
+		URI:        c.Request.URL.String(),
+	})
+	c.JSON(http.StatusOK, revoked)
+}
+
+// revokeUserTokens revokes all Tokens and RefreshTokens of a User whose credentials or status have changed
+// (e.g. disabled, or password changed), logging the outcome. Failures are logged, but not returned.
+func revokeUserTokens(c *gin.Context, u user.User, reason string) {
+	if err := api.TokenService.DeleteAllTokensByUserID(c, u.ID); err != nil {
+		_, _, _ = api.EventService.Create(c, event.Event{
+			UserID:     contextUserID(c),
+			EntityID:   u.ID,
+			EntityType: u.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("%s: delete tokens for user %s %s: %w", reason, u.ID, u.Email, err).Error(),
+			URI:        c.Request.URL.String(),
+			Err:        err,
+		})
+		return
+	}
+	_, _, _ = api.EventService.Create(c, event.Event{
+		UserID:     contextUserID(c),
+		EntityID:   u.ID,
+		EntityType: u.Type(),
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("%s: revoked tokens for User %s %s", reason, u.ID, u.Email),
+		URI:        c.Request.URL.String(),
+	})
+}
@@ -2,12 +2,14 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/voxtechnica/tuid-go"
@@ -15,6 +17,9 @@ import (
 	"github.com/voxtechnica/versionary"
 
 	"versionary-api/pkg/email"
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/role"
+	"versionary-api/pkg/token"
 	"versionary-api/pkg/user"
 )
 
@@ -1114,7 +1119,8 @@ func TestResetUserPassword(t *testing.T) {
 
 	// Unknown token
 	w = httptest.NewRecorder()
-	token := tuid.NewID().String()
+	token, _, err := user.NewPasswordResetToken()
+	expect.NoError(err)
 	req, err = http.NewRequest("PUT", "/v1/users/"+regularUser.Email+"/resets/"+token, strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json;charset=UFT-8")
 	req.Header.Set("Accept", "application/json;charset=UFT-8")
@@ -1218,7 +1224,7 @@ func TestResetFlow(t *testing.T) {
 	// Create user
 	var u user.User
 	w := httptest.NewRecorder()
-	body1 := `{"givenName": "Password_Reset_User", "email":"PasswordReset@test.com"}`
+	body1 := `{"givenName": "Password_Reset_User", "email":"PasswordReset@test.com", "password": "old_password123"}`
 	req, err := http.NewRequest("POST", "/v1/users", strings.NewReader(body1))
 	req.Header.Set("Authorization", "Bearer "+adminToken)
 	req.Header.Set("Content-Type", "application/json;charset=UFT-8")
@@ -1234,6 +1240,10 @@ func TestResetFlow(t *testing.T) {
 		}
 	}
 
+	// Get a bearer token, to be revoked by the password reset
+	bearer, err := api.TokenService.Create(context.Background(), token.Token{UserID: u.ID, Email: u.Email})
+	expect.NoError(err)
+
 	// Generate reset token
 	w = httptest.NewRecorder()
 	req, err = http.NewRequest("POST", "/v1/users/"+u.Email+"/resets", nil)
@@ -1244,9 +1254,10 @@ func TestResetFlow(t *testing.T) {
 		expect.Equal(http.StatusNoContent, w.Code, "HTTP Status Code")
 	}
 
-	// Check the email message
+	// Check the email message, which contains a reset link
+	var resetToken string
 	w = httptest.NewRecorder()
-	req, err = http.NewRequest("GET", "/v1/emails?address="+u.Email, nil)
+	req, err = http.NewRequest("GET", "/v1/emails?reverse=true&address="+u.Email, nil)
 	req.Header.Set("Authorization", "Bearer "+adminToken)
 	req.Header.Set("Accept", "application/json;charset=UTF-8")
 	if expect.NoError(err) {
@@ -1256,7 +1267,12 @@ func TestResetFlow(t *testing.T) {
 		if expect.NoError(json.NewDecoder(w.Body).Decode(&emails), "Decode JSON Emails") {
 			if expect.NotEmpty(emails, "Emails") {
 				expect.Contains(emails[0].Subject, "Password Reset", "Email Subject")
-				expect.Contains(emails[0].BodyText, "reset token", "Email Body")
+				link := api.WebURL + "/users/" + u.ID + "/resets/"
+				if expect.Contains(emails[0].BodyText, link, "Email Body") {
+					_, resetToken, _ = strings.Cut(emails[0].BodyText, link)
+					resetToken, _, _ = strings.Cut(resetToken, "\n")
+					expect.True(user.IsPasswordResetToken(resetToken), "Reset Token")
+				}
 			}
 		}
 	}
@@ -1272,14 +1288,15 @@ func TestResetFlow(t *testing.T) {
 		expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
 		if expect.NoError(json.NewDecoder(w.Body).Decode(&u2), "Decode JSON User") {
 			expect.NotEmpty(u2.PasswordReset, "PasswordReset token is not empty")
-			expect.NotEqual(u.PasswordReset, u2.PasswordReset)
+			expect.NotEqual(resetToken, u2.PasswordReset, "PasswordReset token is hashed")
+			expect.True(u2.PasswordResetExpires.After(time.Now()), "PasswordReset expires")
 		}
 	}
 
 	// Reset the password
 	w = httptest.NewRecorder()
 	body := `{"password": "new_password123"}`
-	req, err = http.NewRequest("PUT", "/v1/users/"+u2.Email+"/resets/"+u2.PasswordReset, strings.NewReader(body))
+	req, err = http.NewRequest("PUT", "/v1/users/"+u2.Email+"/resets/"+resetToken, strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json;charset=UFT-8")
 	req.Header.Set("Accept", "application/json;charset=UFT-8")
 	if expect.NoError(err) {
@@ -1287,6 +1304,18 @@ func TestResetFlow(t *testing.T) {
 		expect.Equal(http.StatusNoContent, w.Code, "HTTP Status Code")
 	}
 
+	// Bearer tokens are revoked
+	exists := api.TokenService.Exists(context.Background(), bearer.ID)
+	expect.False(exists, "Bearer token revoked")
+
+	// Reset tokens are single-use
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("PUT", "/v1/users/"+u2.Email+"/resets/"+resetToken, strings.NewReader(body))
+	if expect.NoError(err) {
+		r.ServeHTTP(w, req)
+		expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	}
+
 	// Read the user
 	w = httptest.NewRecorder()
 	req, err = http.NewRequest("GET", "/v1/users/"+u2.ID, nil)
@@ -1298,6 +1327,7 @@ func TestResetFlow(t *testing.T) {
 		var u3 user.User
 		if expect.NoError(json.NewDecoder(w.Body).Decode(&u3), "Decode JSON User") {
 			expect.Empty(u3.PasswordReset, "PasswordReset token is empty")
+			expect.True(u3.PasswordResetExpires.IsZero(), "PasswordReset expiration is empty")
 			expect.NotEqual(u2.PasswordHash, u3.PasswordHash, "PasswordHash has changed")
 		}
 	}
@@ -1316,3 +1346,557 @@ func TestResetFlow(t *testing.T) {
 		}
 	}
 }
+
+func TestTOTPFlow(t *testing.T) {
+	expect := assert.New(t)
+	u, _, err := api.UserService.Create(context.Background(), user.User{
+		GivenName: "totp_user",
+		Email:     "totp_user@test.com",
+		Password:  "totpabcd1234",
+		Status:    user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	serve := func(method, path, bearer string, body any) *httptest.ResponseRecorder {
+		j, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBuffer(j))
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+	// Get a token without a second factor
+	var res token.Response
+	w := serve("POST", "/v1/tokens", "", token.Request{Username: u.Email, Password: "totpabcd1234"})
+	if !expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") {
+		return
+	}
+	expect.NoError(json.NewDecoder(w.Body).Decode(&res), "Decode JSON Token Response")
+	// Users may only enroll themselves
+	w = serve("POST", "/v1/users/"+regularUser.ID+"/totp", res.AccessToken, nil)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Enroll
+	var enrollment TOTPEnrollment
+	w = serve("POST", "/v1/users/"+u.ID+"/totp", res.AccessToken, nil)
+	if !expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") {
+		return
+	}
+	expect.NoError(json.NewDecoder(w.Body).Decode(&enrollment), "Decode JSON TOTP Enrollment")
+	expect.NotEmpty(enrollment.Secret)
+	expect.True(strings.HasPrefix(enrollment.URI, "otpauth://totp/"))
+	// Pending enrollments are not enforced
+	w = serve("POST", "/v1/tokens", "", token.Request{Username: u.Email, Password: "totpabcd1234"})
+	expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code")
+	// Confirm the enrollment
+	w = serve("PUT", "/v1/users/"+u.ID+"/totp", res.AccessToken, TOTPRequest{Code: "000000"})
+	expect.Equal(http.StatusUnprocessableEntity, w.Code, "HTTP Status Code")
+	code, _ := user.TOTPCode(enrollment.Secret, time.Now())
+	var recovery TOTPRecoveryCodes
+	w = serve("PUT", "/v1/users/"+u.ID+"/totp", res.AccessToken, TOTPRequest{Code: code})
+	if !expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		return
+	}
+	expect.NoError(json.NewDecoder(w.Body).Decode(&recovery), "Decode JSON Recovery Codes")
+	expect.NotEmpty(recovery.RecoveryCodes)
+	// Enrolling again requires disabling first
+	w = serve("POST", "/v1/users/"+u.ID+"/totp", res.AccessToken, nil)
+	expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+	// The TOTP secret and recovery codes are never returned, even to administrators, and are retained on update
+	var enrolled user.User
+	w = serve("GET", "/v1/users/"+u.ID, adminToken, nil)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&enrolled), "Decode JSON User") {
+		expect.NotEmpty(enrolled.PasswordHash)
+		expect.Empty(enrolled.TOTPSecret)
+		expect.Empty(enrolled.RecoveryCodes)
+		enrolled.TOTPEnabled = false
+		w = serve("PUT", "/v1/users/"+u.ID, adminToken, enrolled)
+		if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+			expect.NotContains(w.Body.String(), "totpSecret")
+			stored, _ := api.UserService.Read(context.Background(), u.ID)
+			expect.True(stored.TOTPEnabled)
+			expect.NotEmpty(stored.TOTPSecret)
+			expect.NotEmpty(stored.RecoveryCodes)
+		}
+	}
+	// A second factor is now required for tokens
+	w = serve("POST", "/v1/tokens", "", token.Request{Username: u.Email, Password: "totpabcd1234"})
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	expect.Contains(w.Body.String(), "second factor code required")
+	w = serve("POST", "/v1/tokens", "", token.Request{Username: u.Email, Password: "totpabcd1234", Code: "000000"})
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	// TOTP codes are single-use: the confirmation code can't be replayed, but the next one is accepted, once
+	w = serve("POST", "/v1/tokens", "", token.Request{Username: u.Email, Password: "totpabcd1234", Code: code})
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	code, _ = user.TOTPCode(enrollment.Secret, time.Now().Add(30*time.Second))
+	w = serve("POST", "/v1/tokens", "", token.Request{Username: u.Email, Password: "totpabcd1234", Code: code})
+	expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code")
+	w = serve("POST", "/v1/tokens", "", token.Request{Username: u.Email, Password: "totpabcd1234", Code: code})
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	w = serve("POST", "/login", "", token.Request{Username: u.Email, Password: "totpabcd1234"})
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	// Recovery codes are single-use
+	w = serve("POST", "/login", "", token.Request{Username: u.Email, Password: "totpabcd1234", Code: recovery.RecoveryCodes[0]})
+	expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code")
+	w = serve("POST", "/login", "", token.Request{Username: u.Email, Password: "totpabcd1234", Code: recovery.RecoveryCodes[0]})
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	// Disabling TOTP requires a code
+	w = serve("DELETE", "/v1/users/"+u.ID+"/totp", res.AccessToken, TOTPRequest{})
+	expect.Equal(http.StatusUnprocessableEntity, w.Code, "HTTP Status Code")
+	// Only administrators may reset a second factor
+	w = serve("POST", "/v1/users/"+u.ID+"/totp/resets", res.AccessToken, nil)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = serve("POST", "/v1/users/"+u.ID+"/totp/resets", adminToken, nil)
+	expect.Equal(http.StatusNoContent, w.Code, "HTTP Status Code")
+	w = serve("POST", "/v1/tokens", "", token.Request{Username: u.Email, Password: "totpabcd1234"})
+	expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code")
+	// Enroll again, then disable with a recovery code
+	w = serve("POST", "/v1/users/"+u.ID+"/totp", res.AccessToken, nil)
+	expect.NoError(json.NewDecoder(w.Body).Decode(&enrollment), "Decode JSON TOTP Enrollment")
+	code, _ = user.TOTPCode(enrollment.Secret, time.Now())
+	w = serve("PUT", "/v1/users/"+u.ID+"/totp", res.AccessToken, TOTPRequest{Code: code})
+	expect.NoError(json.NewDecoder(w.Body).Decode(&recovery), "Decode JSON Recovery Codes")
+	w = serve("DELETE", "/v1/users/"+u.ID+"/totp", res.AccessToken, TOTPRequest{Code: recovery.RecoveryCodes[1]})
+	expect.Equal(http.StatusNoContent, w.Code, "HTTP Status Code")
+	// Clean up
+	_ = api.TokenService.DeleteAllTokensByUserID(context.Background(), u.ID)
+	_, _ = api.UserService.Delete(context.Background(), u.ID)
+}
+
+func TestReadUsersScrubbed(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	// A custom role that may read, but not write, Users
+	readerRole, _, err := api.RoleService.Create(ctx, role.Role{Name: "user_reader", Permissions: []string{role.UserRead}})
+	if !expect.NoError(err) {
+		return
+	}
+	api.InvalidateRoles()
+	defer func() {
+		_, _ = api.RoleService.Delete(ctx, readerRole.ID)
+		api.InvalidateRoles()
+	}()
+	reader, _, err := api.UserService.Create(ctx, user.User{
+		GivenName: "User",
+		Email:     "user_reader@test.com",
+		Roles:     []string{"user_reader"},
+		Status:    user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.UserService.Delete(ctx, reader.ID) }()
+	readerToken, err := api.TokenService.Create(ctx, token.Token{UserID: reader.ID, Email: reader.Email})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _ = api.TokenService.DeleteAllTokensByUserID(ctx, reader.ID) }()
+	// Every user read handler applies the same scrub rule
+	paths := []string{
+		"/v1/users",
+		"/v1/users?email=" + adminUser.Email,
+		"/v1/users?org=" + userOrg.ID,
+		"/v1/users?role=admin",
+		"/v1/users?status=ENABLED",
+		"/v1/users/" + adminUser.ID,
+		"/v1/users/" + adminUser.ID + "/versions",
+		"/v1/users/" + adminUser.ID + "/versions/" + adminUser.VersionID,
+	}
+	for _, path := range paths {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+readerToken.ID)
+		r.ServeHTTP(w, req)
+		if expect.Equal(http.StatusOK, w.Code, path) {
+			expect.Contains(w.Body.String(), adminUser.ID, path)
+			expect.NotContains(w.Body.String(), "passwordHash", path)
+		}
+	}
+}
+
+func TestEmailVerification(t *testing.T) {
+	expect := assert.New(t)
+	// Create a pending user, which sends a verification email
+	var u user.User
+	w := httptest.NewRecorder()
+	body := `{"givenName": "verify_user", "email": "verify_user@test.com", "password": "verifyabcd1234"}`
+	req := httptest.NewRequest("POST", "/v1/users", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	r.ServeHTTP(w, req)
+	if !expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") {
+		return
+	}
+	expect.NoError(json.NewDecoder(w.Body).Decode(&u), "Decode JSON User")
+	expect.Equal(user.PENDING, u.Status)
+	emails, err := api.EmailService.ReadEmailsByAddress(context.Background(), u.Email, true, 10, tuid.MaxID)
+	if !expect.NoError(err) || !expect.NotEmpty(emails) {
+		return
+	}
+	expect.Equal(verificationSubject, emails[0].Subject)
+	_, link, found := strings.Cut(emails[0].BodyText, "/v1/users/"+u.ID+"/verification?token=")
+	if !expect.True(found, "verification link") {
+		return
+	}
+	verifyToken, _, _ := strings.Cut(link, "\n")
+	// Resending immediately is throttled
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", "/v1/users/"+u.Email+"/verification", nil)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusTooManyRequests, w.Code, "HTTP Status Code")
+	expect.NotEmpty(w.Header().Get("Retry-After"))
+	// Unverified users may be refused tokens
+	api.RequireVerified = true
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", "/v1/tokens", strings.NewReader(`{"username": "verify_user@test.com", "password": "verifyabcd1234"}`))
+	req.RemoteAddr = "203.0.113.9:1234"
+	r.ServeHTTP(w, req)
+	api.RequireVerified = false
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Invalid tokens are rejected
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/v1/users/"+u.ID+"/verification?token=bogus", nil)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusUnauthorized, w.Code, "HTTP Status Code")
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/v1/users/"+u.ID+"/verification", nil)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	// Verify the email address
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/v1/users/"+u.ID+"/verification?token="+verifyToken, nil)
+	r.ServeHTTP(w, req)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		expect.NoError(json.NewDecoder(w.Body).Decode(&u), "Decode JSON User")
+		expect.Equal(user.ENABLED, u.Status)
+		expect.True(u.EmailVerified())
+	}
+	// Verified users need not verify again
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", "/v1/users/"+u.ID+"/verification", nil)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+	// Clean up
+	_, err = api.UserService.Delete(context.Background(), u.ID)
+	expect.NoError(err)
+}
+
+func TestRequireVerified(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	u, _, err := api.UserService.Create(ctx, user.User{
+		GivenName: "Unverified",
+		Email:     "unverified_user@test.com",
+		Password:  "unverifiedabcd1234",
+		Status:    user.PENDING,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.UserService.Delete(ctx, u.ID) }()
+	defer func() { _ = api.TokenService.DeleteAllTokensByUserID(ctx, u.ID) }()
+	login := func() int {
+		w := httptest.NewRecorder()
+		body := `{"username": "unverified_user@test.com", "password": "unverifiedabcd1234"}`
+		req := httptest.NewRequest("POST", "/v1/tokens", strings.NewReader(body))
+		req.RemoteAddr = "203.0.113.31:1234"
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	// By default, pending users may sign in
+	expect.Equal(http.StatusCreated, login(), "HTTP Status Code")
+	// If required, pending users are refused tokens until they verify their email address
+	api.RequireVerified = true
+	defer func() { api.RequireVerified = false }()
+	expect.Equal(http.StatusForbidden, login(), "HTTP Status Code")
+	u.VerifiedEmail = u.Email
+	u.Status = user.ENABLED
+	if _, _, err = api.UserService.Update(ctx, u); expect.NoError(err) {
+		expect.Equal(http.StatusCreated, login(), "HTTP Status Code")
+	}
+}
+
+func TestUserSessions(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	u, _, err := api.UserService.Create(ctx, user.User{
+		GivenName: "Session",
+		Email:     "session_user@test.com",
+		Password:  "sessionabcd1234",
+		Status:    user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	// Sign in from two devices
+	signIn := func(agent, ip string) token.Response {
+		var res token.Response
+		w := httptest.NewRecorder()
+		body := `{"username": "session_user@test.com", "password": "sessionabcd1234"}`
+		req := httptest.NewRequest("POST", "/v1/tokens", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", agent)
+		req.RemoteAddr = ip + ":1234"
+		r.ServeHTTP(w, req)
+		if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") {
+			expect.NoError(json.NewDecoder(w.Body).Decode(&res), "Decode JSON Token Response")
+		}
+		return res
+	}
+	desktop := signIn("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15", "203.0.113.21")
+	phone := signIn("Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1", "203.0.113.22")
+	// List the sessions
+	var sessions []token.Session
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/v1/users/"+u.ID+"/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+desktop.AccessToken)
+	r.ServeHTTP(w, req)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&sessions), "Decode JSON Sessions") &&
+		expect.Len(sessions, 2) {
+		expect.Equal(desktop.AccessToken, sessions[0].TokenID)
+		expect.True(sessions[0].Current)
+		expect.Equal("203.0.113.21", sessions[0].ClientIP)
+		expect.NotEmpty(sessions[0].DeviceID)
+		if expect.NotNil(sessions[0].UserAgent) {
+			expect.Equal("Safari", sessions[0].UserAgent.ClientName)
+		}
+		expect.False(sessions[0].LastUsedAt.IsZero())
+		expect.Equal(phone.AccessToken, sessions[1].TokenID)
+		expect.False(sessions[1].Current)
+	}
+	// Other users may not list the sessions
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/v1/users/"+u.ID+"/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+regularToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Sign out everywhere else
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("DELETE", "/v1/users/"+u.ID+"/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+desktop.AccessToken)
+	r.ServeHTTP(w, req)
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") {
+		var revoked []string
+		expect.NoError(json.NewDecoder(w.Body).Decode(&revoked), "Decode JSON Token IDs")
+		expect.Equal([]string{phone.AccessToken}, revoked)
+	}
+	expect.True(api.TokenService.Exists(ctx, desktop.AccessToken), "Current session retained")
+	expect.False(api.TokenService.Exists(ctx, phone.AccessToken), "Other session revoked")
+	_, err = api.TokenService.ReadRefreshToken(ctx, phone.RefreshToken)
+	expect.ErrorIs(err, versionary.ErrNotFound, "Other refresh token revoked")
+	// Changing the password revokes all sessions
+	u.Password = "sessionefgh5678"
+	j, _ := json.Marshal(u)
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("PUT", "/v1/users/"+u.ID, bytes.NewBuffer(j))
+	req.Header.Set("Authorization", "Bearer "+desktop.AccessToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	expect.False(api.TokenService.Exists(ctx, desktop.AccessToken), "Sessions revoked after password change")
+	// Disabling the User revokes all sessions
+	bearer, err := api.TokenService.Create(ctx, token.Token{UserID: u.ID, Email: u.Email})
+	if !expect.NoError(err) {
+		return
+	}
+	u, err = api.UserService.Read(ctx, u.ID)
+	if !expect.NoError(err) {
+		return
+	}
+	u.Status = user.DISABLED
+	j, _ = json.Marshal(u)
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("PUT", "/v1/users/"+u.ID, bytes.NewBuffer(j))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	expect.False(api.TokenService.Exists(ctx, bearer.ID), "Sessions revoked after disabling user")
+}
+
+func TestRegisterUser(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	policy := api.SignupDomains
+	api.SignupDomains = user.DomainPolicy{Allowed: []string{"signup.com"}, Blocked: []string{"spam.signup.com"}}
+	defer func() { api.SignupDomains = policy }()
+	register := func(body, ip string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/register", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = ip + ":1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+	// Register a new User
+	var u user.User
+	w := register(`{"givenName": "Signup", "email": "Signup_User@signup.com", "password": "signup1234"}`, "203.0.113.31")
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&u), "Decode JSON User") {
+		expect.Equal("/v1/users/"+u.ID, w.Header().Get("Location"))
+		expect.Equal("signup_user@signup.com", u.Email)
+		expect.Equal(user.PENDING, u.Status)
+		expect.Empty(u.Roles)
+		expect.Empty(u.PasswordHash)
+	}
+	events, err := api.EventService.ReadEventsByEntityID(ctx, u.ID, false, 100, tuid.MinID)
+	if expect.NoError(err) {
+		registered := versionary.Filter(events, func(e event.Event) bool {
+			return e.LogLevel == event.INFO && strings.HasPrefix(e.Message, "registered User")
+		})
+		expect.Len(registered, 1, "Registration INFO Event")
+	}
+	// Duplicate email addresses are refused
+	w = register(`{"email": "signup_user@signup.com", "password": "signup1234"}`, "203.0.113.31")
+	expect.Equal(http.StatusConflict, w.Code, "HTTP Status Code")
+	// Disallowed and blocked domains are refused
+	w = register(`{"email": "other@elsewhere.com", "password": "signup1234"}`, "203.0.113.31")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = register(`{"email": "other@spam.signup.com", "password": "signup1234"}`, "203.0.113.31")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Invalid registrations are refused
+	w = register(`{"email": "other@signup.com", "password": "short"}`, "203.0.113.31")
+	expect.Equal(http.StatusUnprocessableEntity, w.Code, "HTTP Status Code")
+	w = register(`not json`, "203.0.113.31")
+	expect.Equal(http.StatusBadRequest, w.Code, "HTTP Status Code")
+	// Registrations are rate-limited per client IP address
+	for i := 0; i < api.LockoutService.RegistrationPolicy.Threshold; i++ {
+		w = register(`{"email": "other@elsewhere.com", "password": "signup1234"}`, "203.0.113.32")
+		expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	}
+	w = register(`{"email": "limited@signup.com", "password": "signup1234"}`, "203.0.113.32")
+	expect.Equal(http.StatusTooManyRequests, w.Code, "HTTP Status Code")
+	expect.NotEmpty(w.Header().Get("Retry-After"))
+	w = register(`{"email": "limited@signup.com", "password": "signup1234"}`, "203.0.113.33")
+	expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code")
+	// Clean up
+	for _, email := range []string{"signup_user@signup.com", "limited@signup.com"} {
+		if existing, err := api.UserService.ReadUserByEmail(ctx, email); err == nil {
+			_, _ = api.UserService.Delete(ctx, existing.ID)
+		}
+	}
+}
+
+func TestOrgAdminUsers(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	o, orgAdmin, orgAdminToken := generateOrgAdmin("Scoped Users Org")
+	defer deleteOrgAdmin(o, orgAdmin)
+	other, otherAdmin, _ := generateOrgAdmin("Other Users Org")
+	defer deleteOrgAdmin(other, otherAdmin)
+	outsider, _, err := api.UserService.Create(ctx, user.User{
+		GivenName: "Outsider",
+		Email:     "outsider@test.com",
+		OrgID:     other.ID,
+		OrgName:   other.Name,
+		Status:    user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.UserService.Delete(ctx, outsider.ID) }()
+	call := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+orgAdminToken)
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+	// Create a User in the organization (by default)
+	var member user.User
+	w := call("POST", "/v1/users", `{"givenName": "Member", "email": "org_member@test.com", "status": "ENABLED"}`)
+	if expect.Equal(http.StatusCreated, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&member), "Decode JSON User") {
+		expect.Equal(o.ID, member.OrgID)
+		expect.Equal(o.Name, member.OrgName)
+		expect.Empty(member.PasswordHash)
+		defer func() { _, _ = api.UserService.Delete(ctx, member.ID) }()
+	}
+	// Users may not be created in another organization, or with global roles
+	w = call("POST", "/v1/users", `{"email": "org_other@test.com", "orgID": "`+other.ID+`"}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call("POST", "/v1/users", `{"email": "org_admin_escalation@test.com", "roles": ["admin"]}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call("POST", "/v1/users", `{"email": "org_global_escalation@test.com", "roles": ["creator"]}`)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Users in the organization with global roles are managed globally
+	creator, _, err := api.UserService.Create(ctx, user.User{
+		GivenName: "Creator",
+		Email:     "org_creator@test.com",
+		OrgID:     o.ID,
+		OrgName:   o.Name,
+		Roles:     []string{"creator"},
+		Status:    user.ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = api.UserService.Delete(ctx, creator.ID) }()
+	// List the organization's Users
+	var users []user.User
+	w = call("GET", "/v1/users", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&users), "Decode JSON Users") {
+		ids := versionary.Map(users, func(u user.User) string { return u.ID })
+		expect.ElementsMatch([]string{orgAdmin.ID, member.ID, creator.ID}, ids)
+	}
+	w = call("GET", "/v1/users?role=creator", "")
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&users), "Decode JSON Users") &&
+		expect.Len(users, 1) {
+		expect.Equal(creator.ID, users[0].ID)
+	}
+	w = call("GET", "/v1/users?org="+other.ID, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Read Users in the organization, but not elsewhere
+	w = call("GET", "/v1/users/"+member.ID, "")
+	expect.Equal(http.StatusOK, w.Code, "HTTP Status Code")
+	w = call("GET", "/v1/users/"+outsider.ID, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call("GET", "/v1/users/"+adminUser.ID, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	w = call("GET", "/v1/users/"+creator.ID, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Disable a User in the organization, but not elsewhere
+	member.Status = user.DISABLED
+	member.OrgID = other.ID
+	j, _ := json.Marshal(member)
+	w = call("PUT", "/v1/users/"+member.ID, string(j))
+	if expect.Equal(http.StatusOK, w.Code, "HTTP Status Code") &&
+		expect.NoError(json.NewDecoder(w.Body).Decode(&member), "Decode JSON User") {
+		expect.Equal(user.DISABLED, member.Status)
+		expect.Equal(o.ID, member.OrgID, "may not move users to another organization")
+	}
+	for _, r := range []string{"admin", "creator"} {
+		member.Roles = []string{r}
+		j, _ = json.Marshal(member)
+		w = call("PUT", "/v1/users/"+member.ID, string(j))
+		expect.Equal(http.StatusForbidden, w.Code, r)
+	}
+	creator.Status = user.DISABLED
+	j, _ = json.Marshal(creator)
+	w = call("PUT", "/v1/users/"+creator.ID, string(j))
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	outsider.Status = user.DISABLED
+	j, _ = json.Marshal(outsider)
+	w = call("PUT", "/v1/users/"+outsider.ID, string(j))
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Everything else is forbidden
+	for _, path := range []string{"/v1/user_names", "/v1/users/" + member.ID + "/versions", "/v1/events", "/v1/api_keys"} {
+		w = call("GET", path, "")
+		expect.Equal(http.StatusForbidden, w.Code, path)
+	}
+	w = call("DELETE", "/v1/users/"+member.ID, "")
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+	// Regular users may not list users
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/v1/users", nil)
+	req.Header.Set("Authorization", "Bearer "+regularToken)
+	r.ServeHTTP(w, req)
+	expect.Equal(http.StatusForbidden, w.Code, "HTTP Status Code")
+}
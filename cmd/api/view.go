@@ -12,22 +12,25 @@ import (
 	v "github.com/voxtechnica/versionary"
 
 	"versionary-api/pkg/event"
+	"versionary-api/pkg/role"
 	"versionary-api/pkg/view"
 )
 
 // registerViewRoutes initializes the View routes.
 func registerViewRoutes(r *gin.Engine) {
-	r.POST("/v1/views", createView)
-	r.DELETE("/v1/views/:id", roleAuthorizer("admin"), deleteView)
-	r.GET("/v1/views", roleAuthorizer("admin"), readViews)
-	r.GET("/v1/views/:id", readView)
-	r.HEAD("/v1/views/:id", existsView)
-	r.GET("/v1/view_dates", roleAuthorizer("admin"), readViewDates)
-	r.GET("/v1/view_device_ids", roleAuthorizer("admin"), readViewDeviceIDs)
-	r.GET("/v1/view_counts", roleAuthorizer("admin"), readViewCounts)
-	r.GET("/v1/view_counts/:date", roleAuthorizer("admin"), readViewCount)
-	r.HEAD("/v1/view_counts/:date", roleAuthorizer("admin"), existsViewCount)
-	r.PUT("/v1/view_counts/:date", roleAuthorizer("admin"), updateViewCount)
+	handleRoutes(r, []route{
+		{"POST", "/v1/views", public, global, createView},
+		{"DELETE", "/v1/views/:id", role.ViewWrite, global, deleteView},
+		{"GET", "/v1/views", role.ViewRead, global, readViews},
+		{"GET", "/v1/views/:id", public, global, readView},
+		{"HEAD", "/v1/views/:id", public, global, existsView},
+		{"GET", "/v1/view_dates", role.ViewRead, global, readViewDates},
+		{"GET", "/v1/view_device_ids", role.ViewRead, global, readViewDeviceIDs},
+		{"GET", "/v1/view_counts", role.ViewRead, global, readViewCounts},
+		{"GET", "/v1/view_counts/:date", role.ViewRead, global, readViewCount},
+		{"HEAD", "/v1/view_counts/:date", role.ViewRead, global, existsViewCount},
+		{"PUT", "/v1/view_counts/:date", role.ViewWrite, global, updateViewCount},
+	})
 }
 
 // createView creates a new View.
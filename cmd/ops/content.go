@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/job"
+
+	"github.com/spf13/cobra"
+)
+
+// initContentCmd initializes the content commands.
+func initContentCmd(root *cobra.Command) {
+	contentCmd := &cobra.Command{
+		Use:   "content",
+		Short: "Manage content",
+	}
+	root.AddCommand(contentCmd)
+
+	publishDueCmd := &cobra.Command{
+		Use:   "publish-due",
+		Short: "Publish and unpublish scheduled content",
+		Long:  "Apply the scheduled content changes that are due: mark embargoed content as live once its publishAt time has passed, and archive content once its unpublishAt time has passed. Progress is recorded in a job. The API Lambda function runs this on a schedule, too.",
+		RunE:  publishDueContent,
+	}
+	publishDueCmd.Flags().StringP("env", "e", "", "Operating environment: dev | test | staging | prod")
+	publishDueCmd.Flags().StringP("at", "a", "", "Apply the changes due at this time (RFC 3339; default: now)")
+	_ = publishDueCmd.MarkFlagRequired("env")
+	contentCmd.AddCommand(publishDueCmd)
+
+	reindexCmd := &cobra.Command{
+		Use:   "reindex",
+		Short: "Rebuild the content search index",
+		Long:  "Rewrite the current version of all content, rebuilding its index rows, including the search terms. Content is indexed when it's created and updated, so this is needed only for content saved before search was introduced.",
+		RunE:  reindexContent,
+	}
+	reindexCmd.Flags().StringP("env", "e", "", "Operating environment: dev | test | staging | prod")
+	_ = reindexCmd.MarkFlagRequired("env")
+	contentCmd.AddCommand(reindexCmd)
+}
+
+// publishDueContent applies the scheduled content changes that are due.
+func publishDueContent(cmd *cobra.Command, args []string) error {
+	at := time.Now()
+	if s := cmd.Flag("at").Value.String(); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("invalid time %s: %w", s, err)
+		}
+		at = t
+	}
+	// Initialize the application
+	err := ops.Init(cmd.Flag("env").Value.String())
+	if err != nil {
+		return fmt.Errorf("error initializing application: %s", err)
+	}
+	ctx := context.Background()
+
+	// Record the progress in a Job
+	message := "publish and unpublish content scheduled by " + at.UTC().Format(time.RFC3339)
+	jb, _, err := ops.JobService.Create(ctx, job.Job{
+		Kind:    job.ContentSchedule,
+		Message: message,
+	})
+	if err != nil {
+		return fmt.Errorf("error creating content schedule job: %w", err)
+	}
+	fmt.Printf("Started job %s: %s\n", jb.ID, message)
+	jb = ops.PublishDue(ctx, jb, at)
+	_, _, _ = ops.EventService.Create(ctx, event.Event{
+		EntityID:   jb.ID,
+		EntityType: jb.Type(),
+		LogLevel:   event.INFO,
+		Message:    jb.String(),
+	})
+	j, err := json.MarshalIndent(jb, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling JSON Job %s: %w", jb.ID, err)
+	}
+	fmt.Println(string(j))
+	if jb.Status == job.FAILED {
+		return fmt.Errorf("content schedule job %s failed", jb.ID)
+	}
+	return nil
+}
+
+// reindexContent rewrites the current version of all content, rebuilding its index rows.
+func reindexContent(cmd *cobra.Command, args []string) error {
+	// Initialize the application
+	err := ops.Init(cmd.Flag("env").Value.String())
+	if err != nil {
+		return fmt.Errorf("error initializing application: %s", err)
+	}
+	ctx := context.Background()
+
+	// Rewrite the current version of each Content
+	ids, err := ops.ContentService.ReadAllContentIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("error reading content IDs: %w", err)
+	}
+	for _, id := range ids {
+		c, err := ops.ContentService.Read(ctx, id)
+		if err != nil {
+			return fmt.Errorf("error reading content %s: %w", id, err)
+		}
+		if _, err = ops.ContentService.Write(ctx, c); err != nil {
+			return fmt.Errorf("error reindexing content %s: %w", id, err)
+		}
+	}
+	fmt.Printf("Reindexed %d content items\n", len(ids))
+	return nil
+}
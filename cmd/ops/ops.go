@@ -49,9 +49,11 @@ func main() {
 
 	// Initialize the application commands:
 	initBucketCmd(rootCmd)
+	initContentCmd(rootCmd)
 	initImageCmd(rootCmd)
 	initMetricCmd(rootCmd)
 	initOrgCmd(rootCmd)
+	initRestoreCmd(rootCmd)
 	initTableCmd(rootCmd)
 	initTokenCmd(rootCmd)
 	initTuidCmd(rootCmd)
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"versionary-api/pkg/app"
 	"versionary-api/pkg/event"
+	"versionary-api/pkg/job"
 	"versionary-api/pkg/org"
 
 	"github.com/spf13/cobra"
@@ -58,13 +60,26 @@ func initOrgCmd(root *cobra.Command) {
 	deleteCmd := &cobra.Command{
 		Use:   "delete <orgID>",
 		Short: "Delete specified organization",
-		Long:  "Delete the specified organization, by ID.",
+		Long:  "Delete the specified organization, by ID, handling its members according to the policy: refuse to delete it while members remain, reassign them to the target organization (dropping organization-scoped roles), or disable them. Members whose primary organization is elsewhere just lose their membership.",
 		Args:  cobra.ExactArgs(1),
 		RunE:  deleteOrg,
 	}
 	deleteCmd.Flags().StringP("env", "e", "", "Operating environment: dev | test | staging | prod")
+	deleteCmd.Flags().StringP("policy", "p", "refuse", "Member policy: refuse | reassign | disable")
+	deleteCmd.Flags().StringP("target", "t", "", "Target organization ID (reassign policy only)")
 	_ = deleteCmd.MarkFlagRequired("env")
 	orgCmd.AddCommand(deleteCmd)
+
+	consistencyCmd := &cobra.Command{
+		Use:   "consistency",
+		Short: "Find stale organization and user names",
+		Long:  "Find stale copies of organization names (in users, memberships, and invitations) and user email addresses (in memberships, tokens, and refresh tokens), optionally fixing them. Progress is recorded in a job.",
+		RunE:  checkConsistency,
+	}
+	consistencyCmd.Flags().StringP("env", "e", "", "Operating environment: dev | test | staging | prod")
+	consistencyCmd.Flags().BoolP("fix", "f", false, "Fix the stale names? (default: report only)")
+	_ = consistencyCmd.MarkFlagRequired("env")
+	orgCmd.AddCommand(consistencyCmd)
 }
 
 // createOrg creates a new organization.
@@ -184,18 +199,67 @@ func deleteOrg(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error initializing application: %s", err)
 	}
 	ctx := context.Background()
+	policy, err := app.ParseMemberPolicy(cmd.Flag("policy").Value.String())
+	if err != nil {
+		return err
+	}
 
-	// Delete the specified Organization
-	for _, orgID := range args {
-		org, err := ops.OrgService.Delete(ctx, orgID)
-		if err != nil {
-			return fmt.Errorf("error deleting Organization %s: %w", orgID, err)
-		}
-		orgJSON, err := json.MarshalIndent(org, "", "  ")
-		if err != nil {
-			return fmt.Errorf("error marshaling JSON Org %s: %w", orgID, err)
+	// Delete the specified Organization, handling its members
+	d, err := ops.DeleteOrganization(ctx, app.OrgDeletionRequest{
+		OrgID:       args[0],
+		Policy:      policy,
+		TargetOrgID: cmd.Flag("target").Value.String(),
+	})
+	if d.Organization.ID != "" {
+		dJSON, jsonErr := json.MarshalIndent(d, "", "  ")
+		if jsonErr != nil {
+			return fmt.Errorf("error marshaling JSON Org %s: %w", args[0], jsonErr)
 		}
-		fmt.Println(string(orgJSON))
+		fmt.Println(string(dJSON))
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting Organization %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// checkConsistency finds (and optionally fixes) stale denormalized names across users and tokens.
+func checkConsistency(cmd *cobra.Command, args []string) error {
+	// Initialize the application
+	err := ops.Init(cmd.Flag("env").Value.String())
+	if err != nil {
+		return fmt.Errorf("error initializing application: %s", err)
+	}
+	ctx := context.Background()
+	fix, _ := cmd.Flags().GetBool("fix")
+
+	// Record the progress in a Job
+	message := "find stale names"
+	if fix {
+		message = "find and fix stale names"
+	}
+	jb, _, err := ops.JobService.Create(ctx, job.Job{
+		Kind:    job.Consistency,
+		Message: message,
+	})
+	if err != nil {
+		return fmt.Errorf("error creating consistency job: %w", err)
+	}
+	fmt.Printf("Started job %s: %s\n", jb.ID, message)
+	jb = ops.SyncNames(ctx, jb, fix)
+	_, _, _ = ops.EventService.Create(ctx, event.Event{
+		EntityID:   jb.ID,
+		EntityType: jb.Type(),
+		LogLevel:   event.INFO,
+		Message:    jb.String(),
+	})
+	j, err := json.MarshalIndent(jb, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling JSON Job %s: %w", jb.ID, err)
+	}
+	fmt.Println(string(j))
+	if jb.Status == job.FAILED {
+		return fmt.Errorf("consistency job %s failed", jb.ID)
 	}
 	return nil
 }
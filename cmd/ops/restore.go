@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"versionary-api/pkg/app"
+
+	"github.com/spf13/cobra"
+)
+
+// initRestoreCmd initializes the restore command.
+func initRestoreCmd(root *cobra.Command) {
+	restoreCmd := &cobra.Command{
+		Use:   "restore <entityType> <id> <versionID>",
+		Short: "Restore a prior entity version",
+		Long: "Restore a prior version of an entity, saving it as a new version with a comment recording where it came from. " +
+			"Entity types: " + strings.Join(app.RestorableTypes, " | ") + ". Fields that must not be rolled back " +
+			"(e.g. a User's credentials, or a Content status) are kept from the current version.",
+		Args: cobra.ExactArgs(3),
+		RunE: restoreVersion,
+	}
+	restoreCmd.Flags().StringP("env", "e", "", "Operating environment: dev | test | staging | prod")
+	restoreCmd.Flags().StringP("comment", "c", "", "Reason for restoring the version")
+	_ = restoreCmd.MarkFlagRequired("env")
+	root.AddCommand(restoreCmd)
+}
+
+// restoreVersion restores a prior version of the specified entity.
+func restoreVersion(cmd *cobra.Command, args []string) error {
+	entityType, err := app.ParseRestorableType(args[0])
+	if err != nil {
+		return err
+	}
+	// Initialize the application
+	err = ops.Init(cmd.Flag("env").Value.String())
+	if err != nil {
+		return fmt.Errorf("error initializing application: %s", err)
+	}
+	ctx := context.Background()
+
+	// Restore the version
+	rs, problems, err := ops.Restore(ctx, app.RestoreRequest{
+		EntityType: entityType,
+		ID:         args[1],
+		VersionID:  args[2],
+		Comment:    cmd.Flag("comment").Value.String(),
+		UserName:   "ops",
+	})
+	if err != nil {
+		if len(problems) > 0 {
+			return fmt.Errorf("error restoring %s %s version %s: %s", entityType, args[1], args[2], strings.Join(problems, ", "))
+		}
+		return err
+	}
+	// A restored Organization name is propagated in a background Job
+	ops.WaitForJobs()
+	j, err := json.MarshalIndent(rs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling JSON Restoration: %w", err)
+	}
+	fmt.Println(string(j))
+	return nil
+}
@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"versionary-api/pkg/apikey"
 	"versionary-api/pkg/content"
 	"versionary-api/pkg/device"
 	"versionary-api/pkg/email"
 	"versionary-api/pkg/event"
 	"versionary-api/pkg/image"
+	"versionary-api/pkg/job"
 	"versionary-api/pkg/metric"
 	"versionary-api/pkg/org"
+	"versionary-api/pkg/role"
 	"versionary-api/pkg/token"
 	"versionary-api/pkg/user"
 	"versionary-api/pkg/view"
@@ -74,6 +77,8 @@ func checkTables(cmd *cobra.Command, args []string) error {
 	for _, entity := range tables {
 		// TODO: add new DynamoDB tables here
 		switch entity {
+		case "APIKey":
+			checkTable(ctx, apikey.NewTable(ops.DBClient, ops.Environment))
 		case "Content":
 			checkTable(ctx, content.NewTable(ops.DBClient, ops.Environment))
 		case "Device":
@@ -86,10 +91,22 @@ func checkTables(cmd *cobra.Command, args []string) error {
 			checkTable(ctx, event.NewTable(ops.DBClient, ops.Environment))
 		case "Image":
 			checkTable(ctx, image.NewTable(ops.DBClient, ops.Environment))
+		case "Invitation":
+			checkTable(ctx, org.NewInvitationTable(ops.DBClient, ops.Environment))
+		case "Job":
+			checkTable(ctx, job.NewTable(ops.DBClient, ops.Environment))
+		case "Lockout":
+			checkTable(ctx, user.NewLockoutTable(ops.DBClient, ops.Environment))
+		case "Membership":
+			checkTable(ctx, user.NewMembershipTable(ops.DBClient, ops.Environment))
 		case "Metric":
 			checkTable(ctx, metric.NewTable(ops.DBClient, ops.Environment))
 		case "Organization":
 			checkTable(ctx, org.NewTable(ops.DBClient, ops.Environment))
+		case "RefreshToken":
+			checkTable(ctx, token.NewRefreshTable(ops.DBClient, ops.Environment))
+		case "Role":
+			checkTable(ctx, role.NewTable(ops.DBClient, ops.Environment))
 		case "Token":
 			checkTable(ctx, token.NewTable(ops.DBClient, ops.Environment))
 		case "User":
@@ -145,6 +162,8 @@ func deleteTables(cmd *cobra.Command, args []string) error {
 	for _, entity := range tables {
 		// TODO: add new DynamoDB tables here
 		switch entity {
+		case "APIKey":
+			deleteTable(ctx, apikey.NewTable(ops.DBClient, ops.Environment))
 		case "Content":
 			deleteTable(ctx, content.NewTable(ops.DBClient, ops.Environment))
 		case "Device":
@@ -157,8 +176,20 @@ func deleteTables(cmd *cobra.Command, args []string) error {
 			deleteTable(ctx, event.NewTable(ops.DBClient, ops.Environment))
 		case "Image":
 			deleteTable(ctx, image.NewTable(ops.DBClient, ops.Environment))
+		case "Invitation":
+			deleteTable(ctx, org.NewInvitationTable(ops.DBClient, ops.Environment))
+		case "Job":
+			deleteTable(ctx, job.NewTable(ops.DBClient, ops.Environment))
+		case "Lockout":
+			deleteTable(ctx, user.NewLockoutTable(ops.DBClient, ops.Environment))
+		case "Membership":
+			deleteTable(ctx, user.NewMembershipTable(ops.DBClient, ops.Environment))
 		case "Organization":
 			deleteTable(ctx, org.NewTable(ops.DBClient, ops.Environment))
+		case "RefreshToken":
+			deleteTable(ctx, token.NewRefreshTable(ops.DBClient, ops.Environment))
+		case "Role":
+			deleteTable(ctx, role.NewTable(ops.DBClient, ops.Environment))
 		case "Token":
 			deleteTable(ctx, token.NewTable(ops.DBClient, ops.Environment))
 		case "User":
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 	"versionary-api/pkg/token"
 
 	"github.com/spf13/cobra"
@@ -49,6 +50,18 @@ func initTokenCmd(root *cobra.Command) {
 	readCmd.Flags().StringP("env", "e", "", "Operating environment: dev | test | staging | prod")
 	_ = readCmd.MarkFlagRequired("env")
 	tokenCmd.AddCommand(readCmd)
+
+	rotateCmd := &cobra.Command{
+		Use:   "rotate-keys",
+		Short: "Rotate JWT signing keys",
+		Long:  "Generate a new JWT signing key, retaining recent previous keys to verify outstanding tokens.",
+		Args:  cobra.NoArgs,
+		RunE:  rotateSigningKeys,
+	}
+	rotateCmd.Flags().StringP("env", "e", "", "Operating environment: dev | test | staging | prod")
+	_ = rotateCmd.MarkFlagRequired("env")
+	rotateCmd.Flags().StringP("alg", "a", token.AlgEdDSA, "Signing algorithm: EdDSA | HS256")
+	tokenCmd.AddCommand(rotateCmd)
 }
 
 // createToken creates a new token for the specified user.
@@ -134,3 +147,31 @@ func readToken(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// rotateSigningKeys generates a new JWT signing key.
+func rotateSigningKeys(cmd *cobra.Command, args []string) error {
+	alg := cmd.Flag("alg").Value.String()
+	if !token.ValidAlgorithm(alg) {
+		return fmt.Errorf("invalid signing algorithm %q", alg)
+	}
+	// Initialize the application
+	err := ops.Init(cmd.Flag("env").Value.String())
+	if err != nil {
+		return fmt.Errorf("error initializing application: %w", err)
+	}
+	ctx := context.Background()
+
+	// Rotate the signing keys
+	ks, err := ops.RotateSigningKeys(ctx, alg)
+	if err != nil {
+		return err
+	}
+	for i, k := range ks.Keys {
+		status := "retained"
+		if i == 0 {
+			status = "current"
+		}
+		fmt.Printf("%s %s %s (%s)\n", k.ID, k.Algorithm, k.CreatedAt.Format(time.RFC3339), status)
+	}
+	return nil
+}
@@ -3,12 +3,14 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"versionary-api/pkg/event"
 	"versionary-api/pkg/user"
 
 	"github.com/spf13/cobra"
 	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
 )
 
 // initUserCmd initializes the user commands.
@@ -60,6 +62,39 @@ func initUserCmd(root *cobra.Command) {
 	readCmd.Flags().StringP("env", "e", "", "Operating environment: dev | test | staging | prod")
 	_ = readCmd.MarkFlagRequired("env")
 	userCmd.AddCommand(readCmd)
+
+	hashesCmd := &cobra.Command{
+		Use:   "hashes",
+		Short: "Report password hash schemes",
+		Long:  "Report the number of user accounts using each password hashing scheme, to track the migration away from legacy hashes.",
+		RunE:  reportPasswordHashes,
+	}
+	hashesCmd.Flags().StringP("env", "e", "", "Operating environment: dev | test | staging | prod")
+	hashesCmd.Flags().BoolP("list", "l", false, "List the IDs of users that need a rehash?")
+	_ = hashesCmd.MarkFlagRequired("env")
+	userCmd.AddCommand(hashesCmd)
+
+	unlockCmd := &cobra.Command{
+		Use:   "unlock <userID or email>",
+		Short: "Unlock specified user",
+		Long:  "Clear the failed login attempts recorded for the specified user account (by email address or ID), ending any temporary lockout.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  unlockUser,
+	}
+	unlockCmd.Flags().StringP("env", "e", "", "Operating environment: dev | test | staging | prod")
+	unlockCmd.Flags().StringP("ip", "i", "", "Client IP address to unlock as well")
+	_ = unlockCmd.MarkFlagRequired("env")
+	userCmd.AddCommand(unlockCmd)
+
+	membershipsCmd := &cobra.Command{
+		Use:   "sync-memberships",
+		Short: "Create missing organization memberships",
+		Long:  "Create or update the primary organization membership of every user account from the user's organization and roles, migrating single-organization users to the membership model.",
+		RunE:  syncMemberships,
+	}
+	membershipsCmd.Flags().StringP("env", "e", "", "Operating environment: dev | test | staging | prod")
+	_ = membershipsCmd.MarkFlagRequired("env")
+	userCmd.AddCommand(membershipsCmd)
 }
 
 // createUser creates a new user.
@@ -92,6 +127,9 @@ func createUser(cmd *cobra.Command, args []string) error {
 		if org.Name != "" {
 			u.OrgName = org.Name
 		}
+	} else if org, readErr := ops.OrgService.ReadOrganizationByEmail(ctx, u.Email); readErr == nil {
+		// Join the Organization that verified the email domain
+		u.OrgID, u.OrgName = org.ID, org.Name
 	}
 
 	// Create the User
@@ -170,3 +208,152 @@ func readUser(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// PasswordHashReport summarizes the password hashing schemes in use.
+type PasswordHashReport struct {
+	Total       int            `json:"total"`
+	Schemes     map[string]int `json:"schemes"`
+	NeedsRehash int            `json:"needsRehash"`
+	RehashIDs   []string       `json:"rehashIDs,omitempty"`
+}
+
+// reportPasswordHashes counts user accounts by password hashing scheme.
+func reportPasswordHashes(cmd *cobra.Command, args []string) error {
+	// Initialize the application
+	err := ops.Init(cmd.Flag("env").Value.String())
+	if err != nil {
+		return fmt.Errorf("error initializing application: %w", err)
+	}
+	ctx := context.Background()
+	list, _ := cmd.Flags().GetBool("list")
+
+	// Page through all User accounts
+	report := PasswordHashReport{
+		Schemes: map[string]int{},
+	}
+	offset := tuid.MinID
+	for {
+		ids, err := ops.UserService.ReadIDs(ctx, false, 100, offset)
+		if err != nil {
+			return fmt.Errorf("error reading user IDs after %s: %w", offset, err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		users := ops.UserService.Table.ReadEntities(ctx, ids)
+		if len(users) < len(ids) {
+			return fmt.Errorf("error reading users after %s: read %d of %d", offset, len(users), len(ids))
+		}
+		for _, u := range users {
+			report.Total++
+			report.Schemes[user.PasswordHashScheme(u.PasswordHash)]++
+			if u.PasswordNeedsRehash() {
+				report.NeedsRehash++
+				if list {
+					report.RehashIDs = append(report.RehashIDs, u.ID)
+				}
+			}
+		}
+		offset = ids[len(ids)-1]
+	}
+	j, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling JSON report: %w", err)
+	}
+	fmt.Println(string(j))
+	return nil
+}
+
+// unlockUser clears the failed login attempts for the specified user, and optionally a client IP address.
+func unlockUser(cmd *cobra.Command, args []string) error {
+	// Initialize the application
+	err := ops.Init(cmd.Flag("env").Value.String())
+	if err != nil {
+		return fmt.Errorf("error initializing application: %w", err)
+	}
+	ctx := context.Background()
+
+	// Read the specified User account
+	u, err := ops.UserService.Read(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("error reading User %s: %w", args[0], err)
+	}
+
+	// Clear the Lockout(s)
+	ip := cmd.Flag("ip").Value.String()
+	for _, kind := range []string{user.LockoutUser, user.LockoutIP} {
+		subject := u.ID
+		if kind == user.LockoutIP {
+			subject = ip
+		}
+		if subject == "" {
+			continue
+		}
+		l, err := ops.LockoutService.Unlock(ctx, kind, subject)
+		if err != nil && errors.Is(err, v.ErrNotFound) {
+			fmt.Printf("No failed login attempts for %s %s\n", kind, subject)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error unlocking %s %s: %w", kind, subject, err)
+		}
+		_, _, _ = ops.EventService.Create(ctx, event.Event{
+			EntityID:   u.ID,
+			EntityType: l.Type(),
+			LogLevel:   event.INFO,
+			Message:    fmt.Sprintf("unlocked %s %s after %d failed login attempts", kind, subject, l.Failures),
+		})
+		fmt.Printf("Unlocked %s %s after %d failed login attempts\n", kind, subject, l.Failures)
+	}
+	return nil
+}
+
+// syncMemberships reconciles the primary organization Membership of every user account with the user's Organization.
+// It's idempotent, so it may be run repeatedly.
+func syncMemberships(cmd *cobra.Command, args []string) error {
+	// Initialize the application
+	err := ops.Init(cmd.Flag("env").Value.String())
+	if err != nil {
+		return fmt.Errorf("error initializing application: %w", err)
+	}
+	ctx := context.Background()
+
+	// Page through all User accounts
+	var total, members, failed int
+	offset := tuid.MinID
+	for {
+		ids, err := ops.UserService.ReadIDs(ctx, false, 100, offset)
+		if err != nil {
+			return fmt.Errorf("error reading user IDs after %s: %w", offset, err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		users := ops.UserService.Table.ReadEntities(ctx, ids)
+		if len(users) < len(ids) {
+			return fmt.Errorf("error reading users after %s: read %d of %d", offset, len(users), len(ids))
+		}
+		for _, u := range users {
+			total++
+			if err = ops.UserService.Memberships.SyncUser(ctx, u); err != nil {
+				failed++
+				fmt.Println(err)
+				continue
+			}
+			if u.OrgID != "" {
+				members++
+			}
+		}
+		offset = users[len(users)-1].ID
+	}
+	_, _, _ = ops.EventService.Create(ctx, event.Event{
+		EntityType: ops.UserService.Memberships.EntityType,
+		LogLevel:   event.INFO,
+		Message:    fmt.Sprintf("synced memberships of %d users: %d in an organization, %d failed", total, members, failed),
+	})
+	fmt.Printf("Synced memberships of %d users: %d in an organization, %d failed\n", total, members, failed)
+	if failed > 0 {
+		return fmt.Errorf("error syncing memberships of %d users", failed)
+	}
+	return nil
+}
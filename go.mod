@@ -25,6 +25,7 @@ require (
 	github.com/voxtechnica/tuid-go v1.0.2
 	github.com/voxtechnica/user-agent v0.9.2
 	github.com/voxtechnica/versionary v1.4.0
+	golang.org/x/crypto v0.31.0
 	golang.org/x/image v0.23.0
 )
 
@@ -79,7 +80,6 @@ require (
 	github.com/twitchyliquid64/golang-asm v0.15.1 // indirect
 	github.com/ugorji/go/codec v1.2.12 // indirect
 	golang.org/x/arch v0.12.0 // indirect
-	golang.org/x/crypto v0.31.0 // indirect
 	golang.org/x/net v0.33.0 // indirect
 	golang.org/x/sys v0.28.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
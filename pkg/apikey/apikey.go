@@ -0,0 +1,140 @@
+package apikey
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+	"versionary-api/pkg/ref"
+
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+// SecretPrefix identifies a bearer credential as an APIKey secret, rather than an OAuth Token.
+// A complete secret has the form "vk_<APIKey ID>_<64 hex characters>".
+const SecretPrefix = "vk_"
+
+// APIKey is a long-lived credential for a service account User, granting access to an explicit list of scopes.
+// The secret is displayed only once, when the APIKey is created. Only a SHA256 hash of the secret is stored.
+// A fast hash is sufficient here, because the secret has 256 bits of entropy (unlike a password).
+type APIKey struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	ExpiresAt  time.Time `json:"expiresAt,omitempty"`
+	UserID     string    `json:"userId"`
+	Email      string    `json:"email,omitempty"`
+	Name       string    `json:"name"`
+	Scopes     []string  `json:"scopes"`
+	SecretHash string    `json:"secretHash,omitempty"`
+	Status     Status    `json:"status"`
+}
+
+// Type returns the entity type of the APIKey.
+func (k APIKey) Type() string {
+	return "APIKey"
+}
+
+// RefID returns the Reference ID of the entity.
+func (k APIKey) RefID() ref.RefID {
+	r, _ := ref.NewRefID(k.Type(), k.ID, "")
+	return r
+}
+
+// CompressedJSON returns a compressed JSON representation of the APIKey.
+func (k APIKey) CompressedJSON() []byte {
+	j, err := v.ToCompressedJSON(k)
+	if err != nil {
+		return nil
+	}
+	return j
+}
+
+// Scrub removes the secret hash from the APIKey, for use in API responses.
+func (k APIKey) Scrub() APIKey {
+	k.SecretHash = ""
+	return k
+}
+
+// IsExpired returns true if the APIKey has an expiration time that has passed.
+func (k APIKey) IsExpired(at time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !k.ExpiresAt.After(at)
+}
+
+// HasScope returns true if the APIKey grants the specified scope. A "write" scope implies "read".
+func (k APIKey) HasScope(scope string) bool {
+	if v.Contains(k.Scopes, scope) {
+		return true
+	}
+	resource, action, ok := strings.Cut(scope, ":")
+	return ok && action == READ && v.Contains(k.Scopes, Scope(resource, WRITE))
+}
+
+// ValidSecret checks the supplied secret against the stored secret hash.
+func (k APIKey) ValidSecret(secret string) bool {
+	return k.SecretHash != "" && secret != "" &&
+		subtle.ConstantTimeCompare([]byte(HashSecret(secret)), []byte(k.SecretHash)) == 1
+}
+
+// Validate checks whether the APIKey has all required fields and whether the supplied values are valid,
+// returning a list of problems. If the list is empty, then the APIKey is valid.
+func (k APIKey) Validate() []string {
+	var problems []string
+	if k.ID == "" || !tuid.IsValid(tuid.TUID(k.ID)) {
+		problems = append(problems, "ID is missing or invalid")
+	}
+	if k.CreatedAt.IsZero() {
+		problems = append(problems, "CreatedAt is missing")
+	}
+	if k.UpdatedAt.IsZero() {
+		problems = append(problems, "UpdatedAt is missing")
+	}
+	if k.UserID == "" || !tuid.IsValid(tuid.TUID(k.UserID)) {
+		problems = append(problems, "UserID is missing or invalid")
+	}
+	if k.Name == "" {
+		problems = append(problems, "Name is missing")
+	}
+	if len(k.Scopes) == 0 {
+		problems = append(problems, "Scopes are missing")
+	}
+	for _, s := range k.Scopes {
+		if !ValidScope(s) {
+			problems = append(problems, "Scope "+s+" is invalid. Expecting <resource>:read or <resource>:write, with resource: "+strings.Join(Resources, ", "))
+		}
+	}
+	if k.SecretHash == "" {
+		problems = append(problems, "SecretHash is missing")
+	}
+	if k.Status == "" || !k.Status.IsValid() {
+		statuses := v.Map(Statuses, func(s Status) string { return string(s) })
+		expected := strings.Join(statuses, ", ")
+		problems = append(problems, "Status is missing or invalid. Expecting: "+expected)
+	}
+	return problems
+}
+
+// HashSecret produces a hex-encoded SHA256 hash of an APIKey secret.
+func HashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// IsSecret returns true if the supplied bearer credential looks like an APIKey secret.
+func IsSecret(bearer string) bool {
+	return strings.HasPrefix(bearer, SecretPrefix)
+}
+
+// ParseSecret extracts the APIKey ID from an APIKey secret.
+func ParseSecret(secret string) (string, bool) {
+	if !IsSecret(secret) {
+		return "", false
+	}
+	id, random, ok := strings.Cut(strings.TrimPrefix(secret, SecretPrefix), "_")
+	if !ok || random == "" || !tuid.IsValid(tuid.TUID(id)) {
+		return "", false
+	}
+	return id, true
+}
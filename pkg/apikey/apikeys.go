@@ -0,0 +1,225 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+// ErrInvalidAPIKey is returned when an APIKey secret is malformed, unknown, revoked, or expired.
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+//==============================================================================
+// APIKey Table
+//==============================================================================
+
+// rowAPIKeys is a TableRow definition for APIKeys. APIKeys are not versioned.
+var rowAPIKeys = v.TableRow[APIKey]{
+	RowName:      "api_keys",
+	PartKeyName:  "id",
+	PartKeyValue: func(k APIKey) string { return k.ID },
+	PartKeyLabel: func(k APIKey) string { return k.Name },
+	SortKeyName:  "id",
+	SortKeyValue: func(k APIKey) string { return k.ID },
+	JsonValue:    func(k APIKey) []byte { return k.CompressedJSON() },
+}
+
+// rowAPIKeysUser is a TableRow definition for APIKeys by User ID.
+var rowAPIKeysUser = v.TableRow[APIKey]{
+	RowName:      "api_keys_user",
+	PartKeyName:  "user_id",
+	PartKeyValue: func(k APIKey) string { return k.UserID },
+	PartKeyLabel: func(k APIKey) string { return k.Email },
+	SortKeyName:  "id",
+	SortKeyValue: func(k APIKey) string { return k.ID },
+	JsonValue:    func(k APIKey) []byte { return k.CompressedJSON() },
+}
+
+// NewTable instantiates a new DynamoDB table for APIKeys.
+func NewTable(dbClient *dynamodb.Client, env string) v.Table[APIKey] {
+	if env == "" {
+		env = "dev"
+	}
+	return v.Table[APIKey]{
+		Client:     dbClient,
+		EntityType: "APIKey",
+		TableName:  "api_keys" + "_" + env,
+		TTL:        false,
+		EntityRow:  rowAPIKeys,
+		IndexRows: map[string]v.TableRow[APIKey]{
+			rowAPIKeysUser.RowName: rowAPIKeysUser,
+		},
+	}
+}
+
+// NewMemTable creates an in-memory APIKey table for testing purposes.
+func NewMemTable(table v.Table[APIKey]) v.MemTable[APIKey] {
+	return v.NewMemTable(table)
+}
+
+//==============================================================================
+// APIKey Service
+//==============================================================================
+
+// Service is used to manage APIKeys in a DynamoDB table.
+type Service struct {
+	EntityType string
+	Table      v.TableReadWriter[APIKey]
+}
+
+// NewService creates a new APIKey service backed by a Versionary Table for the specified environment.
+func NewService(dbClient *dynamodb.Client, env string) Service {
+	table := NewTable(dbClient, env)
+	return Service{
+		EntityType: table.EntityType,
+		Table:      table,
+	}
+}
+
+// NewMockService creates a new APIKey service backed by an in-memory table for testing purposes.
+func NewMockService(env string) Service {
+	table := NewMemTable(NewTable(nil, env))
+	return Service{
+		EntityType: table.EntityType,
+		Table:      table,
+	}
+}
+
+//------------------------------------------------------------------------------
+// APIKeys
+//------------------------------------------------------------------------------
+
+// Create an APIKey in the APIKey table. The generated secret is returned, and it is not stored anywhere.
+// It cannot be recovered later; if it is lost, the APIKey should be revoked and replaced.
+func (s Service) Create(ctx context.Context, k APIKey) (APIKey, string, []string, error) {
+	t := tuid.NewID()
+	at, _ := t.Time()
+	k.ID = t.String()
+	k.CreatedAt = at
+	k.UpdatedAt = at
+	k.Status = ENABLED
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return k, "", nil, fmt.Errorf("error creating %s %s: generate secret: %w", s.EntityType, k.ID, err)
+	}
+	secret := SecretPrefix + k.ID + "_" + hex.EncodeToString(random)
+	k.SecretHash = HashSecret(secret)
+	problems := k.Validate()
+	if len(problems) > 0 {
+		return k, "", problems, fmt.Errorf("error creating %s %s: invalid field(s): %s", s.EntityType, k.ID, strings.Join(problems, ", "))
+	}
+	if err := s.Table.WriteEntity(ctx, k); err != nil {
+		return k, "", problems, fmt.Errorf("error creating %s %s for User-%s: %w", s.EntityType, k.ID, k.UserID, err)
+	}
+	return k, secret, problems, nil
+}
+
+// Authenticate reads the APIKey identified by the supplied secret, and verifies that the secret matches,
+// and that the APIKey is neither revoked nor expired. ErrInvalidAPIKey is returned if the key is not usable.
+func (s Service) Authenticate(ctx context.Context, secret string) (APIKey, error) {
+	id, ok := ParseSecret(secret)
+	if !ok {
+		return APIKey{}, ErrInvalidAPIKey
+	}
+	k, err := s.Table.ReadEntity(ctx, id)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		return APIKey{}, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return APIKey{}, fmt.Errorf("error reading %s %s: %w", s.EntityType, id, err)
+	}
+	if !k.ValidSecret(secret) || k.Status != ENABLED || k.IsExpired(time.Now()) {
+		return APIKey{}, ErrInvalidAPIKey
+	}
+	return k, nil
+}
+
+// Revoke marks the specified APIKey as REVOKED. The revoked APIKey is returned.
+func (s Service) Revoke(ctx context.Context, id string) (APIKey, error) {
+	k, err := s.Table.ReadEntity(ctx, id)
+	if err != nil {
+		return k, err
+	}
+	if k.Status == REVOKED {
+		return k, nil
+	}
+	k.Status = REVOKED
+	k.UpdatedAt = time.Now()
+	if err = s.Table.WriteEntity(ctx, k); err != nil {
+		return k, fmt.Errorf("error revoking %s %s: %w", s.EntityType, id, err)
+	}
+	return k, nil
+}
+
+// RevokeAllAPIKeysByUserID revokes all APIKeys for a specified User ID.
+func (s Service) RevokeAllAPIKeysByUserID(ctx context.Context, userID string) error {
+	ids, err := s.ReadAllAPIKeyIDsByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("error revoking all %ss for User-%s: %w", s.EntityType, userID, err)
+	}
+	for _, id := range ids {
+		if _, err = s.Revoke(ctx, id); err != nil {
+			return fmt.Errorf("error revoking all %ss for User-%s: %w", s.EntityType, userID, err)
+		}
+	}
+	return nil
+}
+
+// Write an APIKey to the APIKey table. This method assumes that the APIKey has all the required fields.
+// It would most likely be used for "refreshing" the index rows in the APIKey table.
+func (s Service) Write(ctx context.Context, k APIKey) (APIKey, error) {
+	return k, s.Table.WriteEntity(ctx, k)
+}
+
+// Delete an APIKey from the APIKey table. The deleted APIKey is returned.
+func (s Service) Delete(ctx context.Context, id string) (APIKey, error) {
+	return s.Table.DeleteEntityWithID(ctx, id)
+}
+
+// Exists checks if an APIKey exists in the APIKey table.
+func (s Service) Exists(ctx context.Context, id string) bool {
+	return s.Table.EntityExists(ctx, id)
+}
+
+// Read a specified APIKey from the APIKey table.
+func (s Service) Read(ctx context.Context, id string) (APIKey, error) {
+	return s.Table.ReadEntity(ctx, id)
+}
+
+// ReadIDs returns a paginated list of APIKey IDs and Names in the APIKey table.
+// Sorting is chronological (or reverse). The offset is the last ID returned in a previous request.
+func (s Service) ReadIDs(ctx context.Context, reverse bool, limit int, offset string) ([]v.TextValue, error) {
+	return s.Table.ReadEntityLabels(ctx, reverse, limit, offset)
+}
+
+// ReadAPIKeys returns a paginated list of APIKeys in the APIKey table.
+// Sorting is chronological (or reverse). The offset is the last ID returned in a previous request.
+func (s Service) ReadAPIKeys(ctx context.Context, reverse bool, limit int, offset string) []APIKey {
+	ids, err := s.Table.ReadEntityIDs(ctx, reverse, limit, offset)
+	if err != nil {
+		return []APIKey{}
+	}
+	return s.Table.ReadEntities(ctx, ids)
+}
+
+//------------------------------------------------------------------------------
+// APIKeys by User ID
+//------------------------------------------------------------------------------
+
+// ReadAllAPIKeyIDsByUserID returns a complete list of APIKey IDs for a specified User ID.
+func (s Service) ReadAllAPIKeyIDsByUserID(ctx context.Context, userID string) ([]string, error) {
+	return s.Table.ReadAllSortKeyValues(ctx, rowAPIKeysUser, userID)
+}
+
+// ReadAllAPIKeysByUserID returns the complete list of APIKeys for a User, sorted chronologically.
+func (s Service) ReadAllAPIKeysByUserID(ctx context.Context, userID string) ([]APIKey, error) {
+	return s.Table.ReadAllEntitiesFromRow(ctx, rowAPIKeysUser, userID)
+}
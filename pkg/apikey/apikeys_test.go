@@ -0,0 +1,149 @@
+package apikey
+
+import (
+	"context"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+var (
+	// APIKey Service
+	ctx     = context.Background()
+	service = NewMockService("test")
+
+	// Known User IDs
+	userID1 = tuid.NewID().String()
+	userID2 = tuid.NewID().String()
+)
+
+func TestMain(m *testing.M) {
+	// Check table/row definitions
+	if !service.Table.IsValid() {
+		log.Fatal("invalid table configuration")
+	}
+	// Run tests
+	m.Run()
+}
+
+func TestCreateAuthenticateRevoke(t *testing.T) {
+	expect := assert.New(t)
+	// Create an APIKey
+	k, secret, problems, err := service.Create(ctx, APIKey{
+		UserID: userID1,
+		Email:  "service_account@test.net",
+		Name:   "Image Uploader",
+		Scopes: []string{"images:write", "metrics:write"},
+	})
+	expect.Empty(problems)
+	if !expect.NoError(err) {
+		return
+	}
+	expect.Equal(ENABLED, k.Status)
+	expect.True(strings.HasPrefix(secret, SecretPrefix+k.ID+"_"))
+	expect.NotContains(string(k.CompressedJSON()), secret, "Secret is not stored")
+	expect.Equal(HashSecret(secret), k.SecretHash)
+	expect.Empty(k.Scrub().SecretHash)
+	// Authenticate with the secret
+	check, err := service.Authenticate(ctx, secret)
+	if expect.NoError(err) {
+		expect.Equal(k.ID, check.ID)
+		expect.Equal(userID1, check.UserID)
+	}
+	// Wrong secrets are rejected
+	_, err = service.Authenticate(ctx, secret[:len(secret)-1]+"x")
+	expect.ErrorIs(err, ErrInvalidAPIKey)
+	_, err = service.Authenticate(ctx, "vk_garbage")
+	expect.ErrorIs(err, ErrInvalidAPIKey)
+	_, err = service.Authenticate(ctx, SecretPrefix+tuid.NewID().String()+"_abc")
+	expect.ErrorIs(err, ErrInvalidAPIKey)
+	// List keys by user
+	keys, err := service.ReadAllAPIKeysByUserID(ctx, userID1)
+	if expect.NoError(err) && expect.Equal(1, len(keys)) {
+		expect.Equal(k.ID, keys[0].ID)
+	}
+	// Revoke the APIKey
+	revoked, err := service.Revoke(ctx, k.ID)
+	if expect.NoError(err) {
+		expect.Equal(REVOKED, revoked.Status)
+	}
+	_, err = service.Authenticate(ctx, secret)
+	expect.ErrorIs(err, ErrInvalidAPIKey)
+	// Revoked keys are retained
+	expect.True(service.Exists(ctx, k.ID))
+	_, err = service.Revoke(ctx, tuid.NewID().String())
+	expect.ErrorIs(err, v.ErrNotFound)
+}
+
+func TestExpiredAPIKey(t *testing.T) {
+	expect := assert.New(t)
+	k, secret, _, err := service.Create(ctx, APIKey{
+		UserID:    userID2,
+		Name:      "Expired Key",
+		Scopes:    []string{"contents:read"},
+		ExpiresAt: time.Now().Add(-time.Hour),
+	})
+	if expect.NoError(err) {
+		_, err = service.Authenticate(ctx, secret)
+		expect.ErrorIs(err, ErrInvalidAPIKey)
+		expect.NoError(service.RevokeAllAPIKeysByUserID(ctx, userID2))
+		check, err := service.Read(ctx, k.ID)
+		if expect.NoError(err) {
+			expect.Equal(REVOKED, check.Status)
+		}
+	}
+}
+
+func TestInvalidAPIKey(t *testing.T) {
+	expect := assert.New(t)
+	_, secret, problems, err := service.Create(ctx, APIKey{
+		UserID: "bogus",
+		Scopes: []string{"images:delete", "api_keys:write"},
+	})
+	expect.Error(err)
+	expect.Empty(secret)
+	expect.Equal(4, len(problems), "UserID, Name, and two invalid Scopes")
+}
+
+func TestHasScope(t *testing.T) {
+	expect := assert.New(t)
+	k := APIKey{Scopes: []string{"images:write", "contents:read"}}
+	expect.True(k.HasScope("images:write"))
+	expect.True(k.HasScope("images:read"), "write implies read")
+	expect.True(k.HasScope("contents:read"))
+	expect.False(k.HasScope("contents:write"), "read does not imply write")
+	expect.False(k.HasScope("users:read"))
+	expect.False(k.HasScope(""))
+}
+
+func TestPathResource(t *testing.T) {
+	expect := assert.New(t)
+	expect.Equal("images", PathResource("/v1/images"))
+	expect.Equal("images", PathResource("/v1/images/:id/versions"))
+	expect.Equal("images", PathResource("/v1/image_ids"))
+	expect.Equal("organizations", PathResource("/v1/organization_names"))
+	expect.Equal("users", PathResource("/v1/user_orgs"))
+	expect.Equal("views", PathResource("/v1/view_counts/:date"))
+	expect.Equal("apis", PathResource("/v1/api_keys"))
+	expect.Equal("", PathResource("/login"))
+	expect.Equal("", PathResource("/v1"))
+	expect.Equal(READ, ScopeAction("GET"))
+	expect.Equal(READ, ScopeAction("HEAD"))
+	expect.Equal(WRITE, ScopeAction("POST"))
+	expect.Equal(WRITE, ScopeAction("DELETE"))
+}
+
+func TestPermissionScope(t *testing.T) {
+	expect := assert.New(t)
+	expect.Equal("images:write", PermissionScope("image:write"))
+	expect.Equal("users:read", PermissionScope("user:read"))
+	expect.Equal("contents:write", PermissionScope("content:publish"))
+	expect.Equal("", PermissionScope("apikey:write"), "api keys may not manage api keys")
+	expect.Equal("", PermissionScope("role:write"))
+	expect.Equal("", PermissionScope("*"))
+}
@@ -0,0 +1,89 @@
+package apikey
+
+import (
+	"strings"
+
+	v "github.com/voxtechnica/versionary"
+)
+
+// A scope grants an APIKey access to a resource, in the form "<resource>:<action>" (e.g. "images:write").
+// The resource is the plural entity name used in the API paths, and the action is either "read" or "write".
+// A "write" scope implies the corresponding "read" scope.
+
+// READ is the action for safe requests (GET, HEAD, OPTIONS).
+const READ = "read"
+
+// WRITE is the action for all other requests (POST, PUT, PATCH, DELETE).
+const WRITE = "write"
+
+// Resources is the complete list of resources that may be granted to an APIKey.
+// API keys are deliberately not able to manage API keys.
+var Resources = []string{
+	"contents",
+	"devices",
+	"emails",
+	"events",
+	"images",
+	"metrics",
+	"organizations",
+	"tokens",
+	"users",
+	"views",
+}
+
+// Scope returns a scope string for the specified resource and action.
+func Scope(resource, action string) string {
+	return resource + ":" + action
+}
+
+// ValidScope returns true if the supplied scope has a recognized resource and action.
+func ValidScope(scope string) bool {
+	resource, action, ok := strings.Cut(scope, ":")
+	return ok && v.Contains(Resources, resource) && (action == READ || action == WRITE)
+}
+
+// ScopeAction returns the action required for the specified HTTP method.
+func ScopeAction(method string) string {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS":
+		return READ
+	default:
+		return WRITE
+	}
+}
+
+// PathResource derives the resource name from an API path, using its first segment after the version.
+// Auxiliary collections are mapped to their entity resource (e.g. "/v1/image_ids" is "images").
+// An empty string is returned if the path does not identify a resource.
+func PathResource(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "v1" {
+		return ""
+	}
+	resource, _, _ := strings.Cut(segments[1], "_")
+	if resource == "" {
+		return ""
+	}
+	if !strings.HasSuffix(resource, "s") {
+		resource += "s"
+	}
+	return resource
+}
+
+// PermissionScope returns the scope that corresponds to a role permission, in the form "<resource>:<action>"
+// (e.g. "image:write" is "images:write"). Permissions to publish are write scopes. An empty string is returned
+// if the permission's resource may not be granted to an APIKey (e.g. "apikey:write").
+func PermissionScope(perm string) string {
+	resource, action, ok := strings.Cut(perm, ":")
+	if !ok {
+		return ""
+	}
+	if action != READ {
+		action = WRITE
+	}
+	resource += "s"
+	if !v.Contains(Resources, resource) {
+		return ""
+	}
+	return Scope(resource, action)
+}
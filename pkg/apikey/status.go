@@ -0,0 +1,43 @@
+package apikey
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Status indicates the operational state of an APIKey
+type Status string
+
+// ENABLED Status indicates that the APIKey may be used
+const ENABLED Status = "ENABLED"
+
+// REVOKED Status indicates that the APIKey has been revoked, and may no longer be used
+const REVOKED Status = "REVOKED"
+
+// Statuses is the complete list of valid APIKey statuses
+var Statuses = []Status{ENABLED, REVOKED}
+
+// IsValid returns true if the supplied Status is recognized
+func (s Status) IsValid() bool {
+	for _, v := range Statuses {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a string representation of the Status
+func (s Status) String() string {
+	return string(s)
+}
+
+// ParseStatus returns a Status from a string representation.
+// It validates the string before returning the Status.
+func ParseStatus(s string) (Status, error) {
+	status := Status(strings.ToUpper(s))
+	if status.IsValid() {
+		return status, nil
+	}
+	return "", fmt.Errorf("invalid status: %s", s)
+}
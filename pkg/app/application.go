@@ -3,16 +3,21 @@ package app
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"runtime"
+	"sync"
 	"time"
+	"versionary-api/pkg/apikey"
 	"versionary-api/pkg/content"
 	"versionary-api/pkg/device"
 	"versionary-api/pkg/email"
 	"versionary-api/pkg/event"
 	"versionary-api/pkg/image"
+	"versionary-api/pkg/job"
 	"versionary-api/pkg/metric"
 	"versionary-api/pkg/org"
+	"versionary-api/pkg/role"
 	"versionary-api/pkg/token"
 	"versionary-api/pkg/user"
 	"versionary-api/pkg/view"
@@ -43,34 +48,46 @@ func (a About) String() string {
 
 // Application is the main application object, which contains configuration settings, keys, and initialized services.
 type Application struct {
-	Name               string           // Name of the application
-	GitHash            string           // Git hash of the application
-	BuildTime          time.Time        // Executable build time
-	Language           string           // Go Compiler version (e.g. "go1.x")
-	Environment        string           // Environment name (e.g. "dev", "test", "staging", "prod")
-	BaseDomain         string           // Base domain for the application (e.g. "versionary.net")
-	AdminURL           string           // Admin App URL (e.g. "https://admin.versionary.net")
-	APIURL             string           // API URL (e.g. "https://api.versionary.net")
-	WebURL             string           // Web URL (e.g. "https://www.versionary.net")
-	Description        string           // Description of the application
-	EntityTypes        []string         // Valid entity type names (e.g. "Event", "User", etc.)
-	AWSConfig          aws.Config       // AWS Configuration
-	DBClient           *dynamodb.Client // AWS DynamoDB client
-	S3Client           *s3.Client       // AWS S3 client
-	SESClient          *ses.Client      // AWS SES client
-	ParameterStore     ParameterStore   // AWS SSM Parameter Store client
-	ContentService     content.Service
-	DeviceService      device.Service
-	DeviceCountService device.CountService
-	EmailService       email.Service
-	EventService       event.Service
-	ImageService       image.Service
-	MetricService      metric.Service
-	OrgService         org.Service
-	TokenService       token.Service
-	UserService        user.Service
-	ViewService        view.Service
-	ViewCountService   view.CountService
+	Name                string            // Name of the application
+	GitHash             string            // Git hash of the application
+	BuildTime           time.Time         // Executable build time
+	Language            string            // Go Compiler version (e.g. "go1.x")
+	Environment         string            // Environment name (e.g. "dev", "test", "staging", "prod")
+	BaseDomain          string            // Base domain for the application (e.g. "versionary.net")
+	AdminURL            string            // Admin App URL (e.g. "https://admin.versionary.net")
+	APIURL              string            // API URL (e.g. "https://api.versionary.net")
+	WebURL              string            // Web URL (e.g. "https://www.versionary.net")
+	Description         string            // Description of the application
+	RequireVerified     bool              // Refuse tokens to PENDING Users (e.g. unverified email address)
+	JWTAlgorithm        string            // Issue signed JWT access tokens (EdDSA or HS256); opaque tokens if empty
+	SignupDomains       user.DomainPolicy // Email domains allowed/blocked for self-service registration
+	SyncJobs            bool              // Run Jobs before responding, rather than in the background (e.g. in AWS Lambda)
+	OrgScopeDescendants bool              // Extend org-scoped permissions to descendant Organizations (e.g. divisions)
+	EntityTypes         []string          // Valid entity type names (e.g. "Event", "User", etc.)
+	AWSConfig           aws.Config        // AWS Configuration
+	DBClient            *dynamodb.Client  // AWS DynamoDB client
+	S3Client            *s3.Client        // AWS S3 client
+	SESClient           *ses.Client       // AWS SES client
+	ParameterStore      ParameterStore    // AWS SSM Parameter Store client
+	DNSResolver         org.TXTResolver   // DNS TXT record resolver, for verifying Organization email domains
+	APIKeyService       apikey.Service
+	ContentService      content.Service
+	DeviceService       device.Service
+	DeviceCountService  device.CountService
+	EmailService        email.Service
+	EventService        event.Service
+	ImageService        image.Service
+	InvitationService   org.InvitationService
+	JobService          job.Service
+	LockoutService      user.LockoutService
+	MetricService       metric.Service
+	OrgService          org.Service
+	RoleService         role.Service
+	TokenService        token.Service
+	UserService         user.Service
+	ViewService         view.Service
+	ViewCountService    view.CountService
+	jobs                sync.WaitGroup // background Jobs in progress
 }
 
 // About returns basic information about the initialized Application.
@@ -134,14 +151,21 @@ func (a *Application) setDefaults() {
 	// Entity Types
 	// TODO: Update this list and initialize new services below as new entity types are added
 	a.EntityTypes = []string{
+		"APIKey",
 		"Content",
 		"Device",
 		"DeviceCount",
 		"Email",
 		"Event",
 		"Image",
+		"Invitation",
+		"Job",
+		"Lockout",
+		"Membership",
 		"Metric",
 		"Organization",
+		"RefreshToken",
+		"Role",
 		"Token",
 		"User",
 		"View",
@@ -168,8 +192,10 @@ func (a *Application) Init(env string) error {
 	a.S3Client = s3.NewFromConfig(cfg)
 	a.SESClient = ses.NewFromConfig(cfg)
 	a.ParameterStore = NewParameterStore(cfg)
+	a.DNSResolver = net.DefaultResolver
 
 	// Initialize Services
+	a.APIKeyService = apikey.NewService(a.DBClient, a.Environment)
 	a.ContentService = content.NewService(a.DBClient, a.Environment)
 	a.DeviceService = device.NewService(a.DBClient, a.Environment)
 	a.DeviceCountService = device.NewCountService(a.DBClient, a.Environment)
@@ -191,8 +217,12 @@ func (a *Application) Init(env string) error {
 	}
 	a.EventService = event.NewService(a.DBClient, a.Environment)
 	a.ImageService = image.NewService(a.DBClient, a.S3Client, a.Environment)
+	a.InvitationService = org.NewInvitationService(a.DBClient, a.Environment)
+	a.JobService = job.NewService(a.DBClient, a.Environment)
+	a.LockoutService = user.NewLockoutService(a.DBClient, a.Environment)
 	a.MetricService = metric.NewService(a.DBClient, a.Environment)
 	a.OrgService = org.NewService(a.DBClient, a.Environment)
+	a.RoleService = role.NewService(a.DBClient, a.Environment)
 	a.TokenService = token.NewService(a.DBClient, a.Environment)
 	a.UserService = user.NewService(a.DBClient, a.Environment)
 	a.ViewService = view.NewService(a.DBClient, a.Environment)
@@ -210,8 +240,10 @@ func (a *Application) InitMock(env string) error {
 
 	// Initialize Mock Clients
 	a.ParameterStore = NewParameterStoreMock()
+	a.DNSResolver = org.StaticResolver{}
 
 	// Initialize Services
+	a.APIKeyService = apikey.NewMockService(a.Environment)
 	a.ContentService = content.NewMockService(a.Environment)
 	a.DeviceService = device.NewMockService(a.Environment)
 	a.DeviceCountService = device.NewMockCountService(a.Environment)
@@ -232,8 +264,12 @@ func (a *Application) InitMock(env string) error {
 	}
 	a.EventService = event.NewMockService(a.Environment)
 	a.ImageService = image.NewMockService(a.Environment)
+	a.InvitationService = org.NewMockInvitationService(a.Environment)
+	a.JobService = job.NewMockService(a.Environment)
+	a.LockoutService = user.NewMockLockoutService(a.Environment)
 	a.MetricService = metric.NewMockService(a.Environment)
 	a.OrgService = org.NewMockService(a.Environment)
+	a.RoleService = role.NewMockService(a.Environment)
 	a.TokenService = token.NewMockService(a.Environment)
 	a.UserService = user.NewMockService(a.Environment)
 	a.ViewService = view.NewMockService(a.Environment)
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"versionary-api/pkg/content"
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/job"
+)
+
+// SchedulerName identifies the scheduled publication job as the editor of the Content versions it saves.
+const SchedulerName = "Publication Scheduler"
+
+// PublishDue applies the scheduled Content changes that are due at the specified time, recording progress in
+// the Job. Expired Content is ARCHIVED, and embargoed Content whose PublishAt time has passed is marked as
+// live (PublishedAt). The public read path already honors the schedule; this keeps the Status, the editorial
+// history, and the schedule index up to date.
+func (a *Application) PublishDue(ctx context.Context, j job.Job, at time.Time) job.Job {
+	j.Start()
+	if saved, err := a.JobService.Update(ctx, j); err == nil {
+		j = saved
+	}
+	due, err := a.ContentService.ReadDue(ctx, at)
+	j.Total = len(due)
+	for _, c := range due {
+		j.Processed++
+		j.Stale++
+		var message string
+		var e error
+		if c.IsExpired(at) {
+			comment := fmt.Sprintf("expired at %s", c.UnpublishAt.UTC().Format(time.RFC3339))
+			c, e = a.ContentService.Transition(ctx, c, content.ARCHIVED, "", SchedulerName, comment)
+			message = fmt.Sprintf("archived %s %s: %s", c.Type, c.ID, comment)
+		} else {
+			c.PublishedAt = c.PublishAt
+			c.EditorID = ""
+			c.EditorName = SchedulerName
+			c.Comment = fmt.Sprintf("published at %s", c.PublishAt.UTC().Format(time.RFC3339))
+			c, _, e = a.ContentService.Update(ctx, c)
+			message = fmt.Sprintf("published %s %s: %s", c.Type, c.ID, c.Comment)
+		}
+		if e != nil {
+			j.Fail(fmt.Errorf("error updating scheduled %s %s: %w", c.Type, c.ID, e))
+			continue
+		}
+		j.Changed++
+		_, _, _ = a.EventService.Create(ctx, event.Event{
+			EntityID:   c.ID,
+			EntityType: a.ContentService.EntityType,
+			LogLevel:   event.INFO,
+			Message:    message,
+		})
+	}
+	if err != nil {
+		err = fmt.Errorf("error reading scheduled content: %w", err)
+	}
+	j.Finish(err)
+	if saved, e := a.JobService.Update(ctx, j); e == nil {
+		j = saved
+	}
+	return j
+}
@@ -0,0 +1,227 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/voxtechnica/tuid-go"
+
+	"versionary-api/pkg/job"
+	"versionary-api/pkg/org"
+	"versionary-api/pkg/user"
+)
+
+// jobProgressInterval is how often (in items processed) a running Job records its progress.
+const jobProgressInterval = 25
+
+// RunJob runs a task in the background, unless SyncJobs is set (e.g. in AWS Lambda, where background work
+// is frozen between requests), in which case the task finishes before RunJob returns.
+func (a *Application) RunJob(task func(ctx context.Context)) {
+	if a.SyncJobs {
+		task(context.Background())
+		return
+	}
+	a.jobs.Add(1)
+	go func() {
+		defer a.jobs.Done()
+		task(context.Background())
+	}()
+}
+
+// WaitForJobs waits for any background Jobs to finish.
+func (a *Application) WaitForJobs() {
+	a.jobs.Wait()
+}
+
+//------------------------------------------------------------------------------
+// Denormalized Names
+//------------------------------------------------------------------------------
+
+// SyncOrgName propagates an Organization's name to the copies kept by its members (User.OrgName) and in the
+// Memberships and Invitations, recording progress in the Job. It's used after an Organization is renamed.
+func (a *Application) SyncOrgName(ctx context.Context, j job.Job, o org.Organization) job.Job {
+	s := nameSync{a: a, job: j, fix: true}
+	s.start(ctx)
+	return s.finish(ctx, s.syncOrg(ctx, o))
+}
+
+// SyncNames finds stale denormalized names across all Organizations, Users, Memberships, Invitations,
+// and Tokens, recording progress in the Job. If fix is false, stale names are counted, but not updated.
+func (a *Application) SyncNames(ctx context.Context, j job.Job, fix bool) job.Job {
+	s := nameSync{a: a, job: j, fix: fix}
+	s.start(ctx)
+	return s.finish(ctx, s.syncAll(ctx))
+}
+
+// nameSync finds and (optionally) fixes stale denormalized names, tracking progress in a Job.
+type nameSync struct {
+	a   *Application
+	job job.Job
+	fix bool
+}
+
+// start marks the Job as running.
+func (s *nameSync) start(ctx context.Context) {
+	s.job.Start()
+	s.save(ctx)
+}
+
+// finish marks the Job as finished, and returns it.
+func (s *nameSync) finish(ctx context.Context, err error) job.Job {
+	s.job.Finish(err)
+	s.save(ctx)
+	return s.job
+}
+
+// save records the Job's progress. Progress is best-effort; the Job continues even if it can't be saved.
+func (s *nameSync) save(ctx context.Context) {
+	if j, err := s.a.JobService.Update(ctx, s.job); err == nil {
+		s.job = j
+	}
+}
+
+// check records that an item was processed. If it's stale, it's counted, and updated if fixing is enabled.
+func (s *nameSync) check(ctx context.Context, stale bool, update func() error) {
+	s.job.Processed++
+	if stale {
+		s.job.Stale++
+		if s.fix {
+			if err := update(); err != nil {
+				s.job.Fail(err)
+			} else {
+				s.job.Changed++
+			}
+		}
+	}
+	if s.job.Processed%jobProgressInterval == 0 {
+		s.save(ctx)
+	}
+}
+
+// skip records that an item could not be processed.
+func (s *nameSync) skip(ctx context.Context, err error) {
+	s.job.Processed++
+	s.job.Fail(err)
+	if s.job.Processed%jobProgressInterval == 0 {
+		s.save(ctx)
+	}
+}
+
+// syncOrg updates the Organization name in its members, Memberships, and Invitations.
+func (s *nameSync) syncOrg(ctx context.Context, o org.Organization) error {
+	memberships, err := s.a.UserService.Memberships.ReadAllMembershipsByOrgID(ctx, o.ID)
+	if err != nil {
+		return fmt.Errorf("error reading memberships in organization %s: %w", o.ID, err)
+	}
+	invitations, err := s.a.InvitationService.ReadAllInvitationsByOrgID(ctx, o.ID)
+	if err != nil {
+		return fmt.Errorf("error reading invitations to organization %s: %w", o.ID, err)
+	}
+	s.job.Total += len(memberships) + len(invitations)
+	for _, m := range memberships {
+		if !m.Primary {
+			s.check(ctx, m.OrgName != o.Name, func() error {
+				m.OrgName = o.Name
+				_, _, err := s.a.UserService.Memberships.Update(ctx, m)
+				return err
+			})
+			continue
+		}
+		// The primary Membership follows the User
+		u, err := s.a.UserService.Read(ctx, m.UserID)
+		if err != nil {
+			s.skip(ctx, fmt.Errorf("error reading user %s: %w", m.UserID, err))
+			continue
+		}
+		s.check(ctx, u.OrgID == o.ID && (u.OrgName != o.Name || m.OrgName != o.Name), func() error {
+			u.OrgName = o.Name
+			_, _, err := s.a.UserService.Update(ctx, u)
+			return err
+		})
+	}
+	for _, i := range invitations {
+		s.check(ctx, i.OrgName != o.Name, func() error {
+			i.OrgName = o.Name
+			_, _, err := s.a.InvitationService.Update(ctx, i)
+			return err
+		})
+	}
+	return nil
+}
+
+// syncAll checks every Organization, and then every User, including Users without a primary Membership.
+func (s *nameSync) syncAll(ctx context.Context) error {
+	orgs, err := s.a.OrgService.ReadAllNames(ctx, false)
+	if err != nil {
+		return fmt.Errorf("error reading organization names: %w", err)
+	}
+	names := make(map[string]string, len(orgs))
+	for _, o := range orgs {
+		names[o.Key] = o.Value
+		if err = s.syncOrg(ctx, org.Organization{ID: o.Key, Name: o.Value}); err != nil {
+			return err
+		}
+	}
+	offset := tuid.MinID
+	for {
+		users := s.a.UserService.ReadUsers(ctx, false, 100, offset)
+		if len(users) == 0 {
+			break
+		}
+		s.job.Total += len(users)
+		for _, u := range users {
+			name, ok := names[u.OrgID]
+			s.check(ctx, ok && u.OrgName != name, func() error {
+				u.OrgName = name
+				_, _, err := s.a.UserService.Update(ctx, u)
+				return err
+			})
+			s.syncUser(ctx, u)
+		}
+		offset = users[len(users)-1].ID
+	}
+	return nil
+}
+
+// syncUser updates the User's email address in their Memberships, Tokens, and RefreshTokens.
+func (s *nameSync) syncUser(ctx context.Context, u user.User) {
+	memberships, err := s.a.UserService.Memberships.ReadAllMembershipsByUserID(ctx, u.ID)
+	if err != nil {
+		s.skip(ctx, fmt.Errorf("error reading memberships of user %s: %w", u.ID, err))
+		return
+	}
+	s.job.Total += len(memberships)
+	for _, m := range memberships {
+		s.check(ctx, m.Email != u.Email, func() error {
+			m.Email = u.Email
+			_, _, err := s.a.UserService.Memberships.Update(ctx, m)
+			return err
+		})
+	}
+	tokens, err := s.a.TokenService.ReadAllTokensByUserID(ctx, u.ID)
+	if err != nil {
+		s.skip(ctx, fmt.Errorf("error reading tokens of user %s: %w", u.ID, err))
+		return
+	}
+	s.job.Total += len(tokens)
+	for _, t := range tokens {
+		s.check(ctx, t.Email != u.Email, func() error {
+			t.Email = u.Email
+			_, err := s.a.TokenService.Write(ctx, t)
+			return err
+		})
+	}
+	refreshTokens, err := s.a.TokenService.ReadAllRefreshTokensByUserID(ctx, u.ID)
+	if err != nil {
+		s.skip(ctx, fmt.Errorf("error reading refresh tokens of user %s: %w", u.ID, err))
+		return
+	}
+	s.job.Total += len(refreshTokens)
+	for _, rt := range refreshTokens {
+		s.check(ctx, rt.Email != u.Email, func() error {
+			rt.Email = u.Email
+			_, err := s.a.TokenService.WriteRefreshToken(ctx, rt)
+			return err
+		})
+	}
+}
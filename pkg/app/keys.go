@@ -0,0 +1,196 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"versionary-api/pkg/token"
+)
+
+// Secret key names, stored in the Parameter Store.
+const (
+	EmailVerificationKey = "email-verification-key"
+	JWTSigningKeys       = "jwt-signing-keys"
+)
+
+// signingKeysRetained is the number of previous JWT signing keys retained after a rotation, so that
+// recently issued tokens remain verifiable until they expire.
+const signingKeysRetained = 2
+
+// signingKeysReloadInterval limits how often JWT signing keys are reloaded from the Parameter Store
+// when a token presents an unknown key ID (e.g. after a rotation by another instance).
+const signingKeysReloadInterval = time.Minute
+
+// signingKeysReloadedAt is the time (Unix nanoseconds) the JWT signing keys were last reloaded.
+var signingKeysReloadedAt atomic.Int64
+
+// keyParameterName returns the Parameter Store name for the specified secret key in the current environment.
+func (a *Application) keyParameterName(name string) string {
+	return "/versionary/" + a.Environment + "/" + name
+}
+
+// SecretKey returns the named secret key (e.g. for signing links), reading it from the Parameter Store.
+// If the key does not exist, a new random 256-bit key is generated and stored. Keys are stored hex-encoded,
+// so that operators may provision them in advance.
+func (a *Application) SecretKey(ctx context.Context, name string) ([]byte, error) {
+	p, err := a.ParameterStore.GetParameter(ctx, Parameter{Name: a.keyParameterName(name)})
+	if err == nil {
+		key, err := hex.DecodeString(p.Value)
+		if err != nil || len(key) == 0 {
+			return nil, fmt.Errorf("error reading secret key %s: invalid hex value", p.Name)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, ErrParameterNotFound) {
+		return nil, fmt.Errorf("error reading secret key %s: %w", p.Name, err)
+	}
+	// Generate and store a new key, unless another instance of the application stored one first
+	key := make([]byte, 32)
+	if _, err = rand.Read(key); err != nil {
+		return nil, fmt.Errorf("error generating secret key %s: %w", p.Name, err)
+	}
+	p.Value = hex.EncodeToString(key)
+	err = a.ParameterStore.CreateParameter(ctx, p)
+	if errors.Is(err, ErrParameterExists) {
+		if p, err = a.ParameterStore.RefreshParameter(ctx, Parameter{Name: p.Name}); err != nil {
+			return nil, fmt.Errorf("error reading secret key %s: %w", p.Name, err)
+		}
+		if key, err = hex.DecodeString(p.Value); err != nil || len(key) == 0 {
+			return nil, fmt.Errorf("error reading secret key %s: invalid hex value", p.Name)
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error storing secret key %s: %w", p.Name, err)
+	}
+	return key, nil
+}
+
+// SigningKeys returns the JWT signing keys, reading them from the Parameter Store. If no keys exist,
+// a new KeySet is generated and stored, using the configured JWT algorithm (EdDSA by default).
+func (a *Application) SigningKeys(ctx context.Context) (token.KeySet, error) {
+	return a.readSigningKeys(ctx, false)
+}
+
+// readSigningKeys reads the JWT signing keys, optionally bypassing the Parameter Store cache.
+func (a *Application) readSigningKeys(ctx context.Context, refresh bool) (token.KeySet, error) {
+	var ks token.KeySet
+	var p Parameter
+	var err error
+	if refresh {
+		p, err = a.ParameterStore.RefreshParameter(ctx, Parameter{Name: a.keyParameterName(JWTSigningKeys)})
+	} else {
+		p, err = a.ParameterStore.GetParameter(ctx, Parameter{Name: a.keyParameterName(JWTSigningKeys)})
+	}
+	if err == nil {
+		if err = json.Unmarshal([]byte(p.Value), &ks); err != nil || len(ks.Keys) == 0 {
+			return ks, fmt.Errorf("error reading signing keys %s: invalid key set", p.Name)
+		}
+		return ks, nil
+	}
+	if !errors.Is(err, ErrParameterNotFound) {
+		return ks, fmt.Errorf("error reading signing keys %s: %w", p.Name, err)
+	}
+	return a.createSigningKeys(ctx)
+}
+
+// createSigningKeys generates and stores the first JWT signing keys, using the configured JWT algorithm.
+// If another instance of the application stored its keys first, those are returned instead, so that
+// every instance signs with the same keys.
+func (a *Application) createSigningKeys(ctx context.Context) (token.KeySet, error) {
+	name := a.keyParameterName(JWTSigningKeys)
+	alg := a.JWTAlgorithm
+	if alg == "" {
+		alg = token.AlgEdDSA
+	}
+	ks, err := token.KeySet{}.Rotate(alg, signingKeysRetained)
+	if err != nil {
+		return ks, fmt.Errorf("error generating signing keys %s: %w", name, err)
+	}
+	b, err := json.Marshal(ks)
+	if err != nil {
+		return ks, fmt.Errorf("error encoding signing keys %s: %w", name, err)
+	}
+	err = a.ParameterStore.CreateParameter(ctx, Parameter{Name: name, Value: string(b)})
+	if errors.Is(err, ErrParameterExists) {
+		p, err := a.ParameterStore.RefreshParameter(ctx, Parameter{Name: name})
+		if err != nil {
+			return ks, fmt.Errorf("error reading signing keys %s: %w", name, err)
+		}
+		ks = token.KeySet{}
+		if err = json.Unmarshal([]byte(p.Value), &ks); err != nil || len(ks.Keys) == 0 {
+			return ks, fmt.Errorf("error reading signing keys %s: invalid key set", name)
+		}
+		return ks, nil
+	}
+	if err != nil {
+		return ks, fmt.Errorf("error storing signing keys %s: %w", name, err)
+	}
+	return ks, nil
+}
+
+// RotateSigningKeys generates a new current JWT signing key with the specified algorithm, retaining
+// the most recent previous keys for verification, and stores the updated KeySet.
+func (a *Application) RotateSigningKeys(ctx context.Context, alg string) (token.KeySet, error) {
+	name := a.keyParameterName(JWTSigningKeys)
+	var ks token.KeySet
+	p, err := a.ParameterStore.RefreshParameter(ctx, Parameter{Name: name})
+	if err == nil {
+		if err = json.Unmarshal([]byte(p.Value), &ks); err != nil {
+			return ks, fmt.Errorf("error reading signing keys %s: invalid key set", name)
+		}
+	} else if !errors.Is(err, ErrParameterNotFound) {
+		return ks, fmt.Errorf("error reading signing keys %s: %w", name, err)
+	}
+	ks, err = ks.Rotate(alg, signingKeysRetained)
+	if err != nil {
+		return ks, fmt.Errorf("error rotating signing keys %s: %w", name, err)
+	}
+	b, err := json.Marshal(ks)
+	if err != nil {
+		return ks, fmt.Errorf("error encoding signing keys %s: %w", name, err)
+	}
+	if err = a.ParameterStore.SetParameter(ctx, Parameter{Name: name, Value: string(b)}); err != nil {
+		return ks, fmt.Errorf("error storing signing keys %s: %w", name, err)
+	}
+	return ks, nil
+}
+
+// SignJWT returns a JWT containing the supplied Claims, signed with the current signing key.
+func (a *Application) SignJWT(ctx context.Context, c token.Claims) (string, error) {
+	ks, err := a.SigningKeys(ctx)
+	if err != nil {
+		return "", err
+	}
+	return ks.Sign(c)
+}
+
+// VerifyJWT verifies a JWT access token, returning its Claims. If the token was signed with an unknown key,
+// the signing keys are reloaded from the Parameter Store (at most once per reload interval) and verification
+// is retried, so that keys rotated by another instance of the application are recognized.
+func (a *Application) VerifyJWT(ctx context.Context, jwt string) (token.Claims, error) {
+	ks, err := a.SigningKeys(ctx)
+	if err != nil {
+		return token.Claims{}, err
+	}
+	c, err := ks.Verify(jwt, time.Now())
+	if !errors.Is(err, token.ErrUnknownKey) {
+		return c, err
+	}
+	last := signingKeysReloadedAt.Load()
+	now := time.Now().UnixNano()
+	if now-last < int64(signingKeysReloadInterval) || !signingKeysReloadedAt.CompareAndSwap(last, now) {
+		return c, err
+	}
+	ks, err = a.readSigningKeys(ctx, true)
+	if err != nil {
+		return token.Claims{}, err
+	}
+	return ks.Verify(jwt, time.Now())
+}
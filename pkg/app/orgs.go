@@ -0,0 +1,323 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/org"
+	"versionary-api/pkg/role"
+	"versionary-api/pkg/user"
+)
+
+// MemberPolicy determines what happens to the members of an Organization when it's deleted.
+type MemberPolicy string
+
+// RefuseMembers Policy refuses to delete an Organization that still has members.
+const RefuseMembers MemberPolicy = "refuse"
+
+// ReassignMembers Policy moves the members to another Organization. Organization-scoped roles are dropped,
+// so that administrators of the deleted Organization don't become administrators of the other one.
+const ReassignMembers MemberPolicy = "reassign"
+
+// DisableMembers Policy disables the members, and revokes their tokens.
+const DisableMembers MemberPolicy = "disable"
+
+// MemberPolicies is the complete list of valid member policies.
+var MemberPolicies = []MemberPolicy{RefuseMembers, ReassignMembers, DisableMembers}
+
+// IsValid returns true if the supplied MemberPolicy is recognized.
+func (p MemberPolicy) IsValid() bool {
+	for _, v := range MemberPolicies {
+		if p == v {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a string representation of the MemberPolicy.
+func (p MemberPolicy) String() string {
+	return string(p)
+}
+
+// ParseMemberPolicy returns a MemberPolicy from a string, defaulting to RefuseMembers if it's empty.
+func ParseMemberPolicy(s string) (MemberPolicy, error) {
+	if s == "" {
+		return RefuseMembers, nil
+	}
+	p := MemberPolicy(strings.ToLower(s))
+	if p.IsValid() {
+		return p, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidMemberPolicy, s)
+}
+
+// ErrInvalidMemberPolicy is returned when a member policy is not recognized.
+var ErrInvalidMemberPolicy = errors.New("invalid member policy")
+
+// ErrInvalidTargetOrg is returned when members can't be reassigned to the specified Organization.
+var ErrInvalidTargetOrg = errors.New("invalid target organization")
+
+// ErrOrgHasMembers is returned when refusing to delete an Organization that still has members.
+var ErrOrgHasMembers = errors.New("organization has members")
+
+// ErrOrgHasChildren is returned when refusing to delete an Organization that still has child Organizations.
+var ErrOrgHasChildren = errors.New("organization has child organizations")
+
+// Member actions, reported for each member of a deleted Organization.
+const (
+	MemberRetained   = "retained"   // refused: the member was not changed
+	MemberReassigned = "reassigned" // the member's primary Organization is now the target Organization
+	MemberDisabled   = "disabled"   // the member was disabled, and their tokens revoked
+	MemberRemoved    = "removed"    // the (non-primary) Membership was deleted; the User is otherwise unchanged
+	MemberFailed     = "failed"     // the member could not be updated
+)
+
+// OrgDeletionRequest specifies an Organization to delete, and what to do with its members.
+type OrgDeletionRequest struct {
+	OrgID       string       // Organization to delete
+	Policy      MemberPolicy // what to do with its members
+	TargetOrgID string       // Organization receiving the members (ReassignMembers only)
+	UserID      string       // who is deleting the Organization, if anyone (for the event log)
+	URI         string       // request URI, if any (for the event log)
+}
+
+// MemberResult reports what happened to a member of a deleted Organization.
+type MemberResult struct {
+	UserID       string   `json:"userId"`
+	Email        string   `json:"email,omitempty"`
+	Primary      bool     `json:"primary,omitempty"`
+	Action       string   `json:"action"`
+	OrgID        string   `json:"orgId,omitempty"`        // the member's new primary Organization, if reassigned
+	RemovedRoles []string `json:"removedRoles,omitempty"` // organization-scoped roles dropped when reassigned
+	Error        string   `json:"error,omitempty"`
+}
+
+// OrgDeletion reports the outcome of deleting an Organization, including what happened to each member.
+type OrgDeletion struct {
+	Organization       org.Organization `json:"organization"`
+	Policy             MemberPolicy     `json:"policy"`
+	TargetOrgID        string           `json:"targetOrgId,omitempty"`
+	TargetOrgName      string           `json:"targetOrgName,omitempty"`
+	Deleted            bool             `json:"deleted"`
+	Members            []MemberResult   `json:"members"`
+	RevokedInvitations int              `json:"revokedInvitations"`
+}
+
+// DeleteOrganization deletes an Organization, first handling its members according to the requested policy.
+// Each change is recorded in the event log. The Organization is not deleted if it has child Organizations
+// (returning ErrOrgHasChildren), if the policy refuses (returning ErrOrgHasMembers), or if any member could not
+// be updated; either way, the report describes each member.
+func (a *Application) DeleteOrganization(ctx context.Context, r OrgDeletionRequest) (OrgDeletion, error) {
+	d := OrgDeletion{Policy: r.Policy, Members: []MemberResult{}}
+	if !r.Policy.IsValid() {
+		return d, fmt.Errorf("%w: %s", ErrInvalidMemberPolicy, r.Policy)
+	}
+	o, err := a.OrgService.Read(ctx, r.OrgID)
+	if err != nil {
+		return d, fmt.Errorf("error reading organization %s: %w", r.OrgID, err)
+	}
+	d.Organization = o
+	children, err := a.OrgService.ReadChildren(ctx, o.ID)
+	if err != nil {
+		return d, fmt.Errorf("error reading children of organization %s: %w", o.ID, err)
+	}
+	if len(children) > 0 {
+		return d, fmt.Errorf("%w: organization %s has %d child organization(s)", ErrOrgHasChildren, o.ID, len(children))
+	}
+	var target org.Organization
+	if r.Policy == ReassignMembers {
+		if r.TargetOrgID == "" || r.TargetOrgID == o.ID {
+			return d, fmt.Errorf("%w: %q (reassigning members of organization %s)", ErrInvalidTargetOrg, r.TargetOrgID, o.ID)
+		}
+		if target, err = a.OrgService.Read(ctx, r.TargetOrgID); err != nil {
+			return d, fmt.Errorf("%w: %s: %w", ErrInvalidTargetOrg, r.TargetOrgID, err)
+		}
+		d.TargetOrgID, d.TargetOrgName = target.ID, target.Name
+	}
+	memberships, err := a.orgMembers(ctx, o.ID)
+	if err != nil {
+		return d, err
+	}
+	if r.Policy == RefuseMembers && len(memberships) > 0 {
+		for _, m := range memberships {
+			d.Members = append(d.Members, MemberResult{UserID: m.UserID, Email: m.Email, Primary: m.Primary, Action: MemberRetained})
+		}
+		return d, fmt.Errorf("%w: organization %s has %d member(s)", ErrOrgHasMembers, o.ID, len(memberships))
+	}
+
+	// Handle each member
+	roles, err := a.Roles(ctx)
+	if err != nil {
+		return d, fmt.Errorf("error reading roles: %w", err)
+	}
+	failed := 0
+	for _, m := range memberships {
+		result, err := a.handleMember(ctx, r, o, target, m, roles)
+		if err != nil {
+			result.Action = MemberFailed
+			result.Error = err.Error()
+			failed++
+		}
+		d.Members = append(d.Members, result)
+	}
+	if failed > 0 {
+		return d, fmt.Errorf("error handling %d member(s) of organization %s", failed, o.ID)
+	}
+
+	// Revoke open Invitations to the Organization
+	invitations, err := a.InvitationService.ReadAllInvitationsByOrgID(ctx, o.ID)
+	if err != nil {
+		return d, fmt.Errorf("error reading invitations to organization %s: %w", o.ID, err)
+	}
+	for _, i := range invitations {
+		if !i.IsOpen(time.Now()) {
+			continue
+		}
+		if _, err = a.InvitationService.Revoke(ctx, i); err != nil {
+			return d, fmt.Errorf("error revoking invitation %s: %w", i.ID, err)
+		}
+		d.RevokedInvitations++
+		a.logOrgDeletion(ctx, r, i.ID, i.Type(), fmt.Sprintf("revoked Invitation %s for %s to deleted Organization %s", i.ID, i.Email, o.ID))
+	}
+
+	// Delete the Organization
+	if _, err = a.OrgService.Delete(ctx, o.ID); err != nil {
+		return d, fmt.Errorf("error deleting organization %s: %w", o.ID, err)
+	}
+	d.Deleted = true
+	a.logOrgDeletion(ctx, r, o.ID, o.Type(), fmt.Sprintf("deleted Organization %s %s (%s %d members)", o.ID, o.Name, r.Policy, len(d.Members)))
+	return d, nil
+}
+
+// orgMembers returns the Memberships in an Organization. Users whose primary Organization it is, but who have
+// no Membership row yet (e.g. legacy Users that have not been synced), are included with a primary Membership
+// derived from the User.
+func (a *Application) orgMembers(ctx context.Context, orgID string) ([]user.Membership, error) {
+	memberships, err := a.UserService.Memberships.ReadAllMembershipsByOrgID(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading memberships in organization %s: %w", orgID, err)
+	}
+	userIDs, err := a.UserService.ReadAllUserIDsByPrimaryOrgID(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading users in organization %s: %w", orgID, err)
+	}
+	for _, id := range userIDs {
+		if slices.ContainsFunc(memberships, func(m user.Membership) bool { return m.UserID == id }) {
+			continue
+		}
+		u, err := a.UserService.Read(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("error reading user %s: %w", id, err)
+		}
+		if m, ok := u.PrimaryMembership(); ok && m.OrgID == orgID {
+			memberships = append(memberships, m)
+		}
+	}
+	return memberships, nil
+}
+
+// handleMember reassigns, disables, or removes a member of an Organization that's being deleted.
+func (a *Application) handleMember(ctx context.Context, r OrgDeletionRequest, o, target org.Organization, m user.Membership, roles []role.Role) (MemberResult, error) {
+	result := MemberResult{UserID: m.UserID, Email: m.Email, Primary: m.Primary}
+	// Members with a primary Organization elsewhere just lose their Membership
+	if !m.Primary {
+		if _, err := a.UserService.Memberships.Delete(ctx, m.ID); err != nil {
+			return result, fmt.Errorf("error deleting membership %s: %w", m.ID, err)
+		}
+		if err := a.clearTokenOrg(ctx, m.UserID, o.ID); err != nil {
+			return result, err
+		}
+		result.Action = MemberRemoved
+		a.logOrgDeletion(ctx, r, m.ID, m.Type(), fmt.Sprintf("removed User %s %s from deleted Organization %s", m.UserID, m.Email, o.ID))
+		return result, nil
+	}
+	u, err := a.UserService.Read(ctx, m.UserID)
+	if err != nil {
+		return result, fmt.Errorf("error reading user %s: %w", m.UserID, err)
+	}
+	switch r.Policy {
+	case ReassignMembers:
+		var kept []string
+		for _, name := range u.Roles {
+			if role.IsOrgScoped(name, roles) {
+				result.RemovedRoles = append(result.RemovedRoles, name)
+			} else {
+				kept = append(kept, name)
+			}
+		}
+		u.OrgID, u.OrgName, u.Roles = target.ID, target.Name, kept
+		if _, _, err = a.UserService.Update(ctx, u); err != nil {
+			return result, fmt.Errorf("error reassigning user %s: %w", u.ID, err)
+		}
+		if err = a.clearTokenOrg(ctx, u.ID, o.ID); err != nil {
+			return result, err
+		}
+		result.Action, result.OrgID = MemberReassigned, target.ID
+		a.logOrgDeletion(ctx, r, u.ID, u.Type(), fmt.Sprintf("reassigned User %s %s from deleted Organization %s to %s %s",
+			u.ID, u.Email, o.ID, target.ID, target.Name))
+	case DisableMembers:
+		u.OrgID, u.OrgName, u.Status = "", "", user.DISABLED
+		if _, _, err = a.UserService.Update(ctx, u); err != nil {
+			return result, fmt.Errorf("error disabling user %s: %w", u.ID, err)
+		}
+		if err = a.TokenService.DeleteAllTokensByUserID(ctx, u.ID); err != nil {
+			return result, fmt.Errorf("error revoking tokens of user %s: %w", u.ID, err)
+		}
+		if err = a.TokenService.DeleteAllRefreshTokensByUserID(ctx, u.ID); err != nil {
+			return result, fmt.Errorf("error revoking refresh tokens of user %s: %w", u.ID, err)
+		}
+		result.Action = MemberDisabled
+		a.logOrgDeletion(ctx, r, u.ID, u.Type(), fmt.Sprintf("disabled User %s %s of deleted Organization %s", u.ID, u.Email, o.ID))
+	}
+	return result, nil
+}
+
+// clearTokenOrg clears the active Organization of the User's Tokens and RefreshTokens that selected the
+// deleted Organization, so that they fall back to the User's primary Organization.
+func (a *Application) clearTokenOrg(ctx context.Context, userID, orgID string) error {
+	tokens, err := a.TokenService.ReadAllTokensByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("error reading tokens of user %s: %w", userID, err)
+	}
+	for _, t := range tokens {
+		if t.OrgID != orgID {
+			continue
+		}
+		t.OrgID = ""
+		if _, err = a.TokenService.Write(ctx, t); err != nil {
+			return fmt.Errorf("error updating token of user %s: %w", userID, err)
+		}
+	}
+	refreshTokens, err := a.TokenService.ReadAllRefreshTokensByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("error reading refresh tokens of user %s: %w", userID, err)
+	}
+	for _, rt := range refreshTokens {
+		if rt.OrgID != orgID {
+			continue
+		}
+		rt.OrgID = ""
+		if _, err = a.TokenService.WriteRefreshToken(ctx, rt); err != nil {
+			return fmt.Errorf("error updating refresh token of user %s: %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// logOrgDeletion records a change made while deleting an Organization. Logging is best-effort.
+func (a *Application) logOrgDeletion(ctx context.Context, r OrgDeletionRequest, entityID, entityType, message string) {
+	_, _, _ = a.EventService.Create(ctx, event.Event{
+		UserID:     r.UserID,
+		EntityID:   entityID,
+		EntityType: entityType,
+		LogLevel:   event.INFO,
+		Message:    message,
+		URI:        r.URI,
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
@@ -12,6 +14,12 @@ import (
 // ErrParameterNotFound is returned when a parameter is not found in the Parameter Store.
 var ErrParameterNotFound = errors.New("parameter store: parameter not found")
 
+// ErrParameterExists is returned when creating a parameter that already exists in the Parameter Store.
+var ErrParameterExists = errors.New("parameter store: parameter already exists")
+
+// cacheLock guards parameter caches, which are read and written by concurrent requests.
+var cacheLock sync.RWMutex
+
 // Parameter represents a simplified AWS parameter in the SSM Parameter Store.
 type Parameter struct {
 	// Name is the case-sensitive name of the parameter. The maximum usable length is 1011 characters.
@@ -39,7 +47,7 @@ func (ps ParameterStore) SetParameter(ctx context.Context, p Parameter) error {
 	if ps.Cache == nil {
 		ps.Cache = &map[string]Parameter{}
 	}
-	(*ps.Cache)[p.Name] = p
+	ps.cache(p)
 	// If the client is not set, the parameter store is in-memory only.
 	if ps.Client == nil {
 		return nil
@@ -70,7 +78,7 @@ func (ps ParameterStore) GetParameter(ctx context.Context, p Parameter) (Paramet
 		ps.Cache = &map[string]Parameter{}
 	}
 	// Try the cache first.
-	cached, ok := (*ps.Cache)[p.Name]
+	cached, ok := ps.cached(p.Name)
 	if ok {
 		return cached, nil
 	}
@@ -93,10 +101,59 @@ func (ps ParameterStore) GetParameter(ctx context.Context, p Parameter) (Paramet
 	}
 	p.Value = *result.Parameter.Value
 	// Cache the parameter.
-	(*ps.Cache)[p.Name] = p
+	ps.cache(p)
 	return p, nil
 }
 
+// CreateParameter creates the provided parameter, unless it already exists, in which case ErrParameterExists
+// is returned. When several instances of the application create the same parameter at once (e.g. a generated
+// key), only one of them succeeds, and the others may read the winning value.
+func (ps ParameterStore) CreateParameter(ctx context.Context, p Parameter) error {
+	if p.Name == "" {
+		return errors.New("parameter store: missing parameter name")
+	}
+	// If the client is not set, the parameter store is in-memory only.
+	if ps.Client == nil {
+		if ps.Cache == nil {
+			return nil
+		}
+		cacheLock.Lock()
+		defer cacheLock.Unlock()
+		if _, ok := (*ps.Cache)[p.Name]; ok {
+			return fmt.Errorf("%w: %s", ErrParameterExists, p.Name)
+		}
+		(*ps.Cache)[p.Name] = p
+		return nil
+	}
+	// If the client is set, the parameter store is backed by AWS SSM.
+	input := &ssm.PutParameterInput{
+		Name:      &p.Name,
+		Value:     &p.Value,
+		Type:      types.ParameterTypeSecureString,
+		Overwrite: aws.Bool(false),
+	}
+	_, err := ps.Client.PutParameter(ctx, input)
+	if err != nil {
+		var exists *types.ParameterAlreadyExists
+		if errors.As(err, &exists) {
+			return fmt.Errorf("%w: %s", ErrParameterExists, p.Name)
+		}
+		return fmt.Errorf("parameter store: create parameter %s: %w", p.Name, err)
+	}
+	ps.cache(p)
+	return nil
+}
+
+// RefreshParameter returns the provided parameter, bypassing the cache, so that changes made elsewhere
+// (e.g. by another instance of the application) are observed. The in-memory parameter store has no
+// other source, so its cached value is returned.
+func (ps ParameterStore) RefreshParameter(ctx context.Context, p Parameter) (Parameter, error) {
+	if ps.Client != nil {
+		ps.uncache(p.Name)
+	}
+	return ps.GetParameter(ctx, p)
+}
+
 // GetParameters returns the provided parameters, in order, with their values populated.
 func (ps ParameterStore) GetParameters(ctx context.Context, params []Parameter) ([]Parameter, error) {
 	if len(params) == 0 {
@@ -111,7 +168,7 @@ func (ps ParameterStore) GetParameters(ctx context.Context, params []Parameter)
 	// Try the cache first.
 	var missing []string
 	for _, p := range params {
-		c, ok := (*ps.Cache)[p.Name]
+		c, ok := ps.cached(p.Name)
 		if ok {
 			p.Value = c.Value
 		} else {
@@ -140,15 +197,15 @@ func (ps ParameterStore) GetParameters(ctx context.Context, params []Parameter)
 	}
 	// Cache the parameters.
 	for _, p := range result.Parameters {
-		(*ps.Cache)[*p.Name] = Parameter{
+		ps.cache(Parameter{
 			Name:  *p.Name,
 			Value: *p.Value,
-		}
+		})
 	}
 	// Update the parameter values.
 	missing = missing[:0]
 	for i, p := range params {
-		c, ok := (*ps.Cache)[p.Name]
+		c, ok := ps.cached(p.Name)
 		if ok {
 			params[i].Value = c.Value
 		} else {
@@ -167,9 +224,7 @@ func (ps ParameterStore) DeleteParameter(ctx context.Context, p Parameter) error
 		return errors.New("parameter store: missing parameter name")
 	}
 	// Delete from the cache.
-	if ps.Cache != nil {
-		delete(*ps.Cache, p.Name)
-	}
+	ps.uncache(p.Name)
 	// If the client is not set, the parameter store is in-memory only.
 	if ps.Client == nil {
 		return nil
@@ -185,6 +240,35 @@ func (ps ParameterStore) DeleteParameter(ctx context.Context, p Parameter) error
 	return nil
 }
 
+// cached returns the named parameter from the cache, if it's there.
+func (ps ParameterStore) cached(name string) (Parameter, bool) {
+	cacheLock.RLock()
+	defer cacheLock.RUnlock()
+	if ps.Cache == nil {
+		return Parameter{}, false
+	}
+	p, ok := (*ps.Cache)[name]
+	return p, ok
+}
+
+// cache adds the parameter to the cache.
+func (ps ParameterStore) cache(p Parameter) {
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if ps.Cache != nil {
+		(*ps.Cache)[p.Name] = p
+	}
+}
+
+// uncache removes the named parameter from the cache.
+func (ps ParameterStore) uncache(name string) {
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if ps.Cache != nil {
+		delete(*ps.Cache, name)
+	}
+}
+
 // NewParameterStore returns a new caching ParameterStore, backed by AWS SSM Parameter Store.
 func NewParameterStore(cfg aws.Config) ParameterStore {
 	return ParameterStore{
@@ -5,6 +5,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/voxtechnica/tuid-go"
+	"sync"
 	"testing"
 )
 
@@ -93,3 +94,35 @@ func TestParameterStoreCRUD(t *testing.T) {
 		expect.ErrorIs(err, ErrParameterNotFound, "failed to get deleted parameter 2")
 	}
 }
+
+// TestParameterStoreMock tests the in-memory ParameterStore, including concurrent use.
+func TestParameterStoreMock(t *testing.T) {
+	expect := assert.New(t)
+	ctx := context.Background()
+	ps := NewParameterStoreMock()
+	p := Parameter{Name: "/versionary/test/" + tuid.NewID().String(), Value: "first"}
+
+	// Only the first create succeeds
+	expect.NoError(ps.CreateParameter(ctx, p))
+	expect.ErrorIs(ps.CreateParameter(ctx, Parameter{Name: p.Name, Value: "second"}), ErrParameterExists)
+	check, err := ps.GetParameter(ctx, Parameter{Name: p.Name})
+	if expect.NoError(err) {
+		expect.Equal("first", check.Value)
+	}
+
+	// The cache is safe for concurrent use
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = ps.RefreshParameter(ctx, Parameter{Name: p.Name})
+			_ = ps.SetParameter(ctx, p)
+			_, _ = ps.GetParameter(ctx, Parameter{Name: p.Name})
+		}()
+	}
+	wg.Wait()
+	expect.NoError(ps.DeleteParameter(ctx, p))
+	_, err = ps.GetParameter(ctx, Parameter{Name: p.Name})
+	expect.ErrorIs(err, ErrParameterNotFound)
+}
@@ -0,0 +1,302 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+
+	"versionary-api/pkg/content"
+	"versionary-api/pkg/device"
+	"versionary-api/pkg/email"
+	"versionary-api/pkg/event"
+	"versionary-api/pkg/image"
+	"versionary-api/pkg/job"
+	"versionary-api/pkg/org"
+	"versionary-api/pkg/ref"
+	"versionary-api/pkg/user"
+)
+
+// RestorableTypes is the complete list of entity types whose prior versions may be restored.
+var RestorableTypes = []string{"Content", "Device", "Email", "Image", "Organization", "User"}
+
+// ErrNotRestorable is returned when restoring prior versions of an entity type is not supported.
+var ErrNotRestorable = errors.New("entity type is not restorable")
+
+// ErrCurrentVersion is returned when the version to restore is already the current version.
+var ErrCurrentVersion = errors.New("version is already current")
+
+// ErrRestoreUnauthorized is returned when the restorer is not authorized to restore the version
+// (e.g. a User version with roles that they may not grant).
+var ErrRestoreUnauthorized = errors.New("not authorized to restore the version")
+
+// ParseRestorableType returns the restorable entity type matching a string, ignoring case (e.g. "content").
+func ParseRestorableType(s string) (string, error) {
+	for _, t := range RestorableTypes {
+		if strings.EqualFold(s, t) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrNotRestorable, s)
+}
+
+// RestoreRequest specifies a prior version of an entity to restore, who is restoring it, and why.
+type RestoreRequest struct {
+	EntityType string // e.g. Content (see RestorableTypes)
+	ID         string // entity ID
+	VersionID  string // prior version to restore
+	Comment    string // reason for restoring the version (optional)
+	UserID     string // who is restoring the version, if anyone (for the editor and the event log)
+	UserName   string // who is restoring the version, if anyone (for the editor)
+	URI        string // request URI, if any (for the event log)
+	// AuthorizeUser checks a User version before it's restored, if set, returning an error wrapping
+	// ErrRestoreUnauthorized if the restorer may not restore it (e.g. its roles or Organization).
+	AuthorizeUser func(version, current user.User) error
+}
+
+// Restoration reports a restored entity version.
+type Restoration struct {
+	EntityType     string `json:"entityType"`
+	ID             string `json:"id"`
+	FromVersionID  string `json:"fromVersionId"`  // the prior version that was restored
+	PriorVersionID string `json:"priorVersionId"` // the version that was current before restoring
+	VersionID      string `json:"versionId"`      // the new version
+	Comment        string `json:"comment"`
+	JobID          string `json:"jobId,omitempty"` // a Job started to finish the restoration, if any
+	Entity         any    `json:"entity"`
+}
+
+// Restore writes a prior version of an entity as a new version, with a comment recording where it came from
+// (in the event log, and in the Content, which has a comment). Fields that the version must not roll back are
+// carried forward from the current version:
+//   - Content: the Status changes only by the publishing workflow; a restored published version is a new draft
+//   - Device: the times it was last seen and expires
+//   - Email: the Status, so that restoring a version never sends (or re-sends) the message
+//   - Image: the file and its analysis (size, hash, dimensions, etc.), since only the current file is stored
+//   - Organization: the email domains, which change only by verification; a restored name is propagated
+//   - User: the credentials (password hash, reset token, TOTP, and recovery codes) and the verified email
+//     address; the Organization name is refreshed, and a restored DISABLED User's tokens are revoked.
+//     Restored roles and Organization are checked with RestoreRequest.AuthorizeUser, if it's set.
+//
+// It returns ErrNotRestorable for an unsupported entity type, v.ErrNotFound if the entity or version doesn't
+// exist, and ErrCurrentVersion if the version is already current. Validation problems are returned, too.
+func (a *Application) Restore(ctx context.Context, r RestoreRequest) (Restoration, []string, error) {
+	entityType, err := ParseRestorableType(r.EntityType)
+	if err != nil {
+		return Restoration{}, nil, err
+	}
+	rs := Restoration{
+		EntityType:    entityType,
+		ID:            r.ID,
+		FromVersionID: r.VersionID,
+		Comment:       "restored from version " + r.VersionID,
+	}
+	if r.Comment != "" {
+		rs.Comment += ": " + r.Comment
+	}
+	var problems []string
+	switch entityType {
+	case "Content":
+		problems, err = restoreVersion(ctx, &rs, a.ContentService.Read, a.ContentService.ReadVersion,
+			func(version, current content.Content) (content.Content, []string, error) {
+				version.Status = current.Status.Edited()
+				version.PublishedAt = time.Time{}
+				version.EditorID = r.UserID
+				version.EditorName = r.UserName
+				version.Comment = rs.Comment
+				return a.ContentService.Update(ctx, version)
+			})
+	case "Device":
+		problems, err = restoreVersion(ctx, &rs, a.DeviceService.Read, a.DeviceService.ReadVersion,
+			func(version, current device.Device) (device.Device, []string, error) {
+				t := tuid.NewID()
+				version.VersionID = t.String()
+				version.UpdatedAt, _ = t.Time()
+				version.LastSeenAt = current.LastSeenAt
+				version.ExpiresAt = current.ExpiresAt
+				if problems := version.Validate(); len(problems) > 0 {
+					return version, problems, fmt.Errorf("invalid field(s): %s", strings.Join(problems, ", "))
+				}
+				// Write (not Update) the Device so that historical Devices by Date are preserved.
+				d, err := a.DeviceService.Write(ctx, version)
+				return d, nil, err
+			})
+	case "Email":
+		problems, err = restoreVersion(ctx, &rs, a.EmailService.Read, a.EmailService.ReadVersion,
+			func(version, current email.Email) (email.Email, []string, error) {
+				version.Status = current.Status
+				return a.EmailService.Update(ctx, version)
+			})
+	case "Image":
+		problems, err = restoreVersion(ctx, &rs, a.ImageService.Read, a.ImageService.ReadVersion,
+			func(version, current image.Image) (image.Image, []string, error) {
+				version.MediaType = current.MediaType
+				version.FileName = current.FileName
+				version.FileSize = current.FileSize
+				version.MD5Hash = current.MD5Hash
+				version.PHash = current.PHash
+				version.Width = current.Width
+				version.Height = current.Height
+				version.AspectRatio = current.AspectRatio
+				version.Status = current.Status
+				return a.ImageService.Update(ctx, version)
+			})
+	case "Organization":
+		var prior org.Organization
+		problems, err = restoreVersion(ctx, &rs, a.OrgService.Read, a.OrgService.ReadVersion,
+			func(version, current org.Organization) (org.Organization, []string, error) {
+				prior = current
+				version.Domains = current.Domains
+				version.DomainChallenges = current.DomainChallenges
+				return a.OrgService.Update(ctx, version)
+			})
+		if o, ok := rs.Entity.(org.Organization); ok && err == nil && o.Name != prior.Name {
+			rs.JobID = a.startRestoredOrgRename(ctx, r, o, prior.Name)
+		}
+	case "User":
+		var prior user.User
+		problems, err = restoreVersion(ctx, &rs, a.UserService.Read, a.UserService.ReadVersion,
+			func(version, current user.User) (user.User, []string, error) {
+				prior = current
+				version = version.RestoreScrubbed(current)
+				version.Password = ""
+				version.VerifiedEmail = current.VerifiedEmail
+				if r.AuthorizeUser != nil {
+					if err := r.AuthorizeUser(version, current); err != nil {
+						return version, nil, err
+					}
+				}
+				version.OrgName = ""
+				if version.OrgID != "" {
+					o, err := a.OrgService.Read(ctx, version.OrgID)
+					if err != nil && errors.Is(err, v.ErrNotFound) {
+						problem := "OrgID " + version.OrgID + " not found"
+						return version, []string{problem}, fmt.Errorf("invalid field(s): %s", problem)
+					}
+					if err != nil {
+						return version, nil, fmt.Errorf("error reading organization %s: %w", version.OrgID, err)
+					}
+					version.OrgName = o.Name
+				}
+				return a.UserService.Update(ctx, version)
+			})
+		if u, ok := rs.Entity.(user.User); ok && err == nil && u.Status == user.DISABLED && prior.Status != user.DISABLED {
+			a.revokeRestoredUserTokens(ctx, r, u)
+		}
+	}
+	if err != nil {
+		return rs, problems, err
+	}
+	message := fmt.Sprintf("restored %s %s version %s as version %s", rs.EntityType, rs.ID, rs.FromVersionID, rs.VersionID)
+	if r.Comment != "" {
+		message += ": " + r.Comment
+	}
+	_, _, _ = a.EventService.Create(ctx, event.Event{
+		UserID:     r.UserID,
+		EntityID:   rs.ID,
+		EntityType: rs.EntityType,
+		OtherIDs:   []string{rs.FromVersionID, rs.PriorVersionID, rs.VersionID},
+		LogLevel:   event.INFO,
+		Message:    message,
+		URI:        r.URI,
+	})
+	return rs, problems, nil
+}
+
+// versioned is an entity with a reference ID, identifying its version.
+type versioned interface {
+	RefID() ref.RefID
+}
+
+// restoreVersion reads the current and prior versions of an entity, and saves the prior version (as prepared
+// by the update function) as a new version, recording the versions in the Restoration.
+func restoreVersion[T versioned](
+	ctx context.Context,
+	rs *Restoration,
+	read func(context.Context, string) (T, error),
+	readVersion func(context.Context, string, string) (T, error),
+	update func(version, current T) (T, []string, error),
+) ([]string, error) {
+	current, err := read(ctx, rs.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s %s: %w", rs.EntityType, rs.ID, err)
+	}
+	rs.PriorVersionID = current.RefID().VersionID
+	if rs.PriorVersionID == rs.FromVersionID {
+		return nil, fmt.Errorf("error restoring %s %s: %w: %s", rs.EntityType, rs.ID, ErrCurrentVersion, rs.FromVersionID)
+	}
+	version, err := readVersion(ctx, rs.ID, rs.FromVersionID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s %s version %s: %w", rs.EntityType, rs.ID, rs.FromVersionID, err)
+	}
+	restored, problems, err := update(version, current)
+	if err != nil {
+		return problems, fmt.Errorf("error restoring %s %s version %s: %w", rs.EntityType, rs.ID, rs.FromVersionID, err)
+	}
+	rs.VersionID = restored.RefID().VersionID
+	rs.Entity = restored
+	return problems, nil
+}
+
+// startRestoredOrgRename propagates a restored Organization name to its members, Memberships, and Invitations,
+// in a Job. The Job ID is returned, or an empty string if the Job could not be created (which is logged).
+func (a *Application) startRestoredOrgRename(ctx context.Context, r RestoreRequest, o org.Organization, priorName string) string {
+	j, _, err := a.JobService.Create(ctx, job.Job{
+		Kind:       job.OrgRename,
+		UserID:     r.UserID,
+		EntityType: o.Type(),
+		EntityID:   o.ID,
+		Message:    fmt.Sprintf("rename Organization %s from %q to %q", o.ID, priorName, o.Name),
+	})
+	if err != nil {
+		_, _, _ = a.EventService.Create(ctx, event.Event{
+			UserID:     r.UserID,
+			EntityID:   o.ID,
+			EntityType: o.Type(),
+			LogLevel:   event.ERROR,
+			Message:    fmt.Errorf("create rename job for organization %s: %w", o.ID, err).Error(),
+			URI:        r.URI,
+			Err:        err,
+		})
+		return ""
+	}
+	a.RunJob(func(ctx context.Context) {
+		j := a.SyncOrgName(ctx, j, o)
+		logLevel := event.INFO
+		if j.Status == job.FAILED {
+			logLevel = event.ERROR
+		}
+		_, _, _ = a.EventService.Create(ctx, event.Event{
+			UserID:     r.UserID,
+			EntityID:   j.ID,
+			EntityType: j.Type(),
+			OtherIDs:   []string{o.ID},
+			LogLevel:   logLevel,
+			Message:    j.String(),
+			URI:        r.URI,
+		})
+	})
+	return j.ID
+}
+
+// revokeRestoredUserTokens signs out a User who was disabled by restoring a prior version.
+func (a *Application) revokeRestoredUserTokens(ctx context.Context, r RestoreRequest, u user.User) {
+	logLevel, message := event.INFO, fmt.Sprintf("user disabled: revoked tokens for User %s %s", u.ID, u.Email)
+	err := a.TokenService.DeleteAllTokensByUserID(ctx, u.ID)
+	if err != nil {
+		logLevel, message = event.ERROR, fmt.Errorf("user disabled: delete tokens for user %s %s: %w", u.ID, u.Email, err).Error()
+	}
+	_, _, _ = a.EventService.Create(ctx, event.Event{
+		UserID:     r.UserID,
+		EntityID:   u.ID,
+		EntityType: u.Type(),
+		LogLevel:   logLevel,
+		Message:    message,
+		URI:        r.URI,
+		Err:        err,
+	})
+}
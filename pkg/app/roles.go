@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"versionary-api/pkg/apikey"
+	"versionary-api/pkg/role"
+	"versionary-api/pkg/user"
+)
+
+// rolesReloadInterval limits how often the stored Roles are reloaded from the Role table, so that
+// permissions may be checked on every request without a database read. Roles changed by another
+// instance of the application take effect within this interval.
+const rolesReloadInterval = time.Minute
+
+// cachedRoles is a snapshot of the stored Roles, and when they were loaded.
+type cachedRoles struct {
+	roles    []role.Role
+	loadedAt time.Time
+}
+
+// rolesCache holds the most recently loaded Roles. It is nil until the Roles are first loaded,
+// or after they have been invalidated.
+var rolesCache atomic.Pointer[cachedRoles]
+
+// Roles returns the stored Roles, which refine the built-in role.DefaultRoles. They're cached briefly.
+func (a *Application) Roles(ctx context.Context) ([]role.Role, error) {
+	if c := rolesCache.Load(); c != nil && time.Since(c.loadedAt) < rolesReloadInterval {
+		return c.roles, nil
+	}
+	roles, err := a.RoleService.ReadAllRoles(ctx)
+	if err != nil {
+		return roles, err
+	}
+	rolesCache.Store(&cachedRoles{roles: roles, loadedAt: time.Now()})
+	return roles, nil
+}
+
+// InvalidateRoles discards the cached Roles (e.g. after a Role is created, updated, or deleted),
+// so that the next permission check reloads them.
+func (a *Application) InvalidateRoles() {
+	rolesCache.Store(nil)
+}
+
+// Permissions returns the effective Permissions of the User, granted by their Roles.
+func (a *Application) Permissions(ctx context.Context, u user.User) (role.Permissions, error) {
+	roles, err := a.Roles(ctx)
+	if err != nil {
+		return role.NewPermissions(u, nil), err
+	}
+	return a.withSubOrgs(ctx, role.NewPermissions(u, roles))
+}
+
+// MembershipPermissions returns the effective Permissions of the User, acting within the Organization of
+// the supplied Membership.
+func (a *Application) MembershipPermissions(ctx context.Context, u user.User, m user.Membership) (role.Permissions, error) {
+	roles, err := a.Roles(ctx)
+	if err != nil {
+		return role.NewMembershipPermissions(u, m, nil), err
+	}
+	return a.withSubOrgs(ctx, role.NewMembershipPermissions(u, m, roles))
+}
+
+// APIKeyPermissions returns the effective Permissions of a service account acting with the supplied APIKey:
+// the permissions granted by the Roles of the service account, limited to those granted by the APIKey scopes.
+func (a *Application) APIKeyPermissions(ctx context.Context, u user.User, k apikey.APIKey) (role.Permissions, error) {
+	p, err := a.Permissions(ctx, u)
+	return p.Limit(func(perm string) bool { return k.HasScope(apikey.PermissionScope(perm)) }), err
+}
+
+// withSubOrgs extends org-scoped Permissions to the descendants of the User's Organization,
+// if OrgScopeDescendants is enabled.
+func (a *Application) withSubOrgs(ctx context.Context, p role.Permissions) (role.Permissions, error) {
+	if !a.OrgScopeDescendants || p.OrgID == "" || len(p.Org) == 0 {
+		return p, nil
+	}
+	ids, err := a.OrgService.ReadDescendantIDs(ctx, p.OrgID)
+	if err != nil {
+		return p, err
+	}
+	if len(ids) > 0 {
+		p.SubOrgIDs = ids
+	}
+	return p, nil
+}
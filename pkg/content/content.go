@@ -9,6 +9,9 @@ import (
 	"github.com/voxtechnica/versionary"
 )
 
+// DateLayout is the format of the dates used to partition the Content publication schedule.
+const DateLayout = "2006-01-02"
+
 // Content is a piece of content of a specified type (e.g. book, chapter, etc.)
 type Content struct {
 	Type         Type      `json:"type"`
@@ -16,6 +19,12 @@ type Content struct {
 	CreatedAt    time.Time `json:"createdAt"`
 	VersionID    string    `json:"versionId"`
 	UpdatedAt    time.Time `json:"updatedAt"`
+	Slug         string    `json:"slug,omitempty"`        // unique per Type; generated from the title if empty
+	SlugAliases  []string  `json:"slugAliases,omitempty"` // prior slugs, redirected to the current slug
+	Status       Status    `json:"status,omitempty"`
+	PublishAt    time.Time `json:"publishAt,omitempty"`   // embargo: not publicly visible before this time
+	UnpublishAt  time.Time `json:"unpublishAt,omitempty"` // expiry: not publicly visible after this time
+	PublishedAt  time.Time `json:"publishedAt,omitempty"` // when the version went live, if it has
 	EditorID     string    `json:"editorId,omitempty"`
 	EditorName   string    `json:"editorName,omitempty"`
 	Comment      string    `json:"comment,omitempty"`
@@ -46,6 +55,66 @@ func (c Content) Title() string {
 	return t + " " + c.ID
 }
 
+// CurrentStatus returns the Status of the Content version. Versions saved before the publishing
+// workflow was introduced have no Status, and were publicly visible, so they're considered PUBLISHED.
+func (c Content) CurrentStatus() Status {
+	if c.Status == "" {
+		return PUBLISHED
+	}
+	return c.Status
+}
+
+// IsPublished returns true if the Content version is publicly visible.
+func (c Content) IsPublished() bool {
+	return c.CurrentStatus() == PUBLISHED
+}
+
+// IsEmbargoed returns true if the Content version is scheduled to be published after the specified time.
+func (c Content) IsEmbargoed(at time.Time) bool {
+	return !c.PublishAt.IsZero() && at.Before(c.PublishAt)
+}
+
+// IsExpired returns true if the Content version was scheduled to be unpublished by the specified time.
+func (c Content) IsExpired(at time.Time) bool {
+	return !c.UnpublishAt.IsZero() && !at.Before(c.UnpublishAt)
+}
+
+// IsVisibleAt returns true if the Content version is publicly visible at the specified time:
+// it's published, its embargo (if any) has lifted, and it hasn't expired.
+func (c Content) IsVisibleAt(at time.Time) bool {
+	return c.IsPublished() && !c.IsEmbargoed(at) && !c.IsExpired(at)
+}
+
+// IsPending returns true if the Content version is published, but waiting for its embargo to lift.
+func (c Content) IsPending() bool {
+	return c.IsPublished() && !c.PublishAt.IsZero() && c.PublishedAt.IsZero()
+}
+
+// IsDue returns true if the published Content version has a scheduled change that is due at the specified
+// time: either its embargo has lifted, but it hasn't been marked as live, or it has expired.
+func (c Content) IsDue(at time.Time) bool {
+	return (c.IsPending() && !c.IsEmbargoed(at)) || (c.IsPublished() && c.IsExpired(at))
+}
+
+// ScheduleDates returns the dates (UTC, YYYY-MM-DD) of the scheduled changes of a published Content version:
+// the pending publication date, and the expiry date.
+func (c Content) ScheduleDates() []string {
+	if !c.IsPublished() {
+		return nil
+	}
+	var dates []string
+	if c.IsPending() {
+		dates = append(dates, c.PublishAt.UTC().Format(DateLayout))
+	}
+	if !c.UnpublishAt.IsZero() {
+		d := c.UnpublishAt.UTC().Format(DateLayout)
+		if len(dates) == 0 || dates[0] != d {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
 // AuthorNames returns a list of the names of the authors of the Content.
 func (c Content) AuthorNames() []string {
 	names := make([]string, 0, len(c.Authors))
@@ -93,6 +162,15 @@ func (c Content) Validate() []string {
 	if c.UpdatedAt.IsZero() {
 		problems = append(problems, "UpdatedAt is missing")
 	}
+	if c.Slug != "" && !IsValidSlug(c.Slug) {
+		problems = append(problems, "Slug must contain only lower-case letters and digits, separated by hyphens")
+	}
+	if c.Status != "" && !c.Status.IsValid() {
+		problems = append(problems, "Status is invalid")
+	}
+	if !c.PublishAt.IsZero() && !c.UnpublishAt.IsZero() && !c.UnpublishAt.After(c.PublishAt) {
+		problems = append(problems, "UnpublishAt must be after PublishAt")
+	}
 	if c.EditorID != "" && !tuid.IsValid(tuid.TUID(c.VersionID)) {
 		problems = append(problems, "EditorID is invalid")
 	}
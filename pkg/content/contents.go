@@ -2,8 +2,12 @@ package content
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"strings"
+	"sync/atomic"
+	"time"
 	"versionary-api/pkg/util"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -67,6 +71,118 @@ var rowContentTitlesTag = v.TableRow[Content]{
 	TextValue:     func(c Content) string { return c.Title() },
 }
 
+// rowContentTitlesStatus is a TableRow definition for searching/browsing Content titles by Status.
+var rowContentTitlesStatus = v.TableRow[Content]{
+	RowName:      "content_titles_status",
+	PartKeyName:  "status",
+	PartKeyValue: func(c Content) string { return c.CurrentStatus().String() },
+	SortKeyName:  "id",
+	SortKeyValue: func(c Content) string { return c.ID },
+	TextValue:    func(c Content) string { return c.Title() },
+}
+
+// rowContentsSchedule is a TableRow definition for finding published Content with scheduled changes, by date.
+// Each date partition holds the Content whose embargo lifts, or which expires, on that date (UTC).
+var rowContentsSchedule = v.TableRow[Content]{
+	RowName:       "contents_schedule",
+	PartKeyName:   "date",
+	PartKeyValues: func(c Content) []string { return c.ScheduleDates() },
+	SortKeyName:   "id",
+	SortKeyValue:  func(c Content) string { return c.ID },
+	JsonValue:     func(c Content) []byte { return c.CompressedJSON() },
+}
+
+// rowContentSlugs is a TableRow definition for finding Content by slug. Each partition (e.g. ARTICLE/my-title)
+// holds the Content that has the slug, or had it as a prior slug (an alias), keeping slugs unique per Type.
+// The text value is the current slug, so that an alias can be redirected without reading the Content.
+var rowContentSlugs = v.TableRow[Content]{
+	RowName:       "content_slugs",
+	PartKeyName:   "type_slug",
+	PartKeyValues: func(c Content) []string { return c.SlugKeys() },
+	SortKeyName:   "id",
+	SortKeyValue:  func(c Content) string { return c.ID },
+	TextValue:     func(c Content) string { return c.Slug },
+}
+
+// rowContentTerms is a TableRow definition for searching Content: an inverted index, with a partition for each
+// search term (word) holding the Content that contains it. See Content.SearchTerms.
+var rowContentTerms = v.TableRow[Content]{
+	RowName:       "content_terms",
+	PartKeyName:   "term",
+	PartKeyValues: func(c Content) []string { return c.SearchTerms() },
+	SortKeyName:   "id",
+	SortKeyValue:  func(c Content) string { return c.ID },
+	TextValue:     func(c Content) string { return c.Title() },
+}
+
+//==============================================================================
+// Published Content Table
+//==============================================================================
+
+// rowPublishedContents is a TableRow definition for the publicly visible version of each Content. It's kept in
+// the Content table, alongside the latest versions, so that public searches don't match unpublished drafts.
+var rowPublishedContents = v.TableRow[Content]{
+	RowName:      "contents_published",
+	PartKeyName:  "id",
+	PartKeyValue: func(c Content) string { return c.ID },
+	PartKeyLabel: func(c Content) string { return c.Title() },
+	SortKeyName:  "version_id",
+	SortKeyValue: func(c Content) string { return c.VersionID },
+	JsonValue:    func(c Content) []byte { return c.CompressedJSON() },
+}
+
+// rowContentPublishedType is a TableRow definition for filtering published Content by Type.
+var rowContentPublishedType = v.TableRow[Content]{
+	RowName:      "content_published_type",
+	PartKeyName:  "type",
+	PartKeyValue: func(c Content) string { return c.Type.String() },
+	SortKeyName:  "id",
+	SortKeyValue: func(c Content) string { return c.ID },
+}
+
+// rowContentPublishedAuthor is a TableRow definition for filtering published Content by Author.
+var rowContentPublishedAuthor = v.TableRow[Content]{
+	RowName:       "content_published_author",
+	PartKeyName:   "author",
+	PartKeyValues: func(c Content) []string { return c.AuthorNames() },
+	SortKeyName:   "id",
+	SortKeyValue:  func(c Content) string { return c.ID },
+}
+
+// rowContentPublishedTag is a TableRow definition for filtering published Content by Tag.
+var rowContentPublishedTag = v.TableRow[Content]{
+	RowName:       "content_published_tag",
+	PartKeyName:   "tag",
+	PartKeyValues: func(c Content) []string { return c.Tags },
+	SortKeyName:   "id",
+	SortKeyValue:  func(c Content) string { return c.ID },
+}
+
+// rowContentPublishedTerms is a TableRow definition for searching published Content: an inverted index of the
+// publicly visible versions. See rowContentTerms.
+var rowContentPublishedTerms = v.TableRow[Content]{
+	RowName:       "content_published_terms",
+	PartKeyName:   "term",
+	PartKeyValues: func(c Content) []string { return c.SearchTerms() },
+	SortKeyName:   "id",
+	SortKeyValue:  func(c Content) string { return c.ID },
+	TextValue:     func(c Content) string { return c.Title() },
+}
+
+// NewPublishedTable instantiates a view of the DynamoDB Content table holding the publicly visible version
+// of each Content, and the indexes used to search them. It's maintained by the Content Service.
+func NewPublishedTable(dbClient *dynamodb.Client, env string) v.Table[Content] {
+	table := NewTable(dbClient, env)
+	table.EntityRow = rowPublishedContents
+	table.IndexRows = map[string]v.TableRow[Content]{
+		rowContentPublishedType.RowName:   rowContentPublishedType,
+		rowContentPublishedAuthor.RowName: rowContentPublishedAuthor,
+		rowContentPublishedTag.RowName:    rowContentPublishedTag,
+		rowContentPublishedTerms.RowName:  rowContentPublishedTerms,
+	}
+	return table
+}
+
 // NewTable instantiates a new DynamoDB Content table.
 func NewTable(dbClient *dynamodb.Client, env string) v.Table[Content] {
 	if env == "" {
@@ -83,6 +199,10 @@ func NewTable(dbClient *dynamodb.Client, env string) v.Table[Content] {
 			rowContentTitlesAuthor.RowName: rowContentTitlesAuthor,
 			rowContentTitlesEditor.RowName: rowContentTitlesEditor,
 			rowContentTitlesTag.RowName:    rowContentTitlesTag,
+			rowContentTitlesStatus.RowName: rowContentTitlesStatus,
+			rowContentsSchedule.RowName:    rowContentsSchedule,
+			rowContentSlugs.RowName:        rowContentSlugs,
+			rowContentTerms.RowName:        rowContentTerms,
 		},
 	}
 }
@@ -100,6 +220,8 @@ func NewMemTable(table v.Table[Content]) v.MemTable[Content] {
 type Service struct {
 	EntityType string
 	Table      v.TableReadWriter[Content]
+	Published  v.TableReadWriter[Content] // the publicly visible version of each Content, for searching
+	counts     *documentCounts
 }
 
 // NewService creates a new Content service backed by a Versionary Table for the specified environment.
@@ -108,6 +230,8 @@ func NewService(dbClient *dynamodb.Client, env string) Service {
 	return Service{
 		EntityType: table.EntityType,
 		Table:      table,
+		Published:  NewPublishedTable(dbClient, env),
+		counts:     &documentCounts{},
 	}
 }
 
@@ -117,6 +241,8 @@ func NewMockService(env string) Service {
 	return Service{
 		EntityType: table.EntityType,
 		Table:      table,
+		Published:  NewMemTable(NewPublishedTable(nil, env)),
+		counts:     &documentCounts{},
 	}
 }
 
@@ -133,6 +259,43 @@ func (s Service) filterTitles(ctx context.Context, row v.TableRow[Content], key
 	return s.Table.FilterTextValues(ctx, row, key, filter)
 }
 
+// assignSlug gives the Content a slug that is unique among the Content of its Type. An empty slug is carried
+// forward from the prior version, if any, or generated from the title, with a numeric suffix (e.g. my-title-2)
+// if it's already in use. When the slug changes, the prior slug is kept as an alias, so that existing links
+// can be redirected. ErrDuplicateSlug is returned if a supplied slug is in use by other Content.
+func (s Service) assignSlug(ctx context.Context, c Content, prior Content) (Content, error) {
+	generate := c.Slug == "" && prior.Slug == ""
+	base := c.Slug
+	switch {
+	case generate:
+		base = Slugify(c.Body.Title)
+		if base == "" {
+			base = strings.ToLower(c.Type.String())
+		}
+		c.Slug = base
+	case c.Slug == "":
+		c.Slug = prior.Slug
+	}
+	c.SlugAliases = v.Filter(prior.SlugAliases, func(alias string) bool { return alias != c.Slug })
+	if prior.Slug != "" && prior.Slug != c.Slug {
+		c.SlugAliases = append(c.SlugAliases, prior.Slug)
+	}
+	for n := 2; ; n++ {
+		owners, err := s.Table.ReadAllTextValues(ctx, rowContentSlugs, SlugKey(c.Type, c.Slug), false)
+		if err != nil {
+			return c, fmt.Errorf("error checking slug duplicates for %s: %w", SlugKey(c.Type, c.Slug), err)
+		}
+		owners = v.Filter(owners, func(tv v.TextValue) bool { return tv.Key != c.ID })
+		if len(owners) == 0 {
+			return c, nil
+		}
+		if !generate {
+			return c, fmt.Errorf("%w: %s (%s)", ErrDuplicateSlug, SlugKey(c.Type, c.Slug), owners[0].Key)
+		}
+		c.Slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
 //------------------------------------------------------------------------------
 // Content Versions
 //------------------------------------------------------------------------------
@@ -145,6 +308,9 @@ func (s Service) Create(ctx context.Context, c Content) (Content, []string, erro
 	c.CreatedAt = at
 	c.VersionID = t.String()
 	c.UpdatedAt = at
+	if c.Status == "" {
+		c.Status = DRAFT
+	}
 	c = c.Sanitize()
 	c.WordCount = c.Body.WordCount()
 	c.ImageCount = c.Body.ImageCount()
@@ -154,10 +320,19 @@ func (s Service) Create(ctx context.Context, c Content) (Content, []string, erro
 	if len(problems) > 0 {
 		return c, problems, fmt.Errorf("error creating %s %s: invalid field(s): %s", s.EntityType, c.ID, strings.Join(problems, ", "))
 	}
-	err := s.Table.WriteEntity(ctx, c)
+	c, err := s.assignSlug(ctx, c, Content{})
+	if err != nil {
+		return c, problems, fmt.Errorf("error creating %s %s: %w", s.EntityType, c.ID, err)
+	}
+	err = s.Table.WriteEntity(ctx, c)
 	if err != nil {
 		return c, problems, fmt.Errorf("error creating %s %s %s: %w", s.EntityType, c.ID, c.Title(), err)
 	}
+	if c.IsPublished() {
+		if err = s.syncPublished(ctx, c.ID); err != nil {
+			return c, problems, fmt.Errorf("error creating %s %s %s: %w", s.EntityType, c.ID, c.Title(), err)
+		}
+	}
 	return c, problems, nil
 }
 
@@ -176,23 +351,107 @@ func (s Service) Update(ctx context.Context, c Content) (Content, []string, erro
 	if len(problems) > 0 {
 		return c, problems, fmt.Errorf("error updating %s %s: invalid field(s): %s", s.EntityType, c.ID, strings.Join(problems, ", "))
 	}
-	return c, problems, s.Table.UpdateEntity(ctx, c)
+	prior, err := s.Table.ReadEntity(ctx, c.ID)
+	if err != nil && !errors.Is(err, v.ErrNotFound) {
+		return c, problems, fmt.Errorf("error updating %s %s: %w", s.EntityType, c.ID, err)
+	}
+	c, err = s.assignSlug(ctx, c, prior)
+	if err != nil {
+		return c, problems, fmt.Errorf("error updating %s %s: %w", s.EntityType, c.ID, err)
+	}
+	if err = s.Table.UpdateEntity(ctx, c); err != nil {
+		return c, problems, err
+	}
+	// Drafts and reviews leave the published version (if any) visible
+	if status := c.CurrentStatus(); status == PUBLISHED || status == ARCHIVED {
+		if err = s.syncPublished(ctx, c.ID); err != nil {
+			return c, problems, fmt.Errorf("error updating %s %s: %w", s.EntityType, c.ID, err)
+		}
+	}
+	return c, problems, nil
+}
+
+// Transition changes the Status of the Content, saving a new version that identifies the editor responsible.
+// ErrInvalidTransition is returned if the workflow does not permit the change. Published Content goes live
+// immediately (PublishedAt), unless it's embargoed, in which case it goes live at PublishAt.
+func (s Service) Transition(ctx context.Context, c Content, to Status, editorID, editorName, comment string) (Content, error) {
+	if _, err := FindTransition(c.CurrentStatus(), to); err != nil {
+		return c, fmt.Errorf("error updating %s %s status: %w", s.EntityType, c.ID, err)
+	}
+	c.Status = to
+	c.PublishedAt = time.Time{}
+	if now := time.Now(); to == PUBLISHED && !c.IsEmbargoed(now) {
+		c.PublishedAt = now
+	}
+	c.EditorID = editorID
+	c.EditorName = editorName
+	c.Comment = comment
+	c, _, err := s.Update(ctx, c)
+	return c, err
 }
 
 // Write a Content to the Content table. This method assumes that the Content has all the required fields.
-// It would most likely be used for "refreshing" the index rows in the Content table.
+// It would most likely be used for "refreshing" the index rows in the Content table, including the published
+// Content and its search index.
 func (s Service) Write(ctx context.Context, c Content) (Content, error) {
-	return c, s.Table.WriteEntity(ctx, c)
+	if err := s.Table.WriteEntity(ctx, c); err != nil {
+		return c, err
+	}
+	return c, s.syncPublished(ctx, c.ID)
 }
 
 // Delete a Content from the Content table. The deleted Content is returned.
 func (s Service) Delete(ctx context.Context, id string) (Content, error) {
-	return s.Table.DeleteEntityWithID(ctx, id)
+	c, err := s.Table.DeleteEntityWithID(ctx, id)
+	if err != nil {
+		return c, err
+	}
+	if _, err = s.Published.DeleteEntityWithID(ctx, id); err != nil && !errors.Is(err, v.ErrNotFound) {
+		return c, fmt.Errorf("error deleting published %s %s: %w", s.EntityType, id, err)
+	}
+	return c, nil
 }
 
 // Delete a Content Version from the Content table. The deleted Content is returned.
 func (s Service) DeleteVersion(ctx context.Context, id string, versionID string) (Content, error) {
-	return s.Table.DeleteEntityVersionWithID(ctx, id, versionID)
+	c, err := s.Table.DeleteEntityVersionWithID(ctx, id, versionID)
+	if err != nil {
+		return c, err
+	}
+	return c, s.syncPublished(ctx, id)
+}
+
+// syncPublished keeps the published Content table up to date with the publicly visible version of the
+// specified Content: it's written when a version goes live (replacing the prior published version), and
+// deleted when the Content is archived or expires, or has no published version. Embargoed versions go live
+// when the publication scheduler marks them as published.
+func (s Service) syncPublished(ctx context.Context, id string) error {
+	c, err := s.ReadPublished(ctx, id)
+	if errors.Is(err, v.ErrNotFound) {
+		if _, err = s.Published.DeleteEntityWithID(ctx, id); err != nil && !errors.Is(err, v.ErrNotFound) {
+			return fmt.Errorf("error deleting published %s %s: %w", s.EntityType, id, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	prior, err := s.Published.ReadCurrentEntityVersionID(ctx, id)
+	if err != nil && !errors.Is(err, v.ErrNotFound) {
+		return fmt.Errorf("error reading published %s %s: %w", s.EntityType, id, err)
+	}
+	if prior == c.VersionID {
+		return nil
+	}
+	if err = s.Published.UpdateEntity(ctx, c); err != nil {
+		return fmt.Errorf("error writing published %s %s: %w", s.EntityType, c.RefID(), err)
+	}
+	if prior != "" {
+		if _, err = s.Published.DeleteEntityVersionWithID(ctx, id, prior); err != nil {
+			return fmt.Errorf("error deleting prior published %s %s: %w", s.EntityType, id, err)
+		}
+	}
+	return nil
 }
 
 // Exists checks if a Content exists in the Content table.
@@ -225,6 +484,41 @@ func (s Service) ReadVersionAsJSON(ctx context.Context, id, versionID string) ([
 	return s.Table.ReadEntityVersionAsJSON(ctx, id, versionID)
 }
 
+// ReadPublished returns the latest version of the specified Content that is publicly visible now.
+// See ReadPublishedAt for details.
+func (s Service) ReadPublished(ctx context.Context, id string) (Content, error) {
+	return s.ReadPublishedAt(ctx, id, time.Now())
+}
+
+// ReadPublishedAt returns the latest PUBLISHED version of the specified Content that is visible at the
+// specified time. An embargoed version is skipped, leaving a previously published version visible until the
+// embargo lifts. If the Content has expired or has been archived since it was last published, or has never
+// been published, v.ErrNotFound is returned.
+func (s Service) ReadPublishedAt(ctx context.Context, id string, at time.Time) (Content, error) {
+	offset := ""
+	for {
+		versions, err := s.Table.ReadEntityVersions(ctx, id, true, 10, offset)
+		if err != nil {
+			return Content{}, err
+		}
+		for _, c := range versions {
+			if c.IsPublished() && c.IsExpired(at) {
+				return Content{}, fmt.Errorf("error reading published %s %s: expired: %w", s.EntityType, id, v.ErrNotFound)
+			}
+			if c.IsVisibleAt(at) {
+				return c, nil
+			}
+			if c.CurrentStatus() == ARCHIVED {
+				return Content{}, fmt.Errorf("error reading published %s %s: archived: %w", s.EntityType, id, v.ErrNotFound)
+			}
+		}
+		if len(versions) < 10 {
+			return Content{}, fmt.Errorf("error reading published %s %s: %w", s.EntityType, id, v.ErrNotFound)
+		}
+		offset = versions[len(versions)-1].VersionID
+	}
+}
+
 // ReadVersions returns paginated versions of the specified Content.
 // Sorting is chronological (or reverse). The offset is the last ID returned in a previous request.
 func (s Service) ReadVersions(ctx context.Context, id string, reverse bool, limit int, offset string) ([]Content, error) {
@@ -298,6 +592,148 @@ func (s Service) ReadContents(ctx context.Context, reverse bool, limit int, offs
 	return s.Table.ReadEntities(ctx, ids)
 }
 
+//------------------------------------------------------------------------------
+// Content Slugs
+//------------------------------------------------------------------------------
+
+// ReadSlug finds the Content of the specified Type with the specified slug, or which had it as a prior slug.
+// It returns the Content ID (Key) and its current slug (Value); if they differ, the slug is an alias that
+// should be redirected to the current slug. If no Content has the slug, v.ErrNotFound is returned.
+func (s Service) ReadSlug(ctx context.Context, t Type, slug string) (v.TextValue, error) {
+	owners, err := s.Table.ReadAllTextValues(ctx, rowContentSlugs, SlugKey(t, slug), false)
+	if err != nil {
+		return v.TextValue{}, fmt.Errorf("error reading %s slug %s: %w", s.EntityType, SlugKey(t, slug), err)
+	}
+	if len(owners) == 0 {
+		return v.TextValue{}, fmt.Errorf("error reading %s slug %s: %w", s.EntityType, SlugKey(t, slug), v.ErrNotFound)
+	}
+	return owners[0], nil
+}
+
+//------------------------------------------------------------------------------
+// Content Search
+//------------------------------------------------------------------------------
+
+// documentCount is a snapshot of the number of Content documents searched, and when they were counted.
+type documentCount struct {
+	n         int64
+	countedAt time.Time
+}
+
+// documentCounts caches the numbers of latest and published Content documents, which are used to rank search
+// hits, so that they're not counted on every search.
+type documentCounts struct {
+	latest, published atomic.Pointer[documentCount]
+}
+
+// countDocuments returns the number of latest or published Content documents, counted at most once per
+// documentCountInterval.
+func (s Service) countDocuments(ctx context.Context, published bool) (int64, error) {
+	table, row, cached := s.Table, rowContents, &s.counts.latest
+	if published {
+		table, row, cached = s.Published, rowPublishedContents, &s.counts.published
+	}
+	if dc := cached.Load(); dc != nil && time.Since(dc.countedAt) < documentCountInterval {
+		return dc.n, nil
+	}
+	n, err := table.CountPartKeyValues(ctx, row)
+	if err == nil {
+		cached.Store(&documentCount{n: n, countedAt: time.Now()})
+	}
+	return n, err
+}
+
+// Search finds the Content containing all the words and "quoted phrases" in the query, optionally filtered
+// by Type, Tag, and Author, and returns a page of hits ranked by relevance, with highlighted snippets.
+// Candidates are found in the inverted index of the latest versions, or of the published versions, and then
+// at most maxSearchCandidates of them (the most recently created) are read and scored. ErrEmptySearch is
+// returned if the query has no search terms.
+func (s Service) Search(ctx context.Context, q SearchQuery) (SearchResults, error) {
+	results := SearchResults{Query: q.Text, Offset: q.Offset, Hits: []SearchHit{}}
+	pq := parseQuery(q.Text)
+	if len(pq.terms) == 0 {
+		return results, fmt.Errorf("error searching %s %q: %w", s.EntityType, q.Text, ErrEmptySearch)
+	}
+	table, terms := s.Table, rowContentTerms
+	filters := []struct {
+		row v.TableRow[Content]
+		key string
+	}{
+		{rowContentTitlesType, q.Type.String()},
+		{rowContentTitlesTag, q.Tag},
+		{rowContentTitlesAuthor, q.Author},
+	}
+	if q.Published {
+		table, terms = s.Published, rowContentPublishedTerms
+		filters[0].row, filters[1].row, filters[2].row = rowContentPublishedType, rowContentPublishedTag, rowContentPublishedAuthor
+	}
+	// Candidates contain every term, and match the filters
+	var candidates []string
+	idf := make(map[string]float64, len(pq.terms))
+	count, err := s.countDocuments(ctx, q.Published)
+	if err != nil {
+		return results, fmt.Errorf("error searching %s %q: %w", s.EntityType, q.Text, err)
+	}
+	for i, term := range pq.terms {
+		ids, err := table.ReadAllSortKeyValues(ctx, terms, term)
+		if err != nil {
+			return results, fmt.Errorf("error searching %s for term %s: %w", s.EntityType, term, err)
+		}
+		df := float64(len(ids))
+		n := math.Max(float64(count), df)
+		idf[term] = math.Log(1 + (n-df+0.5)/(df+0.5))
+		if i == 0 {
+			candidates = ids
+		} else {
+			candidates = intersectIDs(candidates, ids)
+		}
+	}
+	for _, f := range filters {
+		if f.key == "" || len(candidates) == 0 {
+			continue
+		}
+		ids, err := table.ReadAllSortKeyValues(ctx, f.row, f.key)
+		if err != nil {
+			return results, fmt.Errorf("error searching %s by %s %s: %w", s.EntityType, f.row.PartKeyName, f.key, err)
+		}
+		candidates = intersectIDs(candidates, ids)
+	}
+	// Score the most recent candidates (IDs are chronological)
+	if len(candidates) > maxSearchCandidates {
+		candidates = candidates[len(candidates)-maxSearchCandidates:]
+	}
+	now := time.Now()
+	for _, c := range table.ReadEntities(ctx, candidates) {
+		if q.Published && !c.IsVisibleAt(now) {
+			continue
+		}
+		if !q.matchesFilters(c) {
+			continue
+		}
+		if hit, ok := c.match(pq, idf); ok {
+			results.Hits = append(results.Hits, hit)
+		}
+	}
+	// Rank and paginate the hits
+	rankHits(results.Hits)
+	results.Total = len(results.Hits)
+	if q.Limit < 1 {
+		q.Limit = 10
+	}
+	from := min(max(q.Offset, 0), results.Total)
+	results.Hits = results.Hits[from:min(from+q.Limit, results.Total)]
+	return results, nil
+}
+
+// intersectIDs returns the IDs in a that are also in b.
+func intersectIDs(a, b []string) []string {
+	set := make(map[string]bool, len(b))
+	for _, id := range b {
+		set[id] = true
+	}
+	return v.Filter(a, func(id string) bool { return set[id] })
+}
+
 //------------------------------------------------------------------------------
 // Content Titles by Type
 //------------------------------------------------------------------------------
@@ -427,3 +863,73 @@ func (s Service) ReadAllTitlesByTag(ctx context.Context, tag string, sortByValue
 func (s Service) FilterTitlesByTag(ctx context.Context, tag string, contains string, anyMatch bool) ([]v.TextValue, error) {
 	return s.filterTitles(ctx, rowContentTitlesTag, tag, contains, anyMatch)
 }
+
+//------------------------------------------------------------------------------
+// Content Titles by Status
+//------------------------------------------------------------------------------
+
+// ReadAllStatuses returns all Content statuses in the Content table.
+func (s Service) ReadAllStatuses(ctx context.Context) ([]string, error) {
+	return s.Table.ReadAllPartKeyValues(ctx, rowContentTitlesStatus)
+}
+
+// ReadTitlesByStatus returns a paginated list of Content IDs and Titles for a given Content status.
+func (s Service) ReadTitlesByStatus(ctx context.Context, status string, reverse bool, limit int, offset string) ([]v.TextValue, error) {
+	return s.Table.ReadTextValues(ctx, rowContentTitlesStatus, status, reverse, limit, offset)
+}
+
+// ReadAllTitlesByStatus returns all Content IDs and Titles for a given Content status.
+func (s Service) ReadAllTitlesByStatus(ctx context.Context, status string, sortByValue bool) ([]v.TextValue, error) {
+	return s.Table.ReadAllTextValues(ctx, rowContentTitlesStatus, status, sortByValue)
+}
+
+// FilterTitlesByStatus returns a filtered list of Content IDs and Titles for a given Content status.
+// The case-insensitive contains query is split into words, and the words are compared with the value in the TextValue.
+// If anyMatch is true, then a TextValue is included in the results if any of the words are found (OR filter).
+// If anyMatch is false, then the TextValue must contain all the words in the query string (AND filter).
+// The filtered results are sorted alphabetically by value, not by ID.
+func (s Service) FilterTitlesByStatus(ctx context.Context, status string, contains string, anyMatch bool) ([]v.TextValue, error) {
+	return s.filterTitles(ctx, rowContentTitlesStatus, status, contains, anyMatch)
+}
+
+//------------------------------------------------------------------------------
+// Content Publication Schedule
+//------------------------------------------------------------------------------
+
+// ReadAllScheduleDates returns all dates (UTC, YYYY-MM-DD) with scheduled Content changes.
+func (s Service) ReadAllScheduleDates(ctx context.Context) ([]string, error) {
+	return s.Table.ReadAllPartKeyValues(ctx, rowContentsSchedule)
+}
+
+// ReadScheduled returns the published Content with changes scheduled on the specified date (UTC, YYYY-MM-DD).
+func (s Service) ReadScheduled(ctx context.Context, date string) ([]Content, error) {
+	return s.Table.ReadAllEntitiesFromRow(ctx, rowContentsSchedule, date)
+}
+
+// ReadDue returns the published Content with scheduled changes that are due at the specified time: Content
+// whose embargo has lifted, but which hasn't been marked as live, and Content that has expired.
+func (s Service) ReadDue(ctx context.Context, at time.Time) ([]Content, error) {
+	dates, err := s.ReadAllScheduleDates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s schedule dates: %w", s.EntityType, err)
+	}
+	today := at.UTC().Format(DateLayout)
+	var due []Content
+	seen := make(map[string]bool)
+	for _, date := range dates {
+		if date > today {
+			continue
+		}
+		contents, err := s.ReadScheduled(ctx, date)
+		if err != nil {
+			return due, fmt.Errorf("error reading %s scheduled on %s: %w", s.EntityType, date, err)
+		}
+		for _, c := range contents {
+			if !seen[c.ID] && c.IsDue(at) {
+				seen[c.ID] = true
+				due = append(due, c)
+			}
+		}
+	}
+	return due, nil
+}
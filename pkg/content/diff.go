@@ -0,0 +1,488 @@
+package content
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+	"versionary-api/pkg/image"
+)
+
+// Change indicates how an element of the Content differs between two versions.
+type Change string
+
+// ADDED Change indicates an element that is only in the newer version.
+const ADDED Change = "ADDED"
+
+// REMOVED Change indicates an element that is only in the older version.
+const REMOVED Change = "REMOVED"
+
+// MOVED Change indicates a Section with a different parent, or a different order among its siblings.
+const MOVED Change = "MOVED"
+
+// MODIFIED Change indicates an element with different values in the two versions.
+const MODIFIED Change = "MODIFIED"
+
+// Diff describes the differences between two versions of a unit of Content.
+type Diff struct {
+	ContentID string        `json:"contentId"`
+	From      VersionInfo   `json:"from"`
+	To        VersionInfo   `json:"to"`
+	Summary   DiffSummary   `json:"summary"`
+	Sections  []SectionDiff `json:"sections"`
+	Tags      []ItemChange  `json:"tags,omitempty"`
+	Authors   []ItemChange  `json:"authors,omitempty"`
+}
+
+// VersionInfo identifies one of the Content versions being compared.
+type VersionInfo struct {
+	VersionID  string    `json:"versionId"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	Status     Status    `json:"status"`
+	EditorName string    `json:"editorName,omitempty"`
+	Comment    string    `json:"comment,omitempty"`
+	WordCount  int       `json:"wordCount"`
+}
+
+// DiffSummary counts the changed Sections of a Diff. A Section may be both moved and modified.
+type DiffSummary struct {
+	Added    int `json:"added"`
+	Removed  int `json:"removed"`
+	Moved    int `json:"moved"`
+	Modified int `json:"modified"`
+}
+
+// SectionDiff describes the differences in a Section, identified by its ID. Paths are the 1-based positions
+// of the Section among its siblings, from the top of the body (e.g. "2.1"); the body's path is empty.
+// Word-level edits are provided for the changed titles and text. They're complete for an added or removed
+// Section, so that it can be displayed.
+type SectionDiff struct {
+	SectionID    string       `json:"sectionId"`
+	Heading      string       `json:"heading"`
+	Changes      []Change     `json:"changes"`
+	FromParentID string       `json:"fromParentId,omitempty"`
+	FromPath     string       `json:"fromPath,omitempty"`
+	ToParentID   string       `json:"toParentId,omitempty"`
+	ToPath       string       `json:"toPath,omitempty"`
+	Title        []TextEdit   `json:"title,omitempty"`
+	Subtitle     []TextEdit   `json:"subtitle,omitempty"`
+	Text         []TextEdit   `json:"text,omitempty"`
+	Links        []ItemChange `json:"links,omitempty"`
+	Images       []ItemChange `json:"images,omitempty"`
+}
+
+// Has returns true if the Section has the specified Change.
+func (d SectionDiff) Has(change Change) bool {
+	for _, c := range d.Changes {
+		if c == change {
+			return true
+		}
+	}
+	return false
+}
+
+// ItemChange describes an added, removed, or modified Link, Image, Tag, or Author, identified by its key
+// (an ID, tag, or name). The From and To values are brief, human-readable descriptions of the item.
+type ItemChange struct {
+	Key    string `json:"key"`
+	Change Change `json:"change"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+// NewDiff compares two versions of Content. Sections are matched by ID, so that a Section that moves or is
+// edited is recognized as the same Section. Changed Sections are listed in the order of the newer version,
+// followed by the removed Sections, in the order of the older version.
+func NewDiff(from, to Content) Diff {
+	d := Diff{
+		ContentID: to.ID,
+		From:      newVersionInfo(from),
+		To:        newVersionInfo(to),
+		Sections:  []SectionDiff{},
+		Tags:      diffItems(tagItems(from.Tags), tagItems(to.Tags)),
+		Authors:   diffItems(authorItems(from.Authors), authorItems(to.Authors)),
+	}
+	// The bodies are always compared, even if a client has replaced the body's ID
+	from.Body.ID = to.Body.ID
+	fromNodes, fromOrder := flattenSections(from.Body)
+	toNodes, toOrder := flattenSections(to.Body)
+	moved := movedSections(fromNodes, toNodes, fromOrder, toOrder)
+	for _, id := range toOrder {
+		t := toNodes[id]
+		f, ok := fromNodes[id]
+		if !ok {
+			sd := diffSection(Section{}, t.section)
+			sd.Changes = []Change{ADDED}
+			sd.ToParentID, sd.ToPath = t.parentID, t.path
+			d.Sections = append(d.Sections, sd)
+			d.Summary.Added++
+			continue
+		}
+		sd := diffSection(f.section, t.section)
+		if moved[id] {
+			sd.Changes = append(sd.Changes, MOVED)
+			d.Summary.Moved++
+		}
+		if sd.isModified() {
+			sd.Changes = append(sd.Changes, MODIFIED)
+			d.Summary.Modified++
+		}
+		if len(sd.Changes) > 0 {
+			sd.FromParentID, sd.FromPath = f.parentID, f.path
+			sd.ToParentID, sd.ToPath = t.parentID, t.path
+			d.Sections = append(d.Sections, sd)
+		}
+	}
+	for _, id := range fromOrder {
+		if _, ok := toNodes[id]; !ok {
+			f := fromNodes[id]
+			sd := diffSection(f.section, Section{})
+			sd.Heading = sectionHeading(f.section)
+			sd.Changes = []Change{REMOVED}
+			sd.FromParentID, sd.FromPath = f.parentID, f.path
+			d.Sections = append(d.Sections, sd)
+			d.Summary.Removed++
+		}
+	}
+	return d
+}
+
+// IsEmpty returns true if the versions have the same Sections, Tags, and Authors.
+func (d Diff) IsEmpty() bool {
+	return len(d.Sections) == 0 && len(d.Tags) == 0 && len(d.Authors) == 0
+}
+
+// newVersionInfo summarizes a Content version.
+func newVersionInfo(c Content) VersionInfo {
+	return VersionInfo{
+		VersionID:  c.VersionID,
+		UpdatedAt:  c.UpdatedAt,
+		Status:     c.CurrentStatus(),
+		EditorName: c.EditorName,
+		Comment:    c.Comment,
+		WordCount:  c.WordCount,
+	}
+}
+
+// sectionNode is a Section, located in the Content body.
+type sectionNode struct {
+	section  Section
+	parentID string
+	path     string
+}
+
+// flattenSections indexes the Sections of a body by ID, returning the IDs in document (depth-first) order.
+// Subsections are not included in the indexed Sections; they're compared separately.
+func flattenSections(body Section) (map[string]sectionNode, []string) {
+	nodes := make(map[string]sectionNode)
+	var order []string
+	var walk func(s Section, parentID, path string)
+	walk = func(s Section, parentID, path string) {
+		if _, dup := nodes[s.ID]; dup {
+			return
+		}
+		children := s.Sections
+		s.Sections = nil
+		nodes[s.ID] = sectionNode{section: s, parentID: parentID, path: path}
+		order = append(order, s.ID)
+		for i, child := range children {
+			p := strconv.Itoa(i + 1)
+			if path != "" {
+				p = path + "." + p
+			}
+			walk(child, s.ID, p)
+		}
+	}
+	walk(body, "", "")
+	return nodes, order
+}
+
+// movedSections identifies the Sections with a new parent, or a new order among their siblings. For siblings,
+// the longest common subsequence of the old and new orders is considered unmoved, so that inserting or removing
+// a Section doesn't move the Sections after it.
+func movedSections(fromNodes, toNodes map[string]sectionNode, fromOrder, toOrder []string) map[string]bool {
+	moved := make(map[string]bool)
+	fromSiblings := make(map[string][]string)
+	toSiblings := make(map[string][]string)
+	for _, id := range toOrder {
+		t := toNodes[id]
+		f, ok := fromNodes[id]
+		if !ok {
+			continue
+		}
+		if f.parentID != t.parentID {
+			moved[id] = true
+			continue
+		}
+		toSiblings[t.parentID] = append(toSiblings[t.parentID], id)
+	}
+	for _, id := range fromOrder {
+		f := fromNodes[id]
+		if t, ok := toNodes[id]; ok && t.parentID == f.parentID {
+			fromSiblings[f.parentID] = append(fromSiblings[f.parentID], id)
+		}
+	}
+	for parentID, siblings := range toSiblings {
+		kept := commonSubsequence(fromSiblings[parentID], siblings)
+		for _, id := range siblings {
+			if !kept[id] {
+				moved[id] = true
+			}
+		}
+	}
+	return moved
+}
+
+// commonSubsequence returns the members of the longest common subsequence of two lists of unique IDs.
+func commonSubsequence(a, b []string) map[string]bool {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	kept := make(map[string]bool)
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			kept[a[i]] = true
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return kept
+}
+
+// diffSection compares the fields of two versions of a Section, excluding subsections.
+func diffSection(from, to Section) SectionDiff {
+	sd := SectionDiff{
+		SectionID: to.ID,
+		Heading:   sectionHeading(to),
+		Links:     diffItems(linkItems(from.Links), linkItems(to.Links)),
+		Images:    diffItems(imageItems(from.Images), imageItems(to.Images)),
+	}
+	if sd.SectionID == "" {
+		sd.SectionID = from.ID
+	}
+	if from.Title != to.Title {
+		sd.Title = DiffWords(from.Title, to.Title)
+	}
+	if from.Subtitle != to.Subtitle {
+		sd.Subtitle = DiffWords(from.Subtitle, to.Subtitle)
+	}
+	if from.Text != to.Text {
+		sd.Text = DiffWords(from.Text, to.Text)
+	}
+	return sd
+}
+
+// isModified returns true if any of the Section's fields changed.
+func (d SectionDiff) isModified() bool {
+	return len(d.Title) > 0 || len(d.Subtitle) > 0 || len(d.Text) > 0 || len(d.Links) > 0 || len(d.Images) > 0
+}
+
+// sectionHeading returns a brief label for the Section: its title, if it has one, or its ID.
+func sectionHeading(s Section) string {
+	if s.Title != "" {
+		return s.Title
+	}
+	return "Section " + s.ID
+}
+
+// diffItem is a Link, Image, Tag, or Author, reduced to a key and a description for comparison.
+type diffItem struct {
+	key   string
+	label string
+}
+
+// diffItems compares two lists of items by key, returning the removed, modified, and added items.
+func diffItems(from, to []diffItem) []ItemChange {
+	var changes []ItemChange
+	labels := make(map[string]string, len(to))
+	for _, item := range to {
+		labels[item.key] = item.label
+	}
+	old := make(map[string]bool, len(from))
+	for _, item := range from {
+		old[item.key] = true
+		label, ok := labels[item.key]
+		if !ok {
+			changes = append(changes, ItemChange{Key: item.key, Change: REMOVED, From: item.label})
+		} else if label != item.label {
+			changes = append(changes, ItemChange{Key: item.key, Change: MODIFIED, From: item.label, To: label})
+		}
+	}
+	for _, item := range to {
+		if !old[item.key] {
+			changes = append(changes, ItemChange{Key: item.key, Change: ADDED, To: item.label})
+		}
+	}
+	return changes
+}
+
+// linkItems describes Links for comparison.
+func linkItems(links []Link) []diffItem {
+	items := make([]diffItem, 0, len(links))
+	for _, l := range links {
+		key := l.ID
+		if key == "" {
+			key = l.URL
+		}
+		label := fmt.Sprintf("%s (%s)", l.Title, l.URL)
+		if l.Description != "" {
+			label += ": " + l.Description
+		}
+		items = append(items, diffItem{key: key, label: label})
+	}
+	return items
+}
+
+// imageItems describes Images for comparison.
+func imageItems(images []image.Image) []diffItem {
+	items := make([]diffItem, 0, len(images))
+	for _, i := range images {
+		key := i.ID
+		if key == "" {
+			key = i.FileName
+		}
+		items = append(items, diffItem{key: key, label: fmt.Sprintf("%s (%s)", i.Label(), i.AltText)})
+	}
+	return items
+}
+
+// tagItems describes Tags for comparison.
+func tagItems(tags []string) []diffItem {
+	items := make([]diffItem, 0, len(tags))
+	for _, t := range tags {
+		items = append(items, diffItem{key: t, label: t})
+	}
+	return items
+}
+
+// authorItems describes Authors for comparison.
+func authorItems(authors []Author) []diffItem {
+	items := make([]diffItem, 0, len(authors))
+	for _, a := range authors {
+		label := a.Name
+		if a.Email != "" {
+			label += " <" + a.Email + ">"
+		}
+		if a.URL != "" {
+			label += " " + a.URL
+		}
+		items = append(items, diffItem{key: a.Name, label: label})
+	}
+	return items
+}
+
+//------------------------------------------------------------------------------
+// HTML Rendering
+//------------------------------------------------------------------------------
+
+// HTML renders the Diff as an HTML document for reviewers. Changed words are marked with <ins> and <del>,
+// and the changed Sections, Links, Images, Tags, and Authors are marked with classes (e.g. "added").
+func (d Diff) HTML() string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<title>Content " + esc(d.ContentID) + " Changes</title>\n")
+	b.WriteString("<style>ins{background:#dfd;text-decoration:none}del{background:#fdd}" +
+		".added{border-left:4px solid #6c6}.removed{border-left:4px solid #c66}" +
+		".moved,.modified{border-left:4px solid #69c}section{padding-left:1em;margin:1em 0}</style>\n")
+	b.WriteString("</head>\n<body>\n<header>\n")
+	b.WriteString("<h1>Content " + esc(d.ContentID) + " Changes</h1>\n")
+	b.WriteString("<p>From version " + versionHTML(d.From) + " to version " + versionHTML(d.To) + "</p>\n")
+	fmt.Fprintf(&b, "<p>Sections: %d added, %d removed, %d moved, %d modified</p>\n",
+		d.Summary.Added, d.Summary.Removed, d.Summary.Moved, d.Summary.Modified)
+	b.WriteString("</header>\n")
+	if d.IsEmpty() {
+		b.WriteString("<p>No changes.</p>\n")
+	}
+	itemsHTML(&b, "Tags", d.Tags)
+	itemsHTML(&b, "Authors", d.Authors)
+	for _, s := range d.Sections {
+		classes := strings.ToLower(strings.Join(changeStrings(s.Changes), " "))
+		b.WriteString("<section id=\"section-" + esc(s.SectionID) + "\" class=\"" + classes + "\">\n")
+		b.WriteString("<h2>" + esc(s.Heading) + " <small>(" + strings.Join(changeStrings(s.Changes), ", ") + ")</small></h2>\n")
+		if s.Has(MOVED) {
+			b.WriteString("<p>Moved from " + pathHTML(s.FromPath) + " to " + pathHTML(s.ToPath) + "</p>\n")
+		}
+		if len(s.Title) > 0 {
+			b.WriteString("<h3>" + EditsHTML(s.Title) + "</h3>\n")
+		}
+		if len(s.Subtitle) > 0 {
+			b.WriteString("<h4>" + EditsHTML(s.Subtitle) + "</h4>\n")
+		}
+		if len(s.Text) > 0 {
+			b.WriteString("<div>" + EditsHTML(s.Text) + "</div>\n")
+		}
+		itemsHTML(&b, "Links", s.Links)
+		itemsHTML(&b, "Images", s.Images)
+		b.WriteString("</section>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// versionHTML describes a Content version.
+func versionHTML(v VersionInfo) string {
+	s := esc(v.VersionID) + " (" + esc(v.Status.String()) + ", " + v.UpdatedAt.UTC().Format(time.RFC3339)
+	if v.EditorName != "" {
+		s += ", " + esc(v.EditorName)
+	}
+	return s + ")"
+}
+
+// pathHTML describes the position of a Section.
+func pathHTML(path string) string {
+	if path == "" {
+		return "the top"
+	}
+	return "position " + esc(path)
+}
+
+// itemsHTML renders a list of item changes, if there are any.
+func itemsHTML(b *strings.Builder, heading string, changes []ItemChange) {
+	if len(changes) == 0 {
+		return
+	}
+	b.WriteString("<h3>" + heading + "</h3>\n<ul>\n")
+	for _, c := range changes {
+		b.WriteString("<li class=\"" + strings.ToLower(string(c.Change)) + "\">")
+		switch c.Change {
+		case ADDED:
+			b.WriteString("<ins>" + esc(c.To) + "</ins>")
+		case REMOVED:
+			b.WriteString("<del>" + esc(c.From) + "</del>")
+		default:
+			b.WriteString("<del>" + esc(c.From) + "</del> <ins>" + esc(c.To) + "</ins>")
+		}
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ul>\n")
+}
+
+// changeStrings converts a list of Changes to strings.
+func changeStrings(changes []Change) []string {
+	s := make([]string, len(changes))
+	for i, c := range changes {
+		s[i] = string(c)
+	}
+	return s
+}
+
+// esc escapes text for HTML. Sanitized values are already escaped, so they're unescaped first.
+func esc(s string) string {
+	return html.EscapeString(html.UnescapeString(s))
+}
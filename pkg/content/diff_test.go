@@ -0,0 +1,112 @@
+package content
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/voxtechnica/tuid-go"
+)
+
+func TestDiffWords(t *testing.T) {
+	expect := assert.New(t)
+	edits := DiffWords("<p>The quick brown fox.</p>", "<p>The <b>quick</b> red fox.</p>")
+	expect.Equal([]TextEdit{
+		{Op: EQUAL, Text: "<p>The "},
+		{Op: INSERT, Text: "<b>"},
+		{Op: EQUAL, Text: "quick"},
+		{Op: INSERT, Text: "</b>"},
+		{Op: EQUAL, Text: " "},
+		{Op: DELETE, Text: "brown"},
+		{Op: INSERT, Text: "red"},
+		{Op: EQUAL, Text: " fox.</p>"},
+	}, edits)
+	expect.Equal("<p>The <b>quick</b> <del>brown</del><ins>red</ins> fox.</p>", EditsHTML(edits))
+	expect.Equal([]TextEdit{{Op: EQUAL, Text: "Same words"}}, DiffWords("Same words", "Same words"))
+	expect.Empty(DiffWords("", ""))
+	expect.Equal("<ins>New &amp; improved</ins>", EditsHTML(DiffWords("", "New &amp; improved")))
+	expect.Equal("<del>Gone</del>", EditsHTML(DiffWords("<p>Gone</p>", "")))
+}
+
+func TestDiffSections(t *testing.T) {
+	expect := assert.New(t)
+	id := func() string { return tuid.NewID().String() }
+	intro, moved, edited, removed, nested := id(), id(), id(), id(), id()
+	link := Link{ID: id(), Title: "Versionary", URL: "https://github.com/voxtechnica/versionary"}
+	from := Content{
+		ID:        id(),
+		VersionID: id(),
+		Tags:      []string{"draft", "go"},
+		Authors:   []Author{{Name: "Ada"}},
+		Body: Section{ID: id(), Title: "Diffs", Sections: []Section{
+			{ID: intro, Title: "Intro", Text: "<p>Hello there.</p>"},
+			{ID: moved, Title: "Moved", Text: "Moving on."},
+			{ID: edited, Title: "Edited", Text: "<p>Old text.</p>", Links: []Link{link}},
+			{ID: removed, Title: "Removed", Text: "Goodbye."},
+			{ID: nested, Title: "Nested", Text: "Inside."},
+		}},
+	}
+	updated := link
+	updated.URL = "https://github.com/voxtechnica/versionary-api"
+	to := from
+	to.VersionID = id()
+	to.Tags = []string{"go", "published"}
+	to.Authors = []Author{{Name: "Ada", Email: "ada@example.com"}, {Name: "Grace"}}
+	to.Body = Section{ID: from.Body.ID, Title: "Diffs", Sections: []Section{
+		{ID: intro, Title: "Intro", Text: "<p>Hello there.</p>"},
+		{ID: edited, Title: "Edited", Text: "<p>New text.</p>", Links: []Link{updated}, Sections: []Section{
+			{ID: nested, Title: "Nested", Text: "Inside."},
+		}},
+		{ID: moved, Title: "Moved", Text: "Moving on."},
+		{ID: "added", Title: "Added", Text: "Welcome."},
+	}}
+
+	d := NewDiff(from, to)
+	expect.Equal(DiffSummary{Added: 1, Removed: 1, Moved: 2, Modified: 1}, d.Summary)
+	changes := make(map[string][]Change)
+	for _, s := range d.Sections {
+		changes[s.SectionID] = s.Changes
+	}
+	expect.NotContains(changes, intro, "unchanged sections are omitted")
+	expect.NotContains(changes, from.Body.ID)
+	expect.Equal([]Change{MODIFIED}, changes[edited], "edited stays put, since moved is the one that moved")
+	expect.Equal([]Change{MOVED}, changes[moved])
+	expect.Equal([]Change{MOVED}, changes[nested], "new parent")
+	expect.Equal([]Change{ADDED}, changes["added"])
+	expect.Equal([]Change{REMOVED}, changes[removed])
+	expect.Equal(removed, d.Sections[len(d.Sections)-1].SectionID, "removed sections are last")
+
+	for _, s := range d.Sections {
+		switch s.SectionID {
+		case edited:
+			expect.Empty(s.Title)
+			expect.Equal("<p><del>Old</del><ins>New</ins> text.</p>", EditsHTML(s.Text))
+			if expect.Len(s.Links, 1) {
+				expect.Equal(MODIFIED, s.Links[0].Change)
+			}
+		case nested:
+			expect.Equal(edited, s.ToParentID)
+			expect.Equal("2.1", s.ToPath)
+			expect.Equal("5", s.FromPath)
+		case removed:
+			expect.Equal("Removed", s.Heading)
+			expect.Equal([]TextEdit{{Op: DELETE, Text: "Goodbye."}}, s.Text)
+		}
+	}
+	expect.ElementsMatch([]ItemChange{
+		{Key: "draft", Change: REMOVED, From: "draft"},
+		{Key: "published", Change: ADDED, To: "published"},
+	}, d.Tags)
+	expect.ElementsMatch([]ItemChange{
+		{Key: "Ada", Change: MODIFIED, From: "Ada", To: "Ada <ada@example.com>"},
+		{Key: "Grace", Change: ADDED, To: "Grace"},
+	}, d.Authors)
+
+	page := d.HTML()
+	expect.True(strings.HasPrefix(page, "<!DOCTYPE html>"))
+	expect.Contains(page, "<del>Old</del><ins>New</ins>")
+	expect.Contains(page, `class="moved"`)
+	expect.Contains(page, "Ada &lt;ada@example.com&gt;")
+
+	expect.True(NewDiff(from, from).IsEmpty())
+}
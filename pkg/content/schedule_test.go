@@ -0,0 +1,96 @@
+package content
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v "github.com/voxtechnica/versionary"
+)
+
+func TestScheduleValidation(t *testing.T) {
+	expect := assert.New(t)
+	publishAt := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	c := Content{PublishAt: publishAt, UnpublishAt: publishAt}
+	expect.Contains(c.Validate(), "UnpublishAt must be after PublishAt")
+	c.UnpublishAt = publishAt.Add(24 * time.Hour)
+	expect.NotContains(c.Validate(), "UnpublishAt must be after PublishAt")
+	expect.True(c.IsEmbargoed(publishAt.Add(-time.Minute)))
+	expect.False(c.IsVisibleAt(publishAt.Add(-time.Minute)))
+	expect.True(c.IsVisibleAt(publishAt))
+	expect.False(c.IsVisibleAt(c.UnpublishAt))
+	expect.True(c.IsPending())
+	expect.Equal([]string{"2030-01-01", "2030-01-02"}, c.ScheduleDates())
+	c.PublishedAt = publishAt
+	expect.Equal([]string{"2030-01-02"}, c.ScheduleDates())
+	c.Status = DRAFT
+	expect.Empty(c.ScheduleDates(), "only published Content is scheduled")
+}
+
+func TestScheduledPublishing(t *testing.T) {
+	expect := assert.New(t)
+	now := time.Now()
+	c, _, err := service.Create(ctx, Content{
+		Type:        ARTICLE,
+		Body:        Section{Title: "Embargoed Article", Text: "Not yet."},
+		PublishAt:   now.Add(time.Hour),
+		UnpublishAt: now.Add(2 * time.Hour),
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = service.Delete(ctx, c.ID) }()
+	c, err = service.Transition(ctx, c, IN_REVIEW, "", "Editor", "")
+	if !expect.NoError(err) {
+		return
+	}
+	c, err = service.Transition(ctx, c, PUBLISHED, "", "Publisher", "embargoed")
+	if !expect.NoError(err) {
+		return
+	}
+	expect.True(c.PublishedAt.IsZero(), "embargoed Content is not live yet")
+
+	// The embargo hides the published version
+	_, err = service.ReadPublished(ctx, c.ID)
+	expect.True(errors.Is(err, v.ErrNotFound))
+	if pub, err := service.ReadPublishedAt(ctx, c.ID, now.Add(90*time.Minute)); expect.NoError(err) {
+		expect.Equal(c.VersionID, pub.VersionID)
+	}
+
+	// It's due once the embargo lifts
+	if due, err := service.ReadDue(ctx, now); expect.NoError(err) {
+		expect.NotContains(contentIDs(due), c.ID)
+	}
+	if due, err := service.ReadDue(ctx, now.Add(90*time.Minute)); expect.NoError(err) {
+		expect.Contains(contentIDs(due), c.ID)
+	}
+
+	// Once it's live, it's due again only when it expires
+	c.PublishedAt = c.PublishAt
+	c, _, err = service.Update(ctx, c)
+	if !expect.NoError(err) {
+		return
+	}
+	if due, err := service.ReadDue(ctx, now.Add(90*time.Minute)); expect.NoError(err) {
+		expect.NotContains(contentIDs(due), c.ID)
+	}
+	if due, err := service.ReadDue(ctx, now.Add(3*time.Hour)); expect.NoError(err) {
+		expect.Contains(contentIDs(due), c.ID)
+	}
+	_, err = service.ReadPublishedAt(ctx, c.ID, now.Add(3*time.Hour))
+	expect.True(errors.Is(err, v.ErrNotFound), "expired Content is not published")
+
+	// Archived Content is no longer scheduled
+	c, err = service.Transition(ctx, c, ARCHIVED, "", "Publisher", "expired")
+	if !expect.NoError(err) {
+		return
+	}
+	if due, err := service.ReadDue(ctx, now.Add(3*time.Hour)); expect.NoError(err) {
+		expect.NotContains(contentIDs(due), c.ID)
+	}
+}
+
+func contentIDs(contents []Content) []string {
+	return v.Map(contents, func(c Content) string { return c.ID })
+}
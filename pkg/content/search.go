@@ -0,0 +1,355 @@
+package content
+
+import (
+	"errors"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	v "github.com/voxtechnica/versionary"
+)
+
+// ErrEmptySearch is returned when a search query has no searchable words (e.g. only common words like "the").
+var ErrEmptySearch = errors.New("search query has no searchable words")
+
+// Search field weights: a match in a title counts for more than a match in the text.
+const (
+	titleWeight   = 4.0 // the Content title and subtitle
+	headingWeight = 2.0 // section titles and subtitles
+	textWeight    = 1.0 // section text, link titles, and image alt text
+)
+
+const (
+	maxTermLength       = 40  // longer "words" (e.g. hashes) are not indexed
+	snippetWords        = 30  // words of text in a snippet
+	maxSnippets         = 3   // snippets per search hit
+	bm25K1              = 1.2 // term frequency saturation: repeating a word adds less and less to the score
+	maxSearchCandidates = 100 // matching Content read and scored per search; more words or filters narrow it
+)
+
+// documentCountInterval limits how often the Content is counted to rank search hits, since counting it
+// pages through every Content ID. A rough count is enough to weigh how rare each search term is.
+const documentCountInterval = 10 * time.Minute
+
+// stopWords are common words that are not indexed, since nearly all Content contains them.
+// They may be used in quoted phrases, though.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "was": true, "with": true,
+}
+
+// isSearchTerm returns true if the term is indexed for searching.
+func isSearchTerm(term string) bool {
+	return !stopWords[term] && len(term) <= maxTermLength
+}
+
+// word is a lower-case word, and its location in the text in which it was found.
+type word struct {
+	term       string
+	start, end int // byte offsets of the word in the text
+}
+
+// splitWords splits plain text into lower-case words, made of letters and digits. Apostrophes are dropped
+// (e.g. "don't" becomes "dont"), as they are in a Slug.
+func splitWords(text string) []word {
+	var words []word
+	var b strings.Builder
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			words = append(words, word{term: b.String(), start: start, end: end})
+			b.Reset()
+			start = -1
+		}
+	}
+	for i, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if start < 0 {
+				start = i
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case (r == '\'' || r == '’') && start >= 0:
+			continue
+		default:
+			flush(i)
+		}
+	}
+	flush(len(text))
+	return words
+}
+
+// plainText converts sanitized HTML into plain text, keeping words in separate elements apart.
+func plainText(s string) string {
+	return strings.TrimSpace(html.UnescapeString(stripTags(s)))
+}
+
+// searchField is a unit of searchable plain text in a Content version: a title, or the text of a section,
+// link, or image.
+type searchField struct {
+	sectionID string
+	heading   string // the title of the section containing the text
+	weight    float64
+	text      string
+	words     []word
+}
+
+// searchFields flattens the Content's Section tree into its searchable fields.
+func (c Content) searchFields() []searchField {
+	var fields []searchField
+	var walk func(s Section, weight float64)
+	walk = func(s Section, weight float64) {
+		heading := plainText(s.Title)
+		add := func(text string, weight float64) {
+			if text = plainText(text); text != "" {
+				fields = append(fields, searchField{s.ID, heading, weight, text, splitWords(text)})
+			}
+		}
+		add(s.Title, weight)
+		add(s.Subtitle, weight)
+		add(s.Text, textWeight)
+		for _, l := range s.Links {
+			add(l.Title, textWeight)
+		}
+		for _, i := range s.Images {
+			add(i.AltText, textWeight)
+		}
+		for _, sub := range s.Sections {
+			walk(sub, headingWeight)
+		}
+	}
+	walk(c.Body, titleWeight)
+	return fields
+}
+
+// SearchTerms returns the unique, sorted search terms in the Content: the words of its titles, text, link
+// titles, and image alt text, excluding common words. They're the keys of the inverted index used to search.
+func (c Content) SearchTerms() []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, f := range c.searchFields() {
+		for _, t := range f.words {
+			if !seen[t.term] && isSearchTerm(t.term) {
+				seen[t.term] = true
+				terms = append(terms, t.term)
+			}
+		}
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+// SearchQuery specifies a full-text search of Content, with optional filters.
+type SearchQuery struct {
+	Text      string // words and "quoted phrases", all of which must match
+	Type      Type   // filter by Type (optional)
+	Tag       string // filter by Tag (optional)
+	Author    string // filter by Author name (optional)
+	Published bool   // search the publicly visible versions, rather than the latest versions
+	Limit     int    // maximum number of hits to return (default: 10)
+	Offset    int    // number of ranked hits to skip
+}
+
+// matchesFilters returns true if the Content version has the Type, Tag, and Author specified by the query.
+func (q SearchQuery) matchesFilters(c Content) bool {
+	return (q.Type == "" || c.Type == q.Type) &&
+		(q.Tag == "" || v.Contains(c.Tags, q.Tag)) &&
+		(q.Author == "" || v.Contains(c.AuthorNames(), q.Author))
+}
+
+// parsedQuery is the text of a search query, split into search terms and phrases.
+type parsedQuery struct {
+	terms   []string   // unique search terms, excluding common words
+	phrases [][]string // quoted phrases of two or more words, including common words
+}
+
+// parseQuery splits the text of a search query into its search terms and quoted phrases.
+func parseQuery(text string) parsedQuery {
+	var q parsedQuery
+	seen := make(map[string]bool)
+	for i, part := range strings.Split(text, `"`) {
+		words := v.Map(splitWords(part), func(t word) string { return t.term })
+		for _, w := range words {
+			if !seen[w] && isSearchTerm(w) {
+				seen[w] = true
+				q.terms = append(q.terms, w)
+			}
+		}
+		if i%2 == 1 && len(words) > 1 {
+			q.phrases = append(q.phrases, words)
+		}
+	}
+	return q
+}
+
+// SearchHit is a Content version that matches a search, with highlighted snippets of the matching text.
+type SearchHit struct {
+	ID        string    `json:"id"`
+	VersionID string    `json:"versionId"`
+	Type      Type      `json:"type"`
+	Slug      string    `json:"slug,omitempty"`
+	Status    Status    `json:"status,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Title     string    `json:"title"`     // plain text
+	TitleHTML string    `json:"titleHtml"` // HTML, with matching words highlighted
+	Score     float64   `json:"score"`
+	Snippets  []Snippet `json:"snippets,omitempty"`
+}
+
+// Snippet is an excerpt of matching text, as HTML with the matching words highlighted (<mark>).
+type Snippet struct {
+	SectionID string `json:"sectionId,omitempty"`
+	Heading   string `json:"heading,omitempty"` // the title of the section containing the text
+	HTML      string `json:"html"`
+}
+
+// SearchResults is a page of ranked search hits.
+type SearchResults struct {
+	Query  string      `json:"query"`
+	Total  int         `json:"total"` // the number of hits on all pages
+	Offset int         `json:"offset"`
+	Hits   []SearchHit `json:"hits"`
+}
+
+// match scores the Content version against the query. Each term's score is weighted by the field it's found
+// in and by its rarity (idf, inverse document frequency), with diminishing returns for repetition (as in
+// BM25). A quoted phrase scores again, for each field it's found in. If any term or phrase is missing, the
+// version doesn't match.
+func (c Content) match(q parsedQuery, idf map[string]float64) (SearchHit, bool) {
+	terms := make(map[string]bool, len(q.terms))
+	for _, t := range q.terms {
+		terms[t] = true
+	}
+	found := make(map[string]bool, len(q.terms))
+	foundPhrases := make([]bool, len(q.phrases))
+	type scoredField struct {
+		field searchField
+		score float64
+	}
+	var matched []scoredField
+	score := 0.0
+	for _, f := range c.searchFields() {
+		tf := make(map[string]int)
+		for _, t := range f.words {
+			if terms[t.term] {
+				tf[t.term]++
+				found[t.term] = true
+			}
+		}
+		if len(tf) == 0 {
+			continue
+		}
+		s := 0.0
+		for term, n := range tf {
+			s += f.weight * idf[term] * float64(n) * (bm25K1 + 1) / (float64(n) + bm25K1)
+		}
+		for i, phrase := range q.phrases {
+			if containsPhrase(f.words, phrase) {
+				foundPhrases[i] = true
+				s += f.weight
+			}
+		}
+		score += s
+		matched = append(matched, scoredField{f, s})
+	}
+	if len(found) < len(q.terms) || v.Contains(foundPhrases, false) {
+		return SearchHit{}, false
+	}
+	title := plainText(c.Title())
+	hit := SearchHit{
+		ID:        c.ID,
+		VersionID: c.VersionID,
+		Type:      c.Type,
+		Slug:      c.Slug,
+		Status:    c.Status,
+		UpdatedAt: c.UpdatedAt,
+		Title:     title,
+		TitleHTML: highlight(title, splitWords(title), 0, len(title), terms),
+		Score:     math.Round(score*1000) / 1000,
+	}
+	// Snippets of the best-matching text (titles are highlighted already)
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].score > matched[j].score })
+	for _, m := range matched {
+		if m.field.weight != textWeight {
+			continue
+		}
+		hit.Snippets = append(hit.Snippets, m.field.snippet(terms))
+		if len(hit.Snippets) == maxSnippets {
+			break
+		}
+	}
+	return hit, true
+}
+
+// containsPhrase returns true if the words of the phrase appear consecutively.
+func containsPhrase(words []word, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		j := 0
+		for j < len(phrase) && words[i+j].term == phrase[j] {
+			j++
+		}
+		if j == len(phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// snippet excerpts the field's text around its first matching word, highlighting the matching words.
+func (f searchField) snippet(terms map[string]bool) Snippet {
+	first := 0
+	for i, t := range f.words {
+		if terms[t.term] {
+			first = i
+			break
+		}
+	}
+	from := max(0, first-snippetWords/3)
+	to := min(len(f.words), from+snippetWords)
+	from = max(0, to-snippetWords)
+	start, end := 0, len(f.text)
+	if from > 0 {
+		start = f.words[from].start
+	}
+	if to < len(f.words) {
+		end = f.words[to-1].end
+	}
+	s := highlight(f.text, f.words[from:to], start, end, terms)
+	if from > 0 {
+		s = "…" + s
+	}
+	if to < len(f.words) {
+		s += "…"
+	}
+	return Snippet{SectionID: f.sectionID, Heading: f.heading, HTML: s}
+}
+
+// highlight returns the plain text from start to end as HTML, marking the words that are search terms.
+func highlight(text string, words []word, start, end int, terms map[string]bool) string {
+	var b strings.Builder
+	at := start
+	for _, t := range words {
+		if !terms[t.term] || t.start < at || t.end > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[at:t.start]))
+		b.WriteString("<mark>" + html.EscapeString(text[t.start:t.end]) + "</mark>")
+		at = t.end
+	}
+	b.WriteString(html.EscapeString(text[at:end]))
+	return b.String()
+}
+
+// rankHits sorts search hits by descending score, and then by the most recently updated.
+func rankHits(hits []SearchHit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].UpdatedAt.After(hits[j].UpdatedAt)
+	})
+}
@@ -0,0 +1,170 @@
+package content
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"versionary-api/pkg/image"
+)
+
+func TestSearchTerms(t *testing.T) {
+	expect := assert.New(t)
+	c := Content{Body: Section{
+		Title: "Quokka Habits",
+		Text:  "<p>The quokka&#39;s smile.</p><p>Don't feed it.</p>",
+		Links: []Link{{Title: "Rottnest Island"}},
+		Sections: []Section{{
+			Images: []image.Image{{AltText: "A smiling quokka"}},
+		}},
+	}}
+	expect.Equal([]string{"dont", "feed", "habits", "island", "quokka", "quokkas", "rottnest", "smile", "smiling"}, c.SearchTerms())
+
+	q := parseQuery(`the "state of the art" quokka quokka`)
+	expect.Equal([]string{"state", "art", "quokka"}, q.terms)
+	expect.Equal([][]string{{"state", "of", "the", "art"}}, q.phrases)
+	expect.Empty(parseQuery("the and of").terms)
+}
+
+func TestSearch(t *testing.T) {
+	expect := assert.New(t)
+	create := func(c Content) Content {
+		c, _, err := service.Create(ctx, c)
+		expect.NoError(err)
+		return c
+	}
+	titled := create(Content{Type: ARTICLE, Tags: []string{"wildlife"}, Authors: []Author{{Name: "Ada"}}, Body: Section{
+		Title: "Marsupial Zephyr",
+		Text:  "<p>A field guide to the marsupial zephyr of the western plains.</p>",
+	}})
+	defer func() { _, _ = service.Delete(ctx, titled.ID) }()
+	mentioned := create(Content{Type: ARTICLE, Body: Section{
+		Title: "Western Plains",
+		Sections: []Section{{
+			Title: "Sightings",
+			Text:  "<p>Rarely, a zephyr is seen near the water at dusk, looking for a marsupial friend.</p>",
+		}},
+	}})
+	defer func() { _, _ = service.Delete(ctx, mentioned.ID) }()
+	chapter := create(Content{Type: CHAPTER, Body: Section{
+		Title: "Zephyr Chapter",
+		Text:  "<p>The marsupial zephyr, again.</p>",
+	}})
+	defer func() { _, _ = service.Delete(ctx, chapter.ID) }()
+
+	// Ranked by relevance, with highlighted snippets
+	results, err := service.Search(ctx, SearchQuery{Text: "Marsupial zephyr"})
+	if expect.NoError(err) && expect.Equal(3, results.Total) {
+		hits := results.Hits
+		expect.Contains([]string{titled.ID, chapter.ID}, hits[0].ID, "title matches rank first")
+		expect.Equal(mentioned.ID, hits[2].ID)
+		expect.Equal("<mark>Marsupial</mark> <mark>Zephyr</mark> (ARTICLE)", hitByID(hits, titled.ID).TitleHTML)
+		if s := hitByID(hits, mentioned.ID).Snippets; expect.Len(s, 1) {
+			expect.Equal("Sightings", s[0].Heading)
+			expect.Equal("Rarely, a <mark>zephyr</mark> is seen near the water at dusk, looking for a <mark>marsupial</mark> friend.", s[0].HTML)
+		}
+	}
+
+	// Phrases and filters
+	results, err = service.Search(ctx, SearchQuery{Text: `"marsupial zephyr of the western"`})
+	if expect.NoError(err) && expect.Equal(1, results.Total) {
+		expect.Equal(titled.ID, results.Hits[0].ID)
+	}
+	for _, q := range []SearchQuery{
+		{Text: "zephyr", Type: ARTICLE, Tag: "wildlife"},
+		{Text: "zephyr", Author: "Ada"},
+	} {
+		results, err = service.Search(ctx, q)
+		if expect.NoError(err) && expect.Equal(1, results.Total) {
+			expect.Equal(titled.ID, results.Hits[0].ID)
+		}
+	}
+	results, err = service.Search(ctx, SearchQuery{Text: "zephyr", Type: CHAPTER})
+	if expect.NoError(err) && expect.Equal(1, results.Total) {
+		expect.Equal(chapter.ID, results.Hits[0].ID)
+	}
+
+	// Pagination
+	results, err = service.Search(ctx, SearchQuery{Text: "zephyr", Limit: 2, Offset: 2})
+	if expect.NoError(err) {
+		expect.Equal(3, results.Total)
+		expect.Len(results.Hits, 1)
+	}
+
+	// Updates are re-indexed, and unpublished Content is found only by editors
+	mentioned.Body.Sections[0].Text = "<p>Nothing to see here.</p>"
+	_, _, err = service.Update(ctx, mentioned)
+	expect.NoError(err)
+	results, err = service.Search(ctx, SearchQuery{Text: "zephyr"})
+	if expect.NoError(err) {
+		expect.Equal(2, results.Total)
+	}
+	results, err = service.Search(ctx, SearchQuery{Text: "zephyr", Published: true})
+	if expect.NoError(err) {
+		expect.Equal(0, results.Total)
+	}
+
+	// Published searches match the publicly visible version, rather than later drafts
+	publish := func(c Content) Content {
+		c, err := service.Transition(ctx, c, IN_REVIEW, "", "Editor", "")
+		if expect.NoError(err) {
+			c, err = service.Transition(ctx, c, PUBLISHED, "", "Publisher", "")
+			expect.NoError(err)
+		}
+		return c
+	}
+	live := publish(titled)
+	draft := live
+	draft.Status = live.Status.Edited()
+	draft.Body.Text = "<p>A wombat, not a zephyr.</p>"
+	draft, _, err = service.Update(ctx, draft)
+	expect.NoError(err)
+	results, err = service.Search(ctx, SearchQuery{Text: "wombat", Published: true})
+	if expect.NoError(err) {
+		expect.Equal(0, results.Total)
+	}
+	results, err = service.Search(ctx, SearchQuery{Text: "marsupial", Type: ARTICLE, Published: true})
+	if expect.NoError(err) && expect.Equal(1, results.Total) {
+		expect.Equal(live.VersionID, results.Hits[0].VersionID)
+	}
+	live = publish(draft)
+	results, err = service.Search(ctx, SearchQuery{Text: "wombat", Author: "Ada", Published: true})
+	if expect.NoError(err) && expect.Equal(1, results.Total) {
+		expect.Equal(live.VersionID, results.Hits[0].VersionID)
+	}
+	_, err = service.Transition(ctx, live, ARCHIVED, "", "Publisher", "")
+	expect.NoError(err)
+	results, err = service.Search(ctx, SearchQuery{Text: "wombat", Published: true})
+	if expect.NoError(err) {
+		expect.Equal(0, results.Total)
+	}
+
+	_, err = service.Search(ctx, SearchQuery{Text: "of the"})
+	expect.True(errors.Is(err, ErrEmptySearch))
+}
+
+func TestSearchCandidates(t *testing.T) {
+	expect := assert.New(t)
+	for i := 0; i <= maxSearchCandidates; i++ {
+		c, _, err := service.Create(ctx, Content{Type: ARTICLE, Body: Section{Title: "Common Pangolin"}})
+		if !expect.NoError(err) {
+			return
+		}
+		defer func() { _, _ = service.Delete(ctx, c.ID) }()
+	}
+	results, err := service.Search(ctx, SearchQuery{Text: "pangolin"})
+	if expect.NoError(err) {
+		expect.Equal(maxSearchCandidates, results.Total)
+	}
+}
+
+// hitByID returns the search hit with the specified Content ID.
+func hitByID(hits []SearchHit, id string) SearchHit {
+	for _, h := range hits {
+		if h.ID == id {
+			return h
+		}
+	}
+	return SearchHit{}
+}
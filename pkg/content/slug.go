@@ -0,0 +1,100 @@
+package content
+
+import (
+	"errors"
+	"html"
+	"strings"
+	"unicode"
+)
+
+// ErrDuplicateSlug is returned when a slug is already in use by other Content of the same Type.
+var ErrDuplicateSlug = errors.New("slug is already in use")
+
+// maxSlugLength limits the length of a slug generated from a title. Longer slugs are cut at a word boundary.
+const maxSlugLength = 80
+
+// Slugify converts text (e.g. a title) into a URL-friendly slug: lower-case letters and digits, separated by
+// single hyphens. HTML tags are dropped, apostrophes are removed (e.g. "don't" becomes "dont"), and runs of
+// any other characters (spaces, punctuation, etc.) become a hyphen.
+func Slugify(text string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range html.UnescapeString(stripTags(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if hyphen && b.Len() > 0 {
+				b.WriteRune('-')
+			}
+			hyphen = false
+			b.WriteRune(unicode.ToLower(r))
+		case r == '\'' || r == '’':
+			continue
+		default:
+			hyphen = true
+		}
+	}
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+		if i := strings.LastIndexByte(slug, '-'); i > 0 {
+			slug = slug[:i]
+		}
+		slug = strings.ToValidUTF8(slug, "")
+	}
+	return slug
+}
+
+// stripTags replaces HTML tags in the text with spaces.
+func stripTags(text string) string {
+	if !strings.ContainsRune(text, '<') {
+		return text
+	}
+	var b strings.Builder
+	inTag := false
+	for _, r := range text {
+		switch {
+		case r == '<':
+			inTag = true
+			b.WriteRune(' ')
+		case r == '>' && inTag:
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// IsValidSlug returns true if the slug is non-empty, and contains only lower-case letters and digits,
+// separated by single hyphens.
+func IsValidSlug(slug string) bool {
+	if slug == "" || strings.HasPrefix(slug, "-") || strings.HasSuffix(slug, "-") || strings.Contains(slug, "--") {
+		return false
+	}
+	for _, r := range slug {
+		if r != '-' && !unicode.IsDigit(r) && !(unicode.IsLetter(r) && !unicode.IsUpper(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// SlugKey returns the key that identifies a slug among the Content of the specified Type (e.g. ARTICLE/my-title).
+func SlugKey(t Type, slug string) string {
+	if slug == "" {
+		return ""
+	}
+	return t.String() + "/" + slug
+}
+
+// SlugKeys returns the keys of the Content's slug and its aliases, which are reserved for the Content.
+func (c Content) SlugKeys() []string {
+	if c.Slug == "" {
+		return nil
+	}
+	keys := []string{SlugKey(c.Type, c.Slug)}
+	for _, alias := range c.SlugAliases {
+		keys = append(keys, SlugKey(c.Type, alias))
+	}
+	return keys
+}
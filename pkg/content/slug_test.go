@@ -0,0 +1,93 @@
+package content
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v "github.com/voxtechnica/versionary"
+)
+
+func TestSlugify(t *testing.T) {
+	expect := assert.New(t)
+	expect.Equal("the-go-programming-language", Slugify("The Go Programming Language"))
+	expect.Equal("dont-panic-a-guide", Slugify("Don't Panic: A Guide!"))
+	expect.Equal("fish-chips", Slugify("<em>Fish</em> &amp; Chips"))
+	expect.Equal("café-2024", Slugify("  Café — 2024  "))
+	expect.Empty(Slugify("!?"))
+	long := Slugify(strings.Repeat("word ", 30))
+	expect.LessOrEqual(len(long), maxSlugLength)
+	expect.False(strings.HasSuffix(long, "-"))
+
+	expect.True(IsValidSlug("my-title-2"))
+	expect.False(IsValidSlug(""))
+	expect.False(IsValidSlug("My-Title"))
+	expect.False(IsValidSlug("my--title"))
+	expect.False(IsValidSlug("-my-title"))
+	expect.False(IsValidSlug("my title"))
+	expect.Contains(Content{Slug: "Bad Slug"}.Validate(), "Slug must contain only lower-case letters and digits, separated by hyphens")
+}
+
+func TestSlugs(t *testing.T) {
+	expect := assert.New(t)
+	create := func(title, slug string) Content {
+		c, _, err := service.Create(ctx, Content{Type: ARTICLE, Slug: slug, Body: Section{Title: title, Text: "Slugs."}})
+		expect.NoError(err)
+		return c
+	}
+
+	// Generated slugs are unique per Type
+	first := create("Slug Test", "")
+	defer func() { _, _ = service.Delete(ctx, first.ID) }()
+	expect.Equal("slug-test", first.Slug)
+	second := create("Slug Test", "")
+	defer func() { _, _ = service.Delete(ctx, second.ID) }()
+	expect.Equal("slug-test-2", second.Slug)
+	category, _, err := service.Create(ctx, Content{Type: CATEGORY, Body: Section{Title: "Slug Test"}})
+	if expect.NoError(err) {
+		defer func() { _, _ = service.Delete(ctx, category.ID) }()
+		expect.Equal("slug-test", category.Slug, "other types have their own slugs")
+	}
+
+	// A supplied slug must be unused
+	_, _, err = service.Create(ctx, Content{Type: ARTICLE, Slug: "slug-test", Body: Section{Title: "Other"}})
+	expect.True(errors.Is(err, ErrDuplicateSlug))
+
+	// An omitted slug is unchanged, and a changed slug keeps the prior slug as an alias
+	first.Slug = ""
+	first.Body.Title = "Slug Test Renamed"
+	first, _, err = service.Update(ctx, first)
+	if !expect.NoError(err) {
+		return
+	}
+	expect.Equal("slug-test", first.Slug)
+	first.Slug = "slug-test-renamed"
+	first, _, err = service.Update(ctx, first)
+	if !expect.NoError(err) {
+		return
+	}
+	expect.Equal([]string{"slug-test"}, first.SlugAliases)
+	tv, err := service.ReadSlug(ctx, ARTICLE, "slug-test")
+	if expect.NoError(err) {
+		expect.Equal(first.ID, tv.Key)
+		expect.Equal("slug-test-renamed", tv.Value)
+	}
+	tv, err = service.ReadSlug(ctx, ARTICLE, "slug-test-renamed")
+	if expect.NoError(err) {
+		expect.Equal(first.ID, tv.Key)
+	}
+
+	// Aliases are reserved, but the Content may take its prior slug back
+	second.Slug = "slug-test"
+	_, _, err = service.Update(ctx, second)
+	expect.True(errors.Is(err, ErrDuplicateSlug))
+	first.Slug = "slug-test"
+	first, _, err = service.Update(ctx, first)
+	if expect.NoError(err) {
+		expect.Equal([]string{"slug-test-renamed"}, first.SlugAliases)
+	}
+
+	_, err = service.ReadSlug(ctx, ARTICLE, "no-such-slug")
+	expect.True(errors.Is(err, v.ErrNotFound))
+}
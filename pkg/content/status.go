@@ -0,0 +1,90 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Status indicates the state of a Content version in the publishing workflow.
+type Status string
+
+// DRAFT Status indicates that the Content is being written or edited.
+const DRAFT Status = "DRAFT"
+
+// IN_REVIEW Status indicates that the Content has been submitted for review before publication.
+const IN_REVIEW Status = "IN_REVIEW"
+
+// PUBLISHED Status indicates that the Content is publicly visible.
+const PUBLISHED Status = "PUBLISHED"
+
+// ARCHIVED Status indicates that the Content has been withdrawn from publication.
+const ARCHIVED Status = "ARCHIVED"
+
+// Statuses is the complete list of valid Content statuses.
+var Statuses = []Status{DRAFT, IN_REVIEW, PUBLISHED, ARCHIVED}
+
+// IsValid returns true if the supplied Status is recognized.
+func (s Status) IsValid() bool {
+	for _, v := range Statuses {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a string representation of the Status.
+func (s Status) String() string {
+	return string(s)
+}
+
+// ParseStatus returns a Status from a string representation.
+// It validates the string before returning the Status.
+func ParseStatus(s string) (Status, error) {
+	status := Status(strings.ToUpper(s))
+	if status.IsValid() {
+		return status, nil
+	}
+	return "", fmt.Errorf("invalid status: %s", s)
+}
+
+// Edited returns the Status of a new version of Content edited in this Status. Editing published or
+// archived Content starts a new draft, leaving the published version visible until the draft is published.
+func (s Status) Edited() Status {
+	if s == PUBLISHED || s == ARCHIVED || s == "" {
+		return DRAFT
+	}
+	return s
+}
+
+// ErrInvalidTransition is returned when the workflow does not permit a change from one Status to another.
+var ErrInvalidTransition = errors.New("invalid content status transition")
+
+// Transition is a permitted change of Content Status. Publishing transitions require the permission to
+// publish Content, in addition to the permission to edit it.
+type Transition struct {
+	From    Status `json:"from"`
+	To      Status `json:"to"`
+	Publish bool   `json:"publish"`
+}
+
+// Transitions is the complete list of permitted Status changes.
+var Transitions = []Transition{
+	{From: DRAFT, To: IN_REVIEW},                    // submit for review
+	{From: IN_REVIEW, To: DRAFT},                    // withdraw, or return to the editor
+	{From: IN_REVIEW, To: PUBLISHED, Publish: true}, // approve and publish
+	{From: PUBLISHED, To: ARCHIVED, Publish: true},  // withdraw from publication
+	{From: ARCHIVED, To: DRAFT, Publish: true},      // reopen for editing
+}
+
+// FindTransition returns the Transition from one Status to another, if the workflow permits it.
+// Otherwise, ErrInvalidTransition is returned.
+func FindTransition(from, to Status) (Transition, error) {
+	for _, t := range Transitions {
+		if t.From == from && t.To == to {
+			return t, nil
+		}
+	}
+	return Transition{}, fmt.Errorf("%w: from %s to %s", ErrInvalidTransition, from, to)
+}
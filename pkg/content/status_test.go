@@ -0,0 +1,96 @@
+package content
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v "github.com/voxtechnica/versionary"
+)
+
+func TestStatusTransitions(t *testing.T) {
+	expect := assert.New(t)
+	if tr, err := FindTransition(IN_REVIEW, PUBLISHED); expect.NoError(err) {
+		expect.True(tr.Publish)
+	}
+	if tr, err := FindTransition(DRAFT, IN_REVIEW); expect.NoError(err) {
+		expect.False(tr.Publish)
+	}
+	_, err := FindTransition(DRAFT, PUBLISHED)
+	expect.ErrorIs(err, ErrInvalidTransition)
+	_, err = FindTransition(ARCHIVED, PUBLISHED)
+	expect.ErrorIs(err, ErrInvalidTransition)
+	expect.Equal(DRAFT, PUBLISHED.Edited())
+	expect.Equal(IN_REVIEW, IN_REVIEW.Edited())
+	expect.True(Content{}.IsPublished(), "legacy Content without a Status")
+	_, err = ParseStatus("in_review")
+	expect.NoError(err)
+	_, err = ParseStatus("pending")
+	expect.Error(err)
+}
+
+func TestPublishingWorkflow(t *testing.T) {
+	expect := assert.New(t)
+	c, _, err := service.Create(ctx, Content{
+		Type: ARTICLE,
+		Body: Section{Title: "Workflow Article", Text: "First draft."},
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = service.Delete(ctx, c.ID) }()
+	expect.Equal(DRAFT, c.Status)
+
+	// Drafts are not published
+	_, err = service.ReadPublished(ctx, c.ID)
+	expect.True(errors.Is(err, v.ErrNotFound))
+	_, err = service.Transition(ctx, c, PUBLISHED, "", "", "")
+	expect.ErrorIs(err, ErrInvalidTransition)
+
+	// Review and publish
+	c, err = service.Transition(ctx, c, IN_REVIEW, book.EditorID, "Editor", "ready for review")
+	if !expect.NoError(err) {
+		return
+	}
+	c, err = service.Transition(ctx, c, PUBLISHED, book.EditorID, "Publisher", "approved")
+	if !expect.NoError(err) {
+		return
+	}
+	published := c.VersionID
+	expect.Equal("approved", c.Comment)
+	if titles, err := service.ReadAllTitlesByStatus(ctx, PUBLISHED.String(), false); expect.NoError(err) {
+		expect.Contains(titleIDs(titles), c.ID)
+	}
+
+	// A new draft leaves the published version visible
+	c.Status = c.Status.Edited()
+	c.Body.Text = "Second draft."
+	c, _, err = service.Update(ctx, c)
+	if !expect.NoError(err) {
+		return
+	}
+	if pub, err := service.ReadPublished(ctx, c.ID); expect.NoError(err) {
+		expect.Equal(published, pub.VersionID)
+		expect.Equal("First draft.", pub.Body.Text)
+	}
+	if titles, err := service.ReadAllTitlesByStatus(ctx, DRAFT.String(), false); expect.NoError(err) {
+		expect.Contains(titleIDs(titles), c.ID)
+	}
+	if statuses, err := service.ReadAllStatuses(ctx); expect.NoError(err) {
+		expect.Contains(statuses, DRAFT.String())
+	}
+
+	// Archived Content is no longer published
+	c, _ = service.Transition(ctx, c, IN_REVIEW, "", "", "")
+	c, _ = service.Transition(ctx, c, PUBLISHED, "", "", "")
+	c, err = service.Transition(ctx, c, ARCHIVED, "", "", "")
+	if expect.NoError(err) {
+		_, err = service.ReadPublished(ctx, c.ID)
+		expect.True(errors.Is(err, v.ErrNotFound))
+	}
+}
+
+// titleIDs returns the keys (Content IDs) of the TextValues.
+func titleIDs(titles []v.TextValue) []string {
+	return v.Map(titles, func(tv v.TextValue) string { return tv.Key })
+}
@@ -0,0 +1,186 @@
+package content
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EditOp is the kind of a TextEdit: text that is unchanged, inserted, or deleted.
+type EditOp string
+
+// EQUAL EditOp indicates text that is in both versions.
+const EQUAL EditOp = "EQUAL"
+
+// INSERT EditOp indicates text that is only in the newer version.
+const INSERT EditOp = "INSERT"
+
+// DELETE EditOp indicates text that is only in the older version.
+const DELETE EditOp = "DELETE"
+
+// TextEdit is a run of text (or HTML) that is unchanged, inserted, or deleted.
+type TextEdit struct {
+	Op   EditOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// maxDiffCells limits the size of the table used to compare two texts (after removing the common prefix and
+// suffix). Larger changes are reported as a replacement of the whole changed region.
+const maxDiffCells = 4_000_000
+
+// DiffWords compares two texts word by word, returning the edits that turn the first into the second.
+// HTML tags are compared as whole tokens, so that markup changes don't garble the words around them.
+// Identical texts produce a single EQUAL edit (or none, if they're empty).
+func DiffWords(from, to string) []TextEdit {
+	a, b := tokenize(from), tokenize(to)
+	// Trim the common prefix and suffix
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	var edits []TextEdit
+	edits = appendEdit(edits, EQUAL, a[:prefix]...)
+	edits = append(edits, diffTokens(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	edits = appendEdit(edits, EQUAL, a[len(a)-suffix:]...)
+	return mergeEdits(edits)
+}
+
+// diffTokens compares two token lists using a longest common subsequence table.
+func diffTokens(a, b []string) []TextEdit {
+	var edits []TextEdit
+	if len(a) == 0 || len(b) == 0 || len(a)*len(b) > maxDiffCells {
+		edits = appendEdit(edits, DELETE, a...)
+		return appendEdit(edits, INSERT, b...)
+	}
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			edits = appendEdit(edits, EQUAL, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = appendEdit(edits, DELETE, a[i])
+			i++
+		default:
+			edits = appendEdit(edits, INSERT, b[j])
+			j++
+		}
+	}
+	edits = appendEdit(edits, DELETE, a[i:]...)
+	return appendEdit(edits, INSERT, b[j:]...)
+}
+
+// appendEdit appends tokens to the list of edits, extending the last edit if it has the same operation.
+func appendEdit(edits []TextEdit, op EditOp, tokens ...string) []TextEdit {
+	if len(tokens) == 0 {
+		return edits
+	}
+	text := strings.Join(tokens, "")
+	if n := len(edits); n > 0 && edits[n-1].Op == op {
+		edits[n-1].Text += text
+		return edits
+	}
+	return append(edits, TextEdit{Op: op, Text: text})
+}
+
+// mergeEdits combines adjacent edits with the same operation.
+func mergeEdits(edits []TextEdit) []TextEdit {
+	var merged []TextEdit
+	for _, e := range edits {
+		merged = appendEdit(merged, e.Op, e.Text)
+	}
+	return merged
+}
+
+// tokenize splits text into HTML tags, words, runs of whitespace, and individual punctuation characters.
+// Entities (e.g. &amp;) are kept together with the surrounding word.
+func tokenize(s string) []string {
+	var tokens []string
+	for len(s) > 0 {
+		n := 0
+		r, size := utf8.DecodeRuneInString(s)
+		switch {
+		case s[0] == '<':
+			if end := strings.IndexByte(s, '>'); end >= 0 {
+				n = end + 1
+			} else {
+				n = len(s)
+			}
+		case unicode.IsSpace(r):
+			n = strings.IndexFunc(s, func(r rune) bool { return !unicode.IsSpace(r) })
+		case isWordRune(r):
+			n = strings.IndexFunc(s, func(r rune) bool { return !isWordRune(r) })
+		default:
+			n = size
+		}
+		if n <= 0 {
+			n = len(s)
+		}
+		tokens = append(tokens, s[:n])
+		s = s[n:]
+	}
+	return tokens
+}
+
+// isWordRune returns true if the rune is part of a word (including HTML entities, like &amp;).
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '&' || r == '#' || r == ';' || r == '\'' || r == '_'
+}
+
+// EditsHTML renders text edits for display, marking inserted words with <ins> and deleted words with <del>.
+// Inserted tags are kept, so that the result has the structure of the newer version; deleted tags are dropped.
+// The text is expected to be sanitized already, as it is in saved Content (titles are HTML-escaped).
+func EditsHTML(edits []TextEdit) string {
+	var b strings.Builder
+	for _, e := range edits {
+		if e.Op == EQUAL {
+			b.WriteString(e.Text)
+			continue
+		}
+		tag := "ins"
+		if e.Op == DELETE {
+			tag = "del"
+		}
+		open := false
+		for _, t := range tokenize(e.Text) {
+			if strings.HasPrefix(t, "<") {
+				if open {
+					b.WriteString("</" + tag + ">")
+					open = false
+				}
+				if e.Op == INSERT {
+					b.WriteString(t)
+				}
+				continue
+			}
+			if !open {
+				b.WriteString("<" + tag + ">")
+				open = true
+			}
+			b.WriteString(t)
+		}
+		if open {
+			b.WriteString("</" + tag + ">")
+		}
+	}
+	return b.String()
+}
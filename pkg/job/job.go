@@ -0,0 +1,163 @@
+package job
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"versionary-api/pkg/ref"
+
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+// Status indicates the progress of a Job.
+type Status string
+
+// PENDING Status indicates that the Job has been created, but has not yet started.
+const PENDING Status = "PENDING"
+
+// RUNNING Status indicates that the Job is in progress.
+const RUNNING Status = "RUNNING"
+
+// SUCCEEDED Status indicates that the Job finished without errors.
+const SUCCEEDED Status = "SUCCEEDED"
+
+// FAILED Status indicates that the Job finished, but one or more items could not be processed,
+// or the Job could not be completed.
+const FAILED Status = "FAILED"
+
+// Statuses is the complete list of valid Job statuses.
+var Statuses = []Status{PENDING, RUNNING, SUCCEEDED, FAILED}
+
+// IsValid returns true if the supplied Status is recognized.
+func (s Status) IsValid() bool {
+	for _, v := range Statuses {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a string representation of the Status.
+func (s Status) String() string {
+	return string(s)
+}
+
+// Kinds of Jobs
+const (
+	OrgRename       = "org_rename"       // propagate an Organization rename to denormalized names
+	Consistency     = "consistency"      // find and fix stale denormalized names
+	ContentSchedule = "content_schedule" // publish and unpublish Content on schedule
+)
+
+// MaxErrors limits the number of error messages retained in a Job.
+const MaxErrors = 20
+
+// Job records the progress of a long-running task, such as updating the Users of a renamed Organization.
+// Jobs are not versioned; progress overwrites the Job. They're retained for 30 days.
+type Job struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Kind       string    `json:"kind"`
+	UserID     string    `json:"userId,omitempty"`     // who started the Job, if anyone
+	EntityType string    `json:"entityType,omitempty"` // subject of the Job, if any (e.g. Organization)
+	EntityID   string    `json:"entityId,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	Status     Status    `json:"status"`
+	Total      int       `json:"total"`     // items found so far
+	Processed  int       `json:"processed"` // items checked
+	Stale      int       `json:"stale"`     // items found to need an update
+	Changed    int       `json:"changed"`   // items updated
+	Failed     int       `json:"failed"`    // items that could not be updated
+	Errors     []string  `json:"errors,omitempty"`
+	StartedAt  time.Time `json:"startedAt,omitempty"`
+	FinishedAt time.Time `json:"finishedAt,omitempty"`
+}
+
+// Type returns the entity type of the Job.
+func (j Job) Type() string {
+	return "Job"
+}
+
+// RefID returns the Reference ID of the entity.
+func (j Job) RefID() ref.RefID {
+	r, _ := ref.NewRefID(j.Type(), j.ID, "")
+	return r
+}
+
+// CompressedJSON returns a compressed JSON representation of the Job.
+func (j Job) CompressedJSON() []byte {
+	b, err := v.ToCompressedJSON(j)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// Done returns true if the Job has finished, successfully or not.
+func (j Job) Done() bool {
+	return j.Status == SUCCEEDED || j.Status == FAILED
+}
+
+// Start marks the Job as running.
+func (j *Job) Start() {
+	j.Status = RUNNING
+	j.StartedAt = time.Now()
+}
+
+// Fail records an item that could not be processed, retaining up to MaxErrors messages.
+func (j *Job) Fail(err error) {
+	j.Failed++
+	if len(j.Errors) < MaxErrors {
+		j.Errors = append(j.Errors, err.Error())
+	}
+}
+
+// Finish marks the Job as finished. It has failed if the supplied error is not nil, or if any items failed.
+func (j *Job) Finish(err error) {
+	if err != nil && len(j.Errors) < MaxErrors {
+		j.Errors = append(j.Errors, err.Error())
+	}
+	if err != nil || j.Failed > 0 {
+		j.Status = FAILED
+	} else {
+		j.Status = SUCCEEDED
+	}
+	j.FinishedAt = time.Now()
+}
+
+// String returns a brief summary of the Job's progress.
+func (j Job) String() string {
+	return fmt.Sprintf("%s Job %s %s: %d processed, %d stale, %d changed, %d failed",
+		j.Kind, j.ID, j.Status, j.Processed, j.Stale, j.Changed, j.Failed)
+}
+
+// Validate checks whether the Job has all required fields and whether the supplied values are valid,
+// returning a list of problems. If the list is empty, then the Job is valid.
+func (j Job) Validate() []string {
+	var problems []string
+	if j.ID == "" || !tuid.IsValid(tuid.TUID(j.ID)) {
+		problems = append(problems, "ID is missing or invalid")
+	}
+	if j.CreatedAt.IsZero() {
+		problems = append(problems, "CreatedAt is missing")
+	}
+	if j.UpdatedAt.IsZero() {
+		problems = append(problems, "UpdatedAt is missing")
+	}
+	if j.ExpiresAt.IsZero() {
+		problems = append(problems, "ExpiresAt is missing")
+	}
+	if j.Kind == "" {
+		problems = append(problems, "Kind is missing")
+	}
+	if j.Status == "" || !j.Status.IsValid() {
+		statuses := v.Map(Statuses, func(s Status) string { return string(s) })
+		problems = append(problems, "Status is missing or invalid. Expecting: "+strings.Join(statuses, ", "))
+	}
+	return problems
+}
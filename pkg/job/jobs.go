@@ -0,0 +1,158 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+// Lifetime is how long a Job is retained after it's created.
+const Lifetime = 30 * 24 * time.Hour
+
+//==============================================================================
+// Job Table
+//==============================================================================
+
+// rowJobs is a TableRow definition for Jobs. Jobs are not versioned.
+var rowJobs = v.TableRow[Job]{
+	RowName:      "jobs",
+	PartKeyName:  "id",
+	PartKeyValue: func(j Job) string { return j.ID },
+	PartKeyLabel: func(j Job) string { return j.Kind },
+	SortKeyName:  "id",
+	SortKeyValue: func(j Job) string { return j.ID },
+	JsonValue:    func(j Job) []byte { return j.CompressedJSON() },
+	TimeToLive:   func(j Job) int64 { return j.ExpiresAt.Unix() },
+}
+
+// rowJobsEntity is a TableRow definition for Jobs by subject Entity ID (e.g. the renamed Organization).
+var rowJobsEntity = v.TableRow[Job]{
+	RowName:      "jobs_entity",
+	PartKeyName:  "entity_id",
+	PartKeyValue: func(j Job) string { return j.EntityID },
+	PartKeyLabel: func(j Job) string { return j.EntityType },
+	SortKeyName:  "id",
+	SortKeyValue: func(j Job) string { return j.ID },
+	JsonValue:    func(j Job) []byte { return j.CompressedJSON() },
+	TimeToLive:   func(j Job) int64 { return j.ExpiresAt.Unix() },
+}
+
+// NewTable instantiates a new DynamoDB table for Jobs.
+func NewTable(dbClient *dynamodb.Client, env string) v.Table[Job] {
+	if env == "" {
+		env = "dev"
+	}
+	return v.Table[Job]{
+		Client:     dbClient,
+		EntityType: "Job",
+		TableName:  "jobs" + "_" + env,
+		TTL:        true,
+		EntityRow:  rowJobs,
+		IndexRows: map[string]v.TableRow[Job]{
+			rowJobsEntity.RowName: rowJobsEntity,
+		},
+	}
+}
+
+// NewMemTable creates an in-memory Job table for testing purposes.
+func NewMemTable(table v.Table[Job]) v.MemTable[Job] {
+	return v.NewMemTable(table)
+}
+
+//==============================================================================
+// Job Service
+//==============================================================================
+
+// Service is used to manage Jobs in a DynamoDB table.
+type Service struct {
+	EntityType string
+	Table      v.TableReadWriter[Job]
+}
+
+// NewService creates a new Job service backed by a Versionary Table for the specified environment.
+func NewService(dbClient *dynamodb.Client, env string) Service {
+	table := NewTable(dbClient, env)
+	return Service{
+		EntityType: table.EntityType,
+		Table:      table,
+	}
+}
+
+// NewMockService creates a new Job service backed by an in-memory table for testing purposes.
+func NewMockService(env string) Service {
+	table := NewMemTable(NewTable(nil, env))
+	return Service{
+		EntityType: table.EntityType,
+		Table:      table,
+	}
+}
+
+//------------------------------------------------------------------------------
+// Jobs
+//------------------------------------------------------------------------------
+
+// Create a PENDING Job in the Job table.
+func (s Service) Create(ctx context.Context, j Job) (Job, []string, error) {
+	id := tuid.NewID()
+	at, _ := id.Time()
+	j.ID = id.String()
+	j.CreatedAt = at
+	j.UpdatedAt = at
+	j.ExpiresAt = at.Add(Lifetime)
+	j.Status = PENDING
+	problems := j.Validate()
+	if len(problems) > 0 {
+		return j, problems, fmt.Errorf("error creating %s %s: invalid field(s): %s", s.EntityType, j.ID, strings.Join(problems, ", "))
+	}
+	if err := s.Table.WriteEntity(ctx, j); err != nil {
+		return j, problems, fmt.Errorf("error creating %s %s %s: %w", s.EntityType, j.ID, j.Kind, err)
+	}
+	return j, problems, nil
+}
+
+// Update a Job in the Job table, recording its progress.
+func (s Service) Update(ctx context.Context, j Job) (Job, error) {
+	j.UpdatedAt = time.Now()
+	if problems := j.Validate(); len(problems) > 0 {
+		return j, fmt.Errorf("error updating %s %s: invalid field(s): %s", s.EntityType, j.ID, strings.Join(problems, ", "))
+	}
+	if err := s.Table.WriteEntity(ctx, j); err != nil {
+		return j, fmt.Errorf("error updating %s %s %s: %w", s.EntityType, j.ID, j.Kind, err)
+	}
+	return j, nil
+}
+
+// Delete a Job from the Job table. The deleted Job is returned.
+func (s Service) Delete(ctx context.Context, id string) (Job, error) {
+	return s.Table.DeleteEntityWithID(ctx, id)
+}
+
+// Exists checks if a Job exists in the Job table.
+func (s Service) Exists(ctx context.Context, id string) bool {
+	return s.Table.EntityExists(ctx, id)
+}
+
+// Read a specified Job from the Job table.
+func (s Service) Read(ctx context.Context, id string) (Job, error) {
+	return s.Table.ReadEntity(ctx, id)
+}
+
+// ReadJobs returns a paginated list of Jobs in the Job table.
+// Sorting is chronological (or reverse). The offset is the last ID returned in a previous request.
+func (s Service) ReadJobs(ctx context.Context, reverse bool, limit int, offset string) []Job {
+	ids, err := s.Table.ReadEntityIDs(ctx, reverse, limit, offset)
+	if err != nil {
+		return []Job{}
+	}
+	return s.Table.ReadEntities(ctx, ids)
+}
+
+// ReadAllJobsByEntityID returns all Jobs about the specified entity, in chronological order.
+func (s Service) ReadAllJobsByEntityID(ctx context.Context, entityID string) ([]Job, error) {
+	return s.Table.ReadAllEntitiesFromRow(ctx, rowJobsEntity, entityID)
+}
@@ -0,0 +1,95 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/voxtechnica/tuid-go"
+)
+
+var (
+	ctx     = context.Background()
+	service = NewMockService("test")
+)
+
+func TestValidate(t *testing.T) {
+	expect := assert.New(t)
+	j := Job{}
+	expect.Len(j.Validate(), 6)
+	j, problems, err := service.Create(ctx, Job{})
+	expect.Error(err)
+	expect.Equal([]string{"Kind is missing"}, problems)
+}
+
+func TestProgress(t *testing.T) {
+	expect := assert.New(t)
+	j := Job{Kind: Consistency}
+	j.Start()
+	expect.Equal(RUNNING, j.Status)
+	expect.False(j.Done())
+	for i := 0; i < MaxErrors+5; i++ {
+		j.Fail(fmt.Errorf("item %d", i))
+	}
+	expect.Equal(MaxErrors+5, j.Failed)
+	expect.Len(j.Errors, MaxErrors)
+	j.Finish(nil)
+	expect.Equal(FAILED, j.Status)
+	expect.True(j.Done())
+
+	j = Job{Kind: Consistency}
+	j.Finish(nil)
+	expect.Equal(SUCCEEDED, j.Status)
+	j = Job{Kind: Consistency}
+	j.Finish(errors.New("interrupted"))
+	expect.Equal(FAILED, j.Status)
+	expect.Equal([]string{"interrupted"}, j.Errors)
+}
+
+func TestCreateReadUpdateDelete(t *testing.T) {
+	expect := assert.New(t)
+	if !service.Table.IsValid() {
+		t.Fatal("invalid table configuration")
+	}
+	orgID := tuid.NewID().String()
+	j, problems, err := service.Create(ctx, Job{
+		Kind:       OrgRename,
+		EntityType: "Organization",
+		EntityID:   orgID,
+	})
+	expect.Empty(problems)
+	if !expect.NoError(err) {
+		return
+	}
+	expect.Equal(PENDING, j.Status)
+	expect.Equal(j.CreatedAt.Add(Lifetime), j.ExpiresAt)
+	expect.True(service.Exists(ctx, j.ID))
+
+	// Record progress
+	j.Start()
+	j.Total, j.Processed, j.Stale, j.Changed = 10, 5, 2, 2
+	j, err = service.Update(ctx, j)
+	if expect.NoError(err) {
+		check, err := service.Read(ctx, j.ID)
+		if expect.NoError(err) {
+			expect.Equal(RUNNING, check.Status)
+			expect.Equal(5, check.Processed)
+			expect.Equal(2, check.Changed)
+		}
+	}
+
+	// Read Jobs about the Organization
+	jobs, err := service.ReadAllJobsByEntityID(ctx, orgID)
+	if expect.NoError(err) && expect.Len(jobs, 1) {
+		expect.Equal(j.ID, jobs[0].ID)
+	}
+	jobs = service.ReadJobs(ctx, false, 10, tuid.MinID)
+	expect.Len(jobs, 1)
+
+	// Delete the Job
+	_, err = service.Delete(ctx, j.ID)
+	expect.NoError(err)
+	expect.False(service.Exists(ctx, j.ID))
+}
@@ -0,0 +1,132 @@
+package org
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DomainChallengeLifetime is the duration for which a domain verification challenge may be completed.
+const DomainChallengeLifetime = 7 * 24 * time.Hour
+
+// DomainChallengePrefix is prepended to a domain to name the DNS TXT record holding its challenge value.
+const DomainChallengePrefix = "_versionary-challenge."
+
+// domainChallengeBytes is the number of random bytes in a domain challenge value.
+const domainChallengeBytes = 24
+
+// ErrInvalidDomain is returned when an email domain is not a valid DNS name.
+var ErrInvalidDomain = errors.New("invalid domain")
+
+// ErrDomainChallengeNotFound is returned when verifying a domain without an open challenge.
+var ErrDomainChallengeNotFound = errors.New("domain challenge not found")
+
+// ErrDomainNotVerified is returned when the DNS TXT record does not hold the challenge value.
+var ErrDomainNotVerified = errors.New("domain challenge record not found")
+
+// ErrDomainVerified is returned when the Organization has already verified the domain.
+var ErrDomainVerified = errors.New("domain is already verified")
+
+// ErrDomainTaken is returned when the domain has already been verified by another Organization.
+var ErrDomainTaken = errors.New("domain is verified by another organization")
+
+// domainPattern is the required form of a (lowercase) domain name, with at least two labels.
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// StandardizeDomain returns a lowercase domain name, without surrounding whitespace, a leading "@",
+// or a trailing ".".
+func StandardizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.TrimLeft(strings.ToLower(strings.TrimSpace(domain)), "@"), ".")
+}
+
+// ValidDomain returns true if the (standardized) domain name is valid.
+func ValidDomain(domain string) bool {
+	return len(domain) <= 253 && domainPattern.MatchString(domain)
+}
+
+// EmailDomains returns the domain of an email address and its parent domains, most specific first,
+// excluding the top-level domain (e.g. "eng.example.com", "example.com").
+func EmailDomains(address string) []string {
+	_, domain, found := strings.Cut(strings.ToLower(strings.TrimSpace(address)), "@")
+	if !found {
+		return nil
+	}
+	var domains []string
+	for strings.Contains(domain, ".") {
+		domains = append(domains, domain)
+		_, domain, _ = strings.Cut(domain, ".")
+	}
+	return domains
+}
+
+// DomainChallenge is an open request to verify an Organization's ownership of an email domain.
+// Ownership is proven by publishing the Value in a DNS TXT record named RecordName.
+type DomainChallenge struct {
+	Domain     string    `json:"domain"`
+	RecordName string    `json:"recordName"`
+	Value      string    `json:"value"`
+	CreatedAt  time.Time `json:"createdAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// NewDomainChallenge generates a new random challenge for the (standardized) domain.
+func NewDomainChallenge(domain string, at time.Time) (DomainChallenge, error) {
+	if !ValidDomain(domain) {
+		return DomainChallenge{}, fmt.Errorf("%w: %q", ErrInvalidDomain, domain)
+	}
+	b := make([]byte, domainChallengeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return DomainChallenge{}, fmt.Errorf("error generating domain challenge: %w", err)
+	}
+	return DomainChallenge{
+		Domain:     domain,
+		RecordName: DomainChallengePrefix + domain,
+		Value:      "versionary-domain-verification=" + base64.RawURLEncoding.EncodeToString(b),
+		CreatedAt:  at,
+		ExpiresAt:  at.Add(DomainChallengeLifetime),
+	}, nil
+}
+
+// Verify checks the challenge's DNS TXT record, using the supplied resolver, at the specified time.
+func (dc DomainChallenge) Verify(ctx context.Context, r TXTResolver, at time.Time) error {
+	if !dc.ExpiresAt.After(at) {
+		return fmt.Errorf("%w: %s challenge expired at %s", ErrDomainChallengeNotFound, dc.Domain, dc.ExpiresAt.Format(time.RFC3339))
+	}
+	records, err := r.LookupTXT(ctx, dc.RecordName)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return fmt.Errorf("%w: %s", ErrDomainNotVerified, dc.RecordName)
+		}
+		return fmt.Errorf("error looking up %s: %w", dc.RecordName, err)
+	}
+	for _, record := range records {
+		if strings.TrimSpace(record) == dc.Value {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrDomainNotVerified, dc.RecordName)
+}
+
+// TXTResolver looks up DNS TXT records. The net.DefaultResolver is used in production; tests may use a
+// StaticResolver instead.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// StaticResolver is a TXTResolver that answers from a map of record names to values, for testing purposes.
+type StaticResolver map[string][]string
+
+// LookupTXT returns the TXT records with the specified name, or a not-found DNS error.
+func (r StaticResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if records, ok := r[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
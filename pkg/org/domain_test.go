@@ -0,0 +1,117 @@
+package org
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v "github.com/voxtechnica/versionary"
+)
+
+func TestDomainNames(t *testing.T) {
+	expect := assert.New(t)
+	expect.Equal("example.com", StandardizeDomain(" @Example.COM. "))
+	expect.True(ValidDomain("example.com"))
+	expect.True(ValidDomain("mail-1.example.co.uk"))
+	expect.False(ValidDomain("com"))
+	expect.False(ValidDomain("-bad.example.com"))
+	expect.False(ValidDomain("bad_domain.com"))
+	expect.Equal([]string{"eng.example.com", "example.com"}, EmailDomains("Someone@Eng.Example.com"))
+	expect.Nil(EmailDomains("someone"))
+}
+
+func TestDomainChallengeVerify(t *testing.T) {
+	expect := assert.New(t)
+	now := time.Now()
+	dc, err := NewDomainChallenge("example.com", now)
+	if !expect.NoError(err) {
+		return
+	}
+	expect.Equal("_versionary-challenge.example.com", dc.RecordName)
+	expect.NotEmpty(dc.Value)
+	r := StaticResolver{}
+	expect.ErrorIs(dc.Verify(ctx, r, now), ErrDomainNotVerified)
+	r[dc.RecordName] = []string{"some other record"}
+	expect.ErrorIs(dc.Verify(ctx, r, now), ErrDomainNotVerified)
+	r[dc.RecordName] = []string{"some other record", dc.Value}
+	expect.NoError(dc.Verify(ctx, r, now))
+	expect.ErrorIs(dc.Verify(ctx, r, now.Add(DomainChallengeLifetime)), ErrDomainChallengeNotFound)
+	_, err = NewDomainChallenge("not a domain", now)
+	expect.ErrorIs(err, ErrInvalidDomain)
+}
+
+func TestDomainVerification(t *testing.T) {
+	expect := assert.New(t)
+	r := StaticResolver{}
+	o, _, err := service.Create(ctx, Organization{Name: "Domain Owner", Status: ENABLED})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = service.Delete(ctx, o.ID) }()
+	other, _, err := service.Create(ctx, Organization{Name: "Domain Rival", Status: ENABLED})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = service.Delete(ctx, other.ID) }()
+
+	// Start verifying a domain
+	o, dc, err := service.CreateDomainChallenge(ctx, o, "Owner.example.com")
+	if !expect.NoError(err) {
+		return
+	}
+	expect.Equal("owner.example.com", dc.Domain)
+	if expect.Len(o.DomainChallenges, 1) {
+		expect.Equal(dc.Value, o.DomainChallenges[0].Value)
+	}
+	_, err = service.VerifyDomain(ctx, r, o, "unknown.example.com")
+	expect.ErrorIs(err, ErrDomainChallengeNotFound)
+	_, err = service.VerifyDomain(ctx, r, o, dc.Domain)
+	expect.ErrorIs(err, ErrDomainNotVerified)
+
+	// Publish the DNS TXT record, and verify the domain
+	r[dc.RecordName] = []string{dc.Value}
+	o, err = service.VerifyDomain(ctx, r, o, dc.Domain)
+	if !expect.NoError(err) {
+		return
+	}
+	expect.Equal([]string{"owner.example.com"}, o.Domains)
+	expect.Empty(o.DomainChallenges)
+	_, _, err = service.CreateDomainChallenge(ctx, o, dc.Domain)
+	expect.ErrorIs(err, ErrDomainVerified)
+
+	// Another Organization may not claim the domain
+	_, _, err = service.CreateDomainChallenge(ctx, other, dc.Domain)
+	expect.ErrorIs(err, ErrDomainTaken)
+
+	// Look up the Organization by domain or email address
+	if check, err := service.ReadOrganizationByDomain(ctx, "OWNER.example.com"); expect.NoError(err) {
+		expect.Equal(o.ID, check.ID)
+	}
+	if check, err := service.ReadOrganizationByEmail(ctx, "someone@eng.owner.example.com"); expect.NoError(err) {
+		expect.Equal(o.ID, check.ID)
+	}
+	_, err = service.ReadOrganizationByEmail(ctx, "someone@example.com")
+	expect.True(errors.Is(err, v.ErrNotFound))
+	if domains, err := service.ReadAllDomains(ctx); expect.NoError(err) {
+		expect.Contains(domains, "owner.example.com")
+	}
+
+	// Disabled Organizations are not joined automatically
+	o.Status = DISABLED
+	o, _, err = service.Update(ctx, o)
+	if expect.NoError(err) {
+		_, err = service.ReadOrganizationByEmail(ctx, "someone@owner.example.com")
+		expect.True(errors.Is(err, v.ErrNotFound))
+	}
+
+	// Remove the domain
+	o, err = service.RemoveDomain(ctx, o, dc.Domain)
+	if expect.NoError(err) {
+		expect.Empty(o.Domains)
+		_, err = service.ReadOrganizationByDomain(ctx, dc.Domain)
+		expect.True(errors.Is(err, v.ErrNotFound))
+	}
+	_, err = service.RemoveDomain(ctx, o, dc.Domain)
+	expect.True(errors.Is(err, v.ErrNotFound))
+}
@@ -0,0 +1,95 @@
+package org
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHierarchy(t *testing.T) {
+	expect := assert.New(t)
+	create := func(name, parentID string) Organization {
+		o, _, err := service.Create(ctx, Organization{Name: name, Status: ENABLED, ParentID: parentID})
+		expect.NoError(err)
+		return o
+	}
+	root := create("Hierarchy Root", "")
+	defer func() { _, _ = service.Delete(ctx, root.ID) }()
+	division := create("Hierarchy Division", root.ID)
+	defer func() { _, _ = service.Delete(ctx, division.ID) }()
+	team := create("Hierarchy Team", division.ID)
+	defer func() { _, _ = service.Delete(ctx, team.ID) }()
+	other := create("Hierarchy Other Division", root.ID)
+	defer func() { _, _ = service.Delete(ctx, other.ID) }()
+
+	// Children are listed by parent
+	if children, err := service.ReadChildren(ctx, root.ID); expect.NoError(err) {
+		expect.Equal([]string{division.ID, other.ID}, orgIDs(children))
+	}
+	if parentIDs, err := service.ReadAllParentIDs(ctx); expect.NoError(err) {
+		expect.Subset(parentIDs, []string{root.ID, division.ID})
+	}
+
+	// Ancestors start with the parent, and end with the root
+	if ancestors, err := service.ReadAncestors(ctx, team); expect.NoError(err) {
+		expect.Equal([]string{division.ID, root.ID}, orgIDs(ancestors))
+	}
+	if ancestors, err := service.ReadAncestors(ctx, root); expect.NoError(err) {
+		expect.Empty(ancestors)
+	}
+
+	// The subtree includes every descendant
+	if tree, err := service.ReadSubtree(ctx, root); expect.NoError(err) {
+		expect.Equal([]string{root.ID, division.ID, team.ID, other.ID}, tree.IDs())
+		expect.Equal(3, tree.Height())
+	}
+	if ids, err := service.ReadDescendantIDs(ctx, division.ID); expect.NoError(err) {
+		expect.Equal([]string{team.ID}, ids)
+	}
+
+	// An Organization may not become its own ancestor, or have a missing parent
+	root.ParentID = team.ID
+	_, problems, err := service.Update(ctx, root)
+	expect.ErrorIs(err, ErrHierarchyCycle)
+	expect.NotEmpty(problems)
+	root.ParentID = root.ID
+	_, problems, _ = service.Update(ctx, root)
+	expect.Contains(problems, "ParentID is invalid")
+	_, _, err = service.Create(ctx, Organization{Name: "Orphan", ParentID: "9G5rgTk3bxHxM8Ak"})
+	expect.ErrorIs(err, ErrInvalidParent)
+
+	// Moving a branch updates the hierarchy
+	team.ParentID = other.ID
+	if team, _, err = service.Update(ctx, team); expect.NoError(err) {
+		if children, err := service.ReadChildren(ctx, division.ID); expect.NoError(err) {
+			expect.Empty(children)
+		}
+		if ancestors, err := service.ReadAncestors(ctx, team); expect.NoError(err) {
+			expect.Equal([]string{other.ID, root.ID}, orgIDs(ancestors))
+		}
+	}
+}
+
+func TestHierarchyDepth(t *testing.T) {
+	expect := assert.New(t)
+	parentID := ""
+	for i := 0; i < MaxDepth; i++ {
+		o, _, err := service.Create(ctx, Organization{Name: "Level", Status: ENABLED, ParentID: parentID})
+		if !expect.NoError(err) {
+			return
+		}
+		defer func(id string) { _, _ = service.Delete(ctx, id) }(o.ID)
+		parentID = o.ID
+	}
+	_, _, err := service.Create(ctx, Organization{Name: "Too Deep", Status: ENABLED, ParentID: parentID})
+	expect.ErrorIs(err, ErrInvalidParent)
+}
+
+// orgIDs returns the IDs of the Organizations.
+func orgIDs(orgs []Organization) []string {
+	ids := make([]string, len(orgs))
+	for i, o := range orgs {
+		ids[i] = o.ID
+	}
+	return ids
+}
@@ -0,0 +1,188 @@
+package org
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"versionary-api/pkg/ref"
+
+	"github.com/voxtechnica/tuid-go"
+	"github.com/voxtechnica/versionary"
+)
+
+// InvitationLifetime is the default duration for which an Invitation may be accepted.
+const InvitationLifetime = 7 * 24 * time.Hour
+
+// invitationTokenBytes is the number of random bytes in an invitation token.
+const invitationTokenBytes = 32
+
+// ErrInvalidInvitation is returned when an invitation token does not match the Invitation.
+var ErrInvalidInvitation = errors.New("invalid invitation token")
+
+// ErrExpiredInvitation is returned when an Invitation has expired.
+var ErrExpiredInvitation = errors.New("expired invitation")
+
+// ErrClosedInvitation is returned when an Invitation has already been accepted or revoked.
+var ErrClosedInvitation = errors.New("invitation is no longer open")
+
+// InvitationStatus indicates the state of an Invitation.
+type InvitationStatus string
+
+// INVITED InvitationStatus indicates that the Invitation has been sent, and may be accepted
+const INVITED InvitationStatus = "INVITED"
+
+// ACCEPTED InvitationStatus indicates that the invited person has joined the Organization
+const ACCEPTED InvitationStatus = "ACCEPTED"
+
+// REVOKED InvitationStatus indicates that the Invitation was withdrawn before it was accepted
+const REVOKED InvitationStatus = "REVOKED"
+
+// InvitationStatuses is the complete list of valid Invitation statuses
+var InvitationStatuses = []InvitationStatus{INVITED, ACCEPTED, REVOKED}
+
+// IsValid returns true if the supplied InvitationStatus is recognized
+func (s InvitationStatus) IsValid() bool {
+	for _, v := range InvitationStatuses {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a string representation of the InvitationStatus
+func (s InvitationStatus) String() string {
+	return string(s)
+}
+
+// Invitation invites a person, by email address, to join an Organization with the specified roles.
+// The invitation is delivered with a link containing a secret token; only a hash of the token is stored.
+type Invitation struct {
+	ID          string           `json:"id"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	VersionID   string           `json:"versionID"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+	ExpiresAt   time.Time        `json:"expiresAt"`
+	OrgID       string           `json:"orgId"`
+	OrgName     string           `json:"orgName,omitempty"`
+	Email       string           `json:"email"`
+	Roles       []string         `json:"roles,omitempty"`
+	InviterID   string           `json:"inviterId"`
+	InviterName string           `json:"inviterName,omitempty"`
+	Status      InvitationStatus `json:"status"`
+	TokenHash   string           `json:"tokenHash,omitempty"`
+	AcceptedBy  string           `json:"acceptedBy,omitempty"` // ID of the User who accepted the Invitation
+	AcceptedAt  time.Time        `json:"acceptedAt,omitempty"`
+}
+
+// Type returns the entity type of the Invitation.
+func (i Invitation) Type() string {
+	return "Invitation"
+}
+
+// RefID returns the Reference ID of the entity.
+func (i Invitation) RefID() ref.RefID {
+	r, _ := ref.NewRefID(i.Type(), i.ID, i.VersionID)
+	return r
+}
+
+// CompressedJSON returns a compressed JSON representation of the Invitation.
+func (i Invitation) CompressedJSON() []byte {
+	j, err := versionary.ToCompressedJSON(i)
+	if err != nil {
+		return nil
+	}
+	return j
+}
+
+// Scrub removes the token hash from the Invitation.
+func (i Invitation) Scrub() Invitation {
+	i.TokenHash = ""
+	return i
+}
+
+// IsOpen returns true if the Invitation may still be accepted at the specified time.
+func (i Invitation) IsOpen(at time.Time) bool {
+	return i.Status == INVITED && i.ExpiresAt.After(at)
+}
+
+// Validate checks whether the Invitation has all required fields and whether
+// the supplied values are valid, returning a list of problems. If the list is
+// empty, then the Invitation is valid.
+func (i Invitation) Validate() []string {
+	var problems []string
+	if i.ID == "" || !tuid.IsValid(tuid.TUID(i.ID)) {
+		problems = append(problems, "ID is missing or invalid")
+	}
+	if i.CreatedAt.IsZero() {
+		problems = append(problems, "CreatedAt is missing")
+	}
+	if i.VersionID == "" || !tuid.IsValid(tuid.TUID(i.VersionID)) {
+		problems = append(problems, "VersionID is missing or invalid")
+	}
+	if i.UpdatedAt.IsZero() {
+		problems = append(problems, "UpdatedAt is missing")
+	}
+	if i.ExpiresAt.IsZero() {
+		problems = append(problems, "ExpiresAt is missing")
+	}
+	if i.OrgID == "" || !tuid.IsValid(tuid.TUID(i.OrgID)) {
+		problems = append(problems, "OrgID is missing or invalid")
+	}
+	if i.Email == "" || !strings.Contains(i.Email, "@") {
+		problems = append(problems, "Email is missing or invalid")
+	}
+	if i.InviterID == "" || !tuid.IsValid(tuid.TUID(i.InviterID)) {
+		problems = append(problems, "InviterID is missing or invalid")
+	}
+	if i.Status == "" || !i.Status.IsValid() {
+		statuses := versionary.Map(InvitationStatuses, func(s InvitationStatus) string { return string(s) })
+		expected := strings.Join(statuses, ", ")
+		problems = append(problems, "Status is missing or invalid. Expecting: "+expected)
+	}
+	if i.TokenHash == "" {
+		problems = append(problems, "TokenHash is missing")
+	}
+	return problems
+}
+
+// NewInvitationToken generates a new random invitation token, returning the token and its hash.
+func NewInvitationToken() (string, string, error) {
+	b := make([]byte, invitationTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("error generating invitation token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashInvitationToken(token), nil
+}
+
+// hashInvitationToken returns the hex-encoded SHA256 hash of an invitation token.
+func hashInvitationToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// ValidateToken checks an invitation token against the Invitation at the specified time.
+// ErrInvalidInvitation, ErrClosedInvitation, or ErrExpiredInvitation is returned if it may not be accepted.
+func (i Invitation) ValidateToken(token string, at time.Time) error {
+	if i.TokenHash == "" || token == "" {
+		return ErrInvalidInvitation
+	}
+	if subtle.ConstantTimeCompare([]byte(hashInvitationToken(token)), []byte(i.TokenHash)) != 1 {
+		return ErrInvalidInvitation
+	}
+	if i.Status != INVITED {
+		return ErrClosedInvitation
+	}
+	if !i.ExpiresAt.After(at) {
+		return ErrExpiredInvitation
+	}
+	return nil
+}
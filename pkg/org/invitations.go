@@ -0,0 +1,284 @@
+package org
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"versionary-api/pkg/email"
+	"versionary-api/pkg/util"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+//==============================================================================
+// Invitation Table
+//==============================================================================
+
+// rowInvitations is a TableRow definition for Invitation versions.
+var rowInvitations = v.TableRow[Invitation]{
+	RowName:      "invitations_version",
+	PartKeyName:  "id",
+	PartKeyValue: func(i Invitation) string { return i.ID },
+	PartKeyLabel: func(i Invitation) string { return i.Email },
+	SortKeyName:  "version_id",
+	SortKeyValue: func(i Invitation) string { return i.VersionID },
+	JsonValue:    func(i Invitation) []byte { return i.CompressedJSON() },
+}
+
+// rowInvitationsOrg is a TableRow definition for Invitations by Organization ID.
+var rowInvitationsOrg = v.TableRow[Invitation]{
+	RowName:      "invitations_org",
+	PartKeyName:  "org_id",
+	PartKeyValue: func(i Invitation) string { return i.OrgID },
+	PartKeyLabel: func(i Invitation) string { return i.OrgName },
+	SortKeyName:  "id",
+	SortKeyValue: func(i Invitation) string { return i.ID },
+	JsonValue:    func(i Invitation) []byte { return i.CompressedJSON() },
+}
+
+// rowInvitationsEmail is a TableRow definition for Invitations by email address.
+var rowInvitationsEmail = v.TableRow[Invitation]{
+	RowName:      "invitations_email",
+	PartKeyName:  "email",
+	PartKeyValue: func(i Invitation) string { return i.Email },
+	SortKeyName:  "id",
+	SortKeyValue: func(i Invitation) string { return i.ID },
+	JsonValue:    func(i Invitation) []byte { return i.CompressedJSON() },
+}
+
+// rowInvitationClosures names the claims (see util.Claim) that close Invitations (accepted or revoked), by ID.
+const rowInvitationClosures = "invitation_closures"
+
+// NewInvitationTable instantiates a new DynamoDB table for Invitations.
+func NewInvitationTable(dbClient *dynamodb.Client, env string) v.Table[Invitation] {
+	if env == "" {
+		env = "dev"
+	}
+	return v.Table[Invitation]{
+		Client:     dbClient,
+		EntityType: "Invitation",
+		TableName:  "invitations" + "_" + env,
+		TTL:        false,
+		EntityRow:  rowInvitations,
+		IndexRows: map[string]v.TableRow[Invitation]{
+			rowInvitationsOrg.RowName:   rowInvitationsOrg,
+			rowInvitationsEmail.RowName: rowInvitationsEmail,
+		},
+	}
+}
+
+// NewInvitationMemTable creates an in-memory Invitation table for testing purposes.
+func NewInvitationMemTable(table v.Table[Invitation]) v.MemTable[Invitation] {
+	return v.NewMemTable(table)
+}
+
+//==============================================================================
+// Invitation Service
+//==============================================================================
+
+// InvitationService is used to manage Organization Invitations in a DynamoDB table.
+type InvitationService struct {
+	EntityType string
+	Table      v.TableReadWriter[Invitation]
+}
+
+// NewInvitationService creates a new Invitation service backed by a Versionary Table for the specified environment.
+func NewInvitationService(dbClient *dynamodb.Client, env string) InvitationService {
+	table := NewInvitationTable(dbClient, env)
+	return InvitationService{
+		EntityType: table.EntityType,
+		Table:      table,
+	}
+}
+
+// NewMockInvitationService creates a new Invitation service backed by an in-memory table for testing purposes.
+func NewMockInvitationService(env string) InvitationService {
+	table := NewInvitationMemTable(NewInvitationTable(nil, env))
+	return InvitationService{
+		EntityType: table.EntityType,
+		Table:      table,
+	}
+}
+
+//------------------------------------------------------------------------------
+// Invitation Versions
+//------------------------------------------------------------------------------
+
+// Create an Invitation in the Invitation table. A new invitation token is generated, and its hash is stored.
+// The Invitation and the clear-text token (for delivery to the invited email address) are returned.
+func (s InvitationService) Create(ctx context.Context, i Invitation) (Invitation, string, []string, error) {
+	t := tuid.NewID()
+	at, _ := t.Time()
+	i.ID = t.String()
+	i.CreatedAt = at
+	i.VersionID = t.String()
+	i.UpdatedAt = at
+	if i.ExpiresAt.IsZero() {
+		i.ExpiresAt = at.Add(InvitationLifetime)
+	}
+	i.Status = INVITED
+	i.AcceptedBy = ""
+	i.AcceptedAt = time.Time{}
+	address, err := email.NewIdentity("", i.Email)
+	if err != nil {
+		return i, "", []string{err.Error()}, err
+	}
+	i.Email = address.Address
+	token, hash, err := NewInvitationToken()
+	if err != nil {
+		return i, "", nil, err
+	}
+	i.TokenHash = hash
+	problems := i.Validate()
+	if len(problems) > 0 {
+		return i, "", problems, fmt.Errorf("error creating %s %s: invalid field(s): %s", s.EntityType, i.ID, strings.Join(problems, ", "))
+	}
+	err = s.Table.WriteEntity(ctx, i)
+	if err != nil {
+		return i, "", problems, fmt.Errorf("error creating %s %s %s: %w", s.EntityType, i.ID, i.Email, err)
+	}
+	return i, token, problems, nil
+}
+
+// Update an Invitation in the Invitation table. If a previous version does not exist, the Invitation is created.
+func (s InvitationService) Update(ctx context.Context, i Invitation) (Invitation, []string, error) {
+	t := tuid.NewID()
+	at, _ := t.Time()
+	i.VersionID = t.String()
+	i.UpdatedAt = at
+	problems := i.Validate()
+	if len(problems) > 0 {
+		return i, problems, fmt.Errorf("error updating %s %s: invalid field(s): %s", s.EntityType, i.ID, strings.Join(problems, ", "))
+	}
+	return i, problems, s.Table.UpdateEntity(ctx, i)
+}
+
+// Claim an open Invitation with the supplied token, before accepting it, with a conditional write. When several
+// requests claim the same Invitation at once, only one succeeds; the others receive ErrClosedInvitation.
+// ErrInvalidInvitation or ErrExpiredInvitation is returned if the token may not be used. The claim holder
+// should then apply the acceptance and close the Invitation with Accept, or Release the claim if that fails.
+func (s InvitationService) Claim(ctx context.Context, i Invitation, token string) (util.Claim, error) {
+	if err := i.ValidateToken(token, time.Now()); err != nil {
+		return util.Claim{}, err
+	}
+	return s.claim(ctx, i)
+}
+
+// claim records that the Invitation is being closed, whether accepted or revoked.
+func (s InvitationService) claim(ctx context.Context, i Invitation) (util.Claim, error) {
+	c := util.Claim{
+		RowName: rowInvitationClosures,
+		Key:     i.ID,
+		Owner:   tuid.NewID().String(),
+	}
+	err := util.WriteClaim(ctx, s.Table, c)
+	if errors.Is(err, util.ErrClaimed) {
+		return c, ErrClosedInvitation
+	}
+	if err != nil {
+		return c, fmt.Errorf("error claiming %s %s: %w", s.EntityType, i.ID, err)
+	}
+	return c, nil
+}
+
+// Release a claimed Invitation that could not be accepted, so that it may be claimed again.
+func (s InvitationService) Release(ctx context.Context, c util.Claim) error {
+	return util.DeleteClaim(ctx, s.Table, c)
+}
+
+// Accept an Invitation with the supplied token, on behalf of the specified User. ErrInvalidInvitation,
+// ErrClosedInvitation, or ErrExpiredInvitation is returned if the Invitation may not be accepted.
+// To accept an Invitation only once, even with concurrent requests, Claim it first.
+func (s InvitationService) Accept(ctx context.Context, i Invitation, token, userID string) (Invitation, error) {
+	if err := i.ValidateToken(token, time.Now()); err != nil {
+		return i, err
+	}
+	i.Status = ACCEPTED
+	i.AcceptedBy = userID
+	i.AcceptedAt = time.Now()
+	i, _, err := s.Update(ctx, i)
+	if err != nil {
+		return i, fmt.Errorf("error accepting %s %s: %w", s.EntityType, i.ID, err)
+	}
+	return i, nil
+}
+
+// Revoke an open Invitation, so that it may no longer be accepted.
+// ErrClosedInvitation is returned if the Invitation has already been accepted or revoked.
+func (s InvitationService) Revoke(ctx context.Context, i Invitation) (Invitation, error) {
+	if i.Status != INVITED {
+		return i, ErrClosedInvitation
+	}
+	c, err := s.claim(ctx, i)
+	if err != nil {
+		return i, err
+	}
+	i.Status = REVOKED
+	i, _, err = s.Update(ctx, i)
+	if err != nil {
+		_ = s.Release(ctx, c)
+		return i, fmt.Errorf("error revoking %s %s: %w", s.EntityType, i.ID, err)
+	}
+	return i, nil
+}
+
+// Write an Invitation to the Invitation table. This method assumes that the Invitation has all the required fields.
+// It would most likely be used for "refreshing" the index rows in the Invitation table.
+func (s InvitationService) Write(ctx context.Context, i Invitation) (Invitation, error) {
+	return i, s.Table.WriteEntity(ctx, i)
+}
+
+// Delete an Invitation from the Invitation table. The deleted Invitation is returned.
+func (s InvitationService) Delete(ctx context.Context, id string) (Invitation, error) {
+	return s.Table.DeleteEntityWithID(ctx, id)
+}
+
+// Exists checks if an Invitation exists in the Invitation table.
+func (s InvitationService) Exists(ctx context.Context, id string) bool {
+	return s.Table.EntityExists(ctx, id)
+}
+
+// Read a specified Invitation from the Invitation table.
+func (s InvitationService) Read(ctx context.Context, id string) (Invitation, error) {
+	return s.Table.ReadEntity(ctx, id)
+}
+
+// ReadVersions returns paginated versions of the specified Invitation.
+// Sorting is chronological (or reverse). The offset is the last ID returned in a previous request.
+func (s InvitationService) ReadVersions(ctx context.Context, id string, reverse bool, limit int, offset string) ([]Invitation, error) {
+	return s.Table.ReadEntityVersions(ctx, id, reverse, limit, offset)
+}
+
+// ReadAllVersions returns all versions of the specified Invitation in chronological order.
+func (s InvitationService) ReadAllVersions(ctx context.Context, id string) ([]Invitation, error) {
+	return s.Table.ReadAllEntityVersions(ctx, id)
+}
+
+//------------------------------------------------------------------------------
+// Invitations by Organization
+//------------------------------------------------------------------------------
+
+// ReadInvitationsByOrgID returns paginated Invitations to the specified Organization.
+// Sorting is chronological (or reverse). The offset is the ID of the last Invitation returned in a previous request.
+func (s InvitationService) ReadInvitationsByOrgID(ctx context.Context, orgID string, reverse bool, limit int, offset string) ([]Invitation, error) {
+	return s.Table.ReadEntitiesFromRow(ctx, rowInvitationsOrg, orgID, reverse, limit, offset)
+}
+
+// ReadAllInvitationsByOrgID returns the complete, chronological list of Invitations to the specified Organization.
+func (s InvitationService) ReadAllInvitationsByOrgID(ctx context.Context, orgID string) ([]Invitation, error) {
+	return s.Table.ReadAllEntitiesFromRow(ctx, rowInvitationsOrg, orgID)
+}
+
+//------------------------------------------------------------------------------
+// Invitations by Email Address
+//------------------------------------------------------------------------------
+
+// ReadAllInvitationsByEmail returns the complete, chronological list of Invitations to the specified email address.
+func (s InvitationService) ReadAllInvitationsByEmail(ctx context.Context, address string) ([]Invitation, error) {
+	return s.Table.ReadAllEntitiesFromRow(ctx, rowInvitationsEmail, strings.ToLower(strings.TrimSpace(address)))
+}
@@ -0,0 +1,166 @@
+package org
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+var invitationService = NewMockInvitationService("test")
+
+func TestInvitationTable(t *testing.T) {
+	expect := assert.New(t)
+	expect.True(invitationService.Table.IsValid())
+}
+
+func TestInvitationStatus(t *testing.T) {
+	expect := assert.New(t)
+	expect.True(INVITED.IsValid())
+	expect.True(REVOKED.IsValid())
+	expect.False(InvitationStatus("PENDING").IsValid())
+	expect.Equal("ACCEPTED", ACCEPTED.String())
+}
+
+func TestInvitationValidateToken(t *testing.T) {
+	expect := assert.New(t)
+	token, hash, err := NewInvitationToken()
+	if !expect.NoError(err) {
+		return
+	}
+	expect.NotEqual(token, hash)
+	i := Invitation{Status: INVITED, TokenHash: hash, ExpiresAt: t2}
+	expect.NoError(i.ValidateToken(token, t1))
+	expect.True(i.IsOpen(t1))
+	expect.ErrorIs(i.ValidateToken("", t1), ErrInvalidInvitation)
+	expect.ErrorIs(i.ValidateToken(token+"x", t1), ErrInvalidInvitation)
+	expect.ErrorIs(i.ValidateToken(token, t3), ErrExpiredInvitation)
+	expect.False(i.IsOpen(t3))
+	i.Status = REVOKED
+	expect.ErrorIs(i.ValidateToken(token, t1), ErrClosedInvitation)
+	expect.False(i.IsOpen(t1))
+	expect.Empty(i.Scrub().TokenHash)
+}
+
+func TestInvitationLifecycle(t *testing.T) {
+	expect := assert.New(t)
+	orgID := tuid.NewID().String()
+	inviterID := tuid.NewID().String()
+	// Invalid email addresses are rejected
+	_, _, problems, err := invitationService.Create(ctx, Invitation{
+		OrgID:     orgID,
+		Email:     "not an email address",
+		InviterID: inviterID,
+	})
+	expect.Error(err)
+	expect.NotEmpty(problems)
+	// Create an invitation
+	i, token, problems, err := invitationService.Create(ctx, Invitation{
+		OrgID:     orgID,
+		OrgName:   "Invited Organization",
+		Email:     " Invited.Person@Test.com ",
+		Roles:     []string{"org_admin"},
+		InviterID: inviterID,
+		Status:    ACCEPTED,
+	})
+	expect.Empty(problems)
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = invitationService.Delete(ctx, i.ID) }()
+	expect.NotEmpty(token)
+	expect.Equal("invited.person@test.com", i.Email)
+	expect.Equal(INVITED, i.Status)
+	expect.WithinDuration(i.CreatedAt.Add(InvitationLifetime), i.ExpiresAt, time.Second)
+	expect.True(invitationService.Exists(ctx, i.ID))
+	// Read invitations by organization and email address
+	byOrg, err := invitationService.ReadAllInvitationsByOrgID(ctx, orgID)
+	if expect.NoError(err) && expect.Len(byOrg, 1) {
+		expect.Equal(i.ID, byOrg[0].ID)
+	}
+	byEmail, err := invitationService.ReadAllInvitationsByEmail(ctx, "INVITED.PERSON@test.com")
+	if expect.NoError(err) && expect.Len(byEmail, 1) {
+		expect.Equal(i.ID, byEmail[0].ID)
+	}
+	// A wrong token may not be used to accept the invitation
+	_, err = invitationService.Accept(ctx, i, "wrong", inviterID)
+	expect.ErrorIs(err, ErrInvalidInvitation)
+	// Accept the invitation
+	userID := tuid.NewID().String()
+	accepted, err := invitationService.Accept(ctx, i, token, userID)
+	if expect.NoError(err) {
+		expect.Equal(ACCEPTED, accepted.Status)
+		expect.Equal(userID, accepted.AcceptedBy)
+		expect.False(accepted.AcceptedAt.IsZero())
+		expect.NotEqual(i.VersionID, accepted.VersionID)
+	}
+	// Accepted invitations may be neither accepted again nor revoked
+	_, err = invitationService.Accept(ctx, accepted, token, userID)
+	expect.ErrorIs(err, ErrClosedInvitation)
+	_, err = invitationService.Revoke(ctx, accepted)
+	expect.ErrorIs(err, ErrClosedInvitation)
+	versions, err := invitationService.ReadAllVersions(ctx, i.ID)
+	if expect.NoError(err) {
+		expect.Len(versions, 2)
+	}
+	// Delete the invitation
+	_, err = invitationService.Delete(ctx, i.ID)
+	if expect.NoError(err) {
+		_, err = invitationService.Read(ctx, i.ID)
+		expect.ErrorIs(err, v.ErrNotFound)
+	}
+}
+
+func TestInvitationRevoke(t *testing.T) {
+	expect := assert.New(t)
+	i, token, _, err := invitationService.Create(ctx, Invitation{
+		OrgID:     tuid.NewID().String(),
+		Email:     "revoked.person@test.com",
+		InviterID: tuid.NewID().String(),
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = invitationService.Delete(ctx, i.ID) }()
+	revoked, err := invitationService.Revoke(ctx, i)
+	if expect.NoError(err) {
+		expect.Equal(REVOKED, revoked.Status)
+		_, err = invitationService.Accept(ctx, revoked, token, tuid.NewID().String())
+		expect.ErrorIs(err, ErrClosedInvitation)
+	}
+}
+
+func TestInvitationClaim(t *testing.T) {
+	expect := assert.New(t)
+	i, token, _, err := invitationService.Create(ctx, Invitation{
+		OrgID:     tuid.NewID().String(),
+		Email:     "claimed.person@test.com",
+		InviterID: tuid.NewID().String(),
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = invitationService.Delete(ctx, i.ID) }()
+	_, err = invitationService.Claim(ctx, i, "wrong")
+	expect.ErrorIs(err, ErrInvalidInvitation)
+	// Only one request may claim an invitation, and it may not be revoked while claimed
+	c, err := invitationService.Claim(ctx, i, token)
+	if !expect.NoError(err) {
+		return
+	}
+	_, err = invitationService.Claim(ctx, i, token)
+	expect.ErrorIs(err, ErrClosedInvitation)
+	_, err = invitationService.Revoke(ctx, i)
+	expect.ErrorIs(err, ErrClosedInvitation)
+	// A released claim may be claimed again
+	expect.NoError(invitationService.Release(ctx, c))
+	c, err = invitationService.Claim(ctx, i, token)
+	if expect.NoError(err) {
+		accepted, err := invitationService.Accept(ctx, i, token, tuid.NewID().String())
+		if expect.NoError(err) {
+			expect.Equal(ACCEPTED, accepted.Status)
+		}
+	}
+}
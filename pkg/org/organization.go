@@ -1,6 +1,8 @@
 package org
 
 import (
+	"errors"
+	"slices"
 	"strings"
 	"time"
 	"versionary-api/pkg/ref"
@@ -9,6 +11,15 @@ import (
 	"github.com/voxtechnica/versionary"
 )
 
+// MaxDepth limits the depth of the Organization hierarchy (the number of Organizations from a root to a leaf).
+const MaxDepth = 16
+
+// ErrInvalidParent is returned when an Organization's parent does not exist, or the hierarchy would be too deep.
+var ErrInvalidParent = errors.New("invalid parent organization")
+
+// ErrHierarchyCycle is returned when an Organization would become its own ancestor.
+var ErrHierarchyCycle = errors.New("organization would be its own ancestor")
+
 type Organization struct {
 	ID        string    `json:"id"`
 	CreatedAt time.Time `json:"createdAt"`
@@ -16,6 +27,12 @@ type Organization struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 	Name      string    `json:"name"`
 	Status    Status    `json:"status"`
+	ParentID  string    `json:"parentId,omitempty"` // parent Organization (e.g. of a division), if any
+	// Domains are the verified email domains owned by the Organization. New Users with a matching email
+	// address join the Organization automatically.
+	Domains []string `json:"domains,omitempty"`
+	// DomainChallenges are the open requests to verify email domains.
+	DomainChallenges []DomainChallenge `json:"domainChallenges,omitempty"`
 }
 
 // Type returns the entity type of the Organization.
@@ -63,5 +80,59 @@ func (o Organization) Validate() []string {
 		expected := strings.Join(statuses, ", ")
 		problems = append(problems, "Status is missing or invalid. Expecting: "+expected)
 	}
+	if o.ParentID != "" && (!tuid.IsValid(tuid.TUID(o.ParentID)) || o.ParentID == o.ID) {
+		problems = append(problems, "ParentID is invalid")
+	}
+	for _, d := range o.Domains {
+		if !ValidDomain(d) {
+			problems = append(problems, "Domain is invalid: "+d)
+		}
+	}
 	return problems
 }
+
+// HasDomain returns true if the Organization has verified the (standardized) email domain.
+func (o Organization) HasDomain(domain string) bool {
+	return slices.Contains(o.Domains, domain)
+}
+
+// DomainChallenge returns the open challenge for the (standardized) email domain, if any.
+func (o Organization) DomainChallenge(domain string) (DomainChallenge, bool) {
+	for _, dc := range o.DomainChallenges {
+		if dc.Domain == domain {
+			return dc, true
+		}
+	}
+	return DomainChallenge{}, false
+}
+
+// WithoutDomain returns the Organization without the (standardized) email domain or its challenge.
+func (o Organization) WithoutDomain(domain string) Organization {
+	o.Domains = slices.DeleteFunc(slices.Clone(o.Domains), func(d string) bool { return d == domain })
+	o.DomainChallenges = slices.DeleteFunc(slices.Clone(o.DomainChallenges), func(dc DomainChallenge) bool { return dc.Domain == domain })
+	return o
+}
+
+// Tree is an Organization and its descendants.
+type Tree struct {
+	Organization Organization `json:"organization"`
+	Children     []Tree       `json:"children,omitempty"`
+}
+
+// IDs returns the IDs of the Organizations in the Tree, root first (depth-first).
+func (t Tree) IDs() []string {
+	ids := []string{t.Organization.ID}
+	for _, child := range t.Children {
+		ids = append(ids, child.IDs()...)
+	}
+	return ids
+}
+
+// Height returns the number of levels in the Tree, including its root.
+func (t Tree) Height() int {
+	height := 0
+	for _, child := range t.Children {
+		height = max(height, child.Height())
+	}
+	return height + 1
+}
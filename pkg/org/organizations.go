@@ -2,8 +2,11 @@ package org
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 	"versionary-api/pkg/util"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -36,6 +39,28 @@ var rowOrganizationsStatus = v.TableRow[Organization]{
 	JsonValue:    func(o Organization) []byte { return o.CompressedJSON() },
 }
 
+// rowOrganizationsDomain is a TableRow definition for Organizations by verified email domain.
+var rowOrganizationsDomain = v.TableRow[Organization]{
+	RowName:       "organizations_domain",
+	PartKeyName:   "domain",
+	PartKeyValues: func(o Organization) []string { return o.Domains },
+	PartKeyLabel:  func(o Organization) string { return o.Name },
+	SortKeyName:   "id",
+	SortKeyValue:  func(o Organization) string { return o.ID },
+	JsonValue:     func(o Organization) []byte { return o.CompressedJSON() },
+}
+
+// rowOrganizationsParent is a TableRow definition for Organizations by parent Organization.
+var rowOrganizationsParent = v.TableRow[Organization]{
+	RowName:      "organizations_parent",
+	PartKeyName:  "parent_id",
+	PartKeyValue: func(o Organization) string { return o.ParentID },
+	PartKeyLabel: func(o Organization) string { return o.Name },
+	SortKeyName:  "id",
+	SortKeyValue: func(o Organization) string { return o.ID },
+	JsonValue:    func(o Organization) []byte { return o.CompressedJSON() },
+}
+
 // NewTable instantiates a new DynamoDB table for organizations.
 func NewTable(dbClient *dynamodb.Client, env string) v.Table[Organization] {
 	if env == "" {
@@ -49,6 +74,8 @@ func NewTable(dbClient *dynamodb.Client, env string) v.Table[Organization] {
 		EntityRow:  rowOrganizations,
 		IndexRows: map[string]v.TableRow[Organization]{
 			rowOrganizationsStatus.RowName: rowOrganizationsStatus,
+			rowOrganizationsDomain.RowName: rowOrganizationsDomain,
+			rowOrganizationsParent.RowName: rowOrganizationsParent,
 		},
 	}
 }
@@ -105,6 +132,9 @@ func (s Service) Create(ctx context.Context, o Organization) (Organization, []st
 	if len(problems) > 0 {
 		return o, problems, fmt.Errorf("error creating %s %s: invalid field(s): %s", s.EntityType, o.ID, strings.Join(problems, ", "))
 	}
+	if err := s.checkParent(ctx, o); err != nil {
+		return o, []string{err.Error()}, fmt.Errorf("error creating %s %s: %w", s.EntityType, o.ID, err)
+	}
 	err := s.Table.WriteEntity(ctx, o)
 	if err != nil {
 		return o, problems, fmt.Errorf("error creating %s %s %s: %w", s.EntityType, o.ID, o.Name, err)
@@ -122,6 +152,9 @@ func (s Service) Update(ctx context.Context, o Organization) (Organization, []st
 	if len(problems) > 0 {
 		return o, problems, fmt.Errorf("error updating %s %s: invalid field(s): %s", s.EntityType, o.ID, strings.Join(problems, ", "))
 	}
+	if err := s.checkParent(ctx, o); err != nil {
+		return o, []string{err.Error()}, fmt.Errorf("error updating %s %s: %w", s.EntityType, o.ID, err)
+	}
 	return o, problems, s.Table.UpdateEntity(ctx, o)
 }
 
@@ -301,3 +334,220 @@ func (s Service) ReadOrganizationByName(ctx context.Context, name string) (Organ
 	}
 	return o, nil
 }
+
+//------------------------------------------------------------------------------
+// Organization Hierarchy
+//------------------------------------------------------------------------------
+
+// ReadAllParentIDs returns a complete, sorted list of the IDs of Organizations that have child Organizations.
+func (s Service) ReadAllParentIDs(ctx context.Context) ([]string, error) {
+	return s.Table.ReadAllPartKeyValues(ctx, rowOrganizationsParent)
+}
+
+// ReadChildren returns the child Organizations of the specified Organization, sorted by ID.
+func (s Service) ReadChildren(ctx context.Context, parentID string) ([]Organization, error) {
+	return s.Table.ReadAllEntitiesFromRow(ctx, rowOrganizationsParent, parentID)
+}
+
+// ReadAncestors returns the ancestors of the Organization, starting with its parent and ending with the root.
+// ErrHierarchyCycle is returned if the stored hierarchy contains a cycle or exceeds MaxDepth.
+func (s Service) ReadAncestors(ctx context.Context, o Organization) ([]Organization, error) {
+	var ancestors []Organization
+	seen := map[string]bool{o.ID: true}
+	for parentID := o.ParentID; parentID != ""; {
+		if seen[parentID] || len(ancestors) >= MaxDepth {
+			return ancestors, fmt.Errorf("error reading %s %s ancestors: %w", s.EntityType, o.ID, ErrHierarchyCycle)
+		}
+		seen[parentID] = true
+		parent, err := s.Read(ctx, parentID)
+		if err != nil {
+			return ancestors, fmt.Errorf("error reading %s %s ancestor %s: %w", s.EntityType, o.ID, parentID, err)
+		}
+		ancestors = append(ancestors, parent)
+		parentID = parent.ParentID
+	}
+	return ancestors, nil
+}
+
+// ReadSubtree returns the Organization and all of its descendants.
+func (s Service) ReadSubtree(ctx context.Context, o Organization) (Tree, error) {
+	return s.readSubtree(ctx, o, map[string]bool{}, 1)
+}
+
+// readSubtree recursively reads the descendants of the Organization, guarding against cycles in stored data.
+func (s Service) readSubtree(ctx context.Context, o Organization, seen map[string]bool, depth int) (Tree, error) {
+	tree := Tree{Organization: o}
+	if seen[o.ID] || depth > MaxDepth {
+		return tree, fmt.Errorf("error reading %s %s subtree: %w", s.EntityType, o.ID, ErrHierarchyCycle)
+	}
+	seen[o.ID] = true
+	children, err := s.ReadChildren(ctx, o.ID)
+	if err != nil {
+		return tree, fmt.Errorf("error reading %s %s children: %w", s.EntityType, o.ID, err)
+	}
+	for _, child := range children {
+		subtree, err := s.readSubtree(ctx, child, seen, depth+1)
+		if err != nil {
+			return tree, err
+		}
+		tree.Children = append(tree.Children, subtree)
+	}
+	return tree, nil
+}
+
+// ReadDescendantIDs returns the IDs of all descendants of the specified Organization (excluding itself).
+func (s Service) ReadDescendantIDs(ctx context.Context, id string) ([]string, error) {
+	tree, err := s.ReadSubtree(ctx, Organization{ID: id})
+	if err != nil {
+		return nil, err
+	}
+	return tree.IDs()[1:], nil
+}
+
+// checkParent verifies that the Organization's parent exists, that the Organization is not its own
+// ancestor, and that the hierarchy does not exceed MaxDepth.
+func (s Service) checkParent(ctx context.Context, o Organization) error {
+	if o.ParentID == "" {
+		return nil
+	}
+	if o.ParentID == o.ID {
+		return ErrHierarchyCycle
+	}
+	parent, err := s.Read(ctx, o.ParentID)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		return fmt.Errorf("%w: %s not found", ErrInvalidParent, o.ParentID)
+	}
+	if err != nil {
+		return err
+	}
+	ancestors, err := s.ReadAncestors(ctx, parent)
+	if err != nil {
+		return err
+	}
+	for _, a := range ancestors {
+		if a.ID == o.ID {
+			return ErrHierarchyCycle
+		}
+	}
+	height := 1
+	if o.ID != "" {
+		tree, err := s.ReadSubtree(ctx, Organization{ID: o.ID})
+		if err != nil {
+			return err
+		}
+		height = tree.Height()
+	}
+	if len(ancestors)+1+height > MaxDepth {
+		return fmt.Errorf("%w: the hierarchy would exceed %d levels", ErrInvalidParent, MaxDepth)
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+// Organizations by Domain
+//------------------------------------------------------------------------------
+
+// ReadAllDomains returns a complete, alphabetical list of verified email domains in the Organization table.
+func (s Service) ReadAllDomains(ctx context.Context) ([]string, error) {
+	return s.Table.ReadAllPartKeyValues(ctx, rowOrganizationsDomain)
+}
+
+// ReadOrganizationByDomain returns the Organization that verified the specified email domain.
+// If no Organization has verified it, v.ErrNotFound is returned.
+func (s Service) ReadOrganizationByDomain(ctx context.Context, domain string) (Organization, error) {
+	domain = StandardizeDomain(domain)
+	orgs, err := s.Table.ReadAllEntitiesFromRow(ctx, rowOrganizationsDomain, domain)
+	if err != nil {
+		return Organization{}, err
+	}
+	if len(orgs) == 0 {
+		return Organization{}, fmt.Errorf("error reading %s with domain %s: %w", s.EntityType, domain, v.ErrNotFound)
+	}
+	return orgs[0], nil
+}
+
+// ReadOrganizationByEmail returns the ENABLED Organization that verified the domain of the email address,
+// or one of its parent domains, preferring the most specific domain. If there is none, v.ErrNotFound is returned.
+func (s Service) ReadOrganizationByEmail(ctx context.Context, address string) (Organization, error) {
+	for _, domain := range EmailDomains(address) {
+		o, err := s.ReadOrganizationByDomain(ctx, domain)
+		if err != nil && errors.Is(err, v.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return o, err
+		}
+		if o.Status == ENABLED {
+			return o, nil
+		}
+	}
+	return Organization{}, fmt.Errorf("error reading %s for email %s: %w", s.EntityType, address, v.ErrNotFound)
+}
+
+// CreateDomainChallenge starts (or restarts) verification of an email domain for the Organization.
+// The updated Organization and the new challenge are returned. ErrDomainTaken is returned if another
+// Organization has already verified the domain, and ErrDomainVerified if this one has.
+func (s Service) CreateDomainChallenge(ctx context.Context, o Organization, domain string) (Organization, DomainChallenge, error) {
+	domain = StandardizeDomain(domain)
+	if o.HasDomain(domain) {
+		return o, DomainChallenge{}, fmt.Errorf("error creating %s %s challenge: %w: %s", s.EntityType, o.ID, ErrDomainVerified, domain)
+	}
+	dc, err := NewDomainChallenge(domain, time.Now())
+	if err != nil {
+		return o, dc, err
+	}
+	if err = s.checkDomainOwner(ctx, o, domain); err != nil {
+		return o, dc, err
+	}
+	o = o.WithoutDomain(domain)
+	o.DomainChallenges = append(o.DomainChallenges, dc)
+	o, _, err = s.Update(ctx, o)
+	return o, dc, err
+}
+
+// VerifyDomain checks the DNS TXT record of the Organization's open challenge for the email domain, using the
+// supplied resolver. If the record holds the challenge value, the domain is verified and the updated Organization
+// is returned. ErrDomainChallengeNotFound, ErrDomainNotVerified, or ErrDomainTaken is returned otherwise.
+func (s Service) VerifyDomain(ctx context.Context, r TXTResolver, o Organization, domain string) (Organization, error) {
+	domain = StandardizeDomain(domain)
+	dc, ok := o.DomainChallenge(domain)
+	if !ok {
+		return o, fmt.Errorf("error verifying %s %s: %w: %s", s.EntityType, o.ID, ErrDomainChallengeNotFound, domain)
+	}
+	if err := dc.Verify(ctx, r, time.Now()); err != nil {
+		return o, fmt.Errorf("error verifying %s %s: %w", s.EntityType, o.ID, err)
+	}
+	if err := s.checkDomainOwner(ctx, o, domain); err != nil {
+		return o, err
+	}
+	o = o.WithoutDomain(domain)
+	o.Domains = append(o.Domains, domain)
+	slices.Sort(o.Domains)
+	o, _, err := s.Update(ctx, o)
+	return o, err
+}
+
+// RemoveDomain removes a verified email domain (or an open challenge) from the Organization.
+func (s Service) RemoveDomain(ctx context.Context, o Organization, domain string) (Organization, error) {
+	domain = StandardizeDomain(domain)
+	if _, ok := o.DomainChallenge(domain); !ok && !o.HasDomain(domain) {
+		return o, fmt.Errorf("error removing %s %s domain %s: %w", s.EntityType, o.ID, domain, v.ErrNotFound)
+	}
+	o, _, err := s.Update(ctx, o.WithoutDomain(domain))
+	return o, err
+}
+
+// checkDomainOwner returns ErrDomainTaken if another Organization has verified the email domain.
+func (s Service) checkDomainOwner(ctx context.Context, o Organization, domain string) error {
+	owner, err := s.ReadOrganizationByDomain(ctx, domain)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if owner.ID != o.ID {
+		return fmt.Errorf("%w: %s (%s %s)", ErrDomainTaken, domain, s.EntityType, owner.ID)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package role
+
+import (
+	"sort"
+	"strings"
+
+	v "github.com/voxtechnica/versionary"
+)
+
+// A Permission authorizes an action on a kind of resource, in the form "<resource>:<action>" (e.g. "content:write").
+// The resource is the singular entity name, and the action is either "read" or "write". A "write" permission
+// implies the corresponding "read" permission. The special permission "*" grants every permission.
+// Content also has a "publish" action, which approves its publication (see ContentPublish).
+
+// READ is the action for viewing a resource.
+const READ = "read"
+
+// WRITE is the action for creating, updating, or deleting a resource.
+const WRITE = "write"
+
+// PUBLISH is the action for publishing (or archiving) Content, granted separately from editing it.
+const PUBLISH = "publish"
+
+// All is the permission that grants every other permission.
+const All = "*"
+
+// Resources is the complete list of resources for which permissions may be granted.
+var Resources = []string{
+	"apikey",
+	"content",
+	"device",
+	"diag",
+	"email",
+	"event",
+	"image",
+	"job",
+	"metric",
+	"organization",
+	"role",
+	"token",
+	"user",
+	"view",
+}
+
+// Permissions required by the API routes.
+const (
+	APIKeyRead        = "apikey:read"
+	APIKeyWrite       = "apikey:write"
+	ContentRead       = "content:read"
+	ContentWrite      = "content:write"
+	ContentPublish    = "content:publish"
+	DeviceRead        = "device:read"
+	DeviceWrite       = "device:write"
+	DiagRead          = "diag:read"
+	EmailRead         = "email:read"
+	EmailWrite        = "email:write"
+	EventRead         = "event:read"
+	EventWrite        = "event:write"
+	ImageRead         = "image:read"
+	ImageWrite        = "image:write"
+	JobRead           = "job:read"
+	JobWrite          = "job:write"
+	MetricRead        = "metric:read"
+	MetricWrite       = "metric:write"
+	OrganizationRead  = "organization:read"
+	OrganizationWrite = "organization:write"
+	RoleRead          = "role:read"
+	RoleWrite         = "role:write"
+	TokenRead         = "token:read"
+	TokenWrite        = "token:write"
+	UserRead          = "user:read"
+	UserWrite         = "user:write"
+	ViewRead          = "view:read"
+	ViewWrite         = "view:write"
+)
+
+// Permission returns a permission string for the specified resource and action.
+func Permission(resource, action string) string {
+	return resource + ":" + action
+}
+
+// ValidPermission returns true if the supplied permission has a recognized resource and action, or is All.
+func ValidPermission(p string) bool {
+	if p == All || p == ContentPublish {
+		return true
+	}
+	resource, action, ok := strings.Cut(p, ":")
+	return ok && v.Contains(Resources, resource) && (action == READ || action == WRITE)
+}
+
+// Grants returns true if the list of granted permissions includes the specified permission,
+// either explicitly, by implication (write implies read), or by the All permission.
+func Grants(granted []string, p string) bool {
+	if v.Contains(granted, All) || v.Contains(granted, p) {
+		return true
+	}
+	resource, action, ok := strings.Cut(p, ":")
+	return ok && action == READ && v.Contains(granted, Permission(resource, WRITE))
+}
+
+// Permissions are the effective permissions of a User, granted by their Roles. Global permissions apply
+// everywhere. Organization permissions apply only to resources within the User's active Organization:
+// their primary Organization, by default, or another Organization in which they hold a Membership.
+type Permissions struct {
+	UserID   string   `json:"userId"`
+	Roles    []string `json:"roles"`
+	OrgID    string   `json:"orgId,omitempty"`
+	OrgRoles []string `json:"orgRoles,omitempty"` // Membership roles, if OrgID is not the primary Organization
+	Global   []string `json:"global"`
+	Org      []string `json:"org"`
+	// SubOrgIDs are descendants of the Organization, where the Org permissions also apply, if enabled.
+	SubOrgIDs []string `json:"subOrgIds,omitempty"`
+}
+
+// Has returns true if the permission is granted globally.
+func (p Permissions) Has(perm string) bool {
+	return Grants(p.Global, perm)
+}
+
+// HasInOrg returns true if the permission is granted globally, or within the specified Organization
+// (including its descendant Organizations, if listed in SubOrgIDs).
+func (p Permissions) HasInOrg(perm, orgID string) bool {
+	return p.Has(perm) || (p.InScope(orgID) && Grants(p.Org, perm))
+}
+
+// InScope returns true if the specified Organization is the User's Organization, or one of its listed descendants.
+func (p Permissions) InScope(orgID string) bool {
+	return orgID != "" && (orgID == p.OrgID || v.Contains(p.SubOrgIDs, orgID))
+}
+
+// HasAnywhere returns true if the permission is granted, either globally or within the User's Organization.
+func (p Permissions) HasAnywhere(perm string) bool {
+	return p.HasInOrg(perm, p.OrgID)
+}
+
+// addPermission adds a permission to a sorted list, if it is not already present.
+func addPermission(list []string, p string) []string {
+	if v.Contains(list, p) {
+		return list
+	}
+	list = append(list, p)
+	sort.Strings(list)
+	return list
+}
+
+// Limit returns the Permissions, keeping only those accepted by the allow function (e.g. the scopes of an
+// APIKey), in both the global and organization lists. The All permission is expanded into the individual
+// permissions it grants, so that it may be limited as well.
+func (p Permissions) Limit(allow func(perm string) bool) Permissions {
+	p.Global = limitPermissions(p.Global, allow)
+	p.Org = limitPermissions(p.Org, allow)
+	return p
+}
+
+// limitPermissions returns the permissions in the list that are accepted by the allow function,
+// expanding the All permission into every read, write, and publish permission. A write permission that is not
+// accepted is reduced to the read permission it implies, if that is accepted.
+func limitPermissions(list []string, allow func(perm string) bool) []string {
+	if v.Contains(list, All) {
+		list = []string{ContentPublish}
+		for _, resource := range Resources {
+			list = append(list, Permission(resource, READ), Permission(resource, WRITE))
+		}
+	}
+	limited := []string{}
+	for _, perm := range list {
+		if allow(perm) {
+			limited = addPermission(limited, perm)
+		} else if resource, action, _ := strings.Cut(perm, ":"); action == WRITE && allow(Permission(resource, READ)) {
+			limited = addPermission(limited, Permission(resource, READ))
+		}
+	}
+	return limited
+}
@@ -0,0 +1,177 @@
+package role
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"versionary-api/pkg/ref"
+	"versionary-api/pkg/user"
+
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+// Built-in role names. Administrators have every permission, and that cannot be changed.
+const (
+	Admin    = "admin"
+	OrgAdmin = user.OrgAdminRole
+)
+
+// DefaultRoles are the built-in Roles, used unless a Role with the same name has been stored
+// (with the exception of the "admin" role, which may not be redefined).
+var DefaultRoles = []Role{
+	{
+		Name:        Admin,
+		Description: "Administrator: has all the keys",
+		Permissions: []string{All},
+	},
+	{
+		Name:        OrgAdmin,
+		Description: "Organization administrator: manages the Users in their own Organization",
+		Permissions: []string{OrganizationWrite, UserWrite},
+		OrgScoped:   true,
+	},
+}
+
+// namePattern is the required form of a Role name (e.g. "org_admin").
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Role is a named set of permissions, granted to each User whose Roles include the name.
+// If the Role is organization-scoped, then its permissions apply only within the User's Organization.
+type Role struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	VersionID   string    `json:"versionID"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Permissions []string  `json:"permissions"`
+	OrgScoped   bool      `json:"orgScoped,omitempty"`
+}
+
+// Type returns the entity type of the Role.
+func (r Role) Type() string {
+	return "Role"
+}
+
+// RefID returns the Reference ID of the entity.
+func (r Role) RefID() ref.RefID {
+	id, _ := ref.NewRefID(r.Type(), r.ID, r.VersionID)
+	return id
+}
+
+// CompressedJSON returns a compressed JSON representation of the Role.
+func (r Role) CompressedJSON() []byte {
+	j, err := v.ToCompressedJSON(r)
+	if err != nil {
+		return nil
+	}
+	return j
+}
+
+// Validate checks whether the Role has all required fields and whether the supplied values are valid,
+// returning a list of problems. If the list is empty, then the Role is valid.
+func (r Role) Validate() []string {
+	var problems []string
+	if r.ID == "" || !tuid.IsValid(tuid.TUID(r.ID)) {
+		problems = append(problems, "ID is missing or invalid")
+	}
+	if r.CreatedAt.IsZero() {
+		problems = append(problems, "CreatedAt is missing")
+	}
+	if r.VersionID == "" || !tuid.IsValid(tuid.TUID(r.VersionID)) {
+		problems = append(problems, "VersionID is missing or invalid")
+	}
+	if r.UpdatedAt.IsZero() {
+		problems = append(problems, "UpdatedAt is missing")
+	}
+	if !namePattern.MatchString(r.Name) {
+		problems = append(problems, "Name is missing or invalid. Expecting lowercase letters, digits, and underscores")
+	}
+	if r.Name == Admin {
+		problems = append(problems, "Name admin is reserved")
+	}
+	for _, p := range r.Permissions {
+		if p == All && r.Name != Admin {
+			problems = append(problems, "Permission "+All+" is reserved for the "+Admin+" role")
+		} else if !ValidPermission(p) {
+			problems = append(problems, "Permission "+p+" is invalid. Expecting <resource>:read, <resource>:write, or "+ContentPublish+", with resource: "+strings.Join(Resources, ", "))
+		}
+	}
+	return problems
+}
+
+// NewPermissions returns the effective Permissions of the User, granted by the named Roles in User.Roles.
+// Stored Roles take precedence over the DefaultRoles with the same name. Unknown role names grant nothing.
+func NewPermissions(u user.User, stored []Role) Permissions {
+	byName := rolesByName(stored)
+	p := Permissions{
+		UserID: u.ID,
+		Roles:  u.Roles,
+		OrgID:  u.OrgID,
+		Global: []string{},
+		Org:    []string{},
+	}
+	if p.Roles == nil {
+		p.Roles = []string{}
+	}
+	for _, name := range u.Roles {
+		r, ok := byName[name]
+		if !ok {
+			continue
+		}
+		for _, perm := range r.Permissions {
+			if r.OrgScoped {
+				p.Org = addPermission(p.Org, perm)
+			} else {
+				p.Global = addPermission(p.Global, perm)
+			}
+		}
+	}
+	return p
+}
+
+// NewMembershipPermissions returns the effective Permissions of the User, acting within the Organization of
+// the supplied Membership. In the User's primary Organization, these are the same as NewPermissions. In any
+// other Organization, the User keeps their global permissions, and the Membership Roles grant permissions
+// within that Organization only, whether or not the Roles are organization-scoped.
+func NewMembershipPermissions(u user.User, m user.Membership, stored []Role) Permissions {
+	if m.Primary || m.OrgID == u.OrgID {
+		return NewPermissions(u, stored)
+	}
+	byName := rolesByName(stored)
+	p := NewPermissions(u, stored)
+	p.OrgID = m.OrgID
+	p.OrgRoles = m.Roles
+	p.Org = []string{}
+	for _, name := range m.Roles {
+		if r, ok := byName[name]; ok {
+			for _, perm := range r.Permissions {
+				p.Org = addPermission(p.Org, perm)
+			}
+		}
+	}
+	return p
+}
+
+// IsOrgScoped returns true if the named Role (stored or default) is organization-scoped.
+func IsOrgScoped(name string, stored []Role) bool {
+	r, ok := rolesByName(stored)[name]
+	return ok && r.OrgScoped
+}
+
+// rolesByName returns the DefaultRoles, refined by the stored Roles, keyed by name.
+// The admin role may not be redefined.
+func rolesByName(stored []Role) map[string]Role {
+	byName := make(map[string]Role)
+	for _, r := range DefaultRoles {
+		byName[r.Name] = r
+	}
+	for _, r := range stored {
+		if r.Name != Admin {
+			byName[r.Name] = r
+		}
+	}
+	return byName
+}
@@ -0,0 +1,256 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+// ErrDuplicateName is returned when a Role name is already in use by another Role.
+var ErrDuplicateName = errors.New("role name is already in use")
+
+//==============================================================================
+// Role Table
+//==============================================================================
+
+// rowRoles is a TableRow definition for Role versions.
+var rowRoles = v.TableRow[Role]{
+	RowName:      "roles_version",
+	PartKeyName:  "id",
+	PartKeyValue: func(r Role) string { return r.ID },
+	PartKeyLabel: func(r Role) string { return r.Name },
+	SortKeyName:  "version_id",
+	SortKeyValue: func(r Role) string { return r.VersionID },
+	JsonValue:    func(r Role) []byte { return r.CompressedJSON() },
+}
+
+// rowRolesName is a TableRow definition for Roles by Name.
+// There should be only one Role per name. It's effectively a unique ID for the Role.
+var rowRolesName = v.TableRow[Role]{
+	RowName:      "roles_name",
+	PartKeyName:  "name",
+	PartKeyValue: func(r Role) string { return r.Name },
+	SortKeyName:  "id",
+	SortKeyValue: func(r Role) string { return r.ID },
+	JsonValue:    func(r Role) []byte { return r.CompressedJSON() },
+}
+
+// NewTable instantiates a new DynamoDB table for roles.
+func NewTable(dbClient *dynamodb.Client, env string) v.Table[Role] {
+	if env == "" {
+		env = "dev"
+	}
+	return v.Table[Role]{
+		Client:     dbClient,
+		EntityType: "Role",
+		TableName:  "roles" + "_" + env,
+		TTL:        false,
+		EntityRow:  rowRoles,
+		IndexRows: map[string]v.TableRow[Role]{
+			rowRolesName.RowName: rowRolesName,
+		},
+	}
+}
+
+// NewMemTable creates an in-memory Role table for testing purposes.
+func NewMemTable(table v.Table[Role]) v.MemTable[Role] {
+	return v.NewMemTable(table)
+}
+
+//==============================================================================
+// Role Service
+//==============================================================================
+
+// Service is used to manage Roles in a DynamoDB table.
+type Service struct {
+	EntityType string
+	Table      v.TableReadWriter[Role]
+}
+
+// NewService creates a new Role service backed by a Versionary Table for the specified environment.
+func NewService(dbClient *dynamodb.Client, env string) Service {
+	table := NewTable(dbClient, env)
+	return Service{
+		EntityType: table.EntityType,
+		Table:      table,
+	}
+}
+
+// NewMockService creates a new Role service backed by an in-memory table for testing purposes.
+func NewMockService(env string) Service {
+	table := NewMemTable(NewTable(nil, env))
+	return Service{
+		EntityType: table.EntityType,
+		Table:      table,
+	}
+}
+
+// duplicateName returns the IDs of any other Roles with the specified name.
+func (s Service) duplicateName(ctx context.Context, name, id string) ([]string, error) {
+	ids, err := s.Table.ReadAllSortKeyValues(ctx, rowRolesName, name)
+	if err != nil {
+		return nil, err
+	}
+	return v.Filter(ids, func(i string) bool { return i != id }), nil
+}
+
+//------------------------------------------------------------------------------
+// Role Versions
+//------------------------------------------------------------------------------
+
+// Create a Role in the Role table.
+func (s Service) Create(ctx context.Context, r Role) (Role, []string, error) {
+	t := tuid.NewID()
+	at, _ := t.Time()
+	r.ID = t.String()
+	r.CreatedAt = at
+	r.VersionID = t.String()
+	r.UpdatedAt = at
+	if r.Permissions == nil {
+		r.Permissions = []string{}
+	}
+	problems := r.Validate()
+	if len(problems) > 0 {
+		return r, problems, fmt.Errorf("error creating %s %s: invalid field(s): %s", s.EntityType, r.ID, strings.Join(problems, ", "))
+	}
+	duplicates, err := s.duplicateName(ctx, r.Name, r.ID)
+	if err != nil {
+		return r, problems, fmt.Errorf("error checking name duplicates for %s: %w", r.Name, err)
+	}
+	if len(duplicates) > 0 {
+		return r, problems, fmt.Errorf("error creating %s %s: %w: %s (%s)", s.EntityType, r.ID, ErrDuplicateName, r.Name, strings.Join(duplicates, ", "))
+	}
+	err = s.Table.WriteEntity(ctx, r)
+	if err != nil {
+		return r, problems, fmt.Errorf("error creating %s %s %s: %w", s.EntityType, r.ID, r.Name, err)
+	}
+	return r, problems, nil
+}
+
+// Update a Role in the Role table. If a previous version does not exist, the Role is created.
+func (s Service) Update(ctx context.Context, r Role) (Role, []string, error) {
+	t := tuid.NewID()
+	at, _ := t.Time()
+	r.VersionID = t.String()
+	r.UpdatedAt = at
+	if r.Permissions == nil {
+		r.Permissions = []string{}
+	}
+	problems := r.Validate()
+	if len(problems) > 0 {
+		return r, problems, fmt.Errorf("error updating %s %s: invalid field(s): %s", s.EntityType, r.ID, strings.Join(problems, ", "))
+	}
+	duplicates, err := s.duplicateName(ctx, r.Name, r.ID)
+	if err != nil {
+		return r, problems, fmt.Errorf("error checking name duplicates for %s: %w", r.Name, err)
+	}
+	if len(duplicates) > 0 {
+		return r, problems, fmt.Errorf("error updating %s %s: %w: %s (%s)", s.EntityType, r.ID, ErrDuplicateName, r.Name, strings.Join(duplicates, ", "))
+	}
+	return r, problems, s.Table.UpdateEntity(ctx, r)
+}
+
+// Write a Role to the Role table. This method assumes that the Role has all the required fields.
+// It would most likely be used for "refreshing" the index rows in the Role table.
+func (s Service) Write(ctx context.Context, r Role) (Role, error) {
+	return r, s.Table.WriteEntity(ctx, r)
+}
+
+// Delete a Role from the Role table. The deleted Role is returned.
+func (s Service) Delete(ctx context.Context, id string) (Role, error) {
+	return s.Table.DeleteEntityWithID(ctx, id)
+}
+
+// DeleteVersion deletes a specified Role version from the Role table.
+// The deleted Role version is returned.
+func (s Service) DeleteVersion(ctx context.Context, id, versionID string) (Role, error) {
+	return s.Table.DeleteEntityVersionWithID(ctx, id, versionID)
+}
+
+// Exists checks if a Role exists in the Role table.
+func (s Service) Exists(ctx context.Context, id string) bool {
+	return s.Table.EntityExists(ctx, id)
+}
+
+// Read a specified Role from the Role table.
+func (s Service) Read(ctx context.Context, id string) (Role, error) {
+	return s.Table.ReadEntity(ctx, id)
+}
+
+// ReadAsJSON gets a specified Role from the Role table, serialized as JSON.
+func (s Service) ReadAsJSON(ctx context.Context, id string) ([]byte, error) {
+	return s.Table.ReadEntityAsJSON(ctx, id)
+}
+
+// VersionExists checks if a specified Role version exists in the Role table.
+func (s Service) VersionExists(ctx context.Context, id, versionID string) bool {
+	return s.Table.EntityVersionExists(ctx, id, versionID)
+}
+
+// ReadVersion gets a specified Role version from the Role table.
+func (s Service) ReadVersion(ctx context.Context, id, versionID string) (Role, error) {
+	return s.Table.ReadEntityVersion(ctx, id, versionID)
+}
+
+// ReadVersionAsJSON gets a specified Role version from the Role table, serialized as JSON.
+func (s Service) ReadVersionAsJSON(ctx context.Context, id, versionID string) ([]byte, error) {
+	return s.Table.ReadEntityVersionAsJSON(ctx, id, versionID)
+}
+
+// ReadVersions returns paginated versions of the specified Role.
+// Sorting is chronological (or reverse). The offset is the last ID returned in a previous request.
+func (s Service) ReadVersions(ctx context.Context, id string, reverse bool, limit int, offset string) ([]Role, error) {
+	return s.Table.ReadEntityVersions(ctx, id, reverse, limit, offset)
+}
+
+// ReadVersionsAsJSON returns paginated versions of the specified Role, serialized as JSON.
+// Sorting is chronological (or reverse). The offset is the last ID returned in a previous request.
+func (s Service) ReadVersionsAsJSON(ctx context.Context, id string, reverse bool, limit int, offset string) ([]byte, error) {
+	return s.Table.ReadEntityVersionsAsJSON(ctx, id, reverse, limit, offset)
+}
+
+// ReadAllVersions returns all versions of the specified Role in chronological order.
+func (s Service) ReadAllVersions(ctx context.Context, id string) ([]Role, error) {
+	return s.Table.ReadAllEntityVersions(ctx, id)
+}
+
+// ReadAllIDs returns all Role IDs in the Role table.
+func (s Service) ReadAllIDs(ctx context.Context) ([]string, error) {
+	return s.Table.ReadAllEntityIDs(ctx)
+}
+
+// ReadAllNames returns all Role IDs and Names in the Role table.
+func (s Service) ReadAllNames(ctx context.Context, sortByValue bool) ([]v.TextValue, error) {
+	return s.Table.ReadAllEntityLabels(ctx, sortByValue)
+}
+
+// ReadAllRoles returns all the current Roles in the Role table, in chronological order.
+// There are relatively few Roles, so they're retrieved individually, in parallel.
+func (s Service) ReadAllRoles(ctx context.Context) ([]Role, error) {
+	ids, err := s.Table.ReadAllEntityIDs(ctx)
+	if err != nil {
+		return []Role{}, fmt.Errorf("error reading %s IDs: %w", s.EntityType, err)
+	}
+	return s.Table.ReadEntities(ctx, ids), nil
+}
+
+//------------------------------------------------------------------------------
+// Roles by Name
+//------------------------------------------------------------------------------
+
+// ReadRoleByName returns the first (chronological) Role with the provided name.
+func (s Service) ReadRoleByName(ctx context.Context, name string) (Role, error) {
+	roles, err := s.Table.ReadAllEntitiesFromRow(ctx, rowRolesName, name)
+	if err != nil {
+		return Role{}, err
+	}
+	if len(roles) == 0 {
+		return Role{}, v.ErrNotFound
+	}
+	return roles[0], nil
+}
@@ -0,0 +1,266 @@
+package role
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"versionary-api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+var (
+	// Role Service
+	ctx     = context.Background()
+	service = NewMockService("test")
+
+	// Known timestamps
+	t1 = time.Date(2022, time.April, 1, 12, 0, 0, 0, time.UTC)
+	t2 = time.Date(2022, time.April, 1, 13, 0, 0, 0, time.UTC)
+
+	// Role IDs
+	id1 = tuid.NewIDWithTime(t1).String()
+	id2 = tuid.NewIDWithTime(t2).String()
+
+	// Known Roles
+	r10 = Role{
+		ID:          id1,
+		VersionID:   id1,
+		CreatedAt:   t1,
+		UpdatedAt:   t1,
+		Name:        "editor",
+		Description: "Content editor",
+		Permissions: []string{ContentWrite, ImageWrite},
+	}
+	r20 = Role{
+		ID:          id2,
+		VersionID:   id2,
+		CreatedAt:   t2,
+		UpdatedAt:   t2,
+		Name:        "auditor",
+		Description: "Reviews events and email in their own organization",
+		Permissions: []string{EmailRead, EventRead},
+		OrgScoped:   true,
+	}
+	knownIDs = []string{id1, id2}
+)
+
+func TestMain(m *testing.M) {
+	// Check the table/row definitions
+	if !service.Table.IsValid() {
+		log.Fatal("invalid table configuration")
+	}
+	// Write known roles
+	for _, e := range []Role{r10, r20} {
+		if _, err := service.Write(ctx, e); err != nil {
+			log.Fatal(err)
+		}
+	}
+	// Run the tests
+	m.Run()
+}
+
+func TestValidate(t *testing.T) {
+	expect := assert.New(t)
+	expect.Empty(r10.Validate())
+	r := r10
+	r.Name = "Content Editor"
+	r.Permissions = []string{"content:delete", "widget:read", All}
+	problems := r.Validate()
+	expect.Len(problems, 4)
+	r.Name = Admin
+	r.Permissions = []string{All}
+	expect.Len(r.Validate(), 1)
+}
+
+func TestGrants(t *testing.T) {
+	expect := assert.New(t)
+	expect.True(ValidPermission(ContentWrite))
+	expect.True(ValidPermission(All))
+	expect.True(ValidPermission(ContentPublish))
+	expect.False(ValidPermission("user:publish"))
+	expect.False(ValidPermission("content"))
+	expect.False(ValidPermission("content:delete"))
+	expect.True(Grants([]string{ContentWrite}, ContentRead))
+	expect.True(Grants([]string{ContentWrite}, ContentWrite))
+	expect.False(Grants([]string{ContentRead}, ContentWrite))
+	expect.False(Grants([]string{ContentWrite}, ImageRead))
+	expect.True(Grants([]string{All}, RoleWrite))
+	expect.False(Grants(nil, ContentRead))
+}
+
+func TestNewPermissions(t *testing.T) {
+	expect := assert.New(t)
+	orgID := tuid.NewID().String()
+
+	// Administrators have all permissions, even if the admin role is stored
+	p := NewPermissions(user.User{ID: id1, Roles: []string{Admin}}, []Role{{Name: Admin}})
+	expect.True(p.Has(RoleWrite))
+	expect.Equal([]string{All}, p.Global)
+
+	// Users without roles have no permissions
+	p = NewPermissions(user.User{ID: id1, OrgID: orgID}, nil)
+	expect.Empty(p.Global)
+	expect.Empty(p.Org)
+	expect.NotNil(p.Roles)
+	expect.False(p.HasAnywhere(UserRead))
+
+	// Organization administrators manage users in their own organization
+	p = NewPermissions(user.User{ID: id1, OrgID: orgID, Roles: []string{OrgAdmin}}, nil)
+	expect.False(p.Has(UserRead))
+	expect.True(p.HasInOrg(UserRead, orgID))
+	expect.False(p.HasInOrg(UserRead, id2))
+	expect.True(p.HasAnywhere(OrganizationWrite))
+
+	// Stored roles are combined, and override the default roles
+	p = NewPermissions(user.User{ID: id1, OrgID: orgID, Roles: []string{OrgAdmin, "editor", "auditor", "unknown"}},
+		[]Role{r10, r20, {Name: OrgAdmin, Permissions: []string{UserRead}, OrgScoped: true}})
+	expect.Equal([]string{ContentWrite, ImageWrite}, p.Global)
+	expect.Equal([]string{EmailRead, EventRead, UserRead}, p.Org)
+	expect.True(p.Has(ContentRead))
+	expect.False(p.HasInOrg(UserWrite, orgID))
+	expect.True(p.HasInOrg(EventRead, orgID))
+}
+
+func TestNewMembershipPermissions(t *testing.T) {
+	expect := assert.New(t)
+	orgID := tuid.NewID().String()
+	otherID := tuid.NewID().String()
+	u := user.User{ID: id1, OrgID: orgID, Roles: []string{"editor"}}
+
+	// In the primary organization, the User's roles apply
+	m, _ := u.PrimaryMembership()
+	expect.Equal(NewPermissions(u, []Role{r10, r20}), NewMembershipPermissions(u, m, []Role{r10, r20}))
+
+	// In another organization, only the membership roles apply there, even if they're not organization-scoped
+	m = user.Membership{UserID: id1, OrgID: otherID, Roles: []string{OrgAdmin, "editor"}}
+	p := NewMembershipPermissions(u, m, []Role{r10, r20})
+	expect.Equal(otherID, p.OrgID)
+	expect.Equal([]string{OrgAdmin, "editor"}, p.OrgRoles)
+	expect.True(p.Has(ContentWrite))
+	expect.True(p.HasInOrg(UserWrite, otherID))
+	expect.True(p.HasInOrg(ImageWrite, otherID))
+	expect.False(p.HasInOrg(UserWrite, orgID))
+	expect.False(p.Has(UserRead))
+}
+
+func TestSubOrgPermissions(t *testing.T) {
+	expect := assert.New(t)
+	orgID := tuid.NewID().String()
+	subOrgID := tuid.NewID().String()
+	p := NewPermissions(user.User{ID: id1, OrgID: orgID, Roles: []string{OrgAdmin}}, nil)
+	expect.False(p.HasInOrg(UserWrite, subOrgID))
+
+	// Organization permissions extend to the listed descendant organizations
+	p.SubOrgIDs = []string{subOrgID}
+	expect.True(p.InScope(subOrgID))
+	expect.True(p.HasInOrg(UserWrite, subOrgID))
+	expect.True(p.HasInOrg(UserWrite, orgID))
+	expect.False(p.HasInOrg(UserWrite, id2))
+	expect.False(p.HasInOrg(ContentWrite, subOrgID))
+	expect.False(p.InScope(""))
+}
+
+func TestLimitPermissions(t *testing.T) {
+	expect := assert.New(t)
+	orgID := tuid.NewID().String()
+	allow := func(perm string) bool { return perm == ImageWrite || perm == UserRead }
+
+	// The All permission is expanded, and then limited
+	p := NewPermissions(user.User{ID: id1, Roles: []string{Admin}}, nil).Limit(allow)
+	expect.Equal([]string{ImageWrite, UserRead}, p.Global)
+	expect.False(p.Has(RoleWrite))
+	expect.True(p.Has(ImageRead), "write implies read")
+
+	// Organization permissions are limited too, and write permissions are reduced to read, if allowed
+	p = NewPermissions(user.User{ID: id1, OrgID: orgID, Roles: []string{OrgAdmin, "editor"}}, []Role{r10}).Limit(allow)
+	expect.Equal([]string{ImageWrite}, p.Global)
+	expect.Equal([]string{UserRead}, p.Org)
+	expect.False(p.HasInOrg(UserWrite, orgID))
+	expect.True(p.HasInOrg(UserRead, orgID))
+}
+
+func TestIsOrgScoped(t *testing.T) {
+	expect := assert.New(t)
+	expect.True(IsOrgScoped(OrgAdmin, nil))
+	expect.False(IsOrgScoped(Admin, nil))
+	expect.False(IsOrgScoped("unknown", nil))
+	expect.False(IsOrgScoped(OrgAdmin, []Role{{Name: OrgAdmin, Permissions: []string{UserRead}}}))
+	expect.True(IsOrgScoped("auditor", []Role{{Name: "auditor", Permissions: []string{EventRead}, OrgScoped: true}}))
+}
+
+func TestCreateReadUpdateDelete(t *testing.T) {
+	expect := assert.New(t)
+	// Create a role
+	r, problems, err := service.Create(ctx, Role{
+		Name:        "publisher",
+		Permissions: []string{ContentWrite},
+	})
+	expect.Empty(problems)
+	if expect.NoError(err) {
+		// Role exists in the role table
+		expect.True(service.Exists(ctx, r.ID))
+
+		// Read the role
+		rCheck, err := service.Read(ctx, r.ID)
+		if expect.NoError(err) {
+			expect.Equal(r.VersionID, rCheck.VersionID)
+			expect.Equal(r.Permissions, rCheck.Permissions)
+		}
+		// Read the role by name
+		rCheck, err = service.ReadRoleByName(ctx, "publisher")
+		if expect.NoError(err) {
+			expect.Equal(r.ID, rCheck.ID)
+		}
+		// Read the role as JSON
+		rCheckJSON, err := service.ReadAsJSON(ctx, r.ID)
+		if expect.NoError(err) {
+			expect.Contains(string(rCheckJSON), r.ID)
+		}
+		// Names are unique
+		_, _, err = service.Create(ctx, Role{Name: "publisher"})
+		expect.ErrorIs(err, ErrDuplicateName)
+
+		// Update the role
+		r.Permissions = []string{ContentWrite, ImageWrite}
+		rUpdated, _, err := service.Update(ctx, r)
+		if expect.NoError(err) {
+			expect.NotEqual(rUpdated.ID, rUpdated.VersionID)
+			expect.Len(rUpdated.Permissions, 2)
+		}
+		// Delete the role
+		rDeleted, err := service.Delete(ctx, r.ID)
+		if expect.NoError(err) {
+			expect.Equal(rUpdated.VersionID, rDeleted.VersionID)
+		}
+		expect.False(service.Exists(ctx, r.ID))
+		_, err = service.Read(ctx, r.ID)
+		expect.ErrorIs(err, v.ErrNotFound, "expected ErrNotFound")
+	}
+}
+
+func TestReadAllRoles(t *testing.T) {
+	expect := assert.New(t)
+	roles, err := service.ReadAllRoles(ctx)
+	if expect.NoError(err) {
+		expect.Subset(roles, []Role{r10, r20})
+	}
+	ids, err := service.ReadAllIDs(ctx)
+	if expect.NoError(err) {
+		expect.Subset(ids, knownIDs)
+	}
+}
+
+func TestReadRoleByName(t *testing.T) {
+	expect := assert.New(t)
+	r, err := service.ReadRoleByName(ctx, "auditor")
+	if expect.NoError(err) {
+		expect.Equal(r20, r)
+	}
+	_, err = service.ReadRoleByName(ctx, "missing")
+	expect.ErrorIs(err, v.ErrNotFound)
+}
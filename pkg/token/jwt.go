@@ -0,0 +1,325 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/voxtechnica/tuid-go"
+)
+
+// JWT access tokens are signed (JWS compact serialization), so that they may be verified without reading the
+// Token and its User from the database. They embed the User ID, roles, and expiration time. The Token ID is
+// used as the JWT ID (jti), so the Token is still recorded, and it may be listed, introspected, or deleted.
+// However, a signed token remains valid until it expires, so JWT access tokens should be short-lived.
+//
+// Signing keys are identified by a key ID (kid) in the JWT header. A KeySet holds the current signing key,
+// along with previous keys that are retained to verify tokens issued before the most recent key rotation.
+
+// JWT signing algorithms: Ed25519 signatures (RFC 8037), or HMAC with SHA-256 (RFC 7518).
+const (
+	AlgEdDSA = "EdDSA"
+	AlgHS256 = "HS256"
+)
+
+// hmacKeySize is the size of generated HMAC signing keys (256 bits).
+const hmacKeySize = 32
+
+// ErrInvalidJWT is returned when a JWT is malformed, or its signature is invalid.
+var ErrInvalidJWT = errors.New("invalid jwt")
+
+// ErrExpiredJWT is returned when a JWT has expired.
+var ErrExpiredJWT = errors.New("expired jwt")
+
+// ErrUnknownKey is returned when a JWT was signed with a key that is not in the KeySet.
+var ErrUnknownKey = errors.New("unknown jwt signing key")
+
+// IsJWT returns true if the supplied bearer credential looks like a JWT, rather than an opaque Token ID.
+func IsJWT(bearer string) bool {
+	return strings.Count(bearer, ".") == 2
+}
+
+// ValidAlgorithm returns true if the supplied JWT signing algorithm is supported.
+func ValidAlgorithm(alg string) bool {
+	return alg == AlgEdDSA || alg == AlgHS256
+}
+
+//------------------------------------------------------------------------------
+// Claims
+//------------------------------------------------------------------------------
+
+// Claims are the registered and private claims embedded in a JWT access token.
+type Claims struct {
+	TokenID    string   `json:"jti"`                   // Token ID
+	Subject    string   `json:"sub"`                   // User ID
+	Email      string   `json:"email,omitempty"`       // User email address
+	GivenName  string   `json:"given_name,omitempty"`  // User given name
+	FamilyName string   `json:"family_name,omitempty"` // User family name
+	Roles      []string `json:"roles,omitempty"`       // User roles
+	OrgID      string   `json:"org_id,omitempty"`      // User organization ID
+	ActiveOrg  string   `json:"active_org,omitempty"`  // active Organization ID, if not the primary
+	OrgName    string   `json:"org_name,omitempty"`    // active Organization name, if not the primary
+	OrgRoles   []string `json:"org_roles,omitempty"`   // Membership roles in the active Organization
+	ClientID   string   `json:"client_id,omitempty"`   // APIKey ID (client_credentials grant)
+	Scope      string   `json:"scope,omitempty"`       // space-delimited APIKey scopes (client_credentials grant)
+	Issuer     string   `json:"iss,omitempty"`         // API URL
+	IssuedAt   int64    `json:"iat"`                   // issued-at time (Unix seconds)
+	ExpiresAt  int64    `json:"exp"`                   // expiration time (Unix seconds)
+}
+
+// NewClaims creates JWT Claims for the supplied Token. User details (names, roles, and organization),
+// and the User's Membership in the active Organization, are added by the caller.
+func NewClaims(t Token, scopes []string, issuer string) Claims {
+	return Claims{
+		TokenID:   t.ID,
+		Subject:   t.UserID,
+		Email:     t.Email,
+		ClientID:  t.APIKeyID,
+		ActiveOrg: t.OrgID,
+		Scope:     strings.Join(scopes, " "),
+		Issuer:    issuer,
+		IssuedAt:  t.CreatedAt.Unix(),
+		ExpiresAt: t.ExpiresAt.Unix(),
+	}
+}
+
+// Token returns the Token described by the Claims.
+func (c Claims) Token() Token {
+	return Token{
+		ID:        c.TokenID,
+		CreatedAt: time.Unix(c.IssuedAt, 0),
+		ExpiresAt: time.Unix(c.ExpiresAt, 0),
+		UserID:    c.Subject,
+		Email:     c.Email,
+		APIKeyID:  c.ClientID,
+		OrgID:     c.ActiveOrg,
+	}
+}
+
+// Scopes returns the APIKey scopes granted by the Claims, if any.
+func (c Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+//------------------------------------------------------------------------------
+// Signing Keys
+//------------------------------------------------------------------------------
+
+// SigningKey is a JWT signing key. For EdDSA, Key is an Ed25519 private key. For HS256, Key is a shared secret.
+type SigningKey struct {
+	ID        string    `json:"kid"`
+	Algorithm string    `json:"alg"`
+	Key       []byte    `json:"key"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NewSigningKey generates a new random SigningKey for the specified algorithm, identified by a new TUID.
+func NewSigningKey(alg string) (SigningKey, error) {
+	id := tuid.NewID()
+	at, _ := id.Time()
+	k := SigningKey{ID: id.String(), Algorithm: alg, CreatedAt: at}
+	switch alg {
+	case AlgEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return k, fmt.Errorf("error generating %s signing key: %w", alg, err)
+		}
+		k.Key = private
+	case AlgHS256:
+		k.Key = make([]byte, hmacKeySize)
+		if _, err := rand.Read(k.Key); err != nil {
+			return k, fmt.Errorf("error generating %s signing key: %w", alg, err)
+		}
+	default:
+		return k, fmt.Errorf("error generating signing key: unsupported algorithm %q", alg)
+	}
+	return k, nil
+}
+
+// sign returns the signature of the JWS signing input.
+func (k SigningKey) sign(input []byte) ([]byte, error) {
+	switch k.Algorithm {
+	case AlgEdDSA:
+		if len(k.Key) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("invalid %s signing key %s", k.Algorithm, k.ID)
+		}
+		return ed25519.Sign(ed25519.PrivateKey(k.Key), input), nil
+	case AlgHS256:
+		if len(k.Key) == 0 {
+			return nil, fmt.Errorf("invalid %s signing key %s", k.Algorithm, k.ID)
+		}
+		mac := hmac.New(sha256.New, k.Key)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q for signing key %s", k.Algorithm, k.ID)
+	}
+}
+
+// verify checks the signature of the JWS signing input.
+func (k SigningKey) verify(input, signature []byte) bool {
+	switch k.Algorithm {
+	case AlgEdDSA:
+		if len(k.Key) != ed25519.PrivateKeySize {
+			return false
+		}
+		public := ed25519.PrivateKey(k.Key).Public().(ed25519.PublicKey)
+		return ed25519.Verify(public, input, signature)
+	case AlgHS256:
+		expected, err := k.sign(input)
+		return err == nil && hmac.Equal(expected, signature)
+	default:
+		return false
+	}
+}
+
+// JWK returns the public JSON Web Key (RFC 7517) for the SigningKey. HMAC keys are secret,
+// and cannot be published, so false is returned for them.
+func (k SigningKey) JWK() (JWK, bool) {
+	if k.Algorithm != AlgEdDSA || len(k.Key) != ed25519.PrivateKeySize {
+		return JWK{}, false
+	}
+	public := ed25519.PrivateKey(k.Key).Public().(ed25519.PublicKey)
+	return JWK{
+		KeyType:   "OKP",
+		Curve:     "Ed25519",
+		X:         base64.RawURLEncoding.EncodeToString(public),
+		KeyID:     k.ID,
+		Algorithm: k.Algorithm,
+		Use:       "sig",
+	}, true
+}
+
+// JWK is a public JSON Web Key (RFC 7517), for an Ed25519 signing key (RFC 8037).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+}
+
+// JWKS is a JSON Web Key Set, published so that clients may verify JWT access tokens.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+//------------------------------------------------------------------------------
+// Key Sets
+//------------------------------------------------------------------------------
+
+// KeySet holds JWT signing keys, newest first. The first key is used for signing, and all keys
+// are used for verification.
+type KeySet struct {
+	Keys []SigningKey `json:"keys"`
+}
+
+// Current returns the current signing key.
+func (ks KeySet) Current() (SigningKey, bool) {
+	if len(ks.Keys) == 0 {
+		return SigningKey{}, false
+	}
+	return ks.Keys[0], true
+}
+
+// Key returns the signing key with the specified key ID.
+func (ks KeySet) Key(kid string) (SigningKey, bool) {
+	for _, k := range ks.Keys {
+		if k.ID == kid {
+			return k, true
+		}
+	}
+	return SigningKey{}, false
+}
+
+// Rotate generates a new current signing key, retaining up to the specified number of previous keys.
+func (ks KeySet) Rotate(alg string, retain int) (KeySet, error) {
+	k, err := NewSigningKey(alg)
+	if err != nil {
+		return ks, err
+	}
+	if retain > len(ks.Keys) {
+		retain = len(ks.Keys)
+	}
+	keys := append([]SigningKey{k}, ks.Keys[:max(retain, 0)]...)
+	return KeySet{Keys: keys}, nil
+}
+
+// JWKS returns the public keys in the KeySet. HMAC keys are omitted.
+func (ks KeySet) JWKS() JWKS {
+	keys := []JWK{}
+	for _, k := range ks.Keys {
+		if jwk, ok := k.JWK(); ok {
+			keys = append(keys, jwk)
+		}
+	}
+	return JWKS{Keys: keys}
+}
+
+// jwtHeader is the JOSE header of a JWT.
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
+}
+
+// Sign returns a JWT containing the supplied Claims, signed with the current signing key.
+func (ks KeySet) Sign(c Claims) (string, error) {
+	k, ok := ks.Current()
+	if !ok {
+		return "", errors.New("error signing jwt: no signing key")
+	}
+	header, err := json.Marshal(jwtHeader{Algorithm: k.Algorithm, Type: "JWT", KeyID: k.ID})
+	if err != nil {
+		return "", fmt.Errorf("error signing jwt: %w", err)
+	}
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("error signing jwt: %w", err)
+	}
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := k.sign([]byte(input))
+	if err != nil {
+		return "", fmt.Errorf("error signing jwt: %w", err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Verify checks the signature and expiration time of a JWT at the specified time, returning its Claims.
+// The algorithm in the JWT header must match that of the identified signing key.
+func (ks KeySet) Verify(jwt string, at time.Time) (Claims, error) {
+	var c Claims
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return c, ErrInvalidJWT
+	}
+	var h jwtHeader
+	b, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(b, &h) != nil {
+		return c, ErrInvalidJWT
+	}
+	k, ok := ks.Key(h.KeyID)
+	if !ok {
+		return c, ErrUnknownKey
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || h.Algorithm != k.Algorithm || !k.verify([]byte(parts[0]+"."+parts[1]), signature) {
+		return c, ErrInvalidJWT
+	}
+	b, err = base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || json.Unmarshal(b, &c) != nil || c.Subject == "" || c.TokenID == "" {
+		return Claims{}, ErrInvalidJWT
+	}
+	if c.ExpiresAt <= at.Unix() {
+		return c, ErrExpiredJWT
+	}
+	return c, nil
+}
@@ -0,0 +1,148 @@
+package token
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/voxtechnica/tuid-go"
+)
+
+func TestJWTSignVerify(t *testing.T) {
+	expect := assert.New(t)
+	now := time.Now().Truncate(time.Second)
+	tok := Token{
+		ID:        tuid.NewID().String(),
+		CreatedAt: now,
+		ExpiresAt: now.Add(AccessTokenLifetime),
+		UserID:    tuid.NewID().String(),
+		Email:     "jwt@test.com",
+		APIKeyID:  tuid.NewID().String(),
+	}
+	for _, alg := range []string{AlgEdDSA, AlgHS256} {
+		ks, err := KeySet{}.Rotate(alg, 2)
+		if !expect.NoError(err, alg) {
+			continue
+		}
+		claims := NewClaims(tok, []string{"images:read", "images:write"}, "https://api.test.com")
+		claims.Roles = []string{"admin"}
+		jwt, err := ks.Sign(claims)
+		if !expect.NoError(err, alg) {
+			continue
+		}
+		expect.True(IsJWT(jwt), alg)
+		expect.False(IsJWT(tok.ID), alg)
+		// Verify a valid token
+		got, err := ks.Verify(jwt, now)
+		if expect.NoError(err, alg) {
+			expect.Equal(claims, got, alg)
+			expect.Equal(tok, got.Token(), alg)
+			expect.Equal([]string{"images:read", "images:write"}, got.Scopes(), alg)
+		}
+		// Expired tokens still return their claims
+		got, err = ks.Verify(jwt, tok.ExpiresAt)
+		expect.ErrorIs(err, ErrExpiredJWT, alg)
+		expect.Equal(tok.ID, got.TokenID, alg)
+		// Tampered claims are rejected
+		parts := strings.Split(jwt, ".")
+		claims.Roles = nil
+		payload, _ := json.Marshal(claims)
+		tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload) + "." + parts[2]
+		_, err = ks.Verify(tampered, now)
+		expect.ErrorIs(err, ErrInvalidJWT, alg)
+		// Malformed tokens are rejected
+		_, err = ks.Verify("a.b.c", now)
+		expect.ErrorIs(err, ErrInvalidJWT, alg)
+		// Tokens signed with another key are rejected
+		other, _ := KeySet{}.Rotate(alg, 2)
+		_, err = other.Verify(jwt, now)
+		expect.ErrorIs(err, ErrUnknownKey, alg)
+	}
+}
+
+func TestJWTAlgorithmMismatch(t *testing.T) {
+	expect := assert.New(t)
+	now := time.Now()
+	ks, err := KeySet{}.Rotate(AlgHS256, 2)
+	if !expect.NoError(err) {
+		return
+	}
+	jwt, err := ks.Sign(Claims{TokenID: tuid.NewID().String(), Subject: tuid.NewID().String(), ExpiresAt: now.Add(time.Hour).Unix()})
+	if !expect.NoError(err) {
+		return
+	}
+	// Rewrite the header to claim a different algorithm, keeping the key ID and signature
+	k, _ := ks.Current()
+	parts := strings.Split(jwt, ".")
+	header, _ := json.Marshal(jwtHeader{Algorithm: "none", Type: "JWT", KeyID: k.ID})
+	forged := base64.RawURLEncoding.EncodeToString(header) + "." + parts[1] + "." + parts[2]
+	_, err = ks.Verify(forged, now)
+	expect.ErrorIs(err, ErrInvalidJWT)
+}
+
+func TestKeySetRotation(t *testing.T) {
+	expect := assert.New(t)
+	now := time.Now()
+	ks, err := KeySet{}.Rotate(AlgEdDSA, 2)
+	if !expect.NoError(err) {
+		return
+	}
+	first, _ := ks.Current()
+	jwt, err := ks.Sign(Claims{TokenID: tuid.NewID().String(), Subject: tuid.NewID().String(), ExpiresAt: now.Add(time.Hour).Unix()})
+	if !expect.NoError(err) {
+		return
+	}
+	// Previous keys are retained to verify outstanding tokens
+	for i := 0; i < 2; i++ {
+		ks, err = ks.Rotate(AlgEdDSA, 2)
+		if !expect.NoError(err) {
+			return
+		}
+		_, err = ks.Verify(jwt, now)
+		expect.NoError(err, "rotation %d", i+1)
+	}
+	expect.Len(ks.Keys, 3)
+	current, _ := ks.Current()
+	expect.NotEqual(first.ID, current.ID)
+	// Once the first key is discarded, its tokens are no longer verifiable
+	ks, err = ks.Rotate(AlgEdDSA, 2)
+	if expect.NoError(err) {
+		expect.Len(ks.Keys, 3)
+		_, ok := ks.Key(first.ID)
+		expect.False(ok)
+		_, err = ks.Verify(jwt, now)
+		expect.ErrorIs(err, ErrUnknownKey)
+	}
+	// Unsupported algorithms are rejected
+	_, err = ks.Rotate("RS256", 2)
+	expect.Error(err)
+}
+
+func TestJWKS(t *testing.T) {
+	expect := assert.New(t)
+	ks, err := KeySet{}.Rotate(AlgHS256, 2)
+	if !expect.NoError(err) {
+		return
+	}
+	ks, err = ks.Rotate(AlgEdDSA, 2)
+	if !expect.NoError(err) {
+		return
+	}
+	// HMAC keys are secret, so only the EdDSA key is published
+	jwks := ks.JWKS()
+	if expect.Len(jwks.Keys, 1) {
+		k, _ := ks.Current()
+		jwk := jwks.Keys[0]
+		expect.Equal(k.ID, jwk.KeyID)
+		expect.Equal("OKP", jwk.KeyType)
+		expect.Equal("Ed25519", jwk.Curve)
+		expect.Equal(AlgEdDSA, jwk.Algorithm)
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if expect.NoError(err) {
+			expect.Equal([]byte(k.Key[32:]), x, "public key")
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package token
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// OAuth 2.0 grant types supported by the token endpoint.
+const (
+	GrantPassword          = "password"
+	GrantClientCredentials = "client_credentials"
+	GrantRefreshToken      = "refresh_token"
+)
+
+// OAuth 2.0 error codes, from RFC 6749 section 5.2 and RFC 7009 section 2.2.1.
+const (
+	ErrCodeInvalidRequest       = "invalid_request"
+	ErrCodeInvalidClient        = "invalid_client"
+	ErrCodeInvalidGrant         = "invalid_grant"
+	ErrCodeUnauthorizedClient   = "unauthorized_client"
+	ErrCodeUnsupportedGrantType = "unsupported_grant_type"
+	ErrCodeInvalidScope         = "invalid_scope"
+	ErrCodeUnsupportedTokenType = "unsupported_token_type"
+	ErrCodeServerError          = "server_error"
+)
+
+// Token type hints, from RFC 7009 section 2.1.
+const (
+	HintAccessToken  = "access_token"
+	HintRefreshToken = "refresh_token"
+)
+
+// OAuthRequest provides a standards-compliant OAuth 2.0 Access Token Request, submitted as
+// application/x-www-form-urlencoded. Client credentials may also be supplied with HTTP Basic authentication.
+// The "code" parameter is an extension, for Users enrolled in TOTP.
+// For more information, see https://datatracker.ietf.org/doc/html/rfc6749#section-4.3.2
+type OAuthRequest struct {
+	GrantType    string `form:"grant_type"`    // "password", "client_credentials", or "refresh_token"
+	Username     string `form:"username"`      // email or User ID (password grant)
+	Password     string `form:"password"`      // plaintext password (password grant)
+	Code         string `form:"code"`          // TOTP or recovery code (password grant, if enrolled)
+	RefreshToken string `form:"refresh_token"` // RefreshToken ID (refresh_token grant)
+	Scope        string `form:"scope"`         // space-delimited scopes (client_credentials grant)
+	ClientID     string `form:"client_id"`     // APIKey ID (client_credentials grant)
+	ClientSecret string `form:"client_secret"` // APIKey secret (client_credentials grant)
+	DeviceID     string `form:"device_id"`     // Device ID, if known (password grant)
+	OrgID        string `form:"org_id"`        // active Organization ID, if not the primary (password grant)
+}
+
+// OAuthResponse provides a standards-compliant OAuth 2.0 Access Token Response.
+// For more information, see https://datatracker.ietf.org/doc/html/rfc6749#section-5.1
+type OAuthResponse struct {
+	AccessToken  string `json:"access_token"`            // Token ID (a tuid.TUID)
+	TokenType    string `json:"token_type"`              // always "Bearer"
+	ExpiresIn    int64  `json:"expires_in"`              // lifetime of the access token, in seconds
+	RefreshToken string `json:"refresh_token,omitempty"` // RefreshToken ID, for use with the refresh_token grant
+	Scope        string `json:"scope,omitempty"`         // space-delimited scopes (client_credentials grant)
+}
+
+// NewOAuthResponse creates an OAuthResponse for the supplied access Token and (optional) RefreshToken,
+// with the remaining lifetime of the access Token at the specified time.
+func NewOAuthResponse(t Token, rt RefreshToken, scopes []string, at time.Time) OAuthResponse {
+	return OAuthResponse{
+		AccessToken:  t.ID,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(math.Ceil(t.ExpiresAt.Sub(at).Seconds())),
+		RefreshToken: rt.ID,
+		Scope:        strings.Join(scopes, " "),
+	}
+}
+
+// OAuthError provides a standards-compliant OAuth 2.0 Error Response.
+// For more information, see https://datatracker.ietf.org/doc/html/rfc6749#section-5.2
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+// RevocationRequest provides an OAuth 2.0 Token Revocation Request, submitted as application/x-www-form-urlencoded.
+// For more information, see https://datatracker.ietf.org/doc/html/rfc7009#section-2.1
+type RevocationRequest struct {
+	Token         string `form:"token"`           // access Token ID or RefreshToken ID
+	TokenTypeHint string `form:"token_type_hint"` // "access_token" or "refresh_token" (optional)
+}
+
+// Introspection provides an OAuth 2.0 Token Introspection Response. Inactive tokens include only Active.
+// For more information, see https://datatracker.ietf.org/doc/html/rfc7662#section-2.2
+type Introspection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`      // space-delimited scopes (client_credentials grant)
+	ClientID  string `json:"client_id,omitempty"`  // APIKey ID (client_credentials grant)
+	Username  string `json:"username,omitempty"`   // User email address
+	TokenType string `json:"token_type,omitempty"` // "Bearer" or "refresh_token"
+	Exp       int64  `json:"exp,omitempty"`        // expiration time (Unix seconds)
+	Iat       int64  `json:"iat,omitempty"`        // issued-at time (Unix seconds)
+	Sub       string `json:"sub,omitempty"`        // User ID
+}
+
+// Introspect returns an Introspection for the access Token at the specified time.
+// The scopes are those of the associated APIKey, if any.
+func (t Token) Introspect(scopes []string, at time.Time) Introspection {
+	if t.ID == "" || !t.ExpiresAt.After(at) {
+		return Introspection{}
+	}
+	return Introspection{
+		Active:    true,
+		Scope:     strings.Join(scopes, " "),
+		ClientID:  t.APIKeyID,
+		Username:  t.Email,
+		TokenType: "Bearer",
+		Exp:       t.ExpiresAt.Unix(),
+		Iat:       t.CreatedAt.Unix(),
+		Sub:       t.UserID,
+	}
+}
+
+// Introspect returns an Introspection for the RefreshToken at the specified time.
+// RefreshTokens that have been rotated are no longer active.
+func (t RefreshToken) Introspect(at time.Time) Introspection {
+	if t.ID == "" || t.IsRotated() || t.IsExpired(at) {
+		return Introspection{}
+	}
+	return Introspection{
+		Active:    true,
+		Username:  t.Email,
+		TokenType: HintRefreshToken,
+		Exp:       t.ExpiresAt.Unix(),
+		Iat:       t.CreatedAt.Unix(),
+		Sub:       t.UserID,
+	}
+}
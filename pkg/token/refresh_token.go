@@ -0,0 +1,81 @@
+package token
+
+import (
+	"time"
+	"versionary-api/pkg/ref"
+
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+// RefreshToken is a long-lived credential that may be exchanged, exactly once, for a new access Token and
+// a replacement RefreshToken (rotation). All RefreshTokens descended from the same password grant share a
+// FamilyID. If a RefreshToken that has already been rotated is presented again, the whole family is revoked.
+// For more information, see https://datatracker.ietf.org/doc/html/rfc6749#section-6
+type RefreshToken struct {
+	ID            string    `json:"id"`
+	CreatedAt     time.Time `json:"createdAt"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+	FamilyID      string    `json:"familyId"`
+	UserID        string    `json:"userId"`
+	Email         string    `json:"email,omitempty"`
+	AccessTokenID string    `json:"accessTokenId,omitempty"`
+	ReplacedByID  string    `json:"replacedById,omitempty"`
+	DeviceID      string    `json:"deviceId,omitempty"` // inherited by access Tokens issued in the family
+	OrgID         string    `json:"orgId,omitempty"`    // active Organization, inherited by access Tokens issued in the family
+}
+
+// Type returns the entity type of the RefreshToken.
+func (t RefreshToken) Type() string {
+	return "RefreshToken"
+}
+
+// RefID returns the Reference ID of the entity.
+func (t RefreshToken) RefID() ref.RefID {
+	r, _ := ref.NewRefID(t.Type(), t.ID, "")
+	return r
+}
+
+// CompressedJSON returns a compressed JSON representation of the RefreshToken.
+func (t RefreshToken) CompressedJSON() []byte {
+	j, err := v.ToCompressedJSON(t)
+	if err != nil {
+		return nil
+	}
+	return j
+}
+
+// IsRotated returns true if the RefreshToken has already been exchanged for a replacement.
+func (t RefreshToken) IsRotated() bool {
+	return t.ReplacedByID != ""
+}
+
+// IsExpired returns true if the RefreshToken has expired at the specified time.
+func (t RefreshToken) IsExpired(at time.Time) bool {
+	return !t.ExpiresAt.After(at)
+}
+
+// Validate checks whether the RefreshToken has all required fields and whether the supplied values are valid,
+// returning a list of problems. If the list is empty, then the RefreshToken is valid.
+func (t RefreshToken) Validate() []string {
+	var problems []string
+	if t.ID == "" || !tuid.IsValid(tuid.TUID(t.ID)) {
+		problems = append(problems, "ID is missing or invalid")
+	}
+	if t.CreatedAt.IsZero() {
+		problems = append(problems, "CreatedAt is missing")
+	}
+	if t.ExpiresAt.IsZero() {
+		problems = append(problems, "ExpiresAt is missing")
+	}
+	if t.FamilyID == "" || !tuid.IsValid(tuid.TUID(t.FamilyID)) {
+		problems = append(problems, "FamilyID is missing or invalid")
+	}
+	if t.UserID == "" || !tuid.IsValid(tuid.TUID(t.UserID)) {
+		problems = append(problems, "UserID is missing or invalid")
+	}
+	if t.DeviceID != "" && !tuid.IsValid(tuid.TUID(t.DeviceID)) {
+		problems = append(problems, "DeviceID is invalid")
+	}
+	return problems
+}
@@ -0,0 +1,268 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"versionary-api/pkg/util"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+// ErrInvalidRefreshToken is returned when a RefreshToken does not exist or has expired.
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+// ErrRefreshTokenReused is returned when a RefreshToken that has already been rotated is presented again.
+var ErrRefreshTokenReused = errors.New("refresh token reused")
+
+// Token lifetimes. Access tokens issued with a RefreshToken are short-lived.
+// Access tokens issued without one (e.g. by Create) retain the legacy 30-day lifetime.
+const (
+	AccessTokenLifetime  = time.Hour
+	DefaultTokenLifetime = 30 * 24 * time.Hour
+	RefreshTokenLifetime = 30 * 24 * time.Hour
+)
+
+//==============================================================================
+// RefreshToken Table
+//==============================================================================
+
+// rowRefreshTokens is a TableRow definition for RefreshTokens. RefreshTokens are not versioned.
+var rowRefreshTokens = v.TableRow[RefreshToken]{
+	RowName:      "refresh_tokens",
+	PartKeyName:  "id",
+	PartKeyValue: func(t RefreshToken) string { return t.ID },
+	PartKeyLabel: func(t RefreshToken) string { return t.UserID },
+	SortKeyName:  "id",
+	SortKeyValue: func(t RefreshToken) string { return t.ID },
+	JsonValue:    func(t RefreshToken) []byte { return t.CompressedJSON() },
+	TimeToLive:   func(t RefreshToken) int64 { return t.ExpiresAt.Unix() },
+}
+
+// rowRefreshTokensUser is a TableRow definition for RefreshTokens by User ID.
+var rowRefreshTokensUser = v.TableRow[RefreshToken]{
+	RowName:      "refresh_tokens_user",
+	PartKeyName:  "user_id",
+	PartKeyValue: func(t RefreshToken) string { return t.UserID },
+	PartKeyLabel: func(t RefreshToken) string { return t.Email },
+	SortKeyName:  "id",
+	SortKeyValue: func(t RefreshToken) string { return t.ID },
+	JsonValue:    func(t RefreshToken) []byte { return t.CompressedJSON() },
+	TimeToLive:   func(t RefreshToken) int64 { return t.ExpiresAt.Unix() },
+}
+
+// rowRefreshTokensFamily is a TableRow definition for RefreshTokens by Family ID.
+var rowRefreshTokensFamily = v.TableRow[RefreshToken]{
+	RowName:      "refresh_tokens_family",
+	PartKeyName:  "family_id",
+	PartKeyValue: func(t RefreshToken) string { return t.FamilyID },
+	PartKeyLabel: func(t RefreshToken) string { return t.UserID },
+	SortKeyName:  "id",
+	SortKeyValue: func(t RefreshToken) string { return t.ID },
+	JsonValue:    func(t RefreshToken) []byte { return t.CompressedJSON() },
+	TimeToLive:   func(t RefreshToken) int64 { return t.ExpiresAt.Unix() },
+}
+
+// rowRefreshTokenRotations names the claims (see util.Claim) that mark RefreshTokens as rotated, by ID.
+const rowRefreshTokenRotations = "refresh_token_rotations"
+
+// NewRefreshTable instantiates a new DynamoDB table for RefreshTokens.
+func NewRefreshTable(dbClient *dynamodb.Client, env string) v.Table[RefreshToken] {
+	if env == "" {
+		env = "dev"
+	}
+	return v.Table[RefreshToken]{
+		Client:     dbClient,
+		EntityType: "RefreshToken",
+		TableName:  "refresh_tokens" + "_" + env,
+		TTL:        true,
+		EntityRow:  rowRefreshTokens,
+		IndexRows: map[string]v.TableRow[RefreshToken]{
+			rowRefreshTokensUser.RowName:   rowRefreshTokensUser,
+			rowRefreshTokensFamily.RowName: rowRefreshTokensFamily,
+		},
+	}
+}
+
+// NewRefreshMemTable creates an in-memory RefreshToken table for testing purposes.
+func NewRefreshMemTable(table v.Table[RefreshToken]) v.MemTable[RefreshToken] {
+	return v.NewMemTable(table)
+}
+
+//------------------------------------------------------------------------------
+// Refresh Tokens
+//------------------------------------------------------------------------------
+
+// CreateWithRefresh creates a short-lived access Token and a RefreshToken, starting a new token family.
+func (s Service) CreateWithRefresh(ctx context.Context, t Token) (Token, RefreshToken, error) {
+	t, err := s.CreateWithLifetime(ctx, t, AccessTokenLifetime)
+	if err != nil {
+		return t, RefreshToken{}, err
+	}
+	rt, err := s.createRefreshToken(ctx, t, "")
+	return t, rt, err
+}
+
+// Refresh exchanges a RefreshToken for a new access Token and a replacement RefreshToken in the same family.
+// The new access Token inherits the Device of the family, and records the client IP address of the request.
+// The presented RefreshToken is marked as rotated, and retained until it expires, so that reuse can be detected.
+// If the presented RefreshToken has already been rotated, the entire family is revoked, and
+// ErrRefreshTokenReused is returned.
+func (s Service) Refresh(ctx context.Context, refreshID, clientIP string) (Token, RefreshToken, error) {
+	rt, err := s.RefreshTable.ReadEntity(ctx, refreshID)
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		return Token{}, rt, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return Token{}, rt, fmt.Errorf("error reading RefreshToken-%s: %w", refreshID, err)
+	}
+	if rt.IsRotated() {
+		return Token{}, rt, s.revokeReused(ctx, rt)
+	}
+	if rt.IsExpired(time.Now()) {
+		return Token{}, rt, ErrInvalidRefreshToken
+	}
+	// Claim the rotation with a conditional write, so that only one of several concurrent requests presenting
+	// the same refresh token succeeds. The others are treated as reuse.
+	err = util.WriteClaim(ctx, s.RefreshTable, util.Claim{
+		RowName:   rowRefreshTokenRotations,
+		Key:       rt.ID,
+		Owner:     tuid.NewID().String(),
+		ExpiresAt: rt.ExpiresAt,
+	})
+	if errors.Is(err, util.ErrClaimed) {
+		return Token{}, rt, s.revokeReused(ctx, rt)
+	}
+	if err != nil {
+		return Token{}, rt, fmt.Errorf("error rotating RefreshToken-%s: %w", rt.ID, err)
+	}
+	// Issue a new access token and a replacement refresh token
+	t, err := s.CreateWithLifetime(ctx, Token{
+		UserID:   rt.UserID,
+		Email:    rt.Email,
+		DeviceID: rt.DeviceID,
+		OrgID:    rt.OrgID,
+		ClientIP: clientIP,
+	}, AccessTokenLifetime)
+	if err != nil {
+		return t, rt, err
+	}
+	next, err := s.createRefreshToken(ctx, t, rt.FamilyID)
+	if err != nil {
+		return t, next, err
+	}
+	// Mark the presented refresh token as rotated
+	rt.ReplacedByID = next.ID
+	if err = s.RefreshTable.WriteEntity(ctx, rt); err != nil {
+		return t, next, fmt.Errorf("error rotating RefreshToken-%s: %w", rt.ID, err)
+	}
+	return t, next, nil
+}
+
+// revokeReused revokes the family of a reused RefreshToken, returning ErrRefreshTokenReused.
+func (s Service) revokeReused(ctx context.Context, rt RefreshToken) error {
+	if err := s.RevokeFamily(ctx, rt.FamilyID); err != nil {
+		return fmt.Errorf("%w: %w", ErrRefreshTokenReused, err)
+	}
+	return ErrRefreshTokenReused
+}
+
+// createRefreshToken creates a RefreshToken for the supplied access Token. If the familyID is empty,
+// a new token family is started, identified by the ID of its first RefreshToken.
+func (s Service) createRefreshToken(ctx context.Context, t Token, familyID string) (RefreshToken, error) {
+	id := tuid.NewID()
+	at, _ := id.Time()
+	rt := RefreshToken{
+		ID:            id.String(),
+		CreatedAt:     at,
+		ExpiresAt:     at.Add(RefreshTokenLifetime),
+		FamilyID:      familyID,
+		UserID:        t.UserID,
+		Email:         t.Email,
+		AccessTokenID: t.ID,
+		DeviceID:      t.DeviceID,
+		OrgID:         t.OrgID,
+	}
+	if rt.FamilyID == "" {
+		rt.FamilyID = rt.ID
+	}
+	if problems := rt.Validate(); len(problems) > 0 {
+		return rt, fmt.Errorf("error creating %s %s: invalid field(s): %s", rt.Type(), rt.ID, strings.Join(problems, ", "))
+	}
+	if err := s.RefreshTable.WriteEntity(ctx, rt); err != nil {
+		return rt, fmt.Errorf("error creating RefreshToken-%s for User-%s: %w", rt.ID, rt.UserID, err)
+	}
+	return rt, nil
+}
+
+// ReadRefreshToken reads a specified RefreshToken from the RefreshToken table.
+func (s Service) ReadRefreshToken(ctx context.Context, id string) (RefreshToken, error) {
+	return s.RefreshTable.ReadEntity(ctx, id)
+}
+
+// WriteRefreshToken writes a RefreshToken to the RefreshToken table. This method assumes that the RefreshToken has
+// all the required fields. It would most likely be used for "refreshing" the index rows in the RefreshToken table.
+func (s Service) WriteRefreshToken(ctx context.Context, rt RefreshToken) (RefreshToken, error) {
+	return rt, s.RefreshTable.WriteEntity(ctx, rt)
+}
+
+// DeleteRefreshToken deletes a specified RefreshToken from the RefreshToken table. The deleted RefreshToken is returned.
+func (s Service) DeleteRefreshToken(ctx context.Context, id string) (RefreshToken, error) {
+	return s.RefreshTable.DeleteEntityWithID(ctx, id)
+}
+
+// ReadAllRefreshTokensByFamilyID returns the complete list of RefreshTokens in a family, sorted chronologically.
+func (s Service) ReadAllRefreshTokensByFamilyID(ctx context.Context, familyID string) ([]RefreshToken, error) {
+	return s.RefreshTable.ReadAllEntitiesFromRow(ctx, rowRefreshTokensFamily, familyID)
+}
+
+// ReadAllRefreshTokensByUserID returns the complete list of RefreshTokens for a User, sorted chronologically.
+func (s Service) ReadAllRefreshTokensByUserID(ctx context.Context, userID string) ([]RefreshToken, error) {
+	return s.RefreshTable.ReadAllEntitiesFromRow(ctx, rowRefreshTokensUser, userID)
+}
+
+// RevokeFamily deletes all RefreshTokens in a family, along with the access Tokens that were issued with them.
+func (s Service) RevokeFamily(ctx context.Context, familyID string) error {
+	family, err := s.ReadAllRefreshTokensByFamilyID(ctx, familyID)
+	if err != nil {
+		return fmt.Errorf("error revoking RefreshToken family %s: %w", familyID, err)
+	}
+	for _, rt := range family {
+		if err = s.deleteRefreshTokenAndAccessToken(ctx, rt); err != nil {
+			return fmt.Errorf("error revoking RefreshToken family %s: %w", familyID, err)
+		}
+	}
+	return nil
+}
+
+// DeleteAllRefreshTokensByUserID deletes all RefreshTokens for a specified User ID, along with
+// the access Tokens that were issued with them.
+func (s Service) DeleteAllRefreshTokensByUserID(ctx context.Context, userID string) error {
+	tokens, err := s.ReadAllRefreshTokensByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("error deleting all RefreshTokens for User-%s: %w", userID, err)
+	}
+	for _, rt := range tokens {
+		if err = s.deleteRefreshTokenAndAccessToken(ctx, rt); err != nil {
+			return fmt.Errorf("error deleting all RefreshTokens for User-%s: %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// deleteRefreshTokenAndAccessToken deletes a RefreshToken and its associated access Token, if it still exists.
+func (s Service) deleteRefreshTokenAndAccessToken(ctx context.Context, rt RefreshToken) error {
+	if rt.AccessTokenID != "" {
+		if _, err := s.Delete(ctx, rt.AccessTokenID); err != nil && !errors.Is(err, v.ErrNotFound) {
+			return fmt.Errorf("error deleting Token-%s: %w", rt.AccessTokenID, err)
+		}
+	}
+	if _, err := s.DeleteRefreshToken(ctx, rt.ID); err != nil && !errors.Is(err, v.ErrNotFound) {
+		return fmt.Errorf("error deleting RefreshToken-%s: %w", rt.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package token
+
+import (
+	"testing"
+	"time"
+	"versionary-api/pkg/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+func TestCreateWithRefresh(t *testing.T) {
+	expect := assert.New(t)
+	userID := tuid.NewID().String()
+	at, rt, err := service.CreateWithRefresh(ctx, Token{UserID: userID, Email: "refresh_user@test.net"})
+	if expect.NoError(err) {
+		// Access tokens are short-lived
+		expect.Equal(AccessTokenLifetime, at.ExpiresAt.Sub(at.CreatedAt))
+		expect.True(service.Exists(ctx, at.ID))
+		// The refresh token starts a new family
+		expect.Empty(rt.Validate())
+		expect.Equal(rt.ID, rt.FamilyID)
+		expect.Equal(at.ID, rt.AccessTokenID)
+		expect.Equal(userID, rt.UserID)
+		expect.Equal(RefreshTokenLifetime, rt.ExpiresAt.Sub(rt.CreatedAt))
+		expect.False(rt.IsRotated())
+		// The refresh token is stored in its own table
+		check, err := service.ReadRefreshToken(ctx, rt.ID)
+		if expect.NoError(err) {
+			expect.Equal(rt.ID, check.ID)
+			expect.Equal(rt.FamilyID, check.FamilyID)
+		}
+		// Clean up
+		expect.NoError(service.DeleteAllTokensByUserID(ctx, userID))
+		expect.False(service.Exists(ctx, at.ID))
+		_, err = service.ReadRefreshToken(ctx, rt.ID)
+		expect.ErrorIs(err, v.ErrNotFound)
+	}
+}
+
+func TestRefreshRotation(t *testing.T) {
+	expect := assert.New(t)
+	userID := tuid.NewID().String()
+	at1, rt1, err := service.CreateWithRefresh(ctx, Token{UserID: userID})
+	if !expect.NoError(err) {
+		return
+	}
+	// Exchange the refresh token
+	at2, rt2, err := service.Refresh(ctx, rt1.ID, "")
+	if expect.NoError(err) {
+		expect.NotEqual(at1.ID, at2.ID)
+		expect.NotEqual(rt1.ID, rt2.ID)
+		expect.Equal(rt1.FamilyID, rt2.FamilyID)
+		expect.Equal(at2.ID, rt2.AccessTokenID)
+		expect.Equal(userID, at2.UserID)
+	}
+	// The presented refresh token was rotated
+	check, err := service.ReadRefreshToken(ctx, rt1.ID)
+	if expect.NoError(err) {
+		expect.True(check.IsRotated())
+		expect.Equal(rt2.ID, check.ReplacedByID)
+	}
+	// The replacement may be exchanged in turn
+	at3, rt3, err := service.Refresh(ctx, rt2.ID, "")
+	if expect.NoError(err) {
+		expect.Equal(rt1.FamilyID, rt3.FamilyID)
+		expect.True(service.Exists(ctx, at3.ID))
+	}
+	family, err := service.ReadAllRefreshTokensByFamilyID(ctx, rt1.FamilyID)
+	if expect.NoError(err) {
+		expect.Equal(3, len(family))
+	}
+	// Clean up
+	expect.NoError(service.DeleteAllTokensByUserID(ctx, userID))
+}
+
+func TestRefreshReuse(t *testing.T) {
+	expect := assert.New(t)
+	userID := tuid.NewID().String()
+	at1, rt1, err := service.CreateWithRefresh(ctx, Token{UserID: userID})
+	if !expect.NoError(err) {
+		return
+	}
+	at2, rt2, err := service.Refresh(ctx, rt1.ID, "")
+	if !expect.NoError(err) {
+		return
+	}
+	// An unrelated family for the same user is not affected
+	at3, rt3, err := service.CreateWithRefresh(ctx, Token{UserID: userID})
+	if !expect.NoError(err) {
+		return
+	}
+	// Reusing a rotated refresh token revokes the whole family
+	_, _, err = service.Refresh(ctx, rt1.ID, "")
+	expect.ErrorIs(err, ErrRefreshTokenReused)
+	expect.False(service.Exists(ctx, at1.ID))
+	expect.False(service.Exists(ctx, at2.ID))
+	_, err = service.ReadRefreshToken(ctx, rt2.ID)
+	expect.ErrorIs(err, v.ErrNotFound)
+	_, _, err = service.Refresh(ctx, rt2.ID, "")
+	expect.ErrorIs(err, ErrInvalidRefreshToken)
+	// The other family still works
+	expect.True(service.Exists(ctx, at3.ID))
+	_, _, err = service.Refresh(ctx, rt3.ID, "")
+	expect.NoError(err)
+	// Clean up
+	expect.NoError(service.DeleteAllTokensByUserID(ctx, userID))
+}
+
+func TestRefreshClaimed(t *testing.T) {
+	expect := assert.New(t)
+	userID := tuid.NewID().String()
+	at, rt, err := service.CreateWithRefresh(ctx, Token{UserID: userID})
+	if !expect.NoError(err) {
+		return
+	}
+	// A concurrent request has claimed the rotation, but not yet recorded it
+	expect.NoError(util.WriteClaim(ctx, service.RefreshTable, util.Claim{
+		RowName: rowRefreshTokenRotations,
+		Key:     rt.ID,
+		Owner:   tuid.NewID().String(),
+	}))
+	_, _, err = service.Refresh(ctx, rt.ID, "")
+	expect.ErrorIs(err, ErrRefreshTokenReused)
+	expect.False(service.Exists(ctx, at.ID))
+	// Clean up
+	expect.NoError(service.DeleteAllTokensByUserID(ctx, userID))
+}
+
+func TestRefreshInvalid(t *testing.T) {
+	expect := assert.New(t)
+	// Unknown refresh token
+	_, _, err := service.Refresh(ctx, tuid.NewID().String(), "")
+	expect.ErrorIs(err, ErrInvalidRefreshToken)
+	// Expired refresh token
+	id := tuid.NewIDWithTime(t1).String()
+	expired := RefreshToken{
+		ID:        id,
+		CreatedAt: t1,
+		ExpiresAt: t1.Add(time.Hour),
+		FamilyID:  id,
+		UserID:    user1,
+	}
+	expect.NoError(service.RefreshTable.WriteEntity(ctx, expired))
+	_, _, err = service.Refresh(ctx, expired.ID, "")
+	expect.ErrorIs(err, ErrInvalidRefreshToken)
+	_, err = service.DeleteRefreshToken(ctx, expired.ID)
+	expect.NoError(err)
+}
+
+func TestOAuthIntrospection(t *testing.T) {
+	expect := assert.New(t)
+	now := time.Now()
+	at := Token{
+		ID:        tuid.NewID().String(),
+		CreatedAt: now,
+		ExpiresAt: now.Add(AccessTokenLifetime),
+		UserID:    tuid.NewID().String(),
+		Email:     "oauth@test.com",
+		APIKeyID:  tuid.NewID().String(),
+	}
+	expect.Empty(at.Validate())
+	res := NewOAuthResponse(at, RefreshToken{}, []string{"images:read", "images:write"}, now)
+	expect.Equal(int64(AccessTokenLifetime.Seconds()), res.ExpiresIn)
+	expect.Equal("Bearer", res.TokenType)
+	expect.Equal("images:read images:write", res.Scope)
+	expect.Empty(res.RefreshToken)
+	i := at.Introspect([]string{"images:read"}, now)
+	expect.True(i.Active)
+	expect.Equal(at.APIKeyID, i.ClientID)
+	expect.Equal(at.UserID, i.Sub)
+	expect.Equal(at.ExpiresAt.Unix(), i.Exp)
+	expect.Equal(Introspection{}, at.Introspect(nil, at.ExpiresAt), "expired")
+	rt := RefreshToken{
+		ID:        tuid.NewID().String(),
+		CreatedAt: now,
+		ExpiresAt: now.Add(RefreshTokenLifetime),
+		UserID:    at.UserID,
+	}
+	expect.True(rt.Introspect(now).Active)
+	expect.Equal(HintRefreshToken, rt.Introspect(now).TokenType)
+	rt.ReplacedByID = tuid.NewID().String()
+	expect.False(rt.Introspect(now).Active, "rotated")
+}
@@ -0,0 +1,44 @@
+package token
+
+import (
+	"time"
+
+	ua "github.com/voxtechnica/user-agent"
+)
+
+// Session describes an active access Token, as a place where a User is signed in: the Device (parsed
+// User-Agent header) and client IP address that requested the Token, and when it was last used.
+type Session struct {
+	TokenID    string        `json:"tokenId"`
+	CreatedAt  time.Time     `json:"createdAt"`
+	ExpiresAt  time.Time     `json:"expiresAt"`
+	LastUsedAt time.Time     `json:"lastUsedAt"`
+	UserID     string        `json:"userId"`
+	APIKeyID   string        `json:"apiKeyId,omitempty"`
+	DeviceID   string        `json:"deviceId,omitempty"`
+	OrgID      string        `json:"orgId,omitempty"`
+	ClientIP   string        `json:"clientIp,omitempty"`
+	UserAgent  *ua.UserAgent `json:"userAgent,omitempty"`
+	Current    bool          `json:"current"` // the Token used to make the request
+}
+
+// NewSession describes the supplied Token as a Session. The UserAgent of its Device may be nil, if unknown.
+func NewSession(t Token, agent *ua.UserAgent, current bool) Session {
+	lastUsed := t.LastUsedAt
+	if lastUsed.Before(t.CreatedAt) {
+		lastUsed = t.CreatedAt
+	}
+	return Session{
+		TokenID:    t.ID,
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: lastUsed,
+		UserID:     t.UserID,
+		APIKeyID:   t.APIKeyID,
+		DeviceID:   t.DeviceID,
+		OrgID:      t.OrgID,
+		ClientIP:   t.ClientIP,
+		UserAgent:  agent,
+		Current:    current,
+	}
+}
@@ -11,11 +11,16 @@ import (
 // Token models an OAuth2 Resource Owner Password Credentials Grant in this system.
 // For more information, see http://tools.ietf.org/html/rfc6749#section-4.3
 type Token struct {
-	ID        string    `json:"id"`
-	CreatedAt time.Time `json:"createdAt"`
-	ExpiresAt time.Time `json:"expiresAt"`
-	UserID    string    `json:"userId"`
-	Email     string    `json:"email,omitempty"`
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	LastUsedAt time.Time `json:"lastUsedAt,omitempty"`
+	UserID     string    `json:"userId"`
+	Email      string    `json:"email,omitempty"`
+	APIKeyID   string    `json:"apiKeyId,omitempty"` // APIKey used for a client_credentials grant
+	DeviceID   string    `json:"deviceId,omitempty"` // Device that requested the Token
+	OrgID      string    `json:"orgId,omitempty"`    // active Organization, if not the User's primary Organization
+	ClientIP   string    `json:"clientIp,omitempty"` // client IP address that requested the Token
 }
 
 // Type returns the entity type of the Token.
@@ -52,25 +57,46 @@ func (t Token) Validate() []string {
 	if t.UserID == "" || !tuid.IsValid(tuid.TUID(t.UserID)) {
 		problems = append(problems, "UserID is missing or invalid")
 	}
+	if t.APIKeyID != "" && !tuid.IsValid(tuid.TUID(t.APIKeyID)) {
+		problems = append(problems, "APIKeyID is invalid")
+	}
+	if t.DeviceID != "" && !tuid.IsValid(tuid.TUID(t.DeviceID)) {
+		problems = append(problems, "DeviceID is invalid")
+	}
+	if t.OrgID != "" && !tuid.IsValid(tuid.TUID(t.OrgID)) {
+		problems = append(problems, "OrgID is invalid")
+	}
 	return problems
 }
 
+// IsExpired returns true if the Token has expired at the specified time.
+func (t Token) IsExpired(at time.Time) bool {
+	return !t.ExpiresAt.After(at)
+}
+
 // Request provides a Bearer Token Request in a loose interpretation of the OAuth 2 Specification.
 // It's "loose", because we're allowing Content-Type application/json instead of
 // application/x-www-form-urlencoded, and because we're using camelCase instead of snake_case.
+// For a standards-compliant request, see OAuthRequest.
 // For more information, see http://tools.ietf.org/html/rfc6749#section-4.3.2
 type Request struct {
-	GrantType string `json:"grantType"` // usually "password"
-	Username  string `json:"username"`  // email or User ID
-	Password  string `json:"password"`  // plaintext password
+	GrantType    string `json:"grantType"`              // "password" (default) or "refresh_token"
+	Username     string `json:"username"`               // email or User ID (password grant)
+	Password     string `json:"password"`               // plaintext password (password grant)
+	RefreshToken string `json:"refreshToken,omitempty"` // RefreshToken ID (refresh_token grant)
+	Code         string `json:"code,omitempty"`         // TOTP or recovery code (password grant, if enrolled)
+	DeviceID     string `json:"deviceId,omitempty"`     // Device ID, if known (password grant)
+	OrgID        string `json:"orgId,omitempty"`        // active Organization ID, if not the primary (password grant)
 }
 
 // Response provides a Bearer Token Response in a loose interpretation of the OAuth 2 Specification.
 // It's "loose", because we're using camelCase instead of snake_case.
 // Also, we're providing an expiration timestamp instead of a duration in seconds.
+// For a standards-compliant response, see OAuthResponse.
 // For more information, see https://datatracker.ietf.org/doc/html/rfc6749#section-5.1
 type Response struct {
-	AccessToken string    `json:"accessToken"` // Token ID (a tuid.TUID)
-	TokenType   string    `json:"tokenType"`   // usually "Bearer"
-	ExpiresAt   time.Time `json:"expiresAt"`   // when the token expires (DynamoDB TTL)
+	AccessToken  string    `json:"accessToken"`            // Token ID (a tuid.TUID)
+	TokenType    string    `json:"tokenType"`              // usually "Bearer"
+	ExpiresAt    time.Time `json:"expiresAt"`              // when the token expires (DynamoDB TTL)
+	RefreshToken string    `json:"refreshToken,omitempty"` // RefreshToken ID, for use with the refresh_token grant
 }
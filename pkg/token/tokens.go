@@ -2,8 +2,10 @@ package token
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 	"versionary-api/pkg/util"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -65,27 +67,30 @@ func NewMemTable(table v.Table[Token]) v.MemTable[Token] {
 // Token Service
 //==============================================================================
 
-// Service is used to manage Tokens in a DynamoDB table.
+// Service is used to manage Tokens and RefreshTokens in DynamoDB tables.
 type Service struct {
-	EntityType string
-	Table      v.TableReadWriter[Token]
+	EntityType   string
+	Table        v.TableReadWriter[Token]
+	RefreshTable v.TableReadWriter[RefreshToken]
 }
 
-// NewService creates a new Token service backed by a Versionary Table for the specified environment.
+// NewService creates a new Token service backed by Versionary Tables for the specified environment.
 func NewService(dbClient *dynamodb.Client, env string) Service {
 	table := NewTable(dbClient, env)
 	return Service{
-		EntityType: table.EntityType,
-		Table:      table,
+		EntityType:   table.EntityType,
+		Table:        table,
+		RefreshTable: NewRefreshTable(dbClient, env),
 	}
 }
 
-// NewMockService creates a new Token service backed by an in-memory table for testing purposes.
+// NewMockService creates a new Token service backed by in-memory tables for testing purposes.
 func NewMockService(env string) Service {
 	table := NewMemTable(NewTable(nil, env))
 	return Service{
-		EntityType: table.EntityType,
-		Table:      table,
+		EntityType:   table.EntityType,
+		Table:        table,
+		RefreshTable: NewRefreshMemTable(NewRefreshTable(nil, env)),
 	}
 }
 
@@ -93,13 +98,18 @@ func NewMockService(env string) Service {
 // Tokens
 //------------------------------------------------------------------------------
 
-// Create a Token in the Token table.
+// Create a Token in the Token table, with the default 30-day lifetime.
 func (s Service) Create(ctx context.Context, t Token) (Token, error) {
+	return s.CreateWithLifetime(ctx, t, DefaultTokenLifetime)
+}
+
+// CreateWithLifetime creates a Token in the Token table, expiring after the specified lifetime.
+func (s Service) CreateWithLifetime(ctx context.Context, t Token, lifetime time.Duration) (Token, error) {
 	id := tuid.NewID()
 	at, _ := id.Time()
 	t.ID = id.String()
 	t.CreatedAt = at
-	t.ExpiresAt = at.AddDate(0, 0, 30)
+	t.ExpiresAt = at.Add(lifetime)
 	if problems := t.Validate(); len(problems) > 0 {
 		return t, fmt.Errorf("error creating %s %s: invalid field(s): %s", s.EntityType, t.ID, strings.Join(problems, ", "))
 	}
@@ -226,8 +236,11 @@ func (s Service) ReadAllTokensByUserIDAsJSON(ctx context.Context, userID string)
 	return s.Table.ReadAllEntitiesFromRowAsJSON(ctx, rowTokensUser, userID)
 }
 
-// DeleteAllTokensByUserID deletes all Tokens for a specified User ID from the Token table.
+// DeleteAllTokensByUserID deletes all Tokens and RefreshTokens for a specified User ID.
 func (s Service) DeleteAllTokensByUserID(ctx context.Context, userID string) error {
+	if err := s.DeleteAllRefreshTokensByUserID(ctx, userID); err != nil {
+		return err
+	}
 	ids, err := s.ReadAllTokenIDsByUserID(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("error deleting all Tokens for User-%s: %w", userID, err)
@@ -240,3 +253,83 @@ func (s Service) DeleteAllTokensByUserID(ctx context.Context, userID string) err
 	}
 	return nil
 }
+
+//------------------------------------------------------------------------------
+// Sessions
+//------------------------------------------------------------------------------
+
+// LastUsedInterval limits how often a Token's LastUsedAt time is recorded, to limit the cost of tracking usage.
+const LastUsedInterval = 5 * time.Minute
+
+// Touch records the time a Token was last used, writing it at most once per LastUsedInterval.
+// The Token is written with a conditional write, so that a Token deleted by another request (e.g. revoked
+// by signing out everywhere) is not recreated.
+func (s Service) Touch(ctx context.Context, t Token, at time.Time) (Token, error) {
+	if at.Sub(t.LastUsedAt) < LastUsedInterval {
+		return t, nil
+	}
+	t.LastUsedAt = at
+	err := util.UpdateExisting(ctx, s.Table, t)
+	if errors.Is(err, v.ErrNotFound) {
+		return t, nil
+	}
+	if err != nil {
+		return t, fmt.Errorf("error updating Token-%s last used time: %w", t.ID, err)
+	}
+	return t, nil
+}
+
+// ReadActiveTokensByUserID returns the unexpired Tokens for a specified User ID, sorted chronologically.
+func (s Service) ReadActiveTokensByUserID(ctx context.Context, userID string, at time.Time) ([]Token, error) {
+	tokens, err := s.ReadAllTokensByUserID(ctx, userID)
+	if err != nil {
+		return tokens, err
+	}
+	active := make([]Token, 0, len(tokens))
+	for _, t := range tokens {
+		if !t.IsExpired(at) {
+			active = append(active, t)
+		}
+	}
+	return active, nil
+}
+
+// DeleteOtherTokensByUserID deletes all Tokens and RefreshTokens for a specified User ID, except for the
+// specified Token and the RefreshToken family it belongs to, so that the current session remains signed in.
+// The IDs of the deleted access Tokens are returned.
+func (s Service) DeleteOtherTokensByUserID(ctx context.Context, userID, keepID string) ([]string, error) {
+	ids, err := s.ReadAllTokenIDsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting other Tokens for User-%s: %w", userID, err)
+	}
+	refreshTokens, err := s.ReadAllRefreshTokensByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting other Tokens for User-%s: %w", userID, err)
+	}
+	var keepFamilyID string
+	for _, rt := range refreshTokens {
+		if keepID != "" && rt.AccessTokenID == keepID {
+			keepFamilyID = rt.FamilyID
+			break
+		}
+	}
+	for _, rt := range refreshTokens {
+		if keepFamilyID != "" && rt.FamilyID == keepFamilyID {
+			continue
+		}
+		if err = s.deleteRefreshTokenAndAccessToken(ctx, rt); err != nil {
+			return nil, fmt.Errorf("error deleting other Tokens for User-%s: %w", userID, err)
+		}
+	}
+	deleted := []string{}
+	for _, id := range ids {
+		if id == keepID {
+			continue
+		}
+		if _, err = s.Delete(ctx, id); err != nil && !errors.Is(err, v.ErrNotFound) {
+			return deleted, fmt.Errorf("error deleting Token-%s for User-%s: %w", id, userID, err)
+		}
+		deleted = append(deleted, id)
+	}
+	return deleted, nil
+}
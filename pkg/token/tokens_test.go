@@ -81,7 +81,7 @@ var (
 
 func TestMain(m *testing.M) {
 	// Check table/row definitions
-	if !service.Table.IsValid() {
+	if !service.Table.IsValid() || !service.RefreshTable.IsValid() {
 		log.Fatal("invalid table configuration")
 	}
 	// Write known tokens
@@ -272,3 +272,82 @@ func TestDeleteAllTokensByUserID(t *testing.T) {
 	err := service.DeleteAllTokensByUserID(ctx, user3)
 	expect.NoError(err)
 }
+
+func TestSessions(t *testing.T) {
+	expect := assert.New(t)
+	userID := tuid.NewID().String()
+	deviceID := tuid.NewID().String()
+	// Sessions record their Device and client IP address
+	at1, rt1, err := service.CreateWithRefresh(ctx, Token{UserID: userID, DeviceID: deviceID, ClientIP: "203.0.113.1"})
+	if !expect.NoError(err) {
+		return
+	}
+	expect.Equal(deviceID, rt1.DeviceID)
+	// Refreshed Tokens inherit the Device, and record the new client IP address
+	at2, _, err := service.Refresh(ctx, rt1.ID, "203.0.113.2")
+	if expect.NoError(err) {
+		expect.Equal(deviceID, at2.DeviceID)
+		expect.Equal("203.0.113.2", at2.ClientIP)
+	}
+	at3, _, err := service.CreateWithRefresh(ctx, Token{UserID: userID, ClientIP: "203.0.113.3"})
+	if !expect.NoError(err) {
+		return
+	}
+	at4, err := service.Create(ctx, Token{UserID: userID})
+	if !expect.NoError(err) {
+		return
+	}
+	// Touch records the last used time, at most once per interval
+	now := time.Now()
+	touched, err := service.Touch(ctx, at1, now)
+	if expect.NoError(err) {
+		expect.Equal(now, touched.LastUsedAt)
+		check, err := service.Read(ctx, at1.ID)
+		if expect.NoError(err) {
+			expect.True(now.Equal(check.LastUsedAt))
+		}
+		tokens, err := service.ReadAllTokensByUserID(ctx, userID)
+		if expect.NoError(err) {
+			for _, t := range tokens {
+				if t.ID == at1.ID {
+					expect.True(now.Equal(t.LastUsedAt), "Tokens by User")
+				}
+			}
+		}
+	}
+	touched, err = service.Touch(ctx, touched, now.Add(time.Minute))
+	if expect.NoError(err) {
+		expect.Equal(now, touched.LastUsedAt)
+	}
+	session := NewSession(touched, nil, true)
+	expect.Equal(at1.ID, session.TokenID)
+	expect.Equal(deviceID, session.DeviceID)
+	expect.True(session.Current)
+	// Active Tokens exclude expired ones
+	active, err := service.ReadActiveTokensByUserID(ctx, userID, now)
+	if expect.NoError(err) {
+		expect.Len(active, 4)
+	}
+	active, err = service.ReadActiveTokensByUserID(ctx, userID, now.Add(2*AccessTokenLifetime))
+	if expect.NoError(err) && expect.Len(active, 1) {
+		expect.Equal(at4.ID, active[0].ID)
+	}
+	// Deleting other Tokens retains the current Token and its RefreshToken family
+	deleted, err := service.DeleteOtherTokensByUserID(ctx, userID, at2.ID)
+	if expect.NoError(err) {
+		expect.ElementsMatch([]string{at1.ID, at3.ID, at4.ID}, deleted)
+		expect.True(service.Exists(ctx, at2.ID))
+		expect.False(service.Exists(ctx, at3.ID))
+		family, err := service.ReadAllRefreshTokensByUserID(ctx, userID)
+		if expect.NoError(err) && expect.Len(family, 2) {
+			expect.Equal(rt1.FamilyID, family[0].FamilyID)
+			expect.Equal(rt1.FamilyID, family[1].FamilyID)
+		}
+	}
+	// Touch does not restore a deleted Token
+	_, err = service.Touch(ctx, at3, now)
+	if expect.NoError(err) {
+		expect.False(service.Exists(ctx, at3.ID))
+	}
+	expect.NoError(service.DeleteAllTokensByUserID(ctx, userID))
+}
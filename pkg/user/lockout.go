@@ -0,0 +1,166 @@
+package user
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v "github.com/voxtechnica/versionary"
+)
+
+// Lockout kinds: failed logins are tracked separately per User and per client IP address.
+// Self-service registrations are also rate-limited per client IP address.
+const (
+	LockoutUser         = "user"
+	LockoutIP           = "ip"
+	LockoutRegistration = "registration"
+)
+
+// LockoutPolicy configures brute-force protection for a kind of Lockout. Once Threshold consecutive
+// failures have been recorded, each further failure locks the subject for BaseDelay, doubling with each
+// additional failure, up to MaxDelay. Failures are forgotten after a quiet period of ResetAfter.
+type LockoutPolicy struct {
+	Threshold  int           `json:"threshold"`
+	BaseDelay  time.Duration `json:"baseDelay"`
+	MaxDelay   time.Duration `json:"maxDelay"`
+	ResetAfter time.Duration `json:"resetAfter"`
+}
+
+// ParseLockoutPolicy parses a LockoutPolicy in the form "threshold/baseDelay/maxDelay/resetAfter", with
+// durations in the form accepted by time.ParseDuration (e.g. "5/1m/1h/24h"). A threshold of 0 disables lockouts.
+func ParseLockoutPolicy(s string) (LockoutPolicy, error) {
+	fields := strings.Split(strings.TrimSpace(s), "/")
+	if len(fields) != 4 {
+		return LockoutPolicy{}, fmt.Errorf("invalid lockout policy %q: expecting threshold/baseDelay/maxDelay/resetAfter", s)
+	}
+	var p LockoutPolicy
+	var err error
+	if p.Threshold, err = strconv.Atoi(fields[0]); err != nil || p.Threshold < 0 {
+		return LockoutPolicy{}, fmt.Errorf("invalid lockout policy %q: threshold must be a non-negative integer", s)
+	}
+	durations := []*time.Duration{&p.BaseDelay, &p.MaxDelay, &p.ResetAfter}
+	for i, d := range durations {
+		if *d, err = time.ParseDuration(fields[i+1]); err != nil || *d <= 0 {
+			return LockoutPolicy{}, fmt.Errorf("invalid lockout policy %q: %s is not a positive duration", s, fields[i+1])
+		}
+	}
+	if p.MaxDelay < p.BaseDelay {
+		return LockoutPolicy{}, fmt.Errorf("invalid lockout policy %q: maxDelay is less than baseDelay", s)
+	}
+	return p, nil
+}
+
+// String returns the LockoutPolicy in the form accepted by ParseLockoutPolicy.
+func (p LockoutPolicy) String() string {
+	return fmt.Sprintf("%d/%s/%s/%s", p.Threshold, p.BaseDelay, p.MaxDelay, p.ResetAfter)
+}
+
+// Delay returns the lockout duration for the specified number of consecutive failures.
+// No delay is imposed until the Threshold has been reached.
+func (p LockoutPolicy) Delay(failures int) time.Duration {
+	if p.Threshold <= 0 || failures < p.Threshold {
+		return 0
+	}
+	d := p.BaseDelay
+	for i := p.Threshold; i < failures && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// Lockout tracks consecutive failed login attempts for a User or a client IP address,
+// and the time until which further login attempts are refused.
+type Lockout struct {
+	ID          string    `json:"id"`      // kind and subject, e.g. "user:<User ID>" or "ip:<IP address>"
+	Kind        string    `json:"kind"`    // "user", "ip", or "registration"
+	Subject     string    `json:"subject"` // User ID or IP address
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"lastFailure"`
+	LockedUntil time.Time `json:"lockedUntil,omitempty"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// NewLockout returns an empty Lockout for the specified kind and subject.
+func NewLockout(kind, subject string) Lockout {
+	return Lockout{
+		ID:      LockoutID(kind, subject),
+		Kind:    kind,
+		Subject: subject,
+	}
+}
+
+// LockoutID returns the Lockout ID for the specified kind and subject.
+func LockoutID(kind, subject string) string {
+	return kind + ":" + subject
+}
+
+// Type returns the entity type of the Lockout.
+func (l Lockout) Type() string {
+	return "Lockout"
+}
+
+// CompressedJSON returns a compressed JSON representation of the Lockout.
+func (l Lockout) CompressedJSON() []byte {
+	j, err := v.ToCompressedJSON(l)
+	if err != nil {
+		return nil
+	}
+	return j
+}
+
+// IsLocked returns true if login attempts are refused at the specified time.
+func (l Lockout) IsLocked(at time.Time) bool {
+	return l.LockedUntil.After(at)
+}
+
+// RetryAfter returns the time remaining until the Lockout expires, rounded up to the next second.
+func (l Lockout) RetryAfter(at time.Time) time.Duration {
+	if !l.IsLocked(at) {
+		return 0
+	}
+	d := l.LockedUntil.Sub(at)
+	if r := d % time.Second; r > 0 {
+		d += time.Second - r
+	}
+	return d
+}
+
+// Fail records a failed login attempt at the specified time, locking the subject if the policy requires it.
+func (l Lockout) Fail(p LockoutPolicy, at time.Time) Lockout {
+	if !l.LastFailure.IsZero() && at.Sub(l.LastFailure) > p.ResetAfter {
+		l.Failures = 0
+	}
+	l.Failures++
+	l.LastFailure = at
+	if d := p.Delay(l.Failures); d > 0 {
+		l.LockedUntil = at.Add(d)
+	}
+	l.ExpiresAt = at.Add(p.ResetAfter)
+	if l.LockedUntil.After(at) {
+		l.ExpiresAt = l.LockedUntil.Add(p.ResetAfter)
+	}
+	return l
+}
+
+// Validate checks whether the Lockout has all required fields and whether the supplied values are valid,
+// returning a list of problems. If the list is empty, then the Lockout is valid.
+func (l Lockout) Validate() []string {
+	var problems []string
+	if l.Kind != LockoutUser && l.Kind != LockoutIP && l.Kind != LockoutRegistration {
+		problems = append(problems, "Kind is missing or invalid. Expecting: user, ip, registration")
+	}
+	if l.Subject == "" {
+		problems = append(problems, "Subject is missing")
+	}
+	if l.ID != LockoutID(l.Kind, l.Subject) {
+		problems = append(problems, "ID is missing or invalid")
+	}
+	if l.ExpiresAt.IsZero() {
+		problems = append(problems, "ExpiresAt is missing")
+	}
+	return problems
+}
@@ -0,0 +1,275 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	v "github.com/voxtechnica/versionary"
+
+	"versionary-api/pkg/util"
+)
+
+// Default lockout policies. Client IP addresses may be shared by many Users (e.g. behind a NAT gateway),
+// so they are allowed more failures than an individual User. Registrations from a client IP address
+// are counted like failures, so that a burst of sign-ups locks the address for a while.
+var (
+	DefaultUserLockoutPolicy = LockoutPolicy{
+		Threshold:  5,
+		BaseDelay:  time.Minute,
+		MaxDelay:   time.Hour,
+		ResetAfter: 24 * time.Hour,
+	}
+	DefaultIPLockoutPolicy = LockoutPolicy{
+		Threshold:  50,
+		BaseDelay:  time.Minute,
+		MaxDelay:   time.Hour,
+		ResetAfter: 24 * time.Hour,
+	}
+	DefaultRegistrationLockoutPolicy = LockoutPolicy{
+		Threshold:  10,
+		BaseDelay:  time.Hour,
+		MaxDelay:   24 * time.Hour,
+		ResetAfter: time.Hour,
+	}
+)
+
+//==============================================================================
+// Lockout Table
+//==============================================================================
+
+// rowLockouts is a TableRow definition for Lockouts. Lockouts are not versioned. The text value is the time of
+// the last failure, which changes with every write, so that concurrent failures are detected (see recordFailure).
+var rowLockouts = v.TableRow[Lockout]{
+	RowName:      "lockouts",
+	PartKeyName:  "id",
+	PartKeyValue: func(l Lockout) string { return l.ID },
+	PartKeyLabel: func(l Lockout) string { return l.Subject },
+	SortKeyName:  "id",
+	SortKeyValue: func(l Lockout) string { return l.ID },
+	JsonValue:    func(l Lockout) []byte { return l.CompressedJSON() },
+	TextValue:    func(l Lockout) string { return l.LastFailure.Format(time.RFC3339Nano) },
+	TimeToLive:   func(l Lockout) int64 { return l.ExpiresAt.Unix() },
+}
+
+// rowLockoutsKind is a TableRow definition for Lockouts by kind ("user", "ip", or "registration").
+var rowLockoutsKind = v.TableRow[Lockout]{
+	RowName:      "lockouts_kind",
+	PartKeyName:  "kind",
+	PartKeyValue: func(l Lockout) string { return l.Kind },
+	PartKeyLabel: func(l Lockout) string { return l.Subject },
+	SortKeyName:  "id",
+	SortKeyValue: func(l Lockout) string { return l.ID },
+	JsonValue:    func(l Lockout) []byte { return l.CompressedJSON() },
+	TimeToLive:   func(l Lockout) int64 { return l.ExpiresAt.Unix() },
+}
+
+// NewLockoutTable instantiates a new DynamoDB table for Lockouts.
+func NewLockoutTable(dbClient *dynamodb.Client, env string) v.Table[Lockout] {
+	if env == "" {
+		env = "dev"
+	}
+	return v.Table[Lockout]{
+		Client:     dbClient,
+		EntityType: "Lockout",
+		TableName:  "lockouts" + "_" + env,
+		TTL:        true,
+		EntityRow:  rowLockouts,
+		IndexRows: map[string]v.TableRow[Lockout]{
+			rowLockoutsKind.RowName: rowLockoutsKind,
+		},
+	}
+}
+
+// NewLockoutMemTable creates an in-memory Lockout table for testing purposes.
+func NewLockoutMemTable(table v.Table[Lockout]) v.MemTable[Lockout] {
+	return v.NewMemTable(table)
+}
+
+//==============================================================================
+// Lockout Service
+//==============================================================================
+
+// LockoutService tracks failed login attempts per User and per client IP address, and registrations per
+// client IP address, temporarily refusing further attempts once the configured policy threshold is reached.
+type LockoutService struct {
+	EntityType         string
+	Table              v.TableReadWriter[Lockout]
+	UserPolicy         LockoutPolicy
+	IPPolicy           LockoutPolicy
+	RegistrationPolicy LockoutPolicy
+}
+
+// NewLockoutService creates a new Lockout service backed by a Versionary Table for the specified environment.
+func NewLockoutService(dbClient *dynamodb.Client, env string) LockoutService {
+	table := NewLockoutTable(dbClient, env)
+	return LockoutService{
+		EntityType:         table.EntityType,
+		Table:              table,
+		UserPolicy:         DefaultUserLockoutPolicy,
+		IPPolicy:           DefaultIPLockoutPolicy,
+		RegistrationPolicy: DefaultRegistrationLockoutPolicy,
+	}
+}
+
+// NewMockLockoutService creates a new Lockout service backed by an in-memory table for testing purposes.
+func NewMockLockoutService(env string) LockoutService {
+	table := NewLockoutMemTable(NewLockoutTable(nil, env))
+	return LockoutService{
+		EntityType:         table.EntityType,
+		Table:              table,
+		UserPolicy:         DefaultUserLockoutPolicy,
+		IPPolicy:           DefaultIPLockoutPolicy,
+		RegistrationPolicy: DefaultRegistrationLockoutPolicy,
+	}
+}
+
+// policy returns the LockoutPolicy for the specified kind.
+func (s LockoutService) policy(kind string) LockoutPolicy {
+	switch kind {
+	case LockoutIP:
+		return s.IPPolicy
+	case LockoutRegistration:
+		return s.RegistrationPolicy
+	default:
+		return s.UserPolicy
+	}
+}
+
+//------------------------------------------------------------------------------
+// Lockouts
+//------------------------------------------------------------------------------
+
+// Read a specified Lockout from the Lockout table. If no failures have been recorded
+// for the subject, an empty Lockout is returned, along with a versionary.ErrNotFound error.
+func (s LockoutService) Read(ctx context.Context, kind, subject string) (Lockout, error) {
+	l, err := s.Table.ReadEntity(ctx, LockoutID(kind, subject))
+	if err != nil {
+		return NewLockout(kind, subject), err
+	}
+	return l, nil
+}
+
+// Check returns the active Lockout for the specified User and/or client IP address, if either is locked.
+// The Lockout with the latest expiration is returned. Empty subjects are ignored.
+func (s LockoutService) Check(ctx context.Context, userID, ip string) (Lockout, bool, error) {
+	now := time.Now()
+	var locked Lockout
+	for _, kind := range []string{LockoutUser, LockoutIP} {
+		subject := userID
+		if kind == LockoutIP {
+			subject = ip
+		}
+		if subject == "" {
+			continue
+		}
+		l, err := s.Read(ctx, kind, subject)
+		if err != nil && !errors.Is(err, v.ErrNotFound) {
+			return l, false, fmt.Errorf("error checking %s %s: %w", s.EntityType, l.ID, err)
+		}
+		if l.IsLocked(now) && l.LockedUntil.After(locked.LockedUntil) {
+			locked = l
+		}
+	}
+	return locked, locked.ID != "", nil
+}
+
+// RecordFailure records a failed login attempt for the specified User and/or client IP address.
+// Empty subjects are ignored. The Lockouts that were locked by this failure are returned.
+func (s LockoutService) RecordFailure(ctx context.Context, userID, ip string) ([]Lockout, error) {
+	now := time.Now()
+	var locked []Lockout
+	for _, kind := range []string{LockoutUser, LockoutIP} {
+		subject := userID
+		if kind == LockoutIP {
+			subject = ip
+		}
+		if subject == "" {
+			continue
+		}
+		l, err := s.recordFailure(ctx, kind, subject)
+		if err != nil {
+			return locked, fmt.Errorf("error recording login failure for %s: %w", l.ID, err)
+		}
+		if l.IsLocked(now) {
+			locked = append(locked, l)
+		}
+	}
+	return locked, nil
+}
+
+// RecordSuccess clears the failed login attempts for a User after a successful login.
+// Client IP address failures are retained until they expire, so that a single valid
+// account cannot be used to reset the failure count of an address.
+func (s LockoutService) RecordSuccess(ctx context.Context, userID string) error {
+	_, err := s.Unlock(ctx, LockoutUser, userID)
+	if err != nil && !errors.Is(err, v.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// CheckRegistration returns the active registration Lockout for the specified client IP address, if it is locked.
+func (s LockoutService) CheckRegistration(ctx context.Context, ip string) (Lockout, bool, error) {
+	l, err := s.Read(ctx, LockoutRegistration, ip)
+	if err != nil && !errors.Is(err, v.ErrNotFound) {
+		return l, false, fmt.Errorf("error checking %s %s: %w", s.EntityType, l.ID, err)
+	}
+	return l, l.IsLocked(time.Now()), nil
+}
+
+// RecordRegistration records a self-service registration attempt from the specified client IP address.
+// The updated Lockout is returned; it is locked once the registration policy threshold is reached.
+func (s LockoutService) RecordRegistration(ctx context.Context, ip string) (Lockout, error) {
+	l, err := s.recordFailure(ctx, LockoutRegistration, ip)
+	if err != nil {
+		return l, fmt.Errorf("error recording registration for %s: %w", l.ID, err)
+	}
+	return l, nil
+}
+
+// lockoutRetries limits how many times a failure is recorded again after a conflicting write.
+const lockoutRetries = 10
+
+// recordFailure records a failure (a failed login attempt, or a registration) for the specified kind and subject,
+// returning the updated Lockout. Each failure is written with a conditional write, and recorded again if another
+// request recorded a failure since the Lockout was read, so that concurrent failures are all counted.
+func (s LockoutService) recordFailure(ctx context.Context, kind, subject string) (Lockout, error) {
+	for i := 0; ; i++ {
+		prior, err := s.Read(ctx, kind, subject)
+		exists := err == nil
+		if err != nil && !errors.Is(err, v.ErrNotFound) {
+			return prior, err
+		}
+		l := prior.Fail(s.policy(kind), time.Now())
+		if problems := l.Validate(); len(problems) > 0 {
+			return l, fmt.Errorf("invalid field(s): %s", strings.Join(problems, ", "))
+		}
+		err = util.CompareAndWrite(ctx, s.Table, prior, exists, l)
+		if errors.Is(err, util.ErrConflict) && i < lockoutRetries {
+			continue
+		}
+		return l, err
+	}
+}
+
+// Unlock clears the failed login attempts for the specified kind and subject. The deleted Lockout is returned.
+// If no failures have been recorded, a versionary.ErrNotFound error is returned.
+func (s LockoutService) Unlock(ctx context.Context, kind, subject string) (Lockout, error) {
+	l, err := s.Table.DeleteEntityWithID(ctx, LockoutID(kind, subject))
+	if err != nil && errors.Is(err, v.ErrNotFound) {
+		return NewLockout(kind, subject), err
+	}
+	if err != nil {
+		return l, fmt.Errorf("error unlocking %s: %w", LockoutID(kind, subject), err)
+	}
+	return l, nil
+}
+
+// ReadAllLockoutsByKind returns all current Lockouts of the specified kind ("user", "ip", or "registration").
+func (s LockoutService) ReadAllLockoutsByKind(ctx context.Context, kind string) ([]Lockout, error) {
+	return s.Table.ReadAllEntitiesFromRow(ctx, rowLockoutsKind, kind)
+}
@@ -0,0 +1,177 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+
+	"versionary-api/pkg/util"
+)
+
+var lockoutService = NewMockLockoutService("test")
+
+func TestLockoutPolicyDelay(t *testing.T) {
+	expect := assert.New(t)
+	p := LockoutPolicy{Threshold: 3, BaseDelay: time.Minute, MaxDelay: 10 * time.Minute, ResetAfter: time.Hour}
+	expect.Equal(time.Duration(0), p.Delay(0))
+	expect.Equal(time.Duration(0), p.Delay(2))
+	expect.Equal(time.Minute, p.Delay(3))
+	expect.Equal(2*time.Minute, p.Delay(4))
+	expect.Equal(4*time.Minute, p.Delay(5))
+	expect.Equal(8*time.Minute, p.Delay(6))
+	expect.Equal(10*time.Minute, p.Delay(7))
+	expect.Equal(10*time.Minute, p.Delay(1000))
+	expect.Equal(time.Duration(0), LockoutPolicy{}.Delay(1000), "disabled policy")
+}
+
+func TestParseLockoutPolicy(t *testing.T) {
+	expect := assert.New(t)
+	p, err := ParseLockoutPolicy("3/30s/10m/1h")
+	if expect.NoError(err) {
+		expect.Equal(LockoutPolicy{Threshold: 3, BaseDelay: 30 * time.Second, MaxDelay: 10 * time.Minute, ResetAfter: time.Hour}, p)
+	}
+	p, err = ParseLockoutPolicy(DefaultUserLockoutPolicy.String())
+	if expect.NoError(err) {
+		expect.Equal(DefaultUserLockoutPolicy, p)
+	}
+	for _, s := range []string{"", "5/1m/1h", "-1/1m/1h/24h", "five/1m/1h/24h", "5/0s/1h/24h", "5/1m/1h/forever", "5/1h/1m/24h"} {
+		_, err = ParseLockoutPolicy(s)
+		expect.Error(err, s)
+	}
+}
+
+func TestLockoutFail(t *testing.T) {
+	expect := assert.New(t)
+	p := LockoutPolicy{Threshold: 2, BaseDelay: time.Minute, MaxDelay: time.Hour, ResetAfter: time.Hour}
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := NewLockout(LockoutUser, tuid.NewID().String())
+	l = l.Fail(p, at)
+	expect.Equal(1, l.Failures)
+	expect.False(l.IsLocked(at))
+	expect.Empty(l.Validate())
+	l = l.Fail(p, at.Add(time.Second))
+	expect.True(l.IsLocked(at.Add(time.Second)))
+	expect.Equal(time.Minute, l.RetryAfter(at.Add(time.Second)))
+	expect.False(l.IsLocked(at.Add(2 * time.Minute)))
+	// Exponential backoff
+	l = l.Fail(p, at.Add(2*time.Minute))
+	expect.Equal(2*time.Minute, l.RetryAfter(at.Add(2*time.Minute)))
+	// Failures are forgotten after a quiet period
+	l = l.Fail(p, at.Add(3*time.Hour))
+	expect.Equal(1, l.Failures)
+	expect.False(l.IsLocked(at.Add(3 * time.Hour)))
+	// Invalid lockouts
+	expect.NotEmpty(Lockout{Kind: "bogus", Subject: "x"}.Validate())
+}
+
+func TestLockoutService(t *testing.T) {
+	expect := assert.New(t)
+	userID := tuid.NewID().String()
+	ip := "192.0.2.10"
+	// No failures recorded
+	_, locked, err := lockoutService.Check(ctx, userID, ip)
+	expect.NoError(err)
+	expect.False(locked)
+	_, err = lockoutService.Unlock(ctx, LockoutUser, userID)
+	expect.ErrorIs(err, v.ErrNotFound)
+	// Record failures up to the user threshold
+	for i := 1; i < lockoutService.UserPolicy.Threshold; i++ {
+		imposed, err := lockoutService.RecordFailure(ctx, userID, ip)
+		expect.NoError(err)
+		expect.Empty(imposed)
+	}
+	imposed, err := lockoutService.RecordFailure(ctx, userID, ip)
+	if expect.NoError(err) && expect.Len(imposed, 1) {
+		expect.Equal(LockoutID(LockoutUser, userID), imposed[0].ID)
+	}
+	l, locked, err := lockoutService.Check(ctx, userID, ip)
+	expect.NoError(err)
+	expect.True(locked)
+	expect.Equal(LockoutUser, l.Kind)
+	// The client IP address is tracked, but not locked
+	_, locked, err = lockoutService.Check(ctx, "", ip)
+	expect.NoError(err)
+	expect.False(locked)
+	ipLockout, err := lockoutService.Read(ctx, LockoutIP, ip)
+	if expect.NoError(err) {
+		expect.Equal(lockoutService.UserPolicy.Threshold, ipLockout.Failures)
+	}
+	users, err := lockoutService.ReadAllLockoutsByKind(ctx, LockoutUser)
+	expect.NoError(err)
+	expect.Contains(v.Map(users, func(l Lockout) string { return l.Subject }), userID)
+	// A successful login clears the user failures, but not the IP address failures
+	expect.NoError(lockoutService.RecordSuccess(ctx, userID))
+	expect.NoError(lockoutService.RecordSuccess(ctx, userID))
+	_, locked, err = lockoutService.Check(ctx, userID, "")
+	expect.NoError(err)
+	expect.False(locked)
+	expect.True(lockoutService.Table.EntityExists(ctx, LockoutID(LockoutIP, ip)))
+	// Unlock the IP address
+	cleared, err := lockoutService.Unlock(ctx, LockoutIP, ip)
+	if expect.NoError(err) {
+		expect.Equal(ip, cleared.Subject)
+	}
+}
+
+func TestRegistrationLockout(t *testing.T) {
+	expect := assert.New(t)
+	ip := "192.0.2.20"
+	for i := 0; i < lockoutService.RegistrationPolicy.Threshold-1; i++ {
+		l, err := lockoutService.RecordRegistration(ctx, ip)
+		expect.NoError(err)
+		expect.False(l.IsLocked(time.Now()))
+	}
+	_, locked, err := lockoutService.CheckRegistration(ctx, ip)
+	expect.NoError(err)
+	expect.False(locked)
+	// Reaching the threshold locks the client IP address for registration, but not for login
+	l, err := lockoutService.RecordRegistration(ctx, ip)
+	if expect.NoError(err) {
+		expect.Equal(LockoutRegistration, l.Kind)
+		expect.Empty(l.Validate())
+	}
+	l, locked, err = lockoutService.CheckRegistration(ctx, ip)
+	expect.NoError(err)
+	expect.True(locked)
+	expect.Equal(lockoutService.RegistrationPolicy.BaseDelay, l.RetryAfter(l.LastFailure))
+	_, locked, err = lockoutService.Check(ctx, "", ip)
+	expect.NoError(err)
+	expect.False(locked)
+	// Clean up
+	_, err = lockoutService.Unlock(ctx, LockoutRegistration, ip)
+	expect.NoError(err)
+}
+
+func TestLockoutConflict(t *testing.T) {
+	expect := assert.New(t)
+	userID := tuid.NewID().String()
+	p := lockoutService.UserPolicy
+	// A failure recorded from a stale read is rejected, rather than overwriting a concurrent failure
+	_, err := lockoutService.RecordFailure(ctx, userID, "")
+	expect.NoError(err)
+	stale, err := lockoutService.Read(ctx, LockoutUser, userID)
+	expect.NoError(err)
+	_, err = lockoutService.RecordFailure(ctx, userID, "")
+	expect.NoError(err)
+	err = util.CompareAndWrite(ctx, lockoutService.Table, stale, true, stale.Fail(p, time.Now()))
+	expect.ErrorIs(err, util.ErrConflict)
+	// An existing Lockout is not replaced by a new one
+	empty := NewLockout(LockoutUser, userID)
+	err = util.CompareAndWrite(ctx, lockoutService.Table, empty, false, empty.Fail(p, time.Now()))
+	expect.ErrorIs(err, util.ErrConflict)
+	// Both failures were counted
+	l, err := lockoutService.Read(ctx, LockoutUser, userID)
+	if expect.NoError(err) {
+		expect.Equal(2, l.Failures)
+	}
+	lockouts, err := lockoutService.ReadAllLockoutsByKind(ctx, LockoutUser)
+	if expect.NoError(err) {
+		expect.Contains(v.Map(lockouts, func(l Lockout) int { return l.Failures }), 2)
+	}
+	// Clean up
+	_, err = lockoutService.Unlock(ctx, LockoutUser, userID)
+	expect.NoError(err)
+}
@@ -0,0 +1,95 @@
+package user
+
+import (
+	"errors"
+	"time"
+
+	"versionary-api/pkg/ref"
+
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+// ErrDuplicateMembership is returned when a User is already a member of the Organization.
+var ErrDuplicateMembership = errors.New("user is already a member of the organization")
+
+// ErrPrimaryMembership is returned when a User's primary Organization membership (User.OrgID) is
+// modified or removed directly. It follows the User, and is changed by updating the User.
+var ErrPrimaryMembership = errors.New("membership is in the user's primary organization")
+
+// Membership records that a User belongs to an Organization, with the specified roles within it.
+// A User belongs to their primary Organization (User.OrgID), where User.Roles apply, and may be a
+// member of any number of other Organizations, where only the Membership roles apply.
+type Membership struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	VersionID string    `json:"versionID"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	UserID    string    `json:"userId"`
+	Email     string    `json:"email,omitempty"`
+	OrgID     string    `json:"orgId"`
+	OrgName   string    `json:"orgName,omitempty"`
+	Roles     []string  `json:"roles,omitempty"`
+	Primary   bool      `json:"primary,omitempty"` // true for the User's primary Organization (User.OrgID)
+}
+
+// Type returns the entity type of the Membership.
+func (m Membership) Type() string {
+	return "Membership"
+}
+
+// RefID returns the Reference ID of the entity.
+func (m Membership) RefID() ref.RefID {
+	r, _ := ref.NewRefID(m.Type(), m.ID, m.VersionID)
+	return r
+}
+
+// CompressedJSON returns a compressed JSON representation of the Membership.
+func (m Membership) CompressedJSON() []byte {
+	j, err := v.ToCompressedJSON(m)
+	if err != nil {
+		return nil
+	}
+	return j
+}
+
+// Validate checks whether the Membership has all required fields and whether the supplied values are valid,
+// returning a list of problems. If the list is empty, then the Membership is valid.
+func (m Membership) Validate() []string {
+	var problems []string
+	if m.ID == "" || !tuid.IsValid(tuid.TUID(m.ID)) {
+		problems = append(problems, "ID is missing or invalid")
+	}
+	if m.CreatedAt.IsZero() {
+		problems = append(problems, "CreatedAt is missing")
+	}
+	if m.VersionID == "" || !tuid.IsValid(tuid.TUID(m.VersionID)) {
+		problems = append(problems, "VersionID is missing or invalid")
+	}
+	if m.UpdatedAt.IsZero() {
+		problems = append(problems, "UpdatedAt is missing")
+	}
+	if m.UserID == "" || !tuid.IsValid(tuid.TUID(m.UserID)) {
+		problems = append(problems, "UserID is missing or invalid")
+	}
+	if m.OrgID == "" || !tuid.IsValid(tuid.TUID(m.OrgID)) {
+		problems = append(problems, "OrgID is missing or invalid")
+	}
+	return problems
+}
+
+// PrimaryMembership returns the User's membership in their primary Organization, or false if the User
+// does not belong to an Organization. The Membership has no ID; it reflects the User, rather than a stored entity.
+func (u User) PrimaryMembership() (Membership, bool) {
+	if u.OrgID == "" {
+		return Membership{}, false
+	}
+	return Membership{
+		UserID:  u.ID,
+		Email:   u.Email,
+		OrgID:   u.OrgID,
+		OrgName: u.OrgName,
+		Roles:   u.Roles,
+		Primary: true,
+	}, true
+}
@@ -0,0 +1,267 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/voxtechnica/tuid-go"
+	v "github.com/voxtechnica/versionary"
+)
+
+//==============================================================================
+// Membership Table
+//==============================================================================
+
+// rowMemberships is a TableRow definition for Membership versions.
+var rowMemberships = v.TableRow[Membership]{
+	RowName:      "memberships_version",
+	PartKeyName:  "id",
+	PartKeyValue: func(m Membership) string { return m.ID },
+	PartKeyLabel: func(m Membership) string { return m.Email + " " + m.OrgName },
+	SortKeyName:  "version_id",
+	SortKeyValue: func(m Membership) string { return m.VersionID },
+	JsonValue:    func(m Membership) []byte { return m.CompressedJSON() },
+}
+
+// rowMembershipsUser is a TableRow definition for Memberships by User ID. A User has one Membership per Organization.
+var rowMembershipsUser = v.TableRow[Membership]{
+	RowName:      "memberships_user",
+	PartKeyName:  "user_id",
+	PartKeyValue: func(m Membership) string { return m.UserID },
+	PartKeyLabel: func(m Membership) string { return m.Email },
+	SortKeyName:  "org_id",
+	SortKeyValue: func(m Membership) string { return m.OrgID },
+	JsonValue:    func(m Membership) []byte { return m.CompressedJSON() },
+}
+
+// rowMembershipsOrg is a TableRow definition for Memberships by Organization ID.
+var rowMembershipsOrg = v.TableRow[Membership]{
+	RowName:      "memberships_org",
+	PartKeyName:  "org_id",
+	PartKeyValue: func(m Membership) string { return m.OrgID },
+	PartKeyLabel: func(m Membership) string { return m.OrgName },
+	SortKeyName:  "user_id",
+	SortKeyValue: func(m Membership) string { return m.UserID },
+	JsonValue:    func(m Membership) []byte { return m.CompressedJSON() },
+}
+
+// NewMembershipTable instantiates a new DynamoDB table for Organization Memberships.
+func NewMembershipTable(dbClient *dynamodb.Client, env string) v.Table[Membership] {
+	if env == "" {
+		env = "dev"
+	}
+	return v.Table[Membership]{
+		Client:     dbClient,
+		EntityType: "Membership",
+		TableName:  "memberships" + "_" + env,
+		TTL:        false,
+		EntityRow:  rowMemberships,
+		IndexRows: map[string]v.TableRow[Membership]{
+			rowMembershipsUser.RowName: rowMembershipsUser,
+			rowMembershipsOrg.RowName:  rowMembershipsOrg,
+		},
+	}
+}
+
+// NewMembershipMemTable creates an in-memory Membership table for testing purposes.
+func NewMembershipMemTable(table v.Table[Membership]) v.MemTable[Membership] {
+	return v.NewMemTable(table)
+}
+
+//==============================================================================
+// Membership Service
+//==============================================================================
+
+// MembershipService is used to manage User Memberships in Organizations in a DynamoDB table.
+type MembershipService struct {
+	EntityType string
+	Table      v.TableReadWriter[Membership]
+}
+
+// NewMembershipService creates a new Membership service backed by a Versionary Table for the specified environment.
+func NewMembershipService(dbClient *dynamodb.Client, env string) MembershipService {
+	table := NewMembershipTable(dbClient, env)
+	return MembershipService{
+		EntityType: table.EntityType,
+		Table:      table,
+	}
+}
+
+// NewMockMembershipService creates a new Membership service backed by an in-memory table for testing purposes.
+func NewMockMembershipService(env string) MembershipService {
+	table := NewMembershipMemTable(NewMembershipTable(nil, env))
+	return MembershipService{
+		EntityType: table.EntityType,
+		Table:      table,
+	}
+}
+
+//------------------------------------------------------------------------------
+// Membership Versions
+//------------------------------------------------------------------------------
+
+// Create a Membership in the Membership table. ErrDuplicateMembership is returned if the User
+// is already a member of the Organization.
+func (s MembershipService) Create(ctx context.Context, m Membership) (Membership, []string, error) {
+	t := tuid.NewID()
+	at, _ := t.Time()
+	m.ID = t.String()
+	m.CreatedAt = at
+	m.VersionID = t.String()
+	m.UpdatedAt = at
+	problems := m.Validate()
+	if len(problems) > 0 {
+		return m, problems, fmt.Errorf("error creating %s %s: invalid field(s): %s", s.EntityType, m.ID, strings.Join(problems, ", "))
+	}
+	if s.Exists(ctx, m.UserID, m.OrgID) {
+		return m, problems, fmt.Errorf("error creating %s for User %s: %w: %s", s.EntityType, m.UserID, ErrDuplicateMembership, m.OrgID)
+	}
+	if err := s.Table.WriteEntity(ctx, m); err != nil {
+		return m, problems, fmt.Errorf("error creating %s %s for User %s: %w", s.EntityType, m.ID, m.UserID, err)
+	}
+	return m, problems, nil
+}
+
+// Update a Membership in the Membership table. If a previous version does not exist, the Membership is created.
+func (s MembershipService) Update(ctx context.Context, m Membership) (Membership, []string, error) {
+	t := tuid.NewID()
+	at, _ := t.Time()
+	m.VersionID = t.String()
+	m.UpdatedAt = at
+	problems := m.Validate()
+	if len(problems) > 0 {
+		return m, problems, fmt.Errorf("error updating %s %s: invalid field(s): %s", s.EntityType, m.ID, strings.Join(problems, ", "))
+	}
+	return m, problems, s.Table.UpdateEntity(ctx, m)
+}
+
+// Write a Membership to the Membership table. This method assumes that the Membership has all the required fields.
+// It would most likely be used for "refreshing" the index rows in the Membership table.
+func (s MembershipService) Write(ctx context.Context, m Membership) (Membership, error) {
+	return m, s.Table.WriteEntity(ctx, m)
+}
+
+// Delete a Membership from the Membership table. The deleted Membership is returned.
+func (s MembershipService) Delete(ctx context.Context, id string) (Membership, error) {
+	return s.Table.DeleteEntityWithID(ctx, id)
+}
+
+// Exists checks if the User is a member of the Organization.
+func (s MembershipService) Exists(ctx context.Context, userID, orgID string) bool {
+	_, err := s.ReadMembership(ctx, userID, orgID)
+	return err == nil
+}
+
+// Read a specified Membership from the Membership table.
+func (s MembershipService) Read(ctx context.Context, id string) (Membership, error) {
+	return s.Table.ReadEntity(ctx, id)
+}
+
+// ReadMembership reads the User's Membership in the specified Organization.
+func (s MembershipService) ReadMembership(ctx context.Context, userID, orgID string) (Membership, error) {
+	return s.Table.ReadEntityFromRow(ctx, rowMembershipsUser, userID, orgID)
+}
+
+// ReadAllVersions returns all versions of the specified Membership in chronological order.
+func (s MembershipService) ReadAllVersions(ctx context.Context, id string) ([]Membership, error) {
+	return s.Table.ReadAllEntityVersions(ctx, id)
+}
+
+//------------------------------------------------------------------------------
+// Memberships by User
+//------------------------------------------------------------------------------
+
+// ReadAllMembershipsByUserID returns all of the User's Memberships, sorted by Organization ID.
+func (s MembershipService) ReadAllMembershipsByUserID(ctx context.Context, userID string) ([]Membership, error) {
+	return s.Table.ReadAllEntitiesFromRow(ctx, rowMembershipsUser, userID)
+}
+
+// SyncUser reconciles the User's primary Membership with the User's Organization (User.OrgID), creating
+// or updating it as needed, and removing a primary Membership in an Organization the User has left.
+func (s MembershipService) SyncUser(ctx context.Context, u User) error {
+	memberships, err := s.ReadAllMembershipsByUserID(ctx, u.ID)
+	if err != nil {
+		return fmt.Errorf("error reading %s memberships for User %s: %w", s.EntityType, u.ID, err)
+	}
+	p, hasOrg := u.PrimaryMembership()
+	found := false
+	for _, m := range memberships {
+		if hasOrg && m.OrgID == p.OrgID {
+			found = true
+			if m.Primary && m.Email == p.Email && m.OrgName == p.OrgName && slices.Equal(m.Roles, p.Roles) {
+				continue
+			}
+			m.Email, m.OrgName, m.Roles, m.Primary = p.Email, p.OrgName, p.Roles, true
+			if _, _, err = s.Update(ctx, m); err != nil {
+				return fmt.Errorf("error syncing %s for User %s: %w", s.EntityType, u.ID, err)
+			}
+		} else if m.Primary {
+			if _, err = s.Delete(ctx, m.ID); err != nil {
+				return fmt.Errorf("error syncing %s for User %s: %w", s.EntityType, u.ID, err)
+			}
+		}
+	}
+	if hasOrg && !found {
+		if _, _, err = s.Create(ctx, p); err != nil {
+			return fmt.Errorf("error syncing %s for User %s: %w", s.EntityType, u.ID, err)
+		}
+	}
+	return nil
+}
+
+// DeleteAllMembershipsByUserID deletes all of the User's Memberships (e.g. when the User is deleted).
+func (s MembershipService) DeleteAllMembershipsByUserID(ctx context.Context, userID string) error {
+	memberships, err := s.ReadAllMembershipsByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("error reading %s memberships for User %s: %w", s.EntityType, userID, err)
+	}
+	var errs []error
+	for _, m := range memberships {
+		if _, err = s.Delete(ctx, m.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+//------------------------------------------------------------------------------
+// Memberships by Organization
+//------------------------------------------------------------------------------
+
+// ReadOrgs returns a paginated list of Organization IDs and names for which there are Memberships.
+// Sorting is chronological (or reverse). The offset is the last ID returned in a previous request.
+func (s MembershipService) ReadOrgs(ctx context.Context, reverse bool, limit int, offset string) ([]v.TextValue, error) {
+	return s.Table.ReadPartKeyLabels(ctx, rowMembershipsOrg, reverse, limit, offset)
+}
+
+// ReadAllOrgs returns a complete list of Organization IDs and names for which there are Memberships,
+// sorted by name (or by ID).
+func (s MembershipService) ReadAllOrgs(ctx context.Context, sortByValue bool) ([]v.TextValue, error) {
+	return s.Table.ReadAllPartKeyLabels(ctx, rowMembershipsOrg, sortByValue)
+}
+
+// ReadOrgIDs returns a paginated list of Organization IDs for which there are Memberships.
+// Sorting is chronological (or reverse). The offset is the last ID returned in a previous request.
+func (s MembershipService) ReadOrgIDs(ctx context.Context, reverse bool, limit int, offset string) ([]string, error) {
+	return s.Table.ReadPartKeyValues(ctx, rowMembershipsOrg, reverse, limit, offset)
+}
+
+// ReadAllOrgIDs returns a complete, chronological list of Organization IDs for which there are Memberships.
+func (s MembershipService) ReadAllOrgIDs(ctx context.Context) ([]string, error) {
+	return s.Table.ReadAllPartKeyValues(ctx, rowMembershipsOrg)
+}
+
+// ReadMembershipsByOrgID returns paginated Memberships in the specified Organization, sorted by User ID
+// (or reverse). The offset is the last User ID returned in a previous request.
+func (s MembershipService) ReadMembershipsByOrgID(ctx context.Context, orgID string, reverse bool, limit int, offset string) ([]Membership, error) {
+	return s.Table.ReadEntitiesFromRow(ctx, rowMembershipsOrg, orgID, reverse, limit, offset)
+}
+
+// ReadAllMembershipsByOrgID returns all Memberships in the specified Organization, sorted by User ID.
+func (s MembershipService) ReadAllMembershipsByOrgID(ctx context.Context, orgID string) ([]Membership, error) {
+	return s.Table.ReadAllEntitiesFromRow(ctx, rowMembershipsOrg, orgID)
+}
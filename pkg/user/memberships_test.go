@@ -0,0 +1,118 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/voxtechnica/tuid-go"
+)
+
+func TestMembershipValidate(t *testing.T) {
+	expect := assert.New(t)
+	m, ok := u40.PrimaryMembership()
+	if expect.True(ok) {
+		expect.Equal(orgID4, m.OrgID)
+		expect.Equal(u40.Roles, m.Roles)
+		expect.True(m.Primary)
+		expect.Len(m.Validate(), 4) // no ID, CreatedAt, VersionID, UpdatedAt
+	}
+	_, ok = u20.PrimaryMembership()
+	expect.False(ok)
+}
+
+func TestMemberships(t *testing.T) {
+	expect := assert.New(t)
+	// Primary memberships follow the User's Organization
+	m, err := service.Memberships.ReadMembership(ctx, id4, orgID4)
+	if expect.NoError(err) {
+		expect.True(m.Primary)
+		expect.Equal(u40.Roles, m.Roles)
+	}
+	expect.False(service.Memberships.Exists(ctx, id2, orgID4))
+
+	// Create a User in one Organization, and a Membership in another
+	u, _, err := service.Create(ctx, User{
+		GivenName: "Member",
+		Email:     "membership_test_user@test.com",
+		Status:    ENABLED,
+		Roles:     []string{"analyst"},
+		OrgID:     orgID3,
+		OrgName:   "Test Organization 3",
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = service.Delete(ctx, u.ID) }()
+	m, problems, err := service.Memberships.Create(ctx, Membership{
+		UserID:  u.ID,
+		Email:   u.Email,
+		OrgID:   orgID4,
+		OrgName: "Test Organization 4",
+		Roles:   []string{"manager"},
+	})
+	expect.Empty(problems)
+	if !expect.NoError(err) {
+		return
+	}
+	_, _, err = service.Memberships.Create(ctx, Membership{UserID: u.ID, OrgID: orgID4})
+	expect.ErrorIs(err, ErrDuplicateMembership)
+
+	// The User is listed in both Organizations
+	memberships, err := service.Memberships.ReadAllMembershipsByUserID(ctx, u.ID)
+	if expect.NoError(err) && expect.Len(memberships, 2) {
+		expect.ElementsMatch([]string{orgID3, orgID4}, []string{memberships[0].OrgID, memberships[1].OrgID})
+	}
+	users, err := service.ReadAllUsersByOrgID(ctx, orgID4)
+	if expect.NoError(err) {
+		ids := make([]string, 0, len(users))
+		for _, e := range users {
+			ids = append(ids, e.ID)
+		}
+		expect.ElementsMatch([]string{id4, id5, u.ID}, ids)
+	}
+	users, err = service.ReadUsersByOrgID(ctx, orgID3, false, 10, tuid.MinID)
+	if expect.NoError(err) {
+		expect.Len(users, 2)
+	}
+
+	// Moving the User to the other Organization makes that Membership primary, with the User's roles
+	u.OrgID = orgID4
+	u.OrgName = "Test Organization 4"
+	u, _, err = service.Update(ctx, u)
+	if !expect.NoError(err) {
+		return
+	}
+	expect.False(service.Memberships.Exists(ctx, u.ID, orgID3))
+	if p, err := service.Memberships.ReadMembership(ctx, u.ID, orgID4); expect.NoError(err) {
+		expect.Equal(m.ID, p.ID)
+		expect.True(p.Primary)
+		expect.Equal([]string{"analyst"}, p.Roles)
+	}
+
+	// Deleting the User deletes their Memberships
+	_, err = service.Delete(ctx, u.ID)
+	expect.NoError(err)
+	memberships, err = service.Memberships.ReadAllMembershipsByUserID(ctx, u.ID)
+	expect.NoError(err)
+	expect.Empty(memberships)
+}
+
+func TestSyncUser(t *testing.T) {
+	expect := assert.New(t)
+	// Users written without Memberships (e.g. before migration) are synced idempotently
+	u := u30
+	u.ID = tuid.NewID().String()
+	u.VersionID = u.ID
+	if err := service.Table.WriteEntity(ctx, u); !expect.NoError(err) {
+		return
+	}
+	defer func() { _, _ = service.Delete(ctx, u.ID) }()
+	expect.False(service.Memberships.Exists(ctx, u.ID, orgID3))
+	expect.NoError(service.Memberships.SyncUser(ctx, u))
+	expect.NoError(service.Memberships.SyncUser(ctx, u))
+	memberships, err := service.Memberships.ReadAllMembershipsByUserID(ctx, u.ID)
+	if expect.NoError(err) && expect.Len(memberships, 1) {
+		expect.Equal(orgID3, memberships[0].OrgID)
+		expect.True(memberships[0].Primary)
+	}
+}
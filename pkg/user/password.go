@@ -0,0 +1,154 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Password hashes are stored in the PHC string format, using the argon2id key derivation function:
+//
+//	$argon2id$v=19$m=19456,t=2,p=1$<base64 salt>$<base64 key>
+//
+// Legacy password hashes are a hex-encoded SHA256 digest of the User's ID and Password.
+// They are still accepted by ValidPassword, and are upgraded on the next successful login.
+
+// Password hashing schemes, as reported by PasswordHashScheme.
+const (
+	HashSchemeArgon2id = "argon2id"
+	HashSchemeLegacy   = "sha256"
+	HashSchemeNone     = "none"
+	HashSchemeUnknown  = "unknown"
+)
+
+// argon2id parameters (OWASP recommended minimums: 19 MiB memory, 2 iterations, 1 thread).
+const (
+	argonMemory  uint32 = 19 * 1024
+	argonTime    uint32 = 2
+	argonThreads uint8  = 1
+	argonKeyLen  uint32 = 32
+	argonSaltLen        = 16
+)
+
+// argonParams are the parameters encoded in an argon2id PHC string.
+type argonParams struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+// HashPassword produces an argon2id hash of the supplied clear-text password, encoded as a PHC string.
+func HashPassword(password string) (string, error) {
+	if password == "" {
+		return "", errors.New("error hashing password: password is missing")
+	}
+	salt := make([]byte, argonSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("error hashing password: generate salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, argonMemory, argonTime, argonThreads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// PasswordHashScheme identifies the scheme used to produce the supplied password hash.
+func PasswordHashScheme(hash string) string {
+	if hash == "" {
+		return HashSchemeNone
+	}
+	if strings.HasPrefix(hash, "$argon2id$") {
+		return HashSchemeArgon2id
+	}
+	if isLegacyHash(hash) {
+		return HashSchemeLegacy
+	}
+	return HashSchemeUnknown
+}
+
+// verifyPassword checks a clear-text password against a password hash in either the PHC or the legacy format.
+// The User ID is required only for legacy hashes, where it was used as the salt.
+func verifyPassword(id, password, hash string) bool {
+	if password == "" || hash == "" {
+		return false
+	}
+	switch PasswordHashScheme(hash) {
+	case HashSchemeArgon2id:
+		p, err := parseArgonHash(hash)
+		if err != nil {
+			return false
+		}
+		key := argon2.IDKey([]byte(password), p.salt, p.time, p.memory, p.threads, uint32(len(p.key)))
+		return subtle.ConstantTimeCompare(key, p.key) == 1
+	case HashSchemeLegacy:
+		return id != "" && subtle.ConstantTimeCompare([]byte(legacyHashPassword(id, password)), []byte(hash)) == 1
+	default:
+		return false
+	}
+}
+
+// needsRehash returns true if the password hash is not an argon2id hash with the current parameters.
+func needsRehash(hash string) bool {
+	if hash == "" {
+		return false
+	}
+	p, err := parseArgonHash(hash)
+	if err != nil {
+		return true
+	}
+	return p.memory != argonMemory || p.time != argonTime || p.threads != argonThreads ||
+		len(p.key) != int(argonKeyLen)
+}
+
+// parseArgonHash parses an argon2id PHC string.
+func parseArgonHash(hash string) (argonParams, error) {
+	var p argonParams
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return p, errors.New("invalid argon2id hash: unexpected format")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return p, fmt.Errorf("invalid argon2id hash: version: %w", err)
+	}
+	if version != argon2.Version {
+		return p, fmt.Errorf("invalid argon2id hash: unsupported version %d", version)
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return p, fmt.Errorf("invalid argon2id hash: parameters: %w", err)
+	}
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return p, fmt.Errorf("invalid argon2id hash: salt: %w", err)
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return p, fmt.Errorf("invalid argon2id hash: key: %w", err)
+	}
+	if len(p.key) == 0 || p.time == 0 || p.threads == 0 {
+		return p, errors.New("invalid argon2id hash: missing key or parameters")
+	}
+	return p, nil
+}
+
+// legacyHashPassword produces the legacy salted SHA256 hash of a User's ID and Password.
+func legacyHashPassword(id, password string) string {
+	hash := sha256.Sum256([]byte(id + password))
+	return hex.EncodeToString(hash[:])
+}
+
+// isLegacyHash returns true if the supplied hash looks like a hex-encoded SHA256 digest.
+func isLegacyHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
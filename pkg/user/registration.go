@@ -0,0 +1,112 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MinPasswordLength is the minimum length of a password chosen during self-registration.
+const MinPasswordLength = 8
+
+// ErrDuplicateEmail is returned when an email address is already in use by another User.
+var ErrDuplicateEmail = errors.New("email address is already in use")
+
+// ErrDomainBlocked is returned when a self-registration uses an email domain that is blocked.
+var ErrDomainBlocked = errors.New("email domain is blocked")
+
+// ErrDomainNotAllowed is returned when a self-registration uses an email domain that is not on the allow list.
+var ErrDomainNotAllowed = errors.New("email domain is not allowed")
+
+// DomainPolicy restricts the email domains that may be used for self-registration. A rule matches a domain
+// and all of its subdomains. Blocked domains are always refused. If any domains are allowed, then only
+// those domains are permitted.
+type DomainPolicy struct {
+	Allowed []string `json:"allowed,omitempty"`
+	Blocked []string `json:"blocked,omitempty"`
+}
+
+// ParseDomains parses a comma- or space-delimited list of email domains (e.g. from an environment variable),
+// standardizing them as lowercase, without a leading "@" or ".".
+func ParseDomains(list string) []string {
+	var domains []string
+	for _, d := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' }) {
+		d = strings.TrimLeft(strings.ToLower(strings.TrimSpace(d)), "@.")
+		if d != "" {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+// Check returns ErrDomainBlocked or ErrDomainNotAllowed if the email address may not be used for self-registration.
+func (p DomainPolicy) Check(email string) error {
+	_, domain, _ := strings.Cut(StandardizeEmail(email), "@")
+	for _, rule := range p.Blocked {
+		if matchesDomain(domain, rule) {
+			return fmt.Errorf("%w: %s", ErrDomainBlocked, domain)
+		}
+	}
+	if len(p.Allowed) == 0 {
+		return nil
+	}
+	for _, rule := range p.Allowed {
+		if matchesDomain(domain, rule) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrDomainNotAllowed, domain)
+}
+
+// matchesDomain returns true if the domain is the same as, or a subdomain of, the rule domain.
+func matchesDomain(domain, rule string) bool {
+	rule = strings.ToLower(rule)
+	return domain != "" && (domain == rule || strings.HasSuffix(domain, "."+rule))
+}
+
+// Registration is a self-service request to create a new User account.
+type Registration struct {
+	GivenName  string `json:"givenName"`
+	FamilyName string `json:"familyName"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+}
+
+// Validate checks whether the Registration has all required fields, returning a list of problems.
+// Other User fields are validated when the User is created.
+func (r Registration) Validate() []string {
+	var problems []string
+	if strings.TrimSpace(r.Email) == "" {
+		problems = append(problems, "Email is missing")
+	}
+	if len(r.Password) < MinPasswordLength {
+		problems = append(problems, fmt.Sprintf("Password must be at least %d characters", MinPasswordLength))
+	}
+	return problems
+}
+
+// User returns a new PENDING User with no roles or organization, as requested by the Registration.
+func (r Registration) User() User {
+	return User{
+		GivenName:  strings.TrimSpace(r.GivenName),
+		FamilyName: strings.TrimSpace(r.FamilyName),
+		Email:      r.Email,
+		Password:   r.Password,
+		Status:     PENDING,
+	}
+}
+
+// Register creates a new PENDING User from a self-service Registration, if its email domain is permitted by the
+// DomainPolicy. Validation problems are returned, along with an error. ErrDomainBlocked, ErrDomainNotAllowed, or
+// ErrDuplicateEmail is returned if the email address may not be used.
+func (s Service) Register(ctx context.Context, r Registration, policy DomainPolicy) (User, []string, error) {
+	u := r.User()
+	if problems := r.Validate(); len(problems) > 0 {
+		return u, problems, fmt.Errorf("error registering %s: invalid field(s): %s", s.EntityType, strings.Join(problems, ", "))
+	}
+	if err := policy.Check(r.Email); err != nil {
+		return u, nil, fmt.Errorf("error registering %s %s: %w", s.EntityType, StandardizeEmail(r.Email), err)
+	}
+	return s.Create(ctx, u)
+}
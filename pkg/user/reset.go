@@ -0,0 +1,65 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Password reset tokens are 256-bit random values, encoded as unpadded base64url strings. Only a hex-encoded
+// SHA256 hash of the token is stored in User.PasswordReset, along with its expiration time. A token may be
+// used only once, and is invalidated whenever the User's password changes.
+
+// PasswordResetLifetime is how long a password reset token remains valid.
+const PasswordResetLifetime = time.Hour
+
+// passwordResetBytes is the number of random bytes in a password reset token.
+const passwordResetBytes = 32
+
+// ErrInvalidPasswordReset is returned when a password reset token does not match the User's pending reset.
+var ErrInvalidPasswordReset = errors.New("invalid password reset token")
+
+// ErrExpiredPasswordReset is returned when a password reset token has expired.
+var ErrExpiredPasswordReset = errors.New("expired password reset token")
+
+// NewPasswordResetToken generates a new random password reset token, returning the token and its hash.
+func NewPasswordResetToken() (string, string, error) {
+	b := make([]byte, passwordResetBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("error generating password reset token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashPasswordReset(token), nil
+}
+
+// IsPasswordResetToken returns true if the supplied value is a well-formed password reset token.
+func IsPasswordResetToken(token string) bool {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	return err == nil && len(b) == passwordResetBytes
+}
+
+// hashPasswordReset returns the hex-encoded SHA256 hash of a password reset token.
+func hashPasswordReset(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// ValidatePasswordReset checks a password reset token against the User's pending reset at the specified time.
+// ErrInvalidPasswordReset or ErrExpiredPasswordReset is returned if the token is not valid.
+func (u User) ValidatePasswordReset(token string, at time.Time) error {
+	if u.PasswordReset == "" || !IsPasswordResetToken(token) {
+		return ErrInvalidPasswordReset
+	}
+	if subtle.ConstantTimeCompare([]byte(hashPasswordReset(token)), []byte(u.PasswordReset)) != 1 {
+		return ErrInvalidPasswordReset
+	}
+	if !u.PasswordResetExpires.After(at) {
+		return ErrExpiredPasswordReset
+	}
+	return nil
+}
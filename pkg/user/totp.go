@@ -0,0 +1,154 @@
+package user
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Time-based one-time passwords (TOTP) are generated as described in RFC 6238, using the default
+// parameters supported by common authenticator apps: HMAC-SHA1, 6 digits, and a 30-second time step.
+// For more information, see https://datatracker.ietf.org/doc/html/rfc6238
+//
+// Recovery codes are single-use alternatives to a TOTP code, for use when the authenticator is lost.
+// They are high-entropy random values, so they are stored as a hex-encoded SHA256 digest.
+
+// TOTP parameters.
+const (
+	totpDigits     = 6
+	totpPeriod     = 30 // seconds
+	totpSkew       = 1  // time steps accepted before and after the current one
+	totpSecretLen  = 20 // bytes (160 bits, as recommended by RFC 4226)
+	recoveryCount  = 10
+	recoveryLength = 10 // bytes, encoded as 16 base32 characters
+)
+
+// recoveryClaimLifetime is how long a used recovery code remains claimed (see Service.VerifySecondFactor).
+// The code is removed from the User when it's used, so the claim only needs to outlast concurrent requests.
+const recoveryClaimLifetime = 24 * time.Hour
+
+// ErrSecondFactorRequired is returned when a User has enrolled in TOTP, but no code was supplied.
+var ErrSecondFactorRequired = errors.New("second factor code required")
+
+// ErrInvalidSecondFactor is returned when a supplied TOTP or recovery code is not valid.
+var ErrInvalidSecondFactor = errors.New("invalid second factor code")
+
+// b32 is the unpadded base32 encoding used for TOTP secrets and recovery codes.
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a new random TOTP secret, base32-encoded for use in an authenticator app.
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretLen)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("error generating TOTP secret: %w", err)
+	}
+	return b32.EncodeToString(secret), nil
+}
+
+// TOTPCode returns the TOTP code for the supplied base32-encoded secret at the specified time.
+func TOTPCode(secret string, at time.Time) (string, error) {
+	key, err := b32.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil {
+		return "", fmt.Errorf("error generating TOTP code: invalid secret: %w", err)
+	}
+	return hotp(key, uint64(at.Unix()/totpPeriod)), nil
+}
+
+// TOTPURI returns an otpauth:// key URI for the supplied secret, suitable for rendering as a QR code.
+// For more information, see https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+func TOTPURI(issuer, account, secret string) string {
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(totpDigits))
+	q.Set("period", fmt.Sprint(totpPeriod))
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
+
+// validTOTPCode checks a TOTP code against the supplied secret, allowing for a small amount of clock skew.
+func validTOTPCode(secret, code string, at time.Time) bool {
+	_, ok := totpCodeStep(secret, code, at)
+	return ok
+}
+
+// totpCodeStep returns the time step of a TOTP code that is valid for the supplied secret, allowing for a small
+// amount of clock skew. The step is used to reject a code that has already been accepted (RFC 6238, section 5.2).
+func totpCodeStep(secret, code string, at time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if secret == "" || len(code) != totpDigits {
+		return 0, false
+	}
+	key, err := b32.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+	counter := at.Unix() / totpPeriod
+	for i := int64(-totpSkew); i <= totpSkew; i++ {
+		expected := hotp(key, uint64(counter+i))
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return counter + i, true
+		}
+	}
+	return 0, false
+}
+
+// hotp produces an HMAC-based one-time password, as described in RFC 4226.
+func hotp(key []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// GenerateRecoveryCodes returns a set of new clear-text recovery codes, and their hashes for storage.
+func GenerateRecoveryCodes() (codes []string, hashes []string, err error) {
+	for i := 0; i < recoveryCount; i++ {
+		b := make([]byte, recoveryLength)
+		if _, err = rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("error generating recovery codes: %w", err)
+		}
+		s := strings.ToLower(b32.EncodeToString(b))
+		code := s[:8] + "-" + s[8:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode returns a hex-encoded SHA256 digest of a normalized recovery code.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// recoveryCodeIndex returns the index of the hash matching the supplied recovery code, or -1 if none matches.
+func recoveryCodeIndex(hashes []string, code string) int {
+	if strings.TrimSpace(code) == "" {
+		return -1
+	}
+	h := []byte(hashRecoveryCode(code))
+	index := -1
+	for i, stored := range hashes {
+		if subtle.ConstantTimeCompare(h, []byte(stored)) == 1 {
+			index = i
+		}
+	}
+	return index
+}
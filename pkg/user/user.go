@@ -1,8 +1,6 @@
 package user
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"net/mail"
 	"strings"
 	"time"
@@ -15,22 +13,27 @@ import (
 // User represents a person or system that accesses this information system.
 // It plays a key role in authenticating identity and authorizing actions (roles).
 type User struct {
-	ID            string    `json:"id"`
-	CreatedAt     time.Time `json:"createdAt"`
-	VersionID     string    `json:"versionID"`
-	UpdatedAt     time.Time `json:"updatedAt"`
-	GivenName     string    `json:"givenName"`
-	FamilyName    string    `json:"familyName"`
-	Email         string    `json:"email"`
-	Password      string    `json:"password,omitempty"`
-	PasswordHash  string    `json:"passwordHash,omitempty"`
-	PasswordReset string    `json:"passwordReset,omitempty"`
-	Roles         []string  `json:"roles,omitempty"`
-	OrgID         string    `json:"orgID,omitempty"`
-	OrgName       string    `json:"orgName,omitempty"`
-	AvatarURL     string    `json:"avatarURL,omitempty"`
-	WebsiteURL    string    `json:"websiteURL,omitempty"`
-	Status        Status    `json:"status"`
+	ID                   string    `json:"id"`
+	CreatedAt            time.Time `json:"createdAt"`
+	VersionID            string    `json:"versionID"`
+	UpdatedAt            time.Time `json:"updatedAt"`
+	GivenName            string    `json:"givenName"`
+	FamilyName           string    `json:"familyName"`
+	Email                string    `json:"email"`
+	VerifiedEmail        string    `json:"verifiedEmail,omitempty"`
+	Password             string    `json:"password,omitempty"`
+	PasswordHash         string    `json:"passwordHash,omitempty"`
+	PasswordReset        string    `json:"passwordReset,omitempty"`
+	PasswordResetExpires time.Time `json:"passwordResetExpires,omitempty"`
+	TOTPSecret           string    `json:"totpSecret,omitempty"`
+	TOTPEnabled          bool      `json:"totpEnabled,omitempty"`
+	RecoveryCodes        []string  `json:"recoveryCodes,omitempty"`
+	Roles                []string  `json:"roles,omitempty"`
+	OrgID                string    `json:"orgID,omitempty"`
+	OrgName              string    `json:"orgName,omitempty"`
+	AvatarURL            string    `json:"avatarURL,omitempty"`
+	WebsiteURL           string    `json:"websiteURL,omitempty"`
+	Status               Status    `json:"status"`
 }
 
 // Type returns the entity type of the User.
@@ -64,11 +67,37 @@ func (u User) HasRole(role string) bool {
 	return false
 }
 
+// ServiceRole identifies a service account: a non-human User that authenticates with scoped API keys.
+const ServiceRole = "service"
+
+// IsServiceAccount returns true if the User is a service account. Unlike HasRole,
+// the "admin" role does not imply a service account; the "service" role must be explicit.
+func (u User) IsServiceAccount() bool {
+	return v.Contains(u.Roles, ServiceRole)
+}
+
+// OrgAdminRole identifies an organization administrator: a User who may manage the Users in their own
+// Organization (User.OrgID), but who has no authority over other Organizations. Its permissions are
+// defined by the built-in role.DefaultRoles.
+const OrgAdminRole = "org_admin"
+
 // Scrub removes sensitive information from the User.
 func (u User) Scrub() User {
 	u.Password = ""
 	u.PasswordHash = ""
 	u.PasswordReset = ""
+	u.PasswordResetExpires = time.Time{}
+	u.TOTPSecret = ""
+	u.RecoveryCodes = nil
+	return u
+}
+
+// ScrubTOTP removes the plaintext password, the TOTP secret, and the recovery code hashes from the User,
+// which are never returned in an API response, even to administrators.
+func (u User) ScrubTOTP() User {
+	u.Password = ""
+	u.TOTPSecret = ""
+	u.RecoveryCodes = nil
 	return u
 }
 
@@ -77,6 +106,10 @@ func (u User) Scrub() User {
 func (u User) RestoreScrubbed(user User) User {
 	u.PasswordHash = user.PasswordHash
 	u.PasswordReset = user.PasswordReset
+	u.PasswordResetExpires = user.PasswordResetExpires
+	u.TOTPSecret = user.TOTPSecret
+	u.TOTPEnabled = user.TOTPEnabled
+	u.RecoveryCodes = user.RecoveryCodes
 	return u
 }
 
@@ -137,18 +170,33 @@ func (u User) ValidEmail() bool {
 }
 
 // ValidPassword checks the supplied clear-text password against stored password hash.
+// Both argon2id (PHC string) and legacy (salted SHA256) password hashes are supported.
 func (u User) ValidPassword(password string) bool {
-	return u.ID != "" && password != "" && u.PasswordHash != "" &&
-		HashPassword(u.ID, password) == u.PasswordHash
+	return u.ID != "" && verifyPassword(u.ID, password, u.PasswordHash)
 }
 
-// HashPassword produces a salted SHA256 hash of a User's ID and Password.
-func HashPassword(id, password string) string {
-	if id == "" && password == "" {
-		return ""
-	}
-	hash := sha256.Sum256([]byte(id + password))
-	return hex.EncodeToString(hash[:])
+// PasswordNeedsRehash returns true if the User's password hash is a legacy hash,
+// or an argon2id hash produced with outdated parameters.
+func (u User) PasswordNeedsRehash() bool {
+	return needsRehash(u.PasswordHash)
+}
+
+// HasTOTP returns true if the User has enrolled and verified a TOTP authenticator,
+// and must therefore supply a second-factor code when authenticating with a password.
+func (u User) HasTOTP() bool {
+	return u.TOTPEnabled && u.TOTPSecret != ""
+}
+
+// ValidTOTPCode checks the supplied TOTP code against the User's TOTP secret at the specified time.
+// The secret need not be verified yet; this method is also used to verify a new enrollment.
+// Codes that have already been accepted are rejected by VerifySecondFactor, not by this method.
+func (u User) ValidTOTPCode(code string, at time.Time) bool {
+	return validTOTPCode(u.TOTPSecret, code, at)
+}
+
+// ValidRecoveryCode returns true if the supplied code matches one of the User's unused recovery codes.
+func (u User) ValidRecoveryCode(code string) bool {
+	return recoveryCodeIndex(u.RecoveryCodes, code) >= 0
 }
 
 // StandardizeEmail returns the User's email address in a standard format.
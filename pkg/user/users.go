@@ -2,8 +2,12 @@ package user
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 	"versionary-api/pkg/email"
 	"versionary-api/pkg/util"
 
@@ -38,7 +42,8 @@ var rowUsersEmail = v.TableRow[User]{
 	JsonValue:    func(u User) []byte { return u.CompressedJSON() },
 }
 
-// rowUsersOrg is a TableRow definition for Users by Organization ID.
+// rowUsersOrg is a TableRow definition for Users by primary Organization ID. Users by Organization,
+// including their other Organization Memberships, are read from the Membership table.
 var rowUsersOrg = v.TableRow[User]{
 	RowName:      "users_org",
 	PartKeyName:  "org_id",
@@ -98,18 +103,32 @@ func NewMemTable(table v.Table[User]) v.MemTable[User] {
 // User Service
 //==============================================================================
 
-// Service is used to manage Users in a DynamoDB table.
+// rowUsersTOTPSteps names the claims (see util.Claim) of accepted TOTP time steps, by User ID and step.
+const rowUsersTOTPSteps = "users_totp_steps"
+
+// rowUsersRecoveryCodes names the claims (see util.Claim) of used recovery codes, by User ID and code hash.
+const rowUsersRecoveryCodes = "users_recovery_codes"
+
+// rowUsersPasswordResets names the claims (see util.Claim) of used password reset tokens, by User ID and token hash.
+const rowUsersPasswordResets = "users_password_resets"
+
+// Service is used to manage Users in a DynamoDB table. Single-use codes (e.g. TOTP codes) are claimed
+// (see util.Claim) in the Claims table, which has a time to live, so that the claims expire.
 type Service struct {
-	EntityType string
-	Table      v.TableReadWriter[User]
+	EntityType  string
+	Table       v.TableReadWriter[User]
+	Claims      v.TableReadWriter[Lockout]
+	Memberships MembershipService
 }
 
 // NewService creates a new User service backed by a Versionary Table for the specified environment.
 func NewService(dbClient *dynamodb.Client, env string) Service {
 	table := NewTable(dbClient, env)
 	return Service{
-		EntityType: table.EntityType,
-		Table:      table,
+		EntityType:  table.EntityType,
+		Table:       table,
+		Claims:      NewLockoutTable(dbClient, env),
+		Memberships: NewMembershipService(dbClient, env),
 	}
 }
 
@@ -117,8 +136,10 @@ func NewService(dbClient *dynamodb.Client, env string) Service {
 func NewMockService(env string) Service {
 	table := NewMemTable(NewTable(nil, env))
 	return Service{
-		EntityType: table.EntityType,
-		Table:      table,
+		EntityType:  table.EntityType,
+		Table:       table,
+		Claims:      NewLockoutMemTable(NewLockoutTable(nil, env)),
+		Memberships: NewMockMembershipService(env),
 	}
 }
 
@@ -163,11 +184,14 @@ func (s Service) Create(ctx context.Context, u User) (User, []string, error) {
 		return u, problems, fmt.Errorf("error checking email duplicates for %s: %w", u.Email, err)
 	}
 	if len(duplicates) > 0 {
-		return u, problems, fmt.Errorf("error creating %s %s: email address %s is already in use by %s", s.EntityType, u.ID, u.Email, strings.Join(duplicates, ", "))
+		return u, problems, fmt.Errorf("error creating %s %s: %w: %s (%s)", s.EntityType, u.ID, ErrDuplicateEmail, u.Email, strings.Join(duplicates, ", "))
 	}
 	// Hash password
 	if u.Password != "" {
-		u.PasswordHash = HashPassword(u.ID, u.Password)
+		u.PasswordHash, err = HashPassword(u.Password)
+		if err != nil {
+			return u, problems, fmt.Errorf("error creating %s %s: %w", s.EntityType, u.ID, err)
+		}
 		u.Password = ""
 	}
 	// Create User
@@ -175,7 +199,7 @@ func (s Service) Create(ctx context.Context, u User) (User, []string, error) {
 	if err != nil {
 		return u, problems, fmt.Errorf("error creating %s %s %s: %w", s.EntityType, u.ID, u.Email, err)
 	}
-	return u, problems, nil
+	return u, problems, s.syncMemberships(ctx, u)
 }
 
 // Update a User in the User table. If a previous version does not exist, the User is created.
@@ -200,26 +224,247 @@ func (s Service) Update(ctx context.Context, u User) (User, []string, error) {
 		return u, problems, fmt.Errorf("error checking email duplicates for %s: %w", u.Email, err)
 	}
 	if len(duplicates) > 0 {
-		return u, problems, fmt.Errorf("error creating %s %s: email address %s is already in use by %s", s.EntityType, u.ID, u.Email, strings.Join(duplicates, ", "))
+		return u, problems, fmt.Errorf("error updating %s %s: %w: %s (%s)", s.EntityType, u.ID, ErrDuplicateEmail, u.Email, strings.Join(duplicates, ", "))
 	}
-	// Hash password
+	// Hash password, invalidating any pending password reset
 	if u.Password != "" {
-		u.PasswordHash = HashPassword(u.ID, u.Password)
+		u.PasswordHash, err = HashPassword(u.Password)
+		if err != nil {
+			return u, problems, fmt.Errorf("error updating %s %s: %w", s.EntityType, u.ID, err)
+		}
 		u.Password = ""
+		u.PasswordReset = ""
+		u.PasswordResetExpires = time.Time{}
 	}
 	// Update User
-	return u, problems, s.Table.UpdateEntity(ctx, u)
+	if err = s.Table.UpdateEntity(ctx, u); err != nil {
+		return u, problems, err
+	}
+	return u, problems, s.syncMemberships(ctx, u)
+}
+
+// RehashPassword upgrades the User's password hash to the current hashing scheme, if needed.
+// The supplied clear-text password must already have been validated against the existing hash.
+func (s Service) RehashPassword(ctx context.Context, u User, password string) (User, error) {
+	if !u.PasswordNeedsRehash() {
+		return u, nil
+	}
+	if !u.ValidPassword(password) {
+		return u, fmt.Errorf("error rehashing password for %s %s: invalid password", s.EntityType, u.ID)
+	}
+	u.Password = password
+	u, _, err := s.Update(ctx, u)
+	if err != nil {
+		return u, fmt.Errorf("error rehashing password for %s %s: %w", s.EntityType, u.ID, err)
+	}
+	return u, nil
+}
+
+// EnrollTOTP generates a new TOTP secret for the User, replacing any existing second factor.
+// The enrollment is pending (not enforced) until it has been confirmed with ConfirmTOTP.
+func (s Service) EnrollTOTP(ctx context.Context, u User) (User, error) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return u, fmt.Errorf("error enrolling %s %s in TOTP: %w", s.EntityType, u.ID, err)
+	}
+	u.TOTPSecret = secret
+	u.TOTPEnabled = false
+	u.RecoveryCodes = nil
+	u, _, err = s.Update(ctx, u)
+	if err != nil {
+		return u, fmt.Errorf("error enrolling %s %s in TOTP: %w", s.EntityType, u.ID, err)
+	}
+	return u, nil
+}
+
+// ConfirmTOTP verifies a TOTP code against a pending enrollment, enabling the second factor.
+// A new set of recovery codes is returned in clear text; only their hashes are stored.
+func (s Service) ConfirmTOTP(ctx context.Context, u User, code string) (User, []string, error) {
+	if u.TOTPSecret == "" {
+		return u, nil, fmt.Errorf("error confirming TOTP for %s %s: not enrolled", s.EntityType, u.ID)
+	}
+	step, ok := totpCodeStep(u.TOTPSecret, code, time.Now())
+	if !ok {
+		return u, nil, ErrInvalidSecondFactor
+	}
+	if err := s.claimTOTPStep(ctx, u, step); err != nil {
+		return u, nil, err
+	}
+	codes, hashes, err := GenerateRecoveryCodes()
+	if err != nil {
+		return u, nil, fmt.Errorf("error confirming TOTP for %s %s: %w", s.EntityType, u.ID, err)
+	}
+	u.TOTPEnabled = true
+	u.RecoveryCodes = hashes
+	u, _, err = s.Update(ctx, u)
+	if err != nil {
+		return u, nil, fmt.Errorf("error confirming TOTP for %s %s: %w", s.EntityType, u.ID, err)
+	}
+	return u, codes, nil
+}
+
+// DisableTOTP removes the User's TOTP secret and recovery codes, whether pending or enabled.
+func (s Service) DisableTOTP(ctx context.Context, u User) (User, error) {
+	u.TOTPSecret = ""
+	u.TOTPEnabled = false
+	u.RecoveryCodes = nil
+	u, _, err := s.Update(ctx, u)
+	if err != nil {
+		return u, fmt.Errorf("error disabling TOTP for %s %s: %w", s.EntityType, u.ID, err)
+	}
+	return u, nil
+}
+
+// VerifySecondFactor checks the supplied TOTP code or recovery code for a User that has enabled TOTP.
+// Users without TOTP pass without a code. Each code is accepted only once: the time step of a TOTP code is
+// claimed (see claimTOTPStep), and a recovery code is claimed and consumed (removed) when it is used.
+// ErrSecondFactorRequired is returned when the code is missing, and ErrInvalidSecondFactor when it's wrong.
+func (s Service) VerifySecondFactor(ctx context.Context, u User, code string) (User, error) {
+	if !u.HasTOTP() {
+		return u, nil
+	}
+	if strings.TrimSpace(code) == "" {
+		return u, ErrSecondFactorRequired
+	}
+	if step, ok := totpCodeStep(u.TOTPSecret, code, time.Now()); ok {
+		return u, s.claimTOTPStep(ctx, u, step)
+	}
+	i := recoveryCodeIndex(u.RecoveryCodes, code)
+	if i < 0 {
+		return u, ErrInvalidSecondFactor
+	}
+	err := s.claimSecondFactor(ctx, u, rowUsersRecoveryCodes, u.RecoveryCodes[i], time.Now().Add(recoveryClaimLifetime))
+	if err != nil {
+		return u, err
+	}
+	u.RecoveryCodes = append(u.RecoveryCodes[:i:i], u.RecoveryCodes[i+1:]...)
+	u, _, err = s.Update(ctx, u)
+	if err != nil {
+		return u, fmt.Errorf("error consuming recovery code for %s %s: %w", s.EntityType, u.ID, err)
+	}
+	return u, nil
+}
+
+// claimTOTPStep claims the time step of an accepted TOTP code, so that the code is accepted only once
+// (RFC 6238, section 5.2). The earlier steps that are still in the skew window are claimed too, so that an
+// older code is not accepted after a newer one. Each claim expires when its step leaves the window.
+func (s Service) claimTOTPStep(ctx context.Context, u User, step int64) error {
+	expires := func(step int64) time.Time { return time.Unix((step+totpSkew+1)*totpPeriod, 0) }
+	if err := s.claimSecondFactor(ctx, u, rowUsersTOTPSteps, strconv.FormatInt(step, 10), expires(step)); err != nil {
+		return err
+	}
+	for earlier := step - 2*totpSkew; earlier < step; earlier++ {
+		_ = s.claimSecondFactor(ctx, u, rowUsersTOTPSteps, strconv.FormatInt(earlier, 10), expires(earlier)) // best effort
+	}
+	return nil
+}
+
+// claimSecondFactor claims a second factor code (a TOTP time step or a recovery code hash) for the User with a
+// conditional write, so that when several requests present the same code at once, only one succeeds.
+// The others receive ErrInvalidSecondFactor. The claim expires at the specified time.
+func (s Service) claimSecondFactor(ctx context.Context, u User, rowName, key string, expiresAt time.Time) error {
+	err := util.WriteClaim(ctx, s.Claims, util.Claim{
+		RowName:   rowName,
+		Key:       u.ID + "|" + key,
+		Owner:     tuid.NewID().String(),
+		ExpiresAt: expiresAt,
+	})
+	if errors.Is(err, util.ErrClaimed) {
+		return ErrInvalidSecondFactor
+	}
+	if err != nil {
+		return fmt.Errorf("error claiming second factor code for %s %s: %w", s.EntityType, u.ID, err)
+	}
+	return nil
+}
+
+// VerifyEmail checks a signed email verification token, and marks the User's current email address
+// as verified. PENDING Users are ENABLED. If the email address was already verified, the User is returned unchanged.
+func (s Service) VerifyEmail(ctx context.Context, u User, key []byte, token string) (User, error) {
+	if err := u.ValidateEmailVerificationToken(key, token, time.Now()); err != nil {
+		return u, err
+	}
+	if u.EmailVerified() && u.Status != PENDING {
+		return u, nil
+	}
+	u.VerifiedEmail = u.Email
+	if u.Status == PENDING {
+		u.Status = ENABLED
+	}
+	u, _, err := s.Update(ctx, u)
+	if err != nil {
+		return u, fmt.Errorf("error verifying email address for %s %s: %w", s.EntityType, u.ID, err)
+	}
+	return u, nil
+}
+
+// CreatePasswordReset generates a new password reset token for the User, replacing any pending reset.
+// Only a hash of the token is stored. The updated User and the clear-text token are returned.
+func (s Service) CreatePasswordReset(ctx context.Context, u User) (User, string, error) {
+	token, hash, err := NewPasswordResetToken()
+	if err != nil {
+		return u, "", err
+	}
+	u.PasswordReset = hash
+	u.PasswordResetExpires = time.Now().Add(PasswordResetLifetime)
+	u, _, err = s.Update(ctx, u)
+	if err != nil {
+		return u, "", fmt.Errorf("error creating password reset for %s %s: %w", s.EntityType, u.ID, err)
+	}
+	return u, token, nil
+}
+
+// ResetPassword checks a password reset token, and sets the User's new password.
+// The token is claimed with a conditional write and consumed, so that it cannot be used again,
+// even by concurrent requests; the others receive ErrInvalidPasswordReset.
+func (s Service) ResetPassword(ctx context.Context, u User, token, password string) (User, error) {
+	if err := u.ValidatePasswordReset(token, time.Now()); err != nil {
+		return u, err
+	}
+	err := util.WriteClaim(ctx, s.Claims, util.Claim{
+		RowName:   rowUsersPasswordResets,
+		Key:       u.ID + "|" + u.PasswordReset,
+		Owner:     tuid.NewID().String(),
+		ExpiresAt: u.PasswordResetExpires,
+	})
+	if errors.Is(err, util.ErrClaimed) {
+		return u, ErrInvalidPasswordReset
+	}
+	if err != nil {
+		return u, fmt.Errorf("error resetting password for %s %s: %w", s.EntityType, u.ID, err)
+	}
+	u.Password = password
+	u, _, err = s.Update(ctx, u)
+	if err != nil {
+		return u, fmt.Errorf("error resetting password for %s %s: %w", s.EntityType, u.ID, err)
+	}
+	return u, nil
 }
 
 // Write a User to the User table. This method assumes that the User has all the required fields.
 // It would most likely be used for "refreshing" the index rows in the User table.
 func (s Service) Write(ctx context.Context, u User) (User, error) {
-	return u, s.Table.WriteEntity(ctx, u)
+	if err := s.Table.WriteEntity(ctx, u); err != nil {
+		return u, err
+	}
+	return u, s.syncMemberships(ctx, u)
 }
 
-// Delete a User from the User table. The deleted User is returned.
+// Delete a User, and their Organization Memberships, from the User table. The deleted User is returned.
 func (s Service) Delete(ctx context.Context, id string) (User, error) {
-	return s.Table.DeleteEntityWithID(ctx, id)
+	u, err := s.Table.DeleteEntityWithID(ctx, id)
+	if err != nil || s.Memberships.Table == nil {
+		return u, err
+	}
+	return u, s.Memberships.DeleteAllMembershipsByUserID(ctx, id)
+}
+
+// syncMemberships keeps the User's primary Organization Membership consistent with the User.
+func (s Service) syncMemberships(ctx context.Context, u User) error {
+	if s.Memberships.Table == nil {
+		return nil
+	}
+	return s.Memberships.SyncUser(ctx, u)
 }
 
 // DeleteVersion deletes a specified User version from the User table. The deleted User is returned.
@@ -382,51 +627,103 @@ func (s Service) ReadUserIDsByEmail(ctx context.Context, email string) ([]string
 // Users by Organization
 //------------------------------------------------------------------------------
 
-// ReadOrgs returns a paginated list of Organization IDs and names for which there are Users in the User table.
-// Sorting is alphabetical (or reverse). The offset is the last ID returned in a previous request.
+// ReadOrgs returns a paginated list of Organization IDs and names for which there are Users (members).
+// Sorting is by ID (or reverse). The offset is the last ID returned in a previous request.
 func (s Service) ReadOrgs(ctx context.Context, reverse bool, limit int, offset string) ([]v.TextValue, error) {
-	return s.Table.ReadPartKeyLabels(ctx, rowUsersOrg, reverse, limit, offset)
+	return s.Memberships.ReadOrgs(ctx, reverse, limit, offset)
 }
 
-// ReadAllOrgs returns a complete, alphabetical list of Organization IDs and names for which there are Users in the User table.
+// ReadAllOrgs returns a complete, alphabetical list of Organization IDs and names for which there are Users (members).
 // Caution: this may be a LOT of data!
 func (s Service) ReadAllOrgs(ctx context.Context, sortByValue bool) ([]v.TextValue, error) {
-	return s.Table.ReadAllPartKeyLabels(ctx, rowUsersOrg, sortByValue)
+	return s.Memberships.ReadAllOrgs(ctx, sortByValue)
 }
 
-// ReadOrgIDs returns a paginated list of Organization IDs for which there are Users in the User table.
+// ReadOrgIDs returns a paginated list of Organization IDs for which there are Users (members).
 // Sorting is chronological (or reverse). The offset is the last ID returned in a previous request.
 func (s Service) ReadOrgIDs(ctx context.Context, reverse bool, limit int, offset string) ([]string, error) {
-	return s.Table.ReadPartKeyValues(ctx, rowUsersOrg, reverse, limit, offset)
+	return s.Memberships.ReadOrgIDs(ctx, reverse, limit, offset)
 }
 
-// ReadAllOrgIDs returns a complete, chronological list of Organization IDs for which there are Users in the User table.
+// ReadAllOrgIDs returns a complete, chronological list of Organization IDs for which there are Users (members).
 func (s Service) ReadAllOrgIDs(ctx context.Context) ([]string, error) {
-	return s.Table.ReadAllPartKeyValues(ctx, rowUsersOrg)
+	return s.Memberships.ReadAllOrgIDs(ctx)
 }
 
-// ReadUsersByOrgID returns paginated Users by Organization ID. Sorting is chronological (or reverse).
-// The offset is the ID of the last User returned in a previous request.
+// ReadUsersByOrgID returns paginated Users who are members of the Organization, whether it's their primary
+// Organization or not. Sorting is chronological (or reverse). The offset is the ID of the last User returned
+// in a previous request.
 func (s Service) ReadUsersByOrgID(ctx context.Context, orgID string, reverse bool, limit int, offset string) ([]User, error) {
-	return s.Table.ReadEntitiesFromRow(ctx, rowUsersOrg, orgID, reverse, limit, offset)
+	memberships, err := s.Memberships.ReadMembershipsByOrgID(ctx, orgID, reverse, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return s.Table.ReadEntities(ctx, memberUserIDs(memberships)), nil
 }
 
-// ReadUsersByOrgIDAsJSON returns paginated JSON Users by Organization ID. Sorting is chronological (or reverse).
-// The offset is the ID of the last User returned in a previous request.
+// ReadUsersByOrgIDAsJSON returns paginated JSON Users who are members of the Organization.
+// Sorting is chronological (or reverse). The offset is the ID of the last User returned in a previous request.
 func (s Service) ReadUsersByOrgIDAsJSON(ctx context.Context, orgID string, reverse bool, limit int, offset string) ([]byte, error) {
-	return s.Table.ReadEntitiesFromRowAsJSON(ctx, rowUsersOrg, orgID, reverse, limit, offset)
+	memberships, err := s.Memberships.ReadMembershipsByOrgID(ctx, orgID, reverse, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return s.Table.ReadEntitiesAsJSON(ctx, memberUserIDs(memberships)), nil
+}
+
+// ReadUsersByOrgIDs returns paginated Users who are members of any of the Organizations (e.g. an Organization and
+// its descendants). Sorting is chronological (or reverse). The offset is the ID of the last User returned in a
+// previous request.
+func (s Service) ReadUsersByOrgIDs(ctx context.Context, orgIDs []string, reverse bool, limit int, offset string) ([]User, error) {
+	var userIDs []string
+	for _, orgID := range orgIDs {
+		memberships, err := s.Memberships.ReadMembershipsByOrgID(ctx, orgID, reverse, limit, offset)
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, memberUserIDs(memberships)...)
+	}
+	slices.Sort(userIDs)
+	userIDs = slices.Compact(userIDs)
+	if reverse {
+		slices.Reverse(userIDs)
+	}
+	if limit > 0 && len(userIDs) > limit {
+		userIDs = userIDs[:limit]
+	}
+	return s.Table.ReadEntities(ctx, userIDs), nil
 }
 
-// ReadAllUsersByOrgID returns the complete list of Users for the specified Organization ID,
+// ReadAllUsersByOrgID returns the complete list of Users who are members of the Organization,
 // sorted chronologically by CreatedAt timestamp. Caution: this may be a LOT of data!
 func (s Service) ReadAllUsersByOrgID(ctx context.Context, orgID string) ([]User, error) {
-	return s.Table.ReadAllEntitiesFromRow(ctx, rowUsersOrg, orgID)
+	memberships, err := s.Memberships.ReadAllMembershipsByOrgID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Table.ReadEntities(ctx, memberUserIDs(memberships)), nil
 }
 
-// ReadAllUsersByOrgIDAsJSON returns the complete list of Users by Organization ID, serialized as JSON.
-// Caution: this may be a LOT of data!
+// ReadAllUsersByOrgIDAsJSON returns the complete list of Users who are members of the Organization,
+// serialized as JSON. Caution: this may be a LOT of data!
 func (s Service) ReadAllUsersByOrgIDAsJSON(ctx context.Context, orgID string) ([]byte, error) {
-	return s.Table.ReadAllEntitiesFromRowAsJSON(ctx, rowUsersOrg, orgID)
+	memberships, err := s.Memberships.ReadAllMembershipsByOrgID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Table.ReadEntitiesAsJSON(ctx, memberUserIDs(memberships)), nil
+}
+
+// ReadAllUserIDsByPrimaryOrgID returns the complete list of IDs of Users whose primary Organization is the
+// specified one, read from the users_org row rather than the Membership table. This includes Users whose
+// primary Membership has not yet been created (e.g. before the ops user sync command has been run).
+func (s Service) ReadAllUserIDsByPrimaryOrgID(ctx context.Context, orgID string) ([]string, error) {
+	return s.Table.ReadAllSortKeyValues(ctx, rowUsersOrg, orgID)
+}
+
+// memberUserIDs returns the User IDs from a list of Memberships.
+func memberUserIDs(memberships []Membership) []string {
+	return v.Map(memberships, func(m Membership) string { return m.UserID })
 }
 
 //------------------------------------------------------------------------------
@@ -3,12 +3,16 @@ package user
 import (
 	"context"
 	"log"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/voxtechnica/tuid-go"
 	v "github.com/voxtechnica/versionary"
+
+	"versionary-api/pkg/util"
 )
 
 var (
@@ -596,3 +600,349 @@ func TestReadAllUsersByStatusAsJSON(t *testing.T) {
 		expect.Contains(string(checkUsers), u50.Status)
 	}
 }
+
+func TestHashPassword(t *testing.T) {
+	expect := assert.New(t)
+	hash, err := HashPassword("password_test_1234")
+	if expect.NoError(err) {
+		expect.True(strings.HasPrefix(hash, "$argon2id$v=19$"), "PHC string format")
+		expect.Equal(HashSchemeArgon2id, PasswordHashScheme(hash))
+		expect.False(needsRehash(hash))
+		expect.True(verifyPassword("", "password_test_1234", hash))
+		expect.False(verifyPassword("", "wrong_password", hash))
+	}
+	// Hashes are salted
+	hash2, err := HashPassword("password_test_1234")
+	if expect.NoError(err) {
+		expect.NotEqual(hash, hash2)
+	}
+	// Missing password
+	_, err = HashPassword("")
+	expect.Error(err)
+}
+
+func TestValidPassword(t *testing.T) {
+	expect := assert.New(t)
+	// Legacy hash
+	legacy := User{ID: id1, PasswordHash: legacyHashPassword(id1, "legacy_password")}
+	expect.Equal(HashSchemeLegacy, PasswordHashScheme(legacy.PasswordHash))
+	expect.True(legacy.ValidPassword("legacy_password"))
+	expect.False(legacy.ValidPassword("wrong_password"))
+	expect.True(legacy.PasswordNeedsRehash())
+	// argon2id hash
+	hash, err := HashPassword("argon_password")
+	if expect.NoError(err) {
+		current := User{ID: id1, PasswordHash: hash}
+		expect.True(current.ValidPassword("argon_password"))
+		expect.False(current.ValidPassword("wrong_password"))
+		expect.False(current.PasswordNeedsRehash())
+	}
+	// Outdated argon2id parameters
+	outdated := "$argon2id$v=19$m=4096,t=1,p=1$c2FsdHNhbHRzYWx0$Zm9vYmFyZm9vYmFyZm9vYmFyZm9vYmFyZm9vYmFy"
+	expect.True(User{ID: id1, PasswordHash: outdated}.PasswordNeedsRehash())
+	// Missing or malformed hash
+	expect.False(User{ID: id1}.ValidPassword("legacy_password"))
+	expect.False(User{ID: id1}.PasswordNeedsRehash())
+	expect.Equal(HashSchemeNone, PasswordHashScheme(""))
+	expect.Equal(HashSchemeUnknown, PasswordHashScheme("not-a-hash"))
+	expect.False(User{ID: id1, PasswordHash: "$argon2id$v=19$bad"}.ValidPassword("legacy_password"))
+}
+
+func TestRehashPassword(t *testing.T) {
+	expect := assert.New(t)
+	// Create a user with a password
+	u, problems, err := service.Create(ctx, User{
+		GivenName: "rehash_test_user",
+		Email:     "rehash_user_email@test.com",
+		Password:  "rehash_password",
+		Status:    PENDING,
+	})
+	expect.Empty(problems)
+	if expect.NoError(err) {
+		expect.Empty(u.Password)
+		expect.Equal(HashSchemeArgon2id, PasswordHashScheme(u.PasswordHash))
+		expect.True(u.ValidPassword("rehash_password"))
+		// Current hashes are not rehashed
+		same, err := service.RehashPassword(ctx, u, "rehash_password")
+		if expect.NoError(err) {
+			expect.Equal(u, same)
+		}
+		// Simulate a legacy hash
+		u.PasswordHash = legacyHashPassword(u.ID, "rehash_password")
+		u, err = service.Write(ctx, u)
+		expect.NoError(err)
+		// An invalid password is rejected
+		_, err = service.RehashPassword(ctx, u, "wrong_password")
+		expect.Error(err)
+		// Upgrade the legacy hash
+		upgraded, err := service.RehashPassword(ctx, u, "rehash_password")
+		if expect.NoError(err) {
+			expect.NotEqual(u.VersionID, upgraded.VersionID)
+			expect.Equal(HashSchemeArgon2id, PasswordHashScheme(upgraded.PasswordHash))
+			expect.True(upgraded.ValidPassword("rehash_password"))
+			expect.Empty(upgraded.Password)
+		}
+		// Clean up
+		_, err = service.Delete(ctx, u.ID)
+		expect.NoError(err)
+	}
+}
+
+func TestTOTPCode(t *testing.T) {
+	expect := assert.New(t)
+	// RFC 6238 Appendix B test vectors (SHA1), truncated to 6 digits
+	secret := b32.EncodeToString([]byte("12345678901234567890"))
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for at, expected := range vectors {
+		code, err := TOTPCode(secret, time.Unix(at, 0))
+		if expect.NoError(err) {
+			expect.Equal(expected, code, "TOTP code at %d", at)
+		}
+		expect.True(validTOTPCode(secret, expected, time.Unix(at, 0)))
+		expect.True(validTOTPCode(secret, expected, time.Unix(at+totpPeriod, 0)), "clock skew")
+		expect.False(validTOTPCode(secret, expected, time.Unix(at+3*totpPeriod, 0)), "expired")
+	}
+	_, err := TOTPCode("not base32!", time.Now())
+	expect.Error(err)
+	uri := TOTPURI("Versionary", "totp@test.com", secret)
+	expect.True(strings.HasPrefix(uri, "otpauth://totp/Versionary:totp@test.com?"))
+	expect.Contains(uri, "secret="+secret)
+}
+
+func TestTOTPEnrollment(t *testing.T) {
+	expect := assert.New(t)
+	u, _, err := service.Create(ctx, User{
+		GivenName: "totp_test_user",
+		Email:     "totp_user_email@test.com",
+		Password:  "totp_password",
+		Status:    ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	// Users without TOTP do not need a code
+	_, err = service.VerifySecondFactor(ctx, u, "")
+	expect.NoError(err)
+	// A pending enrollment is not enforced
+	u, err = service.EnrollTOTP(ctx, u)
+	if !expect.NoError(err) {
+		return
+	}
+	expect.NotEmpty(u.TOTPSecret)
+	expect.False(u.HasTOTP())
+	_, err = service.VerifySecondFactor(ctx, u, "")
+	expect.NoError(err)
+	// Confirm the enrollment
+	_, _, err = service.ConfirmTOTP(ctx, u, "000000")
+	expect.ErrorIs(err, ErrInvalidSecondFactor)
+	code, _ := TOTPCode(u.TOTPSecret, time.Now())
+	u, codes, err := service.ConfirmTOTP(ctx, u, code)
+	if !expect.NoError(err) {
+		return
+	}
+	expect.True(u.HasTOTP())
+	expect.Len(codes, recoveryCount)
+	expect.Len(u.RecoveryCodes, recoveryCount)
+	expect.NotContains(u.RecoveryCodes, codes[0], "recovery codes are hashed")
+	expect.Empty(u.Scrub().TOTPSecret)
+	expect.Empty(u.Scrub().RecoveryCodes)
+	expect.True(u.Scrub().TOTPEnabled)
+	// A code is now required
+	_, err = service.VerifySecondFactor(ctx, u, "")
+	expect.ErrorIs(err, ErrSecondFactorRequired)
+	_, err = service.VerifySecondFactor(ctx, u, "bogus")
+	expect.ErrorIs(err, ErrInvalidSecondFactor)
+	// TOTP codes are single-use: the confirmation code can't be replayed, nor can an older code after a
+	// newer one. Accepted time steps are claimed in the claims table, without updating the User.
+	_, err = service.VerifySecondFactor(ctx, u, code)
+	expect.ErrorIs(err, ErrInvalidSecondFactor)
+	step := time.Now().Unix() / totpPeriod
+	next, _ := TOTPCode(u.TOTPSecret, time.Unix((step+1)*totpPeriod, 0))
+	verified, err := service.VerifySecondFactor(ctx, u, next)
+	if expect.NoError(err) {
+		expect.Equal(u.VersionID, verified.VersionID)
+		_, err = util.ReadClaim(ctx, service.Claims, rowUsersTOTPSteps, u.ID+"|"+strconv.FormatInt(step+1, 10))
+		expect.NoError(err)
+		_, err = util.ReadClaim(ctx, service.Table, rowUsersTOTPSteps, u.ID+"|"+strconv.FormatInt(step+1, 10))
+		expect.ErrorIs(err, v.ErrNotFound)
+	}
+	_, err = service.VerifySecondFactor(ctx, u, next)
+	expect.ErrorIs(err, ErrInvalidSecondFactor)
+	older, _ := TOTPCode(u.TOTPSecret, time.Unix((step-1)*totpPeriod, 0))
+	_, err = service.VerifySecondFactor(ctx, u, older)
+	expect.ErrorIs(err, ErrInvalidSecondFactor)
+	// Recovery codes are single-use, including for concurrent requests
+	expect.True(u.ValidRecoveryCode(strings.ToUpper(codes[0])))
+	prior := u
+	u, err = service.VerifySecondFactor(ctx, u, codes[0])
+	if expect.NoError(err) {
+		expect.Len(u.RecoveryCodes, recoveryCount-1)
+	}
+	_, err = service.VerifySecondFactor(ctx, u, codes[0])
+	expect.ErrorIs(err, ErrInvalidSecondFactor)
+	_, err = service.VerifySecondFactor(ctx, prior, codes[0])
+	expect.ErrorIs(err, ErrInvalidSecondFactor)
+	// Disable TOTP
+	u, err = service.DisableTOTP(ctx, u)
+	if expect.NoError(err) {
+		expect.False(u.HasTOTP())
+		expect.Empty(u.TOTPSecret)
+		expect.Empty(u.RecoveryCodes)
+	}
+	// Clean up
+	_, err = service.Delete(ctx, u.ID)
+	expect.NoError(err)
+}
+
+func TestEmailVerification(t *testing.T) {
+	expect := assert.New(t)
+	key := []byte("0123456789abcdef0123456789abcdef")
+	u, _, err := service.Create(ctx, User{
+		GivenName: "verify_test_user",
+		Email:     "verify_user_email@test.com",
+		Status:    PENDING,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	expect.False(u.EmailVerified())
+	now := time.Now()
+	token := u.EmailVerificationToken(key, now.Add(EmailVerificationLifetime))
+	// Tokens are checked against the key, the time, and the current email address
+	expect.NoError(u.ValidateEmailVerificationToken(key, token, now))
+	expect.ErrorIs(u.ValidateEmailVerificationToken([]byte("wrong key"), token, now), ErrInvalidVerification)
+	expect.ErrorIs(u.ValidateEmailVerificationToken(key, token, now.Add(EmailVerificationLifetime)), ErrExpiredVerification)
+	expect.ErrorIs(u.ValidateEmailVerificationToken(key, "bogus", now), ErrInvalidVerification)
+	changed := u
+	changed.Email = "verify_other_email@test.com"
+	expect.ErrorIs(changed.ValidateEmailVerificationToken(key, token, now), ErrInvalidVerification)
+	// Verification enables a pending User
+	_, err = service.VerifyEmail(ctx, u, key, "bogus")
+	expect.ErrorIs(err, ErrInvalidVerification)
+	u, err = service.VerifyEmail(ctx, u, key, token)
+	if expect.NoError(err) {
+		expect.True(u.EmailVerified())
+		expect.Equal(ENABLED, u.Status)
+	}
+	// Changing the email address requires verification again
+	u.Email = "Verify_User_Email@test.com"
+	expect.True(u.EmailVerified(), "standardized email")
+	u.Email = "verify_other_email@test.com"
+	expect.False(u.EmailVerified())
+	// Clean up
+	_, err = service.Delete(ctx, u.ID)
+	expect.NoError(err)
+}
+
+func TestPasswordReset(t *testing.T) {
+	expect := assert.New(t)
+	u, _, err := service.Create(ctx, User{
+		GivenName: "reset_test_user",
+		Email:     "reset_user_email@test.com",
+		Password:  "reset_password",
+		Status:    ENABLED,
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	// Only a hash of the token is stored
+	u, token, err := service.CreatePasswordReset(ctx, u)
+	if !expect.NoError(err) {
+		return
+	}
+	expect.True(IsPasswordResetToken(token))
+	expect.False(IsPasswordResetToken("bad_token"))
+	expect.NotEqual(token, u.PasswordReset)
+	expect.Empty(u.Scrub().PasswordReset)
+	expect.True(u.Scrub().PasswordResetExpires.IsZero())
+	// Tokens expire
+	expect.NoError(u.ValidatePasswordReset(token, time.Now()))
+	expect.ErrorIs(u.ValidatePasswordReset(token, time.Now().Add(PasswordResetLifetime)), ErrExpiredPasswordReset)
+	other, _, _ := NewPasswordResetToken()
+	expect.ErrorIs(u.ValidatePasswordReset(other, time.Now()), ErrInvalidPasswordReset)
+	// Tokens are single-use, including for concurrent requests
+	_, err = service.ResetPassword(ctx, u, other, "new_password")
+	expect.ErrorIs(err, ErrInvalidPasswordReset)
+	prior := u
+	u, err = service.ResetPassword(ctx, u, token, "new_password")
+	if expect.NoError(err) {
+		expect.True(u.ValidPassword("new_password"))
+		expect.Empty(u.PasswordReset)
+	}
+	_, err = service.ResetPassword(ctx, u, token, "another_password")
+	expect.ErrorIs(err, ErrInvalidPasswordReset)
+	_, err = service.ResetPassword(ctx, prior, token, "another_password")
+	expect.ErrorIs(err, ErrInvalidPasswordReset)
+	if current, err := service.Read(ctx, u.ID); expect.NoError(err) {
+		expect.True(current.ValidPassword("new_password"))
+	}
+	// Changing the password invalidates a pending reset
+	u, token, err = service.CreatePasswordReset(ctx, u)
+	if expect.NoError(err) {
+		u.Password = "changed_password"
+		u, _, err = service.Update(ctx, u)
+		expect.NoError(err)
+		expect.ErrorIs(u.ValidatePasswordReset(token, time.Now()), ErrInvalidPasswordReset)
+	}
+	// Clean up
+	_, err = service.Delete(ctx, u.ID)
+	expect.NoError(err)
+}
+
+func TestDomainPolicy(t *testing.T) {
+	expect := assert.New(t)
+	expect.Equal([]string{"example.com", "test.org", "sub.test.net"}, ParseDomains(" Example.com, @test.org .sub.test.net,"))
+	expect.Empty(ParseDomains(""))
+	// Any domain is allowed by default
+	expect.NoError(DomainPolicy{}.Check("someone@anywhere.com"))
+	// Blocked domains (and their subdomains) are refused
+	p := DomainPolicy{Blocked: []string{"spam.com"}}
+	expect.ErrorIs(p.Check("someone@SPAM.com"), ErrDomainBlocked)
+	expect.ErrorIs(p.Check("someone@mail.spam.com"), ErrDomainBlocked)
+	expect.NoError(p.Check("someone@notspam.com"))
+	// Only allowed domains are permitted, unless blocked
+	p = DomainPolicy{Allowed: []string{"example.com"}, Blocked: []string{"guest.example.com"}}
+	expect.NoError(p.Check("someone@example.com"))
+	expect.NoError(p.Check("someone@staff.example.com"))
+	expect.ErrorIs(p.Check("someone@guest.example.com"), ErrDomainBlocked)
+	expect.ErrorIs(p.Check("someone@badexample.com"), ErrDomainNotAllowed)
+	expect.ErrorIs(p.Check("not an email address"), ErrDomainNotAllowed)
+}
+
+func TestRegister(t *testing.T) {
+	expect := assert.New(t)
+	policy := DomainPolicy{Allowed: []string{"register.com"}}
+	r := Registration{GivenName: " Self ", FamilyName: "Service", Email: "self@register.com", Password: "short"}
+	// Invalid registrations
+	_, problems, err := service.Register(ctx, r, policy)
+	expect.Error(err)
+	expect.NotEmpty(problems)
+	r.Password = "long_enough"
+	r.Email = "self@elsewhere.com"
+	_, _, err = service.Register(ctx, r, policy)
+	expect.ErrorIs(err, ErrDomainNotAllowed)
+	// A valid registration creates a PENDING User with no roles
+	r.Email = "self@register.com"
+	u, problems, err := service.Register(ctx, r, policy)
+	if !expect.NoError(err) || !expect.Empty(problems) {
+		return
+	}
+	expect.Equal(PENDING, u.Status)
+	expect.Empty(u.Roles)
+	expect.Empty(u.OrgID)
+	expect.Equal("Self", u.GivenName)
+	expect.True(u.ValidPassword("long_enough"))
+	// Email addresses must be unique
+	_, problems, err = service.Register(ctx, r, policy)
+	expect.ErrorIs(err, ErrDuplicateEmail)
+	// Clean up
+	_, err = service.Delete(ctx, u.ID)
+	expect.NoError(err)
+}
@@ -0,0 +1,170 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/voxtechnica/versionary"
+)
+
+// ErrClaimed is returned when a unique key has already been claimed by another owner.
+var ErrClaimed = errors.New("already claimed")
+
+// Claim records the owner of a unique key (e.g. a rotated RefreshToken) in a versionary table. Claims are written
+// with a conditional write, so that when several requests claim the same key at once, only one succeeds.
+// Each claim is stored in a record of its own: rowName|key|<key>, with the owner as its text value.
+type Claim struct {
+	RowName   string    // the kind of claim (e.g. refresh_token_rotations)
+	Key       string    // the unique key (e.g. a RefreshToken ID)
+	Owner     string    // who holds the key (e.g. the ID of the claiming request)
+	ExpiresAt time.Time // when the claim expires (optional; requires a table with a time to live)
+}
+
+// partKey returns the partition key value of the claim record.
+func (c Claim) partKey() string {
+	return c.RowName + "|key|" + c.Key
+}
+
+// memWrites serializes conditional writes (e.g. claims) to in-memory tables, which are not otherwise safe for
+// concurrent use.
+var memWrites sync.Mutex
+
+// WriteClaim claims the key for its owner, unless another owner holds it already, in which case ErrClaimed
+// is returned. Claiming a key again for the same owner succeeds, updating the expiry.
+// Both DynamoDB tables (versionary.Table) and in-memory tables (versionary.MemTable) are supported.
+func WriteClaim[T any](ctx context.Context, table versionary.TableReadWriter[T], c Claim) error {
+	if c.RowName == "" || c.Key == "" || c.Owner == "" {
+		return fmt.Errorf("error claiming %s: row name, key, and owner are required", c.partKey())
+	}
+	switch t := table.(type) {
+	case versionary.Table[T]:
+		item := map[string]types.AttributeValue{
+			attrName(t.PartKeyAttr, "part_key"):     &types.AttributeValueMemberS{Value: c.partKey()},
+			attrName(t.SortKeyAttr, "sort_key"):     &types.AttributeValueMemberS{Value: c.Key},
+			attrName(t.TextValueAttr, "text_value"): &types.AttributeValueMemberS{Value: c.Owner},
+		}
+		if t.TTL && !c.ExpiresAt.IsZero() {
+			item[attrName(t.TimeToLiveAttr, "expires_at")] = &types.AttributeValueMemberN{
+				Value: strconv.FormatInt(c.ExpiresAt.Unix(), 10),
+			}
+		}
+		_, err := t.Client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:           aws.String(t.TableName),
+			Item:                item,
+			ConditionExpression: aws.String("attribute_not_exists(#p) OR #t = :o"),
+			ExpressionAttributeNames: map[string]string{
+				"#p": attrName(t.PartKeyAttr, "part_key"),
+				"#t": attrName(t.TextValueAttr, "text_value"),
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":o": &types.AttributeValueMemberS{Value: c.Owner},
+			},
+		})
+		var failed *types.ConditionalCheckFailedException
+		if errors.As(err, &failed) {
+			return fmt.Errorf("error claiming %s for %s: %w", c.partKey(), c.Owner, ErrClaimed)
+		}
+		if err != nil {
+			return fmt.Errorf("error claiming %s for %s: %w", c.partKey(), c.Owner, err)
+		}
+		return nil
+	case versionary.MemTable[T]:
+		memWrites.Lock()
+		defer memWrites.Unlock()
+		if r, ok := t.Records.GetRecord(c.partKey(), c.Key); ok && r.TextValue != c.Owner {
+			return fmt.Errorf("error claiming %s for %s: %w", c.partKey(), c.Owner, ErrClaimed)
+		}
+		r := versionary.Record{PartKeyValue: c.partKey(), SortKeyValue: c.Key, TextValue: c.Owner}
+		if !c.ExpiresAt.IsZero() {
+			r.TimeToLive = c.ExpiresAt.Unix()
+		}
+		t.Records.SetRecord(r)
+		return nil
+	default:
+		return fmt.Errorf("error claiming %s: unsupported table type %T", c.partKey(), table)
+	}
+}
+
+// ReadClaim returns the claim of the specified key. If the key is not claimed, versionary.ErrNotFound is returned.
+func ReadClaim[T any](ctx context.Context, table versionary.TableReadWriter[T], rowName, key string) (Claim, error) {
+	c := Claim{RowName: rowName, Key: key}
+	switch t := table.(type) {
+	case versionary.Table[T]:
+		out, err := t.Client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName: aws.String(t.TableName),
+			Key: map[string]types.AttributeValue{
+				attrName(t.PartKeyAttr, "part_key"): &types.AttributeValueMemberS{Value: c.partKey()},
+				attrName(t.SortKeyAttr, "sort_key"): &types.AttributeValueMemberS{Value: key},
+			},
+			ConsistentRead: aws.Bool(true),
+		})
+		if err != nil {
+			return c, fmt.Errorf("error reading claim %s: %w", c.partKey(), err)
+		}
+		owner, ok := out.Item[attrName(t.TextValueAttr, "text_value")].(*types.AttributeValueMemberS)
+		if !ok {
+			return c, fmt.Errorf("error reading claim %s: %w", c.partKey(), versionary.ErrNotFound)
+		}
+		c.Owner = owner.Value
+		return c, nil
+	case versionary.MemTable[T]:
+		memWrites.Lock()
+		defer memWrites.Unlock()
+		r, ok := t.Records.GetRecord(c.partKey(), key)
+		if !ok {
+			return c, fmt.Errorf("error reading claim %s: %w", c.partKey(), versionary.ErrNotFound)
+		}
+		c.Owner = r.TextValue
+		return c, nil
+	default:
+		return c, fmt.Errorf("error reading claim %s: unsupported table type %T", c.partKey(), table)
+	}
+}
+
+// DeleteClaim releases the claim of a key, if it's held by the owner.
+func DeleteClaim[T any](ctx context.Context, table versionary.TableReadWriter[T], c Claim) error {
+	switch t := table.(type) {
+	case versionary.Table[T]:
+		_, err := t.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(t.TableName),
+			Key: map[string]types.AttributeValue{
+				attrName(t.PartKeyAttr, "part_key"): &types.AttributeValueMemberS{Value: c.partKey()},
+				attrName(t.SortKeyAttr, "sort_key"): &types.AttributeValueMemberS{Value: c.Key},
+			},
+			ConditionExpression:      aws.String("#t = :o"),
+			ExpressionAttributeNames: map[string]string{"#t": attrName(t.TextValueAttr, "text_value")},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":o": &types.AttributeValueMemberS{Value: c.Owner},
+			},
+		})
+		var failed *types.ConditionalCheckFailedException
+		if err != nil && !errors.As(err, &failed) {
+			return fmt.Errorf("error releasing claim %s for %s: %w", c.partKey(), c.Owner, err)
+		}
+		return nil
+	case versionary.MemTable[T]:
+		memWrites.Lock()
+		defer memWrites.Unlock()
+		if r, ok := t.Records.GetRecord(c.partKey(), c.Key); ok && r.TextValue == c.Owner {
+			t.Records.DeleteRecordForKeys(c.partKey(), c.Key)
+		}
+		return nil
+	default:
+		return fmt.Errorf("error releasing claim %s: unsupported table type %T", c.partKey(), table)
+	}
+}
+
+// attrName returns the configured attribute name of a versionary table, or the versionary default.
+func attrName(configured, defaultName string) string {
+	if configured == "" {
+		return defaultName
+	}
+	return configured
+}
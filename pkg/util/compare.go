@@ -0,0 +1,118 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/voxtechnica/versionary"
+)
+
+// ErrConflict is returned when an entity was changed by another request after it was read.
+var ErrConflict = errors.New("changed by another request")
+
+// CompareAndWrite writes an unversioned entity (e.g. a Lockout) in each of its wide rows, but only if it has not
+// changed since the prior value was read (or, if exists is false, only if it still does not exist), with a
+// conditional write. When several requests update the same entity at once, only one succeeds; the others receive
+// ErrConflict, and should read the entity again and retry. Changes are detected by the text value of the entity
+// row, which must be defined, and must change with every write (e.g. a timestamp). The index rows are written
+// after the entity row, without a condition. Both DynamoDB tables (versionary.Table) and in-memory tables
+// (versionary.MemTable) are supported.
+func CompareAndWrite[T any](ctx context.Context, table versionary.TableReadWriter[T], prior T, exists bool, entity T) error {
+	ref := table.EntityReferenceID(entity)
+	switch t := table.(type) {
+	case versionary.Table[T]:
+		if t.EntityRow.TextValue == nil {
+			return fmt.Errorf("error writing %s: the entity row has no text value", ref)
+		}
+		records := append(valueRecords(t.EntityRow, t.IndexRows, entity), keyRecords(t.EntityRow, t.IndexRows, entity)...)
+		input := &dynamodb.PutItemInput{
+			TableName:                aws.String(t.TableName),
+			Item:                     recordItem(t, records[0]),
+			ConditionExpression:      aws.String("attribute_not_exists(#p)"),
+			ExpressionAttributeNames: map[string]string{"#p": attrName(t.PartKeyAttr, "part_key")},
+		}
+		if exists {
+			input.ConditionExpression = aws.String("#t = :t")
+			input.ExpressionAttributeNames = map[string]string{"#t": attrName(t.TextValueAttr, "text_value")}
+			input.ExpressionAttributeValues = map[string]types.AttributeValue{
+				":t": &types.AttributeValueMemberS{Value: t.EntityRow.TextValue(prior)},
+			}
+		}
+		_, err := t.Client.PutItem(ctx, input)
+		var failed *types.ConditionalCheckFailedException
+		if errors.As(err, &failed) {
+			return fmt.Errorf("error writing %s: %w", ref, ErrConflict)
+		}
+		if err != nil {
+			return fmt.Errorf("error writing %s: %w", ref, err)
+		}
+		for _, r := range records[1:] {
+			_, err = t.Client.PutItem(ctx, &dynamodb.PutItemInput{
+				TableName: aws.String(t.TableName),
+				Item:      recordItem(t, r),
+			})
+			if err != nil {
+				return fmt.Errorf("error writing %s: %w", ref, err)
+			}
+		}
+		return nil
+	case versionary.MemTable[T]:
+		if t.EntityRow.TextValue == nil {
+			return fmt.Errorf("error writing %s: the entity row has no text value", ref)
+		}
+		memWrites.Lock()
+		defer memWrites.Unlock()
+		records := append(valueRecords(t.EntityRow, t.IndexRows, entity), keyRecords(t.EntityRow, t.IndexRows, entity)...)
+		r, ok := t.Records.GetRecord(records[0].PartKeyValue, records[0].SortKeyValue)
+		if ok != exists || (exists && r.TextValue != t.EntityRow.TextValue(prior)) {
+			return fmt.Errorf("error writing %s: %w", ref, ErrConflict)
+		}
+		t.Records.SetRecords(records)
+		return nil
+	default:
+		return fmt.Errorf("error writing %s: unsupported table type %T", ref, table)
+	}
+}
+
+// keyRecords returns the records that track each row's partition key values for the entity,
+// which are written along with its values (see valueRecords).
+func keyRecords[T any](entityRow versionary.TableRow[T], indexRows map[string]versionary.TableRow[T], entity T) []versionary.Record {
+	var records []versionary.Record
+	rows := []versionary.TableRow[T]{entityRow}
+	for _, row := range indexRows {
+		rows = append(rows, row)
+	}
+	for _, row := range rows {
+		if row.SortKeyValue(entity) == "" {
+			continue
+		}
+		var partKeys []string
+		if row.PartKeyValue != nil {
+			partKeys = append(partKeys, row.PartKeyValue(entity))
+		}
+		if row.PartKeyValues != nil {
+			partKeys = append(partKeys, row.PartKeyValues(entity)...)
+		}
+		for _, partKey := range partKeys {
+			if partKey == "" {
+				continue
+			}
+			r := versionary.Record{
+				PartKeyValue: row.RowName + "|" + row.PartKeyName,
+				SortKeyValue: partKey,
+			}
+			if row.PartKeyLabel != nil {
+				r.TextValue = row.PartKeyLabel(entity)
+			}
+			if row.TimeToLive != nil {
+				r.TimeToLive = row.TimeToLive(entity)
+			}
+			records = append(records, r)
+		}
+	}
+	return records
+}
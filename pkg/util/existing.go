@@ -0,0 +1,122 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/voxtechnica/versionary"
+)
+
+// UpdateExisting rewrites an unversioned entity (e.g. a Token) in each of its wide rows, but only where its
+// records still exist, with a conditional write. Unlike WriteEntity, it never recreates an entity that was
+// deleted by another request (e.g. a revoked Token); versionary.ErrNotFound is returned instead.
+// The entity's partition key values must not have changed. Both DynamoDB tables (versionary.Table) and
+// in-memory tables (versionary.MemTable) are supported.
+func UpdateExisting[T any](ctx context.Context, table versionary.TableReadWriter[T], entity T) error {
+	ref := table.EntityReferenceID(entity)
+	switch t := table.(type) {
+	case versionary.Table[T]:
+		for _, r := range valueRecords(t.EntityRow, t.IndexRows, entity) {
+			_, err := t.Client.PutItem(ctx, &dynamodb.PutItemInput{
+				TableName:                aws.String(t.TableName),
+				Item:                     recordItem(t, r),
+				ConditionExpression:      aws.String("attribute_exists(#p)"),
+				ExpressionAttributeNames: map[string]string{"#p": attrName(t.PartKeyAttr, "part_key")},
+			})
+			var failed *types.ConditionalCheckFailedException
+			if errors.As(err, &failed) {
+				return fmt.Errorf("error updating %s: %w", ref, versionary.ErrNotFound)
+			}
+			if err != nil {
+				return fmt.Errorf("error updating %s: %w", ref, err)
+			}
+		}
+		return nil
+	case versionary.MemTable[T]:
+		memWrites.Lock()
+		defer memWrites.Unlock()
+		records := valueRecords(t.EntityRow, t.IndexRows, entity)
+		for _, r := range records {
+			if _, ok := t.Records.GetRecord(r.PartKeyValue, r.SortKeyValue); !ok {
+				return fmt.Errorf("error updating %s: %w", ref, versionary.ErrNotFound)
+			}
+		}
+		t.Records.SetRecords(records)
+		return nil
+	default:
+		return fmt.Errorf("error updating %s: unsupported table type %T", ref, table)
+	}
+}
+
+// valueRecords returns the records holding the entity's values in its entity row and index rows, starting with
+// the entity row. The records that track each row's partition key values are not included.
+func valueRecords[T any](entityRow versionary.TableRow[T], indexRows map[string]versionary.TableRow[T], entity T) []versionary.Record {
+	var records []versionary.Record
+	rows := []versionary.TableRow[T]{entityRow}
+	for _, row := range indexRows {
+		rows = append(rows, row)
+	}
+	for _, row := range rows {
+		sortKey := row.SortKeyValue(entity)
+		var partKeys []string
+		if row.PartKeyValue != nil {
+			partKeys = append(partKeys, row.PartKeyValue(entity))
+		}
+		if row.PartKeyValues != nil {
+			partKeys = append(partKeys, row.PartKeyValues(entity)...)
+		}
+		for _, partKey := range partKeys {
+			if partKey == "" || sortKey == "" {
+				continue
+			}
+			r := versionary.Record{
+				PartKeyValue: row.RowName + "|" + row.PartKeyName + "|" + partKey,
+				SortKeyValue: sortKey,
+			}
+			if row.JsonValue != nil {
+				r.JsonValue = row.JsonValue(entity)
+			}
+			if row.TextValue != nil {
+				r.TextValue = row.TextValue(entity)
+			}
+			if row.NumericValue != nil {
+				r.NumericValue = row.NumericValue(entity)
+			}
+			if row.TimeToLive != nil {
+				r.TimeToLive = row.TimeToLive(entity)
+			}
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+// recordItem returns the DynamoDB item for a record of a versionary table.
+func recordItem[T any](t versionary.Table[T], r versionary.Record) map[string]types.AttributeValue {
+	item := map[string]types.AttributeValue{
+		attrName(t.PartKeyAttr, "part_key"): &types.AttributeValueMemberS{Value: r.PartKeyValue},
+		attrName(t.SortKeyAttr, "sort_key"): &types.AttributeValueMemberS{Value: r.SortKeyValue},
+	}
+	if r.JsonValue != nil {
+		item[attrName(t.JsonValueAttr, "json_value")] = &types.AttributeValueMemberB{Value: r.JsonValue}
+	}
+	if r.TextValue != "" {
+		item[attrName(t.TextValueAttr, "text_value")] = &types.AttributeValueMemberS{Value: r.TextValue}
+	}
+	if r.NumericValue != 0 {
+		item[attrName(t.NumericValueAttr, "num_value")] = &types.AttributeValueMemberN{
+			Value: strconv.FormatFloat(r.NumericValue, 'f', -1, 64),
+		}
+	}
+	if r.TimeToLive != 0 {
+		item[attrName(t.TimeToLiveAttr, "expires_at")] = &types.AttributeValueMemberN{
+			Value: strconv.FormatInt(r.TimeToLive, 10),
+		}
+	}
+	return item
+}